@@ -13,6 +13,7 @@ import (
 var cleanOrg string
 var cleanDryRun bool
 var cleanYes bool
+var cleanCache bool
 
 var cleanCmd = &cobra.Command{
 	Use:   "clean [owner/repo]",
@@ -23,6 +24,7 @@ Examples:
   zero clean                      Remove all (with confirmation)
   zero clean owner/repo           Remove specific project
   zero clean --org myorg          Remove all org projects
+  zero clean --cache              Remove cached scanner results
   zero clean --dry-run            Preview deletion`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runClean,
@@ -34,6 +36,7 @@ func init() {
 	cleanCmd.Flags().StringVar(&cleanOrg, "org", "", "Clean all repos in organization")
 	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Preview what would be deleted")
 	cleanCmd.Flags().BoolVarP(&cleanYes, "yes", "y", false, "Skip confirmation")
+	cleanCmd.Flags().BoolVar(&cleanCache, "cache", false, "Remove cached scanner results instead of projects")
 }
 
 func runClean(cmd *cobra.Command, args []string) error {
@@ -59,7 +62,16 @@ func runClean(cmd *cobra.Command, args []string) error {
 	var targets []string
 	var totalSize int64
 
-	if repo != "" {
+	if cleanCache {
+		// Scanner result cache
+		cachePath := filepath.Join(zeroHome, "cache", "results")
+		if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+			term.Info("No cached results to clean")
+			return nil
+		}
+		targets = []string{cachePath}
+		totalSize = getDirSize(cachePath)
+	} else if repo != "" {
 		// Single repo
 		repoPath := filepath.Join(reposPath, repo)
 		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
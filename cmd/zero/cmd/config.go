@@ -36,6 +36,8 @@ var configSetCmd = &cobra.Command{
 
 Available keys:
   github_token    GitHub personal access token
+  gitlab_token    GitLab personal access token
+  gitea_token     Gitea access token
   anthropic_key   Anthropic API key
 
 Examples:
@@ -53,6 +55,8 @@ var configGetCmd = &cobra.Command{
 
 Available keys:
   github_token    GitHub personal access token
+  gitlab_token    GitLab personal access token
+  gitea_token     Gitea access token
   anthropic_key   Anthropic API key
 
 Examples:
@@ -127,11 +131,17 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	case "github_token", "github", "gh":
 		prompt = "GitHub Token"
 		setter = credentials.SetGitHubToken
+	case "gitlab_token", "gitlab":
+		prompt = "GitLab Token"
+		setter = credentials.SetGitLabToken
+	case "gitea_token", "gitea":
+		prompt = "Gitea Token"
+		setter = credentials.SetGiteaToken
 	case "anthropic_key", "anthropic", "ak":
 		prompt = "Anthropic API Key"
 		setter = credentials.SetAnthropicKey
 	default:
-		return fmt.Errorf("unknown key: %s (use 'github_token' or 'anthropic_key')", key)
+		return fmt.Errorf("unknown key: %s (use 'github_token', 'gitlab_token', 'gitea_token' or 'anthropic_key')", key)
 	}
 
 	var value string
@@ -174,11 +184,17 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 	case "github_token", "github", "gh":
 		info = credentials.GetGitHubToken()
 		name = "GitHub Token"
+	case "gitlab_token", "gitlab":
+		info = credentials.GetGitLabToken()
+		name = "GitLab Token"
+	case "gitea_token", "gitea":
+		info = credentials.GetGiteaToken()
+		name = "Gitea Token"
 	case "anthropic_key", "anthropic", "ak":
 		info = credentials.GetAnthropicKey()
 		name = "Anthropic API Key"
 	default:
-		return fmt.Errorf("unknown key: %s (use 'github_token' or 'anthropic_key')", key)
+		return fmt.Errorf("unknown key: %s (use 'github_token', 'gitlab_token', 'gitea_token' or 'anthropic_key')", key)
 	}
 
 	fmt.Printf("%s: ", name)
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/terminal"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+	"github.com/spf13/cobra"
+)
+
+var depsWhyFormat string
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Query a repository's dependency graph",
+	Long: `Query the dependency graph recorded in a repository's SBOM.
+
+Examples:
+  zero deps why owner/repo pkg:npm/minimist@0.0.8    How minimist is pulled in
+  zero deps why owner/repo minimist                  Every installed version`,
+}
+
+var depsWhyCmd = &cobra.Command{
+	Use:   "why <owner/repo> <purl|name[@version]>",
+	Short: "Show why a dependency is in a repository",
+	Long: `Show every path through which a package is pulled into a repository,
+starting at one of its direct dependencies.
+
+The package is a package URL, with or without a version, or a package name
+optionally followed by @version. All matching versions are shown. At most
+20 paths are listed per version, shortest first.
+
+Examples:
+  zero deps why owner/repo pkg:npm/minimist@0.0.8
+  zero deps why owner/repo pkg:golang/golang.org/x/net
+  zero deps why owner/repo @babel/traverse@7.22.5
+  zero deps why owner/repo lodash --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDepsWhy,
+}
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+	depsCmd.AddCommand(depsWhyCmd)
+
+	depsWhyCmd.Flags().StringVar(&depsWhyFormat, "format", "text", "Output format: text, json")
+}
+
+func runDepsWhy(cmd *cobra.Command, args []string) error {
+	term := terminal.New()
+	repo, query := args[0], args[1]
+
+	analysisDir, err := exportAnalysisDir(term, repo)
+	if err != nil {
+		return err
+	}
+	bom, err := cyclonedx.ReadFile(codepackages.GetSBOMPath(analysisDir))
+	if os.IsNotExist(err) {
+		term.Error("No SBOM found for %s", repo)
+		term.Info("Run: zero hydrate %s --profile packages", repo)
+		return fmt.Errorf("sbom.cdx.json not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read SBOM: %w", err)
+	}
+
+	graph := cyclonedx.NewGraph(bom)
+	matches := []cyclonedx.Introduction{}
+	for _, ref := range graph.Find(query) {
+		matches = append(matches, graph.Explain(ref))
+	}
+
+	if depsWhyFormat == "json" {
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling paths: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(matches) == 0 {
+		term.Warning("%s is not a dependency of %s", query, repo)
+		return nil
+	}
+	if !graph.HasDependencies() {
+		term.Warning("The SBOM for %s has no dependency graph; paths are unavailable", repo)
+	}
+	for i, m := range matches {
+		if i > 0 {
+			term.Info("")
+		}
+		kind := "transitive dependency"
+		if m.Direct {
+			kind = "direct dependency"
+		}
+		if m.Scope != "" && m.Scope != "required" {
+			kind += ", " + m.Scope
+		}
+		term.Info("%s@%s (%s)", m.Package, m.Version, kind)
+		for _, p := range m.Paths {
+			term.Info("  %s", strings.Join(p, " → "))
+		}
+		if m.Truncated {
+			term.Info("  ... more paths not shown")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/licenses"
+	"github.com/crashappsec/zero/pkg/core/sarif"
+	"github.com/crashappsec/zero/pkg/core/spdx"
+	"github.com/crashappsec/zero/pkg/core/terminal"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOutput     string
+	exportSpdxSpec   string
+	exportSpdxFormat string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export analysis results in standard formats",
+	Long: `Export analysis results for a hydrated repository in standard interchange formats.
+
+Formats:
+  sarif    SARIF 2.1.0 for code scanning UIs (GitHub, GitLab, Azure DevOps)
+  notices  THIRD_PARTY_NOTICES attribution file for shipped dependencies
+  spdx     SPDX 2.3 (JSON, tag-value) or 3.0 (JSON-LD) SBOM`,
+}
+
+var exportSarifCmd = &cobra.Command{
+	Use:   "sarif <owner/repo>",
+	Short: "Export findings as SARIF 2.1.0",
+	Long: `Export findings from all scanners as a SARIF 2.1.0 log.
+
+Covers code vulnerabilities, secrets (including git history), package
+vulnerabilities, cryptography, code quality, IaC, containers, GitHub Actions
+and AI/ML security findings.
+
+Every result carries partialFingerprints derived from the same fingerprints
+used by 'zero diff', so code scanning UIs deduplicate alerts across runs.
+
+Examples:
+  zero export sarif owner/repo                   Write to analysis/zero.sarif
+  zero export sarif owner/repo -o results.sarif  Write to a specific file
+  zero export sarif owner/repo -o -              Write to stdout`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportSarif,
+}
+
+var exportNoticesCmd = &cobra.Command{
+	Use:   "notices <owner/repo>",
+	Short: "Export a THIRD_PARTY_NOTICES attribution file",
+	Long: `Generate a third-party notices file from the repository's SBOM.
+
+Lists every shipped dependency with its SPDX license expression, includes the
+license files of installed packages (node_modules, vendor) for their copyright
+lines, and references the standard text of the remaining licenses. Dev
+dependencies are left out.
+
+Examples:
+  zero export notices owner/repo                 Write to analysis/THIRD_PARTY_NOTICES.txt
+  zero export notices owner/repo -o NOTICES.txt  Write to a specific file
+  zero export notices owner/repo -o -            Write to stdout`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportNotices,
+}
+
+var exportSpdxCmd = &cobra.Command{
+	Use:   "spdx <owner/repo>",
+	Short: "Export the SBOM as SPDX",
+	Long: `Convert the repository's CycloneDX SBOM to SPDX.
+
+Components become packages with their purls, checksums and declared license
+expressions; license names that are not on the SPDX list are kept as
+LicenseRef- extracted licenses. The dependency graph becomes DEPENDS_ON
+relationships, with DEV_DEPENDENCY_OF for dev dependencies.
+
+Examples:
+  zero export spdx owner/repo                       Write SPDX 2.3 JSON to analysis/sbom.spdx.json
+  zero export spdx owner/repo --format tag-value    Write SPDX 2.3 tag-value to analysis/sbom.spdx
+  zero export spdx owner/repo --spec 3.0            Write SPDX 3.0 JSON-LD to analysis/sbom.spdx.jsonld
+  zero export spdx owner/repo -o -                  Write to stdout`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportSpdx,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportSarifCmd)
+	exportCmd.AddCommand(exportNoticesCmd)
+	exportCmd.AddCommand(exportSpdxCmd)
+
+	exportSpdxCmd.Flags().StringVar(&exportSpdxSpec, "spec", "2.3", "SPDX version: 2.3, 3.0")
+	exportSpdxCmd.Flags().StringVar(&exportSpdxFormat, "format", "json", "SPDX 2.3 serialization: json, tag-value")
+
+	exportCmd.PersistentFlags().StringVarP(&exportOutput, "output", "o", "", "Output file path, '-' for stdout (default: analysis directory)")
+}
+
+func runExportSarif(cmd *cobra.Command, args []string) error {
+	term := terminal.New()
+	repo := args[0]
+
+	analysisDir, err := exportAnalysisDir(term, repo)
+	if err != nil {
+		return err
+	}
+
+	exporter := sarif.NewExporter(analysisDir, filepath.Join(filepath.Dir(analysisDir), "repo"))
+	log, err := exporter.Export()
+	if err != nil {
+		return fmt.Errorf("failed to export SARIF: %w", err)
+	}
+
+	if exportOutput == "-" {
+		data, err := json.MarshalIndent(log, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling SARIF: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	outputPath := exportOutput
+	if outputPath == "" {
+		outputPath = filepath.Join(analysisDir, "zero.sarif")
+	}
+
+	if err := log.WriteJSON(outputPath); err != nil {
+		return fmt.Errorf("failed to write SARIF: %w", err)
+	}
+
+	results := 0
+	for _, run := range log.Runs {
+		results += len(run.Results)
+	}
+
+	term.Success("SARIF log generated: %s", outputPath)
+	term.Info("  %d runs, %d results", len(log.Runs), results)
+	return nil
+}
+
+func runExportNotices(cmd *cobra.Command, args []string) error {
+	term := terminal.New()
+	repo := args[0]
+
+	analysisDir, err := exportAnalysisDir(term, repo)
+	if err != nil {
+		return err
+	}
+
+	sbomPath := filepath.Join(analysisDir, "sbom.cdx.json")
+	if _, err := os.Stat(sbomPath); os.IsNotExist(err) {
+		term.Error("No SBOM found for %s", repo)
+		term.Info("Run: zero scan %s", repo)
+		return fmt.Errorf("sbom not found")
+	}
+	repoPath := filepath.Join(filepath.Dir(analysisDir), "repo")
+
+	if exportOutput == "-" {
+		_, err := codepackages.WriteNotices(os.Stdout, sbomPath, repoPath)
+		return err
+	}
+
+	outputPath := exportOutput
+	if outputPath == "" {
+		outputPath = filepath.Join(analysisDir, licenses.NoticesFile)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create notices file: %w", err)
+	}
+	count, err := codepackages.WriteNotices(f, sbomPath, repoPath)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write notices: %w", err)
+	}
+
+	term.Success("Third-party notices generated: %s", outputPath)
+	term.Info("  %d packages", count)
+	return nil
+}
+
+func runExportSpdx(cmd *cobra.Command, args []string) error {
+	term := terminal.New()
+	repo := args[0]
+
+	analysisDir, err := exportAnalysisDir(term, repo)
+	if err != nil {
+		return err
+	}
+
+	bom, err := cyclonedx.ReadFile(codepackages.GetSBOMPath(analysisDir))
+	if os.IsNotExist(err) {
+		term.Error("No SBOM found for %s", repo)
+		term.Info("Run: zero scan %s", repo)
+		return fmt.Errorf("sbom not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	doc := spdx.FromCycloneDX(bom)
+
+	var data []byte
+	var defaultName string
+	switch {
+	case exportSpdxSpec == "3.0":
+		data, err = doc.ToJSONLD()
+		defaultName = "sbom.spdx.jsonld"
+	case exportSpdxSpec != "2.3":
+		return fmt.Errorf("unsupported SPDX version %q (use 2.3 or 3.0)", exportSpdxSpec)
+	case exportSpdxFormat == "tag-value":
+		data = doc.ToTagValue()
+		defaultName = "sbom.spdx"
+	case exportSpdxFormat == "json":
+		data, err = doc.ToJSON()
+		defaultName = "sbom.spdx.json"
+	default:
+		return fmt.Errorf("unsupported format %q (use json or tag-value)", exportSpdxFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to serialize SPDX: %w", err)
+	}
+
+	if exportOutput == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	outputPath := exportOutput
+	if outputPath == "" {
+		outputPath = filepath.Join(analysisDir, defaultName)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SPDX: %w", err)
+	}
+
+	term.Success("SPDX SBOM generated: %s", outputPath)
+	term.Info("  %d packages, %d relationships", len(doc.Packages), len(doc.Relationships))
+	return nil
+}
+
+// exportAnalysisDir resolves and validates the analysis directory for a repository
+func exportAnalysisDir(term *terminal.Terminal, repo string) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+
+	zeroHome := cfg.ZeroHome()
+	if zeroHome == "" {
+		zeroHome = ".zero"
+	}
+
+	analysisDir := filepath.Join(zeroHome, "repos", repo, "analysis")
+	if _, err := os.Stat(analysisDir); os.IsNotExist(err) {
+		term.Error("Project not found: %s", repo)
+		term.Info("Run: zero hydrate %s", repo)
+		return "", fmt.Errorf("project not found")
+	}
+
+	return analysisDir, nil
+}
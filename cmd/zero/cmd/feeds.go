@@ -6,19 +6,31 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"syscall"
 	"time"
 
 	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/exploitdb"
 	"github.com/crashappsec/zero/pkg/core/feeds"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/osvdb"
 	"github.com/crashappsec/zero/pkg/core/rag"
 	"github.com/crashappsec/zero/pkg/core/terminal"
+	"github.com/crashappsec/zero/pkg/core/typosquat"
+	"github.com/crashappsec/zero/pkg/core/versions"
 	techid "github.com/crashappsec/zero/pkg/scanner/technology-identification"
 	"github.com/spf13/cobra"
 )
 
 var (
-	feedsForce bool
+	feedsForce          bool
+	feedsOSVFrom        string
+	feedsOSVDB          string
+	feedsTyposquatsFrom string
+	feedsEPSSFrom       string
+	feedsKEVFrom        string
+	feedsExploitDB      string
 )
 
 var feedsCmd = &cobra.Command{
@@ -30,11 +42,21 @@ Zero uses two sources of Semgrep rules:
   - RAG patterns: Custom rules generated from the rag/ knowledge base
   - Semgrep community: Official rules from semgrep.dev registry
 
+Vulnerability matching can run offline against OSV data dumps imported
+into a local database. EPSS scores and the CISA KEV catalog, used to rank
+vulnerabilities by exploitability, are imported the same way. Typosquat
+detection runs offline against a bundled corpus of popular packages that
+can be replaced with a newer one.
+
 Examples:
-  zero feeds rag               Generate rules from RAG knowledge base
-  zero feeds semgrep           Sync Semgrep community rules (SAST)
-  zero feeds semgrep --force   Force sync even if fresh
-  zero feeds status            Show feed status`,
+  zero feeds rag                      Generate rules from RAG knowledge base
+  zero feeds semgrep                  Sync Semgrep community rules (SAST)
+  zero feeds semgrep --force          Force sync even if fresh
+  zero feeds osv --from all.zip       Import an OSV data dump
+  zero feeds epss --from epss.csv.gz  Import EPSS scores
+  zero feeds kev                      Download the CISA KEV catalog
+  zero feeds typosquats --from top.json  Import a popular-package corpus
+  zero feeds status                   Show feed status`,
 }
 
 var feedsSemgrepCmd = &cobra.Command{
@@ -56,6 +78,65 @@ var feedsStatusCmd = &cobra.Command{
 	RunE:  runFeedsStatus,
 }
 
+var feedsOSVCmd = &cobra.Command{
+	Use:   "osv",
+	Short: "Import OSV vulnerability data for offline matching",
+	Long: `Import OSV vulnerability records into a local database.
+
+--from takes an OSV data dump: a zip archive as published at
+https://osv-vulnerabilities.storage.googleapis.com/<ecosystem>/all.zip
+(or all ecosystems at .../all.zip), a JSON record, or a directory of
+records and archives. Re-importing only replaces records that changed.
+
+The code-packages vulns feature matches packages against this database
+first and only queries the OSV API for ecosystems it does not cover.`,
+	RunE: runFeedsOSV,
+}
+
+var feedsEPSSCmd = &cobra.Command{
+	Use:   "epss",
+	Short: "Import EPSS scores for vulnerability prioritization",
+	Long: `Import FIRST EPSS (Exploit Prediction Scoring System) scores into the
+local exploit database.
+
+--from takes the daily scores file published at
+https://epss.cyentia.com/epss_scores-current.csv.gz, gzipped or plain CSV.
+Each import replaces the previous scores.
+
+The code-packages vulns feature combines EPSS with CVSS, KEV, reachability
+and dependency scope to rank vulnerabilities.`,
+	RunE: runFeedsEPSS,
+}
+
+var feedsKEVCmd = &cobra.Command{
+	Use:   "kev",
+	Short: "Import the CISA Known Exploited Vulnerabilities catalog",
+	Long: `Import the CISA Known Exploited Vulnerabilities (KEV) catalog into the
+local exploit database.
+
+Without --from the catalog is downloaded from
+https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json;
+--from imports a copy of that file for offline use. Each import replaces
+the previous catalog.`,
+	RunE: runFeedsKEV,
+}
+
+var feedsTyposquatsCmd = &cobra.Command{
+	Use:   "typosquats",
+	Short: "Import a popular-package corpus for typosquat detection",
+	Long: `Import the popular-package corpus the code-packages typosquats feature
+compares package names against.
+
+--from takes a JSON document listing popular package names per ecosystem
+(purl type), most popular first:
+
+  {"version": "2026.10", "ecosystems": {"npm": ["lodash", "@types/node"], "pypi": ["requests"]}}
+
+Ecosystems in the file replace the bundled lists; the others stay bundled.
+Maven names are group:artifact, Go names are module paths.`,
+	RunE: runFeedsTyposquats,
+}
+
 var feedsRagCmd = &cobra.Command{
 	Use:   "rag",
 	Short: "Generate rules from RAG knowledge base",
@@ -73,11 +154,29 @@ func init() {
 	rootCmd.AddCommand(feedsCmd)
 	feedsCmd.AddCommand(feedsSemgrepCmd)
 	feedsCmd.AddCommand(feedsRagCmd)
+	feedsCmd.AddCommand(feedsOSVCmd)
+	feedsCmd.AddCommand(feedsEPSSCmd)
+	feedsCmd.AddCommand(feedsKEVCmd)
+	feedsCmd.AddCommand(feedsTyposquatsCmd)
 	feedsCmd.AddCommand(feedsStatusCmd)
 
 	feedsSemgrepCmd.Flags().BoolVar(&feedsForce, "force", false, "Force sync even if rules are fresh")
 
 	feedsRagCmd.Flags().BoolVar(&feedsForce, "force", false, "Force regenerate even if RAG unchanged")
+
+	feedsOSVCmd.Flags().StringVar(&feedsOSVFrom, "from", "", "OSV zip archive, JSON record or directory to import")
+	feedsOSVCmd.Flags().StringVar(&feedsOSVDB, "db", "", "Database path (default: <zero home>/feeds/osv.db)")
+	_ = feedsOSVCmd.MarkFlagRequired("from")
+
+	feedsEPSSCmd.Flags().StringVar(&feedsEPSSFrom, "from", "", "EPSS scores file (.csv or .csv.gz) to import")
+	feedsEPSSCmd.Flags().StringVar(&feedsExploitDB, "db", "", "Database path (default: <zero home>/feeds/exploits.db)")
+	_ = feedsEPSSCmd.MarkFlagRequired("from")
+
+	feedsKEVCmd.Flags().StringVar(&feedsKEVFrom, "from", "", "KEV catalog JSON file to import instead of downloading it")
+	feedsKEVCmd.Flags().StringVar(&feedsExploitDB, "db", "", "Database path (default: <zero home>/feeds/exploits.db)")
+
+	feedsTyposquatsCmd.Flags().StringVar(&feedsTyposquatsFrom, "from", "", "Corpus JSON file to import")
+	_ = feedsTyposquatsCmd.MarkFlagRequired("from")
 }
 
 func runFeedsSemgrep(cmd *cobra.Command, args []string) error {
@@ -227,10 +326,366 @@ func runFeedsStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// The OSV database is imported manually rather than synced
+	if dbPath := osvdb.DefaultPath(zeroHome); osvdb.Exists(dbPath) {
+		term.Info("\n%s %s",
+			term.Color(terminal.Cyan, "▸"),
+			term.Color(terminal.Bold, "osv"),
+		)
+		if err := showOSVStats(term, dbPath); err != nil {
+			term.Error("  Error: %v", err)
+		}
+	}
+
+	// EPSS scores and the KEV catalog are imported manually
+	if dbPath := exploitdb.DefaultPath(zeroHome); exploitdb.Exists(dbPath) {
+		term.Info("\n%s %s",
+			term.Color(terminal.Cyan, "▸"),
+			term.Color(terminal.Bold, "exploits"),
+		)
+		if err := showExploitStats(term, dbPath); err != nil {
+			term.Error("  Error: %v", err)
+		}
+	}
+
+	// The typosquat corpus is bundled and optionally imported
+	corpusPath := typosquat.DefaultPath(zeroHome)
+	corpus, err := typosquat.Load(corpusPath)
+	if err == nil {
+		source := "bundled"
+		if _, statErr := os.Stat(corpusPath); statErr == nil {
+			source = corpusPath
+		}
+		term.Info("\n%s %s",
+			term.Color(terminal.Cyan, "▸"),
+			term.Color(terminal.Bold, "typosquats"),
+		)
+		term.Info("  Corpus: %s (version %s)", source, corpus.Version)
+		term.Info("  Packages: %d", corpus.Count())
+	} else {
+		term.Error("  Typosquat corpus: %v", err)
+	}
+
+	// Registry and API responses cached by package analysis
+	if cachePath := liveapi.DefaultStorePath(zeroHome); osvdb.Exists(cachePath) {
+		term.Info("\n%s %s",
+			term.Color(terminal.Cyan, "▸"),
+			term.Color(terminal.Bold, "api cache"),
+		)
+		if err := showAPICacheStats(term, cachePath); err != nil {
+			term.Error("  Error: %v", err)
+		}
+	}
+
 	term.Divider()
 	return nil
 }
 
+func runFeedsTyposquats(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	zeroHome := cfg.ZeroHome()
+	if zeroHome == "" {
+		zeroHome = ".zero"
+	}
+
+	term := terminal.New()
+	dst := typosquat.DefaultPath(zeroHome)
+
+	corpus, err := typosquat.Import(feedsTyposquatsFrom, dst)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	ecosystems := make([]string, 0, len(corpus.Ecosystems))
+	for eco := range corpus.Ecosystems {
+		ecosystems = append(ecosystems, eco)
+	}
+	sort.Strings(ecosystems)
+
+	term.Success("  %s Imported corpus %s: %d packages",
+		term.Color(terminal.Green, "✓"),
+		corpus.Version,
+		corpus.Count(),
+	)
+	for _, eco := range ecosystems {
+		term.Info("    %-12s %d", eco, len(corpus.Ecosystems[eco]))
+	}
+	term.Info("  Stored at %s", term.Color(terminal.Cyan, dst))
+	return nil
+}
+
+func runFeedsOSV(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	zeroHome := cfg.ZeroHome()
+	if zeroHome == "" {
+		zeroHome = ".zero"
+	}
+
+	dbPath := feedsOSVDB
+	if dbPath == "" {
+		dbPath = osvdb.DefaultPath(zeroHome)
+	}
+
+	term := terminal.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		term.Info("\nInterrupted...")
+		cancel()
+	}()
+
+	term.Divider()
+	term.Info("%s", term.Color(terminal.Bold, "Importing OSV Vulnerability Data"))
+	term.Divider()
+	term.Info("Source:   %s", term.Color(terminal.Cyan, feedsOSVFrom))
+	term.Info("Database: %s", term.Color(terminal.Cyan, dbPath))
+	term.Info("")
+
+	db, err := osvdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	start := time.Now()
+	stats, err := db.Import(ctx, feedsOSVFrom)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	term.Success("  %s %d imported, %d unchanged, %d withdrawn (%s)",
+		term.Color(terminal.Green, "✓"),
+		stats.Imported,
+		stats.Unchanged,
+		stats.Withdrawn,
+		formatDuration(time.Since(start)),
+	)
+	if stats.Invalid > 0 {
+		term.Info("  %s %d files were not OSV records",
+			term.Color(terminal.Dim, "○"),
+			stats.Invalid,
+		)
+	}
+
+	term.Divider()
+	return showOSVStats(term, dbPath)
+}
+
+// showAPICacheStats prints the contents of the API response cache
+func showAPICacheStats(term *terminal.Terminal, path string) error {
+	store, err := liveapi.OpenStore(path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stats, err := store.Stats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	term.Info("  Path: %s", path)
+	term.Info("  Responses: %d", stats.Responses)
+	if !stats.Newest.IsZero() {
+		term.Info("  Oldest: %s ago, newest: %s ago",
+			formatAge(time.Since(stats.Oldest)),
+			formatAge(time.Since(stats.Newest)),
+		)
+	}
+
+	endpoints := make([]string, 0, len(stats.Endpoints))
+	for endpoint := range stats.Endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		term.Info("    %-12s %d", endpoint, stats.Endpoints[endpoint])
+	}
+	return nil
+}
+
+// showOSVStats prints the contents of a local OSV database
+func showOSVStats(term *terminal.Terminal, dbPath string) error {
+	db, err := osvdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := db.Stats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if !stats.LastImport.IsZero() {
+		term.Info("  Last Import: %s (%s ago)",
+			stats.LastImport.Local().Format("2006-01-02 15:04"),
+			formatAge(time.Since(stats.LastImport)),
+		)
+	}
+	term.Info("  Vulnerabilities: %d", stats.Vulnerabilities)
+
+	ecosystems := make([]string, 0, len(stats.Ecosystems))
+	for eco := range stats.Ecosystems {
+		ecosystems = append(ecosystems, string(eco))
+	}
+	sort.Strings(ecosystems)
+	for _, eco := range ecosystems {
+		term.Info("    %-12s %d", eco, stats.Ecosystems[versions.Ecosystem(eco)])
+	}
+	return nil
+}
+
+func runFeedsEPSS(cmd *cobra.Command, args []string) error {
+	dbPath, err := exploitDBPath()
+	if err != nil {
+		return err
+	}
+
+	term := terminal.New()
+	term.Divider()
+	term.Info("%s", term.Color(terminal.Bold, "Importing EPSS Scores"))
+	term.Divider()
+	term.Info("Source:   %s", term.Color(terminal.Cyan, feedsEPSSFrom))
+	term.Info("Database: %s", term.Color(terminal.Cyan, dbPath))
+	term.Info("")
+
+	db, err := exploitdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	start := time.Now()
+	stats, err := db.ImportEPSS(context.Background(), feedsEPSSFrom)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	term.Success("  %s %d scores imported (%s)",
+		term.Color(terminal.Green, "✓"),
+		stats.Imported,
+		formatDuration(time.Since(start)),
+	)
+	if stats.Invalid > 0 {
+		term.Info("  %s %d rows could not be parsed",
+			term.Color(terminal.Dim, "○"),
+			stats.Invalid,
+		)
+	}
+
+	term.Divider()
+	return showExploitStats(term, dbPath)
+}
+
+func runFeedsKEV(cmd *cobra.Command, args []string) error {
+	dbPath, err := exploitDBPath()
+	if err != nil {
+		return err
+	}
+
+	term := terminal.New()
+	term.Divider()
+	term.Info("%s", term.Color(terminal.Bold, "Importing CISA KEV Catalog"))
+	term.Divider()
+
+	ctx := context.Background()
+	var data []byte
+	if feedsKEVFrom != "" {
+		term.Info("Source:   %s", term.Color(terminal.Cyan, feedsKEVFrom))
+		data, err = os.ReadFile(feedsKEVFrom)
+	} else {
+		term.Info("Source:   %s", term.Color(terminal.Cyan, liveapi.KEVURL+liveapi.KEVCatalogPath))
+		data, err = liveapi.NewKEVClient().CachedGetRaw(ctx, liveapi.KEVCatalogPath)
+	}
+	if err != nil {
+		return fmt.Errorf("reading KEV catalog: %w", err)
+	}
+	term.Info("Database: %s", term.Color(terminal.Cyan, dbPath))
+	term.Info("")
+
+	db, err := exploitdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := db.ImportKEV(ctx, data)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	term.Success("  %s %d entries imported (catalog %s)",
+		term.Color(terminal.Green, "✓"),
+		stats.Imported,
+		stats.Version,
+	)
+
+	term.Divider()
+	return showExploitStats(term, dbPath)
+}
+
+// exploitDBPath returns the --db flag or the default exploit database path
+func exploitDBPath() (string, error) {
+	if feedsExploitDB != "" {
+		return feedsExploitDB, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	zeroHome := cfg.ZeroHome()
+	if zeroHome == "" {
+		zeroHome = ".zero"
+	}
+	return exploitdb.DefaultPath(zeroHome), nil
+}
+
+// showExploitStats prints the contents of a local exploit database
+func showExploitStats(term *terminal.Terminal, dbPath string) error {
+	db, err := exploitdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := db.Stats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if stats.EPSSScores > 0 {
+		term.Info("  EPSS: %d scores from %s (imported %s ago)",
+			stats.EPSSScores,
+			stats.EPSSScoreDate,
+			formatAge(time.Since(stats.EPSSLastImport)),
+		)
+	} else {
+		term.Info("  EPSS: not imported")
+	}
+	if stats.KEVEntries > 0 {
+		term.Info("  KEV: %d entries, catalog %s (imported %s ago)",
+			stats.KEVEntries,
+			stats.KEVVersion,
+			formatAge(time.Since(stats.KEVLastImport)),
+		)
+	} else {
+		term.Info("  KEV: not imported")
+	}
+	return nil
+}
+
 func runFeedsRag(cmd *cobra.Command, args []string) error {
 	cfg, err := config.Load()
 	if err != nil {
@@ -0,0 +1,205 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/feedback"
+	"github.com/crashappsec/zero/pkg/core/findings"
+	"github.com/crashappsec/zero/pkg/core/policy"
+	"github.com/crashappsec/zero/pkg/core/suppression"
+	"github.com/crashappsec/zero/pkg/core/terminal"
+	"github.com/crashappsec/zero/pkg/workflow/hydrate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gatePolicy        string
+	gateFormat        string
+	gateNoSuppress    bool
+	gateMaxViolations int
+	gateAllowMissing  bool
+)
+
+var gateCmd = &cobra.Command{
+	Use:   "gate <owner/repo>",
+	Short: "Evaluate a policy against scan results (CI gate)",
+	Long: `Evaluate a YAML policy file against a scanned project's results.
+
+Each rule either matches findings in a scanner's output (e.g. critical
+vulnerabilities with a fix, unpinned GitHub Actions, GPL-3.0 production
+dependencies) or checks a metric (e.g. bus factor). Findings marked as
+false positives with 'zero feedback' and low-severity findings in tests,
+examples and docs are suppressed before rules are evaluated.
+
+Rules at level "block" fail the gate, "warn" rules are only reported.
+Per-repo overrides can change a rule's level or threshold. A blocking rule
+whose scanner has no results fails too, unless --allow-missing-results is
+given, so a scanner that crashed or was not run cannot pass the gate.
+
+Exits non-zero when any blocking rule fails.
+
+See config/policy.example.yaml for the policy format.
+
+Examples:
+  zero gate owner/repo                             Use ./zero-policy.yaml
+  zero gate owner/repo --policy ci/policy.yaml     Use a specific policy
+  zero gate owner/repo --format json               Machine-readable report`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGate,
+}
+
+func init() {
+	rootCmd.AddCommand(gateCmd)
+
+	gateCmd.Flags().StringVarP(&gatePolicy, "policy", "p", "zero-policy.yaml", "Policy file")
+	gateCmd.Flags().StringVar(&gateFormat, "format", "text", "Output format: text, json")
+	gateCmd.Flags().BoolVar(&gateNoSuppress, "no-suppress", false, "Do not apply feedback and context suppressions")
+	gateCmd.Flags().IntVar(&gateMaxViolations, "max-violations", 10, "Violations to list per rule (0 = all)")
+	gateCmd.Flags().BoolVar(&gateAllowMissing, "allow-missing-results", false, "Skip rules whose scanner has no results instead of failing them")
+}
+
+func runGate(cmd *cobra.Command, args []string) error {
+	if gateFormat != "text" && gateFormat != "json" {
+		return fmt.Errorf("invalid format %q (use text or json)", gateFormat)
+	}
+
+	p, err := policy.Load(gatePolicy)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	zeroHome := cfg.ZeroHome()
+	if zeroHome == "" {
+		zeroHome = ".zero"
+	}
+
+	repo := args[0]
+	if hydrate.IsSourceTarget(repo) {
+		src, err := hydrate.ParseSource(repo)
+		if err != nil {
+			return err
+		}
+		repo = src.ProjectID()
+	}
+
+	analysisDir := filepath.Join(zeroHome, "repos", repo, "analysis")
+	if _, err := os.Stat(analysisDir); os.IsNotExist(err) {
+		return fmt.Errorf("project not found: %s (run: zero hydrate %s)", repo, args[0])
+	}
+
+	evaluator := policy.NewEvaluator(nil)
+	evaluator.AllowMissingResults = gateAllowMissing
+	if !gateNoSuppress {
+		evaluator.Suppression = suppression.NewService(feedback.NewStorage(zeroHome))
+	}
+
+	report, err := evaluator.Evaluate(p, repo, analysisDir)
+	if err != nil {
+		return err
+	}
+
+	if gateFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printGateReport(terminal.New(), report)
+	}
+
+	if !report.Passed {
+		// The report already explains the failure; main prints the error once
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return fmt.Errorf("policy gate failed: %d blocking %s", report.Blocked, pluralRules(report.Blocked))
+	}
+	return nil
+}
+
+func printGateReport(term *terminal.Terminal, report *policy.Report) {
+	term.Header(fmt.Sprintf("Policy gate: %s", report.Repo))
+
+	for _, r := range report.Rules {
+		var status string
+		switch r.Status {
+		case policy.StatusPass:
+			status = term.Color(terminal.Green, terminal.IconSuccess+" PASS")
+		case policy.StatusFail:
+			status = term.Color(terminal.Red, terminal.IconFailed+" FAIL")
+		case policy.StatusWarn:
+			status = term.Color(terminal.Yellow, terminal.IconWarning+" WARN")
+		default:
+			status = term.Color(terminal.Dim, terminal.IconSkipped+" SKIP")
+		}
+
+		term.Info("  %s  %s  %s", status, term.Color(terminal.Bold, r.ID), term.Color(terminal.Dim, r.Message))
+		if r.Description != "" && r.Status != policy.StatusPass {
+			term.Info("           %s", r.Description)
+		}
+		if r.Suppressed > 0 {
+			term.Info("           %d suppressed", r.Suppressed)
+		}
+		if r.Status != policy.StatusFail && r.Status != policy.StatusWarn {
+			continue
+		}
+
+		violations := r.Violations
+		if gateMaxViolations > 0 && len(violations) > gateMaxViolations {
+			violations = violations[:gateMaxViolations]
+		}
+		for _, f := range violations {
+			term.Info("           %-8s %s", f.Severity, describeViolation(f))
+		}
+		if len(violations) < len(r.Violations) {
+			term.Info("           ... and %d more", len(r.Violations)-len(violations))
+		}
+	}
+
+	fmt.Println()
+	switch {
+	case !report.Passed:
+		term.Error("Gate failed: %d blocking, %d %s", report.Blocked, report.Warned, pluralWarnings(report.Warned))
+	case report.Warned > 0:
+		term.Warning("Gate passed with %d %s", report.Warned, pluralWarnings(report.Warned))
+	default:
+		term.Success("Gate passed")
+	}
+}
+
+// describeViolation formats a finding as "location  title"
+func describeViolation(f findings.Finding) string {
+	if f.Location == nil || f.Location.File == "" {
+		return f.Title
+	}
+	loc := f.Location.File
+	if f.Location.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, f.Location.Line)
+	}
+	return fmt.Sprintf("%s  %s", loc, f.Title)
+}
+
+func pluralRules(n int) string {
+	if n == 1 {
+		return "rule"
+	}
+	return "rules"
+}
+
+func pluralWarnings(n int) string {
+	if n == 1 {
+		return "warning"
+	}
+	return "warnings"
+}
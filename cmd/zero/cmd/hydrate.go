@@ -15,8 +15,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// validTargetPattern matches valid GitHub owner/repo or org names
-var validTargetPattern = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_.]*(/[a-zA-Z0-9][-a-zA-Z0-9_.]*)?$`)
+// validTargetPattern matches valid owner/repo or org names, and GitLab
+// projects in nested groups (group/subgroup/project)
+var validTargetPattern = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_.]*(/[a-zA-Z0-9][-a-zA-Z0-9_.]*)*$`)
 
 var hydrateOpts hydrate.Options
 
@@ -27,8 +28,17 @@ var hydrateCmd = &cobra.Command{
 	Long: `Clone a repository or organization and run analysis.
 
 Target can be:
-  - owner/repo    Single repository (e.g., strapi/strapi)
-  - org-name      GitHub organization (e.g., zero-test-org)
+  - owner/repo    Single repository (e.g., strapi/strapi), or
+                  group/subgroup/project with --forge gitlab
+  - org-name      GitHub organization (e.g., zero-test-org), or a GitLab
+                  group / Gitea organization with --forge
+  - ./path        Local directory or checkout (stored as local/<dir>-<hash>)
+  - git URL       Any git remote: https://, ssh://, git@host:path, file://
+                  (stored as <owner>/<repo> from the last two path segments)
+
+Use --forge gitlab or --forge gitea (with --forge-url for self-hosted
+instances) to list and clone from another forge. Tokens are read from
+GITLAB_TOKEN and GITEA_TOKEN.
 
 The profile argument specifies which analyzers to run. Profiles are defined
 in the config file (config/zero.config.json).
@@ -41,7 +51,11 @@ Examples:
   zero hydrate strapi/strapi all-quick    Clone with all-quick profile
   zero hydrate zero-test-org              Clone and analyze all org repos
   zero hydrate zero-test-org --limit 10   Limit to first 10 repos
-  zero hydrate zero-test-org --demo       Demo mode: skip repos > 50MB`,
+  zero hydrate zero-test-org --demo       Demo mode: skip repos > 50MB
+  zero hydrate ./my-service               Analyze a local checkout
+  zero hydrate my-group --forge gitlab    Clone and analyze a GitLab group
+  zero hydrate infra --forge gitea --forge-url https://git.example.com
+  zero hydrate https://git.example.com/team/repo.git`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runHydrate,
 }
@@ -53,13 +67,17 @@ func init() {
 	hydrateCmd.Flags().IntVar(&hydrateOpts.Limit, "limit", 25, "Maximum repos to process (org mode)")
 	hydrateCmd.Flags().BoolVar(&hydrateOpts.Demo, "demo", false, "Demo mode: skip repos > 50MB, fetch replacements")
 
+	// Forge options
+	hydrateCmd.Flags().StringVar(&hydrateOpts.Forge, "forge", "github", "Forge for org/repo targets: github, gitlab, gitea")
+	hydrateCmd.Flags().StringVar(&hydrateOpts.ForgeURL, "forge-url", "", "Base URL of a self-hosted forge (e.g., https://gitlab.example.com)")
+
 	// Clone options
 	hydrateCmd.Flags().StringVar(&hydrateOpts.Branch, "branch", "", "Clone specific branch")
 	hydrateCmd.Flags().IntVar(&hydrateOpts.Depth, "depth", 1, "Shallow clone depth")
 	hydrateCmd.Flags().BoolVar(&hydrateOpts.CloneOnly, "clone-only", false, "Clone without scanning")
 
 	// Scan options
-	hydrateCmd.Flags().BoolVar(&hydrateOpts.Force, "force", false, "Re-scan even if results exist")
+	hydrateCmd.Flags().BoolVar(&hydrateOpts.Force, "force", false, "Re-scan even if cached results exist")
 	hydrateCmd.Flags().BoolVar(&hydrateOpts.SkipSlow, "skip-slow", false, "Skip slow scanners")
 	hydrateCmd.Flags().BoolVarP(&hydrateOpts.Yes, "yes", "y", false, "Auto-accept prompts")
 	hydrateCmd.Flags().IntVar(&hydrateOpts.ParallelScanners, "parallel", 4, "Parallel scanners per repo")
@@ -75,9 +93,10 @@ func runHydrate(cmd *cobra.Command, args []string) error {
 	// Parse target: owner/repo (single repo) or org-name (organization)
 	target := args[0]
 
-	// Validate target format
-	if !validTargetPattern.MatchString(target) || len(target) > 150 {
-		return fmt.Errorf("invalid target format: %q\nTarget must be a valid GitHub owner/repo or org name", target)
+	// Validate target format (local paths and git URLs are validated by hydrate)
+	isSource := hydrate.IsSourceTarget(target)
+	if !isSource && (!validTargetPattern.MatchString(target) || len(target) > 150) {
+		return fmt.Errorf("invalid target format: %q\nTarget must be a valid owner/repo or org name", target)
 	}
 
 	profile := cfg.Settings.DefaultProfile
@@ -85,8 +104,11 @@ func runHydrate(cmd *cobra.Command, args []string) error {
 		profile = "all-quick"
 	}
 
-	// Check if target is org or repo based on presence of "/"
-	if strings.Contains(target, "/") {
+	// Check if target is a source, org or repo based on presence of "/"
+	if isSource {
+		// Local directory or arbitrary git URL
+		hydrateOpts.Source = target
+	} else if strings.Contains(target, "/") {
 		// Single repo mode: owner/repo
 		hydrateOpts.Repo = target
 	} else {
@@ -32,6 +32,10 @@ var refreshCmd = &cobra.Command{
 By default, only refreshes repositories that need updating based on
 freshness thresholds. Use --force to refresh regardless of staleness.
 
+Scanner results are cached by commit, scanner version, feature config and
+tool versions, so repos whose commit has not changed are restored from the
+cache instead of re-scanned. --force also bypasses this cache.
+
 Examples:
   zero refresh                      Refresh all stale repos
   zero refresh owner/repo           Refresh specific repo
@@ -45,7 +49,7 @@ Examples:
 func init() {
 	rootCmd.AddCommand(refreshCmd)
 
-	refreshCmd.Flags().BoolVar(&refreshForce, "force", false, "Force refresh even if data is fresh")
+	refreshCmd.Flags().BoolVar(&refreshForce, "force", false, "Force refresh even if data is fresh or cached")
 	refreshCmd.Flags().BoolVar(&refreshAll, "all", false, "Refresh all repos, not just stale ones")
 	refreshCmd.Flags().StringVar(&refreshProfile, "profile", "", "Scan profile to use")
 	refreshCmd.Flags().IntVar(&refreshParallel, "parallel", 4, "Number of parallel scans")
@@ -138,6 +142,8 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 
 	// Run refreshes
 	runner := scanner.NewRunner(zeroHome)
+	runner.NoCache = refreshForce
+	runner.SetCacheTTL(time.Duration(cfg.Settings.CacheTTLHours) * time.Hour)
 	success := 0
 	failed := 0
 	skipped := 0
@@ -247,6 +253,8 @@ func refreshRepo(ctx context.Context, term *terminal.Terminal, cfg *config.Confi
 	term.Divider()
 
 	runner := scanner.NewRunner(zeroHome)
+	runner.NoCache = refreshForce
+	runner.SetCacheTTL(time.Duration(cfg.Settings.CacheTTLHours) * time.Hour)
 	scanners, _ := cfg.GetProfileScanners(profile)
 	progress := scanner.NewProgress(scanners)
 	start := time.Now()
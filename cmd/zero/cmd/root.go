@@ -16,6 +16,7 @@ var (
 	// Global flags
 	verbose bool
 	noColor bool
+	offline bool
 
 	// Terminal instance
 	term *terminal.Terminal
@@ -38,7 +39,12 @@ Quick Start:
 		if noColor {
 			os.Setenv("NO_COLOR", "1")
 		}
+		if offline {
+			// Also reaches plugins, which inherit the environment
+			os.Setenv("ZERO_OFFLINE", "1")
+		}
 		term = terminal.New()
+		applyConfig()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		printBanner()
@@ -55,6 +61,7 @@ func Execute() error {
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Use only cached registry and API data (results may be stale)")
 }
 
 func printBanner() {
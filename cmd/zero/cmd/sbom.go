@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/crashappsec/zero/pkg/workflow/hydrate"
+	"github.com/spf13/cobra"
+)
+
+var sbomName string
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom",
+	Short: "Work with SBOMs that have no repository",
+}
+
+var sbomAnalyzeCmd = &cobra.Command{
+	Use:   "analyze <file>",
+	Short: "Analyze a standalone CycloneDX or SPDX SBOM",
+	Long: `Analyze an SBOM received without source, such as one supplied by a vendor.
+
+Accepts CycloneDX JSON, SPDX 2.3 JSON, SPDX tag-value and SPDX 3.0 JSON-LD.
+Runs the code-packages features that only need the SBOM: vulnerabilities,
+licenses, package health, deprecations, typosquats and provenance. Features
+that need source (integrity, malcontent, reachability, ...) are skipped.
+
+Results are stored as the pseudo-project sbom/<name>, so 'zero report',
+'zero diff', 'zero vex' and 'zero export' work on it. Each analysis is
+recorded in the project history keyed by the SBOM's sha256 digest.
+
+Examples:
+  zero sbom analyze vendor.cdx.json                  Analyze as sbom/vendor
+  zero sbom analyze vendor.spdx --name acme-gateway  Analyze as sbom/acme-gateway
+  zero report sbom/vendor                            Report on the results`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSBOMAnalyze,
+}
+
+func init() {
+	rootCmd.AddCommand(sbomCmd)
+	sbomCmd.AddCommand(sbomAnalyzeCmd)
+
+	sbomAnalyzeCmd.Flags().StringVar(&sbomName, "name", "", "Project name (default: file name without extensions)")
+}
+
+func runSBOMAnalyze(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		term.Info("\nInterrupted...")
+		cancel()
+	}()
+
+	h, err := hydrate.New(&hydrate.Options{})
+	if err != nil {
+		return err
+	}
+
+	term.Info("Analyzing %s...", args[0])
+	result, err := h.AnalyzeSBOM(ctx, args[0], sbomName)
+	if result == nil {
+		return err
+	}
+
+	if r := result.Status.Progress.Results["code-packages"]; r != nil && r.Summary != "" {
+		term.Info("  %s", r.Summary)
+	}
+	if err != nil {
+		term.Error("SBOM analysis failed for %s", result.ProjectID)
+		return err
+	}
+
+	term.Success("Analyzed %s (%s, %d packages)", result.ProjectID, result.Format, result.Packages)
+	term.Info("  Digest: sha256:%s", result.Digest)
+	term.Info("")
+	term.Info("Next steps:")
+	term.Info("  zero report %s", result.ProjectID)
+	term.Info("  zero vex %s", result.ProjectID)
+	term.Info("  zero diff %s", result.ProjectID)
+	return nil
+}
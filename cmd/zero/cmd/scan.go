@@ -9,16 +9,24 @@ import (
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/crashappsec/zero/pkg/core/config"
 	"github.com/crashappsec/zero/pkg/scanner"
 	"github.com/crashappsec/zero/pkg/core/terminal"
+	"github.com/crashappsec/zero/pkg/workflow/diff"
+	"github.com/crashappsec/zero/pkg/workflow/hydrate"
 	"github.com/spf13/cobra"
+	goterm "golang.org/x/term"
 )
 
 var scanForce bool
 var scanSkipSlow bool
 var scanYes bool
+var scanBase string
+var scanHead string
+var scanFailOn string
+var scanFormat string
 
 var scanCmd = &cobra.Command{
 	Use:     "scan <target> [profile]",
@@ -29,6 +37,18 @@ var scanCmd = &cobra.Command{
 Target can be:
   - owner/repo    Single repository (e.g., strapi/strapi)
   - org-name      GitHub organization (e.g., zero-test-org)
+  - ./path        Local directory or checkout (imported as local/<dir>-<hash>)
+  - git URL       Any git remote: https://, ssh://, git@host:path, file://
+
+Local directories and git URLs are imported (cloned or copied) into the
+same .zero/repos/<owner>/<repo> layout before scanning. Local git checkouts
+are cloned, so uncommitted changes are left out (a warning is printed).
+
+Diff mode (--base) scans a pull request: the base and head refs are scanned
+in temporary worktrees and only new findings on lines changed between them
+are reported. The command exits non-zero when any of them are at or above
+the --fail-on severity. Diff mode takes a local git checkout or a hydrated
+owner/repo.
 
 The profile argument specifies which analyzers to run. Profiles are defined
 in the config file (config/zero.config.json).
@@ -40,7 +60,11 @@ Examples:
   zero analyze strapi/strapi           Same as scan (alias)
   zero scan strapi/strapi all-quick    Analyze with all-quick profile
   zero scan zero-test-org              Analyze all repos in org
-  zero scan owner/repo --force         Re-analyze even if results exist`,
+  zero scan owner/repo --force         Re-analyze even if results are cached
+  zero scan ./path/to/checkout         Import and analyze a local directory
+  zero scan https://git.example.com/team/repo.git
+  zero scan . --base origin/main --head HEAD    Report findings new in this branch
+  zero scan owner/repo --base main --fail-on critical`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runScan,
 }
@@ -48,9 +72,13 @@ Examples:
 func init() {
 	rootCmd.AddCommand(scanCmd)
 
-	scanCmd.Flags().BoolVar(&scanForce, "force", false, "Re-scan even if results exist")
+	scanCmd.Flags().BoolVar(&scanForce, "force", false, "Re-scan even if cached results exist")
 	scanCmd.Flags().BoolVar(&scanSkipSlow, "skip-slow", false, "Skip slow scanners")
 	scanCmd.Flags().BoolVarP(&scanYes, "yes", "y", false, "Auto-accept prompts")
+	scanCmd.Flags().StringVar(&scanBase, "base", "", "Diff mode: report only findings introduced since this ref")
+	scanCmd.Flags().StringVar(&scanHead, "head", "HEAD", "Diff mode: ref to compare against --base")
+	scanCmd.Flags().StringVar(&scanFailOn, "fail-on", "high", "Diff mode: exit non-zero on new findings at or above this severity (critical, high, medium, low, none)")
+	scanCmd.Flags().StringVar(&scanFormat, "format", "table", "Diff mode output format: table, json, summary")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -87,6 +115,15 @@ func runScan(cmd *cobra.Command, args []string) error {
 			profile, strings.Join(availableProfiles, "\n  "))
 	}
 
+	if scanBase != "" {
+		return runScanDiff(cmd, cfg, target, profile)
+	}
+
+	// Local directories and git URLs are imported and scanned through hydrate
+	if hydrate.IsSourceTarget(target) {
+		return runScanSource(target, profile)
+	}
+
 	zeroHome := cfg.ZeroHome()
 	if zeroHome == "" {
 		zeroHome = ".zero"
@@ -94,6 +131,8 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	term := terminal.New()
 	runner := scanner.NewRunner(zeroHome)
+	runner.NoCache = scanForce
+	runner.SetCacheTTL(time.Duration(cfg.Settings.CacheTTLHours) * time.Hour)
 
 	// Get repos to scan
 	var repos []string
@@ -175,3 +214,99 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runScanSource imports a local directory or git URL and scans it
+func runScanSource(target, profile string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		term.Info("\nInterrupted...")
+		cancel()
+	}()
+
+	h, err := hydrate.New(&hydrate.Options{
+		Source:   target,
+		Profile:  profile,
+		Force:    scanForce,
+		SkipSlow: scanSkipSlow,
+		Yes:      scanYes,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = h.Run(ctx)
+	return err
+}
+
+// runScanDiff scans the --base and --head refs of a checkout and reports only
+// the findings introduced between them
+func runScanDiff(cmd *cobra.Command, cfg *config.Config, target, profile string) error {
+	failOn := strings.ToLower(scanFailOn)
+	if failOn != "none" && !diff.ValidSeverity(failOn) {
+		return fmt.Errorf("invalid --fail-on %q (use critical, high, medium, low or none)", scanFailOn)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		term.Info("\nInterrupted...")
+		cancel()
+	}()
+
+	h, err := hydrate.New(&hydrate.Options{
+		Profile:  profile,
+		Force:    scanForce,
+		SkipSlow: scanSkipSlow,
+		Yes:      scanYes,
+	})
+	if err != nil {
+		return err
+	}
+	repoPath, err := hydrate.DiffScanRepoPath(h.ZeroHome(), target)
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout clean for machine-readable output
+	status := func(msg string) { term.Info("%s", msg) }
+	if scanFormat == "json" {
+		status = func(msg string) { fmt.Fprintln(os.Stderr, msg) }
+	}
+
+	result, err := h.ScanDiff(ctx, hydrate.DiffScanOptions{
+		RepoPath: repoPath,
+		Base:     scanBase,
+		Head:     scanHead,
+		OnStatus: status,
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range result.Failed {
+		fmt.Fprintf(os.Stderr, "warning: scanner %s did not complete\n", name)
+	}
+
+	useColor := goterm.IsTerminal(int(os.Stdout.Fd()))
+	if err := diff.NewFormatter(os.Stdout, useColor).FormatDelta(result.Delta, scanFormat); err != nil {
+		return err
+	}
+
+	if failOn == "none" {
+		return nil
+	}
+	if n := diff.CountAtOrAbove(result.Delta, failOn); n > 0 {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return fmt.Errorf("%d new findings at or above %s severity", n, failOn)
+	}
+	return nil
+}
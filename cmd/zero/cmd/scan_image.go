@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/crashappsec/zero/pkg/workflow/hydrate"
+	"github.com/spf13/cobra"
+)
+
+var scanImageName string
+
+var scanImageCmd = &cobra.Command{
+	Use:   "scan-image <image.tar>",
+	Short: "Analyze a container image archive",
+	Long: `Analyze a container image saved with 'docker save' or written as an OCI
+image layout tarball (plain or gzipped). No registry or container runtime is
+needed, so images built earlier in CI can be analyzed offline.
+
+The layers are unpacked natively and Zero builds a CycloneDX SBOM from what
+is installed in the image:
+  - OS packages from the dpkg, apk and rpm (sqlite) databases
+  - Language packages: node_modules, Python site-packages, Ruby gems,
+    Maven jars and the modules compiled into Go binaries
+
+It then runs code-packages against that SBOM, the secrets detector and the
+IaC checks over the image filesystem.
+
+Results are stored as the pseudo-project image/<name>, so 'zero report',
+'zero diff', 'zero vex' and 'zero export' work on it. Each analysis is
+recorded in the project history keyed by the image ID.
+
+Examples:
+  docker save app:1.2.3 -o app.tar
+  zero scan-image app.tar                   Analyze as image/app
+  zero scan-image app.tar --name checkout   Analyze as image/checkout
+  zero --offline scan-image app.tar         Use only the local caches`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScanImage,
+}
+
+func init() {
+	rootCmd.AddCommand(scanImageCmd)
+
+	scanImageCmd.Flags().StringVar(&scanImageName, "name", "", "Project name (default: repository of the first image tag, else file name)")
+}
+
+func runScanImage(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		term.Info("\nInterrupted...")
+		cancel()
+	}()
+
+	h, err := hydrate.New(&hydrate.Options{})
+	if err != nil {
+		return err
+	}
+
+	term.Info("Analyzing %s...", args[0])
+	result, err := h.AnalyzeImage(ctx, args[0], scanImageName)
+	if result == nil {
+		return err
+	}
+
+	for _, name := range result.Status.ScannersRun {
+		if r := result.Status.Progress.Results[name]; r != nil && r.Summary != "" {
+			term.Info("  %s: %s", name, r.Summary)
+		}
+	}
+	if err != nil {
+		term.Error("Image analysis failed for %s", result.ProjectID)
+		return err
+	}
+
+	img := result.Image
+	term.Success("Analyzed %s (%s/%s, %d layers, %d files)", result.ProjectID,
+		img.Config.OS, img.Config.Architecture, result.Extract.Layers, result.Extract.Files)
+	if len(img.RepoTags) > 0 {
+		term.Info("  Tags:     %s", strings.Join(img.RepoTags, ", "))
+	}
+	term.Info("  Image ID: sha256:%s", result.Digest)
+	if result.SBOM.Distro != "" {
+		term.Info("  Distro:   %s", result.SBOM.Distro)
+	}
+	term.Info("  Packages: %d OS, %d language", result.SBOM.OSPackages, result.SBOM.LangPackages)
+	for _, db := range result.SBOM.Unsupported {
+		term.Info("  Skipped unsupported package database /%s", db)
+	}
+	for _, db := range result.SBOM.Failed {
+		term.Warning("  Could not read package database /%s: %s", db.Database, db.Error)
+	}
+	term.Info("")
+	term.Info("Next steps:")
+	term.Info("  zero report %s", result.ProjectID)
+	term.Info("  zero vex %s", result.ProjectID)
+	term.Info("  zero diff %s", result.ProjectID)
+	return nil
+}
@@ -13,6 +13,13 @@ package cmd
 // - code-ownership: Code ownership and CODEOWNERS analysis
 // - developer-experience: Developer experience analysis (onboarding, tooling, workflow)
 import (
+	"fmt"
+	"os"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/forge"
+	"github.com/crashappsec/zero/pkg/scanner"
+
 	// Super scanners (v4.0)
 	_ "github.com/crashappsec/zero/pkg/scanner/code-ownership"            // Code ownership analysis
 	_ "github.com/crashappsec/zero/pkg/scanner/code-packages"             // SBOM + package analysis
@@ -22,3 +29,23 @@ import (
 	_ "github.com/crashappsec/zero/pkg/scanner/devops"                    // DevOps and CI/CD security
 	_ "github.com/crashappsec/zero/pkg/scanner/technology-identification" // Technology and AI/ML security
 )
+
+// applyConfig registers what every command needs from the config: the
+// self-hosted forges scanners resolve git remotes against, and external
+// scanner plugins, so profiles can reference them like built-in scanners
+func applyConfig() {
+	cfg, err := config.Load()
+	if err != nil {
+		// Commands that need the config report the error themselves; warn
+		// here so a broken config does not silently drop plugins
+		fmt.Fprintf(os.Stderr, "warning: %v (plugins not registered)\n", err)
+		return
+	}
+	forge.SetHosts(cfg.Settings.Forges)
+	if len(cfg.Plugins) == 0 {
+		return
+	}
+	if err := scanner.RegisterPlugins(cfg.Plugins); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
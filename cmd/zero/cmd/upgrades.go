@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/terminal"
+	"github.com/crashappsec/zero/pkg/core/upgrades"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+	"github.com/spf13/cobra"
+)
+
+var (
+	upgradesFormat    string
+	upgradesDiff      bool
+	upgradesOutput    string
+	upgradesNoResolve bool
+)
+
+var upgradesCmd = &cobra.Command{
+	Use:   "upgrades <owner/repo>",
+	Short: "Plan the dependency upgrades that fix vulnerabilities",
+	Long: `Compute the smallest set of dependency upgrades that fixes the most
+vulnerabilities found by the last scan.
+
+Vulnerable direct dependencies are bumped to their fixed version. Vulnerable
+transitive dependencies are fixed by bumping the direct dependencies that pull
+them in, when deps.dev shows a newer version resolves a fixed one; otherwise
+they are forced with npm overrides, a Go require or a pip pin. Compatible
+upgrades are preferred and upgrades crossing a major version are flagged.
+
+With --diff, the plan is applied to package.json, go.mod and requirements.txt
+and printed as a unified diff for git apply. Regenerate lockfiles afterwards.
+
+Examples:
+  zero upgrades owner/repo                         Show the upgrade plan
+  zero upgrades owner/repo --format json           Machine-readable plan
+  zero upgrades owner/repo --diff | git apply      Apply the plan to a checkout
+  zero upgrades owner/repo --diff -o fix.patch     Write the manifest diff to a file
+  zero upgrades owner/repo --no-resolve            Do not query deps.dev`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUpgrades,
+}
+
+func init() {
+	rootCmd.AddCommand(upgradesCmd)
+
+	upgradesCmd.Flags().StringVar(&upgradesFormat, "format", "text", "Output format: text, json")
+	upgradesCmd.Flags().BoolVar(&upgradesDiff, "diff", false, "Print manifest changes as a unified diff")
+	upgradesCmd.Flags().StringVarP(&upgradesOutput, "output", "o", "", "Write the plan or diff to a file instead of stdout")
+	upgradesCmd.Flags().BoolVar(&upgradesNoResolve, "no-resolve", false, "Fix transitive dependencies with overrides without querying deps.dev")
+}
+
+func runUpgrades(cmd *cobra.Command, args []string) error {
+	term := terminal.New()
+	repo := args[0]
+
+	analysisDir, err := exportAnalysisDir(term, repo)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(analysisDir, codepackages.Name+".json")); os.IsNotExist(err) {
+		term.Error("No vulnerability data found for %s", repo)
+		term.Info("Run: zero hydrate %s --profile packages", repo)
+		return fmt.Errorf("%s.json not found", codepackages.Name)
+	}
+
+	plan, err := codepackages.PlanUpgrades(cmd.Context(), analysisDir, !upgradesNoResolve)
+	if err != nil {
+		return fmt.Errorf("failed to plan upgrades: %w", err)
+	}
+
+	var out string
+	switch {
+	case upgradesDiff:
+		patches, skipped, err := upgrades.PatchManifests(filepath.Join(filepath.Dir(analysisDir), "repo"), plan)
+		if err != nil {
+			return fmt.Errorf("failed to patch manifests: %w", err)
+		}
+		var b strings.Builder
+		for _, p := range patches {
+			b.WriteString(p.Diff())
+		}
+		out = b.String()
+		for _, u := range skipped {
+			fmt.Fprintf(os.Stderr, "skipped %s %s -> %s: no manifest to edit\n", u.Package, strings.Join(u.From, ", "), u.To)
+		}
+	case upgradesFormat == "json":
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling plan: %w", err)
+		}
+		out = string(data) + "\n"
+	default:
+		printUpgradePlan(term, plan)
+		return nil
+	}
+
+	if upgradesOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(upgradesOutput, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", upgradesOutput, err)
+	}
+	term.Success("Written to %s", upgradesOutput)
+	return nil
+}
+
+func printUpgradePlan(term *terminal.Terminal, plan *upgrades.Plan) {
+	if plan.Summary.Vulnerabilities == 0 {
+		term.Success("No vulnerabilities to fix")
+		return
+	}
+
+	term.Info("%d upgrades fix %d of %d vulnerabilities", plan.Summary.Upgrades, plan.Summary.Fixed, plan.Summary.Vulnerabilities)
+	term.Info("")
+	for _, u := range plan.Upgrades {
+		line := fmt.Sprintf("  [%s] %s %s -> %s (%s) fixes %s",
+			u.Severity, u.Package, strings.Join(u.From, ", "), u.To, u.Method, strings.Join(u.Fixes, ", "))
+		if len(u.Resolves) > 0 {
+			line += fmt.Sprintf(", moves %s to fixed versions", strings.Join(u.Resolves, ", "))
+		}
+		if len(u.Via) > 0 {
+			line += fmt.Sprintf(", via %s", strings.Join(u.Via, ", "))
+		}
+		if !u.Breaking {
+			term.Info("%s", line)
+			continue
+		}
+		term.Warning("%s [breaking]", line)
+		if u.CompatibleTo != "" {
+			term.Info("      compatible alternative: %s fixes %s", u.CompatibleTo, strings.Join(u.CompatibleFixes, ", "))
+		}
+	}
+
+	if len(plan.Unfixed) > 0 {
+		term.Info("")
+		term.Warning("%d vulnerabilities have no fixed version:", len(plan.Unfixed))
+		for _, v := range plan.Unfixed {
+			term.Info("  [%s] %s %s@%s", v.Severity, v.ID, v.Package, v.Version)
+		}
+	}
+}
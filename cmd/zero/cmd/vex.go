@@ -86,12 +86,12 @@ func runVex(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("project not found")
 	}
 
-	// Check if packages.json exists (required for vulnerability data)
-	packagesPath := filepath.Join(analysisDir, "packages.json")
+	// Check if code-packages results exist (required for vulnerability data)
+	packagesPath := vex.PackagesPath(analysisDir)
 	if _, err := os.Stat(packagesPath); os.IsNotExist(err) {
 		term.Error("No vulnerability data found for %s", repo)
 		term.Info("Run: zero hydrate %s --profile packages", repo)
-		return fmt.Errorf("packages.json not found")
+		return fmt.Errorf("%s not found", filepath.Base(packagesPath))
 	}
 
 	// Configure generator
@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/sarif"
 )
 
 // AnalysisHandler handles analysis data requests
@@ -236,6 +239,80 @@ func (h *AnalysisHandler) GetDependencies(w http.ResponseWriter, r *http.Request
 	writeJSON(w, http.StatusOK, result)
 }
 
+// ExportSARIF returns all findings for a project as a SARIF 2.1.0 log
+func (h *AnalysisHandler) ExportSARIF(w http.ResponseWriter, r *http.Request) {
+	projectID := chi.URLParam(r, "projectID")
+	projectID = strings.ReplaceAll(projectID, "%2F", "/")
+
+	projectPath := filepath.Join(h.zeroHome, "repos", projectID)
+	analysisPath := filepath.Join(projectPath, "analysis")
+	if _, err := os.Stat(analysisPath); os.IsNotExist(err) {
+		writeError(w, http.StatusNotFound, "project not found", nil)
+		return
+	}
+
+	exporter := sarif.NewExporter(analysisPath, filepath.Join(projectPath, "repo"))
+	log, err := exporter.Export()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to export SARIF", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/sarif+json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(log)
+}
+
+// GetCBOM returns the CycloneDX CBOM generated by code-security crypto features
+func (h *AnalysisHandler) GetCBOM(w http.ResponseWriter, r *http.Request) {
+	projectID := chi.URLParam(r, "projectID")
+	projectID = strings.ReplaceAll(projectID, "%2F", "/")
+
+	path := filepath.Join(h.zeroHome, "repos", projectID, "analysis", "cbom.cdx.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "CBOM not found", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.cyclonedx+json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// WhyDependency returns how a package is pulled into a project, from the
+// dependency graph in its SBOM. The package query parameter is a package
+// URL, with or without a version, or a name optionally followed by @version.
+func (h *AnalysisHandler) WhyDependency(w http.ResponseWriter, r *http.Request) {
+	projectID := chi.URLParam(r, "projectID")
+	projectID = strings.ReplaceAll(projectID, "%2F", "/")
+	query := r.URL.Query().Get("package")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "package query parameter is required", nil)
+		return
+	}
+
+	bom, err := cyclonedx.ReadFile(filepath.Join(h.zeroHome, "repos", projectID, "analysis", "sbom.cdx.json"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "SBOM not found", err)
+		return
+	}
+
+	graph := cyclonedx.NewGraph(bom)
+	matches := []cyclonedx.Introduction{}
+	for _, ref := range graph.Find(query) {
+		matches = append(matches, graph.Explain(ref))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"project":          projectID,
+		"package":          query,
+		"has_dependencies": graph.HasDependencies(),
+		"total":            len(matches),
+		"data":             matches,
+	})
+}
+
 // readAnalysis reads an analysis file for a project
 func (h *AnalysisHandler) readAnalysis(projectID, analysisType string) (map[string]interface{}, error) {
 	path := filepath.Join(h.zeroHome, "repos", projectID, "analysis", analysisType+".json")
@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/crashappsec/zero/pkg/api/jobs"
 	"github.com/crashappsec/zero/pkg/api/types"
 	"github.com/crashappsec/zero/pkg/core/config"
 )
@@ -838,6 +839,166 @@ func TestAnalysisHandler_GetDependencies(t *testing.T) {
 	}
 }
 
+func TestAnalysisHandler_ExportSARIF(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	analysisPath := filepath.Join(tmpDir, "repos", "org", "repo", "analysis")
+	os.MkdirAll(analysisPath, 0755)
+	securityData := map[string]interface{}{
+		"findings": map[string]interface{}{
+			"vulns": []interface{}{
+				map[string]interface{}{"rule_id": "sql-injection", "title": "SQL injection", "severity": "high", "file": "db.go", "line": 10},
+			},
+		},
+	}
+	data, _ := json.Marshal(securityData)
+	os.WriteFile(filepath.Join(analysisPath, "code-security.json"), data, 0644)
+
+	handler := NewAnalysisHandler(tmpDir)
+
+	r := chi.NewRouter()
+	r.Get("/api/projects/{projectID}/export/sarif", handler.ExportSARIF)
+
+	req := httptest.NewRequest("GET", "/api/projects/org%2Frepo/export/sarif", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ExportSARIF() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/sarif+json" {
+		t.Errorf("Content-Type = %q, want application/sarif+json", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	json.Unmarshal(body, &result)
+	if result["version"] != "2.1.0" {
+		t.Errorf("version = %v, want 2.1.0", result["version"])
+	}
+	if runs, ok := result["runs"].([]interface{}); !ok || len(runs) != 1 {
+		t.Errorf("expected 1 run, got %v", result["runs"])
+	}
+}
+
+func TestAnalysisHandler_ExportSARIF_NotFound(t *testing.T) {
+	handler := NewAnalysisHandler(t.TempDir())
+
+	r := chi.NewRouter()
+	r.Get("/api/projects/{projectID}/export/sarif", handler.ExportSARIF)
+
+	req := httptest.NewRequest("GET", "/api/projects/org%2Fmissing/export/sarif", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Result().StatusCode != http.StatusNotFound {
+		t.Errorf("ExportSARIF() status = %d, want %d", w.Result().StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestAnalysisHandler_GetCBOM(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	analysisPath := filepath.Join(tmpDir, "repos", "org", "repo", "analysis")
+	os.MkdirAll(analysisPath, 0755)
+	cbom := `{"bomFormat":"CycloneDX","specVersion":"1.6","components":[{"type":"cryptographic-asset","name":"DES"}]}`
+	os.WriteFile(filepath.Join(analysisPath, "cbom.cdx.json"), []byte(cbom), 0644)
+
+	handler := NewAnalysisHandler(tmpDir)
+
+	r := chi.NewRouter()
+	r.Get("/api/projects/{projectID}/cbom", handler.GetCBOM)
+
+	req := httptest.NewRequest("GET", "/api/projects/org%2Frepo/cbom", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GetCBOM() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/vnd.cyclonedx+json" {
+		t.Errorf("Content-Type = %q, want application/vnd.cyclonedx+json", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var result map[string]interface{}
+	json.Unmarshal(body, &result)
+	if result["bomFormat"] != "CycloneDX" {
+		t.Errorf("bomFormat = %v, want CycloneDX", result["bomFormat"])
+	}
+
+	// Project without a CBOM
+	req = httptest.NewRequest("GET", "/api/projects/org%2Fmissing/cbom", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusNotFound {
+		t.Errorf("GetCBOM() status = %d, want %d", w.Result().StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestAnalysisHandler_WhyDependency(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	analysisPath := filepath.Join(tmpDir, "repos", "org", "repo", "analysis")
+	os.MkdirAll(analysisPath, 0755)
+	sbom := `{"bomFormat":"CycloneDX","specVersion":"1.6",
+		"metadata":{"component":{"bom-ref":"app","name":"app"}},
+		"components":[
+			{"type":"library","bom-ref":"mkdirp","name":"mkdirp","version":"0.5.1","purl":"pkg:npm/mkdirp@0.5.1"},
+			{"type":"library","bom-ref":"minimist","name":"minimist","version":"0.0.8","purl":"pkg:npm/minimist@0.0.8"}
+		],
+		"dependencies":[{"ref":"app","dependsOn":["mkdirp"]},{"ref":"mkdirp","dependsOn":["minimist"]}]}`
+	os.WriteFile(filepath.Join(analysisPath, "sbom.cdx.json"), []byte(sbom), 0644)
+
+	handler := NewAnalysisHandler(tmpDir)
+
+	r := chi.NewRouter()
+	r.Get("/api/repos/{projectID}/deps/why", handler.WhyDependency)
+
+	req := httptest.NewRequest("GET", "/api/repos/org%2Frepo/deps/why?package=pkg:npm/minimist@0.0.8", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("WhyDependency() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Total int `json:"total"`
+		Data  []struct {
+			Direct   bool     `json:"direct"`
+			Shortest []string `json:"shortest_path"`
+		} `json:"data"`
+	}
+	json.Unmarshal(body, &result)
+	if result.Total != 1 || result.Data[0].Direct {
+		t.Fatalf("unexpected result: %s", body)
+	}
+	if got := strings.Join(result.Data[0].Shortest, " > "); got != "mkdirp@0.5.1 > minimist@0.0.8" {
+		t.Errorf("shortest_path = %q", got)
+	}
+
+	// Missing package parameter
+	req = httptest.NewRequest("GET", "/api/repos/org%2Frepo/deps/why", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("WhyDependency() status = %d, want %d", w.Result().StatusCode, http.StatusBadRequest)
+	}
+
+	// Project without an SBOM
+	req = httptest.NewRequest("GET", "/api/repos/org%2Fmissing/deps/why?package=minimist", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Result().StatusCode != http.StatusNotFound {
+		t.Errorf("WhyDependency() status = %d, want %d", w.Result().StatusCode, http.StatusNotFound)
+	}
+}
+
 func TestAnalysisHandler_GetAggregateStats(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -1452,3 +1613,61 @@ func TestConfigHandler_ImportConfig_InvalidJSON(t *testing.T) {
 		t.Errorf("ImportConfig() status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
 	}
 }
+
+func TestScanHandler_AnalyzeSBOM(t *testing.T) {
+	queue := jobs.NewQueue(10)
+	handler := NewScanHandler(queue)
+
+	sbom := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[]}`
+	req := httptest.NewRequest("POST", "/api/sbom/analyze?name=Vendor%20App", strings.NewReader(sbom))
+	w := httptest.NewRecorder()
+
+	handler.AnalyzeSBOM(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("AnalyzeSBOM() status = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+
+	var result SBOMAnalyzeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.ProjectID != "sbom/vendor-app" || result.Format != "cyclonedx" {
+		t.Errorf("response = %+v", result)
+	}
+
+	job, ok := queue.Get(result.JobID)
+	if !ok {
+		t.Fatal("job not queued")
+	}
+	defer os.Remove(job.SBOMPath)
+	data, err := os.ReadFile(job.SBOMPath)
+	if err != nil || string(data) != sbom {
+		t.Errorf("stored SBOM = %q, %v", data, err)
+	}
+}
+
+func TestScanHandler_AnalyzeSBOM_Invalid(t *testing.T) {
+	handler := NewScanHandler(jobs.NewQueue(10))
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{"not an SBOM", "/api/sbom/analyze?name=vendor", `{"hello":"world"}`},
+		{"missing name", "/api/sbom/analyze", `{"bomFormat":"CycloneDX"}`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+
+		handler.AnalyzeSBOM(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -10,6 +13,8 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"github.com/crashappsec/zero/pkg/api/jobs"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+	"github.com/crashappsec/zero/pkg/workflow/hydrate"
 )
 
 // validTargetPattern matches valid GitHub owner/repo or org names
@@ -139,6 +144,99 @@ func (h *ScanHandler) Start(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusAccepted, resp)
 }
 
+// maxSBOMSize limits uploaded SBOMs
+const maxSBOMSize = 50 << 20
+
+// SBOMAnalyzeResponse is returned when an uploaded SBOM is queued
+type SBOMAnalyzeResponse struct {
+	StartResponse
+	ProjectID string `json:"project_id"`
+	Format    string `json:"format"` // cyclonedx, spdx-json, spdx-tag-value or spdx-jsonld
+}
+
+// AnalyzeSBOM queues an uploaded CycloneDX or SPDX SBOM for analysis as the
+// sbom/<name> pseudo-project. The SBOM is either the raw request body or the
+// "file" field of a multipart form; the name query parameter defaults to the
+// uploaded file name.
+func (h *ScanHandler) AnalyzeSBOM(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSBOMSize)
+
+	var data []byte
+	var err error
+	filename := ""
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, ferr := r.FormFile("file")
+		if ferr != nil {
+			writeError(w, http.StatusBadRequest, "file is required", ferr)
+			return
+		}
+		defer file.Close()
+		filename = header.Filename
+		data, err = io.ReadAll(file)
+	} else {
+		data, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "SBOM is too large", err)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "failed to read SBOM", err)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" && filename == "" {
+		writeError(w, http.StatusBadRequest, "name is required", nil)
+		return
+	}
+	format := codepackages.DetectSBOMFormat(data)
+	if format == "" {
+		writeError(w, http.StatusBadRequest, "not a CycloneDX JSON or SPDX SBOM", nil)
+		return
+	}
+	projectID := hydrate.SBOMProjectID(filename, name)
+
+	// The worker removes the file once the job finishes
+	f, err := os.CreateTemp("", "zero-sbom-*")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to store SBOM", err)
+		return
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		writeError(w, http.StatusInternalServerError, "failed to store SBOM", err)
+		return
+	}
+
+	jobID := jobs.GenerateJobID()
+	job := jobs.NewJob(jobID, projectID, false, "")
+	job.SBOMPath = f.Name()
+
+	if err := h.queue.Enqueue(job); err != nil {
+		os.Remove(f.Name())
+		writeError(w, http.StatusServiceUnavailable, "failed to enqueue job", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, SBOMAnalyzeResponse{
+		StartResponse: StartResponse{
+			JobID:      jobID,
+			Target:     projectID,
+			Status:     string(jobs.JobStatusQueued),
+			CreatedAt:  job.StartedAt,
+			WSEndpoint: "/ws/scan/" + jobID,
+		},
+		ProjectID: projectID,
+		Format:    format,
+	})
+}
+
 // Get returns the status of a scan job
 func (h *ScanHandler) Get(w http.ResponseWriter, r *http.Request) {
 	jobID := chi.URLParam(r, "jobID")
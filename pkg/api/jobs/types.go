@@ -37,6 +37,7 @@ type Job struct {
 	Force       bool          `json:"force"`
 	SkipSlow    bool          `json:"skip_slow"`
 	Depth       int           `json:"depth"`
+	SBOMPath    string        `json:"-"`           // uploaded SBOM to analyze instead of cloning; removed when the job ends
 
 	mu          sync.RWMutex
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -88,6 +90,9 @@ func (w *Worker) run(ctx context.Context) {
 func (w *Worker) executeJob(ctx context.Context, job *Job) {
 	log.Printf("[Worker %d] Starting job %s: %s", w.id, job.ID, job.Target)
 	startTime := time.Now()
+	if job.SBOMPath != "" {
+		defer os.Remove(job.SBOMPath)
+	}
 
 	// Broadcast job started
 	w.broadcast(job.ID, JobStatusMessage{
@@ -132,8 +137,17 @@ func (w *Worker) executeJob(ctx context.Context, job *Job) {
 		Status: JobStatusScanning,
 	})
 
-	// Run the hydrate workflow
-	projectIDs, err := h.Run(ctx)
+	// Run the hydrate workflow; uploaded SBOMs are analyzed without a clone
+	var projectIDs []string
+	if job.SBOMPath != "" {
+		var result *hydrate.SBOMResult
+		result, err = h.AnalyzeSBOM(ctx, job.SBOMPath, strings.TrimPrefix(job.Target, hydrate.SBOMOwner+"/"))
+		if result != nil {
+			projectIDs = []string{result.ProjectID}
+		}
+	} else {
+		projectIDs, err = h.Run(ctx)
+	}
 	if err != nil {
 		// Check if it was canceled
 		if ctx.Err() != nil {
@@ -151,6 +151,9 @@ func (s *Server) setupRoutes() {
 			r.Delete("/repos/{projectID}", repoHandler.Delete)
 			r.Get("/repos/{projectID}/freshness", repoHandler.GetFreshness)
 			r.Get("/repos/{projectID}/analysis/{analysisType}", analysisHandler.GetAnalysis)
+			r.Get("/repos/{projectID}/export/sarif", analysisHandler.ExportSARIF)
+			r.Get("/repos/{projectID}/cbom", analysisHandler.GetCBOM)
+			r.Get("/repos/{projectID}/deps/why", analysisHandler.WhyDependency)
 
 			// Backwards compatibility: /projects routes still work
 			r.Get("/projects", repoHandler.List)
@@ -158,6 +161,9 @@ func (s *Server) setupRoutes() {
 			r.Delete("/projects/{projectID}", repoHandler.Delete)
 			r.Get("/projects/{projectID}/freshness", repoHandler.GetFreshness)
 			r.Get("/projects/{projectID}/analysis/{analysisType}", analysisHandler.GetAnalysis)
+			r.Get("/projects/{projectID}/export/sarif", analysisHandler.ExportSARIF)
+			r.Get("/projects/{projectID}/cbom", analysisHandler.GetCBOM)
+			r.Get("/projects/{projectID}/deps/why", analysisHandler.WhyDependency)
 
 			// Analysis aggregation endpoints
 			r.Get("/analysis/stats", analysisHandler.GetAggregateStats)
@@ -192,6 +198,7 @@ func (s *Server) setupRoutes() {
 			r.Get("/scans/stats", scanHandler.Stats)
 			r.Get("/scans/{jobID}", scanHandler.Get)
 			r.Delete("/scans/{jobID}", scanHandler.Cancel)
+			r.Post("/sbom/analyze", scanHandler.AnalyzeSBOM)
 
 			// Banter endpoints (Full Personality Mode)
 			if s.banterHandler != nil {
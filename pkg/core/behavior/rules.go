@@ -0,0 +1,232 @@
+package behavior
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Evidence categories
+const (
+	CategoryInstallHook  = "install-hook"
+	CategoryNetwork      = "network"
+	CategoryExec         = "exec"
+	CategoryObfuscation  = "obfuscation"
+	CategoryCredentials  = "credential-access"
+	CategoryRecon        = "recon"
+	CategoryExfiltration = "exfiltration"
+	CategoryBinary       = "binary"
+)
+
+type rule struct {
+	id          string
+	category    string
+	risk        string
+	description string
+	re          *regexp.Regexp
+}
+
+// match returns the evidence of the rule's first match in data
+func (r rule) match(data []byte) (Evidence, bool) {
+	loc := r.re.FindIndex(data)
+	if loc == nil {
+		return Evidence{}, false
+	}
+	line, text := lineAt(data, loc[0])
+	return Evidence{
+		Rule:        r.id,
+		Category:    r.category,
+		Description: r.description,
+		Risk:        r.risk,
+		Line:        line,
+		Snippet:     snippet(text),
+	}, true
+}
+
+// codeRules match install-time code in JavaScript, Python, Ruby and shell
+var codeRules = []rule{
+	{"http-request", CategoryNetwork, RiskMedium, "Makes HTTP requests",
+		regexp.MustCompile(`require\(\s*['"](node:)?https?['"]\s*\)|\bfetch\(|XMLHttpRequest|urllib\.request|urlopen\(|\brequests\.(get|post|put)\(|http\.client|Net::HTTP|open-uri|Invoke-WebRequest`)},
+	{"raw-socket", CategoryNetwork, RiskMedium, "Opens network sockets",
+		regexp.MustCompile(`require\(\s*['"](node:)?(net|dgram|dns|tls)['"]\s*\)|\bsocket\.socket\(|TCPSocket\.new`)},
+	{"download-tool", CategoryNetwork, RiskMedium, "Downloads with curl or wget",
+		regexp.MustCompile(`\b(curl|wget)\s`)},
+	{"pipe-to-shell", CategoryExec, RiskHigh, "Pipes downloaded content into a shell",
+		regexp.MustCompile(`\b(curl|wget)\b[^|;&\n]*\|\s*(sudo\s+)?(ba|z)?sh\b`)},
+	{"child-process", CategoryExec, RiskLow, "Runs other programs",
+		regexp.MustCompile(`require\(\s*['"](node:)?child_process['"]\s*\)|\bsubprocess\.|os\.system\(|os\.popen\(|\bexecSync\(|\bspawnSync\(|\bexecFileSync\(|Kernel\.system|IO\.popen`)},
+	{"dynamic-eval", CategoryExec, RiskMedium, "Evaluates dynamically built code",
+		regexp.MustCompile(`\beval\(|new Function\(|vm\.runIn(New|This)?Context|\bexec\(\s*(compile|base64|zlib|marshal|codecs|bytes)`)},
+	{"base64-decode", CategoryObfuscation, RiskMedium, "Decodes base64 data",
+		regexp.MustCompile(`base64\.b64decode|Buffer\.from\([^)]*['"]base64['"]|\batob\(|base64\s+(-d|--decode)|Base64\.decode64|base64_decode\(`)},
+	{"packed-payload", CategoryObfuscation, RiskHigh, "Unpacks a compressed or marshalled payload",
+		regexp.MustCompile(`zlib\.decompress\(|marshal\.loads\(|zlib\.inflateSync\(|codecs\.decode\([^)]*rot|String\.fromCharCode\((\s*\d+\s*,){10,}`)},
+	{"credential-files", CategoryCredentials, RiskHigh, "Reads credential files",
+		regexp.MustCompile(`\.npmrc|\.pypirc|\.ssh/|id_rsa|\.aws/credentials|\.docker/config\.json|\.git-credentials|\.kube/config|/etc/passwd|\.bash_history`)},
+	{"secret-env", CategoryCredentials, RiskHigh, "Reads secret environment variables",
+		regexp.MustCompile(`\b(AWS_SECRET_ACCESS_KEY|AWS_SESSION_TOKEN|GITHUB_TOKEN|GH_TOKEN|NPM_TOKEN|PYPI_TOKEN|CI_JOB_TOKEN)\b`)},
+	{"env-dump", CategoryCredentials, RiskHigh, "Collects the whole environment",
+		regexp.MustCompile(`JSON\.stringify\(\s*process\.env\s*\)|dict\(\s*os\.environ\s*\)|os\.environ\.copy\(\)|ENV\.to_h`)},
+	{"host-info", CategoryRecon, RiskLow, "Collects host and user information",
+		regexp.MustCompile(`os\.(hostname|userInfo|networkInterfaces)\(\)|socket\.gethostname\(|getpass\.getuser\(|platform\.node\(`)},
+	{"exfil-endpoint", CategoryExfiltration, RiskHigh, "Contacts a webhook, paste or out-of-band testing service",
+		regexp.MustCompile(`discord(app)?\.com/api/webhooks|api\.telegram\.org/bot|pastebin\.com|ngrok(-free)?\.(io|app)|webhook\.site|pipedream\.net|burpcollaborator\.net|\.oast\.(fun|me|pro|live|site|online)|interact\.sh|requestbin`)},
+}
+
+var (
+	obfuscatorIdentifiers = regexp.MustCompile(`_0x[0-9a-f]{4,6}`)
+	hexEscapes            = regexp.MustCompile(`\\x[0-9a-fA-F]{2}`)
+	encodedBlob           = regexp.MustCompile(`[A-Za-z0-9+/]{400,}={0,2}`)
+
+	installCmdclass = regexp.MustCompile(`cmdclass\s*=\s*\{[^}]*['"](install|develop|egg_info)['"]`)
+	commandSplitter = regexp.MustCompile(`\s*(?:&&|\|\||;)\s*`)
+)
+
+// minifiedLineLength is the line length past which install code is
+// considered minified
+const minifiedLineLength = 1000
+
+// analyze applies the code rules and obfuscation checks to install-time code
+func analyze(data []byte) []Evidence {
+	var evidence []Evidence
+	for _, r := range codeRules {
+		if e, ok := r.match(data); ok {
+			evidence = append(evidence, e)
+		}
+	}
+
+	if loc := encodedBlob.FindIndex(data); loc != nil {
+		line, _ := lineAt(data, loc[0])
+		evidence = append(evidence, Evidence{
+			Rule:        "encoded-blob",
+			Category:    CategoryObfuscation,
+			Description: "Embeds a large encoded blob",
+			Risk:        RiskMedium,
+			Line:        line,
+		})
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) > minifiedLineLength {
+			evidence = append(evidence, Evidence{
+				Rule:        "minified-code",
+				Category:    CategoryObfuscation,
+				Description: "Install code is minified",
+				Risk:        RiskMedium,
+				Line:        i + 1,
+			})
+			break
+		}
+	}
+	for _, e := range obfuscation(data) {
+		if !hasRule(evidence, e.Rule) {
+			evidence = append(evidence, e)
+		}
+	}
+	return evidence
+}
+
+func hasRule(evidence []Evidence, id string) bool {
+	for _, e := range evidence {
+		if e.Rule == id {
+			return true
+		}
+	}
+	return false
+}
+
+// obfuscation detects the output of JavaScript obfuscators and strings
+// packed as hex escapes. Files where either is found are also checked for
+// dynamic evaluation, which is how such payloads run.
+func obfuscation(data []byte) []Evidence {
+	var evidence []Evidence
+	if ids := obfuscatorIdentifiers.FindAllIndex(data, 20); len(ids) == 20 {
+		line, _ := lineAt(data, ids[0][0])
+		evidence = append(evidence, Evidence{
+			Rule:        "obfuscator-identifiers",
+			Category:    CategoryObfuscation,
+			Description: "Code was run through a JavaScript obfuscator",
+			Risk:        RiskHigh,
+			Line:        line,
+		})
+	}
+	if escapes := hexEscapes.FindAllIndex(data, -1); len(escapes) >= 100 && len(escapes)*4*10 >= len(data) {
+		line, _ := lineAt(data, escapes[0][0])
+		evidence = append(evidence, Evidence{
+			Rule:        "hex-escaped-strings",
+			Category:    CategoryObfuscation,
+			Description: "Strings are hidden as hex escapes",
+			Risk:        RiskMedium,
+			Line:        line,
+		})
+	}
+	if len(evidence) > 0 {
+		for _, r := range codeRules {
+			if r.id != "dynamic-eval" {
+				continue
+			}
+			if e, ok := r.match(data); ok {
+				evidence = append(evidence, e)
+			}
+		}
+	}
+	return evidence
+}
+
+// combine derives the evidence that only the combination of several
+// behaviors gives: code that reads credentials and has network access is
+// far more suspicious than either alone
+func combine(evidence []Evidence) []Evidence {
+	has := make(map[string]bool)
+	for _, e := range evidence {
+		has[e.Category] = true
+	}
+	network := has[CategoryNetwork] || has[CategoryExfiltration]
+
+	var out []Evidence
+	switch {
+	case has[CategoryCredentials] && network:
+		out = append(out, Evidence{Rule: "credential-exfiltration", Category: CategoryExfiltration, Risk: RiskCritical,
+			Description: "Reads credentials and sends data over the network"})
+	case has[CategoryRecon] && network:
+		out = append(out, Evidence{Rule: "host-beacon", Category: CategoryExfiltration, Risk: RiskHigh,
+			Description: "Sends host or user information over the network"})
+	}
+	if has[CategoryObfuscation] && (network || has[CategoryExec]) {
+		risk := RiskHigh
+		if network && has[CategoryExec] {
+			risk = RiskCritical
+		}
+		out = append(out, Evidence{Rule: "obfuscated-payload", Category: CategoryObfuscation, Risk: risk,
+			Description: "Runs obfuscated code with network or process access"})
+	} else if has[CategoryNetwork] && has[CategoryExec] {
+		out = append(out, Evidence{Rule: "download-and-execute", Category: CategoryExec, Risk: RiskMedium,
+			Description: "Downloads and runs code at install"})
+	}
+	return out
+}
+
+// lineAt returns the 1-based line number and text of the line holding an
+// offset
+func lineAt(data []byte, offset int) (int, string) {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := bytes.IndexByte(data[offset:], '\n')
+	if end < 0 {
+		end = len(data)
+	} else {
+		end += offset
+	}
+	// Keep the match in view on minified lines
+	if offset-start > 60 {
+		start = offset - 60
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1, string(data[start:end])
+}
+
+// snippet trims evidence text for display
+func snippet(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 120 {
+		s = s[:117] + "..."
+	}
+	return s
+}
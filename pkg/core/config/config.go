@@ -15,17 +15,27 @@ type Config struct {
 	Settings Settings           `json:"settings"`
 	Profiles map[string]Profile `json:"profiles"`
 	Scanners map[string]Scanner `json:"scanners,omitempty"` // Loaded from defaults
+	Plugins  []Plugin           `json:"plugins,omitempty"`  // External scanner executables
 }
 
 // Settings contains global settings
 type Settings struct {
-	DefaultProfile        string `json:"default_profile"`
-	StoragePath           string `json:"storage_path"`
-	ParallelRepos         int    `json:"parallel_repos"`
-	ParallelScanners      int    `json:"parallel_scanners"`
-	ScannerTimeoutSeconds int    `json:"scanner_timeout_seconds"`
-	CacheTTLHours         int    `json:"cache_ttl_hours"`
-	PersonalityMode       string `json:"personality_mode,omitempty"` // "full", "minimal", "neutral" (default: "minimal")
+	DefaultProfile        string      `json:"default_profile"`
+	StoragePath           string      `json:"storage_path"`
+	ParallelRepos         int         `json:"parallel_repos"`
+	ParallelScanners      int         `json:"parallel_scanners"`
+	ScannerTimeoutSeconds int         `json:"scanner_timeout_seconds"`
+	CacheTTLHours         int         `json:"cache_ttl_hours"`
+	PersonalityMode       string      `json:"personality_mode,omitempty"` // "full", "minimal", "neutral" (default: "minimal")
+	Forges                []ForgeHost `json:"forges,omitempty"`           // Self-hosted GitHub Enterprise, GitLab and Gitea instances
+}
+
+// ForgeHost maps a self-hosted git server to its forge type
+type ForgeHost struct {
+	Host     string `json:"host"`                // Hostname used in git remotes (e.g., "gitlab.example.com")
+	Type     string `json:"type"`                // "github", "gitlab" or "gitea"
+	URL      string `json:"url,omitempty"`       // Web root (default: https://<host>)
+	TokenEnv string `json:"token_env,omitempty"` // Environment variable holding the API token
 }
 
 // Scanner defines a scanner configuration with features
@@ -38,6 +48,19 @@ type Scanner struct {
 	Features      map[string]interface{} `json:"features,omitempty"`
 }
 
+// Plugin declares an external scanner executable. Plugins receive the scan
+// options as JSON on stdin and stream progress and the scan result as JSON
+// lines on stdout (see pkg/scanner/plugin.go).
+type Plugin struct {
+	Name           string            `json:"name"`
+	Description    string            `json:"description,omitempty"`
+	Command        []string          `json:"command"`                   // Executable and arguments
+	Dependencies   []string          `json:"dependencies,omitempty"`    // Scanners that must run first
+	Version        string            `json:"version,omitempty"`         // Enables result caching; bump to invalidate
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"` // Overrides scanner_timeout_seconds
+	Env            map[string]string `json:"env,omitempty"`             // Extra environment variables
+}
+
 // Profile defines a scanning profile
 type Profile struct {
 	Name             string                            `json:"name"`
@@ -237,6 +260,21 @@ func loadAndMerge(cfg *Config, path string) error {
 		cfg.Scanners[name] = scanner
 	}
 
+	// Merge plugins (overlay wins for each plugin name)
+	for _, plugin := range overlay.Plugins {
+		replaced := false
+		for i := range cfg.Plugins {
+			if cfg.Plugins[i].Name == plugin.Name {
+				cfg.Plugins[i] = plugin
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			cfg.Plugins = append(cfg.Plugins, plugin)
+		}
+	}
+
 	return nil
 }
 
@@ -168,6 +168,19 @@ func (c *Config) Validate() error {
 		"developer-experience":       true,
 	}
 
+	for _, plugin := range c.Plugins {
+		if plugin.Name == "" {
+			return fmt.Errorf("plugin without name")
+		}
+		if validScanners[plugin.Name] {
+			return fmt.Errorf("plugin %s conflicts with a built-in scanner", plugin.Name)
+		}
+		if len(plugin.Command) == 0 {
+			return fmt.Errorf("plugin %s has no command", plugin.Name)
+		}
+		validScanners[plugin.Name] = true
+	}
+
 	for profileName, profile := range c.Profiles {
 		for _, scanner := range profile.Scanners {
 			if !validScanners[scanner] {
@@ -12,8 +12,10 @@ import (
 
 // Credentials holds API keys and tokens
 type Credentials struct {
-	GitHubToken    string `json:"github_token,omitempty"`
-	AnthropicKey   string `json:"anthropic_api_key,omitempty"`
+	GitHubToken  string `json:"github_token,omitempty"`
+	GitLabToken  string `json:"gitlab_token,omitempty"`
+	GiteaToken   string `json:"gitea_token,omitempty"`
+	AnthropicKey string `json:"anthropic_api_key,omitempty"`
 }
 
 // Source indicates where a credential came from
@@ -130,6 +132,60 @@ func GetGitHubToken() CredentialInfo {
 	}
 }
 
+// GetGitLabToken returns the GitLab token from the best available source
+// Priority: 1. GITLAB_TOKEN env var, 2. credentials.json
+func GetGitLabToken() CredentialInfo {
+	// 1. Check environment variable
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		return CredentialInfo{
+			Value:  token,
+			Source: SourceEnvVar,
+			Valid:  true,
+		}
+	}
+
+	// 2. Check credentials file
+	if creds, err := Load(); err == nil && creds.GitLabToken != "" {
+		return CredentialInfo{
+			Value:  creds.GitLabToken,
+			Source: SourceConfigFile,
+			Valid:  true,
+		}
+	}
+
+	return CredentialInfo{
+		Source: SourceNone,
+		Valid:  false,
+	}
+}
+
+// GetGiteaToken returns the Gitea token from the best available source
+// Priority: 1. GITEA_TOKEN env var, 2. credentials.json
+func GetGiteaToken() CredentialInfo {
+	// 1. Check environment variable
+	if token := os.Getenv("GITEA_TOKEN"); token != "" {
+		return CredentialInfo{
+			Value:  token,
+			Source: SourceEnvVar,
+			Valid:  true,
+		}
+	}
+
+	// 2. Check credentials file
+	if creds, err := Load(); err == nil && creds.GiteaToken != "" {
+		return CredentialInfo{
+			Value:  creds.GiteaToken,
+			Source: SourceConfigFile,
+			Valid:  true,
+		}
+	}
+
+	return CredentialInfo{
+		Source: SourceNone,
+		Valid:  false,
+	}
+}
+
 // GetAnthropicKey returns the Anthropic API key from the best available source
 // Priority: 1. ANTHROPIC_API_KEY env var, 2. credentials.json
 func GetAnthropicKey() CredentialInfo {
@@ -167,6 +223,26 @@ func SetGitHubToken(token string) error {
 	return Save(creds)
 }
 
+// SetGitLabToken saves the GitLab token to the credentials file
+func SetGitLabToken(token string) error {
+	creds, err := Load()
+	if err != nil {
+		creds = &Credentials{}
+	}
+	creds.GitLabToken = token
+	return Save(creds)
+}
+
+// SetGiteaToken saves the Gitea token to the credentials file
+func SetGiteaToken(token string) error {
+	creds, err := Load()
+	if err != nil {
+		creds = &Credentials{}
+	}
+	creds.GiteaToken = token
+	return Save(creds)
+}
+
 // SetAnthropicKey saves the Anthropic API key to the credentials file
 func SetAnthropicKey(key string) error {
 	creds, err := Load()
@@ -0,0 +1,129 @@
+// Package cvss parses CVSS v3.0, v3.1 and v4.0 vector strings and computes
+// their scores, so severities come from the vector rather than from labels
+// assigned by each advisory database
+package cvss
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Versions of the CVSS specification
+const (
+	V30 = "3.0"
+	V31 = "3.1"
+	V40 = "4.0"
+)
+
+// Severity ratings, shared by CVSS v3 and v4
+const (
+	SeverityNone     = "none"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Vector is a parsed CVSS vector
+type Vector struct {
+	Version string
+	metrics map[string]string
+	raw     string
+}
+
+// Parse parses a vector such as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+// or "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N".
+// Every base metric must be present; metric order is not enforced.
+func Parse(s string) (*Vector, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.Split(s, "/")
+	prefix, version, ok := strings.Cut(parts[0], ":")
+	if !ok || prefix != "CVSS" {
+		return nil, fmt.Errorf("invalid CVSS vector %q: missing CVSS version prefix", s)
+	}
+
+	var defs map[string][]string
+	var required []string
+	switch version {
+	case V30, V31:
+		defs, required = v3Metrics, v3Required
+	case V40:
+		defs, required = v4Metrics, v4Required
+	default:
+		return nil, fmt.Errorf("invalid CVSS vector %q: unsupported version %q", s, version)
+	}
+
+	v := &Vector{Version: version, metrics: make(map[string]string, len(parts)-1), raw: s}
+	for _, part := range parts[1:] {
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid CVSS vector %q: malformed metric %q", s, part)
+		}
+		values, known := defs[name]
+		if !known {
+			return nil, fmt.Errorf("invalid CVSS vector %q: unknown metric %q", s, name)
+		}
+		if !contains(values, value) {
+			return nil, fmt.Errorf("invalid CVSS vector %q: invalid value %q for %s", s, value, name)
+		}
+		if _, dup := v.metrics[name]; dup {
+			return nil, fmt.Errorf("invalid CVSS vector %q: duplicate metric %s", s, name)
+		}
+		v.metrics[name] = value
+	}
+	for _, name := range required {
+		if _, ok := v.metrics[name]; !ok {
+			return nil, fmt.Errorf("invalid CVSS vector %q: missing metric %s", s, name)
+		}
+	}
+	return v, nil
+}
+
+// String returns the vector as parsed
+func (v *Vector) String() string {
+	return v.raw
+}
+
+// Metric returns the value of a metric, or "" if the vector does not set it
+func (v *Vector) Metric(name string) string {
+	return v.metrics[name]
+}
+
+// Score returns the CVSS v3 base score, or the CVSS v4 score, which also
+// accounts for any threat and environmental metrics in the vector
+func (v *Vector) Score() float64 {
+	if v.Version == V40 {
+		return v.scoreV4()
+	}
+	return v.scoreV3()
+}
+
+// Severity returns the severity rating of the vector's score
+func (v *Vector) Severity() string {
+	return Severity(v.Score())
+}
+
+// Severity returns the qualitative rating of a score
+func Severity(score float64) string {
+	switch {
+	case score <= 0:
+		return SeverityNone
+	case score < 4.0:
+		return SeverityLow
+	case score < 7.0:
+		return SeverityMedium
+	case score < 9.0:
+		return SeverityHigh
+	default:
+		return SeverityCritical
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
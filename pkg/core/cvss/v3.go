@@ -0,0 +1,86 @@
+package cvss
+
+import "math"
+
+var v3Required = []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"}
+
+var v3Metrics = map[string][]string{
+	// Base
+	"AV": {"N", "A", "L", "P"},
+	"AC": {"L", "H"},
+	"PR": {"N", "L", "H"},
+	"UI": {"N", "R"},
+	"S":  {"U", "C"},
+	"C":  {"H", "L", "N"},
+	"I":  {"H", "L", "N"},
+	"A":  {"H", "L", "N"},
+	// Temporal
+	"E":  {"X", "H", "F", "P", "U"},
+	"RL": {"X", "U", "W", "T", "O"},
+	"RC": {"X", "C", "R", "U"},
+	// Environmental
+	"CR":  {"X", "H", "M", "L"},
+	"IR":  {"X", "H", "M", "L"},
+	"AR":  {"X", "H", "M", "L"},
+	"MAV": {"X", "N", "A", "L", "P"},
+	"MAC": {"X", "L", "H"},
+	"MPR": {"X", "N", "L", "H"},
+	"MUI": {"X", "N", "R"},
+	"MS":  {"X", "U", "C"},
+	"MC":  {"X", "H", "L", "N"},
+	"MI":  {"X", "H", "L", "N"},
+	"MA":  {"X", "H", "L", "N"},
+}
+
+var (
+	v3AttackVector       = map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}
+	v3AttackComplexity   = map[string]float64{"L": 0.77, "H": 0.44}
+	v3UserInteraction    = map[string]float64{"N": 0.85, "R": 0.62}
+	v3Impact             = map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	v3PrivilegesRequired = map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	// Privileges matter more when the scope changes
+	v3PrivilegesRequiredChanged = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+)
+
+// scoreV3 computes the base score as specified in CVSS v3.1 section 7.1
+func (v *Vector) scoreV3() float64 {
+	m := v.metrics
+	changed := m["S"] == "C"
+
+	iss := 1 - (1-v3Impact[m["C"]])*(1-v3Impact[m["I"]])*(1-v3Impact[m["A"]])
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0
+	}
+
+	pr := v3PrivilegesRequired[m["PR"]]
+	if changed {
+		pr = v3PrivilegesRequiredChanged[m["PR"]]
+	}
+	exploitability := 8.22 * v3AttackVector[m["AV"]] * v3AttackComplexity[m["AC"]] * pr * v3UserInteraction[m["UI"]]
+
+	score := impact + exploitability
+	if changed {
+		score *= 1.08
+	}
+	return v.roundUp(math.Min(score, 10))
+}
+
+// roundUp returns the smallest one-decimal number >= x. CVSS v3.1 defines it
+// on integers to avoid floating point errors such as 4.000000000000001
+// rounding up to 4.1.
+func (v *Vector) roundUp(x float64) float64 {
+	if v.Version == V30 {
+		return math.Ceil(x*10) / 10
+	}
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
@@ -0,0 +1,298 @@
+package cvss
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var v4Required = []string{"AV", "AC", "AT", "PR", "UI", "VC", "VI", "VA", "SC", "SI", "SA"}
+
+var v4Metrics = map[string][]string{
+	// Base
+	"AV": {"N", "A", "L", "P"},
+	"AC": {"L", "H"},
+	"AT": {"N", "P"},
+	"PR": {"N", "L", "H"},
+	"UI": {"N", "P", "A"},
+	"VC": {"H", "L", "N"},
+	"VI": {"H", "L", "N"},
+	"VA": {"H", "L", "N"},
+	"SC": {"H", "L", "N"},
+	"SI": {"H", "L", "N"},
+	"SA": {"H", "L", "N"},
+	// Threat
+	"E": {"X", "A", "P", "U"},
+	// Environmental
+	"CR":  {"X", "H", "M", "L"},
+	"IR":  {"X", "H", "M", "L"},
+	"AR":  {"X", "H", "M", "L"},
+	"MAV": {"X", "N", "A", "L", "P"},
+	"MAC": {"X", "L", "H"},
+	"MAT": {"X", "N", "P"},
+	"MPR": {"X", "N", "L", "H"},
+	"MUI": {"X", "N", "P", "A"},
+	"MVC": {"X", "H", "L", "N"},
+	"MVI": {"X", "H", "L", "N"},
+	"MVA": {"X", "H", "L", "N"},
+	"MSC": {"X", "H", "L", "N"},
+	"MSI": {"X", "S", "H", "L", "N"},
+	"MSA": {"X", "S", "H", "L", "N"},
+	// Supplemental, which does not affect the score
+	"S":  {"X", "N", "P"},
+	"AU": {"X", "N", "Y"},
+	"R":  {"X", "A", "U", "I"},
+	"V":  {"X", "D", "C"},
+	"RE": {"X", "L", "M", "H"},
+	"U":  {"X", "Clear", "Green", "Amber", "Red"},
+}
+
+// v4Levels are the severity distances of metric values from the most severe
+// value, in steps of 0.1
+var v4Levels = map[string]map[string]float64{
+	"AV": {"N": 0.0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0.0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0.0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0.0, "H": 0.1},
+	"AT": {"N": 0.0, "P": 0.1},
+	"VC": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0.0, "M": 0.1, "L": 0.2},
+}
+
+// v4Maxes are the highest severity vectors of each macro vector value. EQ3
+// and EQ6 are scored together, keyed by the EQ3 then the EQ6 value.
+var (
+	v4MaxesEQ1 = [][]string{
+		{"AV:N/PR:N/UI:N"},
+		{"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		{"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	}
+	v4MaxesEQ2 = [][]string{
+		{"AC:L/AT:N"},
+		{"AC:H/AT:N", "AC:L/AT:P"},
+	}
+	v4MaxesEQ3EQ6 = [][][]string{
+		{
+			{"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		},
+		{
+			{"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"},
+		},
+		{
+			nil,
+			{"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+		},
+	}
+	v4MaxesEQ4 = [][]string{
+		{"SC:H/SI:S/SA:S"},
+		{"SC:H/SI:H/SA:H"},
+		{"SC:L/SI:L/SA:L"},
+	}
+)
+
+// v4MaxSeverity is the severity distance, in steps of 0.1, between the
+// highest and lowest vectors of each macro vector value
+var (
+	v4MaxSeverityEQ1    = []float64{1, 4, 5}
+	v4MaxSeverityEQ2    = []float64{1, 2}
+	v4MaxSeverityEQ3EQ6 = [][]float64{{7, 6}, {8, 8}, {0, 10}}
+	v4MaxSeverityEQ4    = []float64{6, 5, 4}
+)
+
+// effective returns the value of a metric after environmental overrides,
+// with unset threat and security requirement metrics at their worst case
+func (v *Vector) effective(name string) string {
+	switch name {
+	case "E":
+		if e := v.metrics["E"]; e != "" && e != "X" {
+			return e
+		}
+		return "A"
+	case "CR", "IR", "AR":
+		if r := v.metrics[name]; r != "" && r != "X" {
+			return r
+		}
+		return "H"
+	}
+	if m := v.metrics["M"+name]; m != "" && m != "X" {
+		return m
+	}
+	return v.metrics[name]
+}
+
+// macroVector returns the EQ1-EQ6 equivalence classes of the vector
+func (v *Vector) macroVector() [6]int {
+	m := v.effective
+	var eq [6]int
+
+	switch {
+	case m("AV") == "N" && m("PR") == "N" && m("UI") == "N":
+		eq[0] = 0
+	case (m("AV") == "N" || m("PR") == "N" || m("UI") == "N") && m("AV") != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+
+	if m("AC") != "L" || m("AT") != "N" {
+		eq[1] = 1
+	}
+
+	switch {
+	case m("VC") == "H" && m("VI") == "H":
+		eq[2] = 0
+	case m("VC") == "H" || m("VI") == "H" || m("VA") == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+
+	switch {
+	case m("SI") == "S" || m("SA") == "S":
+		eq[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+
+	switch m("E") {
+	case "A":
+		eq[4] = 0
+	case "P":
+		eq[4] = 1
+	default:
+		eq[4] = 2
+	}
+
+	if !(m("CR") == "H" && m("VC") == "H" || m("IR") == "H" && m("VI") == "H" || m("AR") == "H" && m("VA") == "H") {
+		eq[5] = 1
+	}
+	return eq
+}
+
+func macroScore(eq [6]int) (float64, bool) {
+	score, ok := lookup[fmt.Sprintf("%d%d%d%d%d%d", eq[0], eq[1], eq[2], eq[3], eq[4], eq[5])]
+	return score, ok
+}
+
+// scoreV4 computes the score as the FIRST CVSS v4.0 calculator does: the
+// macro vector's score, lowered by how far the vector is from the highest
+// severity vector of its macro vector relative to the next lower one
+func (v *Vector) scoreV4() float64 {
+	m := v.effective
+	if m("VC") == "N" && m("VI") == "N" && m("VA") == "N" && m("SC") == "N" && m("SI") == "N" && m("SA") == "N" {
+		return 0
+	}
+
+	eq := v.macroVector()
+	value, ok := macroScore(eq)
+	if !ok {
+		return 0
+	}
+
+	lower := func(i int) [6]int {
+		next := eq
+		next[i]++
+		return next
+	}
+	nextEQ1, okEQ1 := macroScore(lower(0))
+	nextEQ2, okEQ2 := macroScore(lower(1))
+	nextEQ4, okEQ4 := macroScore(lower(3))
+
+	var nextEQ3EQ6 float64
+	var okEQ3EQ6 bool
+	switch {
+	case eq[2] == 0 && eq[5] == 0:
+		// Both 01 and 10 are lower; use the higher of the two
+		left, okLeft := macroScore(lower(5))
+		right, okRight := macroScore(lower(2))
+		if okLeft && left > right {
+			nextEQ3EQ6, okEQ3EQ6 = left, true
+		} else {
+			nextEQ3EQ6, okEQ3EQ6 = right, okRight
+		}
+	case eq[2] == 1 && eq[5] == 0:
+		nextEQ3EQ6, okEQ3EQ6 = macroScore(lower(5))
+	case eq[2] == 2:
+		// 21 is the lowest
+	default:
+		nextEQ3EQ6, okEQ3EQ6 = macroScore(lower(2))
+	}
+
+	dist := v.maxDistances(eq)
+
+	const step = 0.1
+	var sum float64
+	var n int
+	normalize := func(ok bool, next, distance, maxSeverity float64) {
+		if ok {
+			n++
+			sum += (value - next) * (distance / (maxSeverity * step))
+		}
+	}
+	normalize(okEQ1, nextEQ1, dist["AV"]+dist["PR"]+dist["UI"], v4MaxSeverityEQ1[eq[0]])
+	normalize(okEQ2, nextEQ2, dist["AC"]+dist["AT"], v4MaxSeverityEQ2[eq[1]])
+	normalize(okEQ3EQ6, nextEQ3EQ6, dist["VC"]+dist["VI"]+dist["VA"]+dist["CR"]+dist["IR"]+dist["AR"], v4MaxSeverityEQ3EQ6[eq[2]][eq[5]])
+	normalize(okEQ4, nextEQ4, dist["SC"]+dist["SI"]+dist["SA"], v4MaxSeverityEQ4[eq[3]])
+	// The exploit maturity distance is always 0, but EQ5 still counts
+	if _, ok := macroScore(lower(4)); ok {
+		n++
+	}
+
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	value = math.Max(0, math.Min(10, value))
+	// The epsilon keeps floating point errors from rounding x.x5 down
+	return math.Round((value+1e-6)*10) / 10
+}
+
+// maxDistances returns the severity distance of each metric from the first
+// highest severity vector of the macro vector that the vector is not more
+// severe than on any metric
+func (v *Vector) maxDistances(eq [6]int) map[string]float64 {
+	var dist map[string]float64
+	for _, eq1 := range v4MaxesEQ1[eq[0]] {
+		for _, eq2 := range v4MaxesEQ2[eq[1]] {
+			for _, eq3eq6 := range v4MaxesEQ3EQ6[eq[2]][eq[5]] {
+				for _, eq4 := range v4MaxesEQ4[eq[3]] {
+					var below bool
+					dist, below = v.distances(strings.Join([]string{eq1, eq2, eq3eq6, eq4}, "/"))
+					if below {
+						return dist
+					}
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// distances returns the severity distance of each metric from a highest
+// severity vector, and whether no metric is more severe than it
+func (v *Vector) distances(max string) (map[string]float64, bool) {
+	maxMetrics := make(map[string]string)
+	for _, part := range strings.Split(max, "/") {
+		name, value, _ := strings.Cut(part, ":")
+		maxMetrics[name] = value
+	}
+	below := true
+	dist := make(map[string]float64, len(v4Levels))
+	for name, levels := range v4Levels {
+		dist[name] = levels[v.effective(name)] - levels[maxMetrics[name]]
+		if dist[name] < 0 {
+			below = false
+		}
+	}
+	return dist, below
+}
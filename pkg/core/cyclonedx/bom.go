@@ -33,6 +33,17 @@ func NewBOM() *BOM {
 	}
 }
 
+// NewSBOM creates a new SBOM (Software Bill of Materials) for packages
+// resolved before a build, e.g. from lockfiles
+func NewSBOM() *BOM {
+	bom := NewBOM()
+	bom.SerialNumber = generateUUID()
+	bom.Metadata.Lifecycles = []Lifecycle{
+		{Phase: "pre-build"},
+	}
+	return bom
+}
+
 // NewMLBOM creates a new ML-BOM (Machine Learning Bill of Materials)
 func NewMLBOM() *BOM {
 	bom := NewBOM()
@@ -48,6 +48,17 @@ func TestNewMLBOM(t *testing.T) {
 	}
 }
 
+func TestNewSBOM(t *testing.T) {
+	bom := NewSBOM()
+
+	if !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+		t.Errorf("expected SerialNumber to start with 'urn:uuid:', got '%s'", bom.SerialNumber)
+	}
+	if len(bom.Metadata.Lifecycles) == 0 || bom.Metadata.Lifecycles[0].Phase != "pre-build" {
+		t.Errorf("expected phase 'pre-build', got %+v", bom.Metadata.Lifecycles)
+	}
+}
+
 func TestNewCBOM(t *testing.T) {
 	bom := NewCBOM()
 
@@ -464,3 +475,90 @@ func TestCipherFindingToComponent(t *testing.T) {
 		t.Errorf("expected line 42, got %d", c.Evidence.Occurrences[0].Line)
 	}
 }
+
+func TestGraph(t *testing.T) {
+	bom, err := FromJSON([]byte(`{"bomFormat": "CycloneDX", "specVersion": "1.6",
+		"metadata": {"component": {"bom-ref": "app", "name": "app"}},
+		"components": [
+			{"type": "library", "bom-ref": "express", "name": "express", "version": "4.18.2", "purl": "pkg:npm/express@4.18.2"},
+			{"type": "library", "bom-ref": "mkdirp", "name": "mkdirp", "version": "0.5.1", "purl": "pkg:npm/mkdirp@0.5.1"},
+			{"type": "library", "bom-ref": "body-parser", "name": "body-parser", "version": "1.20.1", "purl": "pkg:npm/body-parser@1.20.1"},
+			{"type": "library", "bom-ref": "qs", "name": "qs", "version": "6.11.0", "purl": "pkg:npm/qs@6.11.0"},
+			{"type": "library", "bom-ref": "minimist", "name": "minimist", "version": "0.0.8", "purl": "pkg:npm/minimist@0.0.8"},
+			{"type": "library", "bom-ref": "babel", "group": "@babel", "name": "core", "version": "7.0.0", "purl": "pkg:npm/%40babel/core@7.0.0"},
+			{"type": "library", "bom-ref": "yaml", "name": "PyYAML", "version": "6.0", "purl": "pkg:pypi/pyyaml@6.0"},
+			{"type": "library", "bom-ref": "x-net", "name": "net", "version": "v0.17.0", "purl": "pkg:golang/golang.org/x/net@v0.17.0"}
+		],
+		"dependencies": [
+			{"ref": "app", "dependsOn": ["express", "mkdirp", "babel", "yaml", "x-net"]},
+			{"ref": "express", "dependsOn": ["body-parser", "qs"]},
+			{"ref": "body-parser", "dependsOn": ["qs", "express"]},
+			{"ref": "qs", "dependsOn": ["minimist"]},
+			{"ref": "mkdirp", "dependsOn": ["minimist"]}
+		]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGraph(bom)
+	if !g.HasDependencies() {
+		t.Fatal("expected a dependency graph")
+	}
+
+	intro := g.Explain("minimist")
+	if intro.Direct || intro.Package != "minimist" || intro.Ecosystem != "npm" {
+		t.Errorf("intro = %+v", intro)
+	}
+	if got := strings.Join(intro.Shortest, " > "); got != "mkdirp@0.5.1 > minimist@0.0.8" {
+		t.Errorf("shortest = %q", got)
+	}
+	var paths []string
+	for _, p := range intro.Paths {
+		paths = append(paths, strings.Join(p, " > "))
+	}
+	want := []string{
+		"mkdirp@0.5.1 > minimist@0.0.8",
+		"express@4.18.2 > qs@6.11.0 > minimist@0.0.8",
+		"express@4.18.2 > body-parser@1.20.1 > qs@6.11.0 > minimist@0.0.8",
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Errorf("paths =\n%s\nwant\n%s", strings.Join(paths, "\n"), strings.Join(want, "\n"))
+	}
+	if direct := g.Explain("express"); !direct.Direct || len(direct.Paths) != 1 {
+		t.Errorf("express = %+v", direct)
+	}
+
+	for query, want := range map[string]string{
+		"pkg:npm/minimist@0.0.8":    "minimist",
+		"pkg:npm/minimist":          "minimist",
+		"pkg:npm/@babel/core@7.0.0": "babel",
+		"@babel/core":               "babel",
+		"pkg:npm/minimist@1.2.8":    "",
+		"qs@6.11.0":                 "qs",
+	} {
+		if got := strings.Join(g.Find(query), ","); got != want {
+			t.Errorf("Find(%q) = %q, want %q", query, got, want)
+		}
+	}
+
+	if got := g.Lookup("npm", "@babel/core", "7.0.0"); len(got) != 1 || got[0] != "babel" {
+		t.Errorf("Lookup(@babel/core) = %v", got)
+	}
+	if got := g.Lookup("Go", "golang.org/x/net", "0.17.0"); len(got) != 1 || got[0] != "x-net" {
+		t.Errorf("Lookup(golang.org/x/net) = %v", got)
+	}
+	if got := g.Lookup("PyPI", "PyYAML", "6.0"); len(got) != 1 || got[0] != "yaml" {
+		t.Errorf("Lookup(PyYAML) = %v", got)
+	}
+
+	for file, want := range map[string]string{
+		"node_modules/express/node_modules/qs/lib/index.js":             "qs",
+		"/src/app/node_modules/@babel/core/lib/index.js":                "babel",
+		"vendor/golang.org/x/net/http2/frame.go":                        "x-net",
+		"venv/lib/python3.12/site-packages/PyYAML-6.0.dist-info/RECORD": "yaml",
+		"src/index.js": "",
+	} {
+		if got := strings.Join(g.LookupFile(file), ","); got != want {
+			t.Errorf("LookupFile(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package cyclonedx
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// MaxPaths caps the introducing paths enumerated for a component. Shared
+// dependencies make the number of paths grow exponentially; the shortest
+// path is always found.
+const MaxPaths = 20
+
+// Graph is a BOM's dependency graph, used to explain how a component is
+// pulled into the project
+type Graph struct {
+	components map[string]Component // by bom-ref
+	order      []string             // bom-refs in BOM order
+	edges      map[string][]string
+	parents    map[string][]string
+	direct     []string
+	isDirect   map[string]bool
+	index      map[string][]string // ecosystem|name|version -> bom-refs
+}
+
+// Introduction explains how a component is pulled into the project. Paths
+// start at a direct dependency and end at the component, as name@version.
+type Introduction struct {
+	Ref       string     `json:"ref"`
+	Package   string     `json:"package"`
+	Version   string     `json:"version"`
+	Ecosystem string     `json:"ecosystem"`
+	Purl      string     `json:"purl,omitempty"`
+	Scope     string     `json:"scope,omitempty"`
+	Direct    bool       `json:"direct"`
+	Shortest  []string   `json:"shortest_path,omitempty"`
+	Paths     [][]string `json:"paths,omitempty"`
+	Truncated bool       `json:"truncated,omitempty"` // More than MaxPaths paths exist
+}
+
+// NewGraph builds the dependency graph of a BOM. The project's direct
+// dependencies are those of the metadata component, or every component no
+// other component depends on when the BOM has no project node in its graph.
+func NewGraph(b *BOM) *Graph {
+	g := &Graph{
+		components: make(map[string]Component),
+		edges:      make(map[string][]string, len(b.Dependencies)),
+		parents:    make(map[string][]string),
+		isDirect:   make(map[string]bool),
+		index:      make(map[string][]string),
+	}
+	var add func(components []Component)
+	add = func(components []Component) {
+		for _, c := range components {
+			if c.BOMRef != "" {
+				if _, dup := g.components[c.BOMRef]; !dup {
+					g.order = append(g.order, c.BOMRef)
+				}
+				g.components[c.BOMRef] = c
+				eco, name := componentKey(c)
+				key := indexKey(eco, name, c.Version)
+				g.index[key] = append(g.index[key], c.BOMRef)
+			}
+			add(c.Components)
+		}
+	}
+	add(b.Components)
+
+	dependedOn := make(map[string]bool)
+	for _, dep := range b.Dependencies {
+		g.edges[dep.Ref] = append(g.edges[dep.Ref], dep.DependsOn...)
+		for _, ref := range dep.DependsOn {
+			g.parents[ref] = append(g.parents[ref], dep.Ref)
+			dependedOn[ref] = true
+		}
+	}
+
+	if b.Metadata != nil && b.Metadata.Component != nil && len(g.edges[b.Metadata.Component.BOMRef]) > 0 {
+		g.direct = g.edges[b.Metadata.Component.BOMRef]
+	} else {
+		for _, dep := range b.Dependencies {
+			if _, ok := g.components[dep.Ref]; ok && !dependedOn[dep.Ref] {
+				g.direct = append(g.direct, dep.Ref)
+			}
+		}
+	}
+	for _, ref := range g.direct {
+		g.isDirect[ref] = true
+	}
+	return g
+}
+
+// HasDependencies reports whether the BOM carries a dependency graph
+func (g *Graph) HasDependencies() bool {
+	return len(g.direct) > 0
+}
+
+// Component returns a component by bom-ref
+func (g *Graph) Component(ref string) (Component, bool) {
+	c, ok := g.components[ref]
+	return c, ok
+}
+
+// Label returns a component as name@version
+func (g *Graph) Label(ref string) string {
+	c, ok := g.components[ref]
+	if !ok {
+		return ref
+	}
+	if c.Version == "" {
+		return PackageName(c)
+	}
+	return PackageName(c) + "@" + c.Version
+}
+
+// Explain returns how a component is pulled into the project
+func (g *Graph) Explain(ref string) Introduction {
+	c := g.components[ref]
+	intro := Introduction{
+		Ref:       ref,
+		Package:   PackageName(c),
+		Version:   c.Version,
+		Ecosystem: purlType(c.Purl),
+		Purl:      c.Purl,
+		Scope:     c.Scope,
+		Direct:    g.isDirect[ref],
+	}
+	intro.Shortest, intro.Paths, intro.Truncated = g.paths(ref)
+	return intro
+}
+
+// Paths returns the shortest and all paths pulling in any of the given
+// components, which are usually the same package at different places in
+// the graph
+func (g *Graph) Paths(refs ...string) ([]string, [][]string) {
+	var shortest []string
+	var all [][]string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		s, paths, _ := g.paths(ref)
+		if s != nil && (shortest == nil || len(s) < len(shortest)) {
+			shortest = s
+		}
+		for _, p := range paths {
+			key := strings.Join(p, "\x00")
+			if !seen[key] {
+				seen[key] = true
+				all = append(all, p)
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return len(all[i]) < len(all[j]) })
+	if len(all) > MaxPaths {
+		all = all[:MaxPaths]
+	}
+	return shortest, all
+}
+
+// paths finds the shortest path to a component breadth-first, then
+// enumerates simple paths depth-first through the components that can
+// reach it, up to MaxPaths
+func (g *Graph) paths(target string) ([]string, [][]string, bool) {
+	reaches := map[string]bool{target: true}
+	queue := []string{target}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for _, parent := range g.parents[ref] {
+			if !reaches[parent] {
+				reaches[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	prev := make(map[string]string)
+	visited := make(map[string]bool)
+	queue = queue[:0]
+	for _, ref := range g.direct {
+		if reaches[ref] && !visited[ref] {
+			visited[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+	var shortest []string
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if ref == target {
+			for at := ref; at != ""; at = prev[at] {
+				shortest = append([]string{g.Label(at)}, shortest...)
+			}
+			break
+		}
+		for _, next := range g.edges[ref] {
+			if reaches[next] && !visited[next] {
+				visited[next] = true
+				prev[next] = ref
+				queue = append(queue, next)
+			}
+		}
+	}
+	if shortest == nil {
+		return nil, nil, false
+	}
+
+	var all [][]string
+	truncated := false
+	onPath := make(map[string]bool)
+	var walk func(trail []string)
+	walk = func(trail []string) {
+		if truncated {
+			return
+		}
+		last := trail[len(trail)-1]
+		if last == target {
+			if len(all) == MaxPaths {
+				truncated = true
+				return
+			}
+			labels := make([]string, len(trail))
+			for i, ref := range trail {
+				labels[i] = g.Label(ref)
+			}
+			all = append(all, labels)
+			return
+		}
+		onPath[last] = true
+		for _, next := range g.edges[last] {
+			if reaches[next] && !onPath[next] {
+				walk(append(trail[:len(trail):len(trail)], next))
+			}
+		}
+		onPath[last] = false
+	}
+	seen := make(map[string]bool)
+	for _, ref := range g.direct {
+		if reaches[ref] && !seen[ref] {
+			seen[ref] = true
+			walk([]string{ref})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return len(all[i]) < len(all[j]) })
+	return shortest, all, truncated
+}
+
+// Find returns the components matching a query: a package URL, with or
+// without a version, a package name or name@version
+func (g *Graph) Find(query string) []string {
+	query = strings.TrimSpace(query)
+	var match func(c Component) bool
+	if strings.HasPrefix(query, "pkg:") {
+		want := normalizePurl(query)
+		versioned := stripPurlVersion(want) != want
+		match = func(c Component) bool {
+			have := normalizePurl(c.Purl)
+			if !versioned {
+				have = stripPurlVersion(have)
+			}
+			return have == want
+		}
+	} else {
+		name, version := query, ""
+		if i := strings.LastIndex(query, "@"); i > 0 {
+			name, version = query[:i], query[i+1:]
+		}
+		match = func(c Component) bool {
+			if version != "" && strings.TrimPrefix(c.Version, "v") != strings.TrimPrefix(version, "v") {
+				return false
+			}
+			return strings.EqualFold(PackageName(c), name) || strings.EqualFold(c.Name, name)
+		}
+	}
+
+	var refs []string
+	for _, ref := range g.order {
+		if match(g.components[ref]) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Lookup returns the components of a package version, with the ecosystem
+// and name as OSV reports them (e.g. "npm", "@babel/core")
+func (g *Graph) Lookup(ecosystem, name, version string) []string {
+	return g.index[indexKey(versions.ParseEcosystem(ecosystem), name, version)]
+}
+
+// LookupFile returns the components that installed a file, from its
+// node_modules, vendor or site-packages directory. Files outside installed
+// packages give nothing.
+func (g *Graph) LookupFile(file string) []string {
+	file = "/" + strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, "\\", "/")), "/")
+
+	var eco versions.Ecosystem
+	var name string
+	if rest, ok := installedUnder(file, "node_modules"); ok {
+		parts := strings.SplitN(rest, "/", 3)
+		name = parts[0]
+		if strings.HasPrefix(name, "@") && len(parts) > 1 {
+			name += "/" + parts[1]
+		}
+		eco = versions.NPM
+	} else if rest, ok := installedUnder(file, "site-packages", "dist-packages"); ok {
+		// Either the import package or the .dist-info directory, which is
+		// named after the distribution and version
+		name, _, _ = strings.Cut(rest, "/")
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".dist-info"), ".egg-info")
+		if loc := distInfoVersion.FindStringIndex(name); loc != nil {
+			name = name[:loc[0]]
+		}
+		name = strings.TrimSuffix(name, ".py")
+		eco = versions.PyPI
+	} else if rest, ok := installedUnder(file, "vendor"); ok {
+		// Go module paths have a variable number of elements; the longest
+		// vendored module containing the file wins
+		for _, ref := range g.order {
+			c := g.components[ref]
+			if versions.ParseEcosystem(purlType(c.Purl)) != versions.Go {
+				continue
+			}
+			if module := PackageName(c); strings.HasPrefix(rest, module+"/") && len(module) > len(name) {
+				name = module
+			}
+		}
+		eco = versions.Go
+	}
+	if name == "" {
+		return nil
+	}
+
+	name = normalizeName(eco, name)
+	var refs []string
+	for _, ref := range g.order {
+		if ceco, cname := componentKey(g.components[ref]); ceco == eco && cname == name {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+var distInfoVersion = regexp.MustCompile(`-[0-9][^/]*$`)
+
+// installedUnder returns the part of a path below the innermost of the
+// given install directories
+func installedUnder(file string, dirs ...string) (string, bool) {
+	best := -1
+	var rest string
+	for _, dir := range dirs {
+		if i := strings.LastIndex(file, "/"+dir+"/"); i > best {
+			best = i
+			rest = file[i+len(dir)+2:]
+		}
+	}
+	return rest, best >= 0
+}
+
+// PackageName returns a component's package name as OSV and package
+// managers spell it: "@scope/name" for npm, "group:artifact" for Maven and
+// the full module path for Go
+func PackageName(c Component) string {
+	parts := strings.Split(stripPurlVersion(normalizePurl(strings.TrimPrefix(c.Purl, "pkg:"))), "/")
+	if c.Purl == "" || len(parts) < 2 {
+		if c.Group != "" {
+			return c.Group + "/" + c.Name
+		}
+		return c.Name
+	}
+	namespace := strings.Join(parts[1:len(parts)-1], "/")
+	name := parts[len(parts)-1]
+	switch {
+	case namespace == "":
+		return name
+	case parts[0] == "maven":
+		return namespace + ":" + name
+	}
+	return namespace + "/" + name
+}
+
+// componentKey returns the ecosystem and normalized name a component is
+// indexed under
+func componentKey(c Component) (versions.Ecosystem, string) {
+	eco := versions.ParseEcosystem(purlType(c.Purl))
+	return eco, normalizeName(eco, PackageName(c))
+}
+
+func indexKey(eco versions.Ecosystem, name, version string) string {
+	return string(eco) + "|" + normalizeName(eco, name) + "|" + strings.TrimPrefix(version, "v")
+}
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName folds the spellings an ecosystem treats as equal
+func normalizeName(eco versions.Ecosystem, name string) string {
+	switch eco {
+	case versions.PyPI:
+		return strings.ToLower(pypiSeparators.ReplaceAllString(name, "-"))
+	case versions.NuGet, versions.Packagist:
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// purlType returns the type of a package URL, e.g. "npm"
+func purlType(purl string) string {
+	t, _, _ := strings.Cut(strings.TrimPrefix(purl, "pkg:"), "/")
+	if t == purl {
+		return ""
+	}
+	return t
+}
+
+// normalizePurl unescapes a package URL and drops its qualifiers and
+// subpath
+func normalizePurl(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+	if unescaped, err := url.PathUnescape(purl); err == nil {
+		purl = unescaped
+	}
+	return purl
+}
+
+// stripPurlVersion drops the version of a normalized package URL
+func stripPurlVersion(purl string) string {
+	slash := strings.LastIndex(purl, "/")
+	if i := strings.LastIndex(purl, "@"); i > slash {
+		return purl[:i]
+	}
+	return purl
+}
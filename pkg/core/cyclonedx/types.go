@@ -4,6 +4,7 @@ package cyclonedx
 
 import (
 	"encoding/json"
+	"os"
 	"time"
 )
 
@@ -394,6 +395,15 @@ func FromJSON(data []byte) (*BOM, error) {
 	return &bom, nil
 }
 
+// ReadFile reads a BOM from a JSON file
+func ReadFile(path string) (*BOM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
 // TimestampNow returns the current time in ISO 8601 format
 func TimestampNow() string {
 	return time.Now().UTC().Format(time.RFC3339)
@@ -0,0 +1,182 @@
+// Package exploitdb provides an offline store of exploit intelligence: EPSS
+// scores (https://www.first.org/epss) and the CISA Known Exploited
+// Vulnerabilities catalog, so vulnerabilities can be prioritized by how
+// likely they are to be exploited without network access
+package exploitdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS epss (
+	cve        TEXT PRIMARY KEY,
+	score      REAL NOT NULL,
+	percentile REAL NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS kev (
+	cve        TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	date_added TEXT NOT NULL,
+	due_date   TEXT NOT NULL,
+	ransomware INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// DB is a local store of EPSS scores and KEV entries keyed by CVE
+type DB struct {
+	db   *sql.DB
+	path string
+}
+
+// Intel is the exploit intelligence known for a CVE
+type Intel struct {
+	CVE            string    `json:"cve"`
+	EPSS           float64   `json:"epss"`            // Probability of exploitation activity in the next 30 days
+	EPSSPercentile float64   `json:"epss_percentile"` // Share of CVEs with a lower or equal score
+	HasEPSS        bool      `json:"has_epss"`
+	KEV            *KEVEntry `json:"kev,omitempty"`
+}
+
+// KEVEntry is an entry of the CISA Known Exploited Vulnerabilities catalog
+type KEVEntry struct {
+	Name       string `json:"name"`
+	DateAdded  string `json:"date_added"`
+	DueDate    string `json:"due_date"`
+	Ransomware bool   `json:"ransomware"` // Known use in ransomware campaigns
+}
+
+// Stats describes the contents of a database
+type Stats struct {
+	EPSSScores     int       `json:"epss_scores"`
+	EPSSScoreDate  string    `json:"epss_score_date,omitempty"`
+	EPSSModel      string    `json:"epss_model,omitempty"`
+	EPSSLastImport time.Time `json:"epss_last_import,omitempty"`
+	KEVEntries     int       `json:"kev_entries"`
+	KEVVersion     string    `json:"kev_version,omitempty"`
+	KEVLastImport  time.Time `json:"kev_last_import,omitempty"`
+}
+
+// DefaultPath returns the database location under a Zero home directory
+func DefaultPath(zeroHome string) string {
+	return filepath.Join(zeroHome, "feeds", "exploits.db")
+}
+
+// Exists returns true if a database has been imported at path
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// Open opens the database at path, creating it if needed
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating db directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite only supports one writer
+	db.SetMaxIdleConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+
+	return &DB{db: db, path: path}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Path returns the database file path
+func (d *DB) Path() string {
+	return d.path
+}
+
+// Stats returns record counts and import metadata
+func (d *DB) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{}
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM epss`).Scan(&stats.EPSSScores); err != nil {
+		return nil, fmt.Errorf("counting EPSS scores: %w", err)
+	}
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM kev`).Scan(&stats.KEVEntries); err != nil {
+		return nil, fmt.Errorf("counting KEV entries: %w", err)
+	}
+
+	stats.EPSSScoreDate, _ = d.metadata(ctx, "epss_score_date")
+	stats.EPSSModel, _ = d.metadata(ctx, "epss_model_version")
+	if v, err := d.metadata(ctx, "epss_last_import"); err == nil {
+		stats.EPSSLastImport, _ = time.Parse(time.RFC3339, v)
+	}
+	stats.KEVVersion, _ = d.metadata(ctx, "kev_catalog_version")
+	if v, err := d.metadata(ctx, "kev_last_import"); err == nil {
+		stats.KEVLastImport, _ = time.Parse(time.RFC3339, v)
+	}
+	return stats, nil
+}
+
+// Lookup returns the EPSS score and KEV entry of a CVE, or nil if the
+// database has neither
+func (d *DB) Lookup(ctx context.Context, cve string) (*Intel, error) {
+	cve = strings.ToUpper(strings.TrimSpace(cve))
+	intel := &Intel{CVE: cve}
+
+	err := d.db.QueryRowContext(ctx, `SELECT score, percentile FROM epss WHERE cve = ?`, cve).
+		Scan(&intel.EPSS, &intel.EPSSPercentile)
+	switch {
+	case err == nil:
+		intel.HasEPSS = true
+	case err != sql.ErrNoRows:
+		return nil, fmt.Errorf("reading EPSS score: %w", err)
+	}
+
+	var kev KEVEntry
+	err = d.db.QueryRowContext(ctx, `SELECT name, date_added, due_date, ransomware FROM kev WHERE cve = ?`, cve).
+		Scan(&kev.Name, &kev.DateAdded, &kev.DueDate, &kev.Ransomware)
+	switch {
+	case err == nil:
+		intel.KEV = &kev
+	case err != sql.ErrNoRows:
+		return nil, fmt.Errorf("reading KEV entry: %w", err)
+	}
+
+	if !intel.HasEPSS && intel.KEV == nil {
+		return nil, nil
+	}
+	return intel, nil
+}
+
+func (d *DB) metadata(ctx context.Context, key string) (string, error) {
+	var value string
+	err := d.db.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, key).Scan(&value)
+	return value, err
+}
+
+func (d *DB) setMetadata(ctx context.Context, tx *sql.Tx, values map[string]string) error {
+	for key, value := range values {
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO metadata (key, value) VALUES (?, ?)`, key, value); err != nil {
+			return fmt.Errorf("saving import metadata: %w", err)
+		}
+	}
+	return nil
+}
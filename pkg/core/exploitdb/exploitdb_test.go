@@ -0,0 +1,135 @@
+package exploitdb
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const epssCSV = `#model_version:v2025.03.14,score_date:2026-10-15T12:55:00Z
+cve,epss,percentile
+CVE-2021-44228,0.94358,0.99960
+CVE-2023-32681,0.00062,0.27364
+not-a-cve,0.1,0.5
+CVE-2024-0001,oops,0.1
+`
+
+const kevJSON = `{
+  "title": "CISA Catalog of Known Exploited Vulnerabilities",
+  "catalogVersion": "2026.10.14",
+  "count": 2,
+  "vulnerabilities": [
+    {"cveID": "CVE-2021-44228", "vulnerabilityName": "Apache Log4j2 Remote Code Execution Vulnerability",
+     "dateAdded": "2021-12-10", "dueDate": "2021-12-24", "knownRansomwareCampaignUse": "Known"},
+    {"cveID": "CVE-2022-22965", "vulnerabilityName": "Spring Framework Remote Code Execution Vulnerability",
+     "dateAdded": "2022-04-04", "dueDate": "2022-04-25", "knownRansomwareCampaignUse": "Unknown"}
+  ]
+}`
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(DefaultPath(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportEPSS(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	path := filepath.Join(t.TempDir(), "epss_scores-current.csv.gz")
+	writeGzip(t, path, epssCSV)
+
+	stats, err := db.ImportEPSS(ctx, path)
+	if err != nil {
+		t.Fatalf("ImportEPSS() error = %v", err)
+	}
+	if stats.Imported != 2 || stats.Invalid != 2 || stats.Version != "2026-10-15T12:55:00Z" {
+		t.Errorf("ImportEPSS() stats = %+v", stats)
+	}
+
+	intel, err := db.Lookup(ctx, "cve-2021-44228")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intel == nil || !intel.HasEPSS || intel.EPSS != 0.94358 || intel.EPSSPercentile != 0.9996 {
+		t.Errorf("Lookup() = %+v", intel)
+	}
+
+	// Imports replace the previous scores
+	plain := filepath.Join(t.TempDir(), "epss.csv")
+	if err := os.WriteFile(plain, []byte("cve,epss,percentile\nCVE-2023-32681,0.5,0.9\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ImportEPSS(ctx, plain); err != nil {
+		t.Fatal(err)
+	}
+	if intel, _ := db.Lookup(ctx, "CVE-2021-44228"); intel != nil {
+		t.Errorf("Lookup() after re-import = %+v, want nil", intel)
+	}
+
+	if _, err := db.ImportEPSS(ctx, writeTemp(t, "cve,score\n")); err == nil {
+		t.Error("ImportEPSS() of a file without EPSS columns succeeded")
+	}
+}
+
+func TestImportKEV(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	stats, err := db.ImportKEV(ctx, []byte(kevJSON))
+	if err != nil {
+		t.Fatalf("ImportKEV() error = %v", err)
+	}
+	if stats.Imported != 2 || stats.Version != "2026.10.14" {
+		t.Errorf("ImportKEV() stats = %+v", stats)
+	}
+
+	intel, err := db.Lookup(ctx, "CVE-2021-44228")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intel == nil || intel.KEV == nil || !intel.KEV.Ransomware || intel.KEV.DateAdded != "2021-12-10" || intel.HasEPSS {
+		t.Errorf("Lookup() = %+v", intel)
+	}
+	if intel, _ := db.Lookup(ctx, "CVE-2000-0001"); intel != nil {
+		t.Errorf("Lookup() of an unknown CVE = %+v, want nil", intel)
+	}
+
+	dbStats, err := db.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbStats.KEVEntries != 2 || dbStats.KEVVersion != "2026.10.14" || dbStats.KEVLastImport.IsZero() {
+		t.Errorf("Stats() = %+v", dbStats)
+	}
+}
+
+func writeTemp(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,193 @@
+package exploitdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportStats summarizes an import
+type ImportStats struct {
+	Imported int    `json:"imported"`
+	Invalid  int    `json:"invalid"`           // Rows or entries that could not be parsed
+	Version  string `json:"version,omitempty"` // EPSS score date or KEV catalog version
+}
+
+// ImportEPSS replaces the EPSS scores with a daily scores file as
+// published at https://epss.cyentia.com/epss_scores-current.csv.gz, either
+// gzipped or plain CSV
+func (d *DB) ImportEPSS(ctx context.Context, from string) (*ImportStats, error) {
+	f, err := os.Open(from)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.EqualFold(filepath.Ext(from), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", from, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	// The first line holds the model version and score date:
+	// #model_version:v2025.03.14,score_date:2025-10-15T12:55:00Z
+	br := bufio.NewReader(r)
+	meta := make(map[string]string)
+	if first, err := br.Peek(1); err == nil && first[0] == '#' {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("reading %s: %w", from, err)
+		}
+		for _, field := range strings.Split(strings.TrimSpace(line[1:]), ",") {
+			if k, v, ok := strings.Cut(field, ":"); ok {
+				meta[k] = v
+			}
+		}
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", from, err)
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.TrimSpace(name)] = i
+	}
+	cveCol, ok1 := cols["cve"]
+	scoreCol, ok2 := cols["epss"]
+	pctCol, ok3 := cols["percentile"]
+	if !ok1 || !ok2 || !ok3 {
+		return nil, fmt.Errorf("%s is not an EPSS scores file: expected cve, epss and percentile columns", from)
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM epss`); err != nil {
+		return nil, fmt.Errorf("clearing EPSS scores: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR REPLACE INTO epss (cve, score, percentile) VALUES (?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	stats := &ImportStats{Version: meta["score_date"]}
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil || len(row) <= max(cveCol, scoreCol, pctCol) {
+			stats.Invalid++
+			continue
+		}
+		score, err1 := strconv.ParseFloat(row[scoreCol], 64)
+		pct, err2 := strconv.ParseFloat(row[pctCol], 64)
+		cve := strings.ToUpper(strings.TrimSpace(row[cveCol]))
+		if err1 != nil || err2 != nil || !strings.HasPrefix(cve, "CVE-") {
+			stats.Invalid++
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, cve, score, pct); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", cve, err)
+		}
+		stats.Imported++
+	}
+	if stats.Imported == 0 {
+		return nil, fmt.Errorf("%s contains no EPSS scores", from)
+	}
+
+	err = d.setMetadata(ctx, tx, map[string]string{
+		"epss_last_import":   time.Now().UTC().Format(time.RFC3339),
+		"epss_score_date":    meta["score_date"],
+		"epss_model_version": meta["model_version"],
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing import: %w", err)
+	}
+	return stats, nil
+}
+
+// kevCatalog is the CISA KEV catalog JSON format, as published at
+// https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json
+type kevCatalog struct {
+	CatalogVersion  string `json:"catalogVersion"`
+	Vulnerabilities []struct {
+		CVEID                      string `json:"cveID"`
+		VulnerabilityName          string `json:"vulnerabilityName"`
+		DateAdded                  string `json:"dateAdded"`
+		DueDate                    string `json:"dueDate"`
+		KnownRansomwareCampaignUse string `json:"knownRansomwareCampaignUse"`
+	} `json:"vulnerabilities"`
+}
+
+// ImportKEV replaces the KEV entries with a CISA KEV catalog
+func (d *DB) ImportKEV(ctx context.Context, data []byte) (*ImportStats, error) {
+	var catalog kevCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("parsing KEV catalog: %w", err)
+	}
+	if len(catalog.Vulnerabilities) == 0 {
+		return nil, fmt.Errorf("KEV catalog contains no vulnerabilities")
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kev`); err != nil {
+		return nil, fmt.Errorf("clearing KEV entries: %w", err)
+	}
+
+	stats := &ImportStats{Version: catalog.CatalogVersion}
+	for _, v := range catalog.Vulnerabilities {
+		cve := strings.ToUpper(strings.TrimSpace(v.CVEID))
+		if !strings.HasPrefix(cve, "CVE-") {
+			stats.Invalid++
+			continue
+		}
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO kev (cve, name, date_added, due_date, ransomware) VALUES (?, ?, ?, ?, ?)`,
+			cve, v.VulnerabilityName, v.DateAdded, v.DueDate, strings.EqualFold(v.KnownRansomwareCampaignUse, "Known"))
+		if err != nil {
+			return nil, fmt.Errorf("writing %s: %w", cve, err)
+		}
+		stats.Imported++
+	}
+
+	err = d.setMetadata(ctx, tx, map[string]string{
+		"kev_last_import":     time.Now().UTC().Format(time.RFC3339),
+		"kev_catalog_version": catalog.CatalogVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing import: %w", err)
+	}
+	return stats, nil
+}
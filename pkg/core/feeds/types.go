@@ -55,8 +55,11 @@ type FeedType string
 const (
 	FeedSemgrepRules FeedType = "semgrep-rules"
 	FeedRAGPatterns  FeedType = "rag-patterns"
-	// Note: Vulnerability data is queried LIVE via OSV.dev API during scans
-	// Pre-approved URL: https://api.osv.dev/v1/query
+	// Note: Vulnerability data comes from an OSV data dump imported with
+	// "zero feeds osv" (see pkg/core/osvdb), falling back to querying the
+	// OSV.dev API during scans. Pre-approved URL: https://api.osv.dev/v1/query
+	// EPSS scores and the CISA KEV catalog are imported the same way with
+	// "zero feeds epss" and "zero feeds kev" (see pkg/core/exploitdb).
 )
 
 // FeedConfig configures a single feed
@@ -0,0 +1,272 @@
+// Package forge provides a common interface over git hosting services
+// (GitHub, GitLab, Gitea) for repository listings, collaborators and PR data
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/credentials"
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// Kind identifies a forge implementation
+type Kind string
+
+const (
+	KindGitHub Kind = "github"
+	KindGitLab Kind = "gitlab"
+	KindGitea  Kind = "gitea"
+)
+
+// Forge is a git hosting service. Repository names are full paths as used in
+// the forge's web URLs ("owner/repo", or "group/subgroup/repo" on GitLab).
+type Forge interface {
+	// Kind returns the forge type
+	Kind() Kind
+
+	// BaseURL returns the web root of the forge (e.g., https://gitlab.example.com)
+	BaseURL() string
+
+	// HasToken returns true if an API token is configured
+	HasToken() bool
+
+	// CloneURL returns the HTTPS clone URL for a repository
+	CloneURL(fullName string) string
+
+	// ListOrgRepos returns repositories for an organization, group or user
+	ListOrgRepos(ctx context.Context, org string, limit int) ([]github.Repository, error)
+
+	// CountMergedPullRequests returns the number of merged pull/merge requests
+	CountMergedPullRequests(ctx context.Context, fullName string) (int, error)
+
+	// ListMergedPullRequests returns merged pull/merge requests, most recent first
+	ListMergedPullRequests(ctx context.Context, fullName string, opts PullRequestOptions) ([]PullRequest, error)
+
+	// ListCollaborators returns users with access to a repository
+	ListCollaborators(ctx context.Context, fullName string) ([]github.Collaborator, error)
+}
+
+// PullRequestOptions controls pull request listing
+type PullRequestOptions struct {
+	Limit     int       // Max PRs to return (default: 100)
+	Since     time.Time // Skip PRs created before this time
+	WithStats bool      // Fetch additions/deletions (extra request per PR on some forges)
+}
+
+// PullRequest is a merged pull request (GitHub, Gitea) or merge request (GitLab)
+type PullRequest struct {
+	Number    int             `json:"number"`
+	Title     string          `json:"title"`
+	Author    string          `json:"author"`
+	CreatedAt time.Time       `json:"created_at"`
+	MergedAt  time.Time       `json:"merged_at"`
+	Additions int             `json:"additions"`
+	Deletions int             `json:"deletions"`
+	Reviews   []github.Review `json:"reviews"`
+}
+
+// Review states normalized across forges (GitHub naming)
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
+// ReviewData converts pull requests to the format used for ownership analysis
+func ReviewData(prs []PullRequest) []github.PRReviewData {
+	result := make([]github.PRReviewData, 0, len(prs))
+	for _, pr := range prs {
+		result = append(result, github.PRReviewData{
+			PRNumber: pr.Number,
+			Title:    pr.Title,
+			Author:   pr.Author,
+			MergedAt: pr.MergedAt,
+			Reviews:  pr.Reviews,
+		})
+	}
+	return result
+}
+
+// New creates a forge client. baseURL is the web root; empty uses the public
+// instance (github.com, gitlab.com). Gitea has no public default.
+func New(kind Kind, baseURL, token string) (Forge, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	switch kind {
+	case KindGitHub, "":
+		return newGitHub(baseURL, token)
+	case KindGitLab:
+		return newGitLab(baseURL, token), nil
+	case KindGitea:
+		if baseURL == "" {
+			return nil, fmt.Errorf("gitea requires a base URL")
+		}
+		return newGitea(baseURL, token), nil
+	default:
+		return nil, fmt.Errorf("unknown forge type: %s (valid: github, gitlab, gitea)", kind)
+	}
+}
+
+// Open creates a forge client using the configured token for the forge type
+func Open(kind Kind, baseURL string) (Forge, error) {
+	return New(kind, baseURL, defaultToken(kind))
+}
+
+// SSHURL returns the scp-style SSH clone URL of a repository
+// (git@host:owner/repo.git), for cloning with the user's SSH keys when
+// HTTPS needs a token
+func SSHURL(f Forge, fullName string) string {
+	u, err := url.Parse(f.BaseURL())
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return fmt.Sprintf("git@%s:%s.git", u.Hostname(), fullName)
+}
+
+// ============================================================================
+// Host Detection
+// ============================================================================
+
+var (
+	hostsMu sync.RWMutex
+	hosts   = map[string]config.ForgeHost{}
+)
+
+// SetHosts registers self-hosted forge instances from config
+func SetHosts(list []config.ForgeHost) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	hosts = make(map[string]config.ForgeHost, len(list))
+	for _, h := range list {
+		hosts[strings.ToLower(h.Host)] = h
+	}
+}
+
+// Detect returns the forge type for a host, or "" if unknown
+func Detect(host string) Kind {
+	host = strings.ToLower(host)
+
+	hostsMu.RLock()
+	h, ok := hosts[host]
+	hostsMu.RUnlock()
+	if ok {
+		return Kind(strings.ToLower(h.Type))
+	}
+
+	switch {
+	case host == "github.com":
+		return KindGitHub
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return KindGitLab
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return KindGitea
+	default:
+		return ""
+	}
+}
+
+// ParseRemote extracts the host and full repository path from a git remote URL.
+// Supports https://, ssh:// and scp-style (git@host:path) remotes.
+func ParseRemote(remote string) (host, fullName string, ok bool) {
+	remote = strings.TrimSpace(remote)
+
+	var path string
+	if !strings.Contains(remote, "://") {
+		// scp-style: git@host:owner/repo.git
+		at := strings.Index(remote, "@")
+		colon := strings.Index(remote, ":")
+		if colon <= at+1 {
+			return "", "", false
+		}
+		host = remote[at+1 : colon]
+		path = remote[colon+1:]
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", false
+		}
+		host = u.Hostname()
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", false
+	}
+	return strings.ToLower(host), path, true
+}
+
+// ForRemote returns a forge client and repository path for a git remote URL
+func ForRemote(remote string) (Forge, string, error) {
+	host, fullName, ok := ParseRemote(remote)
+	if !ok {
+		return nil, "", fmt.Errorf("unrecognized git remote: %s", remote)
+	}
+
+	kind := Detect(host)
+	if kind == "" {
+		return nil, "", fmt.Errorf("unknown forge for host %s (add it to settings.forges in config)", host)
+	}
+
+	baseURL := "https://" + host
+	token := ""
+
+	hostsMu.RLock()
+	h, configured := hosts[host]
+	hostsMu.RUnlock()
+	if configured {
+		if h.URL != "" {
+			baseURL = h.URL
+		}
+		if h.TokenEnv != "" {
+			token = os.Getenv(h.TokenEnv)
+		}
+	}
+	if token == "" {
+		token = defaultToken(kind)
+	}
+
+	// Public instances use their canonical API hosts
+	if host == "github.com" || host == "gitlab.com" {
+		baseURL = ""
+	}
+
+	f, err := New(kind, baseURL, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return f, fullName, nil
+}
+
+// defaultToken returns the token for a forge type from credentials
+func defaultToken(kind Kind) string {
+	switch kind {
+	case KindGitLab:
+		return credentials.GetGitLabToken().Value
+	case KindGitea:
+		return credentials.GetGiteaToken().Value
+	default:
+		// GitHub App installation auth takes precedence when configured
+		if os.Getenv("GITHUB_APP_ID") != "" {
+			return ""
+		}
+		return credentials.GetGitHubToken().Value
+	}
+}
+
+// splitFullName splits "owner/repo" into its parts
+func splitFullName(fullName string) (owner, repo string, err error) {
+	parts := strings.Split(strings.Trim(fullName, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid repository name %q: use owner/repo", fullName)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,332 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+)
+
+// newTestServer serves canned JSON responses keyed by request path
+func newTestServer(t *testing.T, routes map[string]interface{}, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if check != nil {
+			check(r)
+		}
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		// Only the first page has data
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			w.Write([]byte("[]"))
+			return
+		}
+		if h, ok := body.(http.HandlerFunc); ok {
+			h(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitHub(t *testing.T) {
+	t.Setenv("ZERO_HOME", t.TempDir()) // Keep the ETag cache out of ~/.zero
+	srv := newTestServer(t, map[string]interface{}{
+		"/api/v3/users/octo/repos": []map[string]interface{}{
+			{"name": "app", "full_name": "octo/app", "clone_url": "https://ghe.example.com/octo/app.git", "owner": map[string]string{"login": "octo"}},
+		},
+		"/api/v3/repos/octo/app/pulls": []map[string]interface{}{
+			{"number": 2, "title": "Merged", "created_at": "2024-01-02T00:00:00Z", "merged_at": "2024-01-03T00:00:00Z", "user": map[string]string{"login": "alice"}},
+			{"number": 1, "title": "Closed", "created_at": "2024-01-01T00:00:00Z", "merged_at": nil, "user": map[string]string{"login": "bob"}},
+		},
+		"/api/v3/repos/octo/app/pulls/2/reviews": []map[string]interface{}{
+			{"state": "APPROVED", "submitted_at": "2024-01-02T12:00:00Z", "user": map[string]string{"login": "carol"}},
+			{"state": "PENDING", "user": map[string]string{"login": "dave"}},
+		},
+		"/api/v3/repos/octo/app/pulls/2": map[string]int{"additions": 10, "deletions": 3},
+		"/api/v3/search/issues":          map[string]int{"total_count": 42},
+		"/api/v3/repos/octo/app/collaborators": []map[string]string{
+			{"login": "alice", "role_name": "admin"},
+		},
+	}, func(r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+	})
+
+	f, err := New(KindGitHub, srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// /orgs/octo 404s, falls back to /users/octo
+	repos, err := f.ListOrgRepos(ctx, "octo", 10)
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].NameWithOwner != "octo/app" || repos[0].DefaultBranch != "main" {
+		t.Errorf("ListOrgRepos() = %+v", repos)
+	}
+
+	count, err := f.CountMergedPullRequests(ctx, "octo/app")
+	if err != nil || count != 42 {
+		t.Errorf("CountMergedPullRequests() = %d, %v", count, err)
+	}
+
+	prs, err := f.ListMergedPullRequests(ctx, "octo/app", PullRequestOptions{WithStats: true})
+	if err != nil {
+		t.Fatalf("ListMergedPullRequests() error = %v", err)
+	}
+	if len(prs) != 1 {
+		t.Fatalf("got %d PRs, want 1 (unmerged excluded)", len(prs))
+	}
+	pr := prs[0]
+	if pr.Number != 2 || pr.Author != "alice" || pr.Additions != 10 || pr.Deletions != 3 {
+		t.Errorf("PR = %+v", pr)
+	}
+	if len(pr.Reviews) != 1 || pr.Reviews[0].State != ReviewApproved || pr.Reviews[0].Author != "carol" {
+		t.Errorf("Reviews = %+v (pending should be dropped)", pr.Reviews)
+	}
+
+	collaborators, err := f.ListCollaborators(ctx, "octo/app")
+	if err != nil || len(collaborators) != 1 || collaborators[0].Permission != "admin" {
+		t.Errorf("ListCollaborators() = %+v, %v", collaborators, err)
+	}
+
+	if got := f.CloneURL("octo/app"); got != srv.URL+"/octo/app.git" {
+		t.Errorf("CloneURL() = %q", got)
+	}
+}
+
+func TestGitLab(t *testing.T) {
+	srv := newTestServer(t, map[string]interface{}{
+		"/api/v4/groups/platform/projects": []map[string]interface{}{
+			{
+				"path": "api", "path_with_namespace": "platform/backend/api",
+				"http_url_to_repo": "https://gitlab.example.com/platform/backend/api.git",
+				"visibility":       "internal", "namespace": map[string]string{"path": "backend"},
+			},
+		},
+		"/api/v4/projects/platform/backend/api/merge_requests": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("state") != "merged" {
+				t.Errorf("state = %q, want merged", r.URL.Query().Get("state"))
+			}
+			w.Header().Set("X-Total", "7")
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"iid": 5, "title": "Add API", "created_at": "2024-02-01T00:00:00Z", "merged_at": "2024-02-02T00:00:00Z", "author": map[string]string{"username": "alice"}},
+			})
+		}),
+		"/api/v4/projects/platform/backend/api/merge_requests/5/notes": []map[string]interface{}{
+			{"body": "approved this merge request", "system": true, "created_at": "2024-02-01T10:00:00Z", "author": map[string]string{"username": "bob"}},
+			{"body": "Looks good", "system": false, "created_at": "2024-02-01T09:00:00Z", "author": map[string]string{"username": "carol"}},
+			{"body": "added 1 commit", "system": true, "author": map[string]string{"username": "alice"}},
+			{"body": "Thanks!", "system": false, "author": map[string]string{"username": "alice"}},
+		},
+		"/api/v4/projects/platform/backend/api/merge_requests/5/changes": map[string]interface{}{
+			"changes": []map[string]string{{"diff": "--- a/x\n+++ b/x\n@@ -1 +1,2 @@\n-old\n+new\n+more\n"}},
+		},
+		"/api/v4/projects/platform/backend/api/members/all": []map[string]interface{}{
+			{"username": "bob", "access_level": 40},
+			{"username": "carol", "access_level": 30},
+			{"username": "eve", "access_level": 20},
+		},
+	}, func(r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "glpat" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+	})
+
+	f, err := New(KindGitLab, srv.URL, "glpat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	repos, err := f.ListOrgRepos(ctx, "platform", 10)
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].NameWithOwner != "backend/api" || repos[0].FullName != "platform/backend/api" || !repos[0].Private {
+		t.Errorf("ListOrgRepos() = %+v", repos)
+	}
+
+	count, err := f.CountMergedPullRequests(ctx, "platform/backend/api")
+	if err != nil || count != 7 {
+		t.Errorf("CountMergedPullRequests() = %d, %v", count, err)
+	}
+
+	prs, err := f.ListMergedPullRequests(ctx, "platform/backend/api", PullRequestOptions{WithStats: true})
+	if err != nil {
+		t.Fatalf("ListMergedPullRequests() error = %v", err)
+	}
+	if len(prs) != 1 || prs[0].Additions != 2 || prs[0].Deletions != 1 {
+		t.Fatalf("ListMergedPullRequests() = %+v", prs)
+	}
+	states := map[string]string{}
+	for _, r := range prs[0].Reviews {
+		states[r.Author] = r.State
+	}
+	if len(states) != 2 || states["bob"] != ReviewApproved || states["carol"] != ReviewCommented {
+		t.Errorf("Reviews = %+v (author notes should be excluded)", prs[0].Reviews)
+	}
+
+	members, err := f.ListCollaborators(ctx, "platform/backend/api")
+	if err != nil || len(members) != 3 {
+		t.Fatalf("ListCollaborators() = %+v, %v", members, err)
+	}
+	want := []string{"admin", "push", "pull"}
+	for i, m := range members {
+		if m.Permission != want[i] {
+			t.Errorf("%s permission = %q, want %q", m.Login, m.Permission, want[i])
+		}
+	}
+}
+
+func TestGitea(t *testing.T) {
+	srv := newTestServer(t, map[string]interface{}{
+		"/api/v1/orgs/infra/repos": []map[string]interface{}{
+			{"name": "tools", "full_name": "infra/tools", "default_branch": "trunk", "owner": map[string]string{"login": "infra"}},
+		},
+		"/api/v1/repos/infra/tools/pulls": []map[string]interface{}{
+			{"number": 3, "title": "Fix", "merged": true, "created_at": "2024-03-01T00:00:00Z", "merged_at": "2024-03-02T00:00:00Z", "additions": 4, "deletions": 1, "user": map[string]string{"login": "alice"}},
+			{"number": 2, "title": "Old", "merged": true, "created_at": "2023-01-01T00:00:00Z", "merged_at": "2023-01-02T00:00:00Z", "user": map[string]string{"login": "alice"}},
+			{"number": 1, "title": "Rejected", "merged": false, "user": map[string]string{"login": "bob"}},
+		},
+		"/api/v1/repos/infra/tools/pulls/3/reviews": []map[string]interface{}{
+			{"state": "REQUEST_CHANGES", "submitted_at": "2024-03-01T05:00:00Z", "user": map[string]string{"login": "bob"}},
+			{"state": "APPROVED", "submitted_at": "2024-03-01T06:00:00Z", "user": map[string]string{"login": "bob"}},
+		},
+	}, func(r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token tea" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Query().Get("per_page") != "" {
+			t.Error("gitea should page with limit, not per_page")
+		}
+	})
+
+	if _, err := New(KindGitea, "", "tea"); err == nil {
+		t.Error("New(gitea) without base URL should fail")
+	}
+
+	f, err := New(KindGitea, srv.URL+"/", "tea")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	repos, err := f.ListOrgRepos(ctx, "infra", 0)
+	if err != nil || len(repos) != 1 || repos[0].DefaultBranch != "trunk" {
+		t.Errorf("ListOrgRepos() = %+v, %v", repos, err)
+	}
+
+	count, err := f.CountMergedPullRequests(ctx, "infra/tools")
+	if err != nil || count != 2 {
+		t.Errorf("CountMergedPullRequests() = %d, %v", count, err)
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prs, err := f.ListMergedPullRequests(ctx, "infra/tools", PullRequestOptions{Since: since})
+	if err != nil {
+		t.Fatalf("ListMergedPullRequests() error = %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 3 || prs[0].Additions != 4 {
+		t.Fatalf("ListMergedPullRequests() = %+v", prs)
+	}
+	if len(prs[0].Reviews) != 2 || prs[0].Reviews[0].State != ReviewChangesRequested {
+		t.Errorf("Reviews = %+v", prs[0].Reviews)
+	}
+
+	data := ReviewData(prs)
+	if len(data) != 1 || data[0].PRNumber != 3 || len(data[0].Reviews) != 2 {
+		t.Errorf("ReviewData() = %+v", data)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := newTestServer(t, map[string]interface{}{}, nil)
+
+	f, _ := New(KindGitLab, srv.URL, "")
+	_, err := f.CountMergedPullRequests(context.Background(), "missing/repo")
+	if err == nil {
+		t.Fatal("expected error for 404")
+	}
+	if f.HasToken() {
+		t.Error("HasToken() should be false without token")
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote   string
+		host     string
+		fullName string
+		ok       bool
+	}{
+		{"https://github.com/owner/repo.git", "github.com", "owner/repo", true},
+		{"git@gitlab.com:group/sub/repo.git", "gitlab.com", "group/sub/repo", true},
+		{"ssh://git@Codeberg.org:22/owner/repo", "codeberg.org", "owner/repo", true},
+		{"https://github.com/", "", "", false},
+		{"not a remote", "", "", false},
+	}
+
+	for _, tt := range tests {
+		host, fullName, ok := ParseRemote(tt.remote)
+		if ok != tt.ok || host != tt.host || fullName != tt.fullName {
+			t.Errorf("ParseRemote(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.remote, host, fullName, ok, tt.host, tt.fullName, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	SetHosts([]config.ForgeHost{{Host: "git.corp.example", Type: "gitlab"}})
+	defer SetHosts(nil)
+
+	tests := map[string]Kind{
+		"github.com":         KindGitHub,
+		"gitlab.com":         KindGitLab,
+		"gitlab.example.com": KindGitLab,
+		"codeberg.org":       KindGitea,
+		"git.corp.example":   KindGitLab,
+		"bitbucket.org":      "",
+	}
+	for host, want := range tests {
+		if got := Detect(host); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", host, got, want)
+		}
+	}
+
+	if _, _, err := ForRemote("https://bitbucket.org/owner/repo.git"); err == nil {
+		t.Error("ForRemote() should fail for unknown host")
+	}
+	f, fullName, err := ForRemote("git@git.corp.example:team/svc.git")
+	if err != nil {
+		t.Fatalf("ForRemote() error = %v", err)
+	}
+	if f.Kind() != KindGitLab || f.BaseURL() != "https://git.corp.example" || fullName != "team/svc" {
+		t.Errorf("ForRemote() = %s %s %s", f.Kind(), f.BaseURL(), fullName)
+	}
+}
+
+func TestSSHURL(t *testing.T) {
+	gh, _ := New(KindGitHub, "", "")
+	gl, _ := New(KindGitLab, "https://gitlab.example.com:8443/", "")
+
+	if got := SSHURL(gh, "owner/repo"); got != "git@github.com:owner/repo.git" {
+		t.Errorf("SSHURL(github) = %q", got)
+	}
+	if got := SSHURL(gl, "group/sub/project"); got != "git@gitlab.example.com:group/sub/project.git" {
+		t.Errorf("SSHURL(gitlab) = %q", got)
+	}
+}
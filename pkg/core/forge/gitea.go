@@ -0,0 +1,210 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// giteaForge talks to the Gitea REST API (also used by Forgejo and Codeberg)
+type giteaForge struct {
+	baseURL string
+	token   string
+	rest    *restClient
+}
+
+// giteaMaxPageSize is the default MAX_RESPONSE_ITEMS of a Gitea instance
+const giteaMaxPageSize = 50
+
+func newGitea(baseURL, token string) *giteaForge {
+	f := &giteaForge{baseURL: baseURL, token: token}
+	f.rest = newRESTClient(baseURL+"/api/v1", "limit", func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	})
+	return f
+}
+
+func (f *giteaForge) Kind() Kind      { return KindGitea }
+func (f *giteaForge) BaseURL() string { return f.baseURL }
+func (f *giteaForge) HasToken() bool  { return f.token != "" }
+
+func (f *giteaForge) CloneURL(fullName string) string {
+	return fmt.Sprintf("%s/%s.git", f.baseURL, fullName)
+}
+
+// ListOrgRepos lists organization repositories, falling back to user repositories
+func (f *giteaForge) ListOrgRepos(ctx context.Context, org string, limit int) ([]github.Repository, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	repos, err := f.listRepos(ctx, "/orgs/"+url.PathEscape(org)+"/repos", limit)
+	if IsNotFound(err) {
+		repos, err = f.listRepos(ctx, "/users/"+url.PathEscape(org)+"/repos", limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing repos: %w", err)
+	}
+	return repos, nil
+}
+
+func (f *giteaForge) listRepos(ctx context.Context, path string, limit int) ([]github.Repository, error) {
+	var repos []github.Repository
+	err := f.rest.paginate(ctx, path, nil, pageLimit(limit, giteaMaxPageSize), func(raw json.RawMessage) (int, bool, error) {
+		// Gitea's repository shape matches GitHub's for the fields we use
+		// (size is reported in KB as well)
+		var page []github.RESTRepository
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing repos: %w", err)
+		}
+		for _, r := range page {
+			if len(repos) >= limit {
+				break
+			}
+			repos = append(repos, r.Repository())
+		}
+		return len(page), len(repos) < limit, nil
+	})
+	return repos, err
+}
+
+// giteaCountLimit caps how many closed PRs are paged through when counting
+const giteaCountLimit = 10000
+
+// CountMergedPullRequests counts merged PRs by paging through closed PRs.
+// Gitea has no merged-state filter or total count header for pulls.
+func (f *giteaForge) CountMergedPullRequests(ctx context.Context, fullName string) (int, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{}
+	query.Set("state", "closed")
+
+	count, seen := 0, 0
+	path := fmt.Sprintf("/repos/%s/%s/pulls", url.PathEscape(owner), url.PathEscape(repo))
+	err = f.rest.paginate(ctx, path, query, giteaMaxPageSize, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Merged bool `json:"merged"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing PRs: %w", err)
+		}
+		for _, p := range page {
+			if p.Merged {
+				count++
+			}
+		}
+		seen += len(page)
+		return len(page), seen < giteaCountLimit, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting PRs: %w", err)
+	}
+	return count, nil
+}
+
+// ListMergedPullRequests lists recently merged PRs with their reviews
+func (f *giteaForge) ListMergedPullRequests(ctx context.Context, fullName string, opts PullRequestOptions) ([]PullRequest, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+	query := url.Values{}
+	query.Set("state", "closed")
+	query.Set("sort", "recentupdate")
+
+	var prs []PullRequest
+	err = f.rest.paginate(ctx, base+"/pulls", query, giteaMaxPageSize, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Number    int    `json:"number"`
+			Title     string `json:"title"`
+			Merged    bool   `json:"merged"`
+			CreatedAt string `json:"created_at"`
+			MergedAt  string `json:"merged_at"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
+			User      struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing PRs: %w", err)
+		}
+		for _, p := range page {
+			if !p.Merged || len(prs) >= limit {
+				continue
+			}
+			pr := PullRequest{
+				Number:    p.Number,
+				Title:     p.Title,
+				Author:    p.User.Login,
+				CreatedAt: parseTime(p.CreatedAt),
+				MergedAt:  parseTime(p.MergedAt),
+				// Gitea includes line stats in the listing (1.20+)
+				Additions: p.Additions,
+				Deletions: p.Deletions,
+			}
+			if !opts.Since.IsZero() && pr.CreatedAt.Before(opts.Since) {
+				continue
+			}
+			prs = append(prs, pr)
+		}
+		return len(page), len(prs) < limit, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching PRs: %w", err)
+	}
+
+	for i := range prs {
+		pr := &prs[i]
+		reviews, err := listReviews(ctx, f.rest, fmt.Sprintf("%s/pulls/%d/reviews", base, pr.Number))
+		if err != nil {
+			return nil, fmt.Errorf("fetching reviews for #%d: %w", pr.Number, err)
+		}
+		pr.Reviews = reviews
+	}
+
+	return prs, nil
+}
+
+// ListCollaborators lists repository collaborators. Gitea's listing returns
+// users only, so collaborators are reported with push access.
+func (f *giteaForge) ListCollaborators(ctx context.Context, fullName string) ([]github.Collaborator, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var collaborators []github.Collaborator
+	path := fmt.Sprintf("/repos/%s/%s/collaborators", url.PathEscape(owner), url.PathEscape(repo))
+	err = f.rest.paginate(ctx, path, nil, giteaMaxPageSize, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Login string `json:"login"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing collaborators: %w", err)
+		}
+		for _, c := range page {
+			collaborators = append(collaborators, github.Collaborator{Login: c.Login, Permission: "push"})
+		}
+		return len(page), true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching collaborators: %w", err)
+	}
+	return collaborators, nil
+}
@@ -0,0 +1,247 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// gitHubForge talks to the GitHub REST API (github.com or GitHub Enterprise)
+// through the native GitHub client, which adds ETag caching, rate-limit
+// backoff and GitHub App auth
+type gitHubForge struct {
+	baseURL string
+	api     *github.Client
+	rest    api
+}
+
+func newGitHub(baseURL, token string) (*gitHubForge, error) {
+	opts := github.ClientOptions{Token: token, CacheDir: github.DefaultCacheDir()}
+	if baseURL == "" {
+		baseURL = "https://github.com"
+	} else {
+		// GitHub Enterprise Server serves the API under /api/v3
+		opts.APIURL = baseURL + "/api/v3"
+	}
+
+	// Without a token, authenticate as a GitHub App installation if configured
+	if token == "" {
+		app, err := github.AppConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		opts.App = app
+	}
+
+	client, err := github.NewClientWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &gitHubForge{baseURL: baseURL, api: client, rest: gitHubAPI{client}}, nil
+}
+
+func (f *gitHubForge) Kind() Kind      { return KindGitHub }
+func (f *gitHubForge) BaseURL() string { return f.baseURL }
+func (f *gitHubForge) HasToken() bool  { return f.api.HasToken() }
+
+func (f *gitHubForge) CloneURL(fullName string) string {
+	return fmt.Sprintf("%s/%s.git", f.baseURL, fullName)
+}
+
+// ListOrgRepos lists organization repositories, falling back to user repositories
+func (f *gitHubForge) ListOrgRepos(ctx context.Context, org string, limit int) ([]github.Repository, error) {
+	return f.api.ListOrgReposContext(ctx, org, limit)
+}
+
+// CountMergedPullRequests uses the search API for an exact count
+func (f *gitHubForge) CountMergedPullRequests(ctx context.Context, fullName string) (int, error) {
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("repo:%s is:pr is:merged", fullName))
+	query.Set("per_page", "1")
+
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if _, err := f.rest.get(ctx, "/search/issues", query, &result); err != nil {
+		return 0, fmt.Errorf("counting PRs: %w", err)
+	}
+	return result.TotalCount, nil
+}
+
+// ListMergedPullRequests lists recently merged PRs with their reviews
+func (f *gitHubForge) ListMergedPullRequests(ctx context.Context, fullName string, opts PullRequestOptions) ([]PullRequest, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	base := fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo))
+	query := url.Values{}
+	query.Set("state", "closed")
+	query.Set("sort", "updated")
+	query.Set("direction", "desc")
+
+	var prs []PullRequest
+	err = f.rest.paginate(ctx, base+"/pulls", query, 100, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Number    int    `json:"number"`
+			Title     string `json:"title"`
+			CreatedAt string `json:"created_at"`
+			MergedAt  string `json:"merged_at"`
+			User      struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing PRs: %w", err)
+		}
+		for _, p := range page {
+			if p.MergedAt == "" || len(prs) >= limit {
+				continue
+			}
+			pr := PullRequest{
+				Number:    p.Number,
+				Title:     p.Title,
+				Author:    p.User.Login,
+				CreatedAt: parseTime(p.CreatedAt),
+				MergedAt:  parseTime(p.MergedAt),
+			}
+			if !opts.Since.IsZero() && pr.CreatedAt.Before(opts.Since) {
+				continue
+			}
+			prs = append(prs, pr)
+		}
+		return len(page), len(prs) < limit, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching PRs: %w", err)
+	}
+
+	for i := range prs {
+		pr := &prs[i]
+		reviews, err := listReviews(ctx, f.rest, fmt.Sprintf("%s/pulls/%d/reviews", base, pr.Number))
+		if err != nil {
+			return nil, fmt.Errorf("fetching reviews for #%d: %w", pr.Number, err)
+		}
+		pr.Reviews = reviews
+
+		if opts.WithStats {
+			var detail struct {
+				Additions int `json:"additions"`
+				Deletions int `json:"deletions"`
+			}
+			if _, err := f.rest.get(ctx, fmt.Sprintf("%s/pulls/%d", base, pr.Number), nil, &detail); err != nil {
+				return nil, fmt.Errorf("fetching PR #%d: %w", pr.Number, err)
+			}
+			pr.Additions = detail.Additions
+			pr.Deletions = detail.Deletions
+		}
+	}
+
+	return prs, nil
+}
+
+// ListCollaborators lists repository collaborators with their role
+func (f *gitHubForge) ListCollaborators(ctx context.Context, fullName string) ([]github.Collaborator, error) {
+	owner, repo, err := splitFullName(fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	var collaborators []github.Collaborator
+	path := fmt.Sprintf("/repos/%s/%s/collaborators", url.PathEscape(owner), url.PathEscape(repo))
+	err = f.rest.paginate(ctx, path, nil, 100, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Login    string `json:"login"`
+			RoleName string `json:"role_name"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing collaborators: %w", err)
+		}
+		for _, c := range page {
+			collaborators = append(collaborators, github.Collaborator{Login: c.Login, Permission: c.RoleName})
+		}
+		return len(page), true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching collaborators: %w", err)
+	}
+	return collaborators, nil
+}
+
+// gitHubAPI adapts the GitHub client's Link-header pagination to the
+// forge api interface
+type gitHubAPI struct {
+	client *github.Client
+}
+
+func (a gitHubAPI) get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	return a.client.Get(ctx, path, query, v)
+}
+
+func (a gitHubAPI) paginate(ctx context.Context, path string, query url.Values, perPage int, fetch func(page json.RawMessage) (n int, more bool, err error)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", strconv.Itoa(perPage))
+	return a.client.Paginate(ctx, path, query, func(page json.RawMessage) (bool, error) {
+		n, more, err := fetch(page)
+		return more && n >= perPage, err
+	})
+}
+
+// listReviews fetches PR reviews from a GitHub-compatible endpoint (GitHub and
+// Gitea share this shape) and normalizes states to GitHub naming
+func listReviews(ctx context.Context, rest api, path string) ([]github.Review, error) {
+	var reviews []github.Review
+	err := rest.paginate(ctx, path, nil, 50, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			State       string `json:"state"`
+			SubmittedAt string `json:"submitted_at"`
+			User        struct {
+				Login string `json:"login"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing reviews: %w", err)
+		}
+		for _, r := range page {
+			state := normalizeReviewState(r.State)
+			if state == "" {
+				continue
+			}
+			reviews = append(reviews, github.Review{
+				Author:      r.User.Login,
+				State:       state,
+				SubmittedAt: parseTime(r.SubmittedAt),
+			})
+		}
+		return len(page), true, nil
+	})
+	return reviews, err
+}
+
+// normalizeReviewState maps forge review states to GitHub naming.
+// Pending and dismissed reviews are dropped.
+func normalizeReviewState(state string) string {
+	switch strings.ToUpper(state) {
+	case "APPROVED":
+		return ReviewApproved
+	case "CHANGES_REQUESTED", "REQUEST_CHANGES":
+		return ReviewChangesRequested
+	case "COMMENTED", "COMMENT":
+		return ReviewCommented
+	default:
+		return ""
+	}
+}
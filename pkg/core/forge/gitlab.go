@@ -0,0 +1,321 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// gitLabForge talks to the GitLab REST API (gitlab.com or self-managed)
+type gitLabForge struct {
+	baseURL string
+	token   string
+	rest    *restClient
+}
+
+func newGitLab(baseURL, token string) *gitLabForge {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+
+	f := &gitLabForge{baseURL: baseURL, token: token}
+	f.rest = newRESTClient(baseURL+"/api/v4", "per_page", func(req *http.Request) {
+		if token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	})
+	return f
+}
+
+func (f *gitLabForge) Kind() Kind      { return KindGitLab }
+func (f *gitLabForge) BaseURL() string { return f.baseURL }
+func (f *gitLabForge) HasToken() bool  { return f.token != "" }
+
+func (f *gitLabForge) CloneURL(fullName string) string {
+	return fmt.Sprintf("%s/%s.git", f.baseURL, fullName)
+}
+
+// projectPath returns the URL-encoded project path used as an API ID
+func projectPath(fullName string) string {
+	return url.PathEscape(strings.Trim(fullName, "/"))
+}
+
+// ListOrgRepos lists projects in a group (including subgroups), falling back
+// to a user's projects
+func (f *gitLabForge) ListOrgRepos(ctx context.Context, org string, limit int) ([]github.Repository, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	query := url.Values{}
+	query.Set("include_subgroups", "true")
+	query.Set("order_by", "last_activity_at")
+
+	repos, err := f.listProjects(ctx, "/groups/"+projectPath(org)+"/projects", query, limit)
+	if IsNotFound(err) {
+		repos, err = f.listProjects(ctx, "/users/"+url.PathEscape(org)+"/projects", url.Values{}, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+	return repos, nil
+}
+
+func (f *gitLabForge) listProjects(ctx context.Context, path string, query url.Values, limit int) ([]github.Repository, error) {
+	var repos []github.Repository
+	err := f.rest.paginate(ctx, path, query, pageLimit(limit, 100), func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Path              string `json:"path"`
+			PathWithNamespace string `json:"path_with_namespace"`
+			HTTPURLToRepo     string `json:"http_url_to_repo"`
+			SSHURLToRepo      string `json:"ssh_url_to_repo"`
+			DefaultBranch     string `json:"default_branch"`
+			Visibility        string `json:"visibility"`
+			Archived          bool   `json:"archived"`
+			ForkedFrom        *struct {
+				ID int `json:"id"`
+			} `json:"forked_from_project"`
+			Namespace struct {
+				Path string `json:"path"`
+			} `json:"namespace"`
+			Statistics *struct {
+				RepositorySize int64 `json:"repository_size"`
+			} `json:"statistics"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing projects: %w", err)
+		}
+		for _, p := range page {
+			if len(repos) >= limit {
+				break
+			}
+			defaultBranch := p.DefaultBranch
+			if defaultBranch == "" {
+				defaultBranch = "main"
+			}
+			size := 0
+			if p.Statistics != nil {
+				size = int(p.Statistics.RepositorySize / 1024)
+			}
+			repos = append(repos, github.Repository{
+				Name: p.Path,
+				// Nested groups are flattened to the immediate namespace so the
+				// project fits the owner/repo storage layout
+				NameWithOwner: p.Namespace.Path + "/" + p.Path,
+				FullName:      p.PathWithNamespace,
+				Owner:         p.Namespace.Path,
+				CloneURL:      p.HTTPURLToRepo,
+				SSHURL:        p.SSHURLToRepo,
+				Size:          size,
+				DefaultBranch: defaultBranch,
+				Private:       p.Visibility != "public",
+				Archived:      p.Archived,
+				Fork:          p.ForkedFrom != nil,
+			})
+		}
+		return len(page), len(repos) < limit, nil
+	})
+	return repos, err
+}
+
+// CountMergedPullRequests reads the X-Total header of the merge request listing
+func (f *gitLabForge) CountMergedPullRequests(ctx context.Context, fullName string) (int, error) {
+	query := url.Values{}
+	query.Set("state", "merged")
+	query.Set("per_page", "1")
+
+	var page []json.RawMessage
+	header, err := f.rest.get(ctx, "/projects/"+projectPath(fullName)+"/merge_requests", query, &page)
+	if err != nil {
+		return 0, fmt.Errorf("counting merge requests: %w", err)
+	}
+
+	// X-Total is omitted for very large result sets
+	if total, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+		return total, nil
+	}
+	return 0, fmt.Errorf("counting merge requests: X-Total header not returned")
+}
+
+// ListMergedPullRequests lists merged merge requests. Reviews are derived from
+// approval system notes and discussion comments.
+func (f *gitLabForge) ListMergedPullRequests(ctx context.Context, fullName string, opts PullRequestOptions) ([]PullRequest, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	base := "/projects/" + projectPath(fullName) + "/merge_requests"
+	query := url.Values{}
+	query.Set("state", "merged")
+	query.Set("order_by", "updated_at")
+	query.Set("sort", "desc")
+	if !opts.Since.IsZero() {
+		query.Set("created_after", opts.Since.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+
+	var prs []PullRequest
+	err := f.rest.paginate(ctx, base, query, pageLimit(limit, 100), func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			IID       int    `json:"iid"`
+			Title     string `json:"title"`
+			CreatedAt string `json:"created_at"`
+			MergedAt  string `json:"merged_at"`
+			Author    struct {
+				Username string `json:"username"`
+			} `json:"author"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing merge requests: %w", err)
+		}
+		for _, mr := range page {
+			if len(prs) >= limit {
+				break
+			}
+			prs = append(prs, PullRequest{
+				Number:    mr.IID,
+				Title:     mr.Title,
+				Author:    mr.Author.Username,
+				CreatedAt: parseTime(mr.CreatedAt),
+				MergedAt:  parseTime(mr.MergedAt),
+			})
+		}
+		return len(page), len(prs) < limit, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching merge requests: %w", err)
+	}
+
+	for i := range prs {
+		pr := &prs[i]
+		reviews, err := f.listReviews(ctx, fmt.Sprintf("%s/%d/notes", base, pr.Number), pr.Author)
+		if err != nil {
+			return nil, fmt.Errorf("fetching notes for !%d: %w", pr.Number, err)
+		}
+		pr.Reviews = reviews
+
+		if opts.WithStats {
+			var changes struct {
+				Changes []struct {
+					Diff string `json:"diff"`
+				} `json:"changes"`
+			}
+			if _, err := f.rest.get(ctx, fmt.Sprintf("%s/%d/changes", base, pr.Number), nil, &changes); err != nil {
+				return nil, fmt.Errorf("fetching changes for !%d: %w", pr.Number, err)
+			}
+			for _, c := range changes.Changes {
+				add, del := countDiffLines(c.Diff)
+				pr.Additions += add
+				pr.Deletions += del
+			}
+		}
+	}
+
+	return prs, nil
+}
+
+// listReviews converts merge request notes to reviews. GitLab records approvals
+// as system notes; non-system notes from anyone but the author count as comments.
+func (f *gitLabForge) listReviews(ctx context.Context, path, author string) ([]github.Review, error) {
+	query := url.Values{}
+	query.Set("sort", "asc")
+
+	var reviews []github.Review
+	err := f.rest.paginate(ctx, path, query, 100, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Body      string `json:"body"`
+			System    bool   `json:"system"`
+			CreatedAt string `json:"created_at"`
+			Author    struct {
+				Username string `json:"username"`
+			} `json:"author"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing notes: %w", err)
+		}
+		for _, n := range page {
+			if n.Author.Username == author {
+				continue
+			}
+			state := ""
+			body := strings.ToLower(n.Body)
+			switch {
+			case n.System && strings.HasPrefix(body, "approved this merge request"):
+				state = ReviewApproved
+			case n.System && strings.HasPrefix(body, "requested changes"):
+				state = ReviewChangesRequested
+			case !n.System:
+				state = ReviewCommented
+			}
+			if state == "" {
+				continue
+			}
+			reviews = append(reviews, github.Review{
+				Author:      n.Author.Username,
+				State:       state,
+				SubmittedAt: parseTime(n.CreatedAt),
+			})
+		}
+		return len(page), true, nil
+	})
+	return reviews, err
+}
+
+// countDiffLines counts added and removed lines in a unified diff
+func countDiffLines(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// ListCollaborators lists project members, including inherited group members.
+// Access levels are mapped to GitHub permission names.
+func (f *gitLabForge) ListCollaborators(ctx context.Context, fullName string) ([]github.Collaborator, error) {
+	var collaborators []github.Collaborator
+	err := f.rest.paginate(ctx, "/projects/"+projectPath(fullName)+"/members/all", nil, 100, func(raw json.RawMessage) (int, bool, error) {
+		var page []struct {
+			Username    string `json:"username"`
+			AccessLevel int    `json:"access_level"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return 0, false, fmt.Errorf("parsing members: %w", err)
+		}
+		for _, m := range page {
+			collaborators = append(collaborators, github.Collaborator{
+				Login:      m.Username,
+				Permission: gitLabPermission(m.AccessLevel),
+			})
+		}
+		return len(page), true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching members: %w", err)
+	}
+	return collaborators, nil
+}
+
+// gitLabPermission maps GitLab access levels to GitHub permission names
+func gitLabPermission(level int) string {
+	switch {
+	case level >= 40: // Maintainer, Owner
+		return "admin"
+	case level >= 30: // Developer
+		return "push"
+	default: // Guest, Reporter
+		return "pull"
+	}
+}
@@ -0,0 +1,135 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// maxPages bounds pagination so a misbehaving server cannot loop forever
+const maxPages = 100
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: HTTP %d: %s", e.URL, e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if err is a 404 from the forge API
+func IsNotFound(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode == http.StatusNotFound
+	}
+	return github.IsNotFound(err)
+}
+
+// api is the request interface shared by the forge implementations
+type api interface {
+	get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error)
+	paginate(ctx context.Context, path string, query url.Values, perPage int, fetch func(page json.RawMessage) (n int, more bool, err error)) error
+}
+
+// restClient is a minimal JSON REST client for forges with page-number pagination
+type restClient struct {
+	apiURL     string
+	httpClient *http.Client
+	authorize  func(req *http.Request)
+	pageSize   string // Query parameter for page size ("per_page" or "limit")
+}
+
+func newRESTClient(apiURL, pageSize string, authorize func(req *http.Request)) *restClient {
+	return &restClient{
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		authorize:  authorize,
+		pageSize:   pageSize,
+	}
+}
+
+// get performs a GET request and decodes the JSON response into v
+func (c *restClient) get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	u := c.apiURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.Header, &APIError{StatusCode: resp.StatusCode, URL: c.apiURL + path, Message: string(body)}
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return resp.Header, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// paginate fetches pages of a list endpoint. fetch decodes one page and returns
+// the number of items it contained and whether to continue.
+func (c *restClient) paginate(ctx context.Context, path string, query url.Values, perPage int, fetch func(page json.RawMessage) (n int, more bool, err error)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set(c.pageSize, strconv.Itoa(perPage))
+
+	for page := 1; page <= maxPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var raw json.RawMessage
+		if _, err := c.get(ctx, path, query, &raw); err != nil {
+			return err
+		}
+
+		n, more, err := fetch(raw)
+		if err != nil {
+			return err
+		}
+		if !more || n < perPage {
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseTime parses an RFC 3339 timestamp, returning zero time on failure
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// pageLimit returns the page size for a result limit
+func pageLimit(limit, max int) int {
+	if limit > 0 && limit < max {
+		return limit
+	}
+	return max
+}
@@ -0,0 +1,372 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// REST and GraphQL Transport
+// ============================================================================
+
+// DefaultAPIURL is the REST API root for github.com
+const DefaultAPIURL = "https://api.github.com"
+
+const (
+	// maxRetries bounds retries after rate limiting
+	maxRetries = 3
+
+	// maxRateLimitWait is the longest we sleep for a rate limit reset before
+	// giving up (primary limits can take up to an hour to reset)
+	maxRateLimitWait = 5 * time.Minute
+
+	// maxPages bounds pagination so a misbehaving server cannot loop forever
+	maxPages = 100
+)
+
+// ClientOptions configures a GitHub client
+type ClientOptions struct {
+	APIURL     string       // REST API root (default: https://api.github.com, GHES: https://host/api/v3)
+	Token      string       // Personal access token (ignored when App is set)
+	App        *AppConfig   // GitHub App installation auth
+	CacheDir   string       // ETag response cache directory ("" disables caching)
+	HTTPClient *http.Client // Custom HTTP client (default: 30s timeout)
+}
+
+// APIError is returned for non-2xx GitHub API responses
+type APIError struct {
+	StatusCode int
+	URL        string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API error (status %d) for %s: %s", e.StatusCode, e.URL, e.Message)
+}
+
+// IsNotFound returns true if err is a 404 from the GitHub API
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// apiResponse is a fully read API response
+type apiResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Cached     bool // Served from the ETag cache after a 304
+}
+
+// Get performs a GET request against the REST API and decodes the JSON
+// response into v. path is relative to the API root (e.g., "/repos/o/r").
+func (c *Client) Get(ctx context.Context, path string, query url.Values, v interface{}) (http.Header, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.resolve(path, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		if err := json.Unmarshal(resp.Body, v); err != nil {
+			return resp.Header, fmt.Errorf("decoding response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// Paginate walks a list endpoint by following Link rel="next" headers.
+// fetch receives each page and returns false to stop early.
+func (c *Client) Paginate(ctx context.Context, path string, query url.Values, fetch func(page json.RawMessage) (more bool, err error)) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if query.Get("per_page") == "" {
+		query.Set("per_page", "100")
+	}
+
+	next := c.resolve(path, query)
+	for page := 0; next != "" && page < maxPages; page++ {
+		resp, err := c.do(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return err
+		}
+
+		more, err := fetch(resp.Body)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+		next = nextLink(resp.Header.Get("Link"))
+	}
+	return nil
+}
+
+// GraphQL runs a GraphQL query and decodes the "data" field into v
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding query: %w", err)
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, c.graphQLURL(), payload)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return fmt.Errorf("decoding GraphQL response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		msgs := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("GraphQL: %s", strings.Join(msgs, "; "))
+	}
+	if v != nil {
+		if err := json.Unmarshal(result.Data, v); err != nil {
+			return fmt.Errorf("decoding GraphQL data: %w", err)
+		}
+	}
+	return nil
+}
+
+// do sends a request with auth, conditional GET and rate-limit handling
+func (c *Client) do(ctx context.Context, method, rawURL string, body []byte) (*apiResponse, error) {
+	auth, err := c.authorization(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached *cacheEntry
+	var cacheKey string
+	if method == http.MethodGet && c.cache != nil {
+		cacheKey = c.cache.key(c.credentialID(), rawURL)
+		cached = c.cache.get(cacheKey)
+	}
+
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("sending request: %w", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		// Not modified: conditional requests don't count against the rate limit
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			header := resp.Header.Clone()
+			if cached.Link != "" {
+				header.Set("Link", cached.Link)
+			}
+			return &apiResponse{StatusCode: http.StatusOK, Header: header, Body: cached.Body, Cached: true}, nil
+		}
+
+		if wait, limited := rateLimitWait(resp, data, attempt); limited {
+			if attempt >= maxRetries || wait > maxRateLimitWait {
+				return nil, &APIError{StatusCode: resp.StatusCode, URL: rawURL, Message: "rate limit exceeded: " + apiMessage(data)}
+			}
+			if err := c.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &APIError{StatusCode: resp.StatusCode, URL: rawURL, Message: apiMessage(data)}
+		}
+
+		if cacheKey != "" {
+			if etag := resp.Header.Get("ETag"); etag != "" {
+				c.cache.put(cacheKey, &cacheEntry{ETag: etag, Link: resp.Header.Get("Link"), Body: data})
+			}
+		}
+
+		return &apiResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}, nil
+	}
+}
+
+// authorization returns the Authorization header value for a request.
+// /app endpoints are authenticated as the App itself (JWT).
+func (c *Client) authorization(ctx context.Context, rawURL string) (string, error) {
+	if c.app != nil {
+		if strings.HasPrefix(rawURL, c.apiURL+"/app/") {
+			jwt, err := c.app.jwt(time.Now())
+			if err != nil {
+				return "", err
+			}
+			return "Bearer " + jwt, nil
+		}
+		token, err := c.app.installationToken(ctx, c)
+		if err != nil {
+			return "", fmt.Errorf("GitHub App auth: %w", err)
+		}
+		return "Bearer " + token, nil
+	}
+	if c.token != "" {
+		return "Bearer " + c.token, nil
+	}
+	return "", nil
+}
+
+// credentialID identifies the credential for cache keys. Installation tokens
+// rotate hourly, so App auth is keyed by installation rather than token.
+func (c *Client) credentialID() string {
+	if c.app != nil {
+		return "app:" + c.app.cfg.AppID + ":" + c.app.cfg.InstallationID
+	}
+	return c.token
+}
+
+// rateLimitWait returns how long to wait before retrying a rate-limited
+// response. Secondary limits without Retry-After back off exponentially
+// from one minute, as GitHub recommends.
+func rateLimitWait(resp *http.Response, body []byte, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			wait := time.Until(time.Unix(reset, 0)) + time.Second
+			if wait < time.Second {
+				wait = time.Second
+			}
+			return wait, true
+		}
+	}
+
+	if strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return time.Minute << attempt, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	if c.sleepFn != nil {
+		c.sleepFn(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resolve builds an absolute URL from an API path and query
+func (c *Client) resolve(path string, query url.Values) string {
+	u := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		u = c.apiURL + path
+	}
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(u, "?") {
+			sep = "&"
+		}
+		u += sep + query.Encode()
+	}
+	return u
+}
+
+// graphQLURL returns the GraphQL endpoint for the API root.
+// GitHub Enterprise Server serves GraphQL at /api/graphql rather than /api/v3/graphql.
+func (c *Client) graphQLURL() string {
+	if strings.HasSuffix(c.apiURL, "/api/v3") {
+		return strings.TrimSuffix(c.apiURL, "/v3") + "/graphql"
+	}
+	return c.apiURL + "/graphql"
+}
+
+// linkNextPattern matches the rel="next" entry of a Link header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the next page URL from a Link header, or ""
+func nextLink(link string) string {
+	if m := linkNextPattern.FindStringSubmatch(link); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// apiMessage extracts the "message" field from an error response body
+func apiMessage(body []byte) string {
+	var msg struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &msg); err == nil && msg.Message != "" {
+		return msg.Message
+	}
+	if len(body) > 200 {
+		body = body[:200]
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// DefaultCacheDir returns the ETag cache directory under ZERO_HOME or ~/.zero
+func DefaultCacheDir() string {
+	if zeroHome := os.Getenv("ZERO_HOME"); zeroHome != "" {
+		return filepath.Join(zeroHome, "cache", "github")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".zero", "cache", "github")
+}
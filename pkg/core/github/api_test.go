@@ -0,0 +1,345 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client pointed at handler with rate-limit sleeps recorded
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ClientOptions) (*Client, *[]time.Duration) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts.APIURL = srv.URL
+	c, err := NewClientWithOptions(opts)
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+
+	var sleeps []time.Duration
+	c.sleepFn = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return c, &sleeps
+}
+
+func TestPaginate_FollowsLinkHeader(t *testing.T) {
+	var srvURL string
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"login":"b"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next", <%s%s?page=2>; rel="last"`, srvURL, r.URL.Path, srvURL, r.URL.Path))
+		fmt.Fprint(w, `[{"login":"a"}]`)
+	}, ClientOptions{Token: "t"})
+	srvURL = c.apiURL
+
+	var logins []string
+	err := c.Paginate(context.Background(), "/orgs/o/members", nil, func(page json.RawMessage) (bool, error) {
+		var users []struct{ Login string }
+		if err := json.Unmarshal(page, &users); err != nil {
+			return false, err
+		}
+		for _, u := range users {
+			logins = append(logins, u.Login)
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Paginate() error = %v", err)
+	}
+	if strings.Join(logins, ",") != "a,b" {
+		t.Errorf("logins = %v, want [a b]", logins)
+	}
+}
+
+func TestDo_ETagCache(t *testing.T) {
+	var fullResponses int32
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&fullResponses, 1)
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"login":"octo"}`)
+	}, ClientOptions{Token: "t", CacheDir: t.TempDir()})
+
+	for i := 0; i < 3; i++ {
+		var user struct{ Login string }
+		if _, err := c.Get(context.Background(), "/user", nil, &user); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if user.Login != "octo" {
+			t.Errorf("request %d: login = %q, want octo", i, user.Login)
+		}
+	}
+	if fullResponses != 1 {
+		t.Errorf("server sent %d full responses, want 1 (rest should be 304)", fullResponses)
+	}
+
+	// A different token must not reuse the cached entry
+	c.token = "other"
+	if _, err := c.Get(context.Background(), "/user", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fullResponses != 2 {
+		t.Errorf("different token should bypass cache, got %d full responses", fullResponses)
+	}
+}
+
+func TestDo_SecondaryRateLimitBackoff(t *testing.T) {
+	var calls int32
+	c, sleeps := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"You have exceeded a secondary rate limit."}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}, ClientOptions{Token: "t"})
+
+	if _, err := c.Get(context.Background(), "/repos/o/r", nil, nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(*sleeps) != 2 || (*sleeps)[0] != time.Minute || (*sleeps)[1] != 2*time.Minute {
+		t.Errorf("sleeps = %v, want [1m 2m]", *sleeps)
+	}
+}
+
+func TestDo_RetryAfter(t *testing.T) {
+	var calls int32
+	c, sleeps := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}, ClientOptions{Token: "t"})
+
+	if _, err := c.Get(context.Background(), "/x", nil, nil); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 7*time.Second {
+		t.Errorf("sleeps = %v, want [7s]", *sleeps)
+	}
+}
+
+func TestDo_PrimaryRateLimitTooLong(t *testing.T) {
+	c, sleeps := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+	}, ClientOptions{Token: "t"})
+
+	_, err := c.Get(context.Background(), "/x", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("Get() error = %v, want rate limit error", err)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("should not sleep for an hour-long reset, slept %v", *sleeps)
+	}
+}
+
+func TestAppAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var minted int32
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		switch r.URL.Path {
+		case "/app/installations":
+			verifyJWT(t, auth, &key.PublicKey)
+			fmt.Fprint(w, `[{"id":42,"account":{"login":"acme"}}]`)
+		case "/app/installations/42/access_tokens":
+			if r.Method != http.MethodPost {
+				t.Errorf("access_tokens method = %s, want POST", r.Method)
+			}
+			verifyJWT(t, auth, &key.PublicKey)
+			atomic.AddInt32(&minted, 1)
+			fmt.Fprintf(w, `{"token":"ghs_test","expires_at":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			if auth != "ghs_test" {
+				t.Errorf("%s Authorization = %q, want installation token", r.URL.Path, auth)
+			}
+			fmt.Fprint(w, `{}`)
+		}
+	}, ClientOptions{App: &AppConfig{AppID: "123", PrivateKey: keyPEM}})
+
+	if !c.HasToken() || !c.IsApp() {
+		t.Error("App client should report a token")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(context.Background(), "/repos/acme/app", nil, nil); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	if minted != 1 {
+		t.Errorf("installation token minted %d times, want 1 (cached)", minted)
+	}
+}
+
+// verifyJWT checks an App JWT's signature and issuer
+func verifyJWT(t *testing.T, token string, pub *rsa.PublicKey) {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("JWT signature invalid: %v", err)
+	}
+	claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if !strings.Contains(string(claims), `"iss":"123"`) {
+		t.Errorf("JWT claims = %s, want iss 123", claims)
+	}
+}
+
+func TestParsePrivateKey_Invalid(t *testing.T) {
+	if _, err := NewClientWithOptions(ClientOptions{App: &AppConfig{AppID: "1", PrivateKey: []byte("nope")}}); err == nil {
+		t.Error("expected error for non-PEM key")
+	}
+	if _, err := NewClientWithOptions(ClientOptions{App: &AppConfig{AppID: "abc"}}); err == nil {
+		t.Error("expected error for non-numeric App ID")
+	}
+}
+
+func TestFetchPRReviews_GraphQL(t *testing.T) {
+	total := 2
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"owner":"acme"`) {
+			t.Errorf("variables missing owner: %s", body)
+		}
+		fmt.Fprintf(w, `{"data":{"repository":{"pullRequests":{
+			"totalCount": %d,
+			"pageInfo": {"hasNextPage": false, "endCursor": "c1"},
+			"nodes": [
+				{"number": 7, "title": "Fix", "author": {"login": "alice"}, "mergedAt": "2024-05-01T00:00:00Z",
+				 "reviews": {"nodes": [{"author": {"login": "bob"}, "state": "APPROVED", "submittedAt": "2024-04-30T00:00:00Z"}]}},
+				{"number": 6, "title": "Docs", "author": {"login": "carol"}, "mergedAt": "2024-04-01T00:00:00Z",
+				 "reviews": {"nodes": []}}
+			]}}}}`, total)
+	}, ClientOptions{Token: "t"})
+
+	oc := &OwnershipClient{Client: c, maxPRs: 10}
+	prs, count, err := oc.FetchPRReviews("acme", "app")
+	if err != nil {
+		t.Fatalf("FetchPRReviews() error = %v", err)
+	}
+	if count != 2 || len(prs) != 2 {
+		t.Fatalf("FetchPRReviews() = %d PRs, total %d", len(prs), count)
+	}
+	if prs[0].PRNumber != 7 || len(prs[0].Reviews) != 1 || prs[0].Reviews[0].State != "APPROVED" {
+		t.Errorf("prs[0] = %+v", prs[0])
+	}
+
+	// Over the threshold: no PRs, total reported
+	total = 500
+	prs, count, err = oc.FetchPRReviews("acme", "app")
+	if err != nil || prs != nil || count != 500 {
+		t.Errorf("over threshold: prs=%v count=%d err=%v", prs, count, err)
+	}
+}
+
+func TestGraphQL_Errors(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":null,"errors":[{"message":"Could not resolve to a Repository"}]}`)
+	}, ClientOptions{Token: "t"})
+
+	err := c.GraphQL(context.Background(), "query { viewer { login } }", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("GraphQL() error = %v", err)
+	}
+}
+
+func TestCheckUserExists(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/alice", "/orgs/acme/teams/core":
+			fmt.Fprint(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}, ClientOptions{Token: "t"})
+	oc := &OwnershipClient{Client: c}
+
+	tests := map[string]bool{"@alice": true, "ghost": false, "acme/core": true, "acme/nope": false}
+	for name, want := range tests {
+		got, err := oc.CheckUserExists(name)
+		if err != nil || got != want {
+			t.Errorf("CheckUserExists(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+}
+
+func TestListOrgRepos_UserFallback(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/octo/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"name":"a","full_name":"octo/a","size":2048,"default_branch":"trunk","owner":{"login":"octo"}},
+			{"name":"b","full_name":"octo/b","archived":true,"owner":{"login":"octo"}},
+			{"name":"c","full_name":"octo/c","owner":{"login":"octo"}}
+		]`)
+	}, ClientOptions{Token: "t"})
+
+	repos, err := c.ListOrgRepos("octo", 2)
+	if err != nil {
+		t.Fatalf("ListOrgRepos() error = %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("got %d repos, want 2 (limit)", len(repos))
+	}
+	if repos[0].NameWithOwner != "octo/a" || repos[0].Size != 2048 || repos[0].DefaultBranch != "trunk" {
+		t.Errorf("repos[0] = %+v", repos[0])
+	}
+	if repos[1].DefaultBranch != "main" || !repos[1].Archived {
+		t.Errorf("repos[1] = %+v", repos[1])
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		DefaultAPIURL:                     "https://api.github.com/graphql",
+		"https://ghe.example.com/api/v3":  "https://ghe.example.com/api/graphql",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com/api/graphql",
+	}
+	for apiURL, want := range tests {
+		c, _ := NewClientWithOptions(ClientOptions{APIURL: apiURL})
+		if got := c.graphQLURL(); got != want {
+			t.Errorf("graphQLURL(%q) = %q, want %q", apiURL, got, want)
+		}
+	}
+}
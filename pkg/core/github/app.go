@@ -0,0 +1,188 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// GitHub App Installation Auth
+// ============================================================================
+
+// AppConfig configures authentication as a GitHub App installation.
+// Installation tokens have per-installation rate limits that scale with the
+// number of repos, which suits large organization hydrations.
+type AppConfig struct {
+	AppID          string // Numeric App ID
+	InstallationID string // Installation ID (default: the App's only installation)
+	PrivateKey     []byte // PEM-encoded RSA private key
+}
+
+// AppConfigFromEnv reads GitHub App settings from the environment:
+// GITHUB_APP_ID, GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY
+// (PEM contents) or GITHUB_APP_PRIVATE_KEY_PATH. Returns nil if unset.
+func AppConfigFromEnv() (*AppConfig, error) {
+	appID := os.Getenv("GITHUB_APP_ID")
+	if appID == "" {
+		return nil, nil
+	}
+
+	key := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if len(key) == 0 {
+		path := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH")
+		if path == "" {
+			return nil, fmt.Errorf("GITHUB_APP_ID is set but GITHUB_APP_PRIVATE_KEY and GITHUB_APP_PRIVATE_KEY_PATH are not")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading GitHub App private key: %w", err)
+		}
+		key = data
+	}
+
+	return &AppConfig{
+		AppID:          appID,
+		InstallationID: os.Getenv("GITHUB_APP_INSTALLATION_ID"),
+		PrivateKey:     key,
+	}, nil
+}
+
+// appAuth mints App JWTs and caches installation tokens until shortly
+// before they expire
+type appAuth struct {
+	cfg AppConfig
+	key *rsa.PrivateKey
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newAppAuth(cfg *AppConfig) (*appAuth, error) {
+	if _, err := strconv.ParseInt(cfg.AppID, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid GitHub App ID %q", cfg.AppID)
+	}
+	key, err := parsePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &appAuth{cfg: *cfg, key: key}, nil
+}
+
+// parsePrivateKey parses a PKCS#1 or PKCS#8 PEM-encoded RSA key
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be RSA")
+	}
+	return key, nil
+}
+
+// jwt returns a signed App JWT valid for 9 minutes. iat is backdated to
+// allow for clock drift, as GitHub recommends.
+func (a *appAuth) jwt(now time.Time) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": a.cfg.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing App JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// installationToken returns a cached installation token, exchanging a new
+// JWT when the cached token is missing or about to expire
+func (a *appAuth) installationToken(ctx context.Context, c *Client) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Until(a.expires) > time.Minute {
+		return a.token, nil
+	}
+
+	if a.cfg.InstallationID == "" {
+		id, err := a.findInstallation(ctx, c)
+		if err != nil {
+			return "", err
+		}
+		a.cfg.InstallationID = id
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, c.apiURL+"/app/installations/"+a.cfg.InstallationID+"/access_tokens", []byte("{}"))
+	if err != nil {
+		return "", fmt.Errorf("creating installation token: %w", err)
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil || result.Token == "" {
+		return "", fmt.Errorf("parsing installation token response")
+	}
+
+	a.token = result.Token
+	a.expires = result.ExpiresAt
+	return a.token, nil
+}
+
+// findInstallation returns the App's installation ID when there is exactly one
+func (a *appAuth) findInstallation(ctx context.Context, c *Client) (string, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.apiURL+"/app/installations", nil)
+	if err != nil {
+		return "", fmt.Errorf("listing App installations: %w", err)
+	}
+
+	var installations []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(resp.Body, &installations); err != nil {
+		return "", fmt.Errorf("parsing App installations: %w", err)
+	}
+
+	switch len(installations) {
+	case 0:
+		return "", fmt.Errorf("GitHub App %s has no installations", a.cfg.AppID)
+	case 1:
+		return strconv.FormatInt(installations[0].ID, 10), nil
+	default:
+		return "", fmt.Errorf("GitHub App %s has %d installations: set GITHUB_APP_INSTALLATION_ID", a.cfg.AppID, len(installations))
+	}
+}
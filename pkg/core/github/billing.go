@@ -2,9 +2,9 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 )
 
@@ -79,7 +79,7 @@ func (c *Client) GetOrgActionsBilling(org string) (*BillingActions, error) {
 		return nil, fmt.Errorf("no GitHub token available - set GITHUB_TOKEN with admin:org scope")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/orgs/%s/settings/billing/actions", org)
+	url := fmt.Sprintf("/orgs/%s/settings/billing/actions", org)
 	return c.fetchBillingActions(url)
 }
 
@@ -90,7 +90,7 @@ func (c *Client) GetUserActionsBilling(username string) (*BillingActions, error)
 		return nil, fmt.Errorf("no GitHub token available")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/users/%s/settings/billing/actions", username)
+	url := fmt.Sprintf("/users/%s/settings/billing/actions", username)
 	return c.fetchBillingActions(url)
 }
 
@@ -101,7 +101,7 @@ func (c *Client) GetOrgPackagesBilling(org string) (*BillingPackages, error) {
 		return nil, fmt.Errorf("no GitHub token available - set GITHUB_TOKEN with admin:org scope")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/orgs/%s/settings/billing/packages", org)
+	url := fmt.Sprintf("/orgs/%s/settings/billing/packages", org)
 	return c.fetchBillingPackages(url)
 }
 
@@ -112,7 +112,7 @@ func (c *Client) GetOrgStorageBilling(org string) (*BillingStorage, error) {
 		return nil, fmt.Errorf("no GitHub token available - set GITHUB_TOKEN with admin:org scope")
 	}
 
-	url := fmt.Sprintf("https://api.github.com/orgs/%s/settings/billing/shared-storage", org)
+	url := fmt.Sprintf("/orgs/%s/settings/billing/shared-storage", org)
 	return c.fetchBillingStorage(url)
 }
 
@@ -192,41 +192,26 @@ func (c *Client) fetchBillingStorage(url string) (*BillingStorage, error) {
 	return &billing, nil
 }
 
-func (c *Client) doGitHubRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) doGitHubRequest(path string) ([]byte, error) {
+	resp, err := c.do(context.Background(), http.MethodGet, c.resolve(path, nil), nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("sending request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
-
-	if resp.StatusCode == 401 {
-		return nil, fmt.Errorf("authentication failed - token may be expired or invalid")
-	}
-	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("access forbidden - token needs admin:org scope for billing data")
-	}
-	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("billing data not found - requires admin:org scope. Add it with: gh auth refresh -s admin:org")
-	}
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			return nil, err
+		}
+		switch apiErr.StatusCode {
+		case 401:
+			return nil, fmt.Errorf("authentication failed - token may be expired or invalid")
+		case 403:
+			return nil, fmt.Errorf("access forbidden - token needs admin:org scope for billing data")
+		case 404:
+			return nil, fmt.Errorf("billing data not found - requires admin:org scope. Add it with: gh auth refresh -s admin:org")
+		default:
+			return nil, fmt.Errorf("GitHub API error (status %d): %s", apiErr.StatusCode, apiErr.Message)
+		}
 	}
 
-	return body, nil
+	return resp.Body, nil
 }
 
 // calculateCostEstimate computes estimated costs based on GitHub pricing
@@ -0,0 +1,90 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ============================================================================
+// ETag Response Cache
+// ============================================================================
+
+// etagCache stores GET responses on disk keyed by URL and credential so
+// repeat requests can be made conditionally (If-None-Match). A 304 response
+// does not count against the REST rate limit.
+type etagCache struct {
+	dir string
+}
+
+// cacheEntry is a cached API response
+type cacheEntry struct {
+	ETag     string    `json:"etag"`
+	Link     string    `json:"link,omitempty"` // Pagination header, not resent on 304
+	Body     []byte    `json:"body"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+func newETagCache(dir string) *etagCache {
+	if dir == "" {
+		return nil
+	}
+	return &etagCache{dir: dir}
+}
+
+// key derives a cache key from the credential and URL. Including the
+// credential keeps responses for different tokens (and their visibility of
+// private repos) separate.
+func (c *etagCache) key(auth, rawURL string) string {
+	sum := sha256.Sum256([]byte(auth + "\n" + rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *etagCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached entry for key, or nil
+func (c *etagCache) get(key string) *cacheEntry {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.ETag == "" {
+		return nil
+	}
+	return &entry
+}
+
+// put stores an entry. Failures are ignored; the cache is an optimization.
+func (c *etagCache) put(key string, entry *cacheEntry) {
+	entry.StoredAt = time.Now()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	// Write atomically so concurrent scans never read a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -2,10 +2,13 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,108 +34,174 @@ type Repository struct {
 	Fork          bool   `json:"fork"`
 }
 
-// Client provides GitHub API access
+// Client provides GitHub REST and GraphQL API access
 type Client struct {
 	token      string
+	apiURL     string
+	app        *appAuth
+	cache      *etagCache
 	httpClient *http.Client
+	sleepFn    func(time.Duration) // Overrides rate-limit sleeps in tests
 }
 
-// NewClient creates a new GitHub client
+// NewClient creates a new GitHub client for github.com. GitHub App
+// installation auth is used when GITHUB_APP_ID is set; otherwise the token
+// comes from the best available credential source.
 func NewClient() *Client {
-	// Use credentials package to get token from best available source
-	tokenInfo := credentials.GetGitHubToken()
+	opts := ClientOptions{CacheDir: DefaultCacheDir()}
 
-	return &Client{
-		token: tokenInfo.Value,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	if app, err := AppConfigFromEnv(); err == nil && app != nil {
+		opts.App = app
+	} else {
+		// Use credentials package to get token from best available source
+		opts.Token = credentials.GetGitHubToken().Value
+	}
+
+	c, err := NewClientWithOptions(opts)
+	if err != nil {
+		// Invalid App config: fall back to token auth
+		opts.App = nil
+		opts.Token = credentials.GetGitHubToken().Value
+		c, _ = NewClientWithOptions(opts)
+	}
+	return c
+}
+
+// NewClientWithOptions creates a GitHub client with explicit settings
+// (GitHub Enterprise Server, App auth, custom cache location)
+func NewClientWithOptions(opts ClientOptions) (*Client, error) {
+	c := &Client{
+		token:      opts.Token,
+		apiURL:     strings.TrimSuffix(opts.APIURL, "/"),
+		cache:      newETagCache(opts.CacheDir),
+		httpClient: opts.HTTPClient,
+	}
+	if c.apiURL == "" {
+		c.apiURL = DefaultAPIURL
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if opts.App != nil {
+		app, err := newAppAuth(opts.App)
+		if err != nil {
+			return nil, err
+		}
+		c.app = app
+		c.token = ""
 	}
+
+	return c, nil
 }
 
-// GetToken returns the current token (for external use)
+// GetToken returns the current token (for external use).
+// Returns "" for GitHub App auth, whose tokens are minted per request.
 func (c *Client) GetToken() string {
 	return c.token
 }
 
-// HasToken returns true if a token is configured
+// HasToken returns true if a token or GitHub App is configured
 func (c *Client) HasToken() bool {
-	return c.token != ""
+	return c.token != "" || c.app != nil
+}
+
+// IsApp returns true if the client authenticates as a GitHub App installation
+func (c *Client) IsApp() bool {
+	return c.app != nil
 }
 
 // ============================================================================
 // Repository Operations
 // ============================================================================
 
-// ListOrgRepos returns repositories for an organization
+// ListOrgRepos returns repositories for an organization, falling back to
+// the user's repositories when org is a user account
 func (c *Client) ListOrgRepos(org string, limit int) ([]Repository, error) {
+	return c.ListOrgReposContext(context.Background(), org, limit)
+}
+
+// ListOrgReposContext is ListOrgRepos with a caller-supplied context
+func (c *Client) ListOrgReposContext(ctx context.Context, org string, limit int) ([]Repository, error) {
 	// Default to 30 if limit is 0 or negative
 	if limit <= 0 {
 		limit = 30
 	}
 
-	// Use gh CLI for simplicity and auth handling
-	args := []string{
-		"repo", "list", org,
-		"--json", "name,nameWithOwner,sshUrl,defaultBranchRef,isPrivate,isArchived,isFork,diskUsage",
-		"--limit", fmt.Sprintf("%d", limit),
+	repos, err := c.listRepos(ctx, fmt.Sprintf("/orgs/%s/repos", url.PathEscape(org)), limit)
+	if IsNotFound(err) {
+		repos, err = c.listRepos(ctx, fmt.Sprintf("/users/%s/repos", url.PathEscape(org)), limit)
 	}
-
-	cmd := exec.Command("gh", args...)
-	out, err := cmd.Output()
 	if err != nil {
-		// Try to get stderr for more info
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("listing repos: %s", string(exitErr.Stderr))
-		}
 		return nil, fmt.Errorf("listing repos: %w", err)
 	}
 
-	var ghRepos []struct {
-		Name             string `json:"name"`
-		NameWithOwner    string `json:"nameWithOwner"`
-		SSHURL           string `json:"sshUrl"`
-		DiskUsage        int    `json:"diskUsage"` // Size in KB
-		DefaultBranchRef struct {
-			Name string `json:"name"`
-		} `json:"defaultBranchRef"`
-		IsPrivate  bool `json:"isPrivate"`
-		IsArchived bool `json:"isArchived"`
-		IsFork     bool `json:"isFork"`
-	}
+	return repos, nil
+}
 
-	if err := json.Unmarshal(out, &ghRepos); err != nil {
-		return nil, fmt.Errorf("parsing repos: %w", err)
+// RESTRepository is the REST API repository shape. Gitea serves the same
+// shape for the fields used here.
+type RESTRepository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	Size          int    `json:"size"` // Size in KB
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+	Archived      bool   `json:"archived"`
+	Fork          bool   `json:"fork"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// Repository converts the REST shape, defaulting the branch to main
+func (r RESTRepository) Repository() Repository {
+	defaultBranch := r.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+	return Repository{
+		Name:          r.Name,
+		FullName:      r.FullName,
+		NameWithOwner: r.FullName,
+		Owner:         r.Owner.Login,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		Size:          r.Size,
+		DefaultBranch: defaultBranch,
+		Private:       r.Private,
+		Archived:      r.Archived,
+		Fork:          r.Fork,
 	}
+}
 
-	repos := make([]Repository, len(ghRepos))
-	for i, r := range ghRepos {
-		parts := strings.Split(r.NameWithOwner, "/")
-		owner := ""
-		if len(parts) >= 2 {
-			owner = parts[0]
-		}
+// listRepos pages through a repository listing endpoint up to limit
+func (c *Client) listRepos(ctx context.Context, path string, limit int) ([]Repository, error) {
+	query := url.Values{}
+	query.Set("sort", "pushed")
+	if limit < 100 {
+		query.Set("per_page", strconv.Itoa(limit))
+	}
 
-		defaultBranch := r.DefaultBranchRef.Name
-		if defaultBranch == "" {
-			defaultBranch = "main"
+	var repos []Repository
+	err := c.Paginate(ctx, path, query, func(page json.RawMessage) (bool, error) {
+		var restRepos []RESTRepository
+		if err := json.Unmarshal(page, &restRepos); err != nil {
+			return false, fmt.Errorf("parsing repos: %w", err)
 		}
 
-		repos[i] = Repository{
-			Name:          r.Name,
-			FullName:      r.NameWithOwner,
-			NameWithOwner: r.NameWithOwner,
-			Owner:         owner,
-			SSHURL:        r.SSHURL,
-			Size:          r.DiskUsage,
-			DefaultBranch: defaultBranch,
-			Private:       r.IsPrivate,
-			Archived:      r.IsArchived,
-			Fork:          r.IsFork,
+		for _, r := range restRepos {
+			if len(repos) >= limit {
+				break
+			}
+			repos = append(repos, r.Repository())
 		}
-	}
+		return len(repos) < limit, nil
+	})
 
-	return repos, nil
+	return repos, err
 }
 
 // ProjectID returns the project identifier for a repo
@@ -206,99 +275,113 @@ func NewOwnershipClient(maxPRs int) *OwnershipClient {
 	}
 }
 
+// mergedPRsQuery fetches merged PRs with their reviews, most recently updated first
+const mergedPRsQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(states: MERGED, first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        author { login }
+        mergedAt
+        reviews(first: 50) {
+          nodes { author { login } state submittedAt }
+        }
+      }
+    }
+  }
+}`
+
 // FetchPRReviews fetches PR review data for a repository
 func (c *OwnershipClient) FetchPRReviews(owner, repo string) ([]PRReviewData, int, error) {
 	if !c.HasToken() {
 		return nil, 0, fmt.Errorf("no GitHub token available")
 	}
 
-	// First, get the count of merged PRs
-	countArgs := []string{
-		"pr", "list",
-		"--repo", fmt.Sprintf("%s/%s", owner, repo),
-		"--state", "merged",
-		"--json", "number",
-		"--limit", "10000", // Get count
-	}
+	ctx := context.Background()
+	var result []PRReviewData
+	var cursor interface{}
+	totalPRs := 0
 
-	countCmd := exec.Command("gh", countArgs...)
-	countOut, err := countCmd.Output()
-	if err != nil {
-		return nil, 0, fmt.Errorf("counting PRs: %w", err)
-	}
-
-	var countResult []struct{ Number int }
-	if err := json.Unmarshal(countOut, &countResult); err != nil {
-		return nil, 0, fmt.Errorf("parsing PR count: %w", err)
-	}
+	for len(result) < c.maxPRs {
+		first := c.maxPRs - len(result)
+		if first > 100 {
+			first = 100
+		}
 
-	totalPRs := len(countResult)
+		var data struct {
+			Repository struct {
+				PullRequests struct {
+					TotalCount int `json:"totalCount"`
+					PageInfo   struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						Number int    `json:"number"`
+						Title  string `json:"title"`
+						Author struct {
+							Login string `json:"login"`
+						} `json:"author"`
+						MergedAt time.Time `json:"mergedAt"`
+						Reviews  struct {
+							Nodes []struct {
+								Author struct {
+									Login string `json:"login"`
+								} `json:"author"`
+								State       string    `json:"state"`
+								SubmittedAt time.Time `json:"submittedAt"`
+							} `json:"nodes"`
+						} `json:"reviews"`
+					} `json:"nodes"`
+				} `json:"pullRequests"`
+			} `json:"repository"`
+		}
 
-	// If too many PRs, return early with warning
-	if totalPRs > c.maxPRs {
-		return nil, totalPRs, nil // Caller should check if result is nil but totalPRs > maxPRs
-	}
+		err := c.GraphQL(ctx, mergedPRsQuery, map[string]interface{}{
+			"owner": owner,
+			"name":  repo,
+			"first": first,
+			"after": cursor,
+		}, &data)
+		if err != nil {
+			return nil, 0, fmt.Errorf("fetching PRs: %w", err)
+		}
 
-	// Fetch PR details with reviews
-	args := []string{
-		"pr", "list",
-		"--repo", fmt.Sprintf("%s/%s", owner, repo),
-		"--state", "merged",
-		"--json", "number,title,author,mergedAt,reviews",
-		"--limit", fmt.Sprintf("%d", c.maxPRs),
-	}
+		prs := data.Repository.PullRequests
+		totalPRs = prs.TotalCount
 
-	cmd := exec.Command("gh", args...)
-	out, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, 0, fmt.Errorf("fetching PRs: %s", string(exitErr.Stderr))
+		// If too many PRs, return early with warning
+		if totalPRs > c.maxPRs {
+			return nil, totalPRs, nil // Caller should check if result is nil but totalPRs > maxPRs
 		}
-		return nil, 0, fmt.Errorf("fetching PRs: %w", err)
-	}
 
-	var ghPRs []struct {
-		Number int    `json:"number"`
-		Title  string `json:"title"`
-		Author struct {
-			Login string `json:"login"`
-		} `json:"author"`
-		MergedAt string `json:"mergedAt"`
-		Reviews  []struct {
-			Author struct {
-				Login string `json:"login"`
-			} `json:"author"`
-			State       string `json:"state"`
-			SubmittedAt string `json:"submittedAt"`
-		} `json:"reviews"`
-	}
-
-	if err := json.Unmarshal(out, &ghPRs); err != nil {
-		return nil, 0, fmt.Errorf("parsing PRs: %w", err)
-	}
+		// Convert to our format
+		for _, pr := range prs.Nodes {
+			reviews := make([]Review, 0, len(pr.Reviews.Nodes))
+			for _, r := range pr.Reviews.Nodes {
+				reviews = append(reviews, Review{
+					Author:      r.Author.Login,
+					State:       r.State,
+					SubmittedAt: r.SubmittedAt,
+				})
+			}
 
-	// Convert to our format
-	result := make([]PRReviewData, 0, len(ghPRs))
-	for _, pr := range ghPRs {
-		mergedAt, _ := time.Parse(time.RFC3339, pr.MergedAt)
-
-		reviews := make([]Review, 0, len(pr.Reviews))
-		for _, r := range pr.Reviews {
-			submittedAt, _ := time.Parse(time.RFC3339, r.SubmittedAt)
-			reviews = append(reviews, Review{
-				Author:      r.Author.Login,
-				State:       r.State,
-				SubmittedAt: submittedAt,
+			result = append(result, PRReviewData{
+				PRNumber: pr.Number,
+				Title:    pr.Title,
+				Author:   pr.Author.Login,
+				MergedAt: pr.MergedAt,
+				Reviews:  reviews,
 			})
 		}
 
-		result = append(result, PRReviewData{
-			PRNumber: pr.Number,
-			Title:    pr.Title,
-			Author:   pr.Author.Login,
-			MergedAt: mergedAt,
-			Reviews:  reviews,
-		})
+		if !prs.PageInfo.HasNextPage {
+			break
+		}
+		cursor = prs.PageInfo.EndCursor
 	}
 
 	return result, totalPRs, nil
@@ -310,29 +393,22 @@ func (c *OwnershipClient) ResolveTeam(org, teamSlug string) ([]TeamMember, error
 		return nil, fmt.Errorf("no GitHub token available")
 	}
 
-	// Use gh api to fetch team members
-	args := []string{
-		"api",
-		fmt.Sprintf("/orgs/%s/teams/%s/members", org, teamSlug),
-		"--jq", ".[].login",
-	}
-
-	cmd := exec.Command("gh", args...)
-	out, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("fetching team members: %s", string(exitErr.Stderr))
+	var members []TeamMember
+	path := fmt.Sprintf("/orgs/%s/teams/%s/members", url.PathEscape(org), url.PathEscape(teamSlug))
+	err := c.Paginate(context.Background(), path, nil, func(page json.RawMessage) (bool, error) {
+		var users []struct {
+			Login string `json:"login"`
 		}
-		return nil, fmt.Errorf("fetching team members: %w", err)
-	}
-
-	// Parse line-by-line output
-	logins := strings.Split(strings.TrimSpace(string(out)), "\n")
-	members := make([]TeamMember, 0, len(logins))
-	for _, login := range logins {
-		if login != "" {
-			members = append(members, TeamMember{Login: login})
+		if err := json.Unmarshal(page, &users); err != nil {
+			return false, err
+		}
+		for _, u := range users {
+			members = append(members, TeamMember{Login: u.Login})
 		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching team members: %w", err)
 	}
 
 	return members, nil
@@ -344,39 +420,29 @@ func (c *OwnershipClient) GetCollaborators(owner, repo string) ([]Collaborator,
 		return nil, fmt.Errorf("no GitHub token available")
 	}
 
-	args := []string{
-		"api",
-		fmt.Sprintf("/repos/%s/%s/collaborators", owner, repo),
-		"--jq", ".[] | {login: .login, permission: .role_name}",
-	}
-
-	cmd := exec.Command("gh", args...)
-	out, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("fetching collaborators: %s", string(exitErr.Stderr))
+	var collaborators []Collaborator
+	path := fmt.Sprintf("/repos/%s/%s/collaborators", url.PathEscape(owner), url.PathEscape(repo))
+	err := c.Paginate(context.Background(), path, nil, func(page json.RawMessage) (bool, error) {
+		var users []struct {
+			Login    string `json:"login"`
+			RoleName string `json:"role_name"`
 		}
-		return nil, fmt.Errorf("fetching collaborators: %w", err)
-	}
-
-	// Parse JSON lines
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	collaborators := make([]Collaborator, 0, len(lines))
-	for _, line := range lines {
-		if line == "" {
-			continue
+		if err := json.Unmarshal(page, &users); err != nil {
+			return false, err
 		}
-		var collab Collaborator
-		if err := json.Unmarshal([]byte(line), &collab); err != nil {
-			continue // Skip malformed lines
+		for _, u := range users {
+			collaborators = append(collaborators, Collaborator{Login: u.Login, Permission: u.RoleName})
 		}
-		collaborators = append(collaborators, collab)
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching collaborators: %w", err)
 	}
 
 	return collaborators, nil
 }
 
-// CheckUserExists verifies if a GitHub user exists
+// CheckUserExists verifies if a GitHub user (or org/team reference) exists
 func (c *OwnershipClient) CheckUserExists(username string) (bool, error) {
 	if !c.HasToken() {
 		return false, fmt.Errorf("no GitHub token available")
@@ -385,31 +451,25 @@ func (c *OwnershipClient) CheckUserExists(username string) (bool, error) {
 	// Remove @ prefix if present
 	username = strings.TrimPrefix(username, "@")
 
+	path := fmt.Sprintf("/users/%s", url.PathEscape(username))
+
 	// Handle team references
 	if strings.Contains(username, "/") {
 		parts := strings.Split(username, "/")
-		if len(parts) == 2 {
-			// This is a team reference, check if team exists
-			args := []string{
-				"api",
-				fmt.Sprintf("/orgs/%s/teams/%s", parts[0], parts[1]),
-				"--silent",
-			}
-			cmd := exec.Command("gh", args...)
-			err := cmd.Run()
-			return err == nil, nil
+		if len(parts) != 2 {
+			return false, nil
 		}
+		path = fmt.Sprintf("/orgs/%s/teams/%s", url.PathEscape(parts[0]), url.PathEscape(parts[1]))
 	}
 
-	// Check user exists
-	args := []string{
-		"api",
-		fmt.Sprintf("/users/%s", username),
-		"--silent",
+	_, err := c.Get(context.Background(), path, nil, nil)
+	if IsNotFound(err) {
+		return false, nil
 	}
-	cmd := exec.Command("gh", args...)
-	err := cmd.Run()
-	return err == nil, nil
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // AggregateReviewerStats aggregates review statistics from PR data
@@ -841,37 +901,28 @@ func (c *Client) CheckTokenPermissions() (*TokenInfo, error) {
 		Valid: false,
 	}
 
-	if c.token == "" {
+	if !c.HasToken() {
 		info.Error = "No GitHub token found (set GITHUB_TOKEN or use 'gh auth login')"
 		return info, nil
 	}
 
-	// Make a request to check the token
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
-	if err != nil {
-		info.Error = fmt.Sprintf("Failed to create request: %v", err)
-		return info, nil
+	// Installation tokens can't call /user; check the rate limit endpoint instead
+	if c.app != nil {
+		return c.checkAppPermissions(info), nil
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	resp, err := c.httpClient.Do(req)
+	// Make a request to check the token
+	resp, err := c.do(context.Background(), http.MethodGet, c.apiURL+"/user", nil)
 	if err != nil {
-		info.Error = fmt.Sprintf("Failed to connect to GitHub: %v", err)
-		return info, nil
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode == 401 {
-		info.Error = "Token is invalid or expired"
-		return info, nil
-	}
-
-	if resp.StatusCode != 200 {
-		info.Error = fmt.Sprintf("GitHub API returned status %d", resp.StatusCode)
+		if apiErr, ok := err.(*APIError); ok {
+			if apiErr.StatusCode == 401 {
+				info.Error = "Token is invalid or expired"
+			} else {
+				info.Error = fmt.Sprintf("GitHub API returned status %d", apiErr.StatusCode)
+			}
+		} else {
+			info.Error = fmt.Sprintf("Failed to connect to GitHub: %v", err)
+		}
 		return info, nil
 	}
 
@@ -881,7 +932,7 @@ func (c *Client) CheckTokenPermissions() (*TokenInfo, error) {
 	var user struct {
 		Login string `json:"login"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&user); err == nil {
+	if err := json.Unmarshal(resp.Body, &user); err == nil {
 		info.Username = user.Login
 	}
 
@@ -924,34 +975,24 @@ func (c *Client) detectFineGrainedPermissions() map[string]string {
 
 	// Test various endpoints to detect permissions
 	endpoints := map[string]struct {
-		url        string
+		path       string
 		permission string
 		level      string
 	}{
 		"repos": {
-			url:        "https://api.github.com/user/repos?per_page=1",
+			path:       "/user/repos?per_page=1",
 			permission: "contents",
 			level:      "read",
 		},
 		"orgs": {
-			url:        "https://api.github.com/user/orgs?per_page=1",
+			path:       "/user/orgs?per_page=1",
 			permission: "organization",
 			level:      "read",
 		},
 	}
 
 	for _, ep := range endpoints {
-		req, _ := http.NewRequest("GET", ep.url, nil)
-		req.Header.Set("Authorization", "Bearer "+c.token)
-		req.Header.Set("Accept", "application/vnd.github+json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			continue
-		}
-		resp.Body.Close()
-
-		if resp.StatusCode == 200 {
+		if _, err := c.Get(context.Background(), ep.path, nil, nil); err == nil {
 			perms[ep.permission] = ep.level
 		}
 	}
@@ -959,6 +1000,30 @@ func (c *Client) detectFineGrainedPermissions() map[string]string {
 	return perms
 }
 
+// checkAppPermissions validates GitHub App installation auth
+func (c *Client) checkAppPermissions(info *TokenInfo) *TokenInfo {
+	info.Type = "github-app"
+
+	var limits struct {
+		Resources struct {
+			Core struct {
+				Limit     int `json:"limit"`
+				Remaining int `json:"remaining"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if _, err := c.Get(context.Background(), "/rate_limit", nil, &limits); err != nil {
+		info.Error = fmt.Sprintf("GitHub App authentication failed: %v", err)
+		return info
+	}
+
+	info.Valid = true
+	info.Username = "app/" + c.app.cfg.AppID
+	info.RateLimit = limits.Resources.Core.Limit
+	info.RateRemaining = limits.Resources.Core.Remaining
+	return info
+}
+
 // CheckToolAvailability checks if required external tools are installed
 func CheckToolAvailability(tools []string) map[string]bool {
 	status := make(map[string]bool)
@@ -1156,35 +1221,19 @@ type AccessibleRepoSummary struct {
 
 // ListAccessibleRepos returns a detailed list of repos the token can access
 func (c *Client) ListAccessibleRepos() (*AccessibleRepoSummary, error) {
-	if c.token == "" {
+	if !c.HasToken() {
 		return nil, fmt.Errorf("no GitHub token available")
 	}
 
+	ctx := context.Background()
 	summary := &AccessibleRepoSummary{}
 
 	// Get authenticated user
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get user info: status %d", resp.StatusCode)
-	}
-
 	var user struct {
 		Login string `json:"login"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, err
+	if _, err := c.Get(ctx, "/user", nil, &user); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 	summary.User = user.Login
 
@@ -1192,101 +1241,65 @@ func (c *Client) ListAccessibleRepos() (*AccessibleRepoSummary, error) {
 	summary.PersonalRepos = c.listUserRepos(user.Login)
 	summary.TotalRepos = len(summary.PersonalRepos)
 
-	// Get organizations
-	orgsReq, err := http.NewRequest("GET", "https://api.github.com/user/orgs", nil)
-	if err != nil {
-		return summary, nil // Return partial result
-	}
-	orgsReq.Header.Set("Authorization", "Bearer "+c.token)
-	orgsReq.Header.Set("Accept", "application/vnd.github+json")
-
-	orgsResp, err := c.httpClient.Do(orgsReq)
-	if err != nil {
-		return summary, nil
-	}
-	defer orgsResp.Body.Close()
-
-	if orgsResp.StatusCode == 200 {
-		var orgs []struct {
-			Login       string `json:"login"`
-			Description string `json:"description"`
-		}
-		if err := json.NewDecoder(orgsResp.Body).Decode(&orgs); err == nil {
-			for _, org := range orgs {
-				repos := c.listOrgRepos(org.Login)
-				role, billingAccess := c.getOrgMembership(org.Login, summary.User)
-				summary.Orgs = append(summary.Orgs, AccessibleOrg{
-					Login:         org.Login,
-					Description:   org.Description,
-					Repos:         repos,
-					Role:          role,
-					BillingAccess: billingAccess,
-				})
-				summary.TotalRepos += len(repos)
-			}
+	// Get organizations (partial result on failure)
+	var orgs []struct {
+		Login       string `json:"login"`
+		Description string `json:"description"`
+	}
+	if _, err := c.Get(ctx, "/user/orgs", nil, &orgs); err == nil {
+		for _, org := range orgs {
+			repos := c.listOrgRepos(org.Login)
+			role, billingAccess := c.getOrgMembership(org.Login, summary.User)
+			summary.Orgs = append(summary.Orgs, AccessibleOrg{
+				Login:         org.Login,
+				Description:   org.Description,
+				Repos:         repos,
+				Role:          role,
+				BillingAccess: billingAccess,
+			})
+			summary.TotalRepos += len(repos)
 		}
 	}
 
 	return summary, nil
 }
 
-// listUserRepos returns repos owned by the user
+// listUserRepos returns non-archived repos owned by the authenticated user
 func (c *Client) listUserRepos(username string) []AccessibleRepo {
-	// Use gh CLI for consistency with auth
-	cmd := exec.Command("gh", "repo", "list", username,
-		"--json", "nameWithOwner,isPrivate",
-		"--limit", "100",
-		"--no-archived")
-	out, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
-	var ghRepos []struct {
-		NameWithOwner string `json:"nameWithOwner"`
-		IsPrivate     bool   `json:"isPrivate"`
-	}
-	if err := json.Unmarshal(out, &ghRepos); err != nil {
-		return nil
-	}
-
-	repos := make([]AccessibleRepo, len(ghRepos))
-	for i, r := range ghRepos {
-		repos[i] = AccessibleRepo{
-			FullName: r.NameWithOwner,
-			Private:  r.IsPrivate,
-			Owner:    username,
-		}
-	}
-	return repos
+	query := url.Values{}
+	query.Set("affiliation", "owner")
+	return c.listAccessibleRepos("/user/repos", query, username)
 }
 
-// listOrgRepos returns repos in an organization
+// listOrgRepos returns non-archived repos in an organization
 func (c *Client) listOrgRepos(org string) []AccessibleRepo {
-	cmd := exec.Command("gh", "repo", "list", org,
-		"--json", "nameWithOwner,isPrivate",
-		"--limit", "100",
-		"--no-archived")
-	out, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
+	return c.listAccessibleRepos(fmt.Sprintf("/orgs/%s/repos", url.PathEscape(org)), nil, org)
+}
 
-	var ghRepos []struct {
-		NameWithOwner string `json:"nameWithOwner"`
-		IsPrivate     bool   `json:"isPrivate"`
-	}
-	if err := json.Unmarshal(out, &ghRepos); err != nil {
-		return nil
-	}
+// listAccessibleRepos returns up to 100 non-archived repos from a listing endpoint
+func (c *Client) listAccessibleRepos(path string, query url.Values, owner string) []AccessibleRepo {
+	const limit = 100
 
-	repos := make([]AccessibleRepo, len(ghRepos))
-	for i, r := range ghRepos {
-		repos[i] = AccessibleRepo{
-			FullName: r.NameWithOwner,
-			Private:  r.IsPrivate,
-			Owner:    org,
+	var repos []AccessibleRepo
+	err := c.Paginate(context.Background(), path, query, func(page json.RawMessage) (bool, error) {
+		var restRepos []RESTRepository
+		if err := json.Unmarshal(page, &restRepos); err != nil {
+			return false, err
 		}
+		for _, r := range restRepos {
+			if r.Archived || len(repos) >= limit {
+				continue
+			}
+			repos = append(repos, AccessibleRepo{
+				FullName: r.FullName,
+				Private:  r.Private,
+				Owner:    owner,
+			})
+		}
+		return len(repos) < limit, nil
+	})
+	if err != nil {
+		return nil
 	}
 	return repos
 }
@@ -1302,31 +1315,12 @@ func (c *Client) getOrgMembership(org, username string) (role string, billingAcc
 	}
 
 	// Query the membership API: GET /orgs/{org}/memberships/{username}
-	url := fmt.Sprintf("https://api.github.com/orgs/%s/memberships/%s", org, username)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return
-	}
-
 	var membership struct {
 		Role  string `json:"role"`  // "admin" or "member"
 		State string `json:"state"` // "active" or "pending"
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&membership); err != nil {
+	path := fmt.Sprintf("/orgs/%s/memberships/%s", url.PathEscape(org), url.PathEscape(username))
+	if _, err := c.Get(context.Background(), path, nil, &membership); err != nil {
 		return
 	}
 
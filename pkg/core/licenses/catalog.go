@@ -0,0 +1,206 @@
+package licenses
+
+import "strings"
+
+// Category groups licenses by the obligations they carry
+type Category string
+
+const (
+	Permissive     Category = "permissive"      // Attribution only (MIT, Apache-2.0, BSD)
+	WeakCopyleft   Category = "weak-copyleft"   // Copyleft limited to the library itself (LGPL, MPL, EPL)
+	StrongCopyleft Category = "strong-copyleft" // Copyleft extending to the combined work (GPL, AGPL)
+	Proprietary    Category = "proprietary"     // Non-open-source or source-available terms
+	Unknown        Category = "unknown"         // Missing or unrecognized license
+)
+
+// categoryRank orders categories from least to most restrictive
+var categoryRank = map[Category]int{
+	Permissive:     0,
+	WeakCopyleft:   1,
+	Unknown:        2,
+	Proprietary:    3,
+	StrongCopyleft: 4,
+}
+
+// catalog maps SPDX license IDs to their category
+var catalog = map[string]Category{
+	// Permissive
+	"0BSD": Permissive, "AFL-3.0": Permissive, "Apache-1.0": Permissive, "Apache-1.1": Permissive,
+	"Apache-2.0": Permissive, "Artistic-2.0": Permissive, "BlueOak-1.0.0": Permissive,
+	"BSD-1-Clause": Permissive, "BSD-2-Clause": Permissive, "BSD-2-Clause-Patent": Permissive,
+	"BSD-3-Clause": Permissive, "BSD-3-Clause-Clear": Permissive, "BSD-4-Clause": Permissive,
+	"BSL-1.0": Permissive, "CC-BY-3.0": Permissive, "CC-BY-4.0": Permissive, "CC0-1.0": Permissive,
+	"curl": Permissive, "ISC": Permissive, "libpng": Permissive, "MIT": Permissive, "MIT-0": Permissive,
+	"NCSA": Permissive, "OpenSSL": Permissive, "PHP-3.01": Permissive, "PostgreSQL": Permissive,
+	"PSF-2.0": Permissive, "Python-2.0": Permissive, "Ruby": Permissive, "Unicode-3.0": Permissive,
+	"Unicode-DFS-2016": Permissive, "Unlicense": Permissive, "UPL-1.0": Permissive, "W3C": Permissive,
+	"WTFPL": Permissive, "X11": Permissive, "Zlib": Permissive, "ZPL-2.1": Permissive,
+
+	// Weak copyleft
+	"APSL-2.0": WeakCopyleft, "CC-BY-SA-4.0": WeakCopyleft, "CDDL-1.0": WeakCopyleft, "CDDL-1.1": WeakCopyleft,
+	"CPL-1.0": WeakCopyleft, "EPL-1.0": WeakCopyleft, "EPL-2.0": WeakCopyleft,
+	"LGPL-2.0-only": WeakCopyleft, "LGPL-2.0-or-later": WeakCopyleft,
+	"LGPL-2.1-only": WeakCopyleft, "LGPL-2.1-or-later": WeakCopyleft,
+	"LGPL-3.0-only": WeakCopyleft, "LGPL-3.0-or-later": WeakCopyleft,
+	"MPL-1.1": WeakCopyleft, "MPL-2.0": WeakCopyleft, "MPL-2.0-no-copyleft-exception": WeakCopyleft,
+	"MS-RL": WeakCopyleft, "OFL-1.1": WeakCopyleft,
+
+	// Strong copyleft
+	"AGPL-1.0-only": StrongCopyleft, "AGPL-1.0-or-later": StrongCopyleft,
+	"AGPL-3.0-only": StrongCopyleft, "AGPL-3.0-or-later": StrongCopyleft,
+	"CECILL-2.1": StrongCopyleft, "CPAL-1.0": StrongCopyleft, "EUPL-1.1": StrongCopyleft, "EUPL-1.2": StrongCopyleft,
+	"GFDL-1.3-only": StrongCopyleft, "GFDL-1.3-or-later": StrongCopyleft,
+	"GPL-1.0-only": StrongCopyleft, "GPL-1.0-or-later": StrongCopyleft,
+	"GPL-2.0-only": StrongCopyleft, "GPL-2.0-or-later": StrongCopyleft,
+	"GPL-3.0-only": StrongCopyleft, "GPL-3.0-or-later": StrongCopyleft,
+	"OSL-3.0": StrongCopyleft, "RPL-1.5": StrongCopyleft, "Sleepycat": StrongCopyleft, "SSPL-1.0": StrongCopyleft,
+
+	// Proprietary and source-available
+	"BUSL-1.1": Proprietary, "CC-BY-NC-4.0": Proprietary, "CC-BY-NC-SA-4.0": Proprietary,
+	"CC-BY-ND-4.0": Proprietary, "Elastic-2.0": Proprietary, "UNLICENSED": Proprietary, "LicenseRef-Proprietary": Proprietary,
+}
+
+// networkCopyleft licenses extend copyleft to users interacting with the
+// software over a network, so they apply to SaaS as well
+var networkCopyleft = map[string]bool{
+	"AGPL-1.0-only": true, "AGPL-1.0-or-later": true, "AGPL-3.0-only": true, "AGPL-3.0-or-later": true,
+	"CPAL-1.0": true, "OSL-3.0": true, "RPL-1.5": true, "SSPL-1.0": true,
+}
+
+// linkingExceptions allow linking without the license's copyleft applying
+// to the combined work
+var linkingExceptions = map[string]bool{
+	"Classpath-exception-2.0": true, "GCC-exception-3.1": true, "LLVM-exception": true,
+	"OpenJDK-assembly-exception-1.0": true, "Universal-FOSS-exception-1.0": true,
+	"Bison-exception-2.2": true, "Autoconf-exception-3.0": true, "FLTK-exception": true,
+}
+
+// gnuLicenses have deprecated bare IDs ("GPL-2.0", "GPL-2.0+") replaced by
+// -only and -or-later variants
+var gnuLicenses = map[string]bool{
+	"GPL-1.0": true, "GPL-2.0": true, "GPL-3.0": true,
+	"LGPL-2.0": true, "LGPL-2.1": true, "LGPL-3.0": true,
+	"AGPL-1.0": true, "AGPL-3.0": true, "GFDL-1.3": true,
+}
+
+// aliases maps common non-SPDX license names (lowercase) to SPDX IDs
+var aliases = map[string]string{
+	"apache 2":                        "Apache-2.0",
+	"apache 2.0":                      "Apache-2.0",
+	"apache-2":                        "Apache-2.0",
+	"apache2":                         "Apache-2.0",
+	"apache license 2.0":              "Apache-2.0",
+	"apache license, version 2.0":     "Apache-2.0",
+	"apache software license":         "Apache-2.0",
+	"the apache license, version 2.0": "Apache-2.0",
+	"the apache software license, version 2.0": "Apache-2.0",
+	"asl 2.0":                               "Apache-2.0",
+	"mit license":                           "MIT",
+	"the mit license":                       "MIT",
+	"expat":                                 "MIT",
+	"bsd license":                           "BSD-3-Clause",
+	"new bsd license":                       "BSD-3-Clause",
+	"the new bsd license":                   "BSD-3-Clause",
+	"bsd 3-clause":                          "BSD-3-Clause",
+	"bsd-3":                                 "BSD-3-Clause",
+	"3-clause bsd license":                  "BSD-3-Clause",
+	"simplified bsd license":                "BSD-2-Clause",
+	"bsd 2-clause":                          "BSD-2-Clause",
+	"bsd-2":                                 "BSD-2-Clause",
+	"isc license":                           "ISC",
+	"mozilla public license 2.0":            "MPL-2.0",
+	"mpl 2.0":                               "MPL-2.0",
+	"eclipse public license 1.0":            "EPL-1.0",
+	"eclipse public license - v 1.0":        "EPL-1.0",
+	"eclipse public license 2.0":            "EPL-2.0",
+	"eclipse public license - v 2.0":        "EPL-2.0",
+	"gplv2":                                 "GPL-2.0-only",
+	"gplv3":                                 "GPL-3.0-only",
+	"gnu general public license v3.0":       "GPL-3.0-only",
+	"gnu lesser general public license":     "LGPL-2.1-or-later",
+	"lgplv3":                                "LGPL-3.0-only",
+	"cddl + gplv2 with classpath exception": "CDDL-1.1",
+	"python software foundation license":    "PSF-2.0",
+	"boost software license 1.0":            "BSL-1.0",
+	"public domain":                         "Unlicense",
+	"proprietary":                           "LicenseRef-Proprietary",
+	"commercial":                            "LicenseRef-Proprietary",
+}
+
+// canonicalIDs maps lowercase IDs to their canonical spelling
+var canonicalIDs = func() map[string]string {
+	m := make(map[string]string, len(catalog)+len(gnuLicenses))
+	for id := range catalog {
+		m[strings.ToLower(id)] = id
+	}
+	for id := range gnuLicenses {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+// Normalize returns the canonical SPDX ID for a license ID or common name
+func Normalize(id string) string {
+	id = strings.TrimSpace(id)
+	if alias, ok := aliases[strings.ToLower(id)]; ok {
+		return alias
+	}
+	if strings.HasPrefix(id, "LicenseRef-") || strings.HasPrefix(id, "DocumentRef-") {
+		return id
+	}
+
+	orLater := strings.HasSuffix(id, "+")
+	base := strings.TrimSuffix(id, "+")
+	canonical, ok := canonicalIDs[strings.ToLower(base)]
+	if !ok {
+		return id
+	}
+	if gnuLicenses[canonical] {
+		if orLater {
+			return canonical + "-or-later"
+		}
+		return canonical + "-only"
+	}
+	if orLater {
+		return canonical + "+"
+	}
+	return canonical
+}
+
+func normalizeException(id string) string {
+	for known := range linkingExceptions {
+		if strings.EqualFold(known, id) {
+			return known
+		}
+	}
+	return id
+}
+
+// CategoryOf returns the category of a license ID. Custom LicenseRef-
+// licenses are unknown: their terms can only be judged by reading them.
+func CategoryOf(id string) Category {
+	id = Normalize(id)
+	if c, ok := catalog[strings.TrimSuffix(id, "+")]; ok {
+		return c
+	}
+	return Unknown
+}
+
+// IsNetworkCopyleft returns true for licenses whose copyleft is triggered
+// by offering the software over a network (AGPL, SSPL)
+func IsNetworkCopyleft(id string) bool {
+	return networkCopyleft[Normalize(id)]
+}
+
+// Known returns true if the license is a recognized SPDX license
+func Known(id string) bool {
+	_, ok := catalog[strings.TrimSuffix(Normalize(id), "+")]
+	return ok
+}
+
+// baseID strips the GNU -only/-or-later suffixes and "+"
+func baseID(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-only")
+	return strings.TrimSuffix(id, "-or-later")
+}
@@ -0,0 +1,258 @@
+// Package licenses parses SPDX license expressions, classifies licenses and
+// evaluates them against a distribution-aware license policy.
+// Specification: https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+package licenses
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed SPDX license expression
+type Expression interface {
+	// String returns the expression in canonical form
+	String() string
+	// Licenses returns the license IDs in the expression, in order
+	Licenses() []string
+}
+
+// License is a single license, optionally with an exception
+// ("GPL-2.0-only WITH Classpath-exception-2.0")
+type License struct {
+	ID        string
+	Exception string
+}
+
+// And requires complying with both licenses
+type And struct {
+	Left, Right Expression
+}
+
+// Or allows choosing either license
+type Or struct {
+	Left, Right Expression
+}
+
+func (l *License) String() string {
+	if l.Exception != "" {
+		return l.ID + " WITH " + l.Exception
+	}
+	return l.ID
+}
+
+func (l *License) Licenses() []string {
+	return []string{l.ID}
+}
+
+func (a *And) String() string {
+	return operand(a.Left, false) + " AND " + operand(a.Right, false)
+}
+
+func (a *And) Licenses() []string {
+	return append(a.Left.Licenses(), a.Right.Licenses()...)
+}
+
+func (o *Or) String() string {
+	return operand(o.Left, true) + " OR " + operand(o.Right, true)
+}
+
+func (o *Or) Licenses() []string {
+	return append(o.Left.Licenses(), o.Right.Licenses()...)
+}
+
+// operand parenthesizes ORs inside ANDs, which bind tighter
+func operand(e Expression, inOr bool) string {
+	if _, ok := e.(*Or); ok && !inOr {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// Parse parses an SPDX license expression. Operators are matched case
+// insensitively, license IDs are normalized to their canonical SPDX form
+// ("apache-2.0" -> "Apache-2.0", "GPL-2.0+" -> "GPL-2.0-or-later") and
+// common non-SPDX names are mapped to SPDX IDs ("Apache 2.0", "MIT/X11").
+func Parse(s string) (Expression, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	if id, ok := aliases[strings.ToLower(s)]; ok {
+		return &License{ID: id}, nil
+	}
+	// Cargo's legacy "MIT/Apache-2.0" means either
+	if strings.Contains(s, "/") && !strings.ContainsAny(s, " ()") {
+		s = strings.ReplaceAll(s, "/", " OR ")
+	}
+
+	p := &parser{tokens: tokenize(s)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", s, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("parsing %q: unexpected %q", s, p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+func tokenize(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) accept(op string) bool {
+	if strings.EqualFold(p.peek(), op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseWith()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseWith()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseWith() (Expression, error) {
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return expr, nil
+	}
+
+	token := p.peek()
+	switch strings.ToUpper(token) {
+	case "", "AND", "OR", "WITH", ")":
+		return nil, fmt.Errorf("expected a license, got %q", token)
+	}
+	p.pos++
+	license := &License{ID: Normalize(token)}
+	if p.accept("WITH") {
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("expected an exception after WITH")
+		}
+		p.pos++
+		license.Exception = normalizeException(exception)
+	}
+	return license, nil
+}
+
+// Entry is one license entry of an SBOM component: an SPDX ID, a license
+// name or an expression
+type Entry struct {
+	ID         string
+	Name       string
+	Expression string
+}
+
+// Combine combines a component's license entries into one SPDX expression;
+// multiple entries must all be met. License names that are not SPDX
+// expressions become LicenseRef- IDs (see Ref) so they remain a single term.
+func Combine(entries []Entry) string {
+	var parts []string
+	for _, e := range entries {
+		if part := entryExpression(e); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(parts[0], "("), ")")
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// entryExpression converts one license entry into an expression that can be
+// joined with others
+func entryExpression(e Entry) string {
+	name := e.Name
+	switch {
+	case e.ID != "":
+		return Normalize(e.ID)
+	case e.Expression != "":
+		if expr, err := Parse(e.Expression); err == nil {
+			return "(" + expr.String() + ")"
+		}
+		name = e.Expression
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	if expr, err := Parse(name); err == nil {
+		return "(" + expr.String() + ")"
+	}
+	return Ref(name)
+}
+
+var refInvalid = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// Ref returns the LicenseRef- ID standing for a license name that is not an
+// SPDX expression, e.g. "Acme EULA" -> "LicenseRef-Acme-EULA"
+func Ref(name string) string {
+	return "LicenseRef-" + strings.Trim(refInvalid.ReplaceAllString(strings.TrimSpace(name), "-"), "-")
+}
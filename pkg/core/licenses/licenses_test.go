@@ -0,0 +1,139 @@
+package licenses
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"MIT", "MIT"},
+		{"mit OR apache-2.0", "MIT OR Apache-2.0"},
+		{"MIT/Apache-2.0", "MIT OR Apache-2.0"},
+		{"GPL-2.0+", "GPL-2.0-or-later"},
+		{"GPL-3.0", "GPL-3.0-only"},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause"},
+		{"MIT AND Apache-2.0 OR ISC", "MIT AND Apache-2.0 OR ISC"},
+		{"GPL-2.0-only WITH classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"Apache License, Version 2.0", "Apache-2.0"},
+		{"LicenseRef-Acme-EULA", "LicenseRef-Acme-EULA"},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "MIT AND", "(MIT OR ISC", "MIT WITH", "AND MIT", "MIT ISC"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) expected error", bad)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		entries []Entry
+		want    string
+	}{
+		{[]Entry{{ID: "mit"}}, "MIT"},
+		{[]Entry{{Expression: "MIT OR Apache-2.0"}}, "MIT OR Apache-2.0"},
+		{[]Entry{{ID: "MIT"}, {Expression: "GPL-2.0-only OR ISC"}}, "MIT AND (GPL-2.0-only OR ISC)"},
+		{[]Entry{{Name: "Apache License 2.0"}}, "Apache-2.0"},
+		{[]Entry{{Name: "Acme EULA"}, {ID: "MIT"}}, "LicenseRef-Acme-EULA AND MIT"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Combine(tt.entries); got != tt.want {
+			t.Errorf("Combine(%+v) = %q, want %q", tt.entries, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	tests := map[string]Category{
+		"MIT":                    Permissive,
+		"apache-2.0":             Permissive,
+		"LGPL-2.1+":              WeakCopyleft,
+		"MPL-2.0":                WeakCopyleft,
+		"GPL-3.0":                StrongCopyleft,
+		"AGPL-3.0-or-later":      StrongCopyleft,
+		"BUSL-1.1":               Proprietary,
+		"LicenseRef-Proprietary": Proprietary,
+		"Something-Custom":       Unknown,
+	}
+	for id, want := range tests {
+		if got := CategoryOf(id); got != want {
+			t.Errorf("CategoryOf(%q) = %s, want %s", id, got, want)
+		}
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	distributed := DefaultPolicy(Distributed)
+	saas := DefaultPolicy(SaaS)
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		expression string
+		decision   Decision
+		category   Category
+	}{
+		{"permissive", distributed, "MIT", Allow, Permissive},
+		{"gpl distributed", distributed, "GPL-3.0-only", Deny, StrongCopyleft},
+		{"gpl saas", saas, "GPL-3.0-only", Allow, StrongCopyleft},
+		{"agpl saas", saas, "AGPL-3.0-only", Deny, StrongCopyleft},
+		{"lgpl distributed", distributed, "LGPL-2.1-only", Review, WeakCopyleft},
+		{"dual license picks the acceptable one", distributed, "GPL-2.0-only OR MIT", Allow, Permissive},
+		{"conjunction needs both", distributed, "MIT AND GPL-2.0-only", Deny, StrongCopyleft},
+		{"classpath exception", distributed, "GPL-2.0-only WITH Classpath-exception-2.0", Review, WeakCopyleft},
+		{"unknown", distributed, "", Review, Unknown},
+		{"unparseable", distributed, "Some (weird license", Review, Unknown},
+		{"deny list covers or-later", Policy{Distribution: SaaS, Deny: []string{"GPL-3.0"}}, "GPL-3.0-or-later", Deny, StrongCopyleft},
+		{"allow list wins over category", Policy{Allow: []string{"LGPL-*"}}, "LGPL-3.0-only", Allow, WeakCopyleft},
+		{"category override", Policy{Categories: map[Category]Decision{Unknown: Deny}}, "NOASSERTION", Deny, Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval := tt.policy.Evaluate(tt.expression)
+			if eval.Decision != tt.decision || eval.Category != tt.category {
+				t.Errorf("Evaluate(%q) = %s/%s, want %s/%s (%s)", tt.expression, eval.Decision, eval.Category, tt.decision, tt.category, eval.Reason)
+			}
+			if eval.Decision != Allow && eval.Reason == "" {
+				t.Errorf("Evaluate(%q) has no reason", tt.expression)
+			}
+		})
+	}
+}
+
+func TestWriteNotices(t *testing.T) {
+	var b strings.Builder
+	err := WriteNotices(&b, "acme", []NoticePackage{
+		{Name: "lodash", Version: "4.17.21", Purl: "pkg:npm/lodash@4.17.21", Expression: "MIT", LicenseText: "Copyright OpenJS Foundation\n\nPermission is hereby granted..."},
+		{Name: "chalk", Version: "5.3.0", Expression: "mit"},
+		{Name: "mystery", Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"acme incorporates",
+		"chalk 5.3.0 - MIT\nlodash 4.17.21 - MIT\nmystery 1.0.0 - NOASSERTION",
+		"Copyright OpenJS Foundation",
+		"MIT: https://spdx.org/licenses/MIT.html",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("notices missing %q:\n%s", want, out)
+		}
+	}
+}
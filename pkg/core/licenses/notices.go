@@ -0,0 +1,101 @@
+package licenses
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// NoticesFile is the conventional name of a third-party notices file
+const NoticesFile = "THIRD_PARTY_NOTICES.txt"
+
+// NoticePackage is a third-party package to attribute
+type NoticePackage struct {
+	Name        string
+	Version     string
+	Purl        string
+	Expression  string // SPDX expression, empty if unknown
+	LicenseText string // The package's own license file, when available
+}
+
+const (
+	heavyRule = "================================================================================"
+	lightRule = "--------------------------------------------------------------------------------"
+)
+
+// WriteNotices writes a third-party notices file: a summary of every
+// package and its license, each package's own license text where known
+// (it carries the copyright lines attribution requires), and references to
+// the standard text of the remaining licenses
+func WriteNotices(w io.Writer, product string, packages []NoticePackage) error {
+	sorted := append([]NoticePackage(nil), packages...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !strings.EqualFold(sorted[i].Name, sorted[j].Name) {
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	var b strings.Builder
+	b.WriteString("THIRD-PARTY SOFTWARE NOTICES AND INFORMATION\n\n")
+	if product != "" {
+		fmt.Fprintf(&b, "%s incorporates the third-party software listed below.\n", product)
+	} else {
+		b.WriteString("This software incorporates the third-party software listed below.\n")
+	}
+	b.WriteString("Each package is provided under the license terms shown for it.\n\n")
+
+	fmt.Fprintf(&b, "%s\nPACKAGES (%d)\n%s\n\n", heavyRule, len(sorted), heavyRule)
+	standard := make(map[string]bool)
+	for _, pkg := range sorted {
+		expression := pkg.Expression
+		if expr, err := Parse(expression); err == nil {
+			expression = expr.String()
+			if pkg.LicenseText == "" {
+				for _, id := range expr.Licenses() {
+					standard[id] = true
+				}
+			}
+		} else if expression == "" {
+			expression = "NOASSERTION"
+		}
+		fmt.Fprintf(&b, "%s %s - %s\n", pkg.Name, pkg.Version, expression)
+	}
+
+	var withText []NoticePackage
+	for _, pkg := range sorted {
+		if strings.TrimSpace(pkg.LicenseText) != "" {
+			withText = append(withText, pkg)
+		}
+	}
+	if len(withText) > 0 {
+		fmt.Fprintf(&b, "\n%s\nLICENSE TEXTS\n%s\n", heavyRule, heavyRule)
+		for _, pkg := range withText {
+			fmt.Fprintf(&b, "\n%s %s\n", pkg.Name, pkg.Version)
+			if pkg.Purl != "" {
+				fmt.Fprintf(&b, "Package: %s\n", pkg.Purl)
+			}
+			fmt.Fprintf(&b, "%s\n%s\n", lightRule, strings.TrimSpace(pkg.LicenseText))
+		}
+	}
+
+	if len(standard) > 0 {
+		ids := make([]string, 0, len(standard))
+		for id := range standard {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Fprintf(&b, "\n%s\nSTANDARD LICENSE TEXTS\n%s\n\n", heavyRule, heavyRule)
+		for _, id := range ids {
+			if strings.HasPrefix(id, "LicenseRef-") || !Known(id) {
+				fmt.Fprintf(&b, "%s: see the package's distribution for the license terms\n", id)
+				continue
+			}
+			fmt.Fprintf(&b, "%s: https://spdx.org/licenses/%s.html\n", id, strings.TrimSuffix(id, "+"))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
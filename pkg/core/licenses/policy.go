@@ -0,0 +1,178 @@
+package licenses
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Distribution is how the software using the dependencies is shipped,
+// which decides which copyleft obligations apply
+type Distribution string
+
+const (
+	// Distributed software is shipped to users (binaries, containers,
+	// libraries, on-prem installs): copyleft applies to the combined work
+	Distributed Distribution = "distributed"
+	// SaaS software only runs as a hosted service: only network copyleft
+	// (AGPL, SSPL) applies
+	SaaS Distribution = "saas"
+)
+
+// Decision is the outcome of evaluating a license against a policy
+type Decision string
+
+const (
+	Allow  Decision = "allow"
+	Review Decision = "review"
+	Deny   Decision = "deny"
+)
+
+var decisionRank = map[Decision]int{Allow: 0, Review: 1, Deny: 2}
+
+// Policy decides which licenses are acceptable. Allow, Deny and Review
+// list license IDs (globs such as "GPL-*" work; a bare GNU ID such as
+// "GPL-3.0" covers both -only and -or-later) and take precedence over
+// the per-category decisions.
+type Policy struct {
+	Distribution Distribution          `json:"distribution"`
+	Allow        []string              `json:"allow,omitempty"`
+	Deny         []string              `json:"deny,omitempty"`
+	Review       []string              `json:"review,omitempty"`
+	Categories   map[Category]Decision `json:"categories,omitempty"` // Overrides the distribution's defaults
+}
+
+// DefaultPolicy returns the category decisions for a distribution model
+func DefaultPolicy(distribution Distribution) Policy {
+	categories := map[Category]Decision{
+		Permissive:     Allow,
+		WeakCopyleft:   Review,
+		StrongCopyleft: Deny,
+		Proprietary:    Review,
+		Unknown:        Review,
+	}
+	if distribution == SaaS {
+		categories[WeakCopyleft] = Allow
+		categories[StrongCopyleft] = Allow // Network copyleft is denied separately
+	} else {
+		distribution = Distributed
+	}
+	return Policy{Distribution: distribution, Categories: categories}
+}
+
+// ParseDistribution parses a distribution model name, defaulting to
+// distributed, the stricter model
+func ParseDistribution(s string) Distribution {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "saas", "service", "hosted":
+		return SaaS
+	}
+	return Distributed
+}
+
+// Evaluation is the result of evaluating a license expression
+type Evaluation struct {
+	Expression string   `json:"expression"`
+	Decision   Decision `json:"decision"`
+	Category   Category `json:"category"`
+	Licenses   []string `json:"licenses"` // Licenses the decision rests on (the chosen side of an OR)
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// Evaluate evaluates a license expression. For OR the most acceptable
+// choice is taken; for AND every license must be acceptable. Expressions
+// that do not parse are evaluated as a single unrecognized license.
+func (p Policy) Evaluate(expression string) Evaluation {
+	expr, err := Parse(expression)
+	if err != nil {
+		if strings.TrimSpace(expression) == "" {
+			return p.leaf(&License{ID: "NOASSERTION"})
+		}
+		expr = &License{ID: strings.TrimSpace(expression)}
+	}
+	eval := p.evaluate(expr)
+	eval.Expression = expr.String()
+	return eval
+}
+
+func (p Policy) evaluate(expr Expression) Evaluation {
+	switch e := expr.(type) {
+	case *Or:
+		left, right := p.evaluate(e.Left), p.evaluate(e.Right)
+		if decisionRank[right.Decision] < decisionRank[left.Decision] ||
+			(right.Decision == left.Decision && categoryRank[right.Category] < categoryRank[left.Category]) {
+			return right
+		}
+		return left
+	case *And:
+		left, right := p.evaluate(e.Left), p.evaluate(e.Right)
+		worst, other := left, right
+		if decisionRank[right.Decision] > decisionRank[left.Decision] {
+			worst, other = right, left
+		}
+		if categoryRank[other.Category] > categoryRank[worst.Category] {
+			worst.Category = other.Category
+		}
+		worst.Licenses = append(append([]string{}, left.Licenses...), right.Licenses...)
+		return worst
+	case *License:
+		return p.leaf(e)
+	}
+	return Evaluation{Decision: Review, Category: Unknown}
+}
+
+func (p Policy) leaf(l *License) Evaluation {
+	eval := Evaluation{
+		Expression: l.String(),
+		Category:   CategoryOf(l.ID),
+		Licenses:   []string{l.ID},
+	}
+	if l.Exception != "" && linkingExceptions[l.Exception] && eval.Category == StrongCopyleft {
+		eval.Category = WeakCopyleft
+	}
+
+	switch {
+	case matchesAny(p.Deny, l.ID):
+		eval.Decision, eval.Reason = Deny, fmt.Sprintf("%s is denied by policy", l.ID)
+	case matchesAny(p.Allow, l.ID):
+		eval.Decision, eval.Reason = Allow, fmt.Sprintf("%s is allowed by policy", l.ID)
+	case matchesAny(p.Review, l.ID):
+		eval.Decision, eval.Reason = Review, fmt.Sprintf("%s requires review by policy", l.ID)
+	case p.Distribution == SaaS && networkCopyleft[l.ID]:
+		eval.Decision, eval.Reason = Deny, fmt.Sprintf("%s is network copyleft, which applies to SaaS", l.ID)
+	default:
+		decision, ok := p.Categories[eval.Category]
+		if !ok {
+			decision = DefaultPolicy(p.Distribution).Categories[eval.Category]
+		}
+		eval.Decision = decision
+		if decision != Allow {
+			eval.Reason = fmt.Sprintf("%s is %s (%s for %s software)", l.ID, eval.Category, decision, p.distribution())
+		}
+	}
+	return eval
+}
+
+func (p Policy) distribution() Distribution {
+	if p.Distribution == "" {
+		return Distributed
+	}
+	return p.Distribution
+}
+
+// matchesAny returns true if a license ID matches one of the policy entries
+func matchesAny(entries []string, id string) bool {
+	for _, entry := range entries {
+		if strings.EqualFold(entry, id) {
+			return true
+		}
+		if ok, _ := path.Match(entry, id); ok {
+			return true
+		}
+		// A bare GNU ID covers both the -only and -or-later variants
+		if gnuLicenses[entry] && baseID(id) == entry {
+			return true
+		}
+	}
+	return false
+}
@@ -5,19 +5,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
-// Client is a generic API client with caching and rate limiting
+// Client is a generic API client with caching and rate limiting.
+// Cached requests are also persisted to the Store (or the shared store set
+// by UseStore) under the cache policy of the client's Endpoint.
 type Client struct {
 	BaseURL     string
+	Endpoint    string
 	HTTPClient  *http.Client
 	Cache       *Cache
+	Store       *Store
 	RateLimiter *RateLimiter
 	UserAgent   string
+	Accept      string // Accept header (default: application/json)
+
+	refreshing sync.Map // store keys being revalidated in the background
 }
 
 // ClientOption configures a Client
@@ -44,6 +53,27 @@ func WithRateLimit(requestsPerSecond int) ClientOption {
 	}
 }
 
+// WithEndpoint sets the endpoint whose cache policy applies to stored responses
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.Endpoint = endpoint
+	}
+}
+
+// WithStore persists cached responses to s instead of the shared store
+func WithStore(s *Store) ClientOption {
+	return func(c *Client) {
+		c.Store = s
+	}
+}
+
+// WithAccept sets the Accept header, for endpoints that don't return JSON
+func WithAccept(mime string) ClientOption {
+	return func(c *Client) {
+		c.Accept = mime
+	}
+}
+
 // WithUserAgent sets the User-Agent header
 func WithUserAgent(ua string) ClientOption {
 	return func(c *Client) {
@@ -82,33 +112,152 @@ func (c *Client) Post(ctx context.Context, path string, body any, result any) er
 
 // Query performs a cached, rate-limited API query (POST)
 func (c *Client) Query(ctx context.Context, path string, body any, result any) error {
-	// Check cache first
-	cacheKey := c.cacheKey(path, body)
+	respBody, err := c.cachedRequest(ctx, "POST", path, body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+// CachedGet performs a cached, rate-limited GET request
+func (c *Client) CachedGet(ctx context.Context, path string, result any) error {
+	respBody, err := c.cachedRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+// CachedGetRaw performs a cached, rate-limited GET request and returns the
+// response body, for endpoints that don't return JSON
+func (c *Client) CachedGetRaw(ctx context.Context, path string) ([]byte, error) {
+	return c.cachedRequest(ctx, "GET", path, nil)
+}
+
+// cachedRequest serves a request from memory or the store when it can and
+// fetches it otherwise. Stored responses past their TTL are served while
+// being refreshed in the background, when fetching fails, and offline.
+func (c *Client) cachedRequest(ctx context.Context, method, path string, body any) ([]byte, error) {
+	rec := statsFrom(ctx)
 	if c.Cache != nil {
-		if cached, ok := c.Cache.Get(cacheKey); ok {
-			return json.Unmarshal(cached, result)
+		if cached, ok := c.Cache.Get(c.cacheKey(path, body)); ok {
+			rec.hit()
+			return cached, nil
 		}
 	}
 
-	// Wait for rate limiter
+	stored, fresh := c.lookup(ctx, method, path, body)
+	if stored != nil {
+		policy := PolicyFor(c.Endpoint)
+		switch {
+		case fresh:
+			rec.hit()
+			return stored.result()
+		case Offline():
+			rec.staleHit()
+			return stored.result()
+		case time.Since(stored.fetchedAt) <= policy.TTL+policy.StaleFor:
+			rec.staleHit()
+			c.revalidate(method, path, body)
+			return stored.result()
+		}
+	}
+
+	if Offline() {
+		rec.miss()
+		return nil, ErrOffline
+	}
+
+	respBody, err := c.fetch(ctx, method, path, body)
+	if err != nil {
+		if stored != nil && servableOnError(ctx, err) {
+			rec.staleHit()
+			return stored.result()
+		}
+		return nil, err
+	}
+	rec.fetch()
+	return respBody, nil
+}
+
+// lookup returns the stored response for a request, if any, and whether it
+// is within its TTL. Fresh successful responses are also kept in memory.
+func (c *Client) lookup(ctx context.Context, method, path string, body any) (*entry, bool) {
+	store := c.store()
+	if store == nil {
+		return nil, false
+	}
+	stored, ok := store.get(ctx, c.storeKey(method, path, body))
+	if !ok {
+		return nil, false
+	}
+	fresh := time.Since(stored.fetchedAt) <= PolicyFor(c.Endpoint).TTL
+	if fresh && c.Cache != nil && stored.status == http.StatusOK {
+		c.Cache.Set(c.cacheKey(path, body), stored.body)
+	}
+	return stored, fresh
+}
+
+// servableOnError returns true if a stored response may stand in for a
+// failed request. A not-found answer is authoritative; anything else
+// (network errors, rate limiting, outages) falls back to what we have.
+func servableOnError(ctx context.Context, err error) bool {
+	var apiErr *APIError
+	return ctx.Err() == nil && !(errors.As(err, &apiErr) && apiErr.IsNotFound())
+}
+
+// fetch makes a rate-limited request and caches its response. Not-found
+// responses are stored as well, so lookups of packages that don't exist
+// (as in dependency confusion checks) aren't repeated on every run.
+func (c *Client) fetch(ctx context.Context, method, path string, body any) ([]byte, error) {
 	if c.RateLimiter != nil {
 		if err := c.RateLimiter.Wait(ctx); err != nil {
-			return fmt.Errorf("rate limit: %w", err)
+			return nil, fmt.Errorf("rate limit: %w", err)
 		}
 	}
 
-	// Make request
-	respBody, err := c.doRequestRaw(ctx, "POST", path, body)
-	if err != nil {
-		return err
+	respBody, err := c.doRequestRaw(ctx, method, path, body)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		c.remember(ctx, method, path, body, http.StatusOK, respBody)
+	case errors.As(err, &apiErr) && apiErr.IsNotFound():
+		c.remember(ctx, method, path, body, apiErr.StatusCode, []byte(apiErr.Body))
 	}
+	return respBody, err
+}
 
-	// Cache the response
-	if c.Cache != nil {
-		c.Cache.Set(cacheKey, respBody)
+// remember caches a response in memory and in the store
+func (c *Client) remember(ctx context.Context, method, path string, body any, status int, respBody []byte) {
+	if c.Cache != nil && status == http.StatusOK {
+		c.Cache.Set(c.cacheKey(path, body), respBody)
 	}
+	if store := c.store(); store != nil {
+		// Failing to persist only costs a request on the next run
+		_ = store.put(ctx, c.storeKey(method, path, body), c.Endpoint, entry{status: status, body: respBody, fetchedAt: time.Now()})
+	}
+}
 
-	return json.Unmarshal(respBody, result)
+// revalidate refreshes a stale response in the background, once at a time
+// per request
+func (c *Client) revalidate(method, path string, body any) {
+	key := c.storeKey(method, path, body)
+	if _, busy := c.refreshing.LoadOrStore(key, true); busy {
+		return
+	}
+	go func() {
+		defer c.refreshing.Delete(key)
+		ctx, cancel := context.WithTimeout(context.Background(), c.HTTPClient.Timeout)
+		defer cancel()
+		_, _ = c.fetch(ctx, method, path, body)
+	}()
+}
+
+func (c *Client) store() *Store {
+	if c.Store != nil {
+		return c.Store
+	}
+	return SharedStore()
 }
 
 func (c *Client) doRequest(ctx context.Context, method, path string, body any, result any) error {
@@ -123,6 +272,9 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body any, r
 }
 
 func (c *Client) doRequestRaw(ctx context.Context, method, path string, body any) ([]byte, error) {
+	if Offline() {
+		return nil, ErrOffline
+	}
 	url := c.BaseURL + path
 
 	var reqBody io.Reader
@@ -140,7 +292,11 @@ func (c *Client) doRequestRaw(ctx context.Context, method, path string, body any
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	accept := "application/json"
+	if c.Accept != "" {
+		accept = c.Accept
+	}
+	req.Header.Set("Accept", accept)
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
@@ -172,6 +328,12 @@ func (c *Client) cacheKey(path string, body any) string {
 	return path + ":" + string(data)
 }
 
+// storeKey identifies a request in the store, which is shared by clients of
+// different APIs
+func (c *Client) storeKey(method, path string, body any) string {
+	return method + " " + c.BaseURL + c.cacheKey(path, body)
+}
+
 // APIError represents an API error response
 type APIError struct {
 	StatusCode int
@@ -2,10 +2,11 @@ package liveapi
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"time"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
 )
 
 // Pre-approved URL for deps.dev API
@@ -22,6 +23,7 @@ func NewDepsDevClient() *DepsDevClient {
 		Client: NewClient(DepsDevBaseURL,
 			WithTimeout(30*time.Second),
 			WithCache(24*time.Hour), // Scorecard updates weekly, cache longer than OSV
+			WithEndpoint(EndpointDepsDev),
 			WithRateLimit(10),
 			WithUserAgent("Zero-Scanner/1.0 (deps.dev Query)"),
 		),
@@ -34,6 +36,7 @@ func NewDepsDevClientWithTimeout(timeout time.Duration) *DepsDevClient {
 		Client: NewClient(DepsDevBaseURL,
 			WithTimeout(timeout),
 			WithCache(24*time.Hour),
+			WithEndpoint(EndpointDepsDev),
 			WithRateLimit(10),
 			WithUserAgent("Zero-Scanner/1.0 (deps.dev Query)"),
 		),
@@ -156,37 +159,6 @@ func NormalizeEcosystem(ecosystem string) string {
 	return ecosystem
 }
 
-// CachedGet performs a cached GET request
-func (c *DepsDevClient) CachedGet(ctx context.Context, path string, result any) error {
-	// Check cache first
-	cacheKey := c.cacheKey(path, nil)
-	if c.Cache != nil {
-		if cached, ok := c.Cache.Get(cacheKey); ok {
-			return json.Unmarshal(cached, result)
-		}
-	}
-
-	// Wait for rate limiter
-	if c.RateLimiter != nil {
-		if err := c.RateLimiter.Wait(ctx); err != nil {
-			return fmt.Errorf("rate limit: %w", err)
-		}
-	}
-
-	// Make request
-	respBody, err := c.doRequestRaw(ctx, "GET", path, nil)
-	if err != nil {
-		return err
-	}
-
-	// Cache the response
-	if c.Cache != nil {
-		c.Cache.Set(cacheKey, respBody)
-	}
-
-	return json.Unmarshal(respBody, result)
-}
-
 // GetVersionDetails retrieves detailed information about a specific package version
 func (c *DepsDevClient) GetVersionDetails(ctx context.Context, ecosystem, name, version string) (*VersionDetails, error) {
 	system := NormalizeEcosystem(ecosystem)
@@ -230,6 +202,45 @@ func (c *DepsDevClient) GetPackageVersions(ctx context.Context, ecosystem, name
 	return &info, nil
 }
 
+// DependencyGraph is the resolved dependency graph of a package version.
+// Node 0 is the package itself.
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+	Error string           `json:"error,omitempty"`
+}
+
+// DependencyNode is a package version in a dependency graph
+type DependencyNode struct {
+	VersionKey VersionKey `json:"versionKey"`
+	Bundled    bool       `json:"bundled"`
+	Relation   string     `json:"relation"` // SELF, DIRECT or INDIRECT
+	Errors     []string   `json:"errors,omitempty"`
+}
+
+// DependencyEdge is a requirement between two nodes of a dependency graph
+type DependencyEdge struct {
+	FromNode    int    `json:"fromNode"`
+	ToNode      int    `json:"toNode"`
+	Requirement string `json:"requirement"`
+}
+
+// GetDependencies retrieves the resolved dependency graph of a package version
+func (c *DepsDevClient) GetDependencies(ctx context.Context, ecosystem, name, version string) (*DependencyGraph, error) {
+	system := NormalizeEcosystem(ecosystem)
+	path := fmt.Sprintf("/systems/%s/packages/%s/versions/%s:dependencies",
+		url.PathEscape(system),
+		url.PathEscape(name),
+		url.PathEscape(version))
+
+	var graph DependencyGraph
+	if err := c.CachedGet(ctx, path, &graph); err != nil {
+		return nil, err
+	}
+
+	return &graph, nil
+}
+
 // IsDeprecated checks if a specific package version is deprecated
 func (c *DepsDevClient) IsDeprecated(ctx context.Context, ecosystem, name, version string) (bool, error) {
 	details, err := c.GetVersionDetails(ctx, ecosystem, name, version)
@@ -298,14 +309,16 @@ func (c *DepsDevClient) GetLatestVersion(ctx context.Context, ecosystem, name st
 	return "", fmt.Errorf("no versions found for %s/%s", ecosystem, name)
 }
 
-// IsOutdated checks if a package version is outdated compared to the latest
+// IsOutdated checks if a package version is older than the latest, using
+// the ecosystem's version ordering (a version ahead of the default, such
+// as a prerelease, is not outdated)
 func (c *DepsDevClient) IsOutdated(ctx context.Context, ecosystem, name, version string) (bool, string, error) {
 	latest, err := c.GetLatestVersion(ctx, ecosystem, name)
 	if err != nil {
 		return false, "", err
 	}
 
-	return version != latest, latest, nil
+	return versions.Compare(versions.ParseEcosystem(ecosystem), version, latest) < 0, latest, nil
 }
 
 // DefaultDepsDevClient is the default deps.dev client instance
@@ -234,6 +234,43 @@ func TestGetPackageVersions(t *testing.T) {
 	}
 }
 
+func TestGetDependencies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedPath := "/systems/NPM/packages/mkdirp/versions/0.5.6:dependencies"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Request path = %q, want %q", r.URL.Path, expectedPath)
+		}
+
+		response := DependencyGraph{
+			Nodes: []DependencyNode{
+				{VersionKey: VersionKey{System: "NPM", Name: "mkdirp", Version: "0.5.6"}, Relation: "SELF"},
+				{VersionKey: VersionKey{System: "NPM", Name: "minimist", Version: "1.2.8"}, Relation: "DIRECT"},
+			},
+			Edges: []DependencyEdge{{FromNode: 0, ToNode: 1, Requirement: "^1.2.6"}},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := &DepsDevClient{
+		Client: NewClient(server.URL, WithTimeout(5*time.Second)),
+	}
+
+	graph, err := client.GetDependencies(context.Background(), "npm", "mkdirp", "0.5.6")
+	if err != nil {
+		t.Fatalf("GetDependencies() error = %v", err)
+	}
+
+	if len(graph.Nodes) != 2 || graph.Nodes[1].VersionKey.Version != "1.2.8" {
+		t.Errorf("Nodes = %+v", graph.Nodes)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].Requirement != "^1.2.6" {
+		t.Errorf("Edges = %+v", graph.Edges)
+	}
+}
+
 func TestIsDeprecated(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2,6 +2,7 @@ package liveapi
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -255,16 +256,138 @@ func TestVulnerability_GetFixedVersion(t *testing.T) {
 		},
 	}
 
-	fixed := v.GetFixedVersion("npm", "lodash")
+	fixed := v.GetFixedVersion("npm", "lodash", "")
 	if fixed != "4.17.21" {
 		t.Errorf("GetFixedVersion() = %q, want %q", fixed, "4.17.21")
 	}
 
 	// Non-matching package should return empty
-	fixed = v.GetFixedVersion("npm", "express")
+	fixed = v.GetFixedVersion("npm", "express", "")
 	if fixed != "" {
 		t.Errorf("GetFixedVersion() for non-matching package = %q, want empty", fixed)
 	}
+
+	// With the installed version, the fix is the smallest unaffected
+	// version above it, ordered the way the ecosystem orders versions
+	v = &Vulnerability{
+		Affected: []Affected{
+			{
+				Package: Package{Name: "django", Ecosystem: "PyPI"},
+				Ranges: []Range{
+					{
+						Type: "ECOSYSTEM",
+						Events: []Event{
+							{Introduced: "4.2"}, {Fixed: "4.2.10"},
+							{Introduced: "0"}, {Fixed: "3.2.24"},
+							{Introduced: "5.0a1"}, {Fixed: "5.0.2"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for version, want := range map[string]string{"3.2.1": "3.2.24", "4.2.9": "4.2.10", "5.0rc1": "5.0.2"} {
+		if fixed := v.GetFixedVersion("pypi", "django", version); fixed != want {
+			t.Errorf("GetFixedVersion(%s) = %q, want %q", version, fixed, want)
+		}
+	}
+}
+
+func TestVulnerability_GetAffectedSymbols(t *testing.T) {
+	var v Vulnerability
+	record := `{"affected": [
+		{"package": {"ecosystem": "npm", "name": "lodash"}, "ecosystem_specific": {"affected_functions": ["lodash.template", "template"]}},
+		{"package": {"ecosystem": "Go", "name": "golang.org/x/net"}, "ecosystem_specific": {"imports": [{"path": "golang.org/x/net/html", "symbols": ["Parse"]}]}}
+	]}`
+	if err := json.Unmarshal([]byte(record), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := v.GetAffectedSymbols("npm", "lodash"); len(got) != 2 || got[0] != "lodash.template" {
+		t.Errorf("GetAffectedSymbols(lodash) = %v", got)
+	}
+	if got := v.GetAffectedSymbols("golang", "golang.org/x/net"); len(got) != 1 || got[0] != "golang.org/x/net/html.Parse" {
+		t.Errorf("GetAffectedSymbols(x/net) = %v", got)
+	}
+	if got := v.GetAffectedSymbols("npm", "express"); got != nil {
+		t.Errorf("GetAffectedSymbols(express) = %v", got)
+	}
+}
+
+func TestAffected_Contains(t *testing.T) {
+	newRange := func(typ string, events ...Event) Range {
+		return Range{Type: typ, Events: events}
+	}
+	tests := []struct {
+		name     string
+		affected Affected
+		version  string
+		want     bool
+	}{
+		{
+			name: "pep440 prerelease before fix",
+			affected: Affected{Package: Package{Ecosystem: "PyPI"}, Ranges: []Range{
+				newRange("ECOSYSTEM", Event{Introduced: "0"}, Event{Fixed: "2.0"})}},
+			version: "2.0rc1",
+			want:    true,
+		},
+		{
+			name: "pep440 post release after fix",
+			affected: Affected{Package: Package{Ecosystem: "PyPI"}, Ranges: []Range{
+				newRange("ECOSYSTEM", Event{Introduced: "0"}, Event{Fixed: "2.0"})}},
+			version: "2.0.post1",
+			want:    false,
+		},
+		{
+			name: "npm multiple intervals",
+			affected: Affected{Package: Package{Ecosystem: "npm"}, Ranges: []Range{
+				newRange("SEMVER", Event{Introduced: "2.0.0"}, Event{Fixed: "2.5.0"},
+					Event{Fixed: "1.9.1"}, Event{Introduced: "1.0.0"})}},
+			version: "1.10.0",
+			want:    false,
+		},
+		{
+			name: "maven last affected",
+			affected: Affected{Package: Package{Ecosystem: "Maven"}, Ranges: []Range{
+				newRange("ECOSYSTEM", Event{Introduced: "2.0-beta9"}, Event{LastAffected: "2.14.1"})}},
+			version: "2.14.1",
+			want:    true,
+		},
+		{
+			name: "maven qualifier ordering",
+			affected: Affected{Package: Package{Ecosystem: "Maven"}, Ranges: []Range{
+				newRange("ECOSYSTEM", Event{Introduced: "2.0-beta9"}, Event{Fixed: "2.15.0"})}},
+			version: "2.0-alpha1",
+			want:    false,
+		},
+		{
+			name: "rubygems prerelease",
+			affected: Affected{Package: Package{Ecosystem: "RubyGems"}, Ranges: []Range{
+				newRange("ECOSYSTEM", Event{Introduced: "7.0.0"}, Event{Fixed: "7.0.4.1"})}},
+			version: "7.0.4",
+			want:    true,
+		},
+		{
+			name:     "explicit versions",
+			affected: Affected{Package: Package{Ecosystem: "crates.io"}, Versions: []string{"0.1.0", "0.1.1"}},
+			version:  "0.1.1",
+			want:     true,
+		},
+		{
+			name: "git ranges ignored",
+			affected: Affected{Package: Package{Ecosystem: "npm"}, Ranges: []Range{
+				newRange("GIT", Event{Introduced: "0"}, Event{Fixed: "abc123"})}},
+			version: "1.0.0",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.affected.Contains(tt.version); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
 }
 
 func TestVulnerability_GetHighestSeverity(t *testing.T) {
@@ -283,7 +406,22 @@ func TestVulnerability_GetHighestSeverity(t *testing.T) {
 			severity: []OSVSeverity{
 				{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"},
 			},
-			want: "medium", // parseCVSSSeverity returns "medium" as default
+			want: "critical",
+		},
+		{
+			name: "highest of CVSS_V3 and CVSS_V4",
+			severity: []OSVSeverity{
+				{Type: "CVSS_V3", Score: "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N"},
+				{Type: "CVSS_V4", Score: "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"},
+			},
+			want: "critical",
+		},
+		{
+			name: "invalid vector",
+			severity: []OSVSeverity{
+				{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N"},
+			},
+			want: "unknown",
 		},
 		{
 			name: "without CVSS_V3",
@@ -0,0 +1,90 @@
+package liveapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// ErrOffline is returned in offline mode for requests with no stored response
+var ErrOffline = errors.New("offline: no cached response")
+
+var offline atomic.Bool
+
+// SetOffline switches every client to serving stored responses only
+func SetOffline(v bool) {
+	offline.Store(v)
+}
+
+// Offline returns true if clients may only serve stored responses, set by
+// SetOffline or the ZERO_OFFLINE environment variable
+func Offline() bool {
+	if offline.Load() {
+		return true
+	}
+	v, _ := strconv.ParseBool(os.Getenv("ZERO_OFFLINE"))
+	return v
+}
+
+// CacheStats counts how cached requests were served
+type CacheStats struct {
+	Hits        int64 `json:"hits"`        // Fresh responses from the cache
+	Fetched     int64 `json:"fetched"`     // Responses fetched from the network
+	Stale       int64 `json:"stale"`       // Responses served past their TTL
+	Unavailable int64 `json:"unavailable"` // Offline requests with no stored response
+}
+
+// StatsRecorder collects CacheStats for the requests made with a context
+type StatsRecorder struct {
+	hits, fetched, stale, unavailable atomic.Int64
+}
+
+// Stats returns the counts recorded so far
+func (r *StatsRecorder) Stats() CacheStats {
+	return CacheStats{
+		Hits:        r.hits.Load(),
+		Fetched:     r.fetched.Load(),
+		Stale:       r.stale.Load(),
+		Unavailable: r.unavailable.Load(),
+	}
+}
+
+// The recording methods accept a nil recorder, for contexts without one
+
+func (r *StatsRecorder) hit() {
+	if r != nil {
+		r.hits.Add(1)
+	}
+}
+
+func (r *StatsRecorder) fetch() {
+	if r != nil {
+		r.fetched.Add(1)
+	}
+}
+
+func (r *StatsRecorder) staleHit() {
+	if r != nil {
+		r.stale.Add(1)
+	}
+}
+
+func (r *StatsRecorder) miss() {
+	if r != nil {
+		r.unavailable.Add(1)
+	}
+}
+
+type statsKey struct{}
+
+// WithStatsRecorder returns a context whose cached requests are counted by r
+func WithStatsRecorder(ctx context.Context, r *StatsRecorder) context.Context {
+	return context.WithValue(ctx, statsKey{}, r)
+}
+
+func statsFrom(ctx context.Context) *StatsRecorder {
+	r, _ := ctx.Value(statsKey{}).(*StatsRecorder)
+	return r
+}
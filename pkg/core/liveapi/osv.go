@@ -2,7 +2,13 @@ package liveapi
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
 	"time"
+
+	"github.com/crashappsec/zero/pkg/core/cvss"
+	"github.com/crashappsec/zero/pkg/core/versions"
 )
 
 // Pre-approved URL for OSV API
@@ -19,6 +25,7 @@ func NewOSVClient() *OSVClient {
 		Client: NewClient(OSVBaseURL,
 			WithTimeout(30*time.Second),
 			WithCache(15*time.Minute),
+			WithEndpoint(EndpointOSV),
 			WithRateLimit(10),
 			WithUserAgent("Zero-Scanner/1.0 (OSV Query)"),
 		),
@@ -31,6 +38,7 @@ func NewOSVClientWithTimeout(timeout time.Duration) *OSVClient {
 		Client: NewClient(OSVBaseURL,
 			WithTimeout(timeout),
 			WithCache(15*time.Minute),
+			WithEndpoint(EndpointOSV),
 			WithRateLimit(10),
 			WithUserAgent("Zero-Scanner/1.0 (OSV Query)"),
 		),
@@ -155,51 +163,116 @@ func (c *OSVClient) QueryPURL(ctx context.Context, purl string) ([]Vulnerability
 	return resp.Vulns, nil
 }
 
-// QueryBatch queries multiple packages at once
+// batchItemPath keys the stored result of one query in a batch. Results are
+// cached per package so batches that differ in a few packages (such as the
+// repositories of an org) reuse each other's results.
+const batchItemPath = "/querybatch#query"
+
+// QueryBatch queries multiple packages at once. Only queries without a
+// fresh cached result are sent; offline, cached results are returned and
+// ErrOffline only if none are cached.
 func (c *OSVClient) QueryBatch(ctx context.Context, queries []QueryRequest) ([]QueryResponse, error) {
-	req := BatchQueryRequest{
-		Queries: queries,
+	rec := statsFrom(ctx)
+	results := make([]QueryResponse, len(queries))
+	stored := make(map[int]*entry)
+	var pending []int
+	for i, q := range queries {
+		e, fresh := c.lookup(ctx, "POST", batchItemPath, q)
+		if e != nil && (fresh || Offline()) && json.Unmarshal(e.body, &results[i]) == nil {
+			if fresh {
+				rec.hit()
+			} else {
+				rec.staleHit()
+			}
+			continue
+		}
+		if e != nil {
+			stored[i] = e
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	if Offline() {
+		for range pending {
+			rec.miss()
+		}
+		if len(pending) == len(queries) {
+			return nil, ErrOffline
+		}
+		return results, nil
+	}
+
+	req := BatchQueryRequest{Queries: make([]QueryRequest, len(pending))}
+	for j, i := range pending {
+		req.Queries[j] = queries[i]
 	}
 
 	var resp BatchQueryResponse
 	if err := c.Post(ctx, "/querybatch", req, &resp); err != nil {
-		return nil, err
+		// Fall back to stale results if every pending query has one
+		if len(stored) < len(pending) || !servableOnError(ctx, err) {
+			return nil, err
+		}
+		for _, i := range pending {
+			if json.Unmarshal(stored[i].body, &results[i]) != nil {
+				return nil, err
+			}
+			rec.staleHit()
+		}
+		return results, nil
 	}
 
-	return resp.Results, nil
+	for j, i := range pending {
+		if j >= len(resp.Results) {
+			break
+		}
+		results[i] = resp.Results[j]
+		if data, err := json.Marshal(resp.Results[j]); err == nil {
+			c.remember(ctx, "POST", batchItemPath, queries[i], http.StatusOK, data)
+		}
+		rec.fetch()
+	}
+	return results, nil
 }
 
 // GetVulnerability retrieves a specific vulnerability by ID
 func (c *OSVClient) GetVulnerability(ctx context.Context, id string) (*Vulnerability, error) {
 	var vuln Vulnerability
-	if err := c.Get(ctx, "/vulns/"+id, &vuln); err != nil {
+	if err := c.CachedGet(ctx, "/vulns/"+id, &vuln); err != nil {
 		return nil, err
 	}
 	return &vuln, nil
 }
 
-// GetHighestSeverity returns the highest severity from vulnerability severity list
+// GetHighestSeverity returns the severity rating of the highest scoring
+// CVSS vector, or "unknown" if there is none
 func (v *Vulnerability) GetHighestSeverity() string {
-	// Check severity array first
-	for _, sev := range v.Severity {
-		if sev.Type == "CVSS_V3" {
-			// Parse CVSS score
-			// CVSS v3 format: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
-			return parseCVSSSeverity(sev.Score)
-		}
+	if vec := v.HighestCVSS(); vec != nil {
+		return vec.Severity()
 	}
 	return "unknown"
 }
 
-func parseCVSSSeverity(score string) string {
-	// Very simplified - just look for common indicators
-	// A real implementation would parse the CVSS vector
-	if len(score) == 0 {
-		return "unknown"
+// HighestCVSS returns the highest scoring CVSS v3 or v4 vector, or nil if
+// the vulnerability has none that parse
+func (v *Vulnerability) HighestCVSS() *cvss.Vector {
+	var highest *cvss.Vector
+	for _, sev := range v.Severity {
+		if sev.Type != "CVSS_V3" && sev.Type != "CVSS_V4" {
+			continue
+		}
+		vec, err := cvss.Parse(sev.Score)
+		if err != nil {
+			continue
+		}
+		if highest == nil || vec.Score() > highest.Score() {
+			highest = vec
+		}
 	}
-	// If it's just a number, map to severity
-	// 9.0-10.0 = critical, 7.0-8.9 = high, 4.0-6.9 = medium, 0.1-3.9 = low
-	return "medium" // Default
+	return highest
 }
 
 // GetCVEs returns CVE aliases from the vulnerability
@@ -213,20 +286,189 @@ func (v *Vulnerability) GetCVEs() []string {
 	return cves
 }
 
-// GetFixedVersion returns the fixed version if available
-func (v *Vulnerability) GetFixedVersion(ecosystem, name string) string {
+// GetFixedVersion returns the version that fixes the vulnerability for a
+// package at the given version: the smallest fixed version above it that no
+// affected range includes. With no version it returns the first fix.
+func (v *Vulnerability) GetFixedVersion(ecosystem, name, version string) string {
+	eco := versions.ParseEcosystem(ecosystem)
+	var best string
+	for _, affected := range v.Affected {
+		if versions.ParseEcosystem(affected.Package.Ecosystem) != eco || affected.Package.Name != name {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				fixed := event.Fixed
+				if fixed == "" {
+					continue
+				}
+				if version != "" && (versions.Compare(eco, fixed, version) <= 0 || affected.Contains(fixed)) {
+					continue
+				}
+				if best == "" || versions.Compare(eco, fixed, best) < 0 {
+					best = fixed
+				}
+			}
+		}
+	}
+	return best
+}
+
+// GetAffectedSymbols returns the vulnerable functions, classes or modules
+// an advisory names for a package, from the ecosystem_specific fields the
+// Go vulnerability database ("imports") and GitHub advisories
+// ("affected_functions") populate. Symbols are returned as written, e.g.
+// "template", "lodash.template" or "org.apache.commons.text.StringSubstitutor".
+func (v *Vulnerability) GetAffectedSymbols(ecosystem, name string) []string {
+	eco := versions.ParseEcosystem(ecosystem)
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(s string) {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
 	for _, affected := range v.Affected {
-		if affected.Package.Ecosystem == ecosystem && affected.Package.Name == name {
-			for _, r := range affected.Ranges {
-				for _, event := range r.Events {
-					if event.Fixed != "" {
-						return event.Fixed
+		if versions.ParseEcosystem(affected.Package.Ecosystem) != eco || affected.Package.Name != name {
+			continue
+		}
+		specific, ok := affected.EcosystemSpecific.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if funcs, ok := specific["affected_functions"].([]interface{}); ok {
+			for _, f := range funcs {
+				if s, ok := f.(string); ok {
+					add(s)
+				}
+			}
+		}
+		if imports, ok := specific["imports"].([]interface{}); ok {
+			for _, imp := range imports {
+				m, ok := imp.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				path, _ := m["path"].(string)
+				syms, _ := m["symbols"].([]interface{})
+				for _, sym := range syms {
+					if s, ok := sym.(string); ok && path != "" {
+						add(path + "." + s)
+					} else if ok {
+						add(s)
 					}
 				}
 			}
 		}
 	}
-	return ""
+	return symbols
+}
+
+// Contains reports whether a version is affected: listed explicitly or
+// inside one of the SEMVER or ECOSYSTEM ranges, compared with the
+// ecosystem's version ordering
+func (a Affected) Contains(version string) bool {
+	if version == "" {
+		return false
+	}
+	eco := versions.ParseEcosystem(a.Package.Ecosystem)
+	for _, v := range a.Versions {
+		if versions.Compare(eco, v, version) == 0 {
+			return true
+		}
+	}
+	for _, r := range a.Ranges {
+		if c := r.Constraint(a.Package.Ecosystem); c != nil && c.Contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Constraint converts the range into a version constraint: walking the
+// events in version order, "introduced" opens an affected interval, "fixed"
+// closes it before and "last_affected" at that version, and "limit" bounds
+// every interval. GIT ranges describe commits and return nil.
+func (r Range) Constraint(ecosystem string) *versions.Constraint {
+	var eco versions.Ecosystem
+	switch r.Type {
+	case "SEMVER":
+		eco = versions.SemVer
+	case "ECOSYSTEM":
+		eco = versions.ParseEcosystem(ecosystem)
+	default:
+		return nil
+	}
+
+	events := make([]Event, len(r.Events))
+	copy(events, r.Events)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareEvents(eco, events[i], events[j]) < 0
+	})
+
+	c := &versions.Constraint{Ecosystem: eco}
+	var lower []versions.Comparator
+	var limits []versions.Comparator
+	open := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if !open {
+				open = true
+				lower = nil
+				if e.Introduced != "0" {
+					lower = []versions.Comparator{{Op: versions.OpGE, Version: e.Introduced}}
+				}
+			}
+		case e.Fixed != "":
+			if open {
+				c.Sets = append(c.Sets, append(lower, versions.Comparator{Op: versions.OpLT, Version: e.Fixed}))
+				open = false
+			}
+		case e.LastAffected != "":
+			if open {
+				c.Sets = append(c.Sets, append(lower, versions.Comparator{Op: versions.OpLE, Version: e.LastAffected}))
+				open = false
+			}
+		case e.Limit != "" && e.Limit != "*":
+			limits = append(limits, versions.Comparator{Op: versions.OpLT, Version: e.Limit})
+		}
+	}
+	if open {
+		c.Sets = append(c.Sets, lower)
+	}
+	for i := range c.Sets {
+		c.Sets[i] = append(c.Sets[i], limits...)
+	}
+	return c
+}
+
+// compareEvents orders events by their version, with introduced "0"
+// before everything
+func compareEvents(eco versions.Ecosystem, a, b Event) int {
+	switch {
+	case a.Introduced == "0" && b.Introduced == "0":
+		return 0
+	case a.Introduced == "0":
+		return -1
+	case b.Introduced == "0":
+		return 1
+	}
+	return versions.Compare(eco, a.version(), b.version())
+}
+
+// version returns whichever version the event carries
+func (e Event) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
 }
 
 // DefaultOSVClient is the default OSV client instance
@@ -0,0 +1,52 @@
+package liveapi
+
+import "time"
+
+// Registry and feed endpoints queried by package analysis
+const (
+	NPMRegistryURL = "https://registry.npmjs.org"
+	PyPIURL        = "https://pypi.org/pypi"
+	GoProxyURL     = "https://proxy.golang.org"
+	PkgGoDevURL    = "https://pkg.go.dev"
+	KEVURL         = "https://www.cisa.gov/sites/default/files/feeds"
+)
+
+// KEVCatalogPath is the path of the KEV catalog JSON under KEVURL
+const KEVCatalogPath = "/known_exploited_vulnerabilities.json"
+
+// NewRegistryClient creates a client for a package registry or feed. Use
+// CachedGet so responses are cached under the endpoint's policy.
+func NewRegistryClient(baseURL, endpoint string, opts ...ClientOption) *Client {
+	return NewClient(baseURL, append([]ClientOption{
+		WithTimeout(10 * time.Second),
+		WithCache(time.Hour),
+		WithEndpoint(endpoint),
+		WithRateLimit(10),
+		WithUserAgent("Zero-Scanner/1.0 (" + endpoint + " Query)"),
+	}, opts...)...)
+}
+
+// NewNPMClient creates a client for the npm registry
+func NewNPMClient() *Client {
+	return NewRegistryClient(NPMRegistryURL, EndpointNPM)
+}
+
+// NewPyPIClient creates a client for the PyPI JSON API
+func NewPyPIClient() *Client {
+	return NewRegistryClient(PyPIURL, EndpointPyPI)
+}
+
+// NewGoProxyClient creates a client for the Go module proxy
+func NewGoProxyClient() *Client {
+	return NewRegistryClient(GoProxyURL, EndpointGoProxy)
+}
+
+// NewPkgGoDevClient creates a client for pkg.go.dev pages
+func NewPkgGoDevClient() *Client {
+	return NewRegistryClient(PkgGoDevURL, EndpointPkgGoDev, WithAccept("text/html"))
+}
+
+// NewKEVClient creates a client for the CISA Known Exploited Vulnerabilities feed
+func NewKEVClient() *Client {
+	return NewRegistryClient(KEVURL, EndpointKEV)
+}
@@ -0,0 +1,255 @@
+package liveapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+const storeSchema = `
+CREATE TABLE IF NOT EXISTS responses (
+	key        TEXT PRIMARY KEY,
+	endpoint   TEXT NOT NULL,
+	status     INTEGER NOT NULL,
+	body       BLOB NOT NULL,
+	fetched_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_responses_endpoint ON responses(endpoint);
+`
+
+// Endpoints with their own cache policy
+const (
+	EndpointOSV      = "osv"
+	EndpointDepsDev  = "deps.dev"
+	EndpointNPM      = "npm"
+	EndpointPyPI     = "pypi"
+	EndpointGoProxy  = "goproxy"
+	EndpointPkgGoDev = "pkg.go.dev"
+	EndpointKEV      = "kev"
+)
+
+// Policy controls how long an endpoint's stored responses are used.
+// Responses younger than TTL are served as is. Until TTL+StaleFor they are
+// served while a refresh runs in the background; after that they are fetched
+// again, and only served if fetching fails or in offline mode.
+type Policy struct {
+	TTL      time.Duration `json:"ttl"`
+	StaleFor time.Duration `json:"stale_for"`
+}
+
+const day = 24 * time.Hour
+
+var (
+	policiesMu sync.RWMutex
+	policies   = map[string]Policy{
+		EndpointOSV:      {TTL: 6 * time.Hour, StaleFor: 7 * day}, // Advisories are published daily
+		EndpointDepsDev:  {TTL: day, StaleFor: 30 * day},          // Scorecard updates weekly
+		EndpointNPM:      {TTL: day, StaleFor: 30 * day},
+		EndpointPyPI:     {TTL: day, StaleFor: 30 * day},
+		EndpointGoProxy:  {TTL: 7 * day, StaleFor: 90 * day}, // Version info is immutable, retractions are rare
+		EndpointPkgGoDev: {TTL: 7 * day, StaleFor: 90 * day},
+		EndpointKEV:      {TTL: day, StaleFor: 30 * day},
+	}
+	defaultPolicy = Policy{TTL: time.Hour, StaleFor: day}
+)
+
+// PolicyFor returns the cache policy of an endpoint
+func PolicyFor(endpoint string) Policy {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	if p, ok := policies[endpoint]; ok {
+		return p
+	}
+	return defaultPolicy
+}
+
+// SetPolicy overrides the cache policy of an endpoint
+func SetPolicy(endpoint string, p Policy) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	policies[endpoint] = p
+}
+
+// Store is a persistent cache of API responses shared by all clients and
+// across Zero invocations, so repeated and org-wide scans reuse registry
+// data instead of querying it again. Not-found responses are stored too.
+type Store struct {
+	db   *sql.DB
+	path string
+}
+
+// StoreStats describes the contents of a store
+type StoreStats struct {
+	Responses int            `json:"responses"`
+	Endpoints map[string]int `json:"endpoints"` // responses per endpoint
+	Oldest    time.Time      `json:"oldest,omitempty"`
+	Newest    time.Time      `json:"newest,omitempty"`
+}
+
+// entry is a stored response
+type entry struct {
+	status    int
+	body      []byte
+	fetchedAt time.Time
+}
+
+// result returns the stored response as Client.doRequestRaw would
+func (e *entry) result() ([]byte, error) {
+	if e.status < 200 || e.status >= 300 {
+		return nil, &APIError{
+			StatusCode: e.status,
+			Status:     fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+			Body:       string(e.body),
+		}
+	}
+	return e.body, nil
+}
+
+// DefaultStorePath returns the store location under a Zero home directory
+func DefaultStorePath(zeroHome string) string {
+	return filepath.Join(zeroHome, "cache", "liveapi.db")
+}
+
+// OpenStore opens the store at path, creating it if needed
+func OpenStore(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("opening cache: %w", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite only supports one writer
+	db.SetMaxIdleConns(1)
+
+	if _, err := db.Exec(storeSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+
+	return &Store{db: db, path: path}, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the store file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+func (s *Store) get(ctx context.Context, key string) (*entry, bool) {
+	var e entry
+	var fetchedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT status, body, fetched_at FROM responses WHERE key = ?`, key).
+		Scan(&e.status, &e.body, &fetchedAt)
+	if err != nil {
+		return nil, false
+	}
+	e.fetchedAt = time.Unix(fetchedAt, 0)
+	return &e, true
+}
+
+func (s *Store) put(ctx context.Context, key, endpoint string, e entry) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO responses (key, endpoint, status, body, fetched_at) VALUES (?, ?, ?, ?, ?)`,
+		key, endpoint, e.status, e.body, e.fetchedAt.Unix())
+	return err
+}
+
+// Stats returns response counts per endpoint
+func (s *Store) Stats(ctx context.Context) (*StoreStats, error) {
+	stats := &StoreStats{Endpoints: make(map[string]int)}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT endpoint, COUNT(*), MIN(fetched_at), MAX(fetched_at) FROM responses GROUP BY endpoint`)
+	if err != nil {
+		return nil, fmt.Errorf("counting responses: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var endpoint string
+		var count int
+		var oldest, newest int64
+		if err := rows.Scan(&endpoint, &count, &oldest, &newest); err != nil {
+			return nil, err
+		}
+		stats.Endpoints[endpoint] = count
+		stats.Responses += count
+		if t := time.Unix(oldest, 0); stats.Oldest.IsZero() || t.Before(stats.Oldest) {
+			stats.Oldest = t
+		}
+		if t := time.Unix(newest, 0); t.After(stats.Newest) {
+			stats.Newest = t
+		}
+	}
+	return stats, rows.Err()
+}
+
+// Clear removes the stored responses of an endpoint, or all responses if
+// endpoint is empty, and returns how many were removed
+func (s *Store) Clear(ctx context.Context, endpoint string) (int64, error) {
+	var res sql.Result
+	var err error
+	if endpoint == "" {
+		res, err = s.db.ExecContext(ctx, `DELETE FROM responses`)
+	} else {
+		res, err = s.db.ExecContext(ctx, `DELETE FROM responses WHERE endpoint = ?`, endpoint)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("clearing cache: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Prune removes responses fetched before olderThan ago
+func (s *Store) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM responses WHERE fetched_at < ?`, time.Now().Add(-olderThan).Unix())
+	if err != nil {
+		return 0, fmt.Errorf("pruning cache: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+var (
+	storeMu      sync.Mutex
+	storePath    string
+	sharedStore  *Store
+	storeOpenErr error
+)
+
+// UseStore makes the store at path the persistent cache of every client.
+// The store is opened on first use; if it cannot be opened, clients fall
+// back to their in-memory cache.
+func UseStore(path string) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if path == storePath {
+		return
+	}
+	if sharedStore != nil {
+		sharedStore.Close()
+	}
+	storePath, sharedStore, storeOpenErr = path, nil, nil
+}
+
+// SharedStore returns the store set by UseStore, or nil if there is none or
+// it cannot be opened
+func SharedStore() *Store {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	if sharedStore == nil && storeOpenErr == nil && storePath != "" {
+		sharedStore, storeOpenErr = OpenStore(storePath)
+	}
+	return sharedStore
+}
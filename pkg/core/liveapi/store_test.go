@@ -0,0 +1,256 @@
+package liveapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer serves {"n": <request number>} for /pkg and 404 for anything
+// else; failing makes every request return 500
+type testServer struct {
+	*httptest.Server
+	calls   atomic.Int32
+	failing atomic.Bool
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.calls.Add(1)
+		switch {
+		case s.failing.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.URL.Path == "/pkg":
+			json.NewEncoder(w).Encode(map[string]int32{"n": n})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := OpenStore(filepath.Join(t.TempDir(), "cache", "liveapi.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// storeClient creates a client without an in-memory cache, as a new Zero
+// invocation would see the store
+func storeClient(baseURL string, store *Store, endpoint string, p Policy) *Client {
+	SetPolicy(endpoint, p)
+	c := NewClient(baseURL, WithStore(store), WithEndpoint(endpoint))
+	c.Cache = nil
+	return c
+}
+
+func getN(t *testing.T, ctx context.Context, c *Client) int32 {
+	t.Helper()
+	var resp struct{ N int32 }
+	if err := c.CachedGet(ctx, "/pkg", &resp); err != nil {
+		t.Fatalf("CachedGet() error = %v", err)
+	}
+	return resp.N
+}
+
+func TestStore_FreshAcrossClients(t *testing.T) {
+	srv := newTestServer(t)
+	store := newTestStore(t)
+	policy := Policy{TTL: time.Hour, StaleFor: time.Hour}
+
+	getN(t, context.Background(), storeClient(srv.URL, store, "test-fresh", policy))
+
+	rec := &StatsRecorder{}
+	ctx := WithStatsRecorder(context.Background(), rec)
+	if n := getN(t, ctx, storeClient(srv.URL, store, "test-fresh", policy)); n != 1 {
+		t.Errorf("second client got response %d, want 1 (stored)", n)
+	}
+	if calls := srv.calls.Load(); calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+	if got := rec.Stats(); got != (CacheStats{Hits: 1}) {
+		t.Errorf("stats = %+v, want one hit", got)
+	}
+
+	stats, err := store.Stats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Responses != 1 || stats.Endpoints["test-fresh"] != 1 {
+		t.Errorf("store stats = %+v", stats)
+	}
+}
+
+func TestStore_StaleWhileRevalidate(t *testing.T) {
+	srv := newTestServer(t)
+	store := newTestStore(t)
+	// Every response is past its TTL but within the stale window
+	c := storeClient(srv.URL, store, "test-swr", Policy{TTL: -time.Second, StaleFor: time.Hour})
+
+	getN(t, context.Background(), c)
+
+	rec := &StatsRecorder{}
+	if n := getN(t, WithStatsRecorder(context.Background(), rec), c); n != 1 {
+		t.Errorf("stale response = %d, want 1", n)
+	}
+	if rec.Stats().Stale != 1 {
+		t.Errorf("stats = %+v, want one stale response", rec.Stats())
+	}
+
+	// The background refresh replaces the stored response
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		e, ok := store.get(context.Background(), c.storeKey("GET", "/pkg", nil))
+		if ok && string(e.body) == "{\"n\":2}\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stale response was not revalidated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStore_StaleIfError(t *testing.T) {
+	srv := newTestServer(t)
+	store := newTestStore(t)
+	// Every response is past its stale window and must be fetched again
+	c := storeClient(srv.URL, store, "test-expired", Policy{TTL: -time.Second, StaleFor: 0})
+
+	getN(t, context.Background(), c)
+	if n := getN(t, context.Background(), c); n != 2 {
+		t.Errorf("expired response was not fetched again: got %d, want 2", n)
+	}
+
+	srv.failing.Store(true)
+	if n := getN(t, context.Background(), c); n != 2 {
+		t.Errorf("failed fetch served %d, want the stored 2", n)
+	}
+}
+
+func TestStore_NotFoundCached(t *testing.T) {
+	srv := newTestServer(t)
+	store := newTestStore(t)
+	policy := Policy{TTL: time.Hour, StaleFor: time.Hour}
+
+	for i := 0; i < 2; i++ {
+		c := storeClient(srv.URL, store, "test-404", policy)
+		err := c.CachedGet(context.Background(), "/missing", &struct{}{})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+			t.Fatalf("CachedGet() error = %v, want not found", err)
+		}
+	}
+	if calls := srv.calls.Load(); calls != 1 {
+		t.Errorf("server calls = %d, want 1 (not found is cached)", calls)
+	}
+}
+
+func TestStore_Offline(t *testing.T) {
+	srv := newTestServer(t)
+	store := newTestStore(t)
+	c := storeClient(srv.URL, store, "test-offline", Policy{TTL: -time.Second, StaleFor: 0})
+	getN(t, context.Background(), c)
+
+	SetOffline(true)
+	defer SetOffline(false)
+
+	rec := &StatsRecorder{}
+	ctx := WithStatsRecorder(context.Background(), rec)
+	if n := getN(t, ctx, c); n != 1 {
+		t.Errorf("offline response = %d, want the stored 1", n)
+	}
+	if err := c.CachedGet(ctx, "/other", &struct{}{}); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached request error = %v, want ErrOffline", err)
+	}
+	if err := c.Get(ctx, "/pkg", &struct{}{}); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached Get() error = %v, want ErrOffline", err)
+	}
+	if calls := srv.calls.Load(); calls != 1 {
+		t.Errorf("server calls = %d, want 1", calls)
+	}
+	if got := rec.Stats(); got != (CacheStats{Stale: 1, Unavailable: 1}) {
+		t.Errorf("stats = %+v", got)
+	}
+}
+
+func TestStore_ClearAndPrune(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+	store.put(ctx, "a", "npm", entry{status: 200, body: []byte("{}"), fetchedAt: old})
+	store.put(ctx, "b", "npm", entry{status: 200, body: []byte("{}"), fetchedAt: time.Now()})
+	store.put(ctx, "c", "osv", entry{status: 200, body: []byte("{}"), fetchedAt: time.Now()})
+
+	if n, err := store.Prune(ctx, 24*time.Hour); err != nil || n != 1 {
+		t.Errorf("Prune() = %d, %v, want 1", n, err)
+	}
+	if n, err := store.Clear(ctx, "osv"); err != nil || n != 1 {
+		t.Errorf("Clear(osv) = %d, %v, want 1", n, err)
+	}
+	if _, ok := store.get(ctx, "b"); !ok {
+		t.Error("Clear(osv) removed an npm response")
+	}
+}
+
+func TestOSVClient_QueryBatchCachesPerPackage(t *testing.T) {
+	var sent atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BatchQueryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sent.Add(int32(len(req.Queries)))
+		resp := BatchQueryResponse{Results: make([]QueryResponse, len(req.Queries))}
+		for i, q := range req.Queries {
+			resp.Results[i].Vulns = []Vulnerability{{ID: "OSV-" + q.Package.Name}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c := NewOSVClient()
+	c.BaseURL = srv.URL
+	c.Store = newTestStore(t)
+	query := func(names ...string) []QueryRequest {
+		var qs []QueryRequest
+		for _, n := range names {
+			qs = append(qs, QueryRequest{Package: &PackageQuery{Name: n, Ecosystem: "npm"}, Version: "1.0.0"})
+		}
+		return qs
+	}
+
+	if _, err := c.QueryBatch(context.Background(), query("a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	results, err := c.QueryBatch(context.Background(), query("b", "c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.Load() != 3 {
+		t.Errorf("queries sent = %d, want 3 (b is cached)", sent.Load())
+	}
+	if len(results) != 2 || results[0].Vulns[0].ID != "OSV-b" || results[1].Vulns[0].ID != "OSV-c" {
+		t.Errorf("results = %+v", results)
+	}
+
+	SetOffline(true)
+	defer SetOffline(false)
+	if results, err := c.QueryBatch(context.Background(), query("a", "d")); err != nil || results[0].Vulns[0].ID != "OSV-a" {
+		t.Errorf("offline QueryBatch() = %+v, %v, want the cached result for a", results, err)
+	}
+	if _, err := c.QueryBatch(context.Background(), query("d")); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline QueryBatch() error = %v, want ErrOffline", err)
+	}
+}
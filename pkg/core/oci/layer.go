@@ -0,0 +1,278 @@
+package oci
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Whiteout markers (AUFS style, as used by Docker and the OCI spec)
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// maxSymlinkHops bounds symlink resolution, as the kernel does
+const maxSymlinkHops = 40
+
+// ExtractStats summarizes an extraction
+type ExtractStats struct {
+	Layers    int   `json:"layers"`
+	Files     int   `json:"files"`
+	Bytes     int64 `json:"bytes"`
+	Whiteouts int   `json:"whiteouts"`
+	Skipped   int   `json:"skipped"` // Devices, FIFOs and entries that would escape the root
+}
+
+// Extract applies the image's layers in order to dst, producing the root
+// filesystem a container would see. Whiteouts remove files of lower
+// layers; symlinks are created but never followed outside dst. Owners,
+// timestamps and special files are not preserved, and every file is left
+// readable by the current user.
+func (a *Archive) Extract(ctx context.Context, dst string) (*ExtractStats, error) {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return nil, err
+	}
+	stats := &ExtractStats{}
+	for _, l := range a.Image.Layers {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		r, err := a.open(l.Path)
+		if err != nil {
+			return stats, err
+		}
+		if err := applyLayer(ctx, r, l.Digest, dst, stats); err != nil {
+			return stats, fmt.Errorf("layer %s: %w", l.Path, err)
+		}
+		stats.Layers++
+	}
+	return stats, nil
+}
+
+// applyLayer unpacks one layer, verifying its digest when known
+func applyLayer(ctx context.Context, r io.Reader, digest, root string, stats *ExtractStats) error {
+	var h hash.Hash
+	if strings.HasPrefix(digest, "sha256:") {
+		h = sha256.New()
+		r = io.TeeReader(r, h)
+	}
+
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	var lr io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		lr = gz
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return errors.New("zstd-compressed layers are not supported")
+	}
+
+	// Paths this layer created; an opaque whiteout only hides lower layers
+	created := make(map[string]bool)
+
+	tr := tar.NewReader(lr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := path.Clean("/" + hdr.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := path.Split(name)
+
+		if base == whiteoutOpaque {
+			stats.Whiteouts++
+			if err := clearDir(root, dir, created); err != nil {
+				return err
+			}
+			continue
+		}
+		if hidden, ok := strings.CutPrefix(base, whiteoutPrefix); ok {
+			stats.Whiteouts++
+			p, err := securePath(root, path.Join(dir, hidden))
+			if err != nil {
+				continue
+			}
+			if err := os.RemoveAll(p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		p, err := securePath(root, name)
+		if err != nil {
+			stats.Skipped++
+			continue
+		}
+		if err := writeEntry(root, p, hdr, tr, stats); err != nil {
+			return err
+		}
+		created[name] = true
+	}
+
+	if h != nil {
+		// Hash the whole blob, including any padding after the tar trailer
+		if _, err := io.Copy(io.Discard, br); err != nil {
+			return err
+		}
+		if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != digest {
+			return fmt.Errorf("digest mismatch: got %s, want %s", got, digest)
+		}
+	}
+	return nil
+}
+
+// writeEntry creates a layer entry at p, replacing whatever lower layers
+// left there (directories are merged)
+func writeEntry(root, p string, hdr *tar.Header, r io.Reader, stats *ExtractStats) error {
+	mode := hdr.FileInfo().Mode().Perm()
+
+	if fi, err := os.Lstat(p); err == nil && !(fi.IsDir() && hdr.Typeflag == tar.TypeDir) {
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		// A lower layer left a file where this layer expects a directory
+		stats.Skipped++
+		return nil
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.Mkdir(p, mode|0700); err != nil && !os.IsExist(err) {
+			return err
+		}
+		return os.Chmod(p, mode|0700)
+	case tar.TypeReg:
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0600)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(f, r)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Bytes += n
+	case tar.TypeSymlink:
+		return os.Symlink(hdr.Linkname, p)
+	case tar.TypeLink:
+		target, err := securePath(root, path.Clean("/"+hdr.Linkname))
+		if err != nil {
+			stats.Skipped++
+			return nil
+		}
+		if err := os.Link(target, p); err != nil {
+			stats.Skipped++
+			return nil
+		}
+		stats.Files++
+	default:
+		// Devices and FIFOs have no content to scan
+		stats.Skipped++
+	}
+	return nil
+}
+
+// clearDir applies an opaque whiteout: everything in dir that this layer
+// did not create is removed
+func clearDir(root, dir string, created map[string]bool) error {
+	p, err := securePath(root, path.Join(dir, whiteoutOpaque))
+	if err != nil {
+		return nil
+	}
+	p = filepath.Dir(p)
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if created[path.Join(dir, e.Name())] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(p, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// securePath maps an absolute path inside the image to a path under root.
+// Symlinks in the parent directories are resolved the way the image would
+// see them: absolute targets start at root and ".." stops there, so no
+// entry can be written outside root. The last element is not resolved.
+func securePath(root, name string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(path.Clean("/"+name), "/"), "/")
+	last := parts[len(parts)-1]
+	pending := parts[:len(parts)-1]
+
+	resolved := "/"
+	hops := 0
+	for len(pending) > 0 {
+		part := pending[0]
+		pending = pending[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = path.Dir(resolved)
+			continue
+		}
+
+		next := path.Join(resolved, part)
+		fi, err := os.Lstat(filepath.Join(root, next))
+		if err != nil || fi.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		hops++
+		if hops > maxSymlinkHops {
+			return "", fmt.Errorf("too many symlinks in %s", name)
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(target, "/") {
+			resolved = "/"
+		}
+		pending = append(strings.Split(target, "/"), pending...)
+	}
+
+	if last == "" {
+		return "", fmt.Errorf("invalid path %q", name)
+	}
+	return filepath.Join(root, filepath.FromSlash(resolved), last), nil
+}
@@ -0,0 +1,389 @@
+// Package oci reads container image archives, as written by `docker save`
+// or as OCI image layouts, and unpacks their layers into a root filesystem
+// without a container runtime
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Archive formats
+const (
+	FormatDocker = "docker-archive" // docker save
+	FormatOCI    = "oci-layout"     // OCI image layout
+)
+
+// maxMetadataSize bounds the manifests and configs read into memory
+const maxMetadataSize = 16 << 20
+
+// Image describes the image in an archive
+type Image struct {
+	Format   string   `json:"format"`
+	RepoTags []string `json:"repo_tags,omitempty"` // e.g. registry.example.com/app:1.2.3
+	ID       string   `json:"id"`                  // sha256 digest of the image config
+	Config   Config   `json:"config"`
+	Layers   []Layer  `json:"layers"`
+}
+
+// Config is the part of the image configuration Zero reports on
+type Config struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Created      string `json:"created,omitempty"`
+	Config       struct {
+		User       string            `json:"User,omitempty"`
+		Env        []string          `json:"Env,omitempty"`
+		Entrypoint []string          `json:"Entrypoint,omitempty"`
+		Cmd        []string          `json:"Cmd,omitempty"`
+		WorkingDir string            `json:"WorkingDir,omitempty"`
+		Labels     map[string]string `json:"Labels,omitempty"`
+	} `json:"config"`
+}
+
+// Layer is a filesystem layer, in the order it is applied
+type Layer struct {
+	Path      string `json:"path"`             // Entry in the archive
+	Digest    string `json:"digest,omitempty"` // Verified on extraction (OCI layouts)
+	MediaType string `json:"media_type,omitempty"`
+	Size      int64  `json:"size"`
+}
+
+// Archive is an open image archive
+type Archive struct {
+	Image *Image
+
+	f       *os.File
+	temp    string // Decompressed copy of a gzipped archive
+	entries map[string]entry
+}
+
+// entry locates a file inside the (uncompressed) archive
+type entry struct {
+	offset   int64
+	size     int64
+	linkname string // Symlinked entries, as docker save writes for shared layers
+}
+
+// Open indexes an image archive and reads its manifest and config. Gzipped
+// archives (image.tar.gz) are decompressed to a temporary file first.
+func Open(archivePath string) (*Archive, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{f: f}
+
+	if gzipped, err := isGzip(f); err != nil {
+		a.Close()
+		return nil, err
+	} else if gzipped {
+		if err := a.decompress(); err != nil {
+			a.Close()
+			return nil, fmt.Errorf("decompressing %s: %w", path.Base(archivePath), err)
+		}
+	}
+
+	if err := a.index(); err != nil {
+		a.Close()
+		return nil, fmt.Errorf("reading %s: %w", path.Base(archivePath), err)
+	}
+
+	switch {
+	case a.has("manifest.json"):
+		err = a.readDockerManifest()
+	case a.has("index.json"):
+		err = a.readOCIIndex()
+	default:
+		err = errors.New("not a docker save or OCI layout archive (no manifest.json or index.json)")
+	}
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close closes the archive and removes any temporary copy
+func (a *Archive) Close() error {
+	err := a.f.Close()
+	if a.temp != "" {
+		os.Remove(a.temp)
+	}
+	return err
+}
+
+func isGzip(f *os.File) (bool, error) {
+	magic := make([]byte, 2)
+	n, err := f.ReadAt(magic, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	return n == 2 && magic[0] == 0x1f && magic[1] == 0x8b, nil
+}
+
+// decompress replaces the archive with a decompressed temporary copy, since
+// layers are read by offset
+func (a *Archive) decompress() error {
+	gz, err := gzip.NewReader(a.f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tmp, err := os.CreateTemp("", "zero-image-*.tar")
+	if err != nil {
+		return err
+	}
+	a.temp = tmp.Name()
+	if _, err := io.Copy(tmp, gz); err != nil {
+		tmp.Close()
+		return err
+	}
+	a.f.Close()
+	a.f = tmp
+	return nil
+}
+
+// index records where each file is in the archive. tar.Reader reads
+// headers block by block, so the file position after Next is the start of
+// the entry's data.
+func (a *Archive) index() error {
+	if _, err := a.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	a.entries = make(map[string]entry)
+	tr := tar.NewReader(a.f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := cleanEntryName(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			offset, err := a.f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			a.entries[name] = entry{offset: offset, size: hdr.Size}
+		case tar.TypeSymlink, tar.TypeLink:
+			a.entries[name] = entry{linkname: hdr.Linkname}
+		}
+	}
+	if len(a.entries) == 0 {
+		return errors.New("empty archive")
+	}
+	return nil
+}
+
+func cleanEntryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (a *Archive) has(name string) bool {
+	_, ok := a.entries[name]
+	return ok
+}
+
+// open returns a reader for an archive entry, following links between
+// entries
+func (a *Archive) open(name string) (*io.SectionReader, error) {
+	name = cleanEntryName(name)
+	for hops := 0; hops < 8; hops++ {
+		e, ok := a.entries[name]
+		if !ok {
+			return nil, fmt.Errorf("%s not found in archive", name)
+		}
+		if e.linkname == "" {
+			return io.NewSectionReader(a.f, e.offset, e.size), nil
+		}
+		if strings.HasPrefix(e.linkname, "/") {
+			name = cleanEntryName(e.linkname)
+		} else {
+			name = cleanEntryName(path.Join(path.Dir(name), e.linkname))
+		}
+	}
+	return nil, fmt.Errorf("too many links resolving %s", name)
+}
+
+// readFile reads a (small) metadata entry
+func (a *Archive) readFile(name string) ([]byte, error) {
+	r, err := a.open(name)
+	if err != nil {
+		return nil, err
+	}
+	if r.Size() > maxMetadataSize {
+		return nil, fmt.Errorf("%s is too large for image metadata", name)
+	}
+	return io.ReadAll(r)
+}
+
+// dockerManifest is an entry of a docker save manifest.json
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// readDockerManifest reads a docker save archive. Archives with several
+// images are read for the first one.
+func (a *Archive) readDockerManifest() error {
+	data, err := a.readFile("manifest.json")
+	if err != nil {
+		return err
+	}
+	var manifests []dockerManifest
+	if err := json.Unmarshal(data, &manifests); err != nil {
+		return fmt.Errorf("parsing manifest.json: %w", err)
+	}
+	if len(manifests) == 0 {
+		return errors.New("manifest.json lists no images")
+	}
+	m := manifests[0]
+
+	img := &Image{Format: FormatDocker, RepoTags: m.RepoTags}
+	if err := a.readConfig(img, m.Config); err != nil {
+		return err
+	}
+	for _, l := range m.Layers {
+		r, err := a.open(l)
+		if err != nil {
+			return fmt.Errorf("layer %s: %w", l, err)
+		}
+		// Docker 25+ writes layers as OCI blobs, named by their digest
+		layer := Layer{Path: cleanEntryName(l), Size: r.Size()}
+		if sum, ok := strings.CutPrefix(layer.Path, "blobs/sha256/"); ok {
+			layer.Digest = "sha256:" + sum
+		}
+		img.Layers = append(img.Layers, layer)
+	}
+	a.Image = img
+	return nil
+}
+
+// descriptor is an OCI content descriptor
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+// manifestOrIndex holds either an image manifest or an image index
+type manifestOrIndex struct {
+	MediaType string       `json:"mediaType"`
+	Config    *descriptor  `json:"config,omitempty"`
+	Layers    []descriptor `json:"layers,omitempty"`
+	Manifests []descriptor `json:"manifests,omitempty"`
+}
+
+// readOCIIndex reads an OCI image layout, descending through image indexes
+// to the image for this platform (or the first Linux image)
+func (a *Archive) readOCIIndex() error {
+	data, err := a.readFile("index.json")
+	if err != nil {
+		return err
+	}
+
+	var tags []string
+	for depth := 0; depth < 4; depth++ {
+		var m manifestOrIndex
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("parsing image index: %w", err)
+		}
+
+		if m.Config != nil {
+			img := &Image{Format: FormatOCI, RepoTags: tags}
+			if err := a.readConfig(img, blobPath(m.Config.Digest)); err != nil {
+				return err
+			}
+			for _, l := range m.Layers {
+				img.Layers = append(img.Layers, Layer{
+					Path:      blobPath(l.Digest),
+					Digest:    l.Digest,
+					MediaType: l.MediaType,
+					Size:      l.Size,
+				})
+			}
+			a.Image = img
+			return nil
+		}
+
+		d := selectManifest(m.Manifests)
+		if d == nil {
+			return errors.New("image index lists no Linux images")
+		}
+		if name := d.Annotations["io.containerd.image.name"]; name != "" {
+			tags = []string{name}
+		} else if ref := d.Annotations["org.opencontainers.image.ref.name"]; ref != "" && tags == nil {
+			tags = []string{ref}
+		}
+		if data, err = a.readFile(blobPath(d.Digest)); err != nil {
+			return err
+		}
+	}
+	return errors.New("image index nested too deeply")
+}
+
+// selectManifest picks the manifest for this machine's architecture,
+// falling back to the first Linux (or unlabelled) one
+func selectManifest(manifests []descriptor) *descriptor {
+	var first *descriptor
+	for i := range manifests {
+		d := &manifests[i]
+		if d.Platform == nil {
+			if first == nil {
+				first = d
+			}
+			continue
+		}
+		if d.Platform.OS != "linux" {
+			continue
+		}
+		if d.Platform.Architecture == runtime.GOARCH {
+			return d
+		}
+		if first == nil {
+			first = d
+		}
+	}
+	return first
+}
+
+// blobPath returns the layout path of a blob, e.g. blobs/sha256/<hex>
+func blobPath(digest string) string {
+	alg, sum, _ := strings.Cut(digest, ":")
+	return path.Join("blobs", alg, sum)
+}
+
+// readConfig reads the image config. The image ID is its digest.
+func (a *Archive) readConfig(img *Image, name string) error {
+	data, err := a.readFile(name)
+	if err != nil {
+		return fmt.Errorf("reading image config: %w", err)
+	}
+	if err := json.Unmarshal(data, &img.Config); err != nil {
+		return fmt.Errorf("parsing image config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	img.ID = "sha256:" + hex.EncodeToString(sum[:])
+	return nil
+}
@@ -0,0 +1,306 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// file is a tar entry for test archives
+type file struct {
+	name     string
+	body     string
+	typeflag byte
+	linkname string
+}
+
+func tarBytes(t *testing.T, files []file) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Typeflag: f.typeflag, Linkname: f.linkname, Mode: 0644}
+		switch f.typeflag {
+		case 0:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = int64(len(f.body))
+		case tar.TypeDir:
+			hdr.Mode = 0755
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(f.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func writeArchive(t *testing.T, files []file) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "image.tar")
+	if err := os.WriteFile(path, tarBytes(t, files), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testConfig = `{"architecture": "amd64", "os": "linux", "config": {"User": "app", "Env": ["PATH=/usr/bin"]}}`
+
+// testLayers are a base layer and an application layer that replaces,
+// deletes and hides base files
+func testLayers(t *testing.T) (base, app []byte) {
+	base = tarBytes(t, []file{
+		{name: "etc/", typeflag: tar.TypeDir},
+		{name: "etc/os-release", body: "ID=debian\n"},
+		{name: "etc/motd", body: "hello\n"},
+		{name: "usr/lib/", typeflag: tar.TypeDir},
+		{name: "lib", typeflag: tar.TypeSymlink, linkname: "usr/lib"},
+		{name: "opt/old/", typeflag: tar.TypeDir},
+		{name: "opt/old/a.txt", body: "a"},
+		{name: "dev/null", typeflag: tar.TypeChar},
+	})
+	app = tarBytes(t, []file{
+		{name: "etc/motd", body: "replaced\n"},
+		{name: "etc/.wh.os-release"},
+		{name: "opt/old/.wh..wh..opq"},
+		{name: "opt/old/b.txt", body: "b"},
+		{name: "lib/libapp.so", body: "ELF"},
+		{name: "app/config.yaml", body: "password: hunter2\n"},
+		{name: "app/link", typeflag: tar.TypeLink, linkname: "app/config.yaml"},
+		// A malicious layer trying to write outside the root
+		{name: "escape", typeflag: tar.TypeSymlink, linkname: "/../../../../tmp"},
+		{name: "escape/pwned", body: "x"},
+		{name: "../outside", body: "x"},
+	})
+	return base, app
+}
+
+func checkRootfs(t *testing.T, root string) {
+	t.Helper()
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	if got := read("etc/motd"); got != "replaced\n" {
+		t.Errorf("etc/motd = %q, want the upper layer's", got)
+	}
+	if _, err := os.Stat(filepath.Join(root, "etc/os-release")); !os.IsNotExist(err) {
+		t.Error("etc/os-release should be whited out")
+	}
+	if _, err := os.Stat(filepath.Join(root, "opt/old/a.txt")); !os.IsNotExist(err) {
+		t.Error("opt/old/a.txt should be hidden by the opaque whiteout")
+	}
+	if got := read("opt/old/b.txt"); got != "b" {
+		t.Errorf("opt/old/b.txt = %q", got)
+	}
+	if got := read("usr/lib/libapp.so"); got != "ELF" {
+		t.Errorf("lib/libapp.so should be written through the lib symlink, got %q", got)
+	}
+	if got := read("app/link"); got != "password: hunter2\n" {
+		t.Errorf("app/link = %q, want the hard link target's content", got)
+	}
+	// The escaping symlink's target resolves inside the root
+	if got := read("tmp/pwned"); got != "x" {
+		t.Errorf("escape/pwned should land in <root>/tmp, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "outside")); !os.IsNotExist(err) {
+		t.Error("../outside escaped the root")
+	}
+	if got := read("outside"); got != "x" {
+		t.Errorf("../outside should land in <root>/outside, got %q", got)
+	}
+}
+
+func TestOpen_DockerArchive(t *testing.T) {
+	base, app := testLayers(t)
+	manifest := `[{"Config": "cfg.json", "RepoTags": ["registry.example.com/team/app:1.2.3"],
+		"Layers": ["l1/layer.tar", "l2/layer.tar", "l3/layer.tar"]}]`
+	path := writeArchive(t, []file{
+		{name: "cfg.json", body: testConfig},
+		{name: "l1/layer.tar", body: string(base)},
+		{name: "l2/layer.tar", body: string(gzipBytes(t, app))},
+		// docker save links layers shared between images
+		{name: "l3/layer.tar", typeflag: tar.TypeSymlink, linkname: "../l1/layer.tar"},
+		{name: "manifest.json", body: manifest},
+	})
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer a.Close()
+
+	img := a.Image
+	if img.Format != FormatDocker || img.ID != digestOf([]byte(testConfig)) {
+		t.Errorf("Image = %+v", img)
+	}
+	if len(img.RepoTags) != 1 || img.Config.OS != "linux" || img.Config.Config.User != "app" {
+		t.Errorf("Image = %+v", img)
+	}
+	if len(img.Layers) != 3 {
+		t.Fatalf("Layers = %+v", img.Layers)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	base, app := testLayers(t)
+	manifest := `[{"Config": "cfg.json", "Layers": ["l1/layer.tar", "l2/layer.tar"]}]`
+	path := writeArchive(t, []file{
+		{name: "manifest.json", body: manifest},
+		{name: "cfg.json", body: testConfig},
+		{name: "l1/layer.tar", body: string(base)},
+		{name: "l2/layer.tar", body: string(gzipBytes(t, app))},
+	})
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	root := filepath.Join(t.TempDir(), "rootfs")
+	stats, err := a.Extract(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if stats.Layers != 2 || stats.Whiteouts != 2 || stats.Skipped != 1 {
+		t.Errorf("stats = %+v, want 2 layers, 2 whiteouts, 1 skipped", stats)
+	}
+	checkRootfs(t, root)
+}
+
+func TestOpen_OCILayout(t *testing.T) {
+	base, app := testLayers(t)
+	appGz := gzipBytes(t, app)
+	blob := func(data []byte) string {
+		return "blobs/sha256/" + strings.TrimPrefix(digestOf(data), "sha256:")
+	}
+
+	manifest, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"config":        map[string]interface{}{"mediaType": "application/vnd.oci.image.config.v1+json", "digest": digestOf([]byte(testConfig))},
+		"layers": []map[string]interface{}{
+			{"mediaType": "application/vnd.oci.image.layer.v1.tar", "digest": digestOf(base), "size": len(base)},
+			{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": digestOf(appGz), "size": len(appGz)},
+		},
+	})
+	// A multi-platform index with the Linux image listed after another OS
+	nested, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests": []map[string]interface{}{
+			{"digest": "sha256:0000", "platform": map[string]string{"os": "windows", "architecture": "amd64"}},
+			{"digest": digestOf(manifest), "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+		},
+	})
+	index := `{"schemaVersion": 2, "manifests": [{"mediaType": "application/vnd.oci.image.index.v1+json",
+		"digest": "` + digestOf(nested) + `", "annotations": {"io.containerd.image.name": "docker.io/library/app:2.0"}}]}`
+
+	files := []file{
+		{name: "oci-layout", body: `{"imageLayoutVersion": "1.0.0"}`},
+		{name: "index.json", body: index},
+		{name: blob(nested), body: string(nested)},
+		{name: blob(manifest), body: string(manifest)},
+		{name: blob([]byte(testConfig)), body: testConfig},
+		{name: blob(base), body: string(base)},
+		{name: blob(appGz), body: string(appGz)},
+	}
+	path := writeArchive(t, files)
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer a.Close()
+	if a.Image.Format != FormatOCI || len(a.Image.Layers) != 2 || a.Image.RepoTags[0] != "docker.io/library/app:2.0" {
+		t.Errorf("Image = %+v", a.Image)
+	}
+
+	root := filepath.Join(t.TempDir(), "rootfs")
+	if _, err := a.Extract(context.Background(), root); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	checkRootfs(t, root)
+
+	// Layers that do not match their digest are rejected
+	files[len(files)-1].body = string(gzipBytes(t, base))
+	tampered := writeArchive(t, files)
+	a2, err := Open(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a2.Close()
+	if _, err := a2.Extract(context.Background(), t.TempDir()); err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("Extract() of a tampered layer error = %v, want digest mismatch", err)
+	}
+}
+
+func TestOpen_Gzipped(t *testing.T) {
+	base, _ := testLayers(t)
+	data := tarBytes(t, []file{
+		{name: "manifest.json", body: `[{"Config": "cfg.json", "Layers": ["l1/layer.tar"]}]`},
+		{name: "cfg.json", body: testConfig},
+		{name: "l1/layer.tar", body: string(base)},
+	})
+	path := filepath.Join(t.TempDir(), "image.tar.gz")
+	if err := os.WriteFile(path, gzipBytes(t, data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	temp := a.temp
+	if len(a.Image.Layers) != 1 {
+		t.Errorf("Layers = %+v", a.Image.Layers)
+	}
+	a.Close()
+	if _, err := os.Stat(temp); !os.IsNotExist(err) {
+		t.Error("Close() left the decompressed copy behind")
+	}
+}
+
+func TestOpen_NotAnImage(t *testing.T) {
+	path := writeArchive(t, []file{{name: "README", body: "hi"}})
+	if _, err := Open(path); err == nil {
+		t.Error("Open() of a plain tarball succeeded")
+	}
+}
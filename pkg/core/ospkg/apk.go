@@ -0,0 +1,26 @@
+package ospkg
+
+import "os"
+
+// parseAPKInstalled reads lib/apk/db/installed, whose stanzas use
+// single-letter keys: P name, V version, A arch, L license, o origin,
+// m maintainer
+func parseAPKInstalled(r *os.Root, path string) ([]Package, error) {
+	var pkgs []Package
+	err := readStanzas(r, path, func(fields map[string]string) {
+		if fields["P"] == "" || fields["V"] == "" {
+			return
+		}
+		pkgs = append(pkgs, Package{
+			Type:       TypeAPK,
+			Name:       fields["P"],
+			Version:    fields["V"],
+			Arch:       fields["A"],
+			Source:     fields["o"],
+			License:    fields["L"],
+			Maintainer: fields["m"],
+			Database:   path,
+		})
+	})
+	return pkgs, err
+}
@@ -0,0 +1,113 @@
+package ospkg
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxCopyrightSize bounds the copyright files read for license names
+const maxCopyrightSize = 1 << 20
+
+// parseDpkgStatus reads var/lib/dpkg/status
+func parseDpkgStatus(r *os.Root, path string) ([]Package, error) {
+	return readDpkgFile(r, path)
+}
+
+// parseDpkgStatusDir reads var/lib/dpkg/status.d, where distroless images
+// keep one status file per package
+func parseDpkgStatusDir(r *os.Root, path string) ([]Package, error) {
+	dir, err := r.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := dir.ReadDir(-1)
+	dir.Close()
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []Package
+	for _, e := range entries {
+		// Skip the md5sums files kept alongside
+		if e.IsDir() || strings.Contains(e.Name(), ".") {
+			continue
+		}
+		found, err := readDpkgFile(r, path+"/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, found...)
+	}
+	return pkgs, nil
+}
+
+func readDpkgFile(r *os.Root, path string) ([]Package, error) {
+	var pkgs []Package
+	err := readStanzas(r, path, func(fields map[string]string) {
+		// Removed packages keep their configuration, and an entry, until purged
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			return
+		}
+		if fields["Package"] == "" || fields["Version"] == "" {
+			return
+		}
+		pkg := Package{
+			Type:       TypeDeb,
+			Name:       fields["Package"],
+			Version:    fields["Version"],
+			Arch:       fields["Architecture"],
+			Maintainer: fields["Maintainer"],
+			Database:   path,
+		}
+		// "Source: glibc (2.36-9)" when the versions differ
+		if src, _, _ := strings.Cut(fields["Source"], " "); src != "" {
+			pkg.Source = src
+		}
+		pkg.License = debLicense(r, pkg.Name)
+		pkgs = append(pkgs, pkg)
+	})
+	return pkgs, err
+}
+
+// debLicense reads the license names from a package's machine-readable
+// copyright file (DEP-5). Free-form copyright files give no license.
+func debLicense(r *os.Root, name string) string {
+	// Multi-arch packages are named e.g. libc6:amd64 in some databases
+	name, _, _ = strings.Cut(name, ":")
+	f, err := r.Open(path.Join("usr/share/doc", name, "copyright"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	seen := make(map[string]bool)
+	var licenses []string
+	scanner := bufio.NewScanner(io.LimitReader(f, maxCopyrightSize))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			first = false
+			if !strings.HasPrefix(line, "Format:") {
+				return ""
+			}
+			continue
+		}
+		value, ok := strings.CutPrefix(line, "License:")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		licenses = append(licenses, value)
+	}
+	sort.Strings(licenses)
+	return strings.Join(licenses, " AND ")
+}
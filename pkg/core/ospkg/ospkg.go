@@ -0,0 +1,229 @@
+// Package ospkg reads the package databases of Linux distributions (dpkg,
+// apk and rpm) from an unpacked root filesystem, so the OS packages of a
+// container image can be inventoried without running it
+package ospkg
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Package types, as used in package URLs
+const (
+	TypeDeb = "deb"
+	TypeAPK = "apk"
+	TypeRPM = "rpm"
+)
+
+// Distro identifies the distribution, from os-release
+type Distro struct {
+	ID        string `json:"id"`                   // e.g. debian, alpine, rhel
+	VersionID string `json:"version_id,omitempty"` // e.g. 12, 3.20.1, 9.4
+	Name      string `json:"name,omitempty"`       // PRETTY_NAME, e.g. Debian GNU/Linux 12 (bookworm)
+	Codename  string `json:"codename,omitempty"`
+}
+
+// Package is an installed OS package
+type Package struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	Version    string `json:"version"` // As the package manager reports it; version-release for rpm
+	Epoch      string `json:"epoch,omitempty"`
+	Arch       string `json:"arch,omitempty"`
+	Source     string `json:"source,omitempty"` // Source package or apk origin
+	License    string `json:"license,omitempty"`
+	Maintainer string `json:"maintainer,omitempty"`
+	Database   string `json:"database"` // Path of the database it was read from, relative to the root
+}
+
+// DatabaseError is a package database that could not be read
+type DatabaseError struct {
+	Database string `json:"database"` // Path relative to the root
+	Error    string `json:"error"`
+}
+
+// Inventory is the result of scanning a root filesystem
+type Inventory struct {
+	Distro      *Distro         `json:"distro,omitempty"`
+	Packages    []Package       `json:"packages"`
+	Databases   []string        `json:"databases"`             // Databases that were read
+	Unsupported []string        `json:"unsupported,omitempty"` // Databases found in formats Zero cannot read
+	Failed      []DatabaseError `json:"failed,omitempty"`      // Databases that are unreadable or corrupt
+}
+
+// Scan reads every package database found under root. A root with no
+// package database (e.g. a scratch image) yields an empty inventory. A
+// database that fails to parse is recorded in Failed and the packages of
+// the others are kept. Files are read through an os.Root, so symlinks in
+// the image cannot point the scan at files outside it.
+func Scan(root string) (*Inventory, error) {
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	inv := &Inventory{Distro: readOSRelease(r)}
+
+	for _, db := range []struct {
+		path  string
+		parse func(r *os.Root, path string) ([]Package, error)
+	}{
+		{"var/lib/dpkg/status", parseDpkgStatus},
+		{"var/lib/dpkg/status.d", parseDpkgStatusDir}, // distroless
+		{"lib/apk/db/installed", parseAPKInstalled},
+		{"var/lib/rpm/rpmdb.sqlite", parseRPMDB},
+		{"usr/lib/sysimage/rpm/rpmdb.sqlite", parseRPMDB},
+	} {
+		if _, err := r.Stat(db.path); err != nil {
+			continue
+		}
+		pkgs, err := db.parse(r, db.path)
+		if err != nil {
+			inv.Failed = append(inv.Failed, DatabaseError{Database: db.path, Error: err.Error()})
+			continue
+		}
+		inv.Databases = append(inv.Databases, db.path)
+		inv.Packages = append(inv.Packages, pkgs...)
+	}
+
+	// BerkeleyDB (RHEL 7 and older) and ndb (SUSE) rpm databases
+	for _, p := range []string{"var/lib/rpm/Packages", "var/lib/rpm/Packages.db", "usr/lib/sysimage/rpm/Packages.db"} {
+		if _, err := r.Stat(p); err == nil {
+			inv.Unsupported = append(inv.Unsupported, p)
+		}
+	}
+
+	sort.Slice(inv.Packages, func(i, j int) bool {
+		if inv.Packages[i].Name != inv.Packages[j].Name {
+			return inv.Packages[i].Name < inv.Packages[j].Name
+		}
+		return inv.Packages[i].Arch < inv.Packages[j].Arch
+	})
+	return inv, nil
+}
+
+// readOSRelease reads etc/os-release, falling back to usr/lib/os-release
+// and, for old Alpine images, etc/alpine-release
+func readOSRelease(r *os.Root) *Distro {
+	for _, p := range []string{"etc/os-release", "usr/lib/os-release"} {
+		fields, err := readKeyValues(r, p)
+		if err != nil || fields["ID"] == "" {
+			continue
+		}
+		return &Distro{
+			ID:        fields["ID"],
+			VersionID: fields["VERSION_ID"],
+			Name:      fields["PRETTY_NAME"],
+			Codename:  fields["VERSION_CODENAME"],
+		}
+	}
+	if data, err := readFile(r, "etc/alpine-release"); err == nil {
+		return &Distro{ID: "alpine", VersionID: strings.TrimSpace(string(data))}
+	}
+	return nil
+}
+
+// readKeyValues parses a shell-style KEY=value file
+func readKeyValues(r *os.Root, path string) (map[string]string, error) {
+	f, err := r.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+	return fields, scanner.Err()
+}
+
+// PURL returns the package URL of an OS package, e.g.
+// pkg:deb/debian/curl@7.88.1-10?arch=amd64&distro=debian-12
+func (p Package) PURL(distro *Distro) string {
+	namespace := defaultNamespace[p.Type]
+	qualifiers := url.Values{}
+	if distro != nil {
+		if distro.ID != "" {
+			namespace = distro.ID
+		}
+		if distro.VersionID != "" {
+			qualifiers.Set("distro", namespace+"-"+distro.VersionID)
+		}
+	}
+	if p.Arch != "" {
+		qualifiers.Set("arch", p.Arch)
+	}
+	if p.Epoch != "" && p.Epoch != "0" {
+		qualifiers.Set("epoch", p.Epoch)
+	}
+
+	purl := "pkg:" + p.Type + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(p.Name) + "@" + url.PathEscape(p.Version)
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+	return purl
+}
+
+// defaultNamespace is used when the distribution is unknown
+var defaultNamespace = map[string]string{
+	TypeDeb: "debian",
+	TypeAPK: "alpine",
+	TypeRPM: "redhat",
+}
+
+// readStanzas reads a file of blank-line separated "Key: value" (or, for
+// apk, "K:value") paragraphs. Continuation lines start with whitespace.
+func readStanzas(r *os.Root, path string, fn func(fields map[string]string)) error {
+	f, err := r.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(fields) > 0 {
+				fn(fields)
+				fields = make(map[string]string)
+			}
+		case line[0] == ' ' || line[0] == '\t':
+			// Long descriptions and conffiles; nothing Zero reports
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(fields) > 0 {
+		fn(fields)
+	}
+	return scanner.Err()
+}
+
+// readFile reads a small file inside the root
+func readFile(r *os.Root, path string) ([]byte, error) {
+	f, err := r.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, 1<<20))
+}
@@ -0,0 +1,287 @@
+package ospkg
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan_Debian(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "usr/lib/os-release", `PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+ID=debian
+VERSION_ID="12"
+VERSION_CODENAME=bookworm
+`)
+	if err := os.Mkdir(filepath.Join(root, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../usr/lib/os-release", filepath.Join(root, "etc/os-release")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, root, "var/lib/dpkg/status", `Package: libc6
+Status: install ok installed
+Priority: optional
+Architecture: amd64
+Multi-Arch: same
+Source: glibc (2.36-9)
+Version: 2.36-9+deb12u4
+Maintainer: GNU Libc Maintainers <debian-glibc@lists.debian.org>
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: oldpkg
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+
+Package: tzdata
+Status: install ok installed
+Architecture: all
+Version: 2024a-0+deb12u1
+`)
+	writeFile(t, root, "usr/share/doc/libc6/copyright", `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: glibc
+
+Files: *
+License: LGPL-2.1+
+
+Files: debian/*
+License: GPL-2+
+
+Files: other/*
+License: LGPL-2.1+
+`)
+	writeFile(t, root, "usr/share/doc/tzdata/copyright", "This is free-form text.\nLicense: public domain\n")
+	// distroless keeps extra packages in status.d
+	writeFile(t, root, "var/lib/dpkg/status.d/base-files", "Package: base-files\nVersion: 12.4+deb12u5\nArchitecture: amd64\n")
+	writeFile(t, root, "var/lib/dpkg/status.d/base-files.md5sums", "d41d8cd98f00b204e9800998ecf8427e  etc/debian_version\n")
+
+	inv, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if inv.Distro == nil || inv.Distro.ID != "debian" || inv.Distro.VersionID != "12" || inv.Distro.Codename != "bookworm" {
+		t.Errorf("Distro = %+v", inv.Distro)
+	}
+	if len(inv.Packages) != 3 || len(inv.Databases) != 2 {
+		t.Fatalf("Packages = %+v, Databases = %v", inv.Packages, inv.Databases)
+	}
+
+	base, libc, tzdata := inv.Packages[0], inv.Packages[1], inv.Packages[2]
+	if base.Name != "base-files" || base.Database != "var/lib/dpkg/status.d/base-files" {
+		t.Errorf("base-files = %+v", base)
+	}
+	if libc.Source != "glibc" || libc.License != "GPL-2+ AND LGPL-2.1+" || libc.Version != "2.36-9+deb12u4" {
+		t.Errorf("libc6 = %+v", libc)
+	}
+	if tzdata.License != "" {
+		t.Errorf("tzdata license = %q, want none from a free-form copyright file", tzdata.License)
+	}
+	if got, want := libc.PURL(inv.Distro), "pkg:deb/debian/libc6@2.36-9+deb12u4?arch=amd64&distro=debian-12"; got != want {
+		t.Errorf("PURL() = %q, want %q", got, want)
+	}
+}
+
+func TestScan_Alpine(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "etc/alpine-release", "3.20.1\n")
+	writeFile(t, root, "lib/apk/db/installed", `C:Q1hdUpqRv5mYgJEqW52UmVsvmy3Ho=
+P:musl
+V:1.2.5-r0
+A:x86_64
+S:411308
+L:MIT
+o:musl
+m:Natanael Copa <ncopa@alpinelinux.org>
+F:lib
+R:ld-musl-x86_64.so.1
+
+P:busybox
+V:1.36.1-r29
+A:x86_64
+L:GPL-2.0-only
+o:busybox
+`)
+
+	inv, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if inv.Distro == nil || inv.Distro.ID != "alpine" {
+		t.Errorf("Distro = %+v", inv.Distro)
+	}
+	if len(inv.Packages) != 2 {
+		t.Fatalf("Packages = %+v", inv.Packages)
+	}
+	musl := inv.Packages[1]
+	if musl.Name != "musl" || musl.License != "MIT" || musl.Source != "musl" || musl.Type != TypeAPK {
+		t.Errorf("musl = %+v", musl)
+	}
+	if got, want := musl.PURL(inv.Distro), "pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64&distro=alpine-3.20.1"; got != want {
+		t.Errorf("PURL() = %q, want %q", got, want)
+	}
+}
+
+// rpmHeader builds a header blob with string tags and an int32 epoch
+func rpmHeader(strs map[int]string, epoch uint32) []byte {
+	var index, data []byte
+	entry := func(tag, typ, offset int) {
+		e := make([]byte, 16)
+		binary.BigEndian.PutUint32(e[0:], uint32(tag))
+		binary.BigEndian.PutUint32(e[4:], uint32(typ))
+		binary.BigEndian.PutUint32(e[8:], uint32(offset))
+		binary.BigEndian.PutUint32(e[12:], 1)
+		index = append(index, e...)
+	}
+	for tag, s := range strs {
+		entry(tag, rpmTypeString, len(data))
+		data = append(data, append([]byte(s), 0)...)
+	}
+	if epoch > 0 {
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+		entry(rpmTagEpoch, rpmTypeInt32, len(data))
+		data = binary.BigEndian.AppendUint32(data, epoch)
+	}
+
+	blob := binary.BigEndian.AppendUint32(nil, uint32(len(index)/16))
+	blob = binary.BigEndian.AppendUint32(blob, uint32(len(data)))
+	return append(append(blob, index...), data...)
+}
+
+func TestScan_RPM(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "etc/os-release", "ID=\"rhel\"\nVERSION_ID=\"9.4\"\n")
+	dbPath := filepath.Join(root, "usr/lib/sysimage/rpm/rpmdb.sqlite")
+	writeFile(t, root, "usr/lib/sysimage/rpm/rpmdb.sqlite", "")
+	writeFile(t, root, "var/lib/rpm/Packages", "berkeleydb")
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE Packages (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	for _, blob := range [][]byte{
+		rpmHeader(map[int]string{
+			rpmTagName: "openssl-libs", rpmTagVersion: "3.0.7", rpmTagRelease: "27.el9",
+			rpmTagArch: "x86_64", rpmTagLicense: "ASL 2.0", rpmTagVendor: "Red Hat, Inc.",
+			rpmTagSourceRPM: "openssl-3.0.7-27.el9.src.rpm",
+		}, 1),
+		rpmHeader(map[int]string{rpmTagName: "gpg-pubkey", rpmTagVersion: "fd431d51"}, 0),
+	} {
+		if _, err := db.Exec("INSERT INTO Packages (blob) VALUES (?)", blob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	inv, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(inv.Packages) != 1 {
+		t.Fatalf("Packages = %+v", inv.Packages)
+	}
+	p := inv.Packages[0]
+	if p.Name != "openssl-libs" || p.Version != "3.0.7-27.el9" || p.Epoch != "1" || p.Source != "openssl" || p.License != "ASL 2.0" {
+		t.Errorf("package = %+v", p)
+	}
+	if got, want := p.PURL(inv.Distro), "pkg:rpm/rhel/openssl-libs@3.0.7-27.el9?arch=x86_64&distro=rhel-9.4&epoch=1"; got != want {
+		t.Errorf("PURL() = %q, want %q", got, want)
+	}
+	if len(inv.Unsupported) != 1 || inv.Unsupported[0] != "var/lib/rpm/Packages" {
+		t.Errorf("Unsupported = %v", inv.Unsupported)
+	}
+}
+
+func TestScan_CorruptDatabase(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "var/lib/dpkg/status", "Package: tzdata\nStatus: install ok installed\nVersion: 2024a-0+deb12u1\n")
+	writeFile(t, root, "var/lib/rpm/rpmdb.sqlite", "not a sqlite database")
+
+	inv, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(inv.Packages) != 1 || inv.Packages[0].Name != "tzdata" {
+		t.Errorf("Packages = %+v, want the dpkg packages kept", inv.Packages)
+	}
+	if len(inv.Databases) != 1 || inv.Databases[0] != "var/lib/dpkg/status" {
+		t.Errorf("Databases = %v", inv.Databases)
+	}
+	if len(inv.Failed) != 1 || inv.Failed[0].Database != "var/lib/rpm/rpmdb.sqlite" || inv.Failed[0].Error == "" {
+		t.Errorf("Failed = %+v", inv.Failed)
+	}
+}
+
+func TestScan_SymlinksStayInRoot(t *testing.T) {
+	outside := t.TempDir()
+	writeFile(t, outside, "os-release", "ID=host\n")
+	writeFile(t, outside, "status", "Package: host-only\nVersion: 1.0\n")
+
+	root := t.TempDir()
+	writeFile(t, root, "usr/lib/os-release", "ID=wolfi\n")
+	for link, target := range map[string]string{
+		"etc/os-release":      filepath.Join(outside, "os-release"),
+		"var/lib/dpkg/status": "../../../../../../../../" + filepath.Join(outside, "status"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, link)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inv, err := Scan(root)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if inv.Distro == nil || inv.Distro.ID != "wolfi" {
+		t.Errorf("Distro = %+v, want the image's own os-release", inv.Distro)
+	}
+	if len(inv.Packages) != 0 {
+		t.Errorf("Packages = %+v, want none read from outside the root", inv.Packages)
+	}
+}
+
+func TestScan_Scratch(t *testing.T) {
+	inv, err := Scan(t.TempDir())
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if inv.Distro != nil || len(inv.Packages) != 0 {
+		t.Errorf("inventory = %+v, want empty", inv)
+	}
+}
+
+func TestSourceRPMName(t *testing.T) {
+	for srpm, want := range map[string]string{
+		"openssl-3.0.7-27.el9.src.rpm":      "openssl",
+		"python3-pip-21.2.3-8.el9.src.rpm":  "python3-pip",
+		"not-a-source-package-1.0-1.x86_64": "",
+		"":                                  "",
+	} {
+		if got := sourceRPMName(srpm); got != want {
+			t.Errorf("sourceRPMName(%q) = %q, want %q", srpm, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package ospkg
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// rpm header tags Zero reads
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagVendor    = 1011
+	rpmTagLicense   = 1014
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+)
+
+// rpm header data types
+const (
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// parseRPMDB reads the SQLite rpm database used since RHEL 9 and Fedora 33.
+// Each row of its Packages table holds one rpm header blob.
+func parseRPMDB(r *os.Root, path string) ([]Package, error) {
+	dbPath, err := resolveInRoot(r, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// immutable: the image is not a live system, so skip locking and the WAL
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT blob FROM Packages")
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer rows.Close()
+
+	var pkgs []Package
+	for rows.Next() {
+		var blob []byte
+		if err := rows.Scan(&blob); err != nil {
+			return nil, err
+		}
+		tags, err := parseRPMHeader(blob)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		// Imported signing keys are stored as pseudo-packages
+		if tags[rpmTagName] == "" || tags[rpmTagName] == "gpg-pubkey" {
+			continue
+		}
+		pkg := Package{
+			Type:       TypeRPM,
+			Name:       tags[rpmTagName],
+			Version:    tags[rpmTagVersion],
+			Epoch:      tags[rpmTagEpoch],
+			Arch:       tags[rpmTagArch],
+			License:    tags[rpmTagLicense],
+			Maintainer: tags[rpmTagVendor],
+			Database:   path,
+		}
+		if rel := tags[rpmTagRelease]; rel != "" {
+			pkg.Version += "-" + rel
+		}
+		pkg.Source = sourceRPMName(tags[rpmTagSourceRPM])
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, rows.Err()
+}
+
+// resolveInRoot returns the host path of a file in the root, for readers
+// that need a path rather than a file. Paths that resolve outside the root
+// are rejected.
+func resolveInRoot(r *os.Root, path string) (string, error) {
+	root, err := filepath.EvalSymlinks(r.Name())
+	if err != nil {
+		return "", err
+	}
+	p, err := filepath.EvalSymlinks(filepath.Join(root, path))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(p, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s resolves outside the image", path)
+	}
+	return p, nil
+}
+
+// parseRPMHeader decodes the tags Zero reads from a header blob: the index
+// entry count and data length (big-endian int32s), 16-byte index entries
+// of tag, type, offset and count, then the data store
+func parseRPMHeader(blob []byte) (map[int]string, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("truncated rpm header")
+	}
+	il := int(binary.BigEndian.Uint32(blob[0:4]))
+	dl := int(binary.BigEndian.Uint32(blob[4:8]))
+	if il < 0 || dl < 0 || il > len(blob)/16 || 8+il*16+dl > len(blob) {
+		return nil, errors.New("invalid rpm header")
+	}
+	data := blob[8+il*16 : 8+il*16+dl]
+
+	tags := make(map[int]string)
+	for i := 0; i < il; i++ {
+		e := blob[8+i*16 : 8+(i+1)*16]
+		tag := int(binary.BigEndian.Uint32(e[0:4]))
+		typ := binary.BigEndian.Uint32(e[4:8])
+		offset := int(binary.BigEndian.Uint32(e[8:12]))
+		if tag < rpmTagName || tag > rpmTagSourceRPM || offset < 0 || offset >= len(data) {
+			continue
+		}
+
+		switch typ {
+		case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+			// Arrays and translations: the first string
+			s := data[offset:]
+			if end := bytes.IndexByte(s, 0); end >= 0 {
+				s = s[:end]
+			}
+			tags[tag] = string(s)
+		case rpmTypeInt32:
+			if offset+4 <= len(data) {
+				tags[tag] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(data[offset:offset+4])), 10)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// sourceRPMName extracts the source package name from e.g.
+// openssl-3.0.7-27.el9.src.rpm
+func sourceRPMName(srpm string) string {
+	s := strings.TrimSuffix(srpm, ".src.rpm")
+	if s == srpm || s == "" {
+		return ""
+	}
+	// name-version-release: the name is everything before the last two dashes
+	for i := 0; i < 2; i++ {
+		idx := strings.LastIndex(s, "-")
+		if idx <= 0 {
+			return ""
+		}
+		s = s[:idx]
+	}
+	return s
+}
@@ -0,0 +1,241 @@
+package osvdb
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// importBatchSize is the number of records written per transaction
+const importBatchSize = 1000
+
+// ImportStats summarizes an import
+type ImportStats struct {
+	Imported  int `json:"imported"`  // New or updated records
+	Unchanged int `json:"unchanged"` // Records already present at the same or a newer modification time
+	Withdrawn int `json:"withdrawn"` // Withdrawn records removed from the database
+	Invalid   int `json:"invalid"`   // Files that are not OSV records
+}
+
+// record holds the fields of an OSV record needed to index it
+type record struct {
+	ID        string `json:"id"`
+	Modified  string `json:"modified"`
+	Withdrawn string `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+	} `json:"affected"`
+}
+
+// Import loads OSV records from a data dump: a zip archive such as
+// https://osv-vulnerabilities.storage.googleapis.com/PyPI/all.zip, a single
+// JSON record, or a directory containing JSON records and/or zip archives.
+// Records replace existing ones only when they are newer, so dumps can be
+// re-imported incrementally.
+func (d *DB) Import(ctx context.Context, from string) (*ImportStats, error) {
+	info, err := os.Stat(from)
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{db: d, ctx: ctx, stats: &ImportStats{}}
+	defer im.rollback()
+
+	if info.IsDir() {
+		err = filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			return im.importFile(path)
+		})
+	} else {
+		err = im.importFile(from)
+	}
+	if err != nil {
+		return im.stats, err
+	}
+
+	if err := im.commit(); err != nil {
+		return im.stats, err
+	}
+
+	if abs, err := filepath.Abs(from); err == nil {
+		from = abs
+	}
+	_, err = d.db.ExecContext(ctx, `INSERT OR REPLACE INTO metadata (key, value) VALUES ('last_import', ?), ('source', ?)`,
+		time.Now().UTC().Format(time.RFC3339), from)
+	if err != nil {
+		return im.stats, fmt.Errorf("saving import metadata: %w", err)
+	}
+
+	return im.stats, nil
+}
+
+type importer struct {
+	db      *DB
+	ctx     context.Context
+	tx      *sql.Tx
+	pending int
+	stats   *ImportStats
+}
+
+func (im *importer) importFile(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".zip":
+		return im.importZip(path)
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return im.importRecord(data)
+	}
+	return nil
+}
+
+func (im *importer) importZip(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s in %s: %w", f.Name, path, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("reading %s in %s: %w", f.Name, path, err)
+		}
+		if err := im.importRecord(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *importer) importRecord(data []byte) error {
+	if err := im.ctx.Err(); err != nil {
+		return err
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil || rec.ID == "" {
+		im.stats.Invalid++
+		return nil
+	}
+
+	if im.tx == nil {
+		tx, err := im.db.db.BeginTx(im.ctx, nil)
+		if err != nil {
+			return fmt.Errorf("starting transaction: %w", err)
+		}
+		im.tx = tx
+	}
+
+	if rec.Withdrawn != "" {
+		res, err := im.tx.ExecContext(im.ctx, `DELETE FROM vulnerabilities WHERE id = ?`, rec.ID)
+		if err != nil {
+			return fmt.Errorf("removing %s: %w", rec.ID, err)
+		}
+		if _, err := im.tx.ExecContext(im.ctx, `DELETE FROM affected_packages WHERE vuln_id = ?`, rec.ID); err != nil {
+			return fmt.Errorf("removing %s: %w", rec.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			im.stats.Withdrawn++
+		}
+		return im.flush()
+	}
+
+	var modified int64
+	if t, err := time.Parse(time.RFC3339Nano, rec.Modified); err == nil {
+		modified = t.UnixNano()
+	}
+	var existing int64
+	err := im.tx.QueryRowContext(im.ctx, `SELECT modified FROM vulnerabilities WHERE id = ?`, rec.ID).Scan(&existing)
+	if err == nil && existing >= modified {
+		im.stats.Unchanged++
+		return nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("reading %s: %w", rec.ID, err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		im.stats.Invalid++
+		return nil
+	}
+	if _, err := im.tx.ExecContext(im.ctx, `INSERT OR REPLACE INTO vulnerabilities (id, modified, record) VALUES (?, ?, ?)`,
+		rec.ID, modified, compact.Bytes()); err != nil {
+		return fmt.Errorf("writing %s: %w", rec.ID, err)
+	}
+	if _, err := im.tx.ExecContext(im.ctx, `DELETE FROM affected_packages WHERE vuln_id = ?`, rec.ID); err != nil {
+		return fmt.Errorf("writing %s: %w", rec.ID, err)
+	}
+	for _, a := range rec.Affected {
+		if a.Package.Name == "" || a.Package.Ecosystem == "" {
+			continue
+		}
+		eco := versions.ParseEcosystem(a.Package.Ecosystem)
+		if _, err := im.tx.ExecContext(im.ctx, `INSERT OR IGNORE INTO affected_packages (ecosystem, name, vuln_id) VALUES (?, ?, ?)`,
+			string(eco), packageKey(eco, a.Package.Name), rec.ID); err != nil {
+			return fmt.Errorf("writing %s: %w", rec.ID, err)
+		}
+	}
+
+	im.stats.Imported++
+	return im.flush()
+}
+
+// flush commits once a batch is full
+func (im *importer) flush() error {
+	im.pending++
+	if im.pending < importBatchSize {
+		return nil
+	}
+	return im.commit()
+}
+
+func (im *importer) commit() error {
+	if im.tx == nil {
+		return nil
+	}
+	err := im.tx.Commit()
+	im.tx = nil
+	im.pending = 0
+	if err != nil {
+		return fmt.Errorf("committing import: %w", err)
+	}
+	return nil
+}
+
+func (im *importer) rollback() {
+	if im.tx != nil {
+		_ = im.tx.Rollback()
+		im.tx = nil
+	}
+}
@@ -0,0 +1,23 @@
+package osvdb
+
+import (
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// Affects returns true if the vulnerability affects the given version of a
+// package
+func Affects(vuln *liveapi.Vulnerability, ecosystem, name, version string) bool {
+	eco := versions.ParseEcosystem(ecosystem)
+	key := packageKey(eco, name)
+	for _, affected := range vuln.Affected {
+		affectedEco := versions.ParseEcosystem(affected.Package.Ecosystem)
+		if affectedEco != eco || packageKey(affectedEco, affected.Package.Name) != key {
+			continue
+		}
+		if affected.Contains(version) {
+			return true
+		}
+	}
+	return false
+}
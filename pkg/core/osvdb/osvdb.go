@@ -0,0 +1,222 @@
+// Package osvdb provides an offline vulnerability database built from OSV
+// (https://osv.dev) data dumps, so vulnerability matching can run without
+// network access
+package osvdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS vulnerabilities (
+	id       TEXT PRIMARY KEY,
+	modified INTEGER NOT NULL,
+	record   BLOB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS affected_packages (
+	ecosystem TEXT NOT NULL,
+	name      TEXT NOT NULL,
+	vuln_id   TEXT NOT NULL,
+	PRIMARY KEY (ecosystem, name, vuln_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_affected_packages_vuln ON affected_packages(vuln_id);
+
+CREATE TABLE IF NOT EXISTS metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// DB is a local index of OSV vulnerability records
+type DB struct {
+	db   *sql.DB
+	path string
+}
+
+// Stats describes the contents of a database
+type Stats struct {
+	Vulnerabilities int                        `json:"vulnerabilities"`
+	Ecosystems      map[versions.Ecosystem]int `json:"ecosystems"` // vulnerabilities per ecosystem
+	LastImport      time.Time                  `json:"last_import,omitempty"`
+	Source          string                     `json:"source,omitempty"`
+}
+
+// DefaultPath returns the database location under a Zero home directory
+func DefaultPath(zeroHome string) string {
+	return filepath.Join(zeroHome, "feeds", "osv.db")
+}
+
+// Exists returns true if a database has been imported at path
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// Open opens the database at path, creating it if needed
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating db directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	db.SetMaxOpenConns(1) // SQLite only supports one writer
+	db.SetMaxIdleConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+
+	return &DB{db: db, path: path}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Path returns the database file path
+func (d *DB) Path() string {
+	return d.path
+}
+
+// Stats returns record counts and import metadata
+func (d *DB) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{Ecosystems: make(map[versions.Ecosystem]int)}
+
+	if err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vulnerabilities`).Scan(&stats.Vulnerabilities); err != nil {
+		return nil, fmt.Errorf("counting vulnerabilities: %w", err)
+	}
+
+	rows, err := d.db.QueryContext(ctx, `SELECT ecosystem, COUNT(DISTINCT vuln_id) FROM affected_packages GROUP BY ecosystem`)
+	if err != nil {
+		return nil, fmt.Errorf("counting ecosystems: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var eco string
+		var count int
+		if err := rows.Scan(&eco, &count); err != nil {
+			return nil, err
+		}
+		stats.Ecosystems[versions.Ecosystem(eco)] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if v, err := d.metadata(ctx, "last_import"); err == nil {
+		stats.LastImport, _ = time.Parse(time.RFC3339, v)
+	}
+	stats.Source, _ = d.metadata(ctx, "source")
+
+	return stats, nil
+}
+
+// Query returns the vulnerabilities affecting a package version. The
+// ecosystem may be an OSV ecosystem ("PyPI") or a purl type ("pypi");
+// versions are matched with the ecosystem's own ordering.
+func (d *DB) Query(ctx context.Context, ecosystem, name, version string) ([]liveapi.Vulnerability, error) {
+	eco := versions.ParseEcosystem(ecosystem)
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT v.record FROM affected_packages a
+		JOIN vulnerabilities v ON v.id = a.vuln_id
+		WHERE a.ecosystem = ? AND a.name = ?
+		ORDER BY v.id`, string(eco), packageKey(eco, name))
+	if err != nil {
+		return nil, fmt.Errorf("querying vulnerabilities: %w", err)
+	}
+	defer rows.Close()
+
+	var vulns []liveapi.Vulnerability
+	for rows.Next() {
+		var record []byte
+		if err := rows.Scan(&record); err != nil {
+			return nil, err
+		}
+		var vuln liveapi.Vulnerability
+		if err := json.Unmarshal(record, &vuln); err != nil {
+			return nil, fmt.Errorf("decoding vulnerability: %w", err)
+		}
+		if Affects(&vuln, ecosystem, name, version) {
+			vulns = append(vulns, vuln)
+		}
+	}
+	return vulns, rows.Err()
+}
+
+// Get returns a vulnerability by ID, or nil if it is not in the database
+func (d *DB) Get(ctx context.Context, id string) (*liveapi.Vulnerability, error) {
+	var record []byte
+	err := d.db.QueryRowContext(ctx, `SELECT record FROM vulnerabilities WHERE id = ?`, id).Scan(&record)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading vulnerability: %w", err)
+	}
+	var vuln liveapi.Vulnerability
+	if err := json.Unmarshal(record, &vuln); err != nil {
+		return nil, fmt.Errorf("decoding vulnerability: %w", err)
+	}
+	return &vuln, nil
+}
+
+func (d *DB) metadata(ctx context.Context, key string) (string, error) {
+	var value string
+	err := d.db.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = ?`, key).Scan(&value)
+	return value, err
+}
+
+var pep503Separators = regexp.MustCompile(`[-_.]+`)
+
+// packageKey normalizes a package name the way its registry does, so
+// lookups match however the SBOM spells it
+func packageKey(eco versions.Ecosystem, name string) string {
+	switch eco {
+	case versions.PyPI:
+		// PEP 503 normalization
+		return pep503Separators.ReplaceAllString(strings.ToLower(name), "-")
+	case versions.NuGet, versions.Packagist:
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// osvEcosystems maps ecosystems to the names OSV uses for them
+var osvEcosystems = map[versions.Ecosystem]string{
+	versions.NPM:       "npm",
+	versions.PyPI:      "PyPI",
+	versions.Go:        "Go",
+	versions.Maven:     "Maven",
+	versions.Cargo:     "crates.io",
+	versions.RubyGems:  "RubyGems",
+	versions.NuGet:     "NuGet",
+	versions.Packagist: "Packagist",
+	versions.Hex:       "Hex",
+	versions.Pub:       "Pub",
+}
+
+// EcosystemName returns the OSV name of an ecosystem (e.g. "pypi" -> "PyPI"),
+// or "" if OSV does not know it
+func EcosystemName(ecosystem string) string {
+	return osvEcosystems[versions.ParseEcosystem(ecosystem)]
+}
@@ -0,0 +1,140 @@
+package osvdb
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+const requestsRecord = `{
+  "id": "GHSA-j8r2-6x86-q33q",
+  "modified": "2024-01-01T00:00:00Z",
+  "summary": "Unintended leak of Proxy-Authorization header in requests",
+  "aliases": ["CVE-2023-32681"],
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "requests"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"fixed": "2.31.0"}]}]
+  }]
+}`
+
+const goRecord = `{
+  "id": "GO-2022-0969",
+  "modified": "2024-01-01T00:00:00.123Z",
+  "affected": [{
+    "package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.0.0-20220906165146-f3363e06e74c"}]}]
+  }]
+}`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportAndQuery(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "dump")
+	writeZip(t, filepath.Join(dump, "PyPI-all.zip"), map[string]string{
+		"GHSA-j8r2-6x86-q33q.json": requestsRecord,
+	})
+	writeFile(t, filepath.Join(dump, "go", "GO-2022-0969.json"), goRecord)
+	writeFile(t, filepath.Join(dump, "README.md"), "not a record")
+	writeFile(t, filepath.Join(dump, "broken.json"), "{")
+
+	db, err := Open(DefaultPath(filepath.Join(dir, "home")))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	stats, err := db.Import(ctx, dump)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if stats.Imported != 2 || stats.Invalid != 1 {
+		t.Errorf("Import() stats = %+v", stats)
+	}
+
+	// Names are matched the way the registry normalizes them
+	vulns, err := db.Query(ctx, "pypi", "Requests", "2.28.0")
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(vulns) != 1 || vulns[0].ID != "GHSA-j8r2-6x86-q33q" || vulns[0].Aliases[0] != "CVE-2023-32681" {
+		t.Errorf("Query() = %+v", vulns)
+	}
+	if vulns, _ := db.Query(ctx, "PyPI", "requests", "2.31.0"); len(vulns) != 0 {
+		t.Errorf("fixed version matched: %+v", vulns)
+	}
+	if vulns, _ := db.Query(ctx, "golang", "golang.org/x/net", "v0.0.0-20220722155237-a158d28d115b"); len(vulns) != 1 {
+		t.Errorf("Go pseudo-version not matched: %+v", vulns)
+	}
+	if vulns, _ := db.Query(ctx, "golang", "golang.org/x/net", "v0.1.0"); len(vulns) != 0 {
+		t.Errorf("Go release after fix matched: %+v", vulns)
+	}
+
+	s, err := db.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if s.Vulnerabilities != 2 || s.Ecosystems[versions.PyPI] != 1 || s.Ecosystems[versions.Go] != 1 || s.LastImport.IsZero() {
+		t.Errorf("Stats() = %+v", s)
+	}
+
+	// Re-importing is incremental; withdrawn records are removed
+	writeFile(t, filepath.Join(dir, "update", "GO-2022-0969.json"), `{"id": "GO-2022-0969", "modified": "2024-02-01T00:00:00Z", "withdrawn": "2024-02-01T00:00:00Z"}`)
+	writeFile(t, filepath.Join(dir, "update", "GHSA-j8r2-6x86-q33q.json"), requestsRecord)
+	stats, err = db.Import(ctx, filepath.Join(dir, "update"))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if stats.Imported != 0 || stats.Unchanged != 1 || stats.Withdrawn != 1 {
+		t.Errorf("re-Import() stats = %+v", stats)
+	}
+	if vuln, _ := db.Get(ctx, "GO-2022-0969"); vuln != nil {
+		t.Errorf("withdrawn record still present")
+	}
+}
+
+func TestEcosystemName(t *testing.T) {
+	for in, want := range map[string]string{"pypi": "PyPI", "cargo": "crates.io", "golang": "Go", "gem": "RubyGems", "unknown": ""} {
+		if got := EcosystemName(in); got != want {
+			t.Errorf("EcosystemName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
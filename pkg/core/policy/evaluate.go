@@ -0,0 +1,200 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/findings"
+	"github.com/crashappsec/zero/pkg/core/suppression"
+)
+
+// Status is the outcome of a single rule
+type Status string
+
+const (
+	StatusPass    Status = "pass"
+	StatusFail    Status = "fail"    // Failed a blocking rule
+	StatusWarn    Status = "warn"    // Failed a warn-level rule
+	StatusSkipped Status = "skipped" // Rule is off, or its scanner has no results and missing results are allowed
+)
+
+// RuleResult is the outcome of evaluating one rule
+type RuleResult struct {
+	ID          string             `json:"id"`
+	Description string             `json:"description,omitempty"`
+	Level       Level              `json:"level"`
+	Status      Status             `json:"status"`
+	Message     string             `json:"message"`
+	Violations  []findings.Finding `json:"violations,omitempty"`
+	Suppressed  int                `json:"suppressed,omitempty"`
+}
+
+// Report is the outcome of evaluating a policy against one project
+type Report struct {
+	Repo    string       `json:"repo"`
+	Passed  bool         `json:"passed"`
+	Blocked int          `json:"blocked"`
+	Warned  int          `json:"warned"`
+	Rules   []RuleResult `json:"rules"`
+}
+
+// Evaluator evaluates policies over scanner outputs
+type Evaluator struct {
+	// Suppression removes findings marked as false positives and
+	// low-severity findings in tests, examples and docs (optional)
+	Suppression *suppression.Service
+
+	// AllowMissingResults skips rules whose scanner has no results. By
+	// default a blocking rule without results fails, so a scanner that
+	// crashed or was never run cannot pass the gate.
+	AllowMissingResults bool
+}
+
+// NewEvaluator creates an evaluator that applies suppressions from svc
+func NewEvaluator(svc *suppression.Service) *Evaluator {
+	return &Evaluator{Suppression: svc}
+}
+
+// Evaluate runs every rule that applies to repo against the scanner outputs
+// in analysisDir
+func (e *Evaluator) Evaluate(p *Policy, repo, analysisDir string) (*Report, error) {
+	report := &Report{Repo: repo, Passed: true}
+	docs := make(map[string]map[string]interface{})
+
+	load := func(scanner string) (map[string]interface{}, error) {
+		if doc, ok := docs[scanner]; ok {
+			return doc, nil
+		}
+		doc, err := readOutput(analysisDir, scanner)
+		if err != nil {
+			return nil, err
+		}
+		docs[scanner] = doc
+		return doc, nil
+	}
+
+	for _, rule := range p.ForRepo(repo) {
+		result := RuleResult{ID: rule.ID, Description: rule.Description, Level: rule.Level}
+
+		var failed bool
+		var err error
+		switch {
+		case rule.Level == LevelOff:
+			result.Status = StatusSkipped
+			result.Message = "disabled"
+			report.Rules = append(report.Rules, result)
+			continue
+		case rule.Findings != nil:
+			failed, err = e.evalFindings(rule.Findings, repo, load, &result)
+		default:
+			failed, err = evalMetric(rule.Metric, load, &result)
+		}
+
+		switch {
+		case err == errNoResults && (e.AllowMissingResults || rule.Level == LevelWarn):
+			result.Status = StatusSkipped
+			result.Message = "no " + ruleScanner(rule) + " results"
+		case err == errNoResults:
+			result.Status = StatusFail
+			result.Message = "no " + ruleScanner(rule) + " results (run the scanner, or pass --allow-missing-results)"
+			report.Blocked++
+			report.Passed = false
+		case err != nil:
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		case !failed:
+			result.Status = StatusPass
+		case rule.Level == LevelWarn:
+			result.Status = StatusWarn
+			report.Warned++
+		default:
+			result.Status = StatusFail
+			report.Blocked++
+			report.Passed = false
+		}
+		report.Rules = append(report.Rules, result)
+	}
+	return report, nil
+}
+
+func (e *Evaluator) evalFindings(rule *FindingRule, repo string, load func(string) (map[string]interface{}, error), result *RuleResult) (bool, error) {
+	scanner, feature, _ := splitSource(rule.Source)
+	doc, err := load(scanner)
+	if err != nil {
+		return false, err
+	}
+	all, err := loadFindings(doc, scanner, feature, repo)
+	if err != nil {
+		return false, err
+	}
+
+	// Findings without a severity (e.g. licenses) parse as info, so info
+	// is included unless the rule sets a minimum
+	opts := findings.FilterOptions{Categories: rule.Categories, IncludeInfo: true}
+	if rule.MinSeverity != "" {
+		opts.MinSeverity = findings.ParseSeverity(rule.MinSeverity)
+	}
+	matched := findings.Filter(all, opts)
+
+	var violations []findings.Finding
+	for _, f := range matched {
+		if matchWhere(f.Metadata, rule.Where) {
+			violations = append(violations, f)
+		}
+	}
+
+	if e.Suppression != nil && len(violations) > 0 {
+		var sr *suppression.Result
+		violations, sr = e.Suppression.FilterFindings(violations, repo)
+		result.Suppressed = sr.Suppressed
+	}
+
+	result.Violations = findings.SortBySeverity(violations)
+	result.Message = fmt.Sprintf("%d matching %s", len(violations), plural(len(violations), "finding"))
+	if rule.Max > 0 {
+		result.Message += fmt.Sprintf(" (max %d)", rule.Max)
+	}
+	return len(violations) > rule.Max, nil
+}
+
+func evalMetric(rule *MetricRule, load func(string) (map[string]interface{}, error), result *RuleResult) (bool, error) {
+	doc, err := load(rule.Scanner)
+	if err != nil {
+		return false, err
+	}
+	v, ok := lookup(doc, rule.Path)
+	if !ok {
+		return false, errNoResults
+	}
+	n, ok := toNumber(v)
+	if !ok {
+		return false, fmt.Errorf("%s.%s is not a number", rule.Scanner, rule.Path)
+	}
+
+	var bounds []string
+	failed := false
+	if rule.Min != nil {
+		bounds = append(bounds, fmt.Sprintf("min %g", *rule.Min))
+		failed = failed || n < *rule.Min
+	}
+	if rule.Max != nil {
+		bounds = append(bounds, fmt.Sprintf("max %g", *rule.Max))
+		failed = failed || n > *rule.Max
+	}
+	result.Message = fmt.Sprintf("%s = %g (%s)", rule.Path, n, strings.Join(bounds, ", "))
+	return failed, nil
+}
+
+func ruleScanner(r Rule) string {
+	if r.Metric != nil {
+		return r.Metric.Scanner
+	}
+	scanner, _, _ := splitSource(r.Findings.Source)
+	return scanner
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
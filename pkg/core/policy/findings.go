@@ -0,0 +1,288 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/findings"
+)
+
+// errNoResults is returned when a scanner's output does not exist
+var errNoResults = fmt.Errorf("no results")
+
+// readOutput reads <scanner>.json from analysisDir as a generic document
+func readOutput(analysisDir, scanner string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(analysisDir, scanner+".json"))
+	if os.IsNotExist(err) {
+		return nil, errNoResults
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s.json: %w", scanner, err)
+	}
+	return doc, nil
+}
+
+// lookup walks a dotted path through nested objects
+func lookup(doc map[string]interface{}, dotted string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, key := range strings.Split(dotted, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// loadFindings converts the findings list at findings.<feature> in a
+// scanner's output to standard findings. The raw fields are kept in
+// Metadata so rules can match on scanner-specific fields (fixed_in,
+// licenses, rule_id, ...).
+func loadFindings(doc map[string]interface{}, scanner, feature, repo string) ([]findings.Finding, error) {
+	v, ok := lookup(doc, "findings."+feature)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not a list of findings", scanner, feature)
+	}
+
+	// License findings carry no dependency scope; take it from the SBOM
+	// so rules can single out production dependencies
+	var scopes map[string]string
+	if scanner == "code-packages" && feature == "licenses" {
+		scopes = componentScopes(doc)
+	}
+
+	owner, name, _ := strings.Cut(repo, "/")
+	result := make([]findings.Finding, 0, len(items))
+	for i, item := range items {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if scopes != nil {
+			if _, ok := raw["scope"]; !ok {
+				if scope, ok := scopes[str(raw, "package")+"@"+str(raw, "version")]; ok {
+					raw["scope"] = scope
+				}
+			}
+		}
+		result = append(result, toFinding(raw, scanner, feature, owner, name, i))
+	}
+	return result, nil
+}
+
+// toFinding maps a raw scanner finding to the standard finding type
+func toFinding(raw map[string]interface{}, scanner, feature, owner, repo string, index int) findings.Finding {
+	f := findings.Finding{
+		ID:       firstNonEmpty(str(raw, "id"), str(raw, "rule_id"), str(raw, "vuln_id"), fmt.Sprintf("%s/%d", feature, index)),
+		Title:    firstNonEmpty(str(raw, "title"), str(raw, "message"), str(raw, "description"), str(raw, "type")),
+		Severity: findings.ParseSeverity(firstNonEmpty(str(raw, "severity"), str(raw, "risk_level"), str(raw, "risk"))),
+		Category: firstNonEmpty(str(raw, "category"), feature),
+		Scanner:  scanner,
+		Metadata: raw,
+	}
+	if f.Title == "" {
+		f.Title = describePackage(raw)
+	}
+
+	file := firstNonEmpty(str(raw, "file"), str(raw, "file_path"), str(raw, "path"))
+	line, _ := raw["line"].(float64)
+	if file != "" {
+		f.Location = &findings.Location{File: file, Line: int(line), Snippet: str(raw, "snippet")}
+	}
+
+	// Keep the scanner's evidence (and its fingerprint) when present, so
+	// feedback recorded against it suppresses the finding
+	if ev, ok := raw["evidence"].(map[string]interface{}); ok {
+		data, _ := json.Marshal(ev)
+		var evidence findings.Evidence
+		if json.Unmarshal(data, &evidence) == nil {
+			f.Evidence = &evidence
+		}
+	}
+	if f.Evidence == nil && file != "" {
+		f.Evidence = findings.NewEvidence(owner, repo, file, f.ID).WithMatch(str(raw, "snippet"), int(line), int(line))
+	}
+	return f
+}
+
+// componentScopes maps name@version to the CycloneDX scope of each SBOM
+// component. Components without a scope are required, as in CycloneDX.
+func componentScopes(doc map[string]interface{}) map[string]string {
+	scopes := make(map[string]string)
+	v, _ := lookup(doc, "findings.generation.components")
+	components, _ := v.([]interface{})
+	for _, c := range components {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		scope := str(m, "scope")
+		if scope == "" {
+			scope = "required"
+		}
+		scopes[str(m, "name")+"@"+str(m, "version")] = scope
+	}
+	return scopes
+}
+
+// matchWhere reports whether a raw finding satisfies every condition.
+//
+// A condition value is matched against the field as follows:
+//   - string: case-insensitive glob (e.g. "GPL-3.0*")
+//   - number or bool: equality
+//   - list: any element matches
+//   - map: operators exists (bool), not (value or list), gte and lte (numbers)
+//
+// List-valued fields (e.g. licenses) match when any element matches.
+func matchWhere(raw map[string]interface{}, where map[string]interface{}) bool {
+	for field, cond := range where {
+		value, present := raw[field]
+		if value == nil {
+			present = false
+		}
+		if !matchCondition(value, present, cond) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCondition(value interface{}, present bool, cond interface{}) bool {
+	switch c := cond.(type) {
+	case map[string]interface{}:
+		for op, arg := range c {
+			switch op {
+			case "exists":
+				want, _ := arg.(bool)
+				if present && isEmpty(value) {
+					present = false
+				}
+				if present != want {
+					return false
+				}
+			case "not":
+				if present && matchCondition(value, present, arg) {
+					return false
+				}
+			case "gte", "lte":
+				n, ok := toNumber(value)
+				limit, lok := toNumber(arg)
+				if !present || !ok || !lok {
+					return false
+				}
+				if (op == "gte" && n < limit) || (op == "lte" && n > limit) {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		for _, alt := range c {
+			if matchCondition(value, present, alt) {
+				return true
+			}
+		}
+		return false
+	default:
+		if !present {
+			return false
+		}
+		return matchValue(value, cond)
+	}
+}
+
+// matchValue matches a field value (possibly a list) against a scalar
+func matchValue(value, want interface{}) bool {
+	if list, ok := value.([]interface{}); ok {
+		for _, v := range list {
+			if matchValue(v, want) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if pattern, ok := want.(string); ok {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprint(value) == pattern
+		}
+		matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+		return err == nil && matched
+	}
+	if n, ok := toNumber(want); ok {
+		v, vok := toNumber(value)
+		return vok && v == n
+	}
+	return value == want
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isEmpty(v interface{}) bool {
+	switch x := v.(type) {
+	case string:
+		return x == ""
+	case []interface{}:
+		return len(x) == 0
+	case map[string]interface{}:
+		return len(x) == 0
+	}
+	return false
+}
+
+func describePackage(raw map[string]interface{}) string {
+	pkg := str(raw, "package")
+	if pkg == "" {
+		return ""
+	}
+	if version := str(raw, "version"); version != "" {
+		return pkg + "@" + version
+	}
+	return pkg
+}
+
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,225 @@
+// Package policy provides policy-as-code gates evaluated over scan results
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Level controls what a failing rule does to the gate
+type Level string
+
+const (
+	LevelBlock Level = "block" // Failing rule fails the gate
+	LevelWarn  Level = "warn"  // Failing rule is reported but does not fail the gate
+	LevelOff   Level = "off"   // Rule is not evaluated
+)
+
+// Policy is a set of rules evaluated over a project's analysis results
+type Policy struct {
+	Version   int        `yaml:"version" json:"version"`
+	Rules     []Rule     `yaml:"rules" json:"rules"`
+	Overrides []Override `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+// Rule is a single policy check. Exactly one of Findings or Metric is set.
+type Rule struct {
+	ID          string       `yaml:"id" json:"id"`
+	Description string       `yaml:"description,omitempty" json:"description,omitempty"`
+	Level       Level        `yaml:"level,omitempty" json:"level,omitempty"` // default: block
+	Findings    *FindingRule `yaml:"findings,omitempty" json:"findings,omitempty"`
+	Metric      *MetricRule  `yaml:"metric,omitempty" json:"metric,omitempty"`
+}
+
+// FindingRule fails when more than Max findings from Source match
+type FindingRule struct {
+	// Source is "<scanner>.<feature>", e.g. code-packages.vulns or devops.github_actions
+	Source      string                 `yaml:"source" json:"source"`
+	MinSeverity string                 `yaml:"min_severity,omitempty" json:"min_severity,omitempty"`
+	Categories  []string               `yaml:"categories,omitempty" json:"categories,omitempty"`
+	Where       map[string]interface{} `yaml:"where,omitempty" json:"where,omitempty"`
+	Max         int                    `yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+// MetricRule fails when a numeric value in a scanner's output is out of bounds
+type MetricRule struct {
+	Scanner string   `yaml:"scanner" json:"scanner"`
+	Path    string   `yaml:"path" json:"path"` // Dotted path, e.g. summary.bus_factor
+	Min     *float64 `yaml:"min,omitempty" json:"min,omitempty"`
+	Max     *float64 `yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+// Override adjusts rules for repositories matching Repos (glob patterns on owner/repo)
+type Override struct {
+	Repos []string                `yaml:"repos" json:"repos"`
+	Rules map[string]RuleOverride `yaml:"rules" json:"rules"`
+}
+
+// RuleOverride replaces a rule's level or finding threshold
+type RuleOverride struct {
+	Level Level `yaml:"level,omitempty" json:"level,omitempty"`
+	Max   *int  `yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+// Load reads and validates a policy file
+func Load(filePath string) (*Policy, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return p, nil
+}
+
+// Parse parses and validates a YAML policy
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks the policy for structural errors
+func (p *Policy) Validate() error {
+	if p.Version > 1 {
+		return fmt.Errorf("unsupported policy version %d", p.Version)
+	}
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("policy has no rules")
+	}
+
+	ids := make(map[string]bool)
+	for i, r := range p.Rules {
+		if r.ID == "" {
+			return fmt.Errorf("rule %d: missing id", i+1)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("rule %s: duplicate id", r.ID)
+		}
+		ids[r.ID] = true
+
+		if !validLevel(r.Level) {
+			return fmt.Errorf("rule %s: invalid level %q (use block, warn or off)", r.ID, r.Level)
+		}
+		if (r.Findings == nil) == (r.Metric == nil) {
+			return fmt.Errorf("rule %s: set exactly one of findings or metric", r.ID)
+		}
+		if f := r.Findings; f != nil {
+			if _, _, ok := splitSource(f.Source); !ok {
+				return fmt.Errorf("rule %s: source must be <scanner>.<feature>, got %q", r.ID, f.Source)
+			}
+			if f.MinSeverity != "" && !validSeverity(f.MinSeverity) {
+				return fmt.Errorf("rule %s: invalid min_severity %q", r.ID, f.MinSeverity)
+			}
+			if f.Max < 0 {
+				return fmt.Errorf("rule %s: max must not be negative", r.ID)
+			}
+		}
+		if m := r.Metric; m != nil {
+			if m.Scanner == "" || m.Path == "" {
+				return fmt.Errorf("rule %s: metric needs scanner and path", r.ID)
+			}
+			if m.Min == nil && m.Max == nil {
+				return fmt.Errorf("rule %s: metric needs min or max", r.ID)
+			}
+		}
+	}
+
+	for _, o := range p.Overrides {
+		if len(o.Repos) == 0 {
+			return fmt.Errorf("override without repos")
+		}
+		for _, pattern := range o.Repos {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("override: invalid repo pattern %q", pattern)
+			}
+		}
+		for id, ro := range o.Rules {
+			if !ids[id] {
+				return fmt.Errorf("override: unknown rule %s", id)
+			}
+			if !validLevel(ro.Level) {
+				return fmt.Errorf("override for %s: invalid level %q", id, ro.Level)
+			}
+		}
+	}
+	return nil
+}
+
+// ForRepo returns the rules that apply to repo (owner/repo) with overrides
+// applied in order, so later overrides win
+func (p *Policy) ForRepo(repo string) []Rule {
+	rules := make([]Rule, len(p.Rules))
+	for i, r := range p.Rules {
+		if r.Level == "" {
+			r.Level = LevelBlock
+		}
+		if r.Findings != nil {
+			f := *r.Findings
+			r.Findings = &f
+		}
+		rules[i] = r
+	}
+
+	for _, o := range p.Overrides {
+		if !matchesRepo(o.Repos, repo) {
+			continue
+		}
+		for i := range rules {
+			ro, ok := o.Rules[rules[i].ID]
+			if !ok {
+				continue
+			}
+			if ro.Level != "" {
+				rules[i].Level = ro.Level
+			}
+			if ro.Max != nil && rules[i].Findings != nil {
+				rules[i].Findings.Max = *ro.Max
+			}
+		}
+	}
+	return rules
+}
+
+func matchesRepo(patterns []string, repo string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repo); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSource splits "<scanner>.<feature>" at the first dot. Scanner names
+// contain dashes but never dots.
+func splitSource(source string) (scanner, feature string, ok bool) {
+	scanner, feature, ok = strings.Cut(source, ".")
+	return scanner, feature, ok && scanner != "" && feature != ""
+}
+
+func validLevel(l Level) bool {
+	switch l {
+	case "", LevelBlock, LevelWarn, LevelOff:
+		return true
+	}
+	return false
+}
+
+func validSeverity(s string) bool {
+	switch strings.ToLower(s) {
+	case "critical", "high", "medium", "low", "info":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/feedback"
+	"github.com/crashappsec/zero/pkg/core/findings"
+	"github.com/crashappsec/zero/pkg/core/suppression"
+)
+
+const testPolicy = `
+version: 1
+rules:
+  - id: no-fixable-critical-vulns
+    findings:
+      source: code-packages.vulns
+      min_severity: critical
+      where:
+        fixed_in: {exists: true}
+  - id: no-unpinned-actions
+    findings:
+      source: devops.github_actions
+      categories: [unpinned-action]
+  - id: no-gpl3-in-production
+    findings:
+      source: code-packages.licenses
+      where:
+        licenses: "GPL-3.0*"
+        scope: required
+  - id: bus-factor
+    level: warn
+    metric:
+      scanner: code-ownership
+      path: summary.bus_factor
+      min: 2
+  - id: no-verified-secrets
+    findings:
+      source: code-security.secrets
+      where:
+        verified: true
+overrides:
+  - repos: ["acme/legacy-*"]
+    rules:
+      no-unpinned-actions: {level: warn}
+      no-gpl3-in-production: {max: 5}
+`
+
+func writeAnalysis(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+var testAnalysis = map[string]string{
+	"code-packages": `{"findings": {
+		"generation": {"components": [
+			{"name": "readline", "version": "8.0", "scope": "required"},
+			{"name": "gpl-test-helper", "version": "1.0", "scope": "optional"},
+			{"name": "mit-lib", "version": "2.0"}
+		]},
+		"vulns": [
+			{"id": "CVE-2024-1", "package": "a", "version": "1.0", "severity": "critical", "fixed_in": "1.1"},
+			{"id": "CVE-2024-2", "package": "b", "version": "1.0", "severity": "critical"},
+			{"id": "CVE-2024-3", "package": "c", "version": "1.0", "severity": "high", "fixed_in": "2.0"}
+		],
+		"licenses": [
+			{"package": "readline", "version": "8.0", "licenses": ["GPL-3.0-only"], "status": "denied"},
+			{"package": "gpl-test-helper", "version": "1.0", "licenses": ["GPL-3.0-or-later"], "status": "denied"},
+			{"package": "mit-lib", "version": "2.0", "licenses": ["MIT"], "status": "allowed"}
+		]
+	}}`,
+	"devops": `{"findings": {"github_actions": [
+		{"rule_id": "unpinned-action", "category": "unpinned-action", "severity": "high", "title": "Action not pinned to SHA", "file": ".github/workflows/ci.yml", "line": 12},
+		{"rule_id": "injection-risk", "category": "injection-risk", "severity": "critical", "title": "Potential command injection", "file": ".github/workflows/ci.yml", "line": 20}
+	]}}`,
+	"code-ownership": `{"summary": {"bus_factor": 1}}`,
+}
+
+func TestParseValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{"no rules", "version: 1\nrules: []", "no rules"},
+		{"missing id", "rules:\n  - metric: {scanner: x, path: y, min: 1}", "missing id"},
+		{"both kinds", "rules:\n  - id: a\n    metric: {scanner: x, path: y, min: 1}\n    findings: {source: x.y}", "exactly one"},
+		{"bad source", "rules:\n  - id: a\n    findings: {source: devops}", "<scanner>.<feature>"},
+		{"bad level", "rules:\n  - id: a\n    level: fatal\n    findings: {source: x.y}", "invalid level"},
+		{"bad severity", "rules:\n  - id: a\n    findings: {source: x.y, min_severity: severe}", "min_severity"},
+		{"metric without bounds", "rules:\n  - id: a\n    metric: {scanner: x, path: y}", "min or max"},
+		{"override unknown rule", "rules:\n  - id: a\n    findings: {source: x.y}\noverrides:\n  - repos: ['*']\n    rules: {b: {level: warn}}", "unknown rule"},
+		{"duplicate id", "rules:\n  - id: a\n    findings: {source: x.y}\n  - id: a\n    findings: {source: x.z}", "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.policy))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Parse([]byte(testPolicy)); err != nil {
+		t.Errorf("Parse(testPolicy) error = %v", err)
+	}
+}
+
+func TestForRepoOverrides(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byID := func(rules []Rule) map[string]Rule {
+		m := make(map[string]Rule)
+		for _, r := range rules {
+			m[r.ID] = r
+		}
+		return m
+	}
+
+	rules := byID(p.ForRepo("acme/api"))
+	if rules["no-unpinned-actions"].Level != LevelBlock {
+		t.Errorf("default level = %q, want block", rules["no-unpinned-actions"].Level)
+	}
+
+	legacy := byID(p.ForRepo("acme/legacy-billing"))
+	if legacy["no-unpinned-actions"].Level != LevelWarn {
+		t.Errorf("override level = %q, want warn", legacy["no-unpinned-actions"].Level)
+	}
+	if legacy["no-gpl3-in-production"].Findings.Max != 5 {
+		t.Errorf("override max = %d, want 5", legacy["no-gpl3-in-production"].Findings.Max)
+	}
+
+	// Overrides must not leak into the shared policy
+	if p.Rules[2].Findings.Max != 0 {
+		t.Error("ForRepo modified the policy")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeAnalysis(t, testAnalysis)
+
+	report, err := NewEvaluator(nil).Evaluate(p, "acme/api", dir)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	want := map[string]struct {
+		status     Status
+		violations int
+	}{
+		"no-fixable-critical-vulns": {StatusFail, 1},
+		"no-unpinned-actions":       {StatusFail, 1},
+		"no-gpl3-in-production":     {StatusFail, 1},
+		"bus-factor":                {StatusWarn, 0},
+		"no-verified-secrets":       {StatusFail, 0}, // No code-security results
+	}
+	for _, r := range report.Rules {
+		w := want[r.ID]
+		if r.Status != w.status || len(r.Violations) != w.violations {
+			t.Errorf("%s: status = %s, violations = %d, want %s, %d (%s)", r.ID, r.Status, len(r.Violations), w.status, w.violations, r.Message)
+		}
+	}
+
+	if got := report.Rules[0].Violations[0].ID; got != "CVE-2024-1" {
+		t.Errorf("fixable critical vuln = %s, want CVE-2024-1", got)
+	}
+	if got := report.Rules[2].Violations[0].Title; got != "readline@8.0" {
+		t.Errorf("GPL violation = %s, want readline@8.0 (optional dependency excluded)", got)
+	}
+	if report.Passed || report.Blocked != 4 || report.Warned != 1 {
+		t.Errorf("report passed = %v, blocked = %d, warned = %d, want false, 4, 1", report.Passed, report.Blocked, report.Warned)
+	}
+
+	// Legacy repos only warn on unpinned actions and allow a few GPL deps,
+	// and rules without results are skipped when missing results are allowed
+	evaluator := NewEvaluator(nil)
+	evaluator.AllowMissingResults = true
+	report, err = evaluator.Evaluate(p, "acme/legacy-app", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Blocked != 1 || report.Warned != 2 {
+		t.Errorf("legacy: blocked = %d, warned = %d, want 1, 2", report.Blocked, report.Warned)
+	}
+	if r := report.Rules[len(report.Rules)-1]; r.ID != "no-verified-secrets" || r.Status != StatusSkipped {
+		t.Errorf("missing results allowed: %s = %s", r.ID, r.Status)
+	}
+}
+
+func TestEvaluateSuppression(t *testing.T) {
+	p, err := Parse([]byte(`
+rules:
+  - id: no-high-code-vulns
+    findings:
+      source: code-security.vulns
+      min_severity: medium
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeAnalysis(t, map[string]string{
+		"code-security": `{"findings": {"vulns": [
+			{"rule_id": "sqli", "severity": "high", "title": "SQL injection", "file": "db/query.go", "line": 10},
+			{"rule_id": "weak-rand", "severity": "medium", "title": "Weak random", "file": "db/query_test.go", "line": 5},
+			{"rule_id": "xss", "severity": "high", "title": "XSS", "file": "web/render.go", "line": 3,
+			 "evidence": {"file_path": "web/render.go", "rule_id": "xss", "fingerprint": "fp-xss"}}
+		]}}`,
+	})
+
+	zeroHome := t.TempDir()
+	storage := feedback.NewStorage(zeroHome)
+	if err := storage.AddFeedback(feedback.NewFeedback(&findings.Evidence{Fingerprint: "fp-xss"}, feedback.VerdictFalsePositive, "escaped by template")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := NewEvaluator(suppression.NewService(storage)).Evaluate(p, "acme/api", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := report.Rules[0]
+	if len(r.Violations) != 1 || r.Violations[0].Title != "SQL injection" {
+		t.Errorf("violations = %+v, want only the SQL injection", r.Violations)
+	}
+	if r.Suppressed != 2 {
+		t.Errorf("suppressed = %d, want 2 (test file and false positive)", r.Suppressed)
+	}
+}
+
+func TestMatchWhere(t *testing.T) {
+	raw := map[string]interface{}{
+		"severity": "high",
+		"licenses": []interface{}{"MIT", "GPL-2.0-only"},
+		"score":    7.5,
+		"fixed_in": "",
+		"in_kev":   true,
+	}
+	tests := []struct {
+		name  string
+		where map[string]interface{}
+		want  bool
+	}{
+		{"glob case-insensitive", map[string]interface{}{"severity": "HI*"}, true},
+		{"list field any element", map[string]interface{}{"licenses": "GPL-*"}, true},
+		{"list condition", map[string]interface{}{"severity": []interface{}{"critical", "high"}}, true},
+		{"bool", map[string]interface{}{"in_kev": true}, true},
+		{"empty value does not exist", map[string]interface{}{"fixed_in": map[string]interface{}{"exists": true}}, false},
+		{"missing field", map[string]interface{}{"verified": true}, false},
+		{"not", map[string]interface{}{"severity": map[string]interface{}{"not": "low"}}, true},
+		{"gte", map[string]interface{}{"score": map[string]interface{}{"gte": 7}}, true},
+		{"lte", map[string]interface{}{"score": map[string]interface{}{"lte": 7}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchWhere(raw, tt.where); got != tt.want {
+				t.Errorf("matchWhere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
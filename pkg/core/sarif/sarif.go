@@ -4,12 +4,17 @@
 package sarif
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/crashappsec/zero/pkg/workflow/diff"
 )
 
 const (
@@ -73,6 +78,7 @@ type Result struct {
 	Message   Message    `json:"message"`
 	Locations []Location `json:"locations,omitempty"`
 	Fixes     []Fix      `json:"fixes,omitempty"`
+	Rank      float64    `json:"rank,omitempty"` // Priority, 0.0-100.0
 	// Additional properties
 	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
 	Properties          map[string]any    `json:"properties,omitempty"`
@@ -250,10 +256,26 @@ func SeverityToLevel(severity string) string {
 	}
 }
 
+// Partial fingerprint keys attached to every result that diff can fingerprint.
+// Code scanning UIs use these to deduplicate alerts across runs even when the
+// finding moves to a different line.
+const (
+	// FingerprintPrimaryKey identifies a finding independent of its location
+	FingerprintPrimaryKey = "zeroPrimaryKey/v1"
+	// FingerprintContentHash identifies a finding by its content
+	FingerprintContentHash = "zeroContentHash/v1"
+)
+
+const (
+	toolVersion = "1.0.0"
+	toolInfoURI = "https://github.com/crashappsec/zero"
+)
+
 // Exporter converts Zero scan results to SARIF format
 type Exporter struct {
 	analysisDir string
 	repoPath    string
+	fpGen       *diff.FingerprintGenerator
 }
 
 // NewExporter creates a new SARIF exporter
@@ -261,6 +283,7 @@ func NewExporter(analysisDir, repoPath string) *Exporter {
 	return &Exporter{
 		analysisDir: analysisDir,
 		repoPath:    repoPath,
+		fpGen:       diff.NewFingerprintGenerator(),
 	}
 }
 
@@ -270,37 +293,58 @@ func (e *Exporter) Export() (*Log, error) {
 
 	// Export each scanner's results (continue even if one scanner fails)
 	_ = e.exportCodeSecurity(log)
+	_ = e.exportGitHistorySecrets(log)
 	_ = e.exportPackageVulns(log)
 	_ = e.exportCrypto(log)
 	_ = e.exportCodeQuality(log)
+	_ = e.exportDevOps(log)
+	_ = e.exportAISecurity(log)
 
 	return log, nil
 }
 
+// readAnalysis reads the first analysis file that exists from the given names
+func (e *Exporter) readAnalysis(names ...string) ([]byte, string, bool) {
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(e.analysisDir, name+".json"))
+		if err == nil {
+			return data, name, true
+		}
+	}
+	return nil, "", false
+}
+
+// setFingerprints attaches diff fingerprints to the last result added to the run
+func (e *Exporter) setFingerprints(run *Run, scanner, feature string, raw json.RawMessage) {
+	if len(run.Results) == 0 {
+		return
+	}
+	fp, ok := e.fpGen.FingerprintFinding(scanner, feature, raw)
+	if !ok {
+		return
+	}
+
+	h := sha256.Sum256([]byte(fp.Scanner + "\x00" + fp.PrimaryKey))
+	fingerprints := map[string]string{
+		FingerprintPrimaryKey: hex.EncodeToString(h[:])[:32],
+	}
+	if fp.ContentHash != "" {
+		fingerprints[FingerprintContentHash] = fp.ContentHash
+	}
+	run.Results[len(run.Results)-1].PartialFingerprints = fingerprints
+}
+
 // exportCodeSecurity exports code-security scanner results
 func (e *Exporter) exportCodeSecurity(log *Log) error {
-	path := filepath.Join(e.analysisDir, "code-security.json")
-	data, err := os.ReadFile(path)
-	if err != nil {
+	data, scanner, ok := e.readAnalysis("code-security")
+	if !ok {
 		return nil // File not found is OK
 	}
 
 	var result struct {
 		Findings struct {
-			Vulns []struct {
-				RuleID   string `json:"rule_id"`
-				File     string `json:"file"`
-				Line     int    `json:"line"`
-				Severity string `json:"severity"`
-				Message  string `json:"message"`
-				Category string `json:"category"`
-			} `json:"vulns"`
-			Secrets []struct {
-				Type     string `json:"type"`
-				File     string `json:"file"`
-				Line     int    `json:"line"`
-				Severity string `json:"severity"`
-			} `json:"secrets"`
+			Vulns   []json.RawMessage `json:"vulns"`
+			Secrets []json.RawMessage `json:"secrets"`
 		} `json:"findings"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
@@ -309,16 +353,31 @@ func (e *Exporter) exportCodeSecurity(log *Log) error {
 
 	// Create run for code vulnerabilities
 	if len(result.Findings.Vulns) > 0 {
-		run := NewRun("zero-code-security", "1.0.0", "https://github.com/crashappsec/zero")
+		run := NewRun("zero-code-security", toolVersion, toolInfoURI)
 		ruleMap := make(map[string]int)
 
-		for _, v := range result.Findings.Vulns {
+		for _, raw := range result.Findings.Vulns {
+			var v struct {
+				RuleID      string `json:"rule_id"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				File        string `json:"file"`
+				Line        int    `json:"line"`
+				Severity    string `json:"severity"`
+				Message     string `json:"message"`
+				Category    string `json:"category"`
+			}
+			if err := json.Unmarshal(raw, &v); err != nil {
+				continue
+			}
+
+			msg := firstNonEmpty(v.Message, v.Description, v.Title, v.RuleID)
 			ruleIndex, ok := ruleMap[v.RuleID]
 			if !ok {
 				ruleIndex = run.AddRule(
 					v.RuleID,
-					v.RuleID,
-					v.Message,
+					firstNonEmpty(v.Title, v.RuleID),
+					msg,
 					"",
 					SeverityToLevel(v.Severity),
 				)
@@ -329,69 +388,158 @@ func (e *Exporter) exportCodeSecurity(log *Log) error {
 				v.RuleID,
 				ruleIndex,
 				SeverityToLevel(v.Severity),
-				v.Message,
+				msg,
 				v.File,
 				v.Line,
 			)
+			e.setFingerprints(run, scanner, "vulns", raw)
 		}
 
 		log.Runs = append(log.Runs, *run)
 	}
 
-	// Create run for secrets
-	if len(result.Findings.Secrets) > 0 {
-		run := NewRun("zero-secrets", "1.0.0", "https://github.com/crashappsec/zero")
-		ruleMap := make(map[string]int)
+	// Create run for secrets found in the working tree
+	// (secrets found in git history are exported by exportGitHistorySecrets)
+	run := NewRun("zero-secrets", toolVersion, toolInfoURI)
+	ruleMap := make(map[string]int)
 
-		for _, s := range result.Findings.Secrets {
-			ruleID := "secret/" + s.Type
-			ruleIndex, ok := ruleMap[ruleID]
-			if !ok {
-				ruleIndex = run.AddRule(
-					ruleID,
-					s.Type,
-					fmt.Sprintf("Detected %s secret", s.Type),
-					"",
-					"error",
-				)
-				ruleMap[ruleID] = ruleIndex
-			}
+	for _, raw := range result.Findings.Secrets {
+		var s struct {
+			Type            string `json:"type"`
+			File            string `json:"file"`
+			Line            int    `json:"line"`
+			Severity        string `json:"severity"`
+			DetectionSource string `json:"detection_source"`
+		}
+		if err := json.Unmarshal(raw, &s); err != nil {
+			continue
+		}
+		if s.DetectionSource == "git_history" {
+			continue
+		}
 
-			run.AddResult(
+		ruleID := "secret/" + s.Type
+		ruleIndex, ok := ruleMap[ruleID]
+		if !ok {
+			ruleIndex = run.AddRule(
 				ruleID,
-				ruleIndex,
-				"error",
+				s.Type,
 				fmt.Sprintf("Detected %s secret", s.Type),
-				s.File,
-				s.Line,
+				"",
+				"error",
 			)
+			ruleMap[ruleID] = ruleIndex
 		}
 
+		run.AddResult(
+			ruleID,
+			ruleIndex,
+			"error",
+			fmt.Sprintf("Detected %s secret", s.Type),
+			s.File,
+			s.Line,
+		)
+		e.setFingerprints(run, scanner, "secrets", raw)
+	}
+
+	if len(run.Results) > 0 {
 		log.Runs = append(log.Runs, *run)
 	}
 
 	return nil
 }
 
+// exportGitHistorySecrets exports secrets that were found in git history
+func (e *Exporter) exportGitHistorySecrets(log *Log) error {
+	data, scanner, ok := e.readAnalysis("code-security")
+	if !ok {
+		return nil
+	}
+
+	var result struct {
+		Findings struct {
+			Secrets []json.RawMessage `json:"secrets"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	run := NewRun("zero-git-history", toolVersion, toolInfoURI)
+	ruleMap := make(map[string]int)
+
+	for _, raw := range result.Findings.Secrets {
+		var s struct {
+			Type            string `json:"type"`
+			File            string `json:"file"`
+			Line            int    `json:"line"`
+			Severity        string `json:"severity"`
+			DetectionSource string `json:"detection_source"`
+			CommitInfo      *struct {
+				Hash      string `json:"hash"`
+				ShortHash string `json:"short_hash"`
+				Author    string `json:"author"`
+				Date      string `json:"date"`
+				IsRemoved bool   `json:"is_removed"`
+			} `json:"commit_info"`
+		}
+		if err := json.Unmarshal(raw, &s); err != nil {
+			continue
+		}
+		if s.DetectionSource != "git_history" {
+			continue
+		}
+
+		ruleID := "secret/history/" + s.Type
+		ruleIndex, ok := ruleMap[ruleID]
+		if !ok {
+			ruleIndex = run.AddRule(
+				ruleID,
+				s.Type+" in git history",
+				fmt.Sprintf("Detected %s secret committed to git history", s.Type),
+				"",
+				"error",
+			)
+			ruleMap[ruleID] = ruleIndex
+		}
+
+		msg := fmt.Sprintf("Detected %s secret in git history", s.Type)
+		if s.CommitInfo != nil {
+			msg = fmt.Sprintf("Detected %s secret in commit %s", s.Type, s.CommitInfo.ShortHash)
+			if s.CommitInfo.IsRemoved {
+				msg += " (removed from current tree, still present in history)"
+			}
+		}
+
+		run.AddResult(ruleID, ruleIndex, "error", msg, s.File, s.Line)
+		e.setFingerprints(run, scanner, "secrets", raw)
+
+		if s.CommitInfo != nil {
+			run.Results[len(run.Results)-1].Properties = map[string]any{
+				"commit":      s.CommitInfo.Hash,
+				"author":      s.CommitInfo.Author,
+				"commit_date": s.CommitInfo.Date,
+				"is_removed":  s.CommitInfo.IsRemoved,
+			}
+		}
+	}
+
+	if len(run.Results) > 0 {
+		log.Runs = append(log.Runs, *run)
+	}
+	return nil
+}
+
 // exportPackageVulns exports package vulnerability results
 func (e *Exporter) exportPackageVulns(log *Log) error {
-	path := filepath.Join(e.analysisDir, "package-analysis.json")
-	data, err := os.ReadFile(path)
-	if err != nil {
+	data, scanner, ok := e.readAnalysis("code-packages", "package-analysis")
+	if !ok {
 		return nil
 	}
 
 	var result struct {
 		Findings struct {
-			Vulns []struct {
-				ID        string   `json:"id"`
-				Aliases   []string `json:"aliases"`
-				Package   string   `json:"package"`
-				Version   string   `json:"version"`
-				Severity  string   `json:"severity"`
-				Title     string   `json:"title"`
-				Ecosystem string   `json:"ecosystem"`
-			} `json:"vulns"`
+			Vulns []json.RawMessage `json:"vulns"`
 		} `json:"findings"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
@@ -402,10 +550,29 @@ func (e *Exporter) exportPackageVulns(log *Log) error {
 		return nil
 	}
 
-	run := NewRun("zero-package-vulns", "1.0.0", "https://github.com/crashappsec/zero")
+	run := NewRun("zero-package-vulns", toolVersion, toolInfoURI)
 	ruleMap := make(map[string]int)
 
-	for _, v := range result.Findings.Vulns {
+	for _, raw := range result.Findings.Vulns {
+		var v struct {
+			ID        string   `json:"id"`
+			Aliases   []string `json:"aliases"`
+			Package   string   `json:"package"`
+			Version   string   `json:"version"`
+			Severity  string   `json:"severity"`
+			Title     string   `json:"title"`
+			Ecosystem string   `json:"ecosystem"`
+
+			CVSSScore     float64 `json:"cvss_score"`
+			EPSS          float64 `json:"epss"`
+			InKEV         bool    `json:"in_kev"`
+			PriorityScore float64 `json:"priority_score"`
+			PriorityRank  int     `json:"priority_rank"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			continue
+		}
+
 		ruleIndex, ok := ruleMap[v.ID]
 		if !ok {
 			ruleIndex = run.AddRule(
@@ -416,6 +583,12 @@ func (e *Exporter) exportPackageVulns(log *Log) error {
 				SeverityToLevel(v.Severity),
 			)
 			ruleMap[v.ID] = ruleIndex
+			// GitHub code scanning derives the displayed severity from this
+			if v.CVSSScore > 0 {
+				run.Tool.Driver.Rules[ruleIndex].Properties = map[string]string{
+					"security-severity": strconv.FormatFloat(v.CVSSScore, 'f', 1, 64),
+				}
+			}
 		}
 
 		// Package vulnerabilities don't have file locations, use logical location
@@ -437,105 +610,122 @@ func (e *Exporter) exportPackageVulns(log *Log) error {
 					},
 				},
 			},
+			Rank: v.PriorityScore,
 			Properties: map[string]any{
 				"package":   v.Package,
 				"version":   v.Version,
 				"ecosystem": v.Ecosystem,
 			},
 		}
+		if v.PriorityRank > 0 {
+			result.Properties["priority_score"] = v.PriorityScore
+			result.Properties["priority_rank"] = v.PriorityRank
+			result.Properties["epss"] = v.EPSS
+			result.Properties["in_kev"] = v.InKEV
+		}
 		run.Results = append(run.Results, result)
+		e.setFingerprints(run, scanner, "vulns", raw)
 	}
 
 	log.Runs = append(log.Runs, *run)
 	return nil
 }
 
-// exportCrypto exports crypto scanner results
+// exportCrypto exports crypto results from code-security (or the legacy crypto scanner)
 func (e *Exporter) exportCrypto(log *Log) error {
-	path := filepath.Join(e.analysisDir, "crypto.json")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
+	run := NewRun("zero-crypto", toolVersion, toolInfoURI)
+	ruleMap := make(map[string]int)
 
-	var result struct {
-		Findings struct {
-			Ciphers []struct {
+	for _, name := range []string{"code-security", "crypto"} {
+		data, scanner, ok := e.readAnalysis(name)
+		if !ok {
+			continue
+		}
+
+		var result struct {
+			Findings struct {
+				Ciphers []json.RawMessage `json:"ciphers"`
+				Keys    []json.RawMessage `json:"keys"`
+			} `json:"findings"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			continue
+		}
+
+		// Weak ciphers
+		for _, raw := range result.Findings.Ciphers {
+			var c struct {
 				Algorithm  string `json:"algorithm"`
 				File       string `json:"file"`
 				Line       int    `json:"line"`
 				Severity   string `json:"severity"`
 				Suggestion string `json:"suggestion"`
-			} `json:"ciphers"`
-			Keys []struct {
+			}
+			if err := json.Unmarshal(raw, &c); err != nil {
+				continue
+			}
+
+			ruleID := "crypto/weak-cipher/" + c.Algorithm
+			ruleIndex, ok := ruleMap[ruleID]
+			if !ok {
+				ruleIndex = run.AddRule(
+					ruleID,
+					"Weak Cipher: "+c.Algorithm,
+					fmt.Sprintf("Use of weak cipher algorithm %s", c.Algorithm),
+					"",
+					SeverityToLevel(c.Severity),
+				)
+				ruleMap[ruleID] = ruleIndex
+			}
+
+			msg := fmt.Sprintf("Weak cipher %s detected", c.Algorithm)
+			if c.Suggestion != "" {
+				msg += ". " + c.Suggestion
+			}
+			run.AddResult(ruleID, ruleIndex, SeverityToLevel(c.Severity), msg, c.File, c.Line)
+			e.setFingerprints(run, scanner, "ciphers", raw)
+		}
+
+		// Hardcoded keys
+		for _, raw := range result.Findings.Keys {
+			var k struct {
 				Type     string `json:"type"`
 				File     string `json:"file"`
 				Line     int    `json:"line"`
 				Severity string `json:"severity"`
-			} `json:"keys"`
-		} `json:"findings"`
-	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return err
-	}
-
-	hasFindings := len(result.Findings.Ciphers) > 0 || len(result.Findings.Keys) > 0
-	if !hasFindings {
-		return nil
-	}
-
-	run := NewRun("zero-crypto", "1.0.0", "https://github.com/crashappsec/zero")
-	ruleMap := make(map[string]int)
+			}
+			if err := json.Unmarshal(raw, &k); err != nil {
+				continue
+			}
 
-	// Weak ciphers
-	for _, c := range result.Findings.Ciphers {
-		ruleID := "crypto/weak-cipher/" + c.Algorithm
-		ruleIndex, ok := ruleMap[ruleID]
-		if !ok {
-			ruleIndex = run.AddRule(
-				ruleID,
-				"Weak Cipher: "+c.Algorithm,
-				fmt.Sprintf("Use of weak cipher algorithm %s", c.Algorithm),
-				"",
-				SeverityToLevel(c.Severity),
-			)
-			ruleMap[ruleID] = ruleIndex
-		}
+			ruleID := "crypto/hardcoded-key/" + k.Type
+			ruleIndex, ok := ruleMap[ruleID]
+			if !ok {
+				ruleIndex = run.AddRule(
+					ruleID,
+					"Hardcoded Key: "+k.Type,
+					fmt.Sprintf("Hardcoded %s key detected", k.Type),
+					"",
+					"error",
+				)
+				ruleMap[ruleID] = ruleIndex
+			}
 
-		msg := fmt.Sprintf("Weak cipher %s detected", c.Algorithm)
-		if c.Suggestion != "" {
-			msg += ". " + c.Suggestion
+			run.AddResult(ruleID, ruleIndex, "error", fmt.Sprintf("Hardcoded %s key", k.Type), k.File, k.Line)
+			e.setFingerprints(run, scanner, "keys", raw)
 		}
-		run.AddResult(ruleID, ruleIndex, SeverityToLevel(c.Severity), msg, c.File, c.Line)
 	}
 
-	// Hardcoded keys
-	for _, k := range result.Findings.Keys {
-		ruleID := "crypto/hardcoded-key/" + k.Type
-		ruleIndex, ok := ruleMap[ruleID]
-		if !ok {
-			ruleIndex = run.AddRule(
-				ruleID,
-				"Hardcoded Key: "+k.Type,
-				fmt.Sprintf("Hardcoded %s key detected", k.Type),
-				"",
-				"error",
-			)
-			ruleMap[ruleID] = ruleIndex
-		}
-
-		run.AddResult(ruleID, ruleIndex, "error", fmt.Sprintf("Hardcoded %s key", k.Type), k.File, k.Line)
+	if len(run.Results) > 0 {
+		log.Runs = append(log.Runs, *run)
 	}
-
-	log.Runs = append(log.Runs, *run)
 	return nil
 }
 
 // exportCodeQuality exports code quality results
 func (e *Exporter) exportCodeQuality(log *Log) error {
-	path := filepath.Join(e.analysisDir, "code-quality.json")
-	data, err := os.ReadFile(path)
-	if err != nil {
+	data, _, ok := e.readAnalysis("code-quality")
+	if !ok {
 		return nil
 	}
 
@@ -547,28 +737,46 @@ func (e *Exporter) exportCodeQuality(log *Log) error {
 					File     string `json:"file"`
 					Line     int    `json:"line"`
 					Message  string `json:"message"`
+					Text     string `json:"text"`
 					Priority string `json:"priority"`
 				} `json:"markers"`
 			} `json:"tech_debt"`
-			Complexity []struct {
-				File       string `json:"file"`
-				Function   string `json:"function"`
-				Line       int    `json:"line"`
-				Complexity int    `json:"complexity"`
-				Type       string `json:"type"`
-			} `json:"complexity"`
+			Complexity json.RawMessage `json:"complexity"`
 		} `json:"findings"`
 	}
 	if err := json.Unmarshal(data, &result); err != nil {
 		return err
 	}
 
-	hasFindings := len(result.Findings.TechDebt.Markers) > 0 || len(result.Findings.Complexity) > 0
+	type complexityFinding struct {
+		File        string `json:"file"`
+		Function    string `json:"function"`
+		Line        int    `json:"line"`
+		Complexity  int    `json:"complexity"`
+		Type        string `json:"type"`
+		Severity    string `json:"severity"`
+		Description string `json:"description"`
+	}
+
+	// Complexity is a list of issues in older output and {"issues": [...]} in newer output
+	var complexity []complexityFinding
+	if len(result.Findings.Complexity) > 0 {
+		if err := json.Unmarshal(result.Findings.Complexity, &complexity); err != nil {
+			var wrapped struct {
+				Issues []complexityFinding `json:"issues"`
+			}
+			if err := json.Unmarshal(result.Findings.Complexity, &wrapped); err == nil {
+				complexity = wrapped.Issues
+			}
+		}
+	}
+
+	hasFindings := len(result.Findings.TechDebt.Markers) > 0 || len(complexity) > 0
 	if !hasFindings {
 		return nil
 	}
 
-	run := NewRun("zero-code-quality", "1.0.0", "https://github.com/crashappsec/zero")
+	run := NewRun("zero-code-quality", toolVersion, toolInfoURI)
 	ruleMap := make(map[string]int)
 
 	// Tech debt markers
@@ -586,11 +794,11 @@ func (e *Exporter) exportCodeQuality(log *Log) error {
 			ruleMap[ruleID] = ruleIndex
 		}
 
-		run.AddResult(ruleID, ruleIndex, "note", m.Message, m.File, m.Line)
+		run.AddResult(ruleID, ruleIndex, "note", firstNonEmpty(m.Message, m.Text, m.Type), m.File, m.Line)
 	}
 
 	// Complexity
-	for _, c := range result.Findings.Complexity {
+	for _, c := range complexity {
 		ruleID := "quality/complexity/" + c.Type
 		ruleIndex, ok := ruleMap[ruleID]
 		if !ok {
@@ -604,10 +812,260 @@ func (e *Exporter) exportCodeQuality(log *Log) error {
 			ruleMap[ruleID] = ruleIndex
 		}
 
-		msg := fmt.Sprintf("Function %s has complexity %d", c.Function, c.Complexity)
+		msg := c.Description
+		if c.Function != "" {
+			msg = fmt.Sprintf("Function %s has complexity %d", c.Function, c.Complexity)
+		}
 		run.AddResult(ruleID, ruleIndex, "warning", msg, c.File, c.Line)
 	}
 
 	log.Runs = append(log.Runs, *run)
 	return nil
 }
+
+// exportDevOps exports IaC, container and GitHub Actions findings from the devops scanner
+func (e *Exporter) exportDevOps(log *Log) error {
+	data, scanner, ok := e.readAnalysis("devops")
+	if !ok {
+		return nil
+	}
+
+	var result struct {
+		Findings struct {
+			IaC           []json.RawMessage `json:"iac"`
+			Containers    []json.RawMessage `json:"containers"`
+			GitHubActions []json.RawMessage `json:"github_actions"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	// Infrastructure as code
+	if len(result.Findings.IaC) > 0 {
+		run := NewRun("zero-iac", toolVersion, toolInfoURI)
+		ruleMap := make(map[string]int)
+
+		for _, raw := range result.Findings.IaC {
+			var f struct {
+				RuleID      string `json:"rule_id"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				Severity    string `json:"severity"`
+				File        string `json:"file"`
+				Line        int    `json:"line"`
+				Resource    string `json:"resource"`
+				Type        string `json:"type"`
+				Resolution  string `json:"resolution"`
+			}
+			if err := json.Unmarshal(raw, &f); err != nil {
+				continue
+			}
+
+			ruleID := "iac/" + f.RuleID
+			ruleIndex, ok := ruleMap[ruleID]
+			if !ok {
+				ruleIndex = run.AddRule(
+					ruleID,
+					firstNonEmpty(f.Title, f.RuleID),
+					firstNonEmpty(f.Description, f.Title, f.RuleID),
+					"",
+					SeverityToLevel(f.Severity),
+				)
+				ruleMap[ruleID] = ruleIndex
+			}
+
+			msg := firstNonEmpty(f.Title, f.Description, f.RuleID)
+			if f.Resource != "" {
+				msg = fmt.Sprintf("%s (%s)", msg, f.Resource)
+			}
+			run.AddResult(ruleID, ruleIndex, SeverityToLevel(f.Severity), msg, f.File, f.Line)
+			e.setFingerprints(run, scanner, "iac", raw)
+		}
+
+		log.Runs = append(log.Runs, *run)
+	}
+
+	// Container images and Dockerfiles
+	if len(result.Findings.Containers) > 0 {
+		run := NewRun("zero-containers", toolVersion, toolInfoURI)
+		ruleMap := make(map[string]int)
+
+		for _, raw := range result.Findings.Containers {
+			var f struct {
+				VulnID       string `json:"vuln_id"`
+				Title        string `json:"title"`
+				Severity     string `json:"severity"`
+				Image        string `json:"image"`
+				Dockerfile   string `json:"dockerfile"`
+				Package      string `json:"package"`
+				Version      string `json:"version"`
+				FixedVersion string `json:"fixed_version"`
+				Line         int    `json:"line"`
+			}
+			if err := json.Unmarshal(raw, &f); err != nil {
+				continue
+			}
+
+			ruleID := "container/" + f.VulnID
+			ruleIndex, ok := ruleMap[ruleID]
+			if !ok {
+				helpURI := ""
+				if strings.HasPrefix(f.VulnID, "CVE-") || strings.HasPrefix(f.VulnID, "GHSA-") {
+					helpURI = fmt.Sprintf("https://osv.dev/vulnerability/%s", f.VulnID)
+				}
+				ruleIndex = run.AddRule(
+					ruleID,
+					f.VulnID,
+					firstNonEmpty(f.Title, f.VulnID),
+					helpURI,
+					SeverityToLevel(f.Severity),
+				)
+				ruleMap[ruleID] = ruleIndex
+			}
+
+			msg := firstNonEmpty(f.Title, f.VulnID)
+			if f.Package != "" {
+				msg = fmt.Sprintf("%s: %s@%s in %s", f.VulnID, f.Package, f.Version, f.Image)
+				if f.FixedVersion != "" {
+					msg += fmt.Sprintf(" (fixed in %s)", f.FixedVersion)
+				}
+			}
+			run.AddResult(ruleID, ruleIndex, SeverityToLevel(f.Severity), msg, f.Dockerfile, f.Line)
+			e.setFingerprints(run, scanner, "containers", raw)
+
+			if f.Image != "" {
+				res := &run.Results[len(run.Results)-1]
+				res.Locations = append(res.Locations, Location{
+					LogicalLocations: []LogicalLocation{
+						{
+							Name:               f.Image,
+							FullyQualifiedName: f.Image,
+							Kind:               "image",
+						},
+					},
+				})
+			}
+		}
+
+		log.Runs = append(log.Runs, *run)
+	}
+
+	// GitHub Actions workflows
+	if len(result.Findings.GitHubActions) > 0 {
+		run := NewRun("zero-github-actions", toolVersion, toolInfoURI)
+		ruleMap := make(map[string]int)
+
+		for _, raw := range result.Findings.GitHubActions {
+			var f struct {
+				RuleID      string `json:"rule_id"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+				Severity    string `json:"severity"`
+				File        string `json:"file"`
+				Line        int    `json:"line"`
+				Suggestion  string `json:"suggestion"`
+			}
+			if err := json.Unmarshal(raw, &f); err != nil {
+				continue
+			}
+
+			ruleID := "github-actions/" + f.RuleID
+			ruleIndex, ok := ruleMap[ruleID]
+			if !ok {
+				ruleIndex = run.AddRule(
+					ruleID,
+					firstNonEmpty(f.Title, f.RuleID),
+					firstNonEmpty(f.Description, f.Title, f.RuleID),
+					"",
+					SeverityToLevel(f.Severity),
+				)
+				ruleMap[ruleID] = ruleIndex
+			}
+
+			msg := firstNonEmpty(f.Title, f.Description, f.RuleID)
+			if f.Suggestion != "" {
+				msg += ". " + f.Suggestion
+			}
+			run.AddResult(ruleID, ruleIndex, SeverityToLevel(f.Severity), msg, f.File, f.Line)
+			e.setFingerprints(run, scanner, "github_actions", raw)
+		}
+
+		log.Runs = append(log.Runs, *run)
+	}
+
+	return nil
+}
+
+// exportAISecurity exports AI/ML security findings from technology-identification
+func (e *Exporter) exportAISecurity(log *Log) error {
+	data, scanner, ok := e.readAnalysis("technology-identification")
+	if !ok {
+		return nil
+	}
+
+	var result struct {
+		Findings struct {
+			Security []json.RawMessage `json:"security"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	if len(result.Findings.Security) == 0 {
+		return nil
+	}
+
+	run := NewRun("zero-ai-security", toolVersion, toolInfoURI)
+	ruleMap := make(map[string]int)
+
+	for _, raw := range result.Findings.Security {
+		var f struct {
+			ID          string `json:"id"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Severity    string `json:"severity"`
+			Category    string `json:"category"`
+			File        string `json:"file"`
+			Line        int    `json:"line"`
+			ModelName   string `json:"model_name"`
+		}
+		if err := json.Unmarshal(raw, &f); err != nil {
+			continue
+		}
+
+		ruleID := "ai/" + firstNonEmpty(f.Category, f.ID)
+		ruleIndex, ok := ruleMap[ruleID]
+		if !ok {
+			ruleIndex = run.AddRule(
+				ruleID,
+				firstNonEmpty(f.Title, f.Category),
+				firstNonEmpty(f.Description, f.Title),
+				"",
+				SeverityToLevel(f.Severity),
+			)
+			ruleMap[ruleID] = ruleIndex
+		}
+
+		msg := firstNonEmpty(f.Title, f.Description, f.ID)
+		if f.ModelName != "" {
+			msg = fmt.Sprintf("%s (model: %s)", msg, f.ModelName)
+		}
+		run.AddResult(ruleID, ruleIndex, SeverityToLevel(f.Severity), msg, f.File, f.Line)
+		e.setFingerprints(run, scanner, "security", raw)
+	}
+
+	log.Runs = append(log.Runs, *run)
+	return nil
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package sarif
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -314,7 +315,12 @@ func TestExporterPackageVulns(t *testing.T) {
 					"version": "4.17.20",
 					"severity": "high",
 					"title": "Prototype pollution vulnerability",
-					"ecosystem": "npm"
+					"ecosystem": "npm",
+					"cvss_score": 7.2,
+					"epss": 0.0123,
+					"in_kev": false,
+					"priority_score": 42.5,
+					"priority_rank": 1
 				}
 			]
 		}
@@ -364,6 +370,17 @@ func TestExporterPackageVulns(t *testing.T) {
 	if logLoc.Kind != "package" {
 		t.Errorf("Expected kind package, got %q", logLoc.Kind)
 	}
+
+	// Check prioritization
+	if result.Rank != 42.5 {
+		t.Errorf("Expected rank 42.5, got %v", result.Rank)
+	}
+	if result.Properties["priority_rank"] != 1 {
+		t.Errorf("Expected priority_rank 1, got %v", result.Properties["priority_rank"])
+	}
+	if got := run.Tool.Driver.Rules[0].Properties["security-severity"]; got != "7.2" {
+		t.Errorf("Expected security-severity 7.2, got %q", got)
+	}
 }
 
 func TestExporterCrypto(t *testing.T) {
@@ -489,6 +506,211 @@ func TestExporterCodeQuality(t *testing.T) {
 	}
 }
 
+func TestExporterDevOps(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	devopsData := `{
+		"findings": {
+			"iac": [
+				{
+					"rule_id": "CKV_AWS_20",
+					"title": "S3 bucket allows public read",
+					"severity": "high",
+					"file": "main.tf",
+					"line": 12,
+					"resource": "aws_s3_bucket.data",
+					"type": "terraform"
+				}
+			],
+			"containers": [
+				{
+					"vuln_id": "CVE-2023-0001",
+					"title": "openssl overflow",
+					"severity": "critical",
+					"image": "alpine:3.14",
+					"dockerfile": "Dockerfile",
+					"package": "openssl",
+					"version": "1.1.1k"
+				}
+			],
+			"github_actions": [
+				{
+					"rule_id": "unpinned-action",
+					"title": "Action not pinned to SHA",
+					"severity": "medium",
+					"file": ".github/workflows/ci.yml",
+					"line": 20,
+					"category": "supply-chain"
+				}
+			]
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "devops.json"), []byte(devopsData), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	log, err := NewExporter(tmpDir, tmpDir).Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	runs := make(map[string]Run)
+	for _, run := range log.Runs {
+		runs[run.Tool.Driver.Name] = run
+	}
+
+	for _, name := range []string{"zero-iac", "zero-containers", "zero-github-actions"} {
+		run, ok := runs[name]
+		if !ok {
+			t.Errorf("Expected %s run not found", name)
+			continue
+		}
+		if len(run.Results) != 1 {
+			t.Errorf("%s: expected 1 result, got %d", name, len(run.Results))
+			continue
+		}
+		if run.Results[0].PartialFingerprints[FingerprintPrimaryKey] == "" {
+			t.Errorf("%s: result missing %s partial fingerprint", name, FingerprintPrimaryKey)
+		}
+	}
+
+	containers := runs["zero-containers"]
+	if len(containers.Results) == 1 {
+		if containers.Results[0].Level != "error" {
+			t.Errorf("Critical container vuln should map to error level, got %q", containers.Results[0].Level)
+		}
+		if len(containers.Results[0].Locations) != 2 {
+			t.Errorf("Container result should have file and image locations, got %d", len(containers.Results[0].Locations))
+		}
+	}
+}
+
+func TestExporterAISecurity(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	techData := `{
+		"findings": {
+			"security": [
+				{
+					"id": "pickle-rce",
+					"title": "Unsafe pickle model loading",
+					"severity": "critical",
+					"category": "pickle_rce",
+					"file": "model/load.py",
+					"line": 7,
+					"model_name": "bert-base"
+				}
+			]
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "technology-identification.json"), []byte(techData), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	log, err := NewExporter(tmpDir, tmpDir).Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if len(log.Runs) != 1 {
+		t.Fatalf("Expected 1 run for AI security, got %d", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "zero-ai-security" {
+		t.Errorf("Expected tool name zero-ai-security, got %q", run.Tool.Driver.Name)
+	}
+	if len(run.Results) != 1 || run.Results[0].RuleID != "ai/pickle_rce" {
+		t.Fatalf("Expected 1 result with rule ai/pickle_rce, got %+v", run.Results)
+	}
+}
+
+func TestExporterGitHistorySecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	codeSecurityData := `{
+		"findings": {
+			"secrets": [
+				{
+					"type": "github_token",
+					"file": "app.go",
+					"line": 3,
+					"severity": "high",
+					"detection_source": "semgrep"
+				},
+				{
+					"type": "aws_access_key",
+					"file": "old/config.env",
+					"line": 1,
+					"severity": "critical",
+					"detection_source": "git_history",
+					"commit_info": {"hash": "abc123def456", "short_hash": "abc123d", "author": "dev", "is_removed": true}
+				}
+			]
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "code-security.json"), []byte(codeSecurityData), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	log, err := NewExporter(tmpDir, tmpDir).Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var secrets, history *Run
+	for i := range log.Runs {
+		switch log.Runs[i].Tool.Driver.Name {
+		case "zero-secrets":
+			secrets = &log.Runs[i]
+		case "zero-git-history":
+			history = &log.Runs[i]
+		}
+	}
+
+	if secrets == nil || len(secrets.Results) != 1 {
+		t.Fatalf("Expected zero-secrets run with 1 working tree secret")
+	}
+	if history == nil || len(history.Results) != 1 {
+		t.Fatalf("Expected zero-git-history run with 1 historical secret")
+	}
+	if history.Results[0].Properties["commit"] != "abc123def456" {
+		t.Errorf("Expected commit property, got %v", history.Results[0].Properties["commit"])
+	}
+}
+
+func TestExporterPartialFingerprintsStable(t *testing.T) {
+	write := func(dir string, line int) {
+		data := fmt.Sprintf(`{
+			"findings": {
+				"vulns": [
+					{"rule_id": "sql-injection", "title": "SQL injection", "file": "src/db.go", "line": %d, "severity": "high"}
+				]
+			}
+		}`, line)
+		if err := os.WriteFile(filepath.Join(dir, "code-security.json"), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	before, after := t.TempDir(), t.TempDir()
+	write(before, 42)
+	write(after, 57)
+
+	logBefore, _ := NewExporter(before, before).Export()
+	logAfter, _ := NewExporter(after, after).Export()
+
+	fpBefore := logBefore.Runs[0].Results[0].PartialFingerprints
+	fpAfter := logAfter.Runs[0].Results[0].PartialFingerprints
+
+	if fpBefore[FingerprintPrimaryKey] == "" {
+		t.Fatal("Expected primary key fingerprint")
+	}
+	if fpBefore[FingerprintPrimaryKey] != fpAfter[FingerprintPrimaryKey] {
+		t.Errorf("Primary key fingerprint should be stable when a finding moves lines: %q != %q",
+			fpBefore[FingerprintPrimaryKey], fpAfter[FingerprintPrimaryKey])
+	}
+}
+
 func TestLogJSON(t *testing.T) {
 	log := NewLog()
 	run := NewRun("test-tool", "1.0.0", "https://example.com")
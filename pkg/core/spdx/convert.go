@@ -0,0 +1,435 @@
+package spdx
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/licenses"
+)
+
+// namespaceBase prefixes the namespaces of documents Zero creates
+const namespaceBase = "https://spdx.crashoverride.com/"
+
+// checksumAlgorithms maps CycloneDX hash algorithms to SPDX checksum
+// algorithms
+var checksumAlgorithms = map[string]string{
+	"MD5":         "MD5",
+	"SHA-1":       "SHA1",
+	"SHA-256":     "SHA256",
+	"SHA-384":     "SHA384",
+	"SHA-512":     "SHA512",
+	"SHA3-256":    "SHA3-256",
+	"SHA3-384":    "SHA3-384",
+	"SHA3-512":    "SHA3-512",
+	"BLAKE2b-256": "BLAKE2b-256",
+	"BLAKE2b-384": "BLAKE2b-384",
+	"BLAKE2b-512": "BLAKE2b-512",
+	"BLAKE3":      "BLAKE3",
+}
+
+// purposes maps CycloneDX component types to SPDX package purposes
+var purposes = map[string]string{
+	cyclonedx.ComponentTypeApplication:  "APPLICATION",
+	cyclonedx.ComponentTypeFramework:    "FRAMEWORK",
+	cyclonedx.ComponentTypeLibrary:      "LIBRARY",
+	cyclonedx.ComponentTypeContainer:    "CONTAINER",
+	cyclonedx.ComponentTypeOS:           "OPERATING-SYSTEM",
+	cyclonedx.ComponentTypeDevice:       "DEVICE",
+	cyclonedx.ComponentTypeFirmware:     "FIRMWARE",
+	cyclonedx.ComponentTypeFile:         "FILE",
+	cyclonedx.ComponentTypeMLModel:      "OTHER",
+	cyclonedx.ComponentTypeData:         "OTHER",
+	cyclonedx.ComponentTypePlatform:     "OTHER",
+	cyclonedx.ComponentTypeDeviceDriver: "OTHER",
+}
+
+var idInvalid = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// FromCycloneDX converts a CycloneDX SBOM to an SPDX 2.3 document. The
+// metadata component becomes the described package; dependencies become
+// DEPENDS_ON relationships, or DEV_DEPENDENCY_OF for optional and excluded
+// components. License names that are not SPDX IDs are kept as extracted
+// licenses.
+func FromCycloneDX(bom *cyclonedx.BOM) *Document {
+	doc := &Document{
+		SPDXVersion:  Version23,
+		DataLicense:  "CC0-1.0",
+		SPDXID:       DocumentID,
+		Name:         "sbom",
+		CreationInfo: CreationInfo{Created: time.Now().UTC().Format(time.RFC3339)},
+		Packages:     []Package{},
+	}
+
+	var root *cyclonedx.Component
+	if bom.Metadata != nil {
+		if bom.Metadata.Timestamp != "" {
+			doc.CreationInfo.Created = bom.Metadata.Timestamp
+		}
+		if bom.Metadata.Tools != nil {
+			for _, t := range bom.Metadata.Tools.Components {
+				doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, "Tool: "+toolName(t.Name, t.Version))
+			}
+		}
+		root = bom.Metadata.Component
+	}
+	if len(doc.CreationInfo.Creators) == 0 {
+		doc.CreationInfo.Creators = []string{"Tool: " + toolName("zero", cyclonedx.ZeroVersion)}
+	}
+	doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, "Organization: Crash Override")
+	if root != nil && root.Name != "" {
+		doc.Name = root.Name
+	}
+	doc.DocumentNamespace = namespace(doc.Name, bom)
+
+	ids := make(map[string]string) // bom-ref -> SPDXID
+	used := make(map[string]bool)
+	extracted := make(map[string]string)
+	add := func(c cyclonedx.Component) string {
+		id := packageID(c, used)
+		if c.BOMRef != "" {
+			ids[c.BOMRef] = id
+		}
+		doc.Packages = append(doc.Packages, toPackage(c, id, extracted))
+		return id
+	}
+
+	var rootID string
+	if root != nil {
+		rootID = add(*root)
+	}
+	var walk func(components []cyclonedx.Component)
+	walk = func(components []cyclonedx.Component) {
+		for _, c := range components {
+			add(c)
+			walk(c.Components)
+		}
+	}
+	walk(bom.Components)
+
+	scopes := make(map[string]string)
+	for _, c := range bom.Components {
+		scopes[c.BOMRef] = c.Scope
+	}
+	dependedOn := make(map[string]bool)
+	for _, dep := range bom.Dependencies {
+		from, ok := ids[dep.Ref]
+		if !ok {
+			continue
+		}
+		for _, ref := range dep.DependsOn {
+			to, ok := ids[ref]
+			if !ok {
+				continue
+			}
+			dependedOn[ref] = true
+			if s := scopes[ref]; s == "optional" || s == "excluded" {
+				doc.Relationships = append(doc.Relationships, Relationship{Element: to, Type: RelDevDependencyOf, Related: from})
+			} else {
+				doc.Relationships = append(doc.Relationships, Relationship{Element: from, Type: RelDependsOn, Related: to})
+			}
+		}
+	}
+
+	// The document describes the project, or its top-level packages when
+	// the SBOM has no project component
+	if rootID != "" {
+		doc.DocumentDescribes = []string{rootID}
+	} else {
+		for _, c := range bom.Components {
+			if !dependedOn[c.BOMRef] {
+				doc.DocumentDescribes = append(doc.DocumentDescribes, ids[c.BOMRef])
+			}
+		}
+	}
+	describes := make([]Relationship, 0, len(doc.DocumentDescribes))
+	for _, id := range doc.DocumentDescribes {
+		describes = append(describes, Relationship{Element: DocumentID, Type: RelDescribes, Related: id})
+	}
+	doc.Relationships = append(describes, doc.Relationships...)
+
+	for id, name := range extracted {
+		doc.ExtractedLicenses = append(doc.ExtractedLicenses, ExtractedLicense{LicenseID: id, ExtractedText: name, Name: name})
+	}
+	sortExtracted(doc.ExtractedLicenses)
+	return doc
+}
+
+// toPackage converts a CycloneDX component, recording the names behind
+// LicenseRef- IDs in extracted
+func toPackage(c cyclonedx.Component, id string, extracted map[string]string) Package {
+	pkg := Package{
+		SPDXID:                id,
+		Name:                  c.Name,
+		VersionInfo:           c.Version,
+		DownloadLocation:      NoAssertion,
+		LicenseConcluded:      NoAssertion,
+		LicenseDeclared:       NoAssertion,
+		CopyrightText:         c.Copyright,
+		Description:           c.Description,
+		PrimaryPackagePurpose: purposes[c.Type],
+	}
+	if c.Group != "" && c.Purl == "" {
+		pkg.Name = c.Group + "/" + c.Name
+	}
+	if c.Purl != "" {
+		pkg.Name = cyclonedx.PackageName(c)
+		pkg.ExternalRefs = append(pkg.ExternalRefs, ExternalRef{Category: "PACKAGE-MANAGER", Type: "purl", Locator: c.Purl})
+	}
+	if c.CPE != "" {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, ExternalRef{Category: "SECURITY", Type: "cpe23Type", Locator: c.CPE})
+	}
+	if c.Supplier != nil && c.Supplier.Name != "" {
+		pkg.Supplier = "Organization: " + c.Supplier.Name
+	}
+	for _, ref := range c.ExternalRefs {
+		if ref.Type == "distribution" && ref.URL != "" {
+			pkg.DownloadLocation = ref.URL
+			break
+		}
+	}
+	for _, h := range c.Hashes {
+		if alg, ok := checksumAlgorithms[h.Algorithm]; ok {
+			pkg.Checksums = append(pkg.Checksums, Checksum{Algorithm: alg, Value: h.Content})
+		}
+	}
+
+	entries := make([]licenses.Entry, 0, len(c.Licenses))
+	for _, lic := range c.Licenses {
+		e := licenses.Entry{Expression: lic.Expression}
+		if lic.License != nil {
+			e.ID, e.Name = lic.License.ID, lic.License.Name
+		}
+		entries = append(entries, e)
+		switch name := strings.TrimSpace(e.Name); {
+		case strings.HasPrefix(e.ID, "LicenseRef-"):
+			extracted[e.ID] = e.ID
+		case e.ID != "":
+		case e.Expression != "":
+			if _, err := licenses.Parse(e.Expression); err != nil {
+				extracted[licenses.Ref(e.Expression)] = e.Expression
+			}
+		case name != "":
+			if _, err := licenses.Parse(name); err != nil {
+				extracted[licenses.Ref(name)] = name
+			}
+		}
+	}
+	if expr := licenses.Combine(entries); expr != "" {
+		pkg.LicenseDeclared = expr
+	}
+	if pkg.CopyrightText == "" {
+		pkg.CopyrightText = NoAssertion
+	}
+	return pkg
+}
+
+// ToCycloneDX converts an SPDX document to a CycloneDX SBOM. A single
+// described package becomes the metadata component. DEPENDS_ON and the
+// *_DEPENDENCY_OF relationships become the dependency graph, with CONTAINS
+// standing in for the described package's dependencies when it has none;
+// dev, test and build dependencies get the optional scope.
+func (d *Document) ToCycloneDX() *cyclonedx.BOM {
+	bom := cyclonedx.NewSBOM()
+	if strings.HasPrefix(d.DocumentNamespace, "urn:uuid:") {
+		bom.SerialNumber = d.DocumentNamespace
+	}
+	if d.CreationInfo.Created != "" {
+		bom.Metadata.Timestamp = d.CreationInfo.Created
+	}
+
+	describes := append([]string(nil), d.DocumentDescribes...)
+	edges := make(map[string][]string)
+	contains := make(map[string][]string)
+	optional := make(map[string]bool)
+	for _, r := range d.Relationships {
+		switch r.Type {
+		case RelDescribes:
+			if r.Element == DocumentID || r.Element == d.SPDXID {
+				describes = append(describes, r.Related)
+			}
+		case "DESCRIBED_BY":
+			describes = append(describes, r.Element)
+		case RelDependsOn:
+			edges[r.Element] = append(edges[r.Element], r.Related)
+		case RelContains:
+			contains[r.Element] = append(contains[r.Element], r.Related)
+		default:
+			if !strings.HasSuffix(r.Type, "_DEPENDENCY_OF") && r.Type != RelDependencyOf {
+				continue
+			}
+			edges[r.Related] = append(edges[r.Related], r.Element)
+			switch r.Type {
+			case RelDevDependencyOf, "TEST_DEPENDENCY_OF", "BUILD_DEPENDENCY_OF", RelOptionalDependencyOf:
+				optional[r.Element] = true
+			}
+		}
+	}
+	describes = unique(describes)
+
+	var rootID string
+	if len(describes) == 1 {
+		rootID = describes[0]
+		if len(edges[rootID]) == 0 {
+			edges[rootID] = contains[rootID]
+		}
+	}
+
+	known := make(map[string]bool, len(d.Packages))
+	for _, p := range d.Packages {
+		known[p.SPDXID] = true
+		c := toComponent(p)
+		if optional[p.SPDXID] {
+			c.Scope = "optional"
+		}
+		if p.SPDXID == rootID {
+			if c.Type == cyclonedx.ComponentTypeLibrary {
+				c.Type = cyclonedx.ComponentTypeApplication
+			}
+			bom.Metadata.Component = &c
+			continue
+		}
+		bom.Components = append(bom.Components, c)
+	}
+
+	for _, p := range d.Packages {
+		var dependsOn []string
+		for _, to := range unique(edges[p.SPDXID]) {
+			if known[to] {
+				dependsOn = append(dependsOn, to)
+			}
+		}
+		if len(dependsOn) > 0 || p.SPDXID == rootID {
+			bom.Dependencies = append(bom.Dependencies, cyclonedx.Dependency{Ref: p.SPDXID, DependsOn: dependsOn})
+		}
+	}
+	return bom
+}
+
+// toComponent converts an SPDX package; its SPDXID becomes the bom-ref
+func toComponent(p Package) cyclonedx.Component {
+	c := cyclonedx.Component{
+		Type:        cyclonedx.ComponentTypeLibrary,
+		BOMRef:      p.SPDXID,
+		Name:        p.Name,
+		Version:     p.VersionInfo,
+		Purl:        p.Purl(),
+		Description: p.Description,
+	}
+	for cdxType, purpose := range purposes {
+		if purpose == strings.ToUpper(p.PrimaryPackagePurpose) && purpose != "OTHER" {
+			c.Type = cdxType
+		}
+	}
+	for _, ref := range p.ExternalRefs {
+		if strings.HasPrefix(ref.Type, "cpe") {
+			c.CPE = ref.Locator
+		}
+	}
+	if p.CopyrightText != NoAssertion && p.CopyrightText != None {
+		c.Copyright = p.CopyrightText
+	}
+	if name := supplierName(p.Supplier); name != "" {
+		c.Supplier = &cyclonedx.OrgEntity{Name: name}
+	}
+	if p.DownloadLocation != "" && p.DownloadLocation != NoAssertion && p.DownloadLocation != None {
+		c.ExternalRefs = append(c.ExternalRefs, cyclonedx.ExternalRef{Type: "distribution", URL: p.DownloadLocation})
+	}
+	for _, cs := range p.Checksums {
+		for cdxAlg, alg := range checksumAlgorithms {
+			if strings.EqualFold(alg, cs.Algorithm) {
+				c.Hashes = append(c.Hashes, cyclonedx.Hash{Algorithm: cdxAlg, Content: cs.Value})
+			}
+		}
+	}
+
+	// The declared license is what the package says; the concluded one
+	// fills in when nothing was declared
+	expr := p.LicenseDeclared
+	if !hasValue(expr) {
+		expr = p.LicenseConcluded
+	}
+	if hasValue(expr) {
+		if parsed, err := licenses.Parse(expr); err == nil {
+			if l, ok := parsed.(*licenses.License); ok && l.Exception == "" {
+				c.Licenses = []cyclonedx.LicenseChoice{{License: &cyclonedx.License{ID: l.ID}}}
+			} else {
+				c.Licenses = []cyclonedx.LicenseChoice{{Expression: parsed.String()}}
+			}
+		} else {
+			c.Licenses = []cyclonedx.LicenseChoice{{Expression: expr}}
+		}
+	}
+	return c
+}
+
+// packageID derives a unique SPDXID from a component's bom-ref
+func packageID(c cyclonedx.Component, used map[string]bool) string {
+	base := c.BOMRef
+	if base == "" {
+		base = c.Name + "-" + c.Version
+	}
+	id := "SPDXRef-Package-" + strings.Trim(idInvalid.ReplaceAllString(base, "-"), "-")
+	for n := 2; used[id]; n++ {
+		id = fmt.Sprintf("SPDXRef-Package-%s-%d", strings.Trim(idInvalid.ReplaceAllString(base, "-"), "-"), n)
+	}
+	used[id] = true
+	return id
+}
+
+// namespace returns a unique document namespace, stable for a BOM with a
+// serial number
+func namespace(name string, bom *cyclonedx.BOM) string {
+	seed := bom.SerialNumber
+	if seed == "" {
+		seed = fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+	}
+	sum := sha256.Sum256([]byte(seed))
+	return fmt.Sprintf("%s%s-%x", namespaceBase, strings.Trim(idInvalid.ReplaceAllString(name, "-"), "-"), sum[:8])
+}
+
+func toolName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "-" + version
+}
+
+// supplierName strips the "Organization:" or "Person:" prefix of a
+// supplier
+func supplierName(supplier string) string {
+	if !hasValue(supplier) {
+		return ""
+	}
+	if _, name, ok := strings.Cut(supplier, ":"); ok {
+		return strings.TrimSpace(name)
+	}
+	return supplier
+}
+
+func hasValue(s string) bool {
+	return s != "" && s != NoAssertion && s != None
+}
+
+func unique(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	out := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func sortExtracted(list []ExtractedLicense) {
+	for i := 1; i < len(list); i++ {
+		for j := i; j > 0 && list[j].LicenseID < list[j-1].LicenseID; j-- {
+			list[j], list[j-1] = list[j-1], list[j]
+		}
+	}
+}
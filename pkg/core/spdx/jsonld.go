@@ -0,0 +1,340 @@
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SPDX 3.0 JSON-LD serialization
+const (
+	context30     = "https://spdx.org/rdf/3.0.1/spdx-context.jsonld"
+	specVersion30 = "3.0.1"
+	creationInfo  = "_:creationinfo"
+)
+
+// hashAlgorithms30 maps SPDX 2.3 checksum algorithms to SPDX 3.0 hash
+// algorithms
+var hashAlgorithms30 = map[string]string{
+	"MD5":         "md5",
+	"SHA1":        "sha1",
+	"SHA256":      "sha256",
+	"SHA384":      "sha384",
+	"SHA512":      "sha512",
+	"SHA3-256":    "sha3_256",
+	"SHA3-384":    "sha3_384",
+	"SHA3-512":    "sha3_512",
+	"BLAKE2b-256": "blake2b256",
+	"BLAKE2b-384": "blake2b384",
+	"BLAKE2b-512": "blake2b512",
+	"BLAKE3":      "blake3",
+}
+
+// element is an SPDX 3.0 graph element. Only the properties Zero reads or
+// writes are modeled.
+type element struct {
+	Type         string `json:"type"`
+	SpdxID       string `json:"spdxId,omitempty"`
+	ID           string `json:"@id,omitempty"`
+	CreationInfo any    `json:"creationInfo,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+
+	// CreationInfo
+	SpecVersion  string   `json:"specVersion,omitempty"`
+	Created      string   `json:"created,omitempty"`
+	CreatedBy    []string `json:"createdBy,omitempty"`
+	CreatedUsing []string `json:"createdUsing,omitempty"`
+
+	// SpdxDocument and Sbom
+	RootElement []string `json:"rootElement,omitempty"`
+	Element     []string `json:"element,omitempty"`
+	DataLicense string   `json:"dataLicense,omitempty"`
+
+	// Package
+	PackageVersion     string               `json:"software_packageVersion,omitempty"`
+	PackageURL         string               `json:"software_packageUrl,omitempty"`
+	DownloadLocation   string               `json:"software_downloadLocation,omitempty"`
+	PrimaryPurpose     string               `json:"software_primaryPurpose,omitempty"`
+	CopyrightText      string               `json:"software_copyrightText,omitempty"`
+	SuppliedBy         string               `json:"suppliedBy,omitempty"`
+	VerifiedUsing      []hash30             `json:"verifiedUsing,omitempty"`
+	ExternalIdentifier []externalIdentifier `json:"externalIdentifier,omitempty"`
+
+	// LicenseExpression
+	LicenseExpression string `json:"simplelicensing_licenseExpression,omitempty"`
+
+	// Relationship
+	From             string   `json:"from,omitempty"`
+	RelationshipType string   `json:"relationshipType,omitempty"`
+	To               []string `json:"to,omitempty"`
+	Scope            string   `json:"scope,omitempty"`
+}
+
+type hash30 struct {
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"hashValue"`
+}
+
+type externalIdentifier struct {
+	Type       string `json:"type"`
+	IDType     string `json:"externalIdentifierType"`
+	Identifier string `json:"identifier"`
+}
+
+type graph30 struct {
+	Context any       `json:"@context"`
+	Graph   []element `json:"@graph"`
+}
+
+// ToJSONLD serializes the document as SPDX 3.0 JSON-LD. Packages become
+// software_Package elements, licenses become license expressions linked by
+// hasDeclaredLicense and hasConcludedLicense, and dev dependencies become
+// development-scoped dependsOn relationships.
+func (d *Document) ToJSONLD() ([]byte, error) {
+	ns := strings.TrimSuffix(d.DocumentNamespace, "#")
+	id := func(spdxID string) string { return ns + "#" + spdxID }
+
+	var creators, tools []string
+	var g []element
+	for _, c := range d.CreationInfo.Creators {
+		kind, name, _ := strings.Cut(c, ":")
+		name = strings.TrimSpace(name)
+		switch strings.TrimSpace(kind) {
+		case "Tool":
+			tools = append(tools, id("SPDXRef-Tool-"+idInvalid.ReplaceAllString(name, "-")))
+			g = append(g, element{Type: "Tool", SpdxID: tools[len(tools)-1], CreationInfo: creationInfo, Name: name})
+		case "Organization", "Person":
+			creators = append(creators, id("SPDXRef-"+kind+"-"+idInvalid.ReplaceAllString(name, "-")))
+			g = append(g, element{Type: strings.TrimSpace(kind), SpdxID: creators[len(creators)-1], CreationInfo: creationInfo, Name: name})
+		}
+	}
+	if len(creators) == 0 {
+		creators = append(creators, id("SPDXRef-Organization-Crash-Override"))
+		g = append(g, element{Type: "Organization", SpdxID: creators[0], CreationInfo: creationInfo, Name: "Crash Override"})
+	}
+	// CreationInfo comes first so the agents above can refer to it
+	g = append([]element{{
+		Type:         "CreationInfo",
+		ID:           creationInfo,
+		SpecVersion:  specVersion30,
+		Created:      d.CreationInfo.Created,
+		CreatedBy:    creators,
+		CreatedUsing: tools,
+	}}, g...)
+
+	agents := make(map[string]bool)
+	var members, roots []string
+	for _, r := range d.DocumentDescribes {
+		roots = append(roots, id(r))
+	}
+	for _, p := range d.Packages {
+		e := element{
+			Type:             "software_Package",
+			SpdxID:           id(p.SPDXID),
+			CreationInfo:     creationInfo,
+			Name:             p.Name,
+			Description:      p.Description,
+			PackageVersion:   p.VersionInfo,
+			PackageURL:       p.Purl(),
+			PrimaryPurpose:   strings.ToLower(strings.ReplaceAll(p.PrimaryPackagePurpose, "-", "")),
+			DownloadLocation: valueOf(p.DownloadLocation),
+			CopyrightText:    valueOf(p.CopyrightText),
+		}
+		if e.PrimaryPurpose == "operatingsystem" {
+			e.PrimaryPurpose = "operatingSystem"
+		}
+		if e.PackageURL != "" {
+			e.ExternalIdentifier = []externalIdentifier{{Type: "ExternalIdentifier", IDType: "packageUrl", Identifier: e.PackageURL}}
+		}
+		for _, c := range p.Checksums {
+			if alg, ok := hashAlgorithms30[strings.ToUpper(c.Algorithm)]; ok {
+				e.VerifiedUsing = append(e.VerifiedUsing, hash30{Type: "Hash", Algorithm: alg, Value: c.Value})
+			}
+		}
+		if name := supplierName(p.Supplier); name != "" {
+			e.SuppliedBy = id("SPDXRef-Organization-" + idInvalid.ReplaceAllString(name, "-"))
+			if !agents[e.SuppliedBy] {
+				agents[e.SuppliedBy] = true
+				g = append(g, element{Type: "Organization", SpdxID: e.SuppliedBy, CreationInfo: creationInfo, Name: name})
+			}
+		}
+		g = append(g, e)
+		members = append(members, e.SpdxID)
+
+		for _, l := range []struct{ rel, expr string }{
+			{"hasDeclaredLicense", p.LicenseDeclared},
+			{"hasConcludedLicense", p.LicenseConcluded},
+		} {
+			if !hasValue(l.expr) {
+				continue
+			}
+			licID := id(p.SPDXID + "-" + l.rel)
+			g = append(g,
+				element{Type: "simplelicensing_LicenseExpression", SpdxID: licID, CreationInfo: creationInfo, LicenseExpression: l.expr},
+				element{Type: "Relationship", SpdxID: licID + "-rel", CreationInfo: creationInfo, From: e.SpdxID, RelationshipType: l.rel, To: []string{licID}},
+			)
+		}
+	}
+
+	for i, r := range d.Relationships {
+		rel := element{Type: "Relationship", SpdxID: id(fmt.Sprintf("SPDXRef-Relationship-%d", i+1)), CreationInfo: creationInfo}
+		switch r.Type {
+		case RelDependsOn:
+			rel.From, rel.RelationshipType, rel.To = id(r.Element), "dependsOn", []string{id(r.Related)}
+		case RelContains:
+			rel.From, rel.RelationshipType, rel.To = id(r.Element), "contains", []string{id(r.Related)}
+		case RelDevDependencyOf, RelOptionalDependencyOf, RelDependencyOf:
+			rel.From, rel.RelationshipType, rel.To = id(r.Related), "dependsOn", []string{id(r.Element)}
+			if r.Type != RelDependencyOf {
+				rel.Type, rel.Scope = "LifecycleScopedRelationship", "development"
+			}
+		default:
+			continue
+		}
+		g = append(g, rel)
+	}
+
+	docID := id(DocumentID)
+	g = append(g,
+		element{Type: "software_Sbom", SpdxID: docID + "-sbom", CreationInfo: creationInfo, RootElement: roots, Element: members},
+		element{Type: "SpdxDocument", SpdxID: docID, CreationInfo: creationInfo, Name: d.Name, DataLicense: d.DataLicense, RootElement: []string{docID + "-sbom"}, Element: members},
+	)
+	return json.MarshalIndent(graph30{Context: context30, Graph: g}, "", "  ")
+}
+
+// parseJSONLD reads an SPDX 3.0 JSON-LD document into the 2.3 model
+func parseJSONLD(data []byte) (*Document, error) {
+	var g graph30
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parsing SPDX JSON-LD: %w", err)
+	}
+
+	doc := &Document{SPDXVersion: Version30, SPDXID: DocumentID, Packages: []Package{}}
+	names := make(map[string]string) // agent spdxId -> name
+	exprs := make(map[string]string) // license spdxId -> expression
+	for _, e := range g.Graph {
+		switch typeName(e.Type) {
+		case "Organization", "Person", "Tool", "SoftwareAgent", "Agent":
+			names[e.SpdxID] = e.Name
+		case "LicenseExpression":
+			exprs[e.SpdxID] = e.LicenseExpression
+		case "CreationInfo":
+			doc.CreationInfo.Created = e.Created
+		}
+	}
+
+	index := make(map[string]int)
+	for _, e := range g.Graph {
+		switch typeName(e.Type) {
+		case "CreationInfo":
+			for _, c := range e.CreatedUsing {
+				doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, "Tool: "+names[c])
+			}
+			for _, c := range e.CreatedBy {
+				doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, "Organization: "+names[c])
+			}
+		case "SpdxDocument":
+			doc.Name, doc.DataLicense = e.Name, e.DataLicense
+			doc.DocumentNamespace, _, _ = strings.Cut(e.SpdxID, "#")
+		case "Sbom":
+			for _, r := range e.RootElement {
+				doc.DocumentDescribes = append(doc.DocumentDescribes, localID(r))
+			}
+		case "Package":
+			p := Package{
+				SPDXID:                localID(e.SpdxID),
+				Name:                  e.Name,
+				VersionInfo:           e.PackageVersion,
+				DownloadLocation:      e.DownloadLocation,
+				CopyrightText:         e.CopyrightText,
+				Description:           e.Description,
+				PrimaryPackagePurpose: strings.ToUpper(e.PrimaryPurpose),
+			}
+			if p.PrimaryPackagePurpose == "OPERATINGSYSTEM" {
+				p.PrimaryPackagePurpose = "OPERATING-SYSTEM"
+			}
+			if name := names[e.SuppliedBy]; name != "" {
+				p.Supplier = "Organization: " + name
+			}
+			purl := e.PackageURL
+			for _, x := range e.ExternalIdentifier {
+				if x.IDType == "packageUrl" && purl == "" {
+					purl = x.Identifier
+				}
+				if strings.HasPrefix(x.IDType, "cpe") {
+					p.ExternalRefs = append(p.ExternalRefs, ExternalRef{Category: "SECURITY", Type: "cpe23Type", Locator: x.Identifier})
+				}
+			}
+			if purl != "" {
+				p.ExternalRefs = append(p.ExternalRefs, ExternalRef{Category: "PACKAGE-MANAGER", Type: "purl", Locator: purl})
+			}
+			for _, h := range e.VerifiedUsing {
+				for alg, alg30 := range hashAlgorithms30 {
+					if alg30 == h.Algorithm {
+						p.Checksums = append(p.Checksums, Checksum{Algorithm: alg, Value: h.Value})
+					}
+				}
+			}
+			index[p.SPDXID] = len(doc.Packages)
+			doc.Packages = append(doc.Packages, p)
+		}
+	}
+
+	for _, e := range g.Graph {
+		t := typeName(e.Type)
+		if t != "Relationship" && t != "LifecycleScopedRelationship" {
+			continue
+		}
+		from := localID(e.From)
+		for _, to := range e.To {
+			switch e.RelationshipType {
+			case "dependsOn":
+				if t == "LifecycleScopedRelationship" && e.Scope != "" && e.Scope != "runtime" {
+					doc.Relationships = append(doc.Relationships, Relationship{Element: localID(to), Type: RelDevDependencyOf, Related: from})
+				} else {
+					doc.Relationships = append(doc.Relationships, Relationship{Element: from, Type: RelDependsOn, Related: localID(to)})
+				}
+			case "contains":
+				doc.Relationships = append(doc.Relationships, Relationship{Element: from, Type: RelContains, Related: localID(to)})
+			case "hasDeclaredLicense", "hasConcludedLicense":
+				i, ok := index[from]
+				if !ok {
+					continue
+				}
+				if e.RelationshipType == "hasDeclaredLicense" {
+					doc.Packages[i].LicenseDeclared = exprs[to]
+				} else {
+					doc.Packages[i].LicenseConcluded = exprs[to]
+				}
+			}
+		}
+	}
+	return doc, nil
+}
+
+// localID strips the document namespace from an element ID, leaving the
+// part after "#" ("https://example.com/doc#SPDXRef-Package-x")
+func localID(id string) string {
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+// typeName strips the profile prefix of a 3.0 type ("software_Package")
+func typeName(t string) string {
+	if i := strings.LastIndex(t, "_"); i >= 0 {
+		return t[i+1:]
+	}
+	return t
+}
+
+// valueOf drops NOASSERTION and NONE, which 3.0 expresses by omission
+func valueOf(s string) string {
+	if !hasValue(s) {
+		return ""
+	}
+	return s
+}
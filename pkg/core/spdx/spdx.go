@@ -0,0 +1,183 @@
+// Package spdx converts between SPDX SBOMs and CycloneDX. Documents are
+// modeled on SPDX 2.3 and read from or written to 2.3 JSON, 2.3 tag-value
+// and 3.0 JSON-LD.
+// Specification: https://spdx.github.io/spdx-spec/v2.3/
+package spdx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Spec versions
+const (
+	Version23 = "SPDX-2.3"
+	Version30 = "SPDX-3.0"
+)
+
+// Values for fields that carry no information
+const (
+	NoAssertion = "NOASSERTION"
+	None        = "NONE"
+)
+
+// DocumentID is the SPDX identifier of the document itself
+const DocumentID = "SPDXRef-DOCUMENT"
+
+// Relationship types used for dependency graphs
+const (
+	RelDescribes            = "DESCRIBES"
+	RelContains             = "CONTAINS"
+	RelDependsOn            = "DEPENDS_ON"
+	RelDependencyOf         = "DEPENDENCY_OF"
+	RelDevDependencyOf      = "DEV_DEPENDENCY_OF"
+	RelOptionalDependencyOf = "OPTIONAL_DEPENDENCY_OF"
+)
+
+// Document is an SPDX document
+type Document struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      CreationInfo       `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []Package          `json:"packages"`
+	Relationships     []Relationship     `json:"relationships,omitempty"`
+	ExtractedLicenses []ExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+// CreationInfo records when and by whom a document was created
+type CreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"` // "Tool: name-version", "Organization: name"
+}
+
+// Package is an SPDX package
+type Package struct {
+	SPDXID                string        `json:"SPDXID"`
+	Name                  string        `json:"name"`
+	VersionInfo           string        `json:"versionInfo,omitempty"`
+	Supplier              string        `json:"supplier,omitempty"` // "Organization: name" or "Person: name"
+	DownloadLocation      string        `json:"downloadLocation"`
+	FilesAnalyzed         bool          `json:"filesAnalyzed"`
+	Checksums             []Checksum    `json:"checksums,omitempty"`
+	LicenseConcluded      string        `json:"licenseConcluded,omitempty"`
+	LicenseDeclared       string        `json:"licenseDeclared,omitempty"`
+	CopyrightText         string        `json:"copyrightText,omitempty"`
+	Description           string        `json:"description,omitempty"`
+	ExternalRefs          []ExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string        `json:"primaryPackagePurpose,omitempty"` // APPLICATION, LIBRARY, ...
+}
+
+// Checksum is a package checksum
+type Checksum struct {
+	Algorithm string `json:"algorithm"` // SHA1, SHA256, ...
+	Value     string `json:"checksumValue"`
+}
+
+// ExternalRef is a reference to an external identifier, e.g. a purl
+type ExternalRef struct {
+	Category string `json:"referenceCategory"` // PACKAGE-MANAGER, SECURITY, ...
+	Type     string `json:"referenceType"`     // purl, cpe23Type, ...
+	Locator  string `json:"referenceLocator"`
+}
+
+// Relationship relates two elements, e.g. "A DEPENDS_ON B"
+type Relationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// ExtractedLicense is the text of a license that is not on the SPDX
+// license list, referenced as LicenseRef-
+type ExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name,omitempty"`
+}
+
+// Purl returns the package URL of a package, if it has one
+func (p *Package) Purl() string {
+	for _, ref := range p.ExternalRefs {
+		if ref.Type == "purl" {
+			return ref.Locator
+		}
+	}
+	return ""
+}
+
+// ToJSON serializes the document as SPDX 2.3 JSON
+func (d *Document) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// IsSPDX reports whether data looks like an SPDX document in any of the
+// supported formats
+func IsSPDX(data []byte) bool {
+	return Format(data) != ""
+}
+
+// Parse reads an SPDX document from 2.3 JSON, 2.3 tag-value or 3.0
+// JSON-LD
+func Parse(data []byte) (*Document, error) {
+	switch Format(data) {
+	case "json":
+		var doc Document
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing SPDX JSON: %w", err)
+		}
+		return &doc, nil
+	case "jsonld":
+		return parseJSONLD(data)
+	case "tag-value":
+		return parseTagValue(data)
+	}
+	return nil, fmt.Errorf("not an SPDX document")
+}
+
+// ReadFile reads an SPDX document from a file
+func ReadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Format returns the serialization of an SPDX document: "json" (2.3 JSON),
+// "jsonld" (3.0 JSON-LD), "tag-value" or "" if data is not SPDX
+func Format(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		var probe struct {
+			SPDXVersion string          `json:"spdxVersion"`
+			Context     json.RawMessage `json:"@context"`
+		}
+		if json.Unmarshal(data, &probe) != nil {
+			return ""
+		}
+		switch {
+		case probe.SPDXVersion != "":
+			return "json"
+		case bytes.Contains(probe.Context, []byte("spdx.org/rdf/3.")):
+			return "jsonld"
+		}
+		return ""
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if bytes.HasPrefix(line, []byte("SPDXVersion:")) {
+			return "tag-value"
+		}
+		break
+	}
+	return ""
+}
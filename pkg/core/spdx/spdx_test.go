@@ -0,0 +1,213 @@
+package spdx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+)
+
+func testBOM() *cyclonedx.BOM {
+	bom := cyclonedx.NewSBOM()
+	bom.SerialNumber = "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"
+	bom.Metadata.Component = &cyclonedx.Component{Type: "application", BOMRef: "app", Name: "app", Version: "1.0.0"}
+	bom.Components = []cyclonedx.Component{
+		{
+			Type: "library", BOMRef: "pkg:npm/express@4.18.2", Name: "express", Version: "4.18.2",
+			Purl:     "pkg:npm/express@4.18.2",
+			Hashes:   []cyclonedx.Hash{{Algorithm: "SHA-256", Content: "abc123"}},
+			Licenses: []cyclonedx.LicenseChoice{{License: &cyclonedx.License{ID: "MIT"}}},
+		},
+		{
+			Type: "library", BOMRef: "pkg:npm/%40babel/core@7.22.0", Name: "core", Group: "@babel", Version: "7.22.0",
+			Purl:     "pkg:npm/%40babel/core@7.22.0",
+			Licenses: []cyclonedx.LicenseChoice{{License: &cyclonedx.License{Name: "Acme EULA"}}},
+		},
+		{
+			Type: "library", BOMRef: "pkg:npm/jest@29.0.0", Name: "jest", Version: "29.0.0",
+			Purl: "pkg:npm/jest@29.0.0", Scope: "optional",
+		},
+	}
+	bom.Dependencies = []cyclonedx.Dependency{
+		{Ref: "app", DependsOn: []string{"pkg:npm/express@4.18.2", "pkg:npm/jest@29.0.0"}},
+		{Ref: "pkg:npm/express@4.18.2", DependsOn: []string{"pkg:npm/%40babel/core@7.22.0"}},
+	}
+	return bom
+}
+
+func TestFromCycloneDX(t *testing.T) {
+	doc := FromCycloneDX(testBOM())
+
+	if doc.SPDXVersion != Version23 || doc.Name != "app" {
+		t.Errorf("document = %s %q", doc.SPDXVersion, doc.Name)
+	}
+	if len(doc.Packages) != 4 {
+		t.Fatalf("packages = %d, want 4", len(doc.Packages))
+	}
+	express := doc.Packages[1]
+	if express.Purl() != "pkg:npm/express@4.18.2" || express.LicenseDeclared != "MIT" {
+		t.Errorf("express = %+v", express)
+	}
+	if len(express.Checksums) != 1 || express.Checksums[0].Algorithm != "SHA256" {
+		t.Errorf("checksums = %+v", express.Checksums)
+	}
+	babel := doc.Packages[2]
+	if babel.Name != "@babel/core" || babel.LicenseDeclared != "LicenseRef-Acme-EULA" {
+		t.Errorf("babel = %q %q", babel.Name, babel.LicenseDeclared)
+	}
+	if len(doc.ExtractedLicenses) != 1 || doc.ExtractedLicenses[0].ExtractedText != "Acme EULA" {
+		t.Errorf("extracted = %+v", doc.ExtractedLicenses)
+	}
+
+	want := map[string]bool{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-app":                                                true,
+		"SPDXRef-Package-app DEPENDS_ON SPDXRef-Package-pkg-npm-express-4.18.2":                         true,
+		"SPDXRef-Package-pkg-npm-jest-29.0.0 DEV_DEPENDENCY_OF SPDXRef-Package-app":                     true,
+		"SPDXRef-Package-pkg-npm-express-4.18.2 DEPENDS_ON SPDXRef-Package-pkg-npm-40babel-core-7.22.0": true,
+	}
+	for _, r := range doc.Relationships {
+		key := r.Element + " " + r.Type + " " + r.Related
+		if !want[key] {
+			t.Errorf("unexpected relationship %s", key)
+		}
+		delete(want, key)
+	}
+	for key := range want {
+		t.Errorf("missing relationship %s", key)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := FromCycloneDX(testBOM())
+
+	formats := map[string]func() ([]byte, error){
+		"json":      doc.ToJSON,
+		"jsonld":    doc.ToJSONLD,
+		"tag-value": func() ([]byte, error) { return doc.ToTagValue(), nil },
+	}
+	for name, serialize := range formats {
+		t.Run(name, func(t *testing.T) {
+			data, err := serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Format(data); got != name {
+				t.Fatalf("format = %q, want %q", got, name)
+			}
+			parsed, err := Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bom := parsed.ToCycloneDX()
+
+			if bom.Metadata.Component == nil || bom.Metadata.Component.Name != "app" {
+				t.Fatalf("metadata component = %+v", bom.Metadata.Component)
+			}
+			if len(bom.Components) != 3 {
+				t.Fatalf("components = %d, want 3", len(bom.Components))
+			}
+			byPurl := make(map[string]cyclonedx.Component)
+			for _, c := range bom.Components {
+				byPurl[c.Purl] = c
+			}
+			express := byPurl["pkg:npm/express@4.18.2"]
+			if len(express.Licenses) != 1 || express.Licenses[0].License == nil || express.Licenses[0].License.ID != "MIT" {
+				t.Errorf("express licenses = %+v", express.Licenses)
+			}
+			if len(express.Hashes) != 1 || express.Hashes[0].Algorithm != "SHA-256" || express.Hashes[0].Content != "abc123" {
+				t.Errorf("express hashes = %+v", express.Hashes)
+			}
+			if byPurl["pkg:npm/jest@29.0.0"].Scope != "optional" {
+				t.Errorf("jest scope = %q, want optional", byPurl["pkg:npm/jest@29.0.0"].Scope)
+			}
+
+			graph := cyclonedx.NewGraph(bom)
+			refs := graph.Find("pkg:npm/%40babel/core")
+			if len(refs) != 1 {
+				t.Fatalf("babel refs = %v", refs)
+			}
+			shortest, _ := graph.Paths(refs[0])
+			if got := strings.Join(shortest, " > "); got != "express@4.18.2 > @babel/core@7.22.0" {
+				t.Errorf("babel path = %q", got)
+			}
+		})
+	}
+}
+
+func TestParseTagValue(t *testing.T) {
+	data := `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: vendor
+DocumentNamespace: https://example.com/vendor
+Creator: Tool: example-1.0
+Created: 2024-01-01T00:00:00Z
+
+# Packages
+PackageName: vendor-app
+SPDXID: SPDXRef-app
+PackageVersion: 2.0
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: false
+PackageLicenseDeclared: NOASSERTION
+
+FileName: ./main.c
+SPDXID: SPDXRef-File-main
+FileCopyrightText: <text>Copyright 2024
+Vendor Inc.</text>
+
+PackageName: openssl
+SPDXID: SPDXRef-openssl
+PackageVersion: 3.0.7
+PackageDownloadLocation: https://www.openssl.org/source/openssl-3.0.7.tar.gz
+FilesAnalyzed: false
+PackageChecksum: SHA256: deadbeef
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: apache-2.0
+PackageCopyrightText: <text>Copyright (c) 1998-2022
+The OpenSSL Project</text>
+ExternalRef: PACKAGE-MANAGER purl pkg:generic/openssl@3.0.7
+ExternalRef: SECURITY cpe23Type cpe:2.3:a:openssl:openssl:3.0.7:*:*:*:*:*:*:*
+
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-app
+Relationship: SPDXRef-app CONTAINS SPDXRef-openssl
+`
+	doc, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "vendor" || doc.SPDXID != DocumentID || len(doc.Packages) != 2 {
+		t.Fatalf("doc = %q %q, %d packages", doc.Name, doc.SPDXID, len(doc.Packages))
+	}
+	openssl := doc.Packages[1]
+	if openssl.CopyrightText != "Copyright (c) 1998-2022\nThe OpenSSL Project" {
+		t.Errorf("copyright = %q", openssl.CopyrightText)
+	}
+
+	bom := doc.ToCycloneDX()
+	if bom.Metadata.Component == nil || bom.Metadata.Component.BOMRef != "SPDXRef-app" {
+		t.Fatalf("metadata component = %+v", bom.Metadata.Component)
+	}
+	c := bom.Components[0]
+	if c.Purl != "pkg:generic/openssl@3.0.7" || c.CPE == "" {
+		t.Errorf("openssl = %q %q", c.Purl, c.CPE)
+	}
+	if len(c.Licenses) != 1 || c.Licenses[0].License.ID != "Apache-2.0" {
+		t.Errorf("licenses = %+v", c.Licenses)
+	}
+	// CONTAINS stands in for the root's missing DEPENDS_ON edges
+	if graph := cyclonedx.NewGraph(bom); !graph.HasDependencies() || !graph.Explain("SPDXRef-openssl").Direct {
+		t.Errorf("openssl should be a direct dependency")
+	}
+}
+
+func TestParse_NotSPDX(t *testing.T) {
+	for _, data := range []string{`{"bomFormat":"CycloneDX"}`, "hello", ""} {
+		if IsSPDX([]byte(data)) {
+			t.Errorf("IsSPDX(%q) = true", data)
+		}
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package spdx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// parseTagValue reads an SPDX 2.x tag-value document. Files, snippets and
+// annotations are skipped; only the document, its packages, relationships
+// and extracted licenses are kept.
+func parseTagValue(data []byte) (*Document, error) {
+	doc := &Document{Packages: []Package{}}
+	var pkg *Package
+	var lic *ExtractedLicense
+	inFile := false
+
+	flush := func() {
+		if pkg != nil {
+			doc.Packages = append(doc.Packages, *pkg)
+			pkg = nil
+		}
+		if lic != nil {
+			doc.ExtractedLicenses = append(doc.ExtractedLicenses, *lic)
+			lic = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tag, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"Tag: value\"", lineNo)
+		}
+		tag = strings.TrimSpace(tag)
+		value = strings.TrimSpace(value)
+
+		// <text>...</text> values may span lines
+		if strings.HasPrefix(value, "<text>") {
+			text := strings.TrimPrefix(value, "<text>")
+			for !strings.Contains(text, "</text>") && scanner.Scan() {
+				lineNo++
+				text += "\n" + scanner.Text()
+			}
+			value, _, _ = strings.Cut(text, "</text>")
+			value = strings.TrimSpace(value)
+		}
+
+		switch tag {
+		case "SPDXVersion":
+			doc.SPDXVersion = value
+		case "DataLicense":
+			doc.DataLicense = value
+		case "DocumentName":
+			doc.Name = value
+		case "DocumentNamespace":
+			doc.DocumentNamespace = value
+		case "Creator":
+			doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, value)
+		case "Created":
+			doc.CreationInfo.Created = value
+		case "PackageName":
+			flush()
+			inFile = false
+			pkg = &Package{Name: value, FilesAnalyzed: true}
+		case "FileName", "SnippetSPDXID":
+			flush()
+			inFile = true
+		case "LicenseID":
+			flush()
+			inFile = false
+			lic = &ExtractedLicense{LicenseID: value}
+		case "ExtractedText":
+			if lic != nil {
+				lic.ExtractedText = value
+			}
+		case "LicenseName":
+			if lic != nil {
+				lic.Name = value
+			}
+		case "SPDXID":
+			switch {
+			case pkg != nil:
+				pkg.SPDXID = value
+			case !inFile && doc.SPDXID == "":
+				doc.SPDXID = value
+			}
+		case "Relationship":
+			fields := strings.Fields(value)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: malformed relationship %q", lineNo, value)
+			}
+			doc.Relationships = append(doc.Relationships, Relationship{Element: fields[0], Type: fields[1], Related: fields[2]})
+		}
+
+		if pkg == nil {
+			continue
+		}
+		switch tag {
+		case "PackageVersion":
+			pkg.VersionInfo = value
+		case "PackageSupplier":
+			pkg.Supplier = value
+		case "PackageDownloadLocation":
+			pkg.DownloadLocation = value
+		case "FilesAnalyzed":
+			pkg.FilesAnalyzed = strings.EqualFold(value, "true")
+		case "PackageChecksum":
+			alg, sum, ok := strings.Cut(value, ":")
+			if ok {
+				pkg.Checksums = append(pkg.Checksums, Checksum{Algorithm: strings.TrimSpace(alg), Value: strings.TrimSpace(sum)})
+			}
+		case "PackageLicenseConcluded":
+			pkg.LicenseConcluded = value
+		case "PackageLicenseDeclared":
+			pkg.LicenseDeclared = value
+		case "PackageCopyrightText":
+			pkg.CopyrightText = value
+		case "PackageDescription":
+			pkg.Description = value
+		case "ExternalRef":
+			fields := strings.Fields(value)
+			if len(fields) >= 3 {
+				pkg.ExternalRefs = append(pkg.ExternalRefs, ExternalRef{Category: fields[0], Type: fields[1], Locator: fields[2]})
+			}
+		case "PrimaryPackagePurpose":
+			pkg.PrimaryPackagePurpose = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading SPDX tag-value: %w", err)
+	}
+	flush()
+	return doc, nil
+}
+
+// ToTagValue serializes the document as SPDX 2.3 tag-value
+func (d *Document) ToTagValue() []byte {
+	var b bytes.Buffer
+	field := func(tag, value string) {
+		if value == "" {
+			return
+		}
+		if strings.Contains(value, "\n") {
+			value = "<text>" + value + "</text>"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", tag, value)
+	}
+
+	field("SPDXVersion", d.SPDXVersion)
+	field("DataLicense", d.DataLicense)
+	field("SPDXID", d.SPDXID)
+	field("DocumentName", d.Name)
+	field("DocumentNamespace", d.DocumentNamespace)
+	for _, c := range d.CreationInfo.Creators {
+		field("Creator", c)
+	}
+	field("Created", d.CreationInfo.Created)
+
+	for _, p := range d.Packages {
+		b.WriteString("\n")
+		field("PackageName", p.Name)
+		field("SPDXID", p.SPDXID)
+		field("PackageVersion", p.VersionInfo)
+		field("PackageSupplier", p.Supplier)
+		field("PackageDownloadLocation", p.DownloadLocation)
+		field("FilesAnalyzed", fmt.Sprint(p.FilesAnalyzed))
+		for _, c := range p.Checksums {
+			field("PackageChecksum", c.Algorithm+": "+c.Value)
+		}
+		field("PackageLicenseConcluded", p.LicenseConcluded)
+		field("PackageLicenseDeclared", p.LicenseDeclared)
+		field("PackageCopyrightText", p.CopyrightText)
+		field("PackageDescription", p.Description)
+		for _, ref := range p.ExternalRefs {
+			field("ExternalRef", ref.Category+" "+ref.Type+" "+ref.Locator)
+		}
+		field("PrimaryPackagePurpose", p.PrimaryPackagePurpose)
+	}
+
+	if len(d.Relationships) > 0 {
+		b.WriteString("\n")
+	}
+	for _, r := range d.Relationships {
+		field("Relationship", r.Element+" "+r.Type+" "+r.Related)
+	}
+
+	for _, l := range d.ExtractedLicenses {
+		b.WriteString("\n")
+		field("LicenseID", l.LicenseID)
+		fmt.Fprintf(&b, "ExtractedText: <text>%s</text>\n", l.ExtractedText)
+		field("LicenseName", l.Name)
+	}
+	return b.Bytes()
+}
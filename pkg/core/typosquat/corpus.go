@@ -0,0 +1,117 @@
+// Package typosquat detects package names that imitate popular packages.
+// Detection runs offline against a corpus of popular package names per
+// ecosystem. A corpus is bundled with Zero and can be replaced with a newer
+// one imported by "zero feeds typosquats".
+package typosquat
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+//go:embed popular.json
+var bundledCorpus []byte
+
+var (
+	bundledOnce sync.Once
+	bundled     *Corpus
+)
+
+// Corpus lists popular package names per ecosystem. Ecosystems are purl
+// types (npm, pypi, golang, maven, cargo, gem, nuget, composer); Maven
+// names are group:artifact and npm names include their scope.
+type Corpus struct {
+	Version    string              `json:"version"`
+	Ecosystems map[string][]string `json:"ecosystems"`
+}
+
+// DefaultPath returns where an imported corpus is stored
+func DefaultPath(zeroHome string) string {
+	return filepath.Join(zeroHome, "feeds", "popular-packages.json")
+}
+
+// Bundled returns the corpus shipped with Zero
+func Bundled() *Corpus {
+	bundledOnce.Do(func() {
+		c, err := Parse(bundledCorpus)
+		if err != nil {
+			panic(fmt.Sprintf("typosquat: bundled corpus: %v", err))
+		}
+		bundled = c
+	})
+	return bundled
+}
+
+// Load returns the corpus at path layered over the bundled one: ecosystems
+// present in the file replace the bundled lists. A missing file yields the
+// bundled corpus.
+func Load(path string) (*Corpus, error) {
+	base := Bundled()
+	if path == "" {
+		return base, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return base, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading corpus: %w", err)
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	merged := &Corpus{Version: c.Version, Ecosystems: make(map[string][]string)}
+	for eco, names := range base.Ecosystems {
+		merged.Ecosystems[eco] = names
+	}
+	for eco, names := range c.Ecosystems {
+		merged.Ecosystems[eco] = names
+	}
+	return merged, nil
+}
+
+// Parse reads a corpus document
+func Parse(data []byte) (*Corpus, error) {
+	var c Corpus
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing corpus: %w", err)
+	}
+	if len(c.Ecosystems) == 0 {
+		return nil, fmt.Errorf("corpus has no ecosystems")
+	}
+	return &c, nil
+}
+
+// Import validates the corpus at src and stores it at dst
+func Import(src, dst string) (*Corpus, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, fmt.Errorf("reading corpus: %w", err)
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return nil, fmt.Errorf("writing corpus: %w", err)
+	}
+	return c, nil
+}
+
+// Count returns the number of package names in the corpus
+func (c *Corpus) Count() int {
+	n := 0
+	for _, names := range c.Ecosystems {
+		n += len(names)
+	}
+	return n
+}
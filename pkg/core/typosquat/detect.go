@@ -0,0 +1,346 @@
+package typosquat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Similarity models, in the order they are tried
+const (
+	ModelHomoglyph    = "homoglyph"          // look-alike characters (0/o, rn/m, Cyrillic а)
+	ModelScope        = "scope-confusion"    // npm scope lookalikes (@typess/node, types-node)
+	ModelSeparator    = "separator-swap"     // added, removed or swapped - _ .
+	ModelKeyboard     = "keyboard-adjacency" // one key replaced by its neighbour
+	ModelCombo        = "combosquatting"     // popular name plus an affix (lodash-js, python-requests)
+	ModelEditDistance = "edit-distance"      // transpositions and other small edits
+)
+
+// Match describes a package name that imitates a popular package
+type Match struct {
+	Target     string  `json:"target"`
+	Model      string  `json:"model"`
+	Confidence float64 `json:"confidence"` // 0-1
+	Reason     string  `json:"reason"`
+}
+
+// RiskLevel maps the match confidence to high, medium or low
+func (m *Match) RiskLevel() string {
+	switch {
+	case m.Confidence >= 0.85:
+		return "high"
+	case m.Confidence >= 0.65:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// Detector checks package names against a corpus
+type Detector struct {
+	popular map[string][]string        // ecosystem -> normalized popular names
+	display map[string]string          // normalized -> corpus spelling
+	known   map[string]map[string]bool // ecosystem -> normalized popular names
+}
+
+// NewDetector builds a detector for a corpus
+func NewDetector(c *Corpus) *Detector {
+	d := &Detector{
+		popular: make(map[string][]string),
+		display: make(map[string]string),
+		known:   make(map[string]map[string]bool),
+	}
+	for eco, names := range c.Ecosystems {
+		d.known[eco] = make(map[string]bool)
+		for _, name := range names {
+			norm := Normalize(eco, name)
+			if norm == "" || d.known[eco][norm] {
+				continue
+			}
+			d.known[eco][norm] = true
+			d.popular[eco] = append(d.popular[eco], norm)
+			d.display[norm] = name
+		}
+	}
+	return d
+}
+
+var (
+	pypiSeparators = regexp.MustCompile(`[-_.]+`)
+	goMajorVersion = regexp.MustCompile(`[/.]v[0-9]+$`)
+)
+
+const digits = "0123456789"
+
+// Normalize returns the name packages are compared by: lowercased, PyPI
+// names per PEP 503 and Go module paths without their major version suffix
+func Normalize(ecosystem, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch ecosystem {
+	case "pypi":
+		name = pypiSeparators.ReplaceAllString(name, "-")
+	case "golang":
+		name = goMajorVersion.ReplaceAllString(name, "")
+	}
+	return name
+}
+
+// Check returns the most confident match of name against the ecosystem's
+// popular packages, or nil. Popular packages themselves never match, and
+// neither do packages published under the same owner (npm scope, Maven
+// group, Go host/org) as the popular package they resemble.
+func (d *Detector) Check(ecosystem, name string) *Match {
+	norm := Normalize(ecosystem, name)
+	if norm == "" || d.known[ecosystem][norm] {
+		return nil
+	}
+
+	var best *Match
+	for _, target := range d.popular[ecosystem] {
+		if o := owner(ecosystem, norm); o != "" && o == owner(ecosystem, target) {
+			continue
+		}
+		m := compare(ecosystem, norm, target)
+		if m == nil || (best != nil && m.Confidence <= best.Confidence) {
+			continue
+		}
+		m.Target = d.display[target]
+		m.Reason = fmt.Sprintf(m.Reason, m.Target)
+		best = m
+	}
+	return best
+}
+
+// compare applies the similarity models to a name and one popular package.
+// Reason is a format string taking the popular package name.
+func compare(ecosystem, name, target string) *Match {
+	if fold(name) == fold(target) {
+		return &Match{Model: ModelHomoglyph, Confidence: 0.95, Reason: "Name uses look-alike characters of popular package '%s'"}
+	}
+	if ecosystem == "npm" {
+		if m := scopeConfusion(name, target); m != nil {
+			return m
+		}
+	}
+	if stripSeparators(name) == stripSeparators(target) {
+		return &Match{Model: ModelSeparator, Confidence: 0.85, Reason: "Name differs from popular package '%s' only in separators"}
+	}
+
+	// Short names are too close to each other for the remaining models
+	if len(target) < 4 {
+		return nil
+	}
+	if a, b, ok := adjacentKeySwap(name, target); ok {
+		return &Match{Model: ModelKeyboard, Confidence: 0.8,
+			Reason: fmt.Sprintf("Name replaces '%c' with the adjacent key '%c' in popular package '%%s'", b, a)}
+	}
+	if transposed(name, target) {
+		return &Match{Model: ModelEditDistance, Confidence: 0.75, Reason: "Name swaps adjacent characters of popular package '%s'"}
+	}
+	if affix, ok := combosquat(ecosystem, name, target); ok {
+		return &Match{Model: ModelCombo, Confidence: 0.7,
+			Reason: fmt.Sprintf("Name adds '%s' to popular package '%%s'", affix)}
+	}
+
+	// boto/boto3, psycopg/psycopg2: usually a package's own major versions
+	if strings.TrimRight(name, digits) == strings.TrimRight(target, digits) {
+		return &Match{Model: ModelEditDistance, Confidence: 0.45, Reason: "Name adds a version number to popular package '%s'"}
+	}
+
+	switch dist := editDistance(name, target, 2); {
+	case dist == 1 && len(target) >= 5:
+		return &Match{Model: ModelEditDistance, Confidence: 0.65, Reason: "Name is one edit away from popular package '%s'"}
+	case dist == 2 && len(target) >= 12:
+		return &Match{Model: ModelEditDistance, Confidence: 0.5, Reason: "Name is two edits away from popular package '%s'"}
+	}
+	return nil
+}
+
+// owner returns who controls a package name: the npm scope, Maven group or
+// Go module host and org. Packages of one owner are not squats of each other.
+func owner(ecosystem, name string) string {
+	switch ecosystem {
+	case "npm":
+		if strings.HasPrefix(name, "@") {
+			scope, _, _ := strings.Cut(name, "/")
+			return scope
+		}
+	case "maven":
+		group, _, ok := strings.Cut(name, ":")
+		if ok {
+			return group
+		}
+	case "golang":
+		parts := strings.SplitN(name, "/", 3)
+		if len(parts) == 3 {
+			return parts[0] + "/" + parts[1]
+		}
+	}
+	return ""
+}
+
+// scopeConfusion matches npm scopes that imitate a popular scope
+// (@typess/node for @types/node) and unscoped flattenings of scoped
+// packages (types-node)
+func scopeConfusion(name, target string) *Match {
+	if !strings.HasPrefix(target, "@") {
+		return nil
+	}
+	targetScope, targetPkg, _ := strings.Cut(strings.TrimPrefix(target, "@"), "/")
+
+	if strings.HasPrefix(name, "@") {
+		scope, pkg, _ := strings.Cut(strings.TrimPrefix(name, "@"), "/")
+		if pkg == targetPkg && scope != targetScope &&
+			(editDistance(scope, targetScope, 1) <= 1 || fold(scope) == fold(targetScope) || stripSeparators(scope) == stripSeparators(targetScope)) {
+			return &Match{Model: ModelScope, Confidence: 0.9,
+				Reason: fmt.Sprintf("Scope @%s imitates @%s of popular package '%%s'", scope, targetScope)}
+		}
+		return nil
+	}
+
+	if stripSeparators(name) == stripSeparators(targetScope+targetPkg) {
+		return &Match{Model: ModelScope, Confidence: 0.8, Reason: "Unscoped name imitates popular scoped package '%s'"}
+	}
+	return nil
+}
+
+// homoglyphs maps characters to the ASCII letter they are mistaken for
+var homoglyphs = map[rune]rune{
+	'0': 'o', '1': 'l', 'i': 'l', '3': 'e', '4': 'a', '5': 's', '$': 's',
+	// Cyrillic and Greek
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'х': 'x', 'у': 'y',
+	'і': 'l', 'ј': 'j', 'ѕ': 's', 'ο': 'o', 'ν': 'v', 'α': 'a', 'ι': 'l',
+}
+
+// multiGlyphs are letter pairs read as a single letter
+var multiGlyphs = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d")
+
+// fold maps look-alike characters to a canonical form
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if c, ok := homoglyphs[r]; ok {
+			r = c
+		}
+		b.WriteRune(r)
+	}
+	return multiGlyphs.Replace(b.String())
+}
+
+func stripSeparators(s string) string {
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(s)
+}
+
+// keyboardRows is the QWERTY layout used for adjacency
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// adjacent reports whether two keys are neighbours on a QWERTY keyboard
+func adjacent(a, b byte) bool {
+	row, col := keyPosition(a)
+	row2, col2 := keyPosition(b)
+	if row < 0 || row2 < 0 {
+		return false
+	}
+	dr, dc := row-row2, col-col2
+	return dr >= -1 && dr <= 1 && dc >= -1 && dc <= 1 && (dr != 0 || dc != 0)
+}
+
+func keyPosition(c byte) (int, int) {
+	for row, keys := range keyboardRows {
+		if col := strings.IndexByte(keys, c); col >= 0 {
+			return row, col
+		}
+	}
+	return -1, -1
+}
+
+// adjacentKeySwap reports whether name is target with a single character
+// replaced by a neighbouring key, returning the typed and intended keys
+func adjacentKeySwap(name, target string) (byte, byte, bool) {
+	if len(name) != len(target) {
+		return 0, 0, false
+	}
+	diff := -1
+	for i := 0; i < len(name); i++ {
+		if name[i] != target[i] {
+			if diff >= 0 {
+				return 0, 0, false
+			}
+			diff = i
+		}
+	}
+	if diff < 0 || !adjacent(name[diff], target[diff]) {
+		return 0, 0, false
+	}
+	return name[diff], target[diff], true
+}
+
+// transposed reports whether name is target with two adjacent characters
+// swapped
+func transposed(name, target string) bool {
+	if len(name) != len(target) {
+		return false
+	}
+	for i := 0; i < len(name)-1; i++ {
+		if name[i] != target[i] {
+			return name[i] == target[i+1] && name[i+1] == target[i] && name[i+2:] == target[i+2:]
+		}
+	}
+	return false
+}
+
+// comboAffixes are words attackers attach to popular names
+var comboAffixes = []string{
+	"js", "node", "nodejs", "npm", "py", "py3", "python", "python3", "lib", "go", "golang",
+	"rs", "rust", "official", "secure", "safe", "latest", "new", "fixed", "dev",
+}
+
+// combosquat reports whether name is target with an affix prepended or
+// appended
+func combosquat(ecosystem, name, target string) (string, bool) {
+	if ecosystem == "golang" || ecosystem == "maven" || strings.HasPrefix(target, "@") {
+		return "", false
+	}
+	for _, affix := range comboAffixes {
+		for _, sep := range []string{"-", "_", ".", ""} {
+			if name == target+sep+affix || name == affix+sep+target {
+				return affix, true
+			}
+		}
+	}
+	return "", false
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b, or max+1 once it exceeds max
+func editDistance(a, b string, max int) int {
+	if d := len(a) - len(b); d > max || -d > max {
+		return max + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return min(prev[len(b)], max+1)
+}
@@ -0,0 +1,120 @@
+package typosquat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	d := NewDetector(Bundled())
+
+	tests := []struct {
+		ecosystem string
+		name      string
+		target    string
+		model     string
+	}{
+		{"npm", "1odash", "lodash", ModelHomoglyph},
+		{"npm", "expreѕѕ", "express", ModelHomoglyph},
+		{"npm", "rnoment", "moment", ModelHomoglyph},
+		{"npm", "@typess/node", "@types/node", ModelScope},
+		{"npm", "types-node", "@types/node", ModelScope},
+		{"npm", "react_dom", "react-dom", ModelSeparator},
+		{"pypi", "pythondateutil", "python-dateutil", ModelSeparator},
+		{"npm", "lodaah", "lodash", ModelKeyboard},
+		{"pypi", "reqeusts", "requests", ModelEditDistance},
+		{"npm", "lodash-js", "lodash", ModelCombo},
+		{"pypi", "python-requests", "requests", ModelCombo},
+		{"npm", "expresss", "express", ModelEditDistance},
+		{"golang", "github.com/slrupsen/logrus", "github.com/sirupsen/logrus", ModelHomoglyph},
+	}
+
+	for _, tt := range tests {
+		m := d.Check(tt.ecosystem, tt.name)
+		if m == nil {
+			t.Errorf("Check(%s, %q) = nil, want %s of %s", tt.ecosystem, tt.name, tt.model, tt.target)
+			continue
+		}
+		if m.Target != tt.target || m.Model != tt.model {
+			t.Errorf("Check(%s, %q) = %s of %s, want %s of %s", tt.ecosystem, tt.name, m.Model, m.Target, tt.model, tt.target)
+		}
+		if m.Confidence <= 0 || m.Confidence > 1 || m.Reason == "" {
+			t.Errorf("Check(%s, %q) = %+v", tt.ecosystem, tt.name, m)
+		}
+	}
+}
+
+func TestCheck_NoMatch(t *testing.T) {
+	d := NewDetector(Bundled())
+
+	tests := []struct {
+		ecosystem string
+		name      string
+	}{
+		{"npm", "lodash"},                           // popular itself
+		{"pypi", "Python_Dateutil"},                 // PEP 503 spelling of a popular name
+		{"npm", "@types/lodahs"},                    // same scope as @types/node
+		{"maven", "org.springframework:spring-orm"}, // same group as spring-core
+		{"golang", "github.com/go-chi/chi/v4"},      // another major version
+		{"golang", "github.com/spf13/afero"},        // same org as cobra
+		{"npm", "left-pad"},                         // unrelated
+		{"npm", "pgx"},                              // short names only match exactly
+		{"cargo", "serde"},                          // popular itself
+		{"unknown", "lodahs"},                       // no corpus for the ecosystem
+	}
+
+	for _, tt := range tests {
+		if m := d.Check(tt.ecosystem, tt.name); m != nil {
+			t.Errorf("Check(%s, %q) = %s of %s, want nil", tt.ecosystem, tt.name, m.Model, m.Target)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"lodash", "lodash", 0},
+		{"lodash", "lodsah", 1},
+		{"lodash", "lodas", 1},
+		{"lodash", "lodashes", 2},
+		{"lodash", "express", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, 2); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "corpus.json")
+	if err := os.WriteFile(src, []byte(`{"version":"test","ecosystems":{"npm":["internal-widget"]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := DefaultPath(dir)
+	if _, err := Import(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Version != "test" || len(c.Ecosystems["npm"]) != 1 || len(c.Ecosystems["pypi"]) == 0 {
+		t.Errorf("corpus = %s, npm %v, %d pypi", c.Version, c.Ecosystems["npm"], len(c.Ecosystems["pypi"]))
+	}
+	if m := NewDetector(c).Check("npm", "internal-wigdet"); m == nil || m.Target != "internal-widget" {
+		t.Errorf("Check(internal-wigdet) = %+v", m)
+	}
+
+	if c, err := Load(filepath.Join(dir, "missing.json")); err != nil || c != Bundled() {
+		t.Errorf("Load(missing) = %v, %v; want bundled corpus", c, err)
+	}
+	if _, err := Import(filepath.Join(dir, "missing.json"), dst); err == nil {
+		t.Error("Import(missing) succeeded")
+	}
+}
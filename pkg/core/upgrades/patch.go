@@ -0,0 +1,444 @@
+package upgrades
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// Patch is a manifest edited to apply a plan
+type Patch struct {
+	File     string   `json:"file"`    // Relative to the repository root
+	Changes  []string `json:"changes"` // One line per edit, e.g. "lodash 4.17.15 -> 4.17.21"
+	Original string   `json:"-"`
+	Patched  string   `json:"-"`
+}
+
+// Diff returns the patch as a unified diff that applies with git apply
+func (p *Patch) Diff() string {
+	return unifiedDiff(p.File, p.Original, p.Patched)
+}
+
+// PatchManifests edits the root manifests of a repository to apply a plan:
+// package.json for npm, go.mod for Go and requirements.txt for pip. Files
+// are not written; upgrades no manifest could take are returned as skipped.
+// Lockfiles (package-lock.json, go.sum) must be regenerated afterwards.
+func PatchManifests(repoPath string, plan *Plan) ([]Patch, []Upgrade, error) {
+	byEco := make(map[versions.Ecosystem][]Upgrade)
+	for _, u := range plan.Upgrades {
+		eco := versions.ParseEcosystem(u.Ecosystem)
+		byEco[eco] = append(byEco[eco], u)
+	}
+
+	var patches []Patch
+	var skipped []Upgrade
+	for _, m := range []struct {
+		eco   versions.Ecosystem
+		file  string
+		apply func(repoPath, content string, u Upgrade) (string, bool)
+	}{
+		{versions.NPM, "package.json", patchPackageJSON},
+		{versions.Go, "go.mod", patchGoMod},
+		{versions.PyPI, "requirements.txt", patchRequirements},
+	} {
+		pending := byEco[m.eco]
+		delete(byEco, m.eco)
+		if len(pending) == 0 {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repoPath, m.file))
+		if os.IsNotExist(err) {
+			skipped = append(skipped, pending...)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", m.file, err)
+		}
+
+		patch := Patch{File: m.file, Original: string(data), Patched: string(data)}
+		for _, u := range pending {
+			content, ok := m.apply(repoPath, patch.Patched, u)
+			if !ok {
+				skipped = append(skipped, u)
+				continue
+			}
+			patch.Patched = content
+			patch.Changes = append(patch.Changes, fmt.Sprintf("%s %s -> %s (%s)", u.Package, strings.Join(u.From, ", "), u.To, u.Method))
+		}
+		if patch.Patched != patch.Original {
+			patches = append(patches, patch)
+		}
+	}
+	for _, rest := range byEco {
+		skipped = append(skipped, rest...)
+	}
+	return patches, skipped, nil
+}
+
+// ==================== npm ====================
+
+var npmRangePrefix = regexp.MustCompile(`^(\^|~|>=)?v?[0-9]+(\.[0-9]+){0,2}([-+][0-9A-Za-z.-]+)?$`)
+
+// npmDependencySection matches the start of the package.json objects a bump
+// edits. Ranges in peerDependencies, overrides and resolutions constrain
+// other packages and are left alone.
+var npmDependencySection = regexp.MustCompile(`"(?:dependencies|devDependencies|optionalDependencies)"\s*:\s*\{`)
+
+// patchPackageJSON raises declared ranges in package.json, keeping their
+// operator, or adds an override for transitive dependencies. Yarn and pnpm
+// projects get "resolutions" instead of npm's "overrides".
+func patchPackageJSON(repoPath, content string, u Upgrade) (string, bool) {
+	entry := regexp.MustCompile(`("` + regexp.QuoteMeta(u.Package) + `"\s*:\s*")([^"]*)(")`)
+
+	if u.Method == MethodBump {
+		changed := false
+		sections := npmDependencySection.FindAllStringIndex(content, -1)
+		// Edit from the end so earlier offsets stay valid
+		for i := len(sections) - 1; i >= 0; i-- {
+			start := sections[i][1]
+			closing := strings.Index(content[start:], "}")
+			if closing < 0 {
+				continue
+			}
+			body := entry.ReplaceAllStringFunc(content[start:start+closing], func(m string) string {
+				parts := entry.FindStringSubmatch(m)
+				spec := npmRangePrefix.FindStringSubmatch(parts[2])
+				if spec == nil {
+					return m
+				}
+				changed = true
+				return parts[1] + spec[1] + u.To + parts[3]
+			})
+			content = content[:start] + body + content[start+closing:]
+		}
+		return content, changed
+	}
+
+	field := "overrides"
+	for _, lock := range []string{"yarn.lock", "pnpm-lock.yaml"} {
+		if _, err := os.Stat(filepath.Join(repoPath, lock)); err == nil {
+			field = "resolutions"
+		}
+	}
+	value := "^" + u.To
+	indent := jsonIndent(content)
+
+	block := regexp.MustCompile(`"` + field + `"\s*:\s*\{`)
+	loc := block.FindStringIndex(content)
+	if loc == nil {
+		end := strings.LastIndex(content, "}")
+		if end < 0 {
+			return content, false
+		}
+		body := strings.TrimRight(content[:end], " \t\r\n")
+		sep := ","
+		if strings.HasSuffix(body, "{") {
+			sep = ""
+		}
+		return body + sep + "\n" + indent + `"` + field + `": {` + "\n" +
+			indent + indent + `"` + u.Package + `": "` + value + `"` + "\n" +
+			indent + "}\n" + content[end:], true
+	}
+
+	closing := strings.Index(content[loc[1]:], "}")
+	if closing < 0 {
+		return content, false
+	}
+	existing := content[loc[1] : loc[1]+closing]
+	if entry.MatchString(existing) {
+		existing = entry.ReplaceAllString(existing, "${1}"+value+"${3}")
+		return content[:loc[1]] + existing + content[loc[1]+closing:], true
+	}
+	line := "\n" + indent + indent + `"` + u.Package + `": "` + value + `"`
+	if strings.TrimSpace(existing) == "" {
+		return content[:loc[1]] + line + "\n" + indent + content[loc[1]+closing:], true
+	}
+	return content[:loc[1]] + line + "," + content[loc[1]:], true
+}
+
+// jsonIndent returns the indentation unit of a JSON document
+func jsonIndent(content string) string {
+	for _, line := range strings.Split(content, "\n")[1:] {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && trimmed != line {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// ==================== Go ====================
+
+// goModBlock matches the opening line of a directive block, e.g. "require ("
+var goModBlock = regexp.MustCompile(`^(\w+)\s*\($`)
+
+// patchGoMod sets the required version of a module, adding a require
+// directive when go.mod does not list it. Minimal version selection then
+// picks the fixed version for every importer. Only require directives are
+// edited: replace and exclude lines name versions of their own.
+func patchGoMod(_ string, content string, u Upgrade) (string, bool) {
+	to := u.To
+	if !strings.HasPrefix(to, "v") {
+		to = "v" + to
+	}
+	require := regexp.MustCompile(`^(\s*(?:require\s+)?` + regexp.QuoteMeta(u.Package) + `\s+)(v\S+)`)
+
+	lines := strings.Split(content, "\n")
+	block := ""
+	changed := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if block != "" {
+			if trimmed == ")" {
+				block = ""
+				continue
+			}
+		} else if m := goModBlock.FindStringSubmatch(trimmed); m != nil {
+			block = m[1]
+			continue
+		}
+		if block != "require" && (block != "" || !strings.HasPrefix(trimmed, "require ")) {
+			continue
+		}
+		if require.MatchString(line) {
+			lines[i] = require.ReplaceAllString(line, "${1}"+to)
+			changed = true
+		}
+	}
+	if changed {
+		return strings.Join(lines, "\n"), true
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\nrequire " + u.Package + " " + to + "\n", true
+}
+
+// ==================== pip ====================
+
+var (
+	requirementLine      = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*([^;#]*)(.*)$`)
+	requirementSpecifier = regexp.MustCompile(`^(===|==|~=|!=|<=|>=|<|>)\s*(\S+)$`)
+	pypiNameSeparators   = regexp.MustCompile(`[-_.]+`)
+)
+
+// patchRequirements raises the version in requirements.txt, replacing an
+// exact pin or a minimum, or appends a pin for transitive dependencies.
+// Upper bounds and exclusions that rule out the fixed version are dropped;
+// a specifier that cannot be read skips the upgrade.
+func patchRequirements(_ string, content string, u Upgrade) (string, bool) {
+	name := normalizePyPI(u.Package)
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		m := requirementLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || normalizePyPI(m[1]) != name {
+			continue
+		}
+		spec, ok := raiseSpecifiers(strings.TrimSpace(m[3]), u.To)
+		if !ok {
+			return content, false
+		}
+		rest := m[4]
+		if rest != "" && !strings.HasPrefix(rest, " ") {
+			rest = " " + rest
+		}
+		lines[i] = m[1] + m[2] + spec + rest
+		return strings.Join(lines, "\n"), true
+	}
+
+	pin := fmt.Sprintf("%s>=%s  # fixes %s", u.Package, u.To, strings.Join(u.Fixes, ", "))
+	if len(u.Via) > 0 {
+		pin += " (via " + strings.Join(u.Via, ", ") + ")"
+	}
+	content = strings.TrimRight(content, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return content + pin + "\n", true
+}
+
+// raiseSpecifiers rewrites a PEP 440 specifier set so that to is the
+// minimum: pins and lower bounds are raised to it, and upper bounds or
+// exclusions it does not satisfy are dropped. A set without a lower bound
+// gets ">=to" in front.
+func raiseSpecifiers(spec, to string) (string, bool) {
+	var clauses []string
+	raised := false
+	for _, clause := range strings.Split(spec, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		m := requirementSpecifier.FindStringSubmatch(clause)
+		if m == nil {
+			return "", false
+		}
+		op := m[1]
+		switch op {
+		case "===", "==", ">=", ">", "~=":
+			if raised {
+				continue
+			}
+			raised = true
+			switch op {
+			case "===":
+				op = "=="
+			case ">":
+				op = ">="
+			}
+			clauses = append(clauses, op+to)
+		default:
+			ok, err := versions.Satisfies(versions.PyPI, to, clause)
+			if err != nil {
+				return "", false
+			}
+			if ok {
+				clauses = append(clauses, clause)
+			}
+		}
+	}
+	if !raised {
+		clauses = append([]string{">=" + to}, clauses...)
+	}
+	return strings.Join(clauses, ","), true
+}
+
+// normalizePyPI normalizes a distribution name per PEP 503
+func normalizePyPI(name string) string {
+	return strings.ToLower(pypiNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// ==================== Diff ====================
+
+const diffContext = 3
+
+// unifiedDiff returns a unified diff between two versions of a file
+func unifiedDiff(file, a, b string) string {
+	if a == b {
+		return ""
+	}
+	x, y := splitLines(a), splitLines(b)
+
+	// Longest common subsequence over the lines, which is cheap for
+	// manifests; the common prefix and suffix are trimmed first
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+	xm, ym := x[pre:len(x)-suf], y[pre:len(y)-suf]
+	lcs := make([][]int, len(xm)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(ym)+1)
+	}
+	for i := len(xm) - 1; i >= 0; i-- {
+		for j := len(ym) - 1; j >= 0; j-- {
+			if xm[i] == ym[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte // ' ', '-', '+'
+		line string
+	}
+	ops := make([]op, 0, len(x)+len(y))
+	for _, l := range x[:pre] {
+		ops = append(ops, op{' ', l})
+	}
+	i, j := 0, 0
+	for i < len(xm) || j < len(ym) {
+		switch {
+		case i < len(xm) && j < len(ym) && xm[i] == ym[j]:
+			ops = append(ops, op{' ', xm[i]})
+			i, j = i+1, j+1
+		case i < len(xm) && (j == len(ym) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', xm[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', ym[j]})
+			j++
+		}
+	}
+	for _, l := range x[len(x)-suf:] {
+		ops = append(ops, op{' ', l})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", file, file)
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while changes are
+		// within twice the context of each other
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		end := first
+		for k := first; k < len(ops); k++ {
+			if ops[k].kind != ' ' {
+				end = k + 1
+			} else if k-end >= 2*diffContext {
+				break
+			}
+		}
+		from := max(first-diffContext, start)
+		to := min(end+diffContext, len(ops))
+
+		oldStart, newStart := 1, 1
+		for _, o := range ops[:from] {
+			if o.kind != '+' {
+				oldStart++
+			}
+			if o.kind != '-' {
+				newStart++
+			}
+		}
+		oldLen, newLen := 0, 0
+		for _, o := range ops[from:to] {
+			if o.kind != '+' {
+				oldLen++
+			}
+			if o.kind != '-' {
+				newLen++
+			}
+		}
+		if oldLen == 0 {
+			oldStart--
+		}
+		if newLen == 0 {
+			newStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+		for _, o := range ops[from:to] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// splitLines splits s after each newline. The last line lacks one when the
+// file has no final newline, so it differs from the same text with one.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
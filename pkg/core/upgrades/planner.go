@@ -0,0 +1,505 @@
+// Package upgrades plans the dependency upgrades that fix known
+// vulnerabilities and edits manifests to apply them.
+//
+// The planner prefers bumping direct dependencies: a vulnerable direct
+// dependency is bumped to its fixed version, and a vulnerable transitive
+// dependency is fixed by bumping the direct dependencies that pull it in,
+// when a Resolver shows a newer version of each resolves it to a fixed
+// version. Otherwise the transitive dependency is forced to a fixed version
+// with the ecosystem's override mechanism (npm overrides, a Go require, a
+// pip pin). Each package appears in a plan at most once.
+package upgrades
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// Methods of applying an upgrade
+const (
+	MethodBump     = "bump"     // Raise the version declared in the manifest
+	MethodOverride = "override" // npm overrides / yarn and pnpm resolutions
+	MethodRequire  = "require"  // Go: require the fixed version, MVS does the rest
+	MethodPin      = "pin"      // pip and others: add a minimum version constraint
+)
+
+// maxResolverLookups caps the versions tried per direct dependency when
+// looking for one that resolves a transitive dependency to a fixed version
+const maxResolverLookups = 25
+
+// Dependency is a package in the resolved dependency graph
+type Dependency struct {
+	Name      string // Ecosystem-native name, as used by OSV
+	Version   string
+	Ecosystem string
+	Direct    bool     // Declared in the project's manifest
+	Via       []string // For transitive dependencies, the direct dependencies pulling it in
+}
+
+// Vulnerability is a known vulnerability in a dependency version
+type Vulnerability struct {
+	ID        string
+	Package   string
+	Version   string
+	Ecosystem string
+	Severity  string
+	FixedIn   string // Lowest fixed version, empty if there is none
+}
+
+// Resolver looks up what newer versions of a package resolve their
+// dependencies to, so transitive vulnerabilities can be fixed by bumping a
+// direct dependency instead of overriding the transitive one
+type Resolver interface {
+	// Versions returns the published versions of a package
+	Versions(ctx context.Context, ecosystem, name string) ([]string, error)
+	// Dependencies returns the dependency graph of a package version
+	Dependencies(ctx context.Context, ecosystem, name, version string) (*Resolved, error)
+}
+
+// Resolved is the dependency graph a package version resolves to
+type Resolved struct {
+	Versions map[string]string // Version each dependency resolves to, by name
+	Complete bool              // Every dependency resolved: a package missing from Versions is not one
+}
+
+// version returns what a dependency resolves to, matching names the way
+// the ecosystem does
+func (r *Resolved) version(eco versions.Ecosystem, name string) (string, bool) {
+	want := normalizeName(eco, name)
+	for n, v := range r.Versions {
+		if normalizeName(eco, n) == want {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Upgrade is one dependency change in a plan
+type Upgrade struct {
+	Package   string   `json:"package"`
+	Ecosystem string   `json:"ecosystem"`
+	From      []string `json:"from"` // Installed versions
+	To        string   `json:"to"`
+	Direct    bool     `json:"direct"`
+	Method    string   `json:"method"`
+	Breaking  bool     `json:"breaking"` // Crosses a major version (or a 0.x minor for caret ecosystems)
+	Severity  string   `json:"severity"` // Highest severity fixed
+	Fixes     []string `json:"fixes"`
+	Resolves  []string `json:"resolves,omitempty"` // Transitive packages the bump moves to fixed versions
+	Via       []string `json:"via,omitempty"`      // Direct dependencies pulling in an overridden package
+
+	// For breaking upgrades, the newest compatible version that fixes some
+	// of the vulnerabilities, and which ones
+	CompatibleTo    string   `json:"compatible_to,omitempty"`
+	CompatibleFixes []string `json:"compatible_fixes,omitempty"`
+}
+
+// Unfixed is a vulnerability no upgrade fixes
+type Unfixed struct {
+	ID        string `json:"id"`
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	Severity  string `json:"severity"`
+}
+
+// Summary counts what a plan achieves
+type Summary struct {
+	Vulnerabilities int `json:"vulnerabilities"`
+	Fixed           int `json:"fixed"`
+	Unfixed         int `json:"unfixed"`
+	Upgrades        int `json:"upgrades"`
+	DirectUpgrades  int `json:"direct_upgrades"`
+	Overrides       int `json:"overrides"`
+	Breaking        int `json:"breaking"`
+}
+
+// Plan is a set of upgrades ordered by impact: highest severity first,
+// then most vulnerabilities fixed
+type Plan struct {
+	Upgrades []Upgrade `json:"upgrades"`
+	Unfixed  []Unfixed `json:"unfixed,omitempty"`
+	Summary  Summary   `json:"summary"`
+}
+
+var severityRank = map[string]int{"critical": 4, "high": 3, "medium": 2, "moderate": 2, "low": 1}
+
+type pkgKey struct {
+	eco  versions.Ecosystem
+	name string
+}
+
+func keyOf(ecosystem, name string) pkgKey {
+	eco := versions.ParseEcosystem(ecosystem)
+	return pkgKey{eco, normalizeName(eco, name)}
+}
+
+// normalizeName returns the form of a package name that compares equal for
+// every spelling the ecosystem accepts (PyPI's Flask, flask and FLASK)
+func normalizeName(eco versions.Ecosystem, name string) string {
+	if eco == versions.PyPI {
+		return normalizePyPI(name)
+	}
+	return name
+}
+
+// target collects the fixable vulnerabilities of one package
+type target struct {
+	name      string
+	ecosystem string
+	from      map[string]bool
+	vulns     []Vulnerability
+	direct    bool
+	via       map[string]bool
+}
+
+// required returns the lowest version fixing every vulnerability
+func (t *target) required() string {
+	eco := versions.ParseEcosystem(t.ecosystem)
+	var best string
+	for _, v := range t.vulns {
+		if best == "" || versions.Compare(eco, v.FixedIn, best) > 0 {
+			best = v.FixedIn
+		}
+	}
+	return best
+}
+
+func (t *target) ids() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, v := range t.vulns {
+		if !seen[v.ID] {
+			seen[v.ID] = true
+			ids = append(ids, v.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (t *target) severity() string {
+	var best string
+	for _, v := range t.vulns {
+		if s := strings.ToLower(v.Severity); severityRank[s] > severityRank[best] {
+			best = s
+		}
+	}
+	if best == "" {
+		return "unknown"
+	}
+	return best
+}
+
+// Build computes an upgrade plan. The resolver may be nil, in which case
+// transitive vulnerabilities are always fixed with overrides.
+func Build(ctx context.Context, deps []Dependency, vulns []Vulnerability, resolver Resolver) *Plan {
+	plan := &Plan{Upgrades: []Upgrade{}}
+
+	installed := make(map[pkgKey][]Dependency)
+	for _, d := range deps {
+		k := keyOf(d.Ecosystem, d.Name)
+		installed[k] = append(installed[k], d)
+	}
+
+	targets := make(map[pkgKey]*target)
+	var order []pkgKey
+	seen := make(map[string]bool)
+	for _, v := range vulns {
+		id := v.ID + "|" + v.Ecosystem + "|" + v.Package + "|" + v.Version
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		plan.Summary.Vulnerabilities++
+
+		if v.FixedIn == "" {
+			plan.Unfixed = append(plan.Unfixed, Unfixed{ID: v.ID, Package: v.Package, Version: v.Version, Ecosystem: v.Ecosystem, Severity: v.Severity})
+			continue
+		}
+		k := keyOf(v.Ecosystem, v.Package)
+		t, ok := targets[k]
+		if !ok {
+			t = &target{name: v.Package, ecosystem: v.Ecosystem, from: make(map[string]bool), via: make(map[string]bool)}
+			targets[k] = t
+			order = append(order, k)
+		}
+		t.from[v.Version] = true
+		t.vulns = append(t.vulns, v)
+	}
+
+	for _, k := range order {
+		t := targets[k]
+		for _, d := range installed[k] {
+			if d.Direct {
+				t.direct = true
+			}
+			for _, via := range d.Via {
+				t.via[via] = true
+			}
+		}
+		// A package not found in the graph is assumed to be declared directly
+		if len(installed[k]) == 0 {
+			t.direct = true
+		}
+	}
+
+	upgrades := make(map[pkgKey]*Upgrade)
+	var upgradeOrder []pkgKey
+	addUpgrade := func(k pkgKey, u *Upgrade) *Upgrade {
+		if existing, ok := upgrades[k]; ok {
+			return existing
+		}
+		upgrades[k] = u
+		upgradeOrder = append(upgradeOrder, k)
+		return u
+	}
+
+	// Direct dependencies are bumped to the version fixing all their vulns
+	for _, k := range order {
+		t := targets[k]
+		if !t.direct {
+			continue
+		}
+		u := addUpgrade(k, &Upgrade{Package: t.name, Ecosystem: t.ecosystem, Direct: true, Method: MethodBump})
+		u.From = sortedKeys(t.from)
+		u.To = t.required()
+		u.Fixes = t.ids()
+		u.Severity = t.severity()
+		u.Breaking = !allCompatible(k.eco, u.From, u.To)
+		if u.Breaking {
+			u.CompatibleTo, u.CompatibleFixes = compatibleFix(k.eco, t.vulns)
+		}
+	}
+
+	// Transitive dependencies are fixed by bumping the direct dependencies
+	// that pull them in, when every one of them has a version that resolves
+	// a fixed version, and overridden otherwise
+	r := &resolution{resolver: resolver, installed: installed, versions: make(map[pkgKey][]string), deps: make(map[string]*Resolved)}
+	for _, k := range order {
+		t := targets[k]
+		if t.direct {
+			continue
+		}
+		required := t.required()
+		bumps := r.bumpsFor(ctx, t, required)
+		if bumps == nil {
+			method := MethodPin
+			switch k.eco {
+			case versions.NPM:
+				method = MethodOverride
+			case versions.Go:
+				method = MethodRequire
+			}
+			u := addUpgrade(k, &Upgrade{Package: t.name, Ecosystem: t.ecosystem, Method: method})
+			u.From = sortedKeys(t.from)
+			u.To = required
+			u.Fixes = t.ids()
+			u.Severity = t.severity()
+			u.Via = sortedKeys(t.via)
+			u.Breaking = !allCompatible(k.eco, u.From, u.To)
+			continue
+		}
+		for _, b := range bumps {
+			dk := keyOf(b.ecosystem, b.name)
+			u := addUpgrade(dk, &Upgrade{Package: b.name, Ecosystem: b.ecosystem, Direct: true, Method: MethodBump, From: []string{b.from}, To: b.to})
+			if versions.Compare(dk.eco, b.to, u.To) > 0 {
+				u.To = b.to
+			}
+			u.Fixes = mergeSorted(u.Fixes, t.ids())
+			u.Resolves = mergeSorted(u.Resolves, []string{t.name})
+			if severityRank[t.severity()] > severityRank[u.Severity] || u.Severity == "" {
+				u.Severity = t.severity()
+			}
+			u.Breaking = !allCompatible(dk.eco, u.From, u.To)
+		}
+	}
+
+	fixed := make(map[string]bool)
+	for _, k := range upgradeOrder {
+		u := upgrades[k]
+		plan.Upgrades = append(plan.Upgrades, *u)
+		for _, id := range u.Fixes {
+			fixed[id] = true
+		}
+		if u.Method == MethodBump {
+			plan.Summary.DirectUpgrades++
+		} else {
+			plan.Summary.Overrides++
+		}
+		if u.Breaking {
+			plan.Summary.Breaking++
+		}
+	}
+	sort.SliceStable(plan.Upgrades, func(i, j int) bool {
+		a, b := plan.Upgrades[i], plan.Upgrades[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if len(a.Fixes) != len(b.Fixes) {
+			return len(a.Fixes) > len(b.Fixes)
+		}
+		return a.Package < b.Package
+	})
+
+	plan.Summary.Upgrades = len(plan.Upgrades)
+	plan.Summary.Fixed = len(fixed)
+	plan.Summary.Unfixed = len(plan.Unfixed)
+	return plan
+}
+
+// bump is a direct dependency version that resolves a transitive
+// dependency to a fixed version
+type bump struct {
+	name, ecosystem, from, to string
+}
+
+// resolution caches resolver lookups across targets
+type resolution struct {
+	resolver  Resolver
+	installed map[pkgKey][]Dependency
+	versions  map[pkgKey][]string
+	deps      map[string]*Resolved
+}
+
+// bumpsFor finds, for every direct dependency pulling in a transitive
+// target, the lowest version (compatible versions first) whose resolved
+// dependencies include a fixed version of the target, or no longer include
+// it at all. A package missing from a partially resolved graph may still
+// be there, so that version does not count. It returns nil if any of them
+// has none.
+func (r *resolution) bumpsFor(ctx context.Context, t *target, required string) []bump {
+	if r.resolver == nil || len(t.via) == 0 {
+		return nil
+	}
+	eco := versions.ParseEcosystem(t.ecosystem)
+	var bumps []bump
+	for _, via := range sortedKeys(t.via) {
+		dk := keyOf(t.ecosystem, via)
+		var current string
+		for _, d := range r.installed[dk] {
+			if d.Direct {
+				current = d.Version
+			}
+		}
+		if current == "" {
+			return nil
+		}
+		to := r.fixingVersion(ctx, dk, current, func(resolved *Resolved) bool {
+			if v, ok := resolved.version(eco, t.name); ok {
+				return versions.Compare(eco, v, required) >= 0
+			}
+			return resolved.Complete
+		})
+		if to == "" {
+			return nil
+		}
+		bumps = append(bumps, bump{name: via, ecosystem: t.ecosystem, from: current, to: to})
+	}
+	return bumps
+}
+
+func (r *resolution) fixingVersion(ctx context.Context, k pkgKey, current string, fixed func(*Resolved) bool) string {
+	available, ok := r.versions[k]
+	if !ok {
+		all, err := r.resolver.Versions(ctx, string(k.eco), k.name)
+		if err == nil {
+			for _, v := range all {
+				if stableVersion.MatchString(v) && versions.Compare(k.eco, v, current) > 0 {
+					available = append(available, v)
+				}
+			}
+			sort.Slice(available, func(i, j int) bool { return versions.Compare(k.eco, available[i], available[j]) < 0 })
+		}
+		r.versions[k] = available
+	}
+
+	// Compatible versions first, then the rest, each in ascending order
+	candidates := make([]string, 0, len(available))
+	for _, v := range available {
+		if versions.Compatible(k.eco, current, v) {
+			candidates = append(candidates, v)
+		}
+	}
+	for _, v := range available {
+		if !versions.Compatible(k.eco, current, v) {
+			candidates = append(candidates, v)
+		}
+	}
+
+	for i, v := range candidates {
+		if i >= maxResolverLookups || ctx.Err() != nil {
+			break
+		}
+		cacheKey := string(k.eco) + "|" + k.name + "|" + v
+		resolved, ok := r.deps[cacheKey]
+		if !ok {
+			var err error
+			if resolved, err = r.resolver.Dependencies(ctx, string(k.eco), k.name, v); err != nil {
+				resolved = nil
+			}
+			r.deps[cacheKey] = resolved
+		}
+		if resolved != nil && fixed(resolved) {
+			return v
+		}
+	}
+	return ""
+}
+
+// stableVersion matches release versions, skipping prereleases
+var stableVersion = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+)*$`)
+
+func allCompatible(eco versions.Ecosystem, from []string, to string) bool {
+	for _, v := range from {
+		if !versions.Compatible(eco, v, to) {
+			return false
+		}
+	}
+	return true
+}
+
+// compatibleFix returns the newest fixed version that is compatible with
+// the installed version it fixes, and the vulnerabilities it fixes
+func compatibleFix(eco versions.Ecosystem, vulns []Vulnerability) (string, []string) {
+	var to string
+	for _, v := range vulns {
+		if versions.Compatible(eco, v.Version, v.FixedIn) && (to == "" || versions.Compare(eco, v.FixedIn, to) > 0) {
+			to = v.FixedIn
+		}
+	}
+	if to == "" {
+		return "", nil
+	}
+	var fixes []string
+	for _, v := range vulns {
+		if versions.Compatible(eco, v.Version, to) && versions.Compare(eco, v.FixedIn, to) <= 0 {
+			fixes = mergeSorted(fixes, []string{v.ID})
+		}
+	}
+	return to, fixes
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func mergeSorted(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		set[s] = true
+	}
+	return sortedKeys(set)
+}
@@ -0,0 +1,62 @@
+package upgrades
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/versions"
+)
+
+// DepsDevResolver resolves package versions and their dependencies with
+// the deps.dev API
+type DepsDevResolver struct {
+	client *liveapi.DepsDevClient
+}
+
+// NewDepsDevResolver creates a resolver backed by a deps.dev client
+func NewDepsDevResolver(client *liveapi.DepsDevClient) *DepsDevResolver {
+	return &DepsDevResolver{client: client}
+}
+
+// Versions returns the published versions of a package
+func (r *DepsDevResolver) Versions(ctx context.Context, ecosystem, name string) ([]string, error) {
+	info, err := r.client.GetPackageVersions(ctx, ecosystem, name)
+	if err != nil {
+		return nil, err
+	}
+	all := make([]string, 0, len(info.Versions))
+	for _, v := range info.Versions {
+		all = append(all, v.VersionKey.Version)
+	}
+	return all, nil
+}
+
+// Dependencies returns the version each transitive dependency of a package
+// version resolves to. When a package resolves to several versions (npm
+// nesting), the lowest is kept since it decides whether a vuln remains.
+// The graph is incomplete when deps.dev reports errors resolving any node.
+func (r *DepsDevResolver) Dependencies(ctx context.Context, ecosystem, name, version string) (*Resolved, error) {
+	graph, err := r.client.GetDependencies(ctx, ecosystem, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if graph.Error != "" {
+		return nil, fmt.Errorf("resolving %s@%s: %s", name, version, graph.Error)
+	}
+	eco := versions.ParseEcosystem(ecosystem)
+	resolved := &Resolved{Versions: make(map[string]string, len(graph.Nodes)), Complete: true}
+	for _, node := range graph.Nodes {
+		if len(node.Errors) > 0 {
+			resolved.Complete = false
+		}
+		if node.Relation == "SELF" {
+			continue
+		}
+		key := node.VersionKey
+		if current, ok := resolved.Versions[key.Name]; !ok || versions.Compare(eco, key.Version, current) < 0 {
+			resolved.Versions[key.Name] = key.Version
+		}
+	}
+	return resolved, nil
+}
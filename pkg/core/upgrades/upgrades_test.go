@@ -0,0 +1,362 @@
+package upgrades
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeResolver serves versions and resolved dependencies from maps
+type fakeResolver struct {
+	versions map[string][]string
+	deps     map[string]map[string]string // name@version -> resolved
+	partial  bool                         // Graphs are incomplete
+}
+
+func (f *fakeResolver) Versions(_ context.Context, _, name string) ([]string, error) {
+	return f.versions[name], nil
+}
+
+func (f *fakeResolver) Dependencies(_ context.Context, _, name, version string) (*Resolved, error) {
+	deps, ok := f.deps[name+"@"+version]
+	if !ok {
+		return nil, nil
+	}
+	return &Resolved{Versions: deps, Complete: !f.partial}, nil
+}
+
+func findUpgrade(plan *Plan, name string) *Upgrade {
+	for i := range plan.Upgrades {
+		if plan.Upgrades[i].Package == name {
+			return &plan.Upgrades[i]
+		}
+	}
+	return nil
+}
+
+func TestBuild(t *testing.T) {
+	deps := []Dependency{
+		{Name: "lodash", Version: "4.17.15", Ecosystem: "npm", Direct: true},
+		{Name: "mkdirp", Version: "0.5.1", Ecosystem: "npm", Direct: true},
+		{Name: "express", Version: "4.17.0", Ecosystem: "npm", Direct: true},
+		{Name: "minimist", Version: "0.0.8", Ecosystem: "npm", Via: []string{"mkdirp"}},
+		{Name: "qs", Version: "6.7.0", Ecosystem: "npm", Via: []string{"express"}},
+		{Name: "debug", Version: "2.6.8", Ecosystem: "npm", Via: []string{"express"}},
+	}
+	vulns := []Vulnerability{
+		{ID: "GHSA-1", Package: "lodash", Version: "4.17.15", Ecosystem: "npm", Severity: "high", FixedIn: "4.17.19"},
+		{ID: "GHSA-2", Package: "lodash", Version: "4.17.15", Ecosystem: "npm", Severity: "critical", FixedIn: "4.17.21"},
+		{ID: "GHSA-2", Package: "lodash", Version: "4.17.15", Ecosystem: "npm", Severity: "critical", FixedIn: "4.17.21"},
+		{ID: "GHSA-3", Package: "minimist", Version: "0.0.8", Ecosystem: "npm", Severity: "critical", FixedIn: "0.2.1"},
+		{ID: "GHSA-4", Package: "qs", Version: "6.7.0", Ecosystem: "npm", Severity: "high", FixedIn: "6.7.3"},
+		{ID: "GHSA-5", Package: "debug", Version: "2.6.8", Ecosystem: "npm", Severity: "low", FixedIn: "2.6.9"},
+		{ID: "GHSA-6", Package: "express", Version: "4.17.0", Ecosystem: "npm", Severity: "medium"},
+	}
+
+	t.Run("offline", func(t *testing.T) {
+		plan := Build(context.Background(), deps, vulns, nil)
+
+		if plan.Summary.Vulnerabilities != 6 || plan.Summary.Fixed != 5 || plan.Summary.Unfixed != 1 {
+			t.Errorf("summary = %+v", plan.Summary)
+		}
+		if plan.Upgrades[0].Package != "lodash" {
+			t.Errorf("first upgrade = %s, want lodash (critical, two fixes)", plan.Upgrades[0].Package)
+		}
+		lodash := findUpgrade(plan, "lodash")
+		if lodash.To != "4.17.21" || lodash.Method != MethodBump || lodash.Breaking || len(lodash.Fixes) != 2 {
+			t.Errorf("lodash = %+v", lodash)
+		}
+		minimist := findUpgrade(plan, "minimist")
+		if minimist == nil || minimist.Method != MethodOverride || !minimist.Breaking || strings.Join(minimist.Via, ",") != "mkdirp" {
+			t.Errorf("minimist = %+v", minimist)
+		}
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		resolver := &fakeResolver{
+			versions: map[string][]string{
+				"mkdirp":  {"0.5.1", "0.5.2-beta", "0.5.5", "0.5.6", "1.0.4"},
+				"express": {"4.17.0", "4.17.1", "4.17.3", "4.18.2", "5.0.0"},
+			},
+			deps: map[string]map[string]string{
+				"mkdirp@0.5.5":   {"minimist": "1.2.5"},
+				"mkdirp@0.5.6":   {"minimist": "1.2.6"},
+				"express@4.17.1": {"qs": "6.7.0", "debug": "2.6.9"},
+				"express@4.17.3": {"qs": "6.9.7", "debug": "2.6.9"},
+			},
+		}
+		plan := Build(context.Background(), deps, vulns, resolver)
+
+		if findUpgrade(plan, "minimist") != nil || findUpgrade(plan, "qs") != nil {
+			t.Fatalf("transitive deps should be fixed by direct bumps: %+v", plan.Upgrades)
+		}
+		mkdirp := findUpgrade(plan, "mkdirp")
+		if mkdirp == nil || mkdirp.To != "0.5.5" || mkdirp.Breaking || strings.Join(mkdirp.Resolves, ",") != "minimist" {
+			t.Errorf("mkdirp = %+v", mkdirp)
+		}
+		express := findUpgrade(plan, "express")
+		if express == nil || express.To != "4.17.3" || strings.Join(express.Fixes, ",") != "GHSA-4,GHSA-5" {
+			t.Errorf("express = %+v", express)
+		}
+		if plan.Summary.DirectUpgrades != 3 || plan.Summary.Overrides != 0 {
+			t.Errorf("summary = %+v", plan.Summary)
+		}
+	})
+
+	t.Run("missing from the resolved graph", func(t *testing.T) {
+		deps := []Dependency{
+			{Name: "requests", Version: "2.25.0", Ecosystem: "PyPI", Direct: true},
+			{Name: "urllib3", Version: "1.26.4", Ecosystem: "PyPI", Via: []string{"requests"}},
+			{Name: "idna", Version: "2.10", Ecosystem: "PyPI", Via: []string{"requests"}},
+		}
+		vulns := []Vulnerability{
+			{ID: "PYSEC-1", Package: "urllib3", Version: "1.26.4", Ecosystem: "PyPI", Severity: "high", FixedIn: "1.26.18"},
+			{ID: "PYSEC-2", Package: "idna", Version: "2.10", Ecosystem: "PyPI", Severity: "medium", FixedIn: "3.7"},
+		}
+		resolver := &fakeResolver{
+			versions: map[string][]string{"requests": {"2.25.0", "2.25.1", "2.31.0"}},
+			deps: map[string]map[string]string{
+				// Spelled differently, still urllib3, still vulnerable
+				"requests@2.25.1": {"URLLib3": "1.26.4"},
+				"requests@2.31.0": {"URLLib3": "2.0.7"},
+			},
+		}
+
+		plan := Build(context.Background(), deps, vulns, resolver)
+		requests := findUpgrade(plan, "requests")
+		if requests == nil || requests.To != "2.31.0" || strings.Join(requests.Resolves, ",") != "idna,urllib3" {
+			t.Errorf("requests = %+v", requests)
+		}
+
+		// idna is missing from an incomplete graph: it may still be there
+		resolver.partial = true
+		plan = Build(context.Background(), deps, vulns, resolver)
+		if idna := findUpgrade(plan, "idna"); idna == nil || idna.Method != MethodPin {
+			t.Errorf("idna = %+v, want a pin", idna)
+		}
+	})
+
+	t.Run("breaking with compatible alternative", func(t *testing.T) {
+		plan := Build(context.Background(), []Dependency{{Name: "urllib3", Version: "1.26.4", Ecosystem: "PyPI", Direct: true}}, []Vulnerability{
+			{ID: "PYSEC-1", Package: "urllib3", Version: "1.26.4", Ecosystem: "PyPI", Severity: "medium", FixedIn: "1.26.18"},
+			{ID: "PYSEC-2", Package: "urllib3", Version: "1.26.4", Ecosystem: "PyPI", Severity: "medium", FixedIn: "2.2.2"},
+		}, nil)
+		u := plan.Upgrades[0]
+		if u.To != "2.2.2" || !u.Breaking || u.CompatibleTo != "1.26.18" || strings.Join(u.CompatibleFixes, ",") != "PYSEC-1" {
+			t.Errorf("urllib3 = %+v", u)
+		}
+	})
+}
+
+func TestPatchManifests(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		"package.json": `{
+  "name": "app",
+  "dependencies": {
+    "lodash": "^4.17.15",
+    "mkdirp": "0.5.1"
+  }
+}
+`,
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	golang.org/x/net v0.17.0 // indirect
+	github.com/gin-gonic/gin v1.9.0
+)
+`,
+		"requirements.txt": "requests==2.28.1\nflask>=2.0,<3  # web\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan := &Plan{Upgrades: []Upgrade{
+		{Package: "lodash", Ecosystem: "npm", From: []string{"4.17.15"}, To: "4.17.21", Direct: true, Method: MethodBump},
+		{Package: "minimist", Ecosystem: "npm", From: []string{"0.0.8"}, To: "1.2.6", Method: MethodOverride},
+		{Package: "golang.org/x/net", Ecosystem: "Go", From: []string{"0.17.0"}, To: "0.23.0", Method: MethodRequire},
+		{Package: "requests", Ecosystem: "PyPI", From: []string{"2.28.1"}, To: "2.31.0", Direct: true, Method: MethodBump},
+		{Package: "Flask", Ecosystem: "PyPI", From: []string{"2.0.0"}, To: "2.2.5", Direct: true, Method: MethodBump},
+		{Package: "urllib3", Ecosystem: "PyPI", From: []string{"1.26.4"}, To: "1.26.18", Method: MethodPin, Fixes: []string{"PYSEC-1"}, Via: []string{"requests"}},
+		{Package: "serde", Ecosystem: "crates.io", From: []string{"1.0.0"}, To: "1.0.1", Direct: true, Method: MethodBump},
+	}}
+
+	patches, skipped, err := PatchManifests(repo, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 1 || skipped[0].Package != "serde" {
+		t.Errorf("skipped = %+v", skipped)
+	}
+
+	patched := make(map[string]string)
+	for _, p := range patches {
+		patched[p.File] = p.Patched
+	}
+	want := map[string][]string{
+		"package.json":     {`"lodash": "^4.17.21"`, `"mkdirp": "0.5.1"`, "  },\n  \"overrides\": {\n    \"minimist\": \"^1.2.6\"\n  }\n}"},
+		"go.mod":           {"golang.org/x/net v0.23.0 // indirect"},
+		"requirements.txt": {"requests==2.31.0\n", "flask>=2.2.5,<3 # web\n", "urllib3>=1.26.18  # fixes PYSEC-1 (via requests)\n"},
+	}
+	for file, snippets := range want {
+		for _, s := range snippets {
+			if !strings.Contains(patched[file], s) {
+				t.Errorf("%s missing %q:\n%s", file, s, patched[file])
+			}
+		}
+	}
+
+	for _, p := range patches {
+		if p.File != "go.mod" {
+			continue
+		}
+		diff := p.Diff()
+		if !strings.Contains(diff, "--- a/go.mod\n+++ b/go.mod\n@@ -3,6 +3,6 @@\n") ||
+			!strings.Contains(diff, "-\tgolang.org/x/net v0.17.0 // indirect\n+\tgolang.org/x/net v0.23.0 // indirect\n") {
+			t.Errorf("unexpected diff:\n%s", diff)
+		}
+	}
+}
+
+func TestPatchPackageJSON_ExistingOverrides(t *testing.T) {
+	content := "{\n\t\"overrides\": {\n\t\t\"semver\": \"^7.5.2\"\n\t}\n}\n"
+	got, ok := patchPackageJSON(t.TempDir(), content, Upgrade{Package: "minimist", To: "1.2.6", Method: MethodOverride})
+	if !ok || !strings.Contains(got, "\"overrides\": {\n\t\t\"minimist\": \"^1.2.6\",\n\t\t\"semver\": \"^7.5.2\"") {
+		t.Errorf("patched = %q", got)
+	}
+	got, _ = patchPackageJSON(t.TempDir(), got, Upgrade{Package: "semver", To: "7.5.4", Method: MethodOverride})
+	if !strings.Contains(got, "\"semver\": \"^7.5.4\"") {
+		t.Errorf("existing override not raised: %q", got)
+	}
+}
+
+func TestPatchPackageJSON_BumpOnlyDependencies(t *testing.T) {
+	content := `{
+  "dependencies": {"lodash": "^4.17.15"},
+  "peerDependencies": {"lodash": "^4.0.0"},
+  "devDependencies": {"lodash": "~4.17.15"},
+  "overrides": {"lodash": "4.17.10"}
+}
+`
+	got, ok := patchPackageJSON(t.TempDir(), content, Upgrade{Package: "lodash", To: "4.17.21", Method: MethodBump})
+	want := `{
+  "dependencies": {"lodash": "^4.17.21"},
+  "peerDependencies": {"lodash": "^4.0.0"},
+  "devDependencies": {"lodash": "~4.17.21"},
+  "overrides": {"lodash": "4.17.10"}
+}
+`
+	if !ok || got != want {
+		t.Errorf("patched = %s", got)
+	}
+}
+
+func TestPatchRequirements_Specifiers(t *testing.T) {
+	tests := []struct {
+		line string
+		want string // "" when the upgrade is skipped
+	}{
+		{"requests", "requests>=2.31.0"},
+		{"requests==2.28.1", "requests==2.31.0"},
+		{"requests>=2.0,<3", "requests>=2.31.0,<3"},
+		{"requests<2.20", "requests>=2.31.0"},
+		{"requests>=2.0,<=2.30,!=2.25.0", "requests>=2.31.0,!=2.25.0"},
+		{"requests!=2.31.0", "requests>=2.31.0"},
+		{"requests~=2.19", "requests~=2.31.0"},
+		{"requests @ https://example.com/requests.whl", ""},
+	}
+	for _, tt := range tests {
+		got, ok := patchRequirements("", tt.line+"\n", Upgrade{Package: "requests", To: "2.31.0", Method: MethodBump})
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: patched to %q, want skipped", tt.line, got)
+			}
+			continue
+		}
+		if !ok || got != tt.want+"\n" {
+			t.Errorf("%s: patched = %q, %v, want %q", tt.line, got, ok, tt.want)
+		}
+	}
+}
+
+func TestPatchGoMod_SkipsReplace(t *testing.T) {
+	content := `module example.com/app
+
+require golang.org/x/net v0.17.0
+
+replace (
+	golang.org/x/net v0.17.0 => ../net
+)
+
+replace golang.org/x/net v0.16.0 => golang.org/x/net v0.17.0
+`
+	got, ok := patchGoMod("", content, Upgrade{Package: "golang.org/x/net", To: "0.23.0", Method: MethodRequire})
+	if !ok || !strings.Contains(got, "require golang.org/x/net v0.23.0\n") ||
+		!strings.Contains(got, "\tgolang.org/x/net v0.17.0 => ../net\n") ||
+		!strings.Contains(got, "replace golang.org/x/net v0.16.0 => golang.org/x/net v0.17.0\n") {
+		t.Errorf("patched = %s", got)
+	}
+}
+
+func TestPatchDiff_GitApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		original string
+		u        Upgrade
+	}{
+		{"no final newline", "requirements.txt", "flask\nrequests==2.28.1",
+			Upgrade{Package: "requests", Ecosystem: "PyPI", To: "2.31.0", Method: MethodBump}},
+		{"newline added by a pin", "requirements.txt", "requests==2.28.1",
+			Upgrade{Package: "urllib3", Ecosystem: "PyPI", To: "1.26.18", Method: MethodPin, Fixes: []string{"PYSEC-1"}}},
+		{"newline added by a require", "go.mod", "module example.com/app\n\ngo 1.22",
+			Upgrade{Package: "golang.org/x/net", Ecosystem: "Go", To: "0.23.0", Method: MethodRequire}},
+		{"final newline kept", "requirements.txt", "requests==2.28.1\nflask\n",
+			Upgrade{Package: "requests", Ecosystem: "PyPI", To: "2.31.0", Method: MethodBump}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.original), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			patches, _, err := PatchManifests(dir, &Plan{Upgrades: []Upgrade{tt.u}})
+			if err != nil || len(patches) != 1 {
+				t.Fatalf("PatchManifests() = %+v, %v", patches, err)
+			}
+			diffPath := filepath.Join(t.TempDir(), "fix.patch")
+			if err := os.WriteFile(diffPath, []byte(patches[0].Diff()), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, args := range [][]string{{"apply", "--check", diffPath}, {"apply", diffPath}} {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = dir
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git %s: %v: %s\n%s", strings.Join(args, " "), err, out, patches[0].Diff())
+				}
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != patches[0].Patched {
+				t.Errorf("applied diff = %q, want %q", data, patches[0].Patched)
+			}
+		})
+	}
+}
@@ -0,0 +1,670 @@
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpEQ Op = "="
+	OpNE Op = "!="
+	OpGT Op = ">"
+	OpGE Op = ">="
+	OpLT Op = "<"
+	OpLE Op = "<="
+)
+
+// Comparator compares a version against a bound. With OpEQ and OpNE a
+// bound ending in ".*" matches by prefix (PEP 440 "==1.2.*").
+type Comparator struct {
+	Op      Op     `json:"op"`
+	Version string `json:"version"`
+}
+
+// Constraint is a version requirement: a version satisfies it when it
+// satisfies every comparator of any one set. No sets means nothing
+// matches; an empty set matches everything.
+type Constraint struct {
+	Ecosystem Ecosystem      `json:"ecosystem"`
+	Sets      [][]Comparator `json:"sets"`
+
+	raw string
+	// npm only lets a prerelease satisfy a range that names a prerelease
+	// of the same major.minor.patch
+	npmPrereleases bool
+}
+
+// ParseConstraint parses a version requirement in the ecosystem's syntax:
+//
+//	npm, Packagist, Pub  "^1.2.0 || >=2.0.0 <3", "1.x", "1.0 - 2.0", "~1.2"
+//	Cargo                "^1.2", "~1.2.3", ">=1, <2" (bare versions are caret)
+//	PyPI                 ">=1.0,!=1.3.*,<2", "~=1.4.5"
+//	Maven, NuGet         "[1.0,2.0)", "(,1.0],[1.2,)"
+//	RubyGems, Hex        "~> 2.2", ">= 1.0, < 2"
+//
+// Other ecosystems (including Go) take comparators separated by spaces or
+// commas, with "||" between alternatives.
+func ParseConstraint(eco Ecosystem, s string) (*Constraint, error) {
+	c := &Constraint{Ecosystem: eco, raw: strings.TrimSpace(s)}
+	var err error
+	switch eco {
+	case NPM, Packagist, Pub:
+		c.Sets, err = parseNPMRange(c.raw, eco == Packagist)
+		c.npmPrereleases = eco == NPM
+	case Cargo:
+		c.Sets, err = parseCargoRequirement(c.raw)
+	case PyPI:
+		c.Sets, err = parsePEP440Specifiers(c.raw)
+	case Maven, NuGet:
+		c.Sets, err = parseIntervals(c.raw, eco == NuGet)
+	case RubyGems, Hex:
+		c.Sets, err = parseGemRequirement(c.raw, eco == Hex)
+	default:
+		c.Sets, err = parseComparatorSets(c.raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s version constraint %q: %w", eco, s, err)
+	}
+	return c, nil
+}
+
+// Satisfies reports whether a version satisfies a constraint string
+func Satisfies(eco Ecosystem, version, constraint string) (bool, error) {
+	c, err := ParseConstraint(eco, constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Contains(version), nil
+}
+
+// String returns the constraint as it was written
+func (c *Constraint) String() string {
+	if c.raw != "" || len(c.Sets) == 0 {
+		return c.raw
+	}
+	var sets []string
+	for _, set := range c.Sets {
+		var parts []string
+		for _, cmp := range set {
+			parts = append(parts, string(cmp.Op)+cmp.Version)
+		}
+		sets = append(sets, strings.Join(parts, " "))
+	}
+	return strings.Join(sets, " || ")
+}
+
+// Contains reports whether version satisfies the constraint
+func (c *Constraint) Contains(version string) bool {
+	for _, set := range c.Sets {
+		if c.matchSet(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// MinSatisfying returns the smallest version >= from that satisfies the
+// constraint. Candidates come from available (e.g. a registry's published
+// versions); without them, from itself and the constraint's inclusive lower
+// bounds are considered, so exclusive bounds such as ">1.0" need the list.
+func (c *Constraint) MinSatisfying(from string, available []string) (string, bool) {
+	candidates := available
+	if len(candidates) == 0 {
+		candidates = append(candidates, from)
+		for _, set := range c.Sets {
+			for _, cmp := range set {
+				if (cmp.Op == OpGE || cmp.Op == OpEQ) && !strings.HasSuffix(cmp.Version, ".*") {
+					candidates = append(candidates, cmp.Version)
+				}
+			}
+		}
+	}
+
+	var best string
+	for _, v := range candidates {
+		if v == "" || from != "" && Compare(c.Ecosystem, v, from) < 0 {
+			continue
+		}
+		if !c.Contains(v) {
+			continue
+		}
+		if best == "" || Compare(c.Ecosystem, v, best) < 0 {
+			best = v
+		}
+	}
+	return best, best != ""
+}
+
+func (c *Constraint) matchSet(set []Comparator, version string) bool {
+	for _, cmp := range set {
+		if !c.match(cmp, version) {
+			return false
+		}
+	}
+	if c.npmPrereleases {
+		v, ok := parseSemver(version, false)
+		if ok && len(v.prerelease) > 0 {
+			return hasPrereleaseOf(set, v)
+		}
+	}
+	return true
+}
+
+func (c *Constraint) match(cmp Comparator, version string) bool {
+	if prefix, ok := strings.CutSuffix(cmp.Version, ".*"); ok && (cmp.Op == OpEQ || cmp.Op == OpNE) {
+		return matchesPrefix(c.Ecosystem, version, prefix) == (cmp.Op == OpEQ)
+	}
+	r := Compare(c.Ecosystem, version, cmp.Version)
+	switch cmp.Op {
+	case OpEQ:
+		return r == 0
+	case OpNE:
+		return r != 0
+	case OpGT:
+		return r > 0
+	case OpGE:
+		return r >= 0
+	case OpLT:
+		return r < 0
+	case OpLE:
+		return r <= 0
+	}
+	return false
+}
+
+// hasPrereleaseOf reports whether a comparator names a prerelease of v's
+// major.minor.patch
+func hasPrereleaseOf(set []Comparator, v semver) bool {
+	for _, cmp := range set {
+		b, ok := parseSemver(cmp.Version, false)
+		if !ok || len(b.prerelease) == 0 {
+			continue
+		}
+		if compareSemver(semver{release: b.release}, semver{release: v.release}) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPrefix reports whether version's release starts with prefix's
+// components ("1.2.5" and "1.2" match prefix "1.2")
+func matchesPrefix(eco Ecosystem, version, prefix string) bool {
+	var release []string
+	if eco == PyPI {
+		p, ok := parsePEP440(version)
+		if !ok {
+			return false
+		}
+		release = p.release
+	} else {
+		release = genericTokenPattern.FindAllString(strings.TrimPrefix(version, "v"), -1)
+	}
+	for i, want := range strings.Split(prefix, ".") {
+		got := "0"
+		if i < len(release) {
+			got = release[i]
+		}
+		if isNumeric(got) && isNumeric(want) {
+			if compareNumeric(got, want) != 0 {
+				return false
+			}
+		} else if !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== Comparators ====================
+
+// operators in match order (longest first)
+var operators = []string{"===", "~=", "~>", "==", "!=", ">=", "<=", "=>", "=<", ">", "<", "=", "^", "~"}
+
+// operatorSpace removes blanks between an operator and its version
+var operatorSpace = regexp.MustCompile(`([<>=!~^]+)\s+`)
+
+// splitOperator splits "op version" into its parts
+func splitOperator(term string) (string, string) {
+	term = strings.TrimSpace(term)
+	for _, op := range operators {
+		if rest, ok := strings.CutPrefix(term, op); ok {
+			switch op {
+			case "=>":
+				op = ">="
+			case "=<":
+				op = "<="
+			}
+			return op, strings.TrimSpace(rest)
+		}
+	}
+	return "", term
+}
+
+func comparator(op, version string) (Comparator, error) {
+	if version == "" {
+		return Comparator{}, fmt.Errorf("missing version after %q", op)
+	}
+	switch op {
+	case "", "=", "==":
+		return Comparator{Op: OpEQ, Version: version}, nil
+	case "!=":
+		return Comparator{Op: OpNE, Version: version}, nil
+	case ">", ">=", "<", "<=":
+		return Comparator{Op: Op(op), Version: version}, nil
+	}
+	return Comparator{}, fmt.Errorf("unsupported operator %q", op)
+}
+
+// parseComparatorSets parses plain comparators: "||" separates
+// alternatives, spaces or commas separate comparators that must all hold
+func parseComparatorSets(s string) ([][]Comparator, error) {
+	var sets [][]Comparator
+	for _, alt := range strings.Split(s, "||") {
+		set := []Comparator{}
+		alt = operatorSpace.ReplaceAllString(alt, "$1")
+		for _, term := range strings.FieldsFunc(alt, isListSeparator) {
+			if term == "*" {
+				continue
+			}
+			cmp, err := comparator(splitOperator(term))
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, cmp)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t'
+}
+
+// ==================== npm, Packagist, Pub, Cargo ====================
+
+// partial is a possibly incomplete version such as "1.2" or "1.x"
+type partial struct {
+	parts []int // the numeric components given, up to three
+	pre   string
+}
+
+func parsePartial(v string) (partial, error) {
+	v = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "v"), "V")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, _ := strings.Cut(v, "-")
+	p := partial{pre: pre}
+	if core == "" {
+		return p, nil
+	}
+	for i, s := range strings.Split(core, ".") {
+		if s == "x" || s == "X" || s == "*" {
+			break
+		}
+		if i >= 3 {
+			return p, fmt.Errorf("too many components in %q", v)
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return p, fmt.Errorf("invalid version %q", v)
+		}
+		p.parts = append(p.parts, n)
+	}
+	return p, nil
+}
+
+// version formats the partial padded with zeros, optionally bumping the
+// component at index bump and dropping everything after it
+func (p partial) version(bump int) string {
+	v := [3]int{}
+	copy(v[:], p.parts)
+	if bump >= 0 {
+		v[bump]++
+		for i := bump + 1; i < 3; i++ {
+			v[i] = 0
+		}
+	}
+	s := fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+	if bump < 0 && p.pre != "" && len(p.parts) == 3 {
+		s += "-" + p.pre
+	}
+	return s
+}
+
+// upper is an exclusive upper bound that also excludes the bound's own
+// prereleases ("<2.0.0-0")
+func (p partial) upper(bump int) Comparator {
+	return Comparator{Op: OpLT, Version: p.version(bump) + "-0"}
+}
+
+func (p partial) lower() Comparator {
+	return Comparator{Op: OpGE, Version: p.version(-1)}
+}
+
+// nothing is a comparator no version satisfies
+var nothing = Comparator{Op: OpLT, Version: "0.0.0-0"}
+
+// parseNPMRange parses npm range syntax, which Packagist and Pub share
+// (Composer's "~1.2" allows anything below 2.0)
+func parseNPMRange(s string, composer bool) ([][]Comparator, error) {
+	var sets [][]Comparator
+	separator := "||"
+	if composer && !strings.Contains(s, "||") {
+		separator = "|"
+	}
+	for _, alt := range strings.Split(s, separator) {
+		alt = operatorSpace.ReplaceAllString(strings.TrimSpace(alt), "$1")
+		set := []Comparator{}
+
+		// Hyphen range: "1.2 - 2.3"
+		if lo, hi, ok := strings.Cut(alt, " - "); ok {
+			from, err := parsePartial(lo)
+			if err != nil {
+				return nil, err
+			}
+			to, err := parsePartial(hi)
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, from.lower())
+			set = append(set, desugar("<=", to, composer)...)
+			sets = append(sets, set)
+			continue
+		}
+
+		for _, term := range strings.FieldsFunc(alt, isListSeparator) {
+			op, v := splitOperator(term)
+			p, err := parsePartial(v)
+			if err != nil {
+				return nil, err
+			}
+			cmps := desugar(op, p, composer)
+			if cmps == nil {
+				return nil, fmt.Errorf("unsupported operator %q", op)
+			}
+			set = append(set, cmps...)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// desugar expands npm's partial versions, tilde and caret ranges into
+// plain comparators
+func desugar(op string, p partial, composer bool) []Comparator {
+	n := len(p.parts)
+	switch op {
+	case "", "=", "==":
+		if n == 3 {
+			return []Comparator{{Op: OpEQ, Version: p.version(-1)}}
+		}
+		if n == 0 {
+			return []Comparator{}
+		}
+		return []Comparator{p.lower(), p.upper(n - 1)}
+	case "!=":
+		return []Comparator{{Op: OpNE, Version: p.version(-1)}}
+	case ">":
+		switch n {
+		case 0:
+			return []Comparator{nothing}
+		case 3:
+			return []Comparator{{Op: OpGT, Version: p.version(-1)}}
+		}
+		return []Comparator{{Op: OpGE, Version: p.version(n - 1)}}
+	case ">=":
+		if n == 0 {
+			return []Comparator{}
+		}
+		return []Comparator{p.lower()}
+	case "<":
+		switch n {
+		case 0:
+			return []Comparator{nothing}
+		case 3:
+			return []Comparator{{Op: OpLT, Version: p.version(-1)}}
+		}
+		return []Comparator{{Op: OpLT, Version: p.version(-1) + "-0"}}
+	case "<=":
+		switch n {
+		case 0:
+			return []Comparator{}
+		case 3:
+			return []Comparator{{Op: OpLE, Version: p.version(-1)}}
+		}
+		return []Comparator{p.upper(n - 1)}
+	case "~", "~>":
+		switch {
+		case n == 0:
+			return []Comparator{}
+		case n == 1:
+			return []Comparator{p.lower(), p.upper(0)}
+		case composer:
+			return []Comparator{p.lower(), p.upper(n - 2)}
+		}
+		return []Comparator{p.lower(), p.upper(1)}
+	case "^":
+		if n == 0 {
+			return []Comparator{}
+		}
+		bump := 0
+		for bump < n-1 && p.parts[bump] == 0 {
+			bump++
+		}
+		return []Comparator{p.lower(), p.upper(bump)}
+	}
+	return nil
+}
+
+// parseCargoRequirement parses Cargo requirements: comma-separated
+// comparators where a bare version is a caret requirement
+func parseCargoRequirement(s string) ([][]Comparator, error) {
+	set := []Comparator{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		op, v := splitOperator(term)
+		if op == "" {
+			op = "^"
+		}
+		p, err := parsePartial(v)
+		if err != nil {
+			return nil, err
+		}
+		cmps := desugar(op, p, false)
+		if cmps == nil {
+			return nil, fmt.Errorf("unsupported operator %q", op)
+		}
+		set = append(set, cmps...)
+	}
+	return [][]Comparator{set}, nil
+}
+
+// ==================== PEP 440 ====================
+
+// parsePEP440Specifiers parses a PEP 440 specifier set such as
+// ">=1.0,!=1.3.*,<2"
+func parsePEP440Specifiers(s string) ([][]Comparator, error) {
+	set := []Comparator{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		op, v := splitOperator(term)
+		switch op {
+		case "~=":
+			// Compatible release: ~=1.4.5 is >=1.4.5, ==1.4.*
+			p, ok := parsePEP440(v)
+			if !ok || len(p.release) < 2 {
+				return nil, fmt.Errorf("invalid compatible release %q", v)
+			}
+			prefix := strings.Join(p.release[:len(p.release)-1], ".")
+			if p.epoch != "0" {
+				prefix = p.epoch + "!" + prefix
+			}
+			set = append(set, Comparator{Op: OpGE, Version: v}, Comparator{Op: OpEQ, Version: prefix + ".*"})
+		case "===":
+			set = append(set, Comparator{Op: OpEQ, Version: v})
+		case "==", "!=", ">", ">=", "<", "<=":
+			p, ok := parsePEP440(strings.TrimSuffix(v, ".*"))
+			if !ok {
+				return nil, fmt.Errorf("invalid version %q", v)
+			}
+			cmp, err := comparator(op, v)
+			if err != nil {
+				return nil, err
+			}
+			// An exclusive <V does not match prereleases of V unless V is
+			// one: bound below V's earliest dev release instead
+			if op == "<" && !p.hasPre && !p.hasPost && !p.hasDev && p.local == nil && !strings.HasSuffix(v, ".*") {
+				cmp.Version = v + ".dev0"
+			}
+			set = append(set, cmp)
+		default:
+			return nil, fmt.Errorf("unsupported specifier %q", term)
+		}
+	}
+	return [][]Comparator{set}, nil
+}
+
+// ==================== Maven, NuGet ====================
+
+// parseIntervals parses interval notation: "[1.0,2.0)" and unions such as
+// "(,1.0],[1.2,)". A bare version is a soft requirement, which Maven
+// resolves to that version and NuGet treats as a minimum.
+func parseIntervals(s string, bareIsMinimum bool) ([][]Comparator, error) {
+	if s == "" {
+		return [][]Comparator{{}}, nil
+	}
+	if !strings.ContainsAny(s, "[(") {
+		op := OpEQ
+		if bareIsMinimum {
+			op = OpGE
+		}
+		return [][]Comparator{{{Op: op, Version: s}}}, nil
+	}
+
+	var sets [][]Comparator
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		rest = strings.TrimLeft(rest, ", ")
+		if rest == "" {
+			break
+		}
+		open := rest[0]
+		if open != '[' && open != '(' {
+			return nil, fmt.Errorf("expected '[' or '(' at %q", rest)
+		}
+		end := strings.IndexAny(rest, "])")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated range %q", rest)
+		}
+		closing := rest[end]
+		body := rest[1:end]
+		rest = rest[end+1:]
+
+		lo, hi, isRange := strings.Cut(body, ",")
+		lo, hi = strings.TrimSpace(lo), strings.TrimSpace(hi)
+		if !isRange {
+			// [1.0] pins an exact version
+			if open != '[' || closing != ']' || lo == "" {
+				return nil, fmt.Errorf("invalid exact version range %q", body)
+			}
+			sets = append(sets, []Comparator{{Op: OpEQ, Version: lo}})
+			continue
+		}
+
+		set := []Comparator{}
+		if lo != "" {
+			op := OpGE
+			if open == '(' {
+				op = OpGT
+			}
+			set = append(set, Comparator{Op: op, Version: lo})
+		}
+		if hi != "" {
+			op := OpLE
+			if closing == ')' {
+				op = OpLT
+			}
+			set = append(set, Comparator{Op: op, Version: hi})
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// ==================== RubyGems, Hex ====================
+
+// parseGemRequirement parses RubyGems requirements (">= 1.0, < 2",
+// "~> 2.2") and Hex's, which join them with "and" / "or"
+func parseGemRequirement(s string, hex bool) ([][]Comparator, error) {
+	var sets [][]Comparator
+	for _, alt := range strings.Split(s, " or ") {
+		set := []Comparator{}
+		alt = strings.ReplaceAll(alt, " and ", ",")
+		for _, term := range strings.Split(alt, ",") {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				continue
+			}
+			op, v := splitOperator(term)
+			if op != "~>" {
+				cmp, err := comparator(op, v)
+				if err != nil {
+					return nil, err
+				}
+				set = append(set, cmp)
+				continue
+			}
+
+			// Pessimistic: ~> 2.2 is >= 2.2, < 3 and ~> 2.2.0 is
+			// >= 2.2.0, < 2.3. The bound's prereleases are excluded too:
+			// RubyGems compares the release part ("3.0.pre" is 3.0), and
+			// Hex bounds with the lowest prerelease as npm does.
+			var segments []string
+			for _, seg := range parseGem(v) {
+				if !isNumeric(seg) {
+					break
+				}
+				segments = append(segments, seg)
+			}
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("invalid version %q", v)
+			}
+			if len(segments) > 1 {
+				segments = segments[:len(segments)-1]
+			}
+			last, err := strconv.Atoi(segments[len(segments)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %q", v)
+			}
+			segments[len(segments)-1] = strconv.Itoa(last + 1)
+			upper := strings.Join(segments, ".")
+			if hex {
+				for i := len(segments); i < 3; i++ {
+					upper += ".0"
+				}
+				upper += "-0"
+			} else {
+				// "A" sorts before every other letter segment and letters
+				// sort before numbers, so 3.A is below all 3.x versions
+				upper += ".A"
+			}
+			set = append(set,
+				Comparator{Op: OpGE, Version: v},
+				Comparator{Op: OpLT, Version: upper})
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
@@ -0,0 +1,116 @@
+package versions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstraintContains(t *testing.T) {
+	tests := []struct {
+		eco        Ecosystem
+		constraint string
+		in         []string
+		out        []string
+	}{
+		{NPM, "^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-beta.1", "1.5.0-rc.1"}},
+		{NPM, "^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{NPM, "^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{NPM, "~1.2", []string{"1.2.0", "1.2.99"}, []string{"1.3.0"}},
+		{NPM, "1.x || >=3.0.0-beta.1 <3.1", []string{"1.0.0", "1.99.0", "3.0.0-beta.2", "3.0.5"}, []string{"2.0.0", "3.1.0", "3.0.1-alpha"}},
+		{NPM, "1.2 - 2.3", []string{"1.2.0", "2.3.99"}, []string{"1.1.9", "2.4.0"}},
+		{NPM, ">= 1.0.0 < 2", []string{"1.5.0"}, []string{"2.0.0"}},
+		{NPM, "*", []string{"0.0.1", "99.0.0"}, nil},
+		{Packagist, "~1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}},
+		{Packagist, "^7.4 | ^8.0", []string{"7.4.1", "8.2.0"}, []string{"7.3.0", "9.0.0"}},
+		{Cargo, "1.2", []string{"1.2.0", "1.9.9"}, []string{"2.0.0", "1.1.0"}},
+		{Cargo, ">=1.2, <1.5", []string{"1.4.9"}, []string{"1.5.0"}},
+		{Cargo, "~1.2.3", []string{"1.2.5"}, []string{"1.3.0"}},
+		{Cargo, "=0.4.1", []string{"0.4.1"}, []string{"0.4.2"}},
+		{PyPI, ">=1.0,!=1.3.*,<2", []string{"1.0", "1.2.9", "1.4"}, []string{"0.9", "1.3", "1.3.2", "2.0"}},
+		{PyPI, "~=1.4.5", []string{"1.4.5", "1.4.9"}, []string{"1.5.0", "1.4.4"}},
+		{PyPI, "~=2.2", []string{"2.2", "2.9"}, []string{"3.0"}},
+		{PyPI, "== 2.31.0", []string{"2.31", "2.31.0"}, []string{"2.31.0.post1"}},
+		{PyPI, "<2", []string{"1.9", "1.9rc1", "1.9.post1"}, []string{"2.0rc1", "2.0a1", "2.0.dev1", "2.0"}},
+		{PyPI, "<2.0", []string{"1.9.9"}, []string{"2.0rc1", "2.0b2.dev1", "2.0"}},
+		{PyPI, "<2.0rc2", []string{"2.0rc1", "2.0a1"}, []string{"2.0rc2", "2.0"}},
+		{PyPI, "<1!2", []string{"1!1.9", "3.0"}, []string{"1!2.0rc1"}},
+		{Maven, "[1.0,2.0)", []string{"1.0", "1.5-SNAPSHOT", "1.9.9"}, []string{"2.0", "0.9"}},
+		{Maven, "(,1.0],[1.2,)", []string{"0.1", "1.0", "1.2", "3.0"}, []string{"1.1"}},
+		{Maven, "[1.5]", []string{"1.5", "1.5.0"}, []string{"1.5.1"}},
+		{NuGet, "6.0.0", []string{"6.0.0", "7.0.0"}, []string{"5.9.0"}},
+		{RubyGems, "~> 2.2", []string{"2.2", "2.9.1"}, []string{"3.0", "2.1"}},
+		{RubyGems, "~> 2.2", []string{"2.2", "2.9.1", "2.9.pre"}, []string{"3.0.pre", "3.a", "3.Alpha", "3.0.0.rc1"}},
+		{RubyGems, "~> 2.2.0", []string{"2.2.5"}, []string{"2.3.0"}},
+		{RubyGems, "~> 2.2.0", []string{"2.2.9.beta"}, []string{"2.3.0.pre", "2.3.a"}},
+		{RubyGems, ">= 1.0, < 2", []string{"1.5"}, []string{"2.0", "2.0.0"}},
+		{Hex, "~> 1.2 or ~> 2.0", []string{"1.9.0", "2.5.0"}, []string{"3.0.0"}},
+		{Hex, "~> 2.2", []string{"2.9.0"}, []string{"3.0.0-rc.1", "3.0.0"}},
+		{Go, ">=v1.2.0 <v1.4.0", []string{"v1.3.0", "v1.3.0-0.20210101000000-abcdefabcdef"}, []string{"v1.4.0", "v1.1.9"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.eco, tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%s, %q) error = %v", tt.eco, tt.constraint, err)
+			continue
+		}
+		for _, v := range tt.in {
+			if !c.Contains(v) {
+				t.Errorf("%s %q should contain %s (sets %v)", tt.eco, tt.constraint, v, c.Sets)
+			}
+		}
+		for _, v := range tt.out {
+			if c.Contains(v) {
+				t.Errorf("%s %q should not contain %s (sets %v)", tt.eco, tt.constraint, v, c.Sets)
+			}
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	tests := []struct {
+		eco        Ecosystem
+		constraint string
+	}{
+		{NPM, "^latest"},
+		{PyPI, "~=1"},
+		{PyPI, "foo"},
+		{Maven, "[1.0,2.0"},
+		{RubyGems, "~> abc"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseConstraint(tt.eco, tt.constraint); err == nil {
+			t.Errorf("ParseConstraint(%s, %q) should fail", tt.eco, tt.constraint)
+		} else if !strings.Contains(err.Error(), tt.constraint) {
+			t.Errorf("error %q does not name the constraint", err)
+		}
+	}
+}
+
+func TestMinSatisfying(t *testing.T) {
+	c, err := ParseConstraint(PyPI, ">=2.31.0,<3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.MinSatisfying("2.28.0", nil); !ok || got != "2.31.0" {
+		t.Errorf("MinSatisfying() = %q, %v", got, ok)
+	}
+	if got, _ := c.MinSatisfying("2.32.1", nil); got != "2.32.1" {
+		t.Errorf("MinSatisfying() from a satisfying version = %q", got)
+	}
+
+	c, err = ParseConstraint(NPM, ">4.17.20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.MinSatisfying("4.17.0", nil); ok {
+		t.Error("exclusive bound without available versions should not resolve")
+	}
+	available := []string{"4.17.19", "4.17.21", "4.17.20", "5.0.0-beta.1", "4.18.0"}
+	if got, _ := c.MinSatisfying("4.17.0", available); got != "4.17.21" {
+		t.Errorf("MinSatisfying() with available versions = %q", got)
+	}
+
+	if ok, err := Satisfies(Maven, "2.17.1", "[2.17.1,)"); err != nil || !ok {
+		t.Errorf("Satisfies() = %v, %v", ok, err)
+	}
+}
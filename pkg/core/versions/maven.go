@@ -0,0 +1,115 @@
+package versions
+
+import (
+	"cmp"
+	"strings"
+)
+
+// mavenQualifiers ranks the well-known Maven qualifiers as in Maven's
+// ComparableVersion; "" is a release (also spelled ga, final or release).
+// Unknown qualifiers sort after all of these, lexically.
+var mavenQualifiers = map[string]int{
+	"alpha":     0,
+	"beta":      1,
+	"milestone": 2,
+	"rc":        3,
+	"snapshot":  4,
+	"":          5,
+	"sp":        6,
+}
+
+var mavenQualifierAliases = map[string]string{
+	"a":       "alpha",
+	"b":       "beta",
+	"m":       "milestone",
+	"cr":      "rc",
+	"ga":      "",
+	"final":   "",
+	"release": "",
+}
+
+// parseMaven splits a Maven version into items at ".", "-" and at
+// transitions between digits and letters, e.g. "1.0-rc1" -> 1, 0, rc, 1
+func parseMaven(v string) []string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	var items []string
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			item := v[start:end]
+			if alias, ok := mavenQualifierAliases[item]; ok {
+				item = alias
+			}
+			items = append(items, item)
+		}
+	}
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case c == '.' || c == '-' || c == '_':
+			flush(i)
+			start = i + 1
+		case i > start && isDigit(c) != isDigit(v[i-1]):
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(v))
+	return items
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// compareMaven compares item lists; a missing item counts as 0 against a
+// number and as a release against a qualifier, so "1.0" == "1.0.0" ==
+// "1.0-ga" and "1.0-alpha" < "1.0" < "1.0-sp". Numbers sort after
+// qualifiers.
+func compareMaven(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y string
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x == "" && isNumeric(y) {
+			x = "0"
+		}
+		if y == "" && isNumeric(x) {
+			y = "0"
+		}
+
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		var c int
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = 1
+		case yNum:
+			c = -1
+		default:
+			c = compareMavenQualifier(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareMavenQualifier(a, b string) int {
+	ra, knownA := mavenQualifiers[a]
+	rb, knownB := mavenQualifiers[b]
+	switch {
+	case knownA && knownB:
+		return cmp.Compare(ra, rb)
+	case knownA:
+		return -1
+	case knownB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
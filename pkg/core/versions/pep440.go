@@ -0,0 +1,157 @@
+package versions
+
+import (
+	"cmp"
+	"regexp"
+	"strings"
+)
+
+// pep440Pattern is the PEP 440 version grammar, including the alternative
+// spellings the specification normalizes ("1.0-alpha.1", "1.0.post-1", ...)
+var pep440Pattern = regexp.MustCompile(`(?i)^v?(?:([0-9]+)!)?([0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?` +
+	`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440 is a parsed PEP 440 version. Number fields hold digit strings;
+// the has* flags record which optional segments are present.
+type pep440 struct {
+	epoch   string
+	release []string
+	preKind int // 0 = a, 1 = b, 2 = rc
+	pre     string
+	post    string
+	dev     string
+	local   []string
+
+	hasPre, hasPost, hasDev bool
+}
+
+func parsePEP440(v string) (pep440, bool) {
+	m := pep440Pattern.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return pep440{}, false
+	}
+
+	p := pep440{
+		epoch:   orZero(m[1]),
+		release: strings.Split(m[2], "."),
+	}
+	if m[3] != "" {
+		p.hasPre = true
+		p.pre = orZero(m[4])
+		switch strings.ToLower(m[3]) {
+		case "a", "alpha":
+			p.preKind = 0
+		case "b", "beta":
+			p.preKind = 1
+		default:
+			p.preKind = 2
+		}
+	}
+	if m[5] != "" || m[6] != "" {
+		p.hasPost = true
+		p.post = orZero(m[5] + m[7])
+	}
+	if m[8] != "" {
+		p.hasDev = true
+		p.dev = orZero(m[9])
+	}
+	if m[10] != "" {
+		p.local = strings.FieldsFunc(strings.ToLower(m[10]), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+	return p, true
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// comparePEP440 implements the PEP 440 ordering: epoch, release, then
+// dev-only releases < pre-releases < final < post-releases, with a .devN
+// sorting before the segment it is attached to and local versions after
+// the public version they extend
+func comparePEP440(a, b pep440) int {
+	if c := compareNumeric(a.epoch, b.epoch); c != 0 {
+		return c
+	}
+	if c := compareTokens(a.release, b.release); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(a.preRank(), b.preRank()); c != 0 {
+		return c
+	}
+	if a.hasPre && b.hasPre {
+		if c := compareNumeric(a.pre, b.pre); c != 0 {
+			return c
+		}
+	}
+
+	if c := compareOptional(a.hasPost, a.post, b.hasPost, b.post, -1); c != 0 {
+		return c
+	}
+	if c := compareOptional(a.hasDev, a.dev, b.hasDev, b.dev, 1); c != 0 {
+		return c
+	}
+
+	switch {
+	case a.local == nil && b.local == nil:
+		return 0
+	case a.local == nil:
+		return -1
+	case b.local == nil:
+		return 1
+	}
+	for i := 0; i < len(a.local) && i < len(b.local); i++ {
+		x, y := a.local[i], b.local[i]
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		var c int
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = 1
+		case yNum:
+			c = -1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a.local), len(b.local))
+}
+
+// preRank orders the pre-release segment: a dev-only release sorts before
+// any pre-release, and a version without a pre-release after all of them
+func (p pep440) preRank() int {
+	switch {
+	case !p.hasPre && !p.hasPost && p.hasDev:
+		return -1
+	case !p.hasPre:
+		return 3
+	}
+	return p.preKind
+}
+
+// compareOptional compares optional numeric segments; absent sorts as
+// missing (-1 = before any value, 1 = after any value)
+func compareOptional(hasA bool, a string, hasB bool, b string, missing int) int {
+	switch {
+	case !hasA && !hasB:
+		return 0
+	case !hasA:
+		return missing
+	case !hasB:
+		return -missing
+	}
+	return compareNumeric(a, b)
+}
@@ -0,0 +1,20 @@
+package versions
+
+import (
+	"strings"
+)
+
+// parseGem splits a RubyGems version into numeric and letter segments as
+// Gem::Version does, e.g. "1.0.0.rc1" -> 1, 0, 0, rc, 1. A "-" marks a
+// prerelease ("1.0-1" is "1.0.pre.1").
+func parseGem(v string) []string {
+	v = strings.ReplaceAll(strings.TrimSpace(v), "-", ".pre.")
+	return genericTokenPattern.FindAllString(v, -1)
+}
+
+// compareGem implements Gem::Version#<=>: segments compare pairwise with
+// missing segments as 0, and any letter segment (a prerelease) sorts
+// before a number, so "1.0.a" < "1.0" < "1.0.1"
+func compareGem(a, b []string) int {
+	return compareTokens(a, b)
+}
@@ -0,0 +1,94 @@
+package versions
+
+import (
+	"cmp"
+	"strings"
+)
+
+// semver is a SemVer 2.0 version. The release may have any number of
+// numeric components so NuGet's four-part versions and npm's loose "1.2"
+// parse too; missing components compare as zero.
+type semver struct {
+	release    []string
+	prerelease []string
+}
+
+// parseSemver parses a SemVer version, accepting a leading "v" or "=" and
+// ignoring build metadata (including Go's "+incompatible"). Go
+// pseudo-versions such as v0.0.0-20191109021931-daa7c04131f5 are ordinary
+// prereleases and sort by their timestamp.
+func parseSemver(v string, foldCase bool) (semver, bool) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimLeft(v, "=")
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+	v, _, _ = strings.Cut(v, "+")
+	if foldCase {
+		v = strings.ToLower(v)
+	}
+
+	core, pre, hasPre := strings.Cut(v, "-")
+	var sv semver
+	for _, part := range strings.Split(core, ".") {
+		if !isNumeric(part) {
+			return semver{}, false
+		}
+		sv.release = append(sv.release, part)
+	}
+	if hasPre {
+		if pre == "" {
+			return semver{}, false
+		}
+		sv.prerelease = strings.Split(pre, ".")
+		for _, id := range sv.prerelease {
+			if id == "" {
+				return semver{}, false
+			}
+		}
+	}
+	return sv, true
+}
+
+func compareSemver(a, b semver) int {
+	for i := 0; i < len(a.release) || i < len(b.release); i++ {
+		x, y := "0", "0"
+		if i < len(a.release) {
+			x = a.release[i]
+		}
+		if i < len(b.release) {
+			y = b.release[i]
+		}
+		if c := compareNumeric(x, y); c != 0 {
+			return c
+		}
+	}
+
+	// A release sorts after all of its prereleases
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, y := a.prerelease[i], b.prerelease[i]
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		var c int
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = -1
+		case yNum:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a.prerelease), len(b.prerelease))
+}
@@ -0,0 +1,213 @@
+// Package versions compares package versions using each ecosystem's own
+// ordering rules (SemVer, PEP 440, Maven, RubyGems, ...)
+package versions
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Ecosystem identifies a package ecosystem's versioning scheme
+type Ecosystem string
+
+const (
+	NPM       Ecosystem = "npm"
+	PyPI      Ecosystem = "pypi"
+	Go        Ecosystem = "go"
+	Maven     Ecosystem = "maven"
+	Cargo     Ecosystem = "cargo"
+	RubyGems  Ecosystem = "rubygems"
+	NuGet     Ecosystem = "nuget"
+	Packagist Ecosystem = "packagist"
+	Hex       Ecosystem = "hex"
+	Pub       Ecosystem = "pub"
+	SemVer    Ecosystem = "semver" // Plain SemVer 2.0, e.g. OSV SEMVER ranges
+)
+
+// ParseEcosystem maps the ecosystem names used by OSV ("PyPI",
+// "crates.io"), purl types ("pypi", "cargo", "golang", "gem") and lockfile
+// parsers to an Ecosystem. OSV suffixes such as "Debian:11" are dropped.
+// Unknown names are returned lower-cased and compare generically.
+func ParseEcosystem(name string) Ecosystem {
+	name, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(name)), ":")
+	switch name {
+	case "npm", "node":
+		return NPM
+	case "pypi", "python", "pip":
+		return PyPI
+	case "go", "golang":
+		return Go
+	case "maven", "gradle":
+		return Maven
+	case "cargo", "crates.io", "rust":
+		return Cargo
+	case "rubygems", "gem", "ruby":
+		return RubyGems
+	case "nuget":
+		return NuGet
+	case "packagist", "composer":
+		return Packagist
+	case "hex":
+		return Hex
+	case "pub":
+		return Pub
+	case "semver":
+		return SemVer
+	}
+	return Ecosystem(name)
+}
+
+// Compare compares two versions using the ecosystem's ordering and returns
+// -1, 0 or 1. Versions an ecosystem cannot parse are compared generically
+// (numeric runs numerically, everything else lexically).
+func Compare(eco Ecosystem, a, b string) int {
+	switch eco {
+	case PyPI:
+		if va, ok := parsePEP440(a); ok {
+			if vb, ok := parsePEP440(b); ok {
+				return comparePEP440(va, vb)
+			}
+		}
+	case Maven:
+		return compareMaven(parseMaven(a), parseMaven(b))
+	case RubyGems:
+		return compareGem(parseGem(a), parseGem(b))
+	case NPM, Go, Cargo, NuGet, Packagist, Hex, Pub, SemVer:
+		if va, ok := parseSemver(a, eco == NuGet); ok {
+			if vb, ok := parseSemver(b, eco == NuGet); ok {
+				return compareSemver(va, vb)
+			}
+		}
+	}
+	return compareGeneric(a, b)
+}
+
+// Valid reports whether a version is well-formed for the ecosystem.
+// Ecosystems without a strict grammar accept any non-empty version.
+func Valid(eco Ecosystem, v string) bool {
+	switch eco {
+	case PyPI:
+		_, ok := parsePEP440(v)
+		return ok
+	case NPM, Go, Cargo, NuGet, Hex, Pub, SemVer:
+		_, ok := parseSemver(v, eco == NuGet)
+		return ok
+	}
+	return strings.TrimSpace(v) != ""
+}
+
+// Compatible reports whether upgrading between two versions stays on the
+// same compatibility line: the same major version, or for 0.x versions in
+// caret-style ecosystems (npm, Cargo, Pub, Hex) the same minor version.
+// Go modules change import path at v2, so they compare major versions too.
+func Compatible(eco Ecosystem, from, to string) bool {
+	a, b := releaseNumbers(eco, from), releaseNumbers(eco, to)
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	if compareNumeric(a[0], b[0]) != 0 {
+		return false
+	}
+	switch eco {
+	case NPM, Cargo, Pub, Hex:
+		if strings.TrimLeft(a[0], "0") == "" {
+			return compareNumeric(at(a, 1), at(b, 1)) == 0
+		}
+	}
+	return true
+}
+
+// releaseNumbers returns the numeric release components of a version
+func releaseNumbers(eco Ecosystem, v string) []string {
+	switch eco {
+	case PyPI:
+		if p, ok := parsePEP440(v); ok {
+			return p.release
+		}
+	case NPM, Go, Cargo, NuGet, Packagist, Hex, Pub, SemVer:
+		if sv, ok := parseSemver(v, eco == NuGet); ok {
+			return sv.release
+		}
+	}
+	var numbers []string
+	for _, token := range genericTokenPattern.FindAllString(strings.ToLower(strings.TrimPrefix(v, "v")), -1) {
+		if !isNumeric(token) {
+			break
+		}
+		numbers = append(numbers, token)
+	}
+	return numbers
+}
+
+func at(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+var genericTokenPattern = regexp.MustCompile(`[0-9]+|[A-Za-z]+`)
+
+// compareGeneric orders versions token by token, where tokens are runs of
+// digits (compared numerically) or letters (compared lexically, and sorting
+// before numbers so "1.0rc1" < "1.0" < "1.0.1")
+func compareGeneric(a, b string) int {
+	ta := genericTokenPattern.FindAllString(strings.ToLower(strings.TrimPrefix(a, "v")), -1)
+	tb := genericTokenPattern.FindAllString(strings.ToLower(strings.TrimPrefix(b, "v")), -1)
+	return compareTokens(ta, tb)
+}
+
+// compareTokens compares digit/letter token lists, padding the shorter list
+// with "0" so trailing zero components do not matter
+func compareTokens(ta, tb []string) int {
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		x, y := "0", "0"
+		if i < len(ta) {
+			x = ta[i]
+		}
+		if i < len(tb) {
+			y = tb[i]
+		}
+		xNum, yNum := isNumeric(x), isNumeric(y)
+		switch {
+		case xNum && yNum:
+			if c := compareNumeric(x, y); c != 0 {
+				return c
+			}
+		case xNum:
+			return 1
+		case yNum:
+			return -1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumeric compares two digit strings of any length
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
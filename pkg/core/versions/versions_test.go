@@ -0,0 +1,107 @@
+package versions
+
+import "testing"
+
+func TestParseEcosystem(t *testing.T) {
+	tests := map[string]Ecosystem{
+		"PyPI":      PyPI,
+		"pypi":      PyPI,
+		"Go":        Go,
+		"golang":    Go,
+		"crates.io": Cargo,
+		"RubyGems":  RubyGems,
+		"gem":       RubyGems,
+		"Packagist": Packagist,
+		"composer":  Packagist,
+		"NuGet":     NuGet,
+		"Maven":     Maven,
+		"Debian:11": Ecosystem("debian"),
+	}
+	for name, want := range tests {
+		if got := ParseEcosystem(name); got != want {
+			t.Errorf("ParseEcosystem(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestCompare lists versions in ascending order per ecosystem; each pair of
+// neighbours must compare -1, and equal spellings are joined with "=="
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		eco      Ecosystem
+		versions []string
+	}{
+		{NPM, []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0"}},
+		{Go, []string{"v0.0.0-20191109021931-daa7c04131f5", "v0.0.0-20200101000000-aaaaaaaaaaaa", "v0.1.0", "v1.2.3", "v1.2.4-0.20210101000000-abcdefabcdef", "v1.2.4", "v2.0.0+incompatible"}},
+		{PyPI, []string{"1.0.dev0", "1.0a1", "1.0a2.dev1", "1.0a2", "1.0b1", "1.0rc1", "1.0", "1.0+local.1", "1.0.post1.dev1", "1.0.post1", "1.1", "1!0.1"}},
+		{Maven, []string{"1.0-alpha1", "1.0-beta", "1.0-m1", "1.0-rc1", "1.0-SNAPSHOT", "1.0", "1.0-sp1", "1.0.1", "1.1", "32.1.2-jre", "32.1.3-android", "32.1.3-jre"}},
+		{RubyGems, []string{"1.0.a", "1.0.0.rc1", "1.0", "1.0.1", "1.1", "1.10"}},
+		{NuGet, []string{"1.0.0-Beta", "1.0.0", "1.0.0.1", "1.0.1"}},
+	}
+	for _, tt := range tests {
+		for i := 1; i < len(tt.versions); i++ {
+			a, b := tt.versions[i-1], tt.versions[i]
+			if got := Compare(tt.eco, a, b); got != -1 {
+				t.Errorf("%s: Compare(%q, %q) = %d, want -1", tt.eco, a, b, got)
+			}
+			if got := Compare(tt.eco, b, a); got != 1 {
+				t.Errorf("%s: Compare(%q, %q) = %d, want 1", tt.eco, b, a, got)
+			}
+		}
+	}
+
+	equal := []struct {
+		eco  Ecosystem
+		a, b string
+	}{
+		{NPM, "v1.2.3", "1.2.3+build.5"},
+		{Go, "v1.2", "v1.2.0"},
+		{PyPI, "1.0", "1.0.0"},
+		{PyPI, "1.0-alpha.1", "1.0a1"},
+		{PyPI, "1.0-1", "1.0.post1"},
+		{Maven, "1.0", "1.0.0-ga"},
+		{Maven, "1.0-cr1", "1.0-rc-1"},
+		{RubyGems, "1.0", "1.0.0"},
+		{NuGet, "1.0.0-BETA", "1.0.0-beta"},
+		{Ecosystem("debian"), "1.2", "1.2.0"},
+	}
+	for _, tt := range equal {
+		if got := Compare(tt.eco, tt.a, tt.b); got != 0 {
+			t.Errorf("%s: Compare(%q, %q) = %d, want 0", tt.eco, tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	if !Valid(PyPI, "2.31.0.post1") || Valid(PyPI, "not-a-version") {
+		t.Error("PEP 440 validation")
+	}
+	if !Valid(Go, "v0.0.0-20191109021931-daa7c04131f5") || Valid(NPM, "1.x") {
+		t.Error("SemVer validation")
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		eco      Ecosystem
+		from, to string
+		want     bool
+	}{
+		{NPM, "4.17.15", "4.17.21", true},
+		{NPM, "4.17.15", "5.0.0", false},
+		{NPM, "0.2.3", "0.2.9", true},
+		{NPM, "0.2.3", "0.3.0", false},
+		{Go, "v0.1.0", "v0.9.0", true},
+		{Go, "v1.4.0", "v2.0.0", false},
+		{PyPI, "2.28.1", "2.31.0", true},
+		{PyPI, "1.26.18", "2.0.7", false},
+		{Maven, "2.9.10.8", "2.12.7.1", true},
+		{Maven, "1.9", "1.10.0", true},
+		{Maven, "1.9", "2.0", false},
+	}
+	for _, tt := range tests {
+		if got := Compatible(tt.eco, tt.from, tt.to); got != tt.want {
+			t.Errorf("Compatible(%s, %q, %q) = %v, want %v", tt.eco, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -12,6 +13,8 @@ import (
 	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
 )
 
 // Default limits - can be overridden via ServerConfig
@@ -112,7 +115,7 @@ func (s *Server) registerTools() {
 	// get_vulnerabilities tool
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_vulnerabilities",
-		Description: "Get known vulnerabilities (CVEs) for a project's dependencies",
+		Description: "Get known vulnerabilities (CVEs) for a project's dependencies, highest priority (CVSS, EPSS, CISA KEV, reachability, scope) first",
 	}, s.handleGetVulnerabilities)
 
 	// get_malcontent tool
@@ -139,6 +142,12 @@ func (s *Server) registerTools() {
 		Description: "Get license information for a project's dependencies",
 	}, s.handleGetLicenses)
 
+	// why_dependency tool
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "why_dependency",
+		Description: "Explain why a package is in a project: every path from a direct dependency that pulls it in",
+	}, s.handleWhyDependency)
+
 	// get_secrets tool
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_secrets",
@@ -214,6 +223,8 @@ type VulnerabilitiesInput struct {
 	Project string `json:"project"`
 	// Filter by severity (critical/high/medium/low)
 	Severity string `json:"severity,omitempty"`
+	// Return only the N highest-priority vulnerabilities
+	Top int `json:"top,omitempty"`
 }
 
 // MalcontentInput parameters for get_malcontent tool
@@ -226,6 +237,14 @@ type MalcontentInput struct {
 	Limit int `json:"limit,omitempty"`
 }
 
+// WhyDependencyInput parameters for why_dependency tool
+type WhyDependencyInput struct {
+	// Project ID in owner/repo format
+	Project string `json:"project"`
+	// Package URL (e.g. pkg:npm/minimist@0.0.8) or name, optionally with @version
+	Package string `json:"package"`
+}
+
 // AnalysisRawInput parameters for get_analysis_raw tool
 type AnalysisRawInput struct {
 	// Project ID in owner/repo format
@@ -409,6 +428,7 @@ func (s *Server) handleGetVulnerabilities(ctx context.Context, req *mcp.CallTool
 
 	if findings, ok := data["findings"].(map[string]interface{}); ok {
 		if vulns, ok := findings["vulns"].([]interface{}); ok {
+			matching := vulns
 			// Filter by severity if specified
 			if input.Severity != "" {
 				// Validate severity value
@@ -417,34 +437,33 @@ func (s *Server) handleGetVulnerabilities(ctx context.Context, req *mcp.CallTool
 					return nil, TextOutput{}, fmt.Errorf("invalid severity: must be critical, high, medium, or low")
 				}
 
-				var filtered []interface{}
+				matching = nil
 				for _, v := range vulns {
 					if vm, ok := v.(map[string]interface{}); ok {
 						if sev, _ := vm["severity"].(string); strings.EqualFold(sev, input.Severity) {
-							filtered = append(filtered, v)
+							matching = append(matching, v)
 						}
 					}
 				}
-				// Apply limit with warning
-				limited, limitWarning := s.limitFindingsWithWarning(filtered, "vulnerabilities")
-				if limitWarning != nil {
-					result["_findings_warning"] = limitWarning
-					warnings = append(warnings, limitWarning["_warning"].(string))
-				}
-				result["vulnerabilities"] = limited
-				result["count"] = len(limited)
-				result["total_matching"] = len(filtered)
+				result["total_matching"] = len(matching)
 			} else {
-				// Apply limit with warning
-				limited, limitWarning := s.limitFindingsWithWarning(vulns, "vulnerabilities")
-				if limitWarning != nil {
-					result["_findings_warning"] = limitWarning
-					warnings = append(warnings, limitWarning["_warning"].(string))
-				}
-				result["vulnerabilities"] = limited
-				result["count"] = len(limited)
 				result["total"] = len(vulns)
 			}
+
+			// Fix-first order; scans from before prioritization have no rank
+			sortByPriorityRank(matching)
+			if input.Top > 0 && len(matching) > input.Top {
+				matching = matching[:input.Top]
+			}
+
+			// Apply limit with warning
+			limited, limitWarning := s.limitFindingsWithWarning(matching, "vulnerabilities")
+			if limitWarning != nil {
+				result["_findings_warning"] = limitWarning
+				warnings = append(warnings, limitWarning["_warning"].(string))
+			}
+			result["vulnerabilities"] = limited
+			result["count"] = len(limited)
 		} else {
 			result["vulnerabilities"] = []interface{}{}
 			result["count"] = 0
@@ -469,6 +488,22 @@ func (s *Server) handleGetVulnerabilities(ctx context.Context, req *mcp.CallTool
 	return nil, TextOutput{Text: string(output)}, nil
 }
 
+// sortByPriorityRank orders vulnerability findings by priority_rank,
+// keeping unranked findings last in their original order
+func sortByPriorityRank(vulns []interface{}) {
+	rank := func(v interface{}) float64 {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if r, ok := vm["priority_rank"].(float64); ok && r > 0 {
+				return r
+			}
+		}
+		return math.MaxFloat64
+	}
+	sort.SliceStable(vulns, func(i, j int) bool {
+		return rank(vulns[i]) < rank(vulns[j])
+	})
+}
+
 func (s *Server) handleGetMalcontent(ctx context.Context, req *mcp.CallToolRequest, input MalcontentInput) (*mcp.CallToolResult, TextOutput, error) {
 	// v4.0: Malcontent is in code-packages scanner under findings.malcontent
 	data, err := s.readAnalysis(input.Project, "code-packages")
@@ -543,6 +578,41 @@ func (s *Server) handleGetLicenses(ctx context.Context, req *mcp.CallToolRequest
 	return nil, TextOutput{Text: string(output)}, nil
 }
 
+func (s *Server) handleWhyDependency(ctx context.Context, req *mcp.CallToolRequest, input WhyDependencyInput) (*mcp.CallToolResult, TextOutput, error) {
+	if err := validateProjectID(input.Project); err != nil {
+		return nil, TextOutput{}, fmt.Errorf("invalid project: %w", err)
+	}
+	if input.Package == "" {
+		return nil, TextOutput{}, fmt.Errorf("package is required")
+	}
+
+	bom, err := cyclonedx.ReadFile(filepath.Join(s.zeroHome, "repos", input.Project, "analysis", "sbom.cdx.json"))
+	if err != nil {
+		return nil, TextOutput{}, fmt.Errorf("no SBOM for '%s'", input.Project)
+	}
+
+	graph := cyclonedx.NewGraph(bom)
+	matches := []cyclonedx.Introduction{}
+	for _, ref := range graph.Find(input.Package) {
+		matches = append(matches, graph.Explain(ref))
+	}
+
+	result := map[string]interface{}{
+		"project": input.Project,
+		"package": input.Package,
+		"matches": matches,
+	}
+	if !graph.HasDependencies() {
+		result["_note"] = "SBOM has no dependency graph; paths are unavailable"
+	}
+
+	output, _, err := s.safeJSONMarshal(result)
+	if err != nil {
+		return nil, TextOutput{}, err
+	}
+	return nil, TextOutput{Text: string(output)}, nil
+}
+
 func (s *Server) handleGetSecrets(ctx context.Context, req *mcp.CallToolRequest, input ProjectInput) (*mcp.CallToolResult, TextOutput, error) {
 	// v4.0: Secrets are in code-security scanner under findings.secrets
 	data, err := s.readAnalysis(input.Project, "code-security")
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -423,6 +424,38 @@ func TestServer_HandleGetVulnerabilities(t *testing.T) {
 		}
 	})
 
+	t.Run("top by priority", func(t *testing.T) {
+		analysis := `{"findings": {"vulns": [
+			{"id": "CVE-2023-010", "package": "old", "severity": "critical"},
+			{"id": "CVE-2023-011", "package": "lodash", "severity": "critical", "priority_rank": 2},
+			{"id": "CVE-2021-44228", "package": "log4j-core", "severity": "high", "priority_rank": 1, "in_kev": true}
+		]}}`
+		dir := filepath.Join(zeroHome, "repos", "owner1", "ranked", "analysis")
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "code-packages.json"), []byte(analysis), 0644)
+
+		_, output, err := s.handleGetVulnerabilities(ctx, nil, VulnerabilitiesInput{Project: "owner1/ranked", Top: 2})
+		if err != nil {
+			t.Fatalf("handleGetVulnerabilities failed: %v", err)
+		}
+
+		var got struct {
+			Vulnerabilities []struct {
+				ID string `json:"id"`
+			} `json:"vulnerabilities"`
+			Total int `json:"total"`
+		}
+		if err := json.Unmarshal([]byte(output.Text), &got); err != nil {
+			t.Fatalf("invalid output: %v", err)
+		}
+		if len(got.Vulnerabilities) != 2 || got.Vulnerabilities[0].ID != "CVE-2021-44228" || got.Vulnerabilities[1].ID != "CVE-2023-011" {
+			t.Errorf("vulnerabilities = %+v, want the two ranked ones in rank order", got.Vulnerabilities)
+		}
+		if got.Total != 3 {
+			t.Errorf("total = %d, want 3", got.Total)
+		}
+	})
+
 	t.Run("non-existent project", func(t *testing.T) {
 		_, _, err := s.handleGetVulnerabilities(ctx, nil, VulnerabilitiesInput{Project: "nonexistent/repo"})
 		if err == nil {
@@ -855,3 +888,38 @@ func BenchmarkServer_ReadAnalysis(b *testing.B) {
 		s.readAnalysis("owner/repo", "code-packages")
 	}
 }
+
+func TestServer_HandleWhyDependency(t *testing.T) {
+	zeroHome, cleanup := setupTestZeroHome(t)
+	defer cleanup()
+
+	sbom := `{"bomFormat": "CycloneDX", "specVersion": "1.6",
+		"metadata": {"component": {"bom-ref": "app", "name": "app"}},
+		"components": [
+			{"type": "library", "bom-ref": "express", "name": "express", "version": "4.17.0", "purl": "pkg:npm/express@4.17.0"},
+			{"type": "library", "bom-ref": "lodash", "name": "lodash", "version": "4.17.0", "purl": "pkg:npm/lodash@4.17.0"}
+		],
+		"dependencies": [
+			{"ref": "app", "dependsOn": ["express"]},
+			{"ref": "express", "dependsOn": ["lodash"]}
+		]}`
+	os.WriteFile(filepath.Join(zeroHome, "repos", "owner1", "repo1", "analysis", "sbom.cdx.json"), []byte(sbom), 0644)
+
+	s := NewServer(zeroHome)
+	ctx := context.Background()
+
+	_, output, err := s.handleWhyDependency(ctx, nil, WhyDependencyInput{Project: "owner1/repo1", Package: "pkg:npm/lodash@4.17.0"})
+	if err != nil {
+		t.Fatalf("handleWhyDependency failed: %v", err)
+	}
+	if !containsString(output.Text, `"express@4.17.0",`) || !containsString(output.Text, `"lodash@4.17.0"`) {
+		t.Errorf("output missing dependency path: %s", output.Text)
+	}
+
+	if _, _, err := s.handleWhyDependency(ctx, nil, WhyDependencyInput{Project: "owner2/repo1", Package: "lodash"}); err == nil {
+		t.Error("expected error for project without SBOM")
+	}
+	if _, _, err := s.handleWhyDependency(ctx, nil, WhyDependencyInput{Project: "../etc", Package: "lodash"}); err == nil {
+		t.Error("expected error for invalid project")
+	}
+}
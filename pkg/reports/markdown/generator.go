@@ -7,10 +7,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -221,12 +223,16 @@ func (g *Generator) getCategorySummary(category Category) (status string, findin
 	totalCritical := 0
 	totalHigh := 0
 	totalFindings := 0
+	partial := false
 
 	for _, analyzer := range analyzers {
 		data, err := g.loadAnalyzerData(analyzer)
 		if err != nil {
 			continue
 		}
+		if len(incompleteFeatures(data)) > 0 {
+			partial = true
+		}
 
 		// Extract severity counts from summary
 		if summary, ok := data["summary"].(map[string]interface{}); ok {
@@ -271,14 +277,44 @@ func (g *Generator) getCategorySummary(category Category) (status string, findin
 			findings = strings.Join(parts, ", ")
 		}
 	}
+	if partial {
+		status += " (partial)"
+	}
 
 	return status, findings
 }
 
+// incompleteFeatures returns the features of a partial scanner result that
+// did not complete, or nil for a complete result
+func incompleteFeatures(data map[string]interface{}) []string {
+	if partial, _ := data["partial"].(bool); !partial {
+		return nil
+	}
+	var features []string
+	if statuses, ok := data["feature_status"].(map[string]interface{}); ok {
+		for name, st := range statuses {
+			if m, ok := st.(map[string]interface{}); ok && m["status"] != "complete" {
+				features = append(features, name)
+			}
+		}
+	}
+	sort.Strings(features)
+	if len(features) == 0 {
+		features = []string{"some features"}
+	}
+	return features
+}
+
 // writeAnalyzerSection writes a section for an analyzer
 func (g *Generator) writeAnalyzerSection(w io.Writer, analyzer string, data map[string]interface{}) {
 	fmt.Fprintf(w, "\n## %s\n\n", analyzerDisplayName(analyzer))
 
+	// Flag partial results before the numbers they qualify
+	if incomplete := incompleteFeatures(data); len(incomplete) > 0 {
+		fmt.Fprintf(w, "> **Partial results:** %s did not complete; findings from these features are missing.\n\n",
+			strings.Join(incomplete, ", "))
+	}
+
 	// Write summary if available
 	if summary, ok := data["summary"].(map[string]interface{}); ok {
 		g.writeSummarySection(w, summary)
@@ -417,6 +453,58 @@ func (g *Generator) writePackagesSection(w io.Writer, data map[string]interface{
 		fmt.Fprintf(w, "### Vulnerabilities\n\n")
 		fmt.Fprintf(w, "%d vulnerabilities found in dependencies.\n\n", len(vulns))
 	}
+
+	g.writeFixFirstSection(w, data)
+}
+
+// writeFixFirstSection lists the highest-priority package vulnerabilities
+func (g *Generator) writeFixFirstSection(w io.Writer, data map[string]interface{}) {
+	findings, _ := data["findings"].(map[string]interface{})
+	vulns, _ := findings["vulns"].([]interface{})
+
+	var ranked []map[string]interface{}
+	for _, v := range vulns {
+		if vm, ok := v.(map[string]interface{}); ok {
+			if rank, _ := vm["priority_rank"].(float64); rank > 0 {
+				ranked = append(ranked, vm)
+			}
+		}
+	}
+	if len(ranked) == 0 {
+		return
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i]["priority_rank"].(float64) < ranked[j]["priority_rank"].(float64)
+	})
+	if len(ranked) > 10 {
+		ranked = ranked[:10]
+	}
+
+	fmt.Fprintf(w, "### Fix First\n\n")
+	fmt.Fprintf(w, "Ranked by CVSS, exploitation (CISA KEV, EPSS), reachability and dependency scope.\n\n")
+	fmt.Fprintf(w, "| # | Vulnerability | Package | Priority | Why | Fix |\n")
+	fmt.Fprintf(w, "|---|---------------|---------|----------|-----|-----|\n")
+	for _, v := range ranked {
+		id, _ := v["id"].(string)
+		pkg, _ := v["package"].(string)
+		version, _ := v["version"].(string)
+		score, _ := v["priority_score"].(float64)
+		fix, _ := v["fix_version"].(string)
+		if fix == "" {
+			fix = "-"
+		}
+		var factors []string
+		if fs, ok := v["priority_factors"].([]interface{}); ok {
+			for _, f := range fs {
+				if s, ok := f.(string); ok {
+					factors = append(factors, s)
+				}
+			}
+		}
+		fmt.Fprintf(w, "| %.0f | %s | %s@%s | %.1f | %s | %s |\n",
+			v["priority_rank"].(float64), id, pkg, version, score, strings.Join(factors, ", "), fix)
+	}
+	fmt.Fprintf(w, "\n")
 }
 
 // writeSecuritySection writes security-specific sections
@@ -438,6 +526,61 @@ func (g *Generator) writeSecuritySection(w io.Writer, data map[string]interface{
 		}
 		fmt.Fprintf(w, "\n")
 	}
+
+	// Write cryptographic bill of materials
+	g.writeCBOMSection(w)
+}
+
+// writeCBOMSection summarizes the CycloneDX CBOM written by code-security
+func (g *Generator) writeCBOMSection(w io.Writer) {
+	path := filepath.Join(g.analysisPath, "cbom.cdx.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	bom, err := cyclonedx.FromJSON(data)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(w, "### Cryptographic Bill of Materials\n\n")
+	fmt.Fprintf(w, "CycloneDX CBOM: `%s`\n\n", path)
+
+	counts := map[string]int{}
+	var algorithms []string
+	seen := map[string]bool{}
+	for _, c := range bom.Components {
+		if c.CryptoProperties == nil {
+			continue
+		}
+		counts[c.CryptoProperties.AssetType]++
+		if c.CryptoProperties.AssetType == cyclonedx.CryptoAssetAlgorithm && !seen[c.Name] {
+			seen[c.Name] = true
+			algorithms = append(algorithms, c.Name)
+		}
+	}
+
+	fmt.Fprintf(w, "| Asset Type | Count |\n")
+	fmt.Fprintf(w, "|------------|-------|\n")
+	for _, assetType := range []string{
+		cyclonedx.CryptoAssetAlgorithm,
+		cyclonedx.CryptoAssetProtocol,
+		cyclonedx.CryptoAssetCertificate,
+		cyclonedx.CryptoAssetRelatedCryptoMaterial,
+	} {
+		if counts[assetType] > 0 {
+			fmt.Fprintf(w, "| %s | %d |\n", assetType, counts[assetType])
+		}
+	}
+	fmt.Fprintf(w, "| vulnerabilities | %d |\n\n", len(bom.Vulnerabilities))
+
+	if len(algorithms) > 0 {
+		if len(algorithms) > 15 { // Limit to top 15
+			algorithms = append(algorithms[:15], fmt.Sprintf("*%d more*", len(algorithms)-15))
+		}
+		fmt.Fprintf(w, "**Algorithms:** %s\n\n", strings.Join(algorithms, ", "))
+	}
 }
 
 // writeDevOpsSection writes DevOps-specific sections
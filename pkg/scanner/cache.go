@@ -0,0 +1,333 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/scanner/common"
+)
+
+// cacheFormat is bumped when the cache key or entry layout changes
+const cacheFormat = 2
+
+// Versioned is implemented by scanners that report their version.
+// Only versioned scanners are cached, so a scanner upgrade always
+// invalidates its previous results.
+type Versioned interface {
+	Version() string
+}
+
+// ToolUser is implemented by scanners that shell out to external tools.
+// The installed tool versions are part of the result cache key.
+type ToolUser interface {
+	Tools() []string
+}
+
+// FeedUser is implemented by scanners that read local data feeds
+// (vulnerability and exploit databases, package corpora). The state of the
+// feed files is part of the result cache key, so refreshing a feed
+// invalidates results produced from the old data.
+type FeedUser interface {
+	Feeds(opts *ScanOptions) []string
+}
+
+// Configured is implemented by scanners whose behavior depends on
+// configuration outside the feature config, such as a plugin's command and
+// environment. The returned settings are part of the result cache key.
+type Configured interface {
+	CacheConfig() map[string]interface{}
+}
+
+// ArtifactWriter is implemented by scanners that write files to the output
+// directory besides <name>.json (e.g., sbom.cdx.json). Artifacts are cached
+// and restored together with the result.
+type ArtifactWriter interface {
+	Artifacts() []string
+}
+
+// ResultCache stores scanner outputs keyed by commit SHA, the Zero build,
+// scanner name and version, resolved feature config, external tool
+// versions, feed state and the keys of the scanner's dependencies. A hit
+// restores the output files without re-running the scanner.
+type ResultCache struct {
+	Dir      string
+	MaxAge   time.Duration // Entries older than this are ignored (0 = no expiry)
+	ZeroHome string        // Resolves the default feed locations of FeedUser scanners
+
+	toolsMu sync.Mutex
+	tools   map[string]string
+}
+
+// cacheManifest describes a cached scanner result
+type cacheManifest struct {
+	Scanner  string    `json:"scanner"`
+	Commit   string    `json:"commit"`
+	Summary  string    `json:"summary"`
+	Files    []string  `json:"files"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// cacheKeyInput is hashed to produce the cache key. encoding/json sorts map
+// keys, so the encoding is deterministic.
+type cacheKeyInput struct {
+	Format       int                    `json:"format"`
+	Commit       string                 `json:"commit"`
+	Build        string                 `json:"build"`
+	Scanner      string                 `json:"scanner"`
+	Version      string                 `json:"version"`
+	Features     map[string]interface{} `json:"features,omitempty"`
+	Config       map[string]interface{} `json:"config,omitempty"`
+	Tools        map[string]string      `json:"tools,omitempty"`
+	Feeds        map[string]string      `json:"feeds,omitempty"`
+	Dependencies map[string]string      `json:"dependencies,omitempty"`
+}
+
+// NewResultCache creates a result cache rooted at dir
+func NewResultCache(dir string) *ResultCache {
+	return &ResultCache{Dir: dir}
+}
+
+// Key returns the cache key for running s at commit with the given feature
+// config. depKeys holds the cache keys of the scanner's dependencies in this
+// run. Returns "" if the scanner is not cacheable.
+func (c *ResultCache) Key(s Scanner, commit string, features map[string]interface{}, depKeys map[string]string) string {
+	v, ok := s.(Versioned)
+	if !ok || commit == "" {
+		return ""
+	}
+
+	input := cacheKeyInput{
+		Format:   cacheFormat,
+		Commit:   commit,
+		Build:    buildID(),
+		Scanner:  s.Name(),
+		Version:  v.Version(),
+		Features: features,
+	}
+	if cs, ok := s.(Configured); ok {
+		input.Config = cs.CacheConfig()
+	}
+	if tu, ok := s.(ToolUser); ok {
+		input.Tools = make(map[string]string)
+		for _, tool := range tu.Tools() {
+			input.Tools[tool] = c.toolVersion(tool)
+		}
+	}
+	if fu, ok := s.(FeedUser); ok {
+		input.Feeds = make(map[string]string)
+		for _, feed := range fu.Feeds(&ScanOptions{ZeroHome: c.ZeroHome, FeatureConfig: features}) {
+			input.Feeds[feed] = feedState(feed)
+		}
+	}
+	for _, dep := range s.Dependencies() {
+		if key, ok := depKeys[dep]; ok {
+			if input.Dependencies == nil {
+				input.Dependencies = make(map[string]string)
+			}
+			input.Dependencies[dep] = key
+		}
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	buildIDOnce sync.Once
+	buildIDVal  string
+)
+
+// buildID identifies the running Zero binary, so an upgrade invalidates
+// results whose output format or analysis may have changed even when the
+// scanner versions were not bumped. Builds of a clean checkout are named
+// by module version and VCS revision; anything else (modified trees, test
+// binaries) by a hash of the executable.
+func buildID() string {
+	buildIDOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			settings := make(map[string]string)
+			for _, s := range info.Settings {
+				settings[s.Key] = s.Value
+			}
+			if rev := settings["vcs.revision"]; rev != "" && settings["vcs.modified"] != "true" {
+				buildIDVal = info.Main.Version + "@" + rev
+				return
+			}
+		}
+		buildIDVal = "unknown"
+		if exe, err := os.Executable(); err == nil {
+			if f, err := os.Open(exe); err == nil {
+				defer f.Close()
+				h := sha256.New()
+				if _, err := io.Copy(h, f); err == nil {
+					buildIDVal = hex.EncodeToString(h.Sum(nil))
+				}
+			}
+		}
+	})
+	return buildIDVal
+}
+
+// feedState describes a feed file by size and modification time, which
+// change whenever the feed is imported or refreshed. Hashing the contents
+// would mean reading a multi-gigabyte vulnerability database per scan.
+func feedState(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "missing"
+	}
+	return fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())
+}
+
+// toolVersion returns the installed version of an external tool, resolved
+// once per cache. Missing tools get a fixed marker so installing one
+// invalidates results produced without it.
+func (c *ResultCache) toolVersion(name string) string {
+	c.toolsMu.Lock()
+	defer c.toolsMu.Unlock()
+
+	if v, ok := c.tools[name]; ok {
+		return v
+	}
+	if c.tools == nil {
+		c.tools = make(map[string]string)
+	}
+
+	v := "missing"
+	if common.ToolExists(name) {
+		if version, err := common.ToolVersion(name); err == nil {
+			v = version
+		} else {
+			v = "unknown"
+		}
+	}
+	c.tools[name] = v
+	return v
+}
+
+func (c *ResultCache) entryDir(key string) string {
+	return filepath.Join(c.Dir, key[:2], key)
+}
+
+// Restore copies a cached result into outputDir. Returns the cached summary
+// and true on a hit.
+func (c *ResultCache) Restore(key, outputDir string) (string, bool) {
+	dir := c.entryDir(key)
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return "", false
+	}
+	var manifest cacheManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", false
+	}
+	if c.MaxAge > 0 && time.Since(manifest.StoredAt) > c.MaxAge {
+		return "", false
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", false
+	}
+	for _, name := range manifest.Files {
+		if err := copyFile(filepath.Join(dir, name), filepath.Join(outputDir, name)); err != nil {
+			return "", false
+		}
+	}
+	return manifest.Summary, true
+}
+
+// Store saves a scanner's output files from outputDir under key.
+// Failures are ignored; the cache is an optimization.
+func (c *ResultCache) Store(key string, s Scanner, commit, outputDir, summary string) {
+	files := []string{s.Name() + ".json"}
+	if aw, ok := s.(ArtifactWriter); ok {
+		for _, name := range aw.Artifacts() {
+			if _, err := os.Stat(filepath.Join(outputDir, name)); err == nil {
+				files = append(files, name)
+			}
+		}
+	}
+
+	dir := c.entryDir(key)
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return
+	}
+
+	// Build the entry in a temp dir and rename it into place so concurrent
+	// runs never see a partial entry
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, name := range files {
+		if err := copyFile(filepath.Join(outputDir, name), filepath.Join(tmp, name)); err != nil {
+			return
+		}
+	}
+
+	manifest := cacheManifest{
+		Scanner:  s.Name(),
+		Commit:   commit,
+		Summary:  summary,
+		Files:    files,
+		StoredAt: time.Now(),
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "manifest.json"), data, 0600); err != nil {
+		return
+	}
+
+	os.RemoveAll(dir)
+	_ = os.Rename(tmp, dir)
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0600)
+}
+
+// cacheableCommit returns the HEAD commit of repoPath, or "" when results for
+// the working tree cannot be cached (not a git repo, or uncommitted changes)
+func cacheableCommit(repoPath string) string {
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(out))
+
+	status, err := exec.Command("git", "-C", repoPath, "status", "--porcelain").Output()
+	if err != nil || len(strings.TrimSpace(string(status))) > 0 {
+		return ""
+	}
+	return commit
+}
+
+// cachedSummary describes a cache hit for progress output
+func cachedSummary(summary string) string {
+	if summary == "" {
+		return "cached"
+	}
+	return fmt.Sprintf("%s (cached)", summary)
+}
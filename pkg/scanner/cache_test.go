@@ -0,0 +1,222 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingScanner writes <name>.json and an artifact and counts its runs
+type countingScanner struct {
+	name    string
+	deps    []string
+	version string
+	runs    int32
+}
+
+func (s *countingScanner) Name() string                       { return s.name }
+func (s *countingScanner) Description() string                { return "test scanner" }
+func (s *countingScanner) Dependencies() []string             { return s.deps }
+func (s *countingScanner) EstimateDuration(int) time.Duration { return time.Second }
+func (s *countingScanner) Version() string                    { return s.version }
+func (s *countingScanner) Artifacts() []string                { return []string{s.name + ".extra"} }
+
+func (s *countingScanner) Run(ctx context.Context, opts *ScanOptions) (*ScanResult, error) {
+	atomic.AddInt32(&s.runs, 1)
+	result := NewScanResult(s.name, s.version, time.Now())
+	_ = result.SetSummary(map[string]int{"total_findings": 1})
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(opts.OutputDir, s.name+".extra"), []byte("artifact"), 0600); err != nil {
+		return nil, err
+	}
+	return result, result.WriteJSON(filepath.Join(opts.OutputDir, s.name+".json"))
+}
+
+// unversionedScanner does not implement Versioned and is never cached
+type unversionedScanner struct{ countingScanner }
+
+// Version shadows countingScanner.Version with a signature that does not
+// satisfy Versioned
+func (s *unversionedScanner) Version() {}
+
+// initGitRepo creates a repository with one commit
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestRunScanners_ResultCache(t *testing.T) {
+	repo := initGitRepo(t)
+	outputDir := t.TempDir()
+	runner := NewNativeRunner(t.TempDir())
+
+	a := &countingScanner{name: "a", version: "1.0.0"}
+	b := &countingScanner{name: "b", deps: []string{"a"}, version: "1.0.0"}
+	c := &countingScanner{name: "c", version: "1.0.0"}
+
+	run := func(opts RunOptions) *RunResult {
+		t.Helper()
+		opts.RepoPath = repo
+		opts.OutputDir = outputDir
+		opts.Scanners = []Scanner{a, b, c}
+		result, err := runner.RunScanners(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("RunScanners() error = %v", err)
+		}
+		return result
+	}
+	runs := func() [3]int32 { return [3]int32{a.runs, b.runs, c.runs} }
+
+	run(RunOptions{})
+	if runs() != [3]int32{1, 1, 1} {
+		t.Fatalf("first run: runs = %v", runs())
+	}
+
+	// Unchanged commit and config: everything is restored from the cache
+	os.RemoveAll(outputDir)
+	result := run(RunOptions{})
+	if runs() != [3]int32{1, 1, 1} {
+		t.Errorf("cached run re-ran scanners: runs = %v", runs())
+	}
+	if !result.Results["a"].Cached || result.Results["a"].Status != StatusComplete {
+		t.Errorf("result a = %+v, want cached complete", result.Results["a"])
+	}
+	for _, name := range []string{"a.json", "a.extra", "c.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("%s not restored: %v", name, err)
+		}
+	}
+
+	// Changing c's config only re-runs c
+	run(RunOptions{FeatureConfigs: map[string]map[string]interface{}{"c": {"deep": true}}})
+	if runs() != [3]int32{1, 1, 2} {
+		t.Errorf("config change for c: runs = %v, want [1 1 2]", runs())
+	}
+
+	// Upgrading a re-runs a and its dependent b
+	a.version = "1.1.0"
+	run(RunOptions{FeatureConfigs: map[string]map[string]interface{}{"c": {"deep": true}}})
+	if runs() != [3]int32{2, 2, 2} {
+		t.Errorf("version change for a: runs = %v, want [2 2 2]", runs())
+	}
+
+	// NoCache re-runs everything
+	run(RunOptions{NoCache: true})
+	if runs() != [3]int32{3, 3, 3} {
+		t.Errorf("NoCache: runs = %v, want [3 3 3]", runs())
+	}
+}
+
+func TestRunScanners_CacheSkipsDirtyTree(t *testing.T) {
+	repo := initGitRepo(t)
+	runner := NewNativeRunner(t.TempDir())
+	s := &countingScanner{name: "a", version: "1.0.0"}
+
+	if err := os.WriteFile(filepath.Join(repo, "untracked.txt"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := runner.RunScanners(context.Background(), RunOptions{
+			RepoPath:  repo,
+			OutputDir: t.TempDir(),
+			Scanners:  []Scanner{s},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.runs != 2 {
+		t.Errorf("dirty tree should not be cached, runs = %d", s.runs)
+	}
+}
+
+func TestRunScanners_CacheExpiry(t *testing.T) {
+	repo := initGitRepo(t)
+	runner := NewNativeRunner(t.TempDir())
+	s := &countingScanner{name: "a", version: "1.0.0"}
+	opts := RunOptions{RepoPath: repo, OutputDir: t.TempDir(), Scanners: []Scanner{s}}
+
+	if _, err := runner.RunScanners(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	runner.Cache.MaxAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, err := runner.RunScanners(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if s.runs != 2 {
+		t.Errorf("expired entry should be ignored, runs = %d", s.runs)
+	}
+}
+
+func TestResultCache_Key(t *testing.T) {
+	cache := NewResultCache(t.TempDir())
+	s := &countingScanner{name: "a", version: "1.0.0"}
+
+	base := cache.Key(s, "abc", map[string]interface{}{"x": 1, "y": map[string]interface{}{"z": true}}, nil)
+	if base == "" {
+		t.Fatal("versioned scanner should have a key")
+	}
+	if got := cache.Key(s, "abc", map[string]interface{}{"y": map[string]interface{}{"z": true}, "x": 1}, nil); got != base {
+		t.Error("key should not depend on map ordering")
+	}
+	if cache.Key(s, "def", map[string]interface{}{"x": 1, "y": map[string]interface{}{"z": true}}, nil) == base {
+		t.Error("key should change with the commit")
+	}
+	if cache.Key(s, "", nil, nil) != "" {
+		t.Error("no commit should mean no key")
+	}
+	if cache.Key(&unversionedScanner{countingScanner{name: "u"}}, "abc", nil, nil) != "" {
+		t.Error("unversioned scanner should not be cached")
+	}
+}
+
+// feedScanner reads a local feed file
+type feedScanner struct {
+	countingScanner
+	feed string
+}
+
+func (s *feedScanner) Feeds(*ScanOptions) []string { return []string{s.feed} }
+
+func TestResultCache_KeyFeeds(t *testing.T) {
+	cache := NewResultCache(t.TempDir())
+	feed := filepath.Join(t.TempDir(), "osv.db")
+	s := &feedScanner{countingScanner{name: "a", version: "1.0.0"}, feed}
+
+	missing := cache.Key(s, "abc", nil, nil)
+	if err := os.WriteFile(feed, []byte("v1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	imported := cache.Key(s, "abc", nil, nil)
+	if imported == missing {
+		t.Error("key should change when a feed is imported")
+	}
+	if err := os.WriteFile(feed, []byte("v2.."), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Key(s, "abc", nil, nil) == imported {
+		t.Error("key should change when a feed is refreshed")
+	}
+	if buildID() == "" {
+		t.Error("build should be identified")
+	}
+}
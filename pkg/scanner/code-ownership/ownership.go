@@ -16,6 +16,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	gitobj "github.com/go-git/go-git/v5/plumbing/object"
 
+	"github.com/crashappsec/zero/pkg/core/forge"
 	"github.com/crashappsec/zero/pkg/core/github"
 	"github.com/crashappsec/zero/pkg/core/languages"
 	"github.com/crashappsec/zero/pkg/scanner"
@@ -24,6 +25,7 @@ import (
 const (
 	Name        = "code-ownership"
 	Description = "Code ownership and CODEOWNERS analysis"
+	Version     = "2.0.0"
 )
 
 // OwnershipScanner implements the code ownership super scanner
@@ -53,6 +55,16 @@ func (s *OwnershipScanner) Dependencies() []string {
 	return []string{}
 }
 
+// Version returns the scanner version
+func (s *OwnershipScanner) Version() string {
+	return Version
+}
+
+// Artifacts returns files written next to the scanner result
+func (s *OwnershipScanner) Artifacts() []string {
+	return []string{"languages.json"}
+}
+
 // EstimateDuration returns estimated scan duration based on repo size
 func (s *OwnershipScanner) EstimateDuration(fileCount int) time.Duration {
 	// Git log analysis can be slow for large repos
@@ -709,9 +721,9 @@ func (s *OwnershipScanner) RunEnhancedAnalysis(
 	findings := &Findings{}
 	summary := &Summary{}
 
-	// Check for GitHub token
-	ghClient := github.NewOwnershipClient(enhancedCfg.GitHub.MaxPRs)
-	summary.GitHubTokenPresent = ghClient.HasToken()
+	// Check for a forge token (GitHub, GitLab or Gitea, based on the origin remote)
+	repoForge, fullName := resolveForge(opts.RepoPath)
+	summary.GitHubTokenPresent = repoForge.HasToken()
 
 	if !summary.GitHubTokenPresent && enhancedCfg.GitHub.Enabled {
 		summary.Warnings = append(summary.Warnings, GitHubTokenMessage)
@@ -820,12 +832,10 @@ func (s *OwnershipScanner) RunEnhancedAnalysis(
 		}
 	}
 
-	// Fetch PR review data if GitHub token available
+	// Fetch PR review data if a forge token is available
 	if summary.GitHubTokenPresent && enhancedCfg.GitHub.FetchPRReviews {
-		// Extract owner/repo from path or git remote
-		owner, repoName := extractRepoInfo(opts.RepoPath)
-		if owner != "" && repoName != "" {
-			prs, totalPRs, err := ghClient.FetchPRReviews(owner, repoName)
+		if fullName != "" {
+			prs, totalPRs, err := fetchPRReviews(ctx, repoForge, fullName, enhancedCfg.GitHub.MaxPRs)
 			if err != nil {
 				summary.Warnings = append(summary.Warnings, fmt.Sprintf("PR analysis error: %v", err))
 			} else if prs == nil && totalPRs > enhancedCfg.GitHub.MaxPRs {
@@ -887,28 +897,68 @@ func (s *OwnershipScanner) RunEnhancedAnalysis(
 	return findings, summary, nil
 }
 
-// extractRepoInfo attempts to get owner/repo from git remote
-func extractRepoInfo(repoPath string) (owner, repo string) {
+// resolveForge returns the forge hosting the repo's origin remote and the
+// repository path on it. Falls back to GitHub with an empty path when the
+// remote is missing or on an unknown host.
+func resolveForge(repoPath string) (forge.Forge, string) {
+	if remote := originURL(repoPath); remote != "" {
+		if f, fullName, err := forge.ForRemote(remote); err == nil {
+			return f, fullName
+		}
+	}
+	f, _ := forge.Open(forge.KindGitHub, "")
+	return f, ""
+}
+
+// fetchPRReviews fetches merged PRs with reviews. Returns nil PRs with the
+// total count when the repo has more than maxPRs merged PRs.
+func fetchPRReviews(ctx context.Context, f forge.Forge, fullName string, maxPRs int) ([]github.PRReviewData, int, error) {
+	totalPRs, err := f.CountMergedPullRequests(ctx, fullName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// If too many PRs, return early with warning
+	if totalPRs > maxPRs {
+		return nil, totalPRs, nil
+	}
+
+	prs, err := f.ListMergedPullRequests(ctx, fullName, forge.PullRequestOptions{Limit: maxPRs})
+	if err != nil {
+		return nil, 0, err
+	}
+	return forge.ReviewData(prs), totalPRs, nil
+}
+
+// originURL returns the URL of the origin remote, or "" if there is none
+func originURL(repoPath string) string {
 	r, err := git.PlainOpen(repoPath)
 	if err != nil {
-		return "", ""
+		return ""
 	}
 
 	remotes, err := r.Remotes()
 	if err != nil || len(remotes) == 0 {
-		return "", ""
+		return ""
 	}
 
-	// Get origin remote URL
 	for _, remote := range remotes {
 		if remote.Config().Name == "origin" {
 			urls := remote.Config().URLs
 			if len(urls) > 0 {
-				return parseGitURL(urls[0])
+				return urls[0]
 			}
 		}
 	}
 
+	return ""
+}
+
+// extractRepoInfo attempts to get owner/repo from git remote
+func extractRepoInfo(repoPath string) (owner, repo string) {
+	if remote := originURL(repoPath); remote != "" {
+		return parseGitURL(remote)
+	}
 	return "", ""
 }
 
@@ -1009,7 +1059,7 @@ func (s *OwnershipScanner) runEnhancedMode(ctx context.Context, opts *scanner.Sc
 	}
 
 	// Create scan result
-	scanResult := scanner.NewScanResult(Name, "2.0.0", startTime)
+	scanResult := scanner.NewScanResult(Name, Version, startTime)
 	scanResult.Repository = opts.RepoPath
 	if err := scanResult.SetSummary(summary); err != nil {
 		return nil, fmt.Errorf("failed to set summary: %w", err)
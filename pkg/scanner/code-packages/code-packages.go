@@ -6,17 +6,28 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/crashappsec/zero/pkg/core/behavior"
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/exploitdb"
+	"github.com/crashappsec/zero/pkg/core/licenses"
 	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/osvdb"
+	"github.com/crashappsec/zero/pkg/core/spdx"
+	"github.com/crashappsec/zero/pkg/core/typosquat"
+	"github.com/crashappsec/zero/pkg/core/upgrades"
+	"github.com/crashappsec/zero/pkg/core/versions"
 	"github.com/crashappsec/zero/pkg/scanner"
 	"github.com/crashappsec/zero/pkg/scanner/common"
 )
@@ -49,6 +60,33 @@ func (s *SupplyChainScanner) Dependencies() []string {
 	return nil
 }
 
+func (s *SupplyChainScanner) Version() string {
+	return Version
+}
+
+func (s *SupplyChainScanner) Tools() []string {
+	return []string{"cdxgen", "syft", "osv-scanner", "mal"}
+}
+
+func (s *SupplyChainScanner) Artifacts() []string {
+	return []string{"sbom.cdx.json"}
+}
+
+// Feeds returns the local OSV and exploit databases and the typosquat corpus
+// the scan would read
+func (s *SupplyChainScanner) Feeds(opts *scanner.ScanOptions) []string {
+	cfg := getFeatureConfig(opts)
+	home := zeroHome(opts)
+	feeds := []string{cfg.Vulns.LocalDB, cfg.Vulns.ExploitDB, cfg.Typosquats.Corpus}
+	defaults := []string{osvdb.DefaultPath(home), exploitdb.DefaultPath(home), typosquat.DefaultPath(home)}
+	for i := range feeds {
+		if feeds[i] == "" {
+			feeds[i] = defaults[i]
+		}
+	}
+	return feeds
+}
+
 func (s *SupplyChainScanner) EstimateDuration(fileCount int) time.Duration {
 	base := 5 * time.Second
 	if s.config.Vulns.Enabled {
@@ -72,6 +110,11 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	// Load feature config
 	s.config = getFeatureConfig(opts)
 
+	// Registry and API responses are cached on disk across runs
+	s.useAPICache(opts)
+	cacheStats := &liveapi.StatsRecorder{}
+	ctx = liveapi.WithStatsRecorder(ctx, cacheStats)
+
 	// Ensure output directory
 	if opts.OutputDir != "" {
 		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
@@ -85,35 +128,63 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 		Findings:    Findings{},
 	}
 
+	// Each feature runs under its own deadline so a slow one (reachability,
+	// malcontent) cannot cost the results of the others
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	var sbomPath string
 	var components []Component
 
 	// 1. SBOM Generation (internal - this scanner generates its own SBOM)
 	if s.config.Generation.Enabled {
-		genSummary, genFindings, path, err := runGeneration(ctx, opts, s.config.Generation)
+		type generationResult struct {
+			summary  *GenerationSummary
+			findings *GenerationFindings
+			path     string
+			err      error
+		}
+		gen, ok := scanner.RunFeature(tracker, "generation", func(ctx context.Context) generationResult {
+			summary, findings, path, err := runGeneration(ctx, opts, s.config.Generation)
+			return generationResult{summary, findings, path, err}
+		})
 		result.FeaturesRun = append(result.FeaturesRun, "generation")
-		if err != nil {
-			result.Summary.Errors = append(result.Summary.Errors, fmt.Sprintf("generation: %v", err))
-			genSummary = &GenerationSummary{Error: err.Error()}
+		if !ok {
+			gen.err = fmt.Errorf("timed out")
 		}
-		result.Summary.Generation = genSummary
-		result.Findings.Generation = genFindings
-		sbomPath = path
-		if genFindings != nil {
-			components = genFindings.Components
+		if gen.err != nil {
+			tracker.Fail("generation", gen.err)
+			result.Summary.Errors = append(result.Summary.Errors, fmt.Sprintf("generation: %v", gen.err))
+			gen.summary = &GenerationSummary{Error: gen.err.Error()}
+		}
+		result.Summary.Generation = gen.summary
+		result.Findings.Generation = gen.findings
+		sbomPath = gen.path
+		if gen.findings != nil {
+			components = gen.findings.Components
 		}
 	}
 
 	// 2. Integrity Verification
 	if s.config.Integrity.Enabled && sbomPath != "" {
-		intSummary, intFindings, err := runIntegrity(opts.RepoPath, sbomPath, components, s.config.Integrity)
-		result.FeaturesRun = append(result.FeaturesRun, "integrity")
-		if err != nil {
-			result.Summary.Errors = append(result.Summary.Errors, fmt.Sprintf("integrity: %v", err))
-			intSummary = &IntegritySummary{Error: err.Error()}
+		type integrityResult struct {
+			summary  *IntegritySummary
+			findings *IntegrityFindings
+			err      error
+		}
+		integrity, ok := scanner.RunFeature(tracker, "integrity", func(context.Context) integrityResult {
+			summary, findings, err := runIntegrity(opts.RepoPath, sbomPath, components, s.config.Integrity)
+			return integrityResult{summary, findings, err}
+		})
+		if ok {
+			result.FeaturesRun = append(result.FeaturesRun, "integrity")
+			if integrity.err != nil {
+				tracker.Fail("integrity", integrity.err)
+				result.Summary.Errors = append(result.Summary.Errors, fmt.Sprintf("integrity: %v", integrity.err))
+				integrity.summary = &IntegritySummary{Error: integrity.err.Error()}
+			}
+			result.Summary.Integrity = integrity.summary
+			result.Findings.Integrity = integrity.findings
 		}
-		result.Summary.Integrity = intSummary
-		result.Findings.Integrity = intFindings
 	}
 
 	// Convert to ComponentData for package analysis features
@@ -129,16 +200,34 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 		})
 	}
 
+	// Features that need the SBOM are skipped, not silently dropped, when
+	// generation did not complete
+	needsSBOM := func(feature string, enabled bool) bool {
+		if !enabled {
+			return false
+		}
+		if sbomPath == "" && s.config.Generation.Enabled && !tracker.Completed("generation") {
+			tracker.Skip(feature, "generation")
+			return false
+		}
+		return true
+	}
+
 	// Parallel features
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
 	// 3. Vulnerabilities
-	if s.config.Vulns.Enabled && sbomPath != "" {
+	if needsSBOM("vulns", s.config.Vulns.Enabled) && sbomPath != "" {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			vulnsResult := s.runVulnsFeature(ctx, opts, sbomPath)
+			vulnsResult, ok := scanner.RunFeature(tracker, "vulns", func(ctx context.Context) *vulnsFeatureResult {
+				return s.runVulnsFeature(ctx, opts, sbomPath)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "vulns")
 			result.Summary.Vulns = vulnsResult.Summary
@@ -148,11 +237,16 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	}
 
 	// 4. Health
-	if s.config.Health.Enabled && len(componentData) > 0 {
+	if needsSBOM("health", s.config.Health.Enabled) && len(componentData) > 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			healthResult := s.runHealthFeature(ctx, componentData)
+			healthResult, ok := scanner.RunFeature(tracker, "health", func(ctx context.Context) *healthFeatureResult {
+				return s.runHealthFeature(ctx, componentData)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "health")
 			result.Summary.Health = healthResult.Summary
@@ -162,11 +256,16 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	}
 
 	// 5. Licenses
-	if s.config.Licenses.Enabled && sbomPath != "" {
+	if needsSBOM("licenses", s.config.Licenses.Enabled) && sbomPath != "" {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			licensesResult := s.runLicensesFeature(sbomPath)
+			licensesResult, ok := scanner.RunFeature(tracker, "licenses", func(context.Context) *licensesFeatureResult {
+				return s.runLicensesFeature(opts, sbomPath)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "licenses")
 			result.Summary.Licenses = licensesResult.Summary
@@ -180,7 +279,12 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			malcontentResult := s.runMalcontentFeature(ctx, opts)
+			malcontentResult, ok := scanner.RunFeature(tracker, "malcontent", func(ctx context.Context) *malcontentFeatureResult {
+				return s.runMalcontentFeature(ctx, opts, sbomPath)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "malcontent")
 			result.Summary.Malcontent = malcontentResult.Summary
@@ -194,7 +298,12 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			confusionResult := s.runConfusionFeature(ctx, opts)
+			confusionResult, ok := scanner.RunFeature(tracker, "confusion", func(ctx context.Context) *confusionFeatureResult {
+				return s.runConfusionFeature(ctx, opts)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "confusion")
 			result.Summary.Confusion = confusionResult.Summary
@@ -204,11 +313,16 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	}
 
 	// 8. Typosquats
-	if s.config.Typosquats.Enabled && len(componentData) > 0 {
+	if needsSBOM("typosquats", s.config.Typosquats.Enabled) && len(componentData) > 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			typosquatsResult := s.runTyposquatsFeature(ctx, componentData)
+			typosquatsResult, ok := scanner.RunFeature(tracker, "typosquats", func(ctx context.Context) *typosquatsFeatureResult {
+				return s.runTyposquatsFeature(ctx, opts, componentData)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "typosquats")
 			result.Summary.Typosquats = typosquatsResult.Summary
@@ -218,11 +332,16 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	}
 
 	// 9. Deprecations
-	if s.config.Deprecations.Enabled && len(componentData) > 0 {
+	if needsSBOM("deprecations", s.config.Deprecations.Enabled) && len(componentData) > 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			deprecationsResult := s.runDeprecationsFeature(ctx, componentData)
+			deprecationsResult, ok := scanner.RunFeature(tracker, "deprecations", func(ctx context.Context) *deprecationsFeatureResult {
+				return s.runDeprecationsFeature(ctx, componentData)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "deprecations")
 			result.Summary.Deprecations = deprecationsResult.Summary
@@ -232,11 +351,16 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	}
 
 	// 10. Duplicates
-	if s.config.Duplicates.Enabled && len(componentData) > 0 {
+	if needsSBOM("duplicates", s.config.Duplicates.Enabled) && len(componentData) > 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			duplicatesResult := s.runDuplicatesFeature(componentData)
+			duplicatesResult, ok := scanner.RunFeature(tracker, "duplicates", func(context.Context) *duplicatesFeatureResult {
+				return s.runDuplicatesFeature(componentData)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "duplicates")
 			result.Summary.Duplicates = duplicatesResult.Summary
@@ -251,24 +375,47 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 
 	// 11. Reachability
 	if s.config.Reachability.Enabled {
-		reachabilityResult := s.runReachabilityFeature(ctx, opts)
-		result.FeaturesRun = append(result.FeaturesRun, "reachability")
-		result.Summary.Reachability = reachabilityResult.Summary
-		result.Findings.Reachability = reachabilityResult.Findings
+		// Copied: prioritization below ranks the findings in place, even
+		// while a feature abandoned at its deadline is still reading them
+		vulns, _ := result.Findings.Vulns.([]VulnFinding)
+		vulns = slices.Clone(vulns)
+		reachabilityResult, ok := scanner.RunFeature(tracker, "reachability", func(ctx context.Context) *reachabilityFeatureResult {
+			return s.runReachabilityFeature(ctx, opts, vulns)
+		})
+		if ok {
+			result.FeaturesRun = append(result.FeaturesRun, "reachability")
+			result.Summary.Reachability = reachabilityResult.Summary
+			result.Findings.Reachability = reachabilityResult.Findings
+		}
+	}
+
+	// Rank vulnerabilities once their reachability is known
+	if vulns, ok := result.Findings.Vulns.([]VulnFinding); ok && len(vulns) > 0 {
+		reachability, _ := result.Findings.Reachability.([]ReachabilityFinding)
+		prioritizeVulns(vulns, reachability)
+		if result.Summary.Vulns != nil {
+			result.Summary.Vulns.FixFirst = fixFirst(vulns)
+		}
 	}
 
 	// 12. Provenance
-	if s.config.Provenance.Enabled && len(componentData) > 0 {
-		provenanceResult := s.runProvenanceFeature(ctx, componentData)
-		result.FeaturesRun = append(result.FeaturesRun, "provenance")
-		result.Summary.Provenance = provenanceResult.Summary
-		result.Findings.Provenance = provenanceResult.Findings
+	if needsSBOM("provenance", s.config.Provenance.Enabled) && len(componentData) > 0 {
+		provenanceResult, ok := scanner.RunFeature(tracker, "provenance", func(ctx context.Context) *provenanceFeatureResult {
+			return s.runProvenanceFeature(ctx, componentData)
+		})
+		if ok {
+			result.FeaturesRun = append(result.FeaturesRun, "provenance")
+			result.Summary.Provenance = provenanceResult.Summary
+			result.Findings.Provenance = provenanceResult.Findings
+		}
 	}
 
 	// 13. Bundle (npm only)
 	if s.config.Bundle.Enabled {
-		bundleResult := s.runBundleFeature(ctx, opts)
-		if bundleResult != nil {
+		bundleResult, ok := scanner.RunFeature(tracker, "bundle", func(ctx context.Context) *bundleFeatureResult {
+			return s.runBundleFeature(ctx, opts)
+		})
+		if ok && bundleResult != nil {
 			result.FeaturesRun = append(result.FeaturesRun, "bundle")
 			result.Summary.Bundle = bundleResult.Summary
 			result.Findings.Bundle = bundleResult.Findings
@@ -277,22 +424,34 @@ func (s *SupplyChainScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 
 	// 14. Recommendations
 	if s.config.Recommendations.Enabled {
-		recommendationsResult := s.runRecommendationsFeature(result)
-		result.FeaturesRun = append(result.FeaturesRun, "recommendations")
-		result.Summary.Recommendations = recommendationsResult.Summary
-		result.Findings.Recommendations = recommendationsResult.Findings
+		input := newRecommendationsInput(result)
+		recommendationsResult, ok := scanner.RunFeature(tracker, "recommendations", func(ctx context.Context) *recommendationsFeatureResult {
+			return s.runRecommendationsFeature(ctx, sbomPath, input)
+		})
+		if ok {
+			result.FeaturesRun = append(result.FeaturesRun, "recommendations")
+			result.Summary.Recommendations = recommendationsResult.Summary
+			result.Findings.Recommendations = recommendationsResult.Findings
+		}
 	}
 
+	result.Summary.Cache = cacheSummary(cacheStats.Stats())
+
 	// Create scan result
+	sbomSource := "internal"
+	if s.config.Generation.SBOMPath != "" {
+		sbomSource = "import"
+	}
 	scanResult := scanner.NewScanResult(Name, Version, start)
 	scanResult.Repository = opts.RepoPath
 	_ = scanResult.SetSummary(result.Summary)
 	_ = scanResult.SetFindings(result)
 	_ = scanResult.SetMetadata(map[string]interface{}{
 		"features_run":    result.FeaturesRun,
-		"sbom_source":     "internal",
+		"sbom_source":     sbomSource,
 		"component_count": len(componentData),
 	})
+	tracker.Apply(scanResult)
 
 	// Write result
 	if opts.OutputDir != "" {
@@ -328,12 +487,18 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := genCfg["fallback_to_syft"].(bool); ok {
 			cfg.Generation.FallbackToSyft = v
 		}
+		if v, ok := genCfg["fallback_to_native"].(bool); ok {
+			cfg.Generation.FallbackToNative = v
+		}
 		if v, ok := genCfg["include_dev"].(bool); ok {
 			cfg.Generation.IncludeDev = v
 		}
 		if v, ok := genCfg["deep"].(bool); ok {
 			cfg.Generation.Deep = v
 		}
+		if v, ok := genCfg["sbom_path"].(string); ok {
+			cfg.Generation.SBOMPath = v
+		}
 	}
 
 	// Parse integrity config
@@ -360,6 +525,15 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := vulnsCfg["include_kev"].(bool); ok {
 			cfg.Vulns.IncludeKEV = v
 		}
+		if v, ok := vulnsCfg["local_db"].(string); ok {
+			cfg.Vulns.LocalDB = v
+		}
+		if v, ok := vulnsCfg["exploit_db"].(string); ok {
+			cfg.Vulns.ExploitDB = v
+		}
+		if v, ok := vulnsCfg["api_fallback"].(bool); ok {
+			cfg.Vulns.APIFallback = v
+		}
 	}
 
 	// Parse health config
@@ -377,6 +551,32 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := licCfg["enabled"].(bool); ok {
 			cfg.Licenses.Enabled = v
 		}
+		if v, ok := licCfg["distribution"].(string); ok {
+			cfg.Licenses.Distribution = v
+		}
+		if v, ok := licCfg["allowed"]; ok && v != nil {
+			cfg.Licenses.AllowedList = common.GetStringArray(licCfg, "allowed")
+		}
+		if v, ok := licCfg["blocked"]; ok && v != nil {
+			cfg.Licenses.BlockedList = common.GetStringArray(licCfg, "blocked")
+		}
+		if v, ok := licCfg["review"]; ok && v != nil {
+			cfg.Licenses.ReviewList = common.GetStringArray(licCfg, "review")
+		}
+		if categories := common.GetMap(licCfg, "categories"); categories != nil {
+			cfg.Licenses.Categories = make(map[string]string)
+			for category, decision := range categories {
+				if d, ok := decision.(string); ok {
+					cfg.Licenses.Categories[category] = d
+				}
+			}
+		}
+		if v, ok := licCfg["fail_on_unknown"].(bool); ok {
+			cfg.Licenses.FailOnUnknown = v
+		}
+		if v, ok := licCfg["notices"].(bool); ok {
+			cfg.Licenses.Notices = v
+		}
 	}
 
 	// Parse malcontent config
@@ -387,6 +587,12 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := malCfg["min_risk_level"].(string); ok {
 			cfg.Malcontent.MinRiskLevel = v
 		}
+		if v, ok := malCfg["engine"].(string); ok && v != "" {
+			cfg.Malcontent.Engine = v
+		}
+		if v, ok := malCfg["cache_dirs"]; ok && v != nil {
+			cfg.Malcontent.CacheDirs = common.GetStringArray(malCfg, "cache_dirs")
+		}
 	}
 
 	// Parse confusion config
@@ -422,6 +628,9 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := recCfg["enabled"].(bool); ok {
 			cfg.Recommendations.Enabled = v
 		}
+		if v, ok := recCfg["resolve_transitive"].(bool); ok {
+			cfg.Recommendations.ResolveTransitive = v
+		}
 	}
 
 	// Parse typosquats config
@@ -429,6 +638,18 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		if v, ok := typoCfg["enabled"].(bool); ok {
 			cfg.Typosquats.Enabled = v
 		}
+		if v, ok := typoCfg["check_similar_names"].(bool); ok {
+			cfg.Typosquats.CheckSimilarNames = v
+		}
+		if v, ok := typoCfg["check_new_packages"].(bool); ok {
+			cfg.Typosquats.CheckNewPackages = v
+		}
+		if v, ok := typoCfg["corpus"].(string); ok {
+			cfg.Typosquats.Corpus = v
+		}
+		if v, ok := typoCfg["min_confidence"].(float64); ok {
+			cfg.Typosquats.MinConfidence = v
+		}
 	}
 
 	// Parse deprecations config
@@ -445,39 +666,91 @@ func getFeatureConfig(opts *scanner.ScanOptions) FeatureConfig {
 		}
 	}
 
+	// Parse API cache config
+	if cacheCfg, ok := opts.FeatureConfig["api_cache"].(map[string]interface{}); ok {
+		if v, ok := cacheCfg["enabled"].(bool); ok {
+			cfg.APICache.Enabled = v
+		}
+		if v, ok := cacheCfg["path"].(string); ok {
+			cfg.APICache.Path = v
+		}
+		cfg.APICache.TTLHours = parseHours(cacheCfg["ttl_hours"])
+		cfg.APICache.StaleHours = parseHours(cacheCfg["stale_hours"])
+	}
+
 	return cfg
 }
 
+// parseHours reads a map of endpoint names to hours
+func parseHours(v interface{}) map[string]int {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	hours := make(map[string]int, len(m))
+	for k, h := range m {
+		if f, ok := h.(float64); ok {
+			hours[k] = int(f)
+		}
+	}
+	return hours
+}
+
 // =============================================================================
 // SBOM Generation Feature (absorbed from sbom scanner)
 // =============================================================================
 
 func runGeneration(ctx context.Context, opts *scanner.ScanOptions, cfg GenerationConfig) (*GenerationSummary, *GenerationFindings, string, error) {
+	sbomFile := filepath.Join(opts.OutputDir, "sbom.cdx.json")
+
+	// A supplied SBOM replaces generation
+	if cfg.SBOMPath != "" {
+		src := cfg.SBOMPath
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(opts.RepoPath, src)
+		}
+		format, err := ImportSBOM(src, sbomFile)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		summary, findings, err := summarizeSBOM(sbomFile, "import")
+		if summary != nil {
+			summary.SourceFormat = format
+		}
+		return summary, findings, sbomFile, err
+	}
+
 	// Determine tool to use
 	tool := cfg.Tool
 	switch tool {
+	case "native":
 	case "cdxgen":
 		if !common.ToolExists("cdxgen") {
 			if cfg.FallbackToSyft && common.ToolExists("syft") {
 				tool = "syft"
+			} else if cfg.FallbackToNative {
+				tool = "native"
 			} else {
 				return nil, nil, "", fmt.Errorf("cdxgen not found and no fallback available")
 			}
 		}
 	case "syft":
 		if !common.ToolExists("syft") {
-			return nil, nil, "", fmt.Errorf("syft not found")
+			if !cfg.FallbackToNative {
+				return nil, nil, "", fmt.Errorf("syft not found")
+			}
+			tool = "native"
 		}
 	default: // auto
 		tool, _ = common.PreferTool("cdxgen", "syft")
 		if tool == "" {
-			return nil, nil, "", fmt.Errorf("no SBOM tool found (install cdxgen or syft)")
+			if !cfg.FallbackToNative {
+				return nil, nil, "", fmt.Errorf("no SBOM tool found (install cdxgen or syft)")
+			}
+			tool = "native"
 		}
 	}
 
-	// Generate SBOM file path
-	sbomFile := filepath.Join(opts.OutputDir, "sbom.cdx.json")
-
 	// Run SBOM generation
 	var err error
 	switch tool {
@@ -506,16 +779,30 @@ func runGeneration(ctx context.Context, opts *scanner.ScanOptions, cfg Generatio
 		if cmdErr != nil {
 			err = cmdErr
 		}
+	case "native":
+		err = generateNativeSBOM(opts.RepoPath, sbomFile, cfg.SpecVersion)
+	}
+
+	// Fall back to the lockfiles when the external tool failed
+	if err != nil && tool != "native" && cfg.FallbackToNative && ctx.Err() == nil {
+		tool = "native"
+		err = generateNativeSBOM(opts.RepoPath, sbomFile, cfg.SpecVersion)
 	}
 
 	if err != nil {
 		return nil, nil, "", err
 	}
 
-	// Parse the generated SBOM
+	summary, findings, err := summarizeSBOM(sbomFile, tool)
+	return summary, findings, sbomFile, err
+}
+
+// summarizeSBOM parses a generated or imported SBOM into the generation
+// summary and findings
+func summarizeSBOM(sbomFile, tool string) (*GenerationSummary, *GenerationFindings, error) {
 	sbomData, parseErr := parseSBOM(sbomFile)
 	if parseErr != nil {
-		return nil, nil, sbomFile, fmt.Errorf("parsing SBOM: %w", parseErr)
+		return nil, nil, fmt.Errorf("parsing SBOM: %w", parseErr)
 	}
 
 	// Build summary
@@ -600,7 +887,55 @@ func runGeneration(ctx context.Context, opts *scanner.ScanOptions, cfg Generatio
 		},
 	}
 
-	return summary, findings, sbomFile, nil
+	return summary, findings, nil
+}
+
+// ImportSBOM copies an externally supplied SBOM to dst as CycloneDX JSON.
+// SPDX 2.3 JSON, SPDX tag-value and SPDX 3.0 JSON-LD documents are
+// converted; CycloneDX JSON is checked and copied as is. It returns the
+// source format: "cyclonedx", "spdx-json", "spdx-tag-value" or
+// "spdx-jsonld".
+func ImportSBOM(src, dst string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("reading SBOM: %w", err)
+	}
+
+	format := DetectSBOMFormat(data)
+	switch format {
+	case "":
+		return "", fmt.Errorf("%s is not a CycloneDX JSON or SPDX SBOM", filepath.Base(src))
+	case "cyclonedx":
+	default:
+		doc, err := spdx.Parse(data)
+		if err != nil {
+			return "", err
+		}
+		if data, err = doc.ToCycloneDX().ToJSON(); err != nil {
+			return "", fmt.Errorf("converting SPDX SBOM: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		return "", fmt.Errorf("writing SBOM: %w", err)
+	}
+	return format, nil
+}
+
+// DetectSBOMFormat identifies an SBOM document: "cyclonedx", "spdx-json",
+// "spdx-tag-value" or "spdx-jsonld". It returns "" for anything else.
+func DetectSBOMFormat(data []byte) string {
+	if spdx.IsSPDX(data) {
+		return "spdx-" + spdx.Format(data)
+	}
+	bom, err := cyclonedx.FromJSON(data)
+	if err != nil || bom.BOMFormat != "CycloneDX" {
+		return ""
+	}
+	return "cyclonedx"
 }
 
 func parseSBOM(sbomPath string) (*CycloneDXBOM, error) {
@@ -730,12 +1065,19 @@ func compareSBOMs(previousPath, currentPath string, currentComponents []Componen
 	for key, current := range currentMap {
 		if previous, exists := previousMap[key]; exists {
 			if previous.Version != current.Version {
-				drift.VersionChanged = append(drift.VersionChanged, VersionChange{
+				change := VersionChange{
 					Name:       current.Name,
 					Ecosystem:  current.Ecosystem,
 					OldVersion: previous.Version,
 					NewVersion: current.Version,
-				})
+				}
+				switch versions.Compare(versions.ParseEcosystem(current.Ecosystem), previous.Version, current.Version) {
+				case -1:
+					change.Direction = "upgrade"
+				case 1:
+					change.Direction = "downgrade"
+				}
+				drift.VersionChanged = append(drift.VersionChanged, change)
 			}
 		}
 	}
@@ -760,7 +1102,7 @@ func verifyAgainstLockfiles(repoPath string, components []Component) []LockfileC
 		if compMap[eco] == nil {
 			compMap[eco] = make(map[string]string)
 		}
-		compMap[eco][c.Name] = c.Version
+		compMap[eco][lockfileName(eco, purlNamespace(c.Purl), c.Name)] = c.Version
 	}
 
 	// Find all lockfiles recursively
@@ -768,37 +1110,8 @@ func verifyAgainstLockfiles(repoPath string, components []Component) []LockfileC
 
 	// Process each lockfile
 	for _, lockPath := range lockfiles {
-		var comp LockfileComparison
 		base := filepath.Base(lockPath)
-
-		switch base {
-		case "package-lock.json":
-			comp = compareLockfile(lockPath, "npm", compMap["npm"])
-		case "yarn.lock":
-			comp = compareYarnLock(lockPath, compMap["npm"])
-		case "pnpm-lock.yaml":
-			comp = comparePnpmLock(lockPath, compMap["npm"])
-		case "go.sum":
-			comp = compareGoSum(lockPath, compMap["golang"])
-		case "requirements.txt":
-			comp = compareRequirements(lockPath, compMap["pypi"])
-		case "poetry.lock":
-			comp = comparePoetryLock(lockPath, compMap["pypi"])
-		case "uv.lock":
-			comp = compareUvLock(lockPath, compMap["pypi"])
-		case "Cargo.lock":
-			comp = compareCargoLock(lockPath, compMap["cargo"])
-		case "Gemfile.lock":
-			comp = compareGemfileLock(lockPath, compMap["gem"])
-		case "composer.lock":
-			comp = compareComposerLock(lockPath, compMap["composer"])
-		case "packages.lock.json":
-			comp = compareNugetLock(lockPath, compMap["nuget"])
-		case "gradle.lockfile":
-			comp = compareGradleLock(lockPath, compMap["maven"])
-		default:
-			continue
-		}
+		comp := compareLockfile(lockPath, compMap[lockfileEcosystems[base]])
 
 		// Include relative path for subdirectory lockfiles
 		relPath, _ := filepath.Rel(repoPath, lockPath)
@@ -816,22 +1129,6 @@ func verifyAgainstLockfiles(repoPath string, components []Component) []LockfileC
 func findLockfilesRecursive(root string) []string {
 	var lockfiles []string
 
-	// Supported lockfile names
-	lockfileNames := map[string]bool{
-		"package-lock.json":  true,
-		"yarn.lock":          true,
-		"pnpm-lock.yaml":     true,
-		"go.sum":             true,
-		"requirements.txt":   true,
-		"poetry.lock":        true,
-		"uv.lock":            true,
-		"Cargo.lock":         true,
-		"Gemfile.lock":       true,
-		"composer.lock":      true,
-		"packages.lock.json": true,
-		"gradle.lockfile":    true,
-	}
-
 	// Directories to skip
 	skipDirs := map[string]bool{
 		"node_modules": true,
@@ -860,7 +1157,7 @@ func findLockfilesRecursive(root string) []string {
 		}
 
 		// Check if this is a lockfile
-		if lockfileNames[info.Name()] {
+		if _, ok := lockfileEcosystems[info.Name()]; ok {
 			lockfiles = append(lockfiles, path)
 		}
 
@@ -870,59 +1167,31 @@ func findLockfilesRecursive(root string) []string {
 	return lockfiles
 }
 
-func compareLockfile(lockPath, ecosystem string, sbomPkgs map[string]string) LockfileComparison {
+// compareLockfile compares the packages resolved in a lockfile with the
+// SBOM's packages of the same ecosystem
+func compareLockfile(lockPath string, sbomPkgs map[string]string) LockfileComparison {
+	base := filepath.Base(lockPath)
+	ecosystem := lockfileEcosystems[base]
 	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
+		Lockfile:  base,
 		Ecosystem: ecosystem,
 		InSBOM:    len(sbomPkgs),
 	}
 
-	data, err := os.ReadFile(lockPath)
+	lf, err := parseLockfile(lockPath)
 	if err != nil {
 		return comp
 	}
 
-	var lockData struct {
-		Packages map[string]struct {
-			Version string `json:"version"`
-		} `json:"packages"`
-		Dependencies map[string]struct {
-			Version string `json:"version"`
-		} `json:"dependencies"`
-	}
-
-	if err := json.Unmarshal(data, &lockData); err != nil {
-		return comp
-	}
-
-	lockPkgs := make(map[string]string)
-
-	// npm v2+ format (packages)
-	for name, pkg := range lockData.Packages {
-		if name == "" || strings.HasPrefix(name, "node_modules/") {
-			// Extract package name from path
-			parts := strings.Split(name, "node_modules/")
-			if len(parts) > 1 {
-				name = parts[len(parts)-1]
-			}
-		}
-		if name != "" && pkg.Version != "" {
-			lockPkgs[name] = pkg.Version
-		}
-	}
-
-	// npm v1 format (dependencies)
-	for name, pkg := range lockData.Dependencies {
-		if pkg.Version != "" {
-			lockPkgs[name] = pkg.Version
-		}
+	lockPkgs := make(map[string]bool)
+	for _, p := range lf.Packages {
+		lockPkgs[lockfileName(ecosystem, p.Group, p.Name)] = true
 	}
-
 	comp.InLockfile = len(lockPkgs)
 
 	// Compare
 	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
+		if lockPkgs[name] {
 			comp.Matched++
 		} else {
 			comp.Extra++
@@ -938,930 +1207,491 @@ func compareLockfile(lockPath, ecosystem string, sbomPkgs map[string]string) Loc
 	return comp
 }
 
-func compareYarnLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "npm",
-		InSBOM:    len(sbomPkgs),
+// lockfileName returns the name a package is compared by between the SBOM
+// and lockfiles: normalized per ecosystem, with Maven's group ID
+func lockfileName(ecosystem, group, name string) string {
+	name = normalizePackageName(ecosystem, name)
+	if ecosystem == "maven" && group != "" {
+		return group + ":" + name
 	}
+	return name
+}
 
-	file, err := os.Open(lockPath)
+// purlNamespace returns the namespace of a purl (the Maven group ID,
+// npm scope, etc.), or "" if it has none
+func purlNamespace(purl string) string {
+	purl = strings.TrimPrefix(purl, "pkg:")
+	if idx := strings.IndexAny(purl, "@?#"); idx >= 0 {
+		purl = purl[:idx]
+	}
+	parts := strings.Split(purl, "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	namespace, err := url.PathUnescape(strings.Join(parts[1:len(parts)-1], "/"))
 	if err != nil {
-		return comp
+		return ""
 	}
-	defer file.Close()
+	return namespace
+}
 
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-	var currentPkg string
+// GetSBOMPath returns the path to the SBOM file in the output directory
+// This is used by other scanners to locate the SBOM
+func GetSBOMPath(outputDir string) string {
+	return filepath.Join(outputDir, "sbom.cdx.json")
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+// LoadSBOM loads and parses an existing SBOM file
+// This is used by other scanners to consume SBOM data
+func LoadSBOM(sbomPath string) (*GenerationFindings, error) {
+	bom, err := parseSBOM(sbomPath)
+	if err != nil {
+		return nil, err
+	}
 
-		// Package line: "package-name@version":
-		if strings.HasSuffix(line, ":") && !strings.HasPrefix(line, " ") {
-			// Extract package name
-			name := strings.TrimSuffix(line, ":")
-			name = strings.Trim(name, "\"")
-			// Remove version constraint
-			if idx := strings.LastIndex(name, "@"); idx > 0 {
-				name = name[:idx]
-			}
-			currentPkg = name
+	components := make([]Component, 0, len(bom.Components))
+	for _, c := range bom.Components {
+		comp := Component{
+			Type:      c.Type,
+			Name:      c.Name,
+			Version:   c.Version,
+			Purl:      c.Purl,
+			Ecosystem: extractEcosystemFromPurl(c.Purl),
+			Scope:     c.Scope,
 		}
 
-		// Version line: "  version "x.x.x""
-		if strings.HasPrefix(line, "  version ") && currentPkg != "" {
-			version := strings.TrimPrefix(line, "  version ")
-			version = strings.Trim(version, "\"")
-			lockPkgs[currentPkg] = version
+		for _, lic := range c.Licenses {
+			if lic.License.ID != "" {
+				comp.Licenses = append(comp.Licenses, lic.License.ID)
+			} else if lic.License.Name != "" {
+				comp.Licenses = append(comp.Licenses, lic.License.Name)
+			}
 		}
-	}
-
-	comp.InLockfile = len(lockPkgs)
 
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
+		components = append(components, comp)
 	}
 
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
+	dependencies := make([]Dependency, 0, len(bom.Dependencies))
+	for _, d := range bom.Dependencies {
+		dependencies = append(dependencies, Dependency{
+			Ref:       d.Ref,
+			DependsOn: d.DependsOn,
+		})
 	}
 
-	return comp
+	return &GenerationFindings{
+		Components:   components,
+		Dependencies: dependencies,
+		Metadata: &SBOMMetadata{
+			BomFormat:   bom.BomFormat,
+			SpecVersion: bom.SpecVersion,
+			Version:     bom.Version,
+		},
+	}, nil
 }
 
-func compareGoSum(sumPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(sumPath),
-		Ecosystem: "golang",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	file, err := os.Open(sumPath)
-	if err != nil {
-		return comp
-	}
-	defer file.Close()
+// ==================== Vulns Feature ====================
 
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
+type vulnsFeatureResult struct {
+	Summary  *VulnsSummary
+	Findings []VulnFinding
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			name := parts[0]
-			version := strings.TrimSuffix(parts[1], "/go.mod")
-			version = strings.TrimPrefix(version, "v")
-			lockPkgs[name] = version
-		}
+func (s *SupplyChainScanner) runVulnsFeature(ctx context.Context, opts *scanner.ScanOptions, sbomPath string) *vulnsFeatureResult {
+	result := &vulnsFeatureResult{
+		Summary:  &VulnsSummary{},
+		Findings: []VulnFinding{},
 	}
 
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
+	// Prefer the local OSV database (zero feeds osv), which matches offline
+	dbPath := s.config.Vulns.LocalDB
+	if dbPath == "" {
+		dbPath = osvdb.DefaultPath(zeroHome(opts))
+	}
+	switch {
+	case osvdb.Exists(dbPath):
+		if err := s.matchVulns(ctx, dbPath, sbomPath, result); err != nil {
+			result.Summary.Error = err.Error()
+			return result
+		}
+	case common.ToolExists("osv-scanner") && !liveapi.Offline():
+		runOSVScanner(ctx, opts, sbomPath, result)
+	case s.config.Vulns.APIFallback:
+		if err := s.matchVulns(ctx, "", sbomPath, result); err != nil {
+			result.Summary.Error = err.Error()
+			return result
 		}
+	default:
+		result.Summary.Error = "no OSV database (run 'zero feeds osv --from <dump>') and osv-scanner not installed"
+		return result
 	}
 
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
+	graph := loadDependencyGraph(sbomPath)
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		refs := graph.Lookup(f.Ecosystem, f.Package, f.Version)
+		f.DependencyPath, f.DependencyPaths = graph.Paths(refs...)
+		f.Scope = graphScope(graph, refs)
 	}
 
-	return comp
-}
+	// Exploit intelligence for prioritization
+	s.enrichExploits(ctx, opts, result)
 
-func compareRequirements(reqPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(reqPath),
-		Ecosystem: "pypi",
-		InSBOM:    len(sbomPkgs),
-	}
+	return result
+}
 
-	file, err := os.Open(reqPath)
+// matchVulns matches the SBOM's components against the OSV database at
+// dbPath. Components in ecosystems the database has no data for are sent to
+// the OSV API when api_fallback is enabled; otherwise, or when the API
+// fails, they are listed as unchecked so the results read as incomplete. An
+// empty dbPath queries the API for everything.
+func (s *SupplyChainScanner) matchVulns(ctx context.Context, dbPath, sbomPath string, result *vulnsFeatureResult) error {
+	sbom, err := LoadSBOM(sbomPath)
 	if err != nil {
-		return comp
+		return fmt.Errorf("loading SBOM: %w", err)
 	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	var db *osvdb.DB
+	covered := make(map[versions.Ecosystem]bool)
+	if dbPath != "" {
+		db, err = osvdb.Open(dbPath)
+		if err != nil {
+			return err
 		}
+		defer db.Close()
 
-		// Handle various formats: pkg==1.0, pkg>=1.0, pkg
-		var name, version string
-		for _, sep := range []string{"==", ">=", "<=", "~=", "!=", ">", "<"} {
-			if idx := strings.Index(line, sep); idx > 0 {
-				name = strings.TrimSpace(line[:idx])
-				version = strings.TrimSpace(line[idx+len(sep):])
-				break
-			}
+		stats, err := db.Stats(ctx)
+		if err != nil {
+			return err
 		}
-		if name == "" {
-			name = line
+		for eco := range stats.Ecosystems {
+			covered[eco] = true
 		}
-
-		// Normalize name (PEP 503)
-		name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
-		lockPkgs[name] = version
+		result.Summary.Sources = append(result.Summary.Sources, "osv-db")
 	}
 
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		normalizedName := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
-		if _, ok := lockPkgs[normalizedName]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
-	}
-
-	for name := range lockPkgs {
-		found := false
-		for sbomName := range sbomPkgs {
-			if strings.ToLower(strings.ReplaceAll(sbomName, "_", "-")) == name {
-				found = true
-				break
-			}
+	seen := make(map[string]bool)
+	var uncovered []Component
+	for _, c := range sbom.Components {
+		if c.Version == "" || c.Purl == "" {
+			continue
 		}
-		if !found {
-			comp.Missing++
+		ecosystem := osvdb.EcosystemName(c.Ecosystem)
+		if ecosystem == "" {
+			continue
 		}
-	}
-
-	return comp
-}
-
-// comparePnpmLock parses pnpm-lock.yaml and compares with SBOM
-func comparePnpmLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "npm",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	file, err := os.Open(lockPath)
-	if err != nil {
-		return comp
-	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	// pnpm-lock.yaml v9 format: package@version: under packages:
-	// pnpm-lock.yaml v6 format: /package/version: under packages:
-	inPackages := false
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "packages:") {
-			inPackages = true
+		if !covered[versions.ParseEcosystem(c.Ecosystem)] {
+			uncovered = append(uncovered, c)
 			continue
 		}
 
-		if inPackages && !strings.HasPrefix(line, " ") && line != "" {
-			inPackages = false
+		name := osvPackageName(c)
+		vulns, err := db.Query(ctx, ecosystem, name, c.Version)
+		if err != nil {
+			return err
 		}
-
-		if inPackages {
-			line = strings.TrimSpace(line)
-			// v9 format: 'package@version':
-			if strings.HasSuffix(line, ":") && strings.Contains(line, "@") {
-				entry := strings.TrimSuffix(line, ":")
-				entry = strings.Trim(entry, "'\"")
-				// Handle scoped packages: @scope/name@version
-				atIdx := strings.LastIndex(entry, "@")
-				if atIdx > 0 {
-					name := entry[:atIdx]
-					version := entry[atIdx+1:]
-					lockPkgs[name] = version
-				}
-			}
-			// v6 format: /package/version:
-			if strings.HasPrefix(line, "/") && strings.HasSuffix(line, ":") {
-				entry := strings.TrimPrefix(line, "/")
-				entry = strings.TrimSuffix(entry, ":")
-				parts := strings.Split(entry, "/")
-				if len(parts) >= 2 {
-					// Handle scoped packages
-					if strings.HasPrefix(parts[0], "@") && len(parts) >= 3 {
-						name := parts[0] + "/" + parts[1]
-						version := parts[2]
-						lockPkgs[name] = version
-					} else {
-						name := parts[0]
-						version := parts[1]
-						lockPkgs[name] = version
-					}
-				}
-			}
+		for i := range vulns {
+			addVulnFinding(result, seen, osvFinding(&vulns[i], ecosystem, name, c.Version))
 		}
 	}
 
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
+	if len(uncovered) == 0 {
+		return nil
+	}
+	if !s.config.Vulns.APIFallback {
+		noun := "packages"
+		if len(uncovered) == 1 {
+			noun = "package"
 		}
+		result.Summary.UncheckedEcosystems = componentEcosystems(uncovered)
+		result.Summary.Error = fmt.Sprintf("OSV database has no %s data and api_fallback is off: %d %s not checked",
+			strings.Join(result.Summary.UncheckedEcosystems, ", "), len(uncovered), noun)
+		return nil
 	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
+	result.Summary.Sources = append(result.Summary.Sources, "osv-api")
+	if err := queryOSVAPI(ctx, uncovered, result, seen); err != nil {
+		if db == nil {
+			return err
 		}
+		result.Summary.UncheckedEcosystems = componentEcosystems(uncovered)
+		result.Summary.Error = fmt.Sprintf("%v: %s packages not checked", err, strings.Join(result.Summary.UncheckedEcosystems, ", "))
 	}
-
-	return comp
+	return nil
 }
 
-// comparePoetryLock parses poetry.lock (TOML) and compares with SBOM
-func comparePoetryLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "pypi",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	file, err := os.Open(lockPath)
-	if err != nil {
-		return comp
-	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	var currentName, currentVersion string
-	inPackage := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Start of package block
-		if line == "[[package]]" {
-			if currentName != "" && currentVersion != "" {
-				lockPkgs[strings.ToLower(strings.ReplaceAll(currentName, "_", "-"))] = currentVersion
-			}
-			currentName = ""
-			currentVersion = ""
-			inPackage = true
-			continue
-		}
-
-		if inPackage {
-			if strings.HasPrefix(line, "name = ") {
-				currentName = strings.Trim(strings.TrimPrefix(line, "name = "), "\"")
-			}
-			if strings.HasPrefix(line, "version = ") {
-				currentVersion = strings.Trim(strings.TrimPrefix(line, "version = "), "\"")
+// componentEcosystems returns the sorted OSV ecosystem names of components
+func componentEcosystems(components []Component) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, c := range components {
+		if name := osvdb.EcosystemName(c.Ecosystem); !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// osvBatchSize is the maximum number of queries per OSV batch request
+const osvBatchSize = 1000
+
+// queryOSVAPI looks up components through the OSV API. Batch queries only
+// return IDs, so full records are fetched once per vulnerability.
+func queryOSVAPI(ctx context.Context, components []Component, result *vulnsFeatureResult, seen map[string]bool) error {
+	client := liveapi.NewOSVClient()
+	records := make(map[string]*liveapi.Vulnerability)
+
+	for start := 0; start < len(components); start += osvBatchSize {
+		batch := components[start:min(start+osvBatchSize, len(components))]
+		queries := make([]liveapi.QueryRequest, len(batch))
+		for i, c := range batch {
+			queries[i] = liveapi.QueryRequest{
+				Package: &liveapi.PackageQuery{
+					Name:      osvPackageName(c),
+					Ecosystem: osvdb.EcosystemName(c.Ecosystem),
+				},
+				Version: c.Version,
 			}
 		}
-	}
-
-	// Don't forget the last package
-	if currentName != "" && currentVersion != "" {
-		lockPkgs[strings.ToLower(strings.ReplaceAll(currentName, "_", "-"))] = currentVersion
-	}
 
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		normalizedName := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
-		if _, ok := lockPkgs[normalizedName]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
+		responses, err := client.QueryBatch(ctx, queries)
+		if err != nil {
+			return fmt.Errorf("querying OSV API: %w", err)
 		}
-	}
-
-	for name := range lockPkgs {
-		found := false
-		for sbomName := range sbomPkgs {
-			if strings.ToLower(strings.ReplaceAll(sbomName, "_", "-")) == name {
-				found = true
+		for i, resp := range responses {
+			if i >= len(batch) {
 				break
 			}
-		}
-		if !found {
-			comp.Missing++
+			q := queries[i]
+			for _, v := range resp.Vulns {
+				vuln, ok := records[v.ID]
+				if !ok {
+					if vuln, err = client.GetVulnerability(ctx, v.ID); err != nil {
+						vuln = &v
+					}
+					records[v.ID] = vuln
+				}
+				addVulnFinding(result, seen, osvFinding(vuln, q.Package.Ecosystem, q.Package.Name, q.Version))
+			}
 		}
 	}
-
-	return comp
+	return nil
 }
 
-// compareUvLock parses uv.lock (TOML) and compares with SBOM
-func compareUvLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "pypi",
-		InSBOM:    len(sbomPkgs),
-	}
+// runOSVScanner runs osv-scanner against the SBOM (or the repository if
+// there is none)
+func runOSVScanner(ctx context.Context, opts *scanner.ScanOptions, sbomPath string, result *vulnsFeatureResult) {
+	result.Summary.Sources = append(result.Summary.Sources, "osv-scanner")
 
-	file, err := os.Open(lockPath)
-	if err != nil {
-		return comp
+	var cmdResult *common.CommandResult
+	if _, err := os.Stat(sbomPath); err == nil {
+		cmdResult, _ = common.RunCommand(ctx, "osv-scanner", "scan", "source", "--format=json", "-S", sbomPath)
+	} else {
+		cmdResult, _ = common.RunCommand(ctx, "osv-scanner", "scan", "source", "--format=json", "-r", opts.RepoPath)
 	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	var currentName, currentVersion string
-	inPackage := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Start of package block
-		if line == "[[package]]" {
-			if currentName != "" && currentVersion != "" {
-				lockPkgs[strings.ToLower(strings.ReplaceAll(currentName, "_", "-"))] = currentVersion
-			}
-			currentName = ""
-			currentVersion = ""
-			inPackage = true
-			continue
-		}
 
-		if inPackage {
-			if strings.HasPrefix(line, "name = ") {
-				currentName = strings.Trim(strings.TrimPrefix(line, "name = "), "\"")
-			}
-			if strings.HasPrefix(line, "version = ") {
-				currentVersion = strings.Trim(strings.TrimPrefix(line, "version = "), "\"")
-			}
-		}
+	if cmdResult == nil || len(cmdResult.Stdout) == 0 {
+		return
 	}
 
-	// Don't forget the last package
-	if currentName != "" && currentVersion != "" {
-		lockPkgs[strings.ToLower(strings.ReplaceAll(currentName, "_", "-"))] = currentVersion
+	// Parse output
+	var output struct {
+		Results []struct {
+			Packages []struct {
+				Package struct {
+					Name      string `json:"name"`
+					Version   string `json:"version"`
+					Ecosystem string `json:"ecosystem"`
+				} `json:"package"`
+				Vulnerabilities []liveapi.Vulnerability `json:"vulnerabilities"`
+			} `json:"packages"`
+		} `json:"results"`
 	}
 
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		normalizedName := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
-		if _, ok := lockPkgs[normalizedName]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
+	if json.Unmarshal(cmdResult.Stdout, &output) != nil {
+		return
 	}
 
-	for name := range lockPkgs {
-		found := false
-		for sbomName := range sbomPkgs {
-			if strings.ToLower(strings.ReplaceAll(sbomName, "_", "-")) == name {
-				found = true
-				break
+	seen := make(map[string]bool)
+	for _, r := range output.Results {
+		for _, pkg := range r.Packages {
+			for i := range pkg.Vulnerabilities {
+				addVulnFinding(result, seen, osvFinding(&pkg.Vulnerabilities[i], pkg.Package.Ecosystem, pkg.Package.Name, pkg.Package.Version))
 			}
 		}
-		if !found {
-			comp.Missing++
-		}
 	}
-
-	return comp
 }
 
-// compareCargoLock parses Cargo.lock (TOML) and compares with SBOM
-func compareCargoLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "cargo",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	file, err := os.Open(lockPath)
-	if err != nil {
-		return comp
-	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	var currentName, currentVersion string
-	inPackage := false
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Start of package block
-		if line == "[[package]]" {
-			if currentName != "" && currentVersion != "" {
-				lockPkgs[currentName] = currentVersion
-			}
-			currentName = ""
-			currentVersion = ""
-			inPackage = true
-			continue
-		}
-
-		if inPackage {
-			if strings.HasPrefix(line, "name = ") {
-				currentName = strings.Trim(strings.TrimPrefix(line, "name = "), "\"")
-			}
-			if strings.HasPrefix(line, "version = ") {
-				currentVersion = strings.Trim(strings.TrimPrefix(line, "version = "), "\"")
-			}
-		}
-	}
-
-	// Don't forget the last package
-	if currentName != "" && currentVersion != "" {
-		lockPkgs[currentName] = currentVersion
-	}
-
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
+// osvFinding converts an OSV record matched against a package version
+func osvFinding(vuln *liveapi.Vulnerability, ecosystem, name, version string) VulnFinding {
+	finding := VulnFinding{
+		ID:        vuln.ID,
+		Aliases:   vuln.Aliases,
+		Package:   name,
+		Version:   version,
+		Ecosystem: ecosystem,
+		Severity:  osvSeverity(vuln),
+		Title:     vuln.Summary,
+		FixedIn:   vuln.GetFixedVersion(ecosystem, name, version),
+		Symbols:   vuln.GetAffectedSymbols(ecosystem, name),
 	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
+	if vec := vuln.HighestCVSS(); vec != nil {
+		finding.CVSSScore = vec.Score()
+		finding.CVSSVector = vec.String()
 	}
-
-	return comp
+	return finding
 }
 
-// compareGemfileLock parses Gemfile.lock and compares with SBOM
-func compareGemfileLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "gem",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	file, err := os.Open(lockPath)
-	if err != nil {
-		return comp
+// osvSeverity rates a record's highest CVSS v3/v4 score, falling back to
+// the severity label advisory databases (e.g. GHSA) record
+func osvSeverity(vuln *liveapi.Vulnerability) string {
+	if vec := vuln.HighestCVSS(); vec != nil && vec.Score() > 0 {
+		return vec.Severity()
 	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	inSpecs := false
-	for scanner.Scan() {
-		line := scanner.Text()
 
-		// GEM section with specs
-		if strings.TrimSpace(line) == "specs:" {
-			inSpecs = true
-			continue
-		}
-
-		// End of specs section (blank line or new section)
-		if inSpecs && (line == "" || !strings.HasPrefix(line, "    ")) {
-			if !strings.HasPrefix(line, " ") {
-				inSpecs = false
-			}
-			continue
-		}
-
-		// Parse gem entries: "    gem-name (version)"
-		if inSpecs && strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "      ") {
-			line = strings.TrimSpace(line)
-			if idx := strings.Index(line, " ("); idx > 0 {
-				name := line[:idx]
-				version := strings.TrimSuffix(line[idx+2:], ")")
-				lockPkgs[name] = version
+	if specific, ok := vuln.DatabaseSpecific.(map[string]interface{}); ok {
+		if label, ok := specific["severity"].(string); ok {
+			switch strings.ToLower(label) {
+			case "critical":
+				return "critical"
+			case "high":
+				return "high"
+			case "moderate", "medium":
+				return "medium"
+			case "low":
+				return "low"
 			}
 		}
 	}
-
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
-	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
-	}
-
-	return comp
+	return "medium"
 }
 
-// compareComposerLock parses composer.lock (JSON) and compares with SBOM
-func compareComposerLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "composer",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	data, err := os.ReadFile(lockPath)
-	if err != nil {
-		return comp
-	}
-
-	var lockData struct {
-		Packages []struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-		} `json:"packages"`
-		PackagesDev []struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-		} `json:"packages-dev"`
+// addVulnFinding records a finding once per vulnerability and package version
+func addVulnFinding(result *vulnsFeatureResult, seen map[string]bool, finding VulnFinding) {
+	key := fmt.Sprintf("%s:%s:%s", finding.ID, finding.Package, finding.Version)
+	if seen[key] {
+		return
 	}
+	seen[key] = true
 
-	if err := json.Unmarshal(data, &lockData); err != nil {
-		return comp
+	result.Findings = append(result.Findings, finding)
+	result.Summary.TotalVulnerabilities++
+	switch finding.Severity {
+	case "critical":
+		result.Summary.Critical++
+	case "high":
+		result.Summary.High++
+	case "medium":
+		result.Summary.Medium++
+	case "low":
+		result.Summary.Low++
 	}
-
-	lockPkgs := make(map[string]string)
-
-	for _, pkg := range lockData.Packages {
-		// Composer versions often have "v" prefix
-		version := strings.TrimPrefix(pkg.Version, "v")
-		lockPkgs[pkg.Name] = version
-	}
-
-	for _, pkg := range lockData.PackagesDev {
-		version := strings.TrimPrefix(pkg.Version, "v")
-		lockPkgs[pkg.Name] = version
-	}
-
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
-	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
-	}
-
-	return comp
 }
 
-// compareNugetLock parses packages.lock.json (NuGet) and compares with SBOM
-func compareNugetLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "nuget",
-		InSBOM:    len(sbomPkgs),
-	}
-
-	data, err := os.ReadFile(lockPath)
-	if err != nil {
-		return comp
-	}
-
-	var lockData struct {
-		Version      int `json:"version"`
-		Dependencies map[string]map[string]struct {
-			Type     string `json:"type"`
-			Resolved string `json:"resolved"`
-		} `json:"dependencies"`
-	}
-
-	if err := json.Unmarshal(data, &lockData); err != nil {
-		return comp
-	}
-
-	lockPkgs := make(map[string]string)
-
-	// Dependencies are keyed by target framework, then by package name
-	for _, frameworkDeps := range lockData.Dependencies {
-		for name, pkg := range frameworkDeps {
-			lockPkgs[name] = pkg.Resolved
-		}
-	}
-
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
-	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
+// osvPackageName returns a component's name as OSV spells it: Maven
+// "group:artifact", and namespaced npm, Go and Packagist names with "/"
+func osvPackageName(c Component) string {
+	purl := strings.TrimPrefix(c.Purl, "pkg:")
+	if idx := strings.IndexAny(purl, "@?#"); idx >= 0 {
+		purl = purl[:idx]
 	}
-
-	return comp
-}
-
-// compareGradleLock parses gradle.lockfile and compares with SBOM
-func compareGradleLock(lockPath string, sbomPkgs map[string]string) LockfileComparison {
-	comp := LockfileComparison{
-		Lockfile:  filepath.Base(lockPath),
-		Ecosystem: "maven",
-		InSBOM:    len(sbomPkgs),
+	parts := strings.Split(purl, "/")
+	if len(parts) < 2 {
+		return c.Name
 	}
-
-	file, err := os.Open(lockPath)
+	name, err := url.PathUnescape(parts[len(parts)-1])
 	if err != nil {
-		return comp
-	}
-	defer file.Close()
-
-	lockPkgs := make(map[string]string)
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip comments and empty lines
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Skip the "empty=" line
-		if strings.HasPrefix(line, "empty=") {
-			continue
-		}
-
-		// Format: group:artifact:version=configuration1,configuration2
-		if idx := strings.Index(line, "="); idx > 0 {
-			coords := line[:idx]
-			parts := strings.Split(coords, ":")
-			if len(parts) >= 3 {
-				// group:artifact:version
-				name := parts[0] + ":" + parts[1]
-				version := parts[2]
-				lockPkgs[name] = version
-			}
-		}
-	}
-
-	comp.InLockfile = len(lockPkgs)
-
-	for name := range sbomPkgs {
-		if _, ok := lockPkgs[name]; ok {
-			comp.Matched++
-		} else {
-			comp.Extra++
-		}
+		return c.Name
 	}
-
-	for name := range lockPkgs {
-		if _, ok := sbomPkgs[name]; !ok {
-			comp.Missing++
-		}
+	namespace := purlNamespace(c.Purl)
+	switch {
+	case namespace == "":
+		return name
+	case c.Ecosystem == "maven":
+		return namespace + ":" + name
 	}
-
-	return comp
-}
-
-// GetSBOMPath returns the path to the SBOM file in the output directory
-// This is used by other scanners to locate the SBOM
-func GetSBOMPath(outputDir string) string {
-	return filepath.Join(outputDir, "sbom.cdx.json")
+	return namespace + "/" + name
 }
 
-// LoadSBOM loads and parses an existing SBOM file
-// This is used by other scanners to consume SBOM data
-func LoadSBOM(sbomPath string) (*GenerationFindings, error) {
-	bom, err := parseSBOM(sbomPath)
-	if err != nil {
-		return nil, err
-	}
-
-	components := make([]Component, 0, len(bom.Components))
-	for _, c := range bom.Components {
-		comp := Component{
-			Type:      c.Type,
-			Name:      c.Name,
-			Version:   c.Version,
-			Purl:      c.Purl,
-			Ecosystem: extractEcosystemFromPurl(c.Purl),
-			Scope:     c.Scope,
+// graphScope returns the SBOM scope of a package: "required" if any of its
+// instances ships, else the scope they share
+func graphScope(graph *cyclonedx.Graph, refs []string) string {
+	scope := ""
+	for _, ref := range refs {
+		c, ok := graph.Component(ref)
+		if !ok {
+			continue
 		}
-
-		for _, lic := range c.Licenses {
-			if lic.License.ID != "" {
-				comp.Licenses = append(comp.Licenses, lic.License.ID)
-			} else if lic.License.Name != "" {
-				comp.Licenses = append(comp.Licenses, lic.License.Name)
-			}
+		switch {
+		case c.Scope == "" || c.Scope == "required":
+			return "required"
+		case scope == "":
+			scope = c.Scope
 		}
-
-		components = append(components, comp)
-	}
-
-	dependencies := make([]Dependency, 0, len(bom.Dependencies))
-	for _, d := range bom.Dependencies {
-		dependencies = append(dependencies, Dependency{
-			Ref:       d.Ref,
-			DependsOn: d.DependsOn,
-		})
 	}
-
-	return &GenerationFindings{
-		Components:   components,
-		Dependencies: dependencies,
-		Metadata: &SBOMMetadata{
-			BomFormat:   bom.BomFormat,
-			SpecVersion: bom.SpecVersion,
-			Version:     bom.Version,
-		},
-	}, nil
-}
-
-// ==================== Vulns Feature ====================
-
-type vulnsFeatureResult struct {
-	Summary  *VulnsSummary
-	Findings []VulnFinding
+	return scope
 }
 
-func (s *SupplyChainScanner) runVulnsFeature(ctx context.Context, opts *scanner.ScanOptions, sbomPath string) *vulnsFeatureResult {
-	result := &vulnsFeatureResult{
-		Summary:  &VulnsSummary{},
-		Findings: []VulnFinding{},
+// useAPICache points the liveapi clients at the configured on-disk cache
+// and applies the per-endpoint TTL overrides
+func (s *SupplyChainScanner) useAPICache(opts *scanner.ScanOptions) {
+	cfg := s.config.APICache
+	if !cfg.Enabled {
+		liveapi.UseStore("")
+		return
 	}
-
-	if !common.ToolExists("osv-scanner") {
-		result.Summary.Error = "osv-scanner not installed"
-		return result
+	path := cfg.Path
+	if path == "" {
+		path = liveapi.DefaultStorePath(zeroHome(opts))
 	}
+	liveapi.UseStore(path)
 
-	// Run osv-scanner against the SBOM
-	var cmdResult *common.CommandResult
-	if _, err := os.Stat(sbomPath); err == nil {
-		cmdResult, _ = common.RunCommand(ctx, "osv-scanner", "scan", "source", "--format=json", "-S", sbomPath)
-	} else {
-		cmdResult, _ = common.RunCommand(ctx, "osv-scanner", "scan", "source", "--format=json", "-r", opts.RepoPath)
+	for endpoint, hours := range cfg.TTLHours {
+		p := liveapi.PolicyFor(endpoint)
+		p.TTL = time.Duration(hours) * time.Hour
+		liveapi.SetPolicy(endpoint, p)
 	}
-
-	if cmdResult == nil || len(cmdResult.Stdout) == 0 {
-		return result
+	for endpoint, hours := range cfg.StaleHours {
+		p := liveapi.PolicyFor(endpoint)
+		p.StaleFor = time.Duration(hours) * time.Hour
+		liveapi.SetPolicy(endpoint, p)
 	}
+}
 
-	// Parse output
-	var output struct {
-		Results []struct {
-			Packages []struct {
-				Package struct {
-					Name      string `json:"name"`
-					Version   string `json:"version"`
-					Ecosystem string `json:"ecosystem"`
-				} `json:"package"`
-				Vulnerabilities []struct {
-					ID       string   `json:"id"`
-					Aliases  []string `json:"aliases"`
-					Summary  string   `json:"summary"`
-					Severity []struct {
-						Type  string `json:"type"`
-						Score string `json:"score"`
-					} `json:"severity"`
-				} `json:"vulnerabilities"`
-			} `json:"packages"`
-		} `json:"results"`
+// cacheSummary describes how registry and API data was served, or returns
+// nil if no requests were made online
+func cacheSummary(stats liveapi.CacheStats) *CacheSummary {
+	offline := liveapi.Offline()
+	if !offline && stats == (liveapi.CacheStats{}) {
+		return nil
 	}
-
-	if json.Unmarshal(cmdResult.Stdout, &output) != nil {
-		return result
+	return &CacheSummary{
+		CacheStats:    stats,
+		Offline:       offline,
+		PossiblyStale: offline || stats.Stale > 0 || stats.Unavailable > 0,
 	}
+}
 
-	seen := make(map[string]bool)
-	for _, r := range output.Results {
-		for _, pkg := range r.Packages {
-			for _, vuln := range pkg.Vulnerabilities {
-				key := fmt.Sprintf("%s:%s:%s", vuln.ID, pkg.Package.Name, pkg.Package.Version)
-				if seen[key] {
-					continue
-				}
-				seen[key] = true
-
-				severity := "medium"
-				for _, sev := range vuln.Severity {
-					if sev.Type == "CVSS_V3" {
-						var score float64
-						_, _ = fmt.Sscanf(sev.Score, "%f", &score)
-						if score >= 9.0 {
-							severity = "critical"
-						} else if score >= 7.0 {
-							severity = "high"
-						} else if score >= 4.0 {
-							severity = "medium"
-						} else {
-							severity = "low"
-						}
-						break
-					}
-				}
-
-				result.Findings = append(result.Findings, VulnFinding{
-					ID:        vuln.ID,
-					Aliases:   vuln.Aliases,
-					Package:   pkg.Package.Name,
-					Version:   pkg.Package.Version,
-					Ecosystem: pkg.Package.Ecosystem,
-					Severity:  severity,
-					Title:     vuln.Summary,
-				})
-
-				result.Summary.TotalVulnerabilities++
-				switch severity {
-				case "critical":
-					result.Summary.Critical++
-				case "high":
-					result.Summary.High++
-				case "medium":
-					result.Summary.Medium++
-				case "low":
-					result.Summary.Low++
-				}
-			}
-		}
+// zeroHome returns the Zero home directory for shared data
+func zeroHome(opts *scanner.ScanOptions) string {
+	if opts.ZeroHome != "" {
+		return opts.ZeroHome
 	}
-
-	// KEV enrichment
-	if s.config.Vulns.IncludeKEV {
-		kevVulns := fetchKEV(ctx)
-		for i := range result.Findings {
-			if kevVulns[result.Findings[i].ID] {
-				result.Findings[i].InKEV = true
-				result.Summary.KEVCount++
-			} else {
-				for _, alias := range result.Findings[i].Aliases {
-					if kevVulns[alias] {
-						result.Findings[i].InKEV = true
-						result.Summary.KEVCount++
-						break
-					}
-				}
-			}
-		}
+	if home := os.Getenv("ZERO_HOME"); home != "" {
+		return home
 	}
-
-	return result
+	return ".zero"
 }
 
 func fetchKEV(ctx context.Context) map[string]bool {
 	vulns := make(map[string]bool)
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, _ := http.NewRequestWithContext(ctx, "GET", "https://www.cisa.gov/sites/default/files/feeds/known_exploited_vulnerabilities.json", nil)
-	resp, err := client.Do(req)
-	if err != nil {
-		return vulns
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return vulns
-	}
 	var catalog struct {
 		Vulnerabilities []struct {
 			CVEID string `json:"cveID"`
 		} `json:"vulnerabilities"`
 	}
-	if json.Unmarshal(body, &catalog) == nil {
+	if liveapi.NewKEVClient().CachedGet(ctx, liveapi.KEVCatalogPath, &catalog) == nil {
 		for _, v := range catalog.Vulnerabilities {
 			vulns[v.CVEID] = true
 		}
@@ -1924,7 +1754,8 @@ func (s *SupplyChainScanner) runHealthFeature(ctx context.Context, components []
 		latestVersion, err := depsClient.GetLatestVersion(ctx, pkg.Ecosystem, pkg.Name)
 		if err == nil {
 			finding.LatestVersion = latestVersion
-			finding.IsOutdated = latestVersion != "" && latestVersion != pkg.Version
+			finding.IsOutdated = latestVersion != "" &&
+				versions.Compare(versions.ParseEcosystem(pkg.Ecosystem), pkg.Version, latestVersion) < 0
 		}
 
 		// Get health score from scorecard
@@ -1965,107 +1796,231 @@ type licensesFeatureResult struct {
 	Findings []LicenseFinding
 }
 
-var (
-	allowedLicenses = map[string]bool{
-		"MIT": true, "Apache-2.0": true, "BSD-2-Clause": true, "BSD-3-Clause": true,
-		"ISC": true, "Unlicense": true, "CC0-1.0": true, "0BSD": true,
-	}
-	deniedLicenses = map[string]bool{
-		"GPL-2.0": true, "GPL-2.0-only": true, "GPL-3.0": true, "GPL-3.0-only": true,
-		"AGPL-3.0": true, "AGPL-3.0-only": true, "SSPL-1.0": true,
-	}
-)
-
-func (s *SupplyChainScanner) runLicensesFeature(sbomPath string) *licensesFeatureResult {
+func (s *SupplyChainScanner) runLicensesFeature(opts *scanner.ScanOptions, sbomPath string) *licensesFeatureResult {
 	result := &licensesFeatureResult{
-		Summary:  &LicensesSummary{LicenseCounts: make(map[string]int)},
+		Summary: &LicensesSummary{
+			LicenseCounts: make(map[string]int),
+			ByCategory:    make(map[string]int),
+		},
 		Findings: []LicenseFinding{},
 	}
 
-	data, err := os.ReadFile(sbomPath)
+	bom, err := parseSBOM(sbomPath)
 	if err != nil {
 		result.Summary.Error = err.Error()
 		return result
 	}
 
-	var sbomDoc struct {
-		Components []struct {
-			Name     string `json:"name"`
-			Version  string `json:"version"`
-			Purl     string `json:"purl"`
-			Licenses []struct {
-				License struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"license"`
-				Expression string `json:"expression"`
-			} `json:"licenses"`
-		} `json:"components"`
+	policy := licensePolicy(s.config.Licenses)
+	result.Summary.Distribution = string(policy.Distribution)
+	graph := loadDependencyGraph(sbomPath)
+	uniqueLicenses := make(map[string]bool)
+
+	for _, c := range bom.Components {
+		result.Summary.TotalPackages++
+
+		expression := componentLicenseExpression(c.Licenses)
+		eval := policy.Evaluate(expression)
+
+		finding := LicenseFinding{
+			Package:   c.Name,
+			Version:   c.Version,
+			Ecosystem: extractEcosystem(c.Purl),
+			Scope:     c.Scope,
+			Category:  string(eval.Category),
+			Reason:    eval.Reason,
+		}
+		result.Summary.ByCategory[string(eval.Category)]++
+
+		if expression == "" {
+			finding.Status = "unknown"
+			result.Summary.Unknown++
+		} else {
+			finding.Expression = eval.Expression
+			finding.Licenses = eval.Licenses
+			for _, lic := range eval.Licenses {
+				uniqueLicenses[lic] = true
+				result.Summary.LicenseCounts[lic]++
+			}
+			switch eval.Decision {
+			case licenses.Allow:
+				finding.Status = "allowed"
+				result.Summary.Allowed++
+			case licenses.Deny:
+				finding.Status = "denied"
+				result.Summary.Denied++
+			default:
+				finding.Status = "review"
+				result.Summary.NeedsReview++
+			}
+		}
+		if eval.Decision == licenses.Deny {
+			result.Summary.PolicyViolations++
+		}
+		if eval.Decision != licenses.Allow {
+			finding.DependencyPath, finding.DependencyPaths = graph.Paths(c.BomRef)
+		}
+		result.Findings = append(result.Findings, finding)
+	}
+	result.Summary.UniqueLicenses = len(uniqueLicenses)
+
+	if s.config.Licenses.Notices && opts.OutputDir != "" {
+		if _, err := writeNoticesFile(filepath.Join(opts.OutputDir, licenses.NoticesFile), bom, opts.RepoPath); err != nil {
+			result.Summary.Error = err.Error()
+		} else {
+			result.Summary.NoticesFile = licenses.NoticesFile
+		}
+	}
+
+	return result
+}
+
+// licensePolicy builds the license policy from the feature configuration,
+// starting from the distribution model's category defaults
+func licensePolicy(cfg LicensesConfig) licenses.Policy {
+	policy := licenses.DefaultPolicy(licenses.ParseDistribution(cfg.Distribution))
+	policy.Allow = cfg.AllowedList
+	policy.Deny = cfg.BlockedList
+	policy.Review = cfg.ReviewList
+	if cfg.FailOnUnknown {
+		policy.Categories[licenses.Unknown] = licenses.Deny
+	}
+	for category, decision := range cfg.Categories {
+		switch d := licenses.Decision(strings.ToLower(decision)); d {
+		case licenses.Allow, licenses.Review, licenses.Deny:
+			policy.Categories[licenses.Category(category)] = d
+		}
+	}
+	return policy
+}
+
+// componentLicenseExpression combines a component's CycloneDX license
+// entries into one SPDX expression; multiple entries must all be met
+func componentLicenseExpression(entries []cdxLicense) string {
+	parts := make([]licenses.Entry, 0, len(entries))
+	for _, lic := range entries {
+		parts = append(parts, licenses.Entry{ID: lic.License.ID, Name: lic.License.Name, Expression: lic.Expression})
+	}
+	return licenses.Combine(parts)
+}
+
+// loadDependencyGraph reads the SBOM's dependency graph. SBOMs that cannot
+// be read give an empty graph, so findings simply carry no paths.
+func loadDependencyGraph(sbomPath string) *cyclonedx.Graph {
+	bom, err := cyclonedx.ReadFile(sbomPath)
+	if err != nil {
+		bom = &cyclonedx.BOM{}
+	}
+	return cyclonedx.NewGraph(bom)
+}
+
+// dependencyGraph returns the SBOM's dependency graph as adjacency lists
+// and the project's direct dependencies: those of the project component,
+// or every top-level component when the SBOM has no project node in its
+// graph
+func dependencyGraph(bom *CycloneDXBOM) (map[string][]string, []string) {
+	graph := make(map[string][]string, len(bom.Dependencies))
+	dependedOn := make(map[string]bool)
+	for _, dep := range bom.Dependencies {
+		graph[dep.Ref] = append(graph[dep.Ref], dep.DependsOn...)
+		for _, ref := range dep.DependsOn {
+			dependedOn[ref] = true
+		}
+	}
+
+	if root := bom.Metadata.Component.BomRef; root != "" && len(graph[root]) > 0 {
+		return graph, graph[root]
+	}
+	components := make(map[string]bool, len(bom.Components))
+	for _, c := range bom.Components {
+		components[c.BomRef] = true
+	}
+	var direct []string
+	for _, dep := range bom.Dependencies {
+		if !dependedOn[dep.Ref] && components[dep.Ref] {
+			direct = append(direct, dep.Ref)
+		}
+	}
+	return graph, direct
+}
+
+// packageLicenseText reads the license file shipped with an installed
+// package, from node_modules for npm and vendor for Go modules
+func packageLicenseText(repoPath string, c Component) string {
+	if repoPath == "" {
+		return ""
+	}
+	var dir string
+	switch c.Ecosystem {
+	case "npm":
+		dir = filepath.Join(repoPath, "node_modules", filepath.FromSlash(osvPackageName(c)))
+	case "golang":
+		dir = filepath.Join(repoPath, "vendor", filepath.FromSlash(osvPackageName(c)))
+	default:
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		name := strings.ToUpper(entry.Name())
+		if entry.IsDir() || !(strings.HasPrefix(name, "LICENSE") || strings.HasPrefix(name, "LICENCE") || strings.HasPrefix(name, "COPYING")) {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(f, 64*1024))
+		f.Close()
+		if err == nil && len(data) > 0 {
+			return string(data)
+		}
 	}
+	return ""
+}
 
-	if json.Unmarshal(data, &sbomDoc) != nil {
-		return result
+// WriteNotices writes a THIRD_PARTY_NOTICES file for the packages in a
+// CycloneDX SBOM, including the license files of packages installed in
+// repoPath. Dev dependencies are left out since they are not shipped.
+// It returns the number of packages attributed.
+func WriteNotices(w io.Writer, sbomPath, repoPath string) (int, error) {
+	bom, err := parseSBOM(sbomPath)
+	if err != nil {
+		return 0, err
 	}
+	return writeNotices(w, bom, repoPath)
+}
 
-	uniqueLicenses := make(map[string]bool)
-
-	for _, c := range sbomDoc.Components {
-		result.Summary.TotalPackages++
-
-		var licenseIDs []string
-		for _, lic := range c.Licenses {
-			if lic.License.ID != "" {
-				licenseIDs = append(licenseIDs, lic.License.ID)
-			} else if lic.License.Name != "" {
-				licenseIDs = append(licenseIDs, lic.License.Name)
-			} else if lic.Expression != "" {
-				licenseIDs = append(licenseIDs, lic.Expression)
-			}
-		}
-
-		status := "unknown"
-		if len(licenseIDs) == 0 {
-			result.Summary.Unknown++
-		} else {
-			hasDenied := false
-			allAllowed := true
-
-			for _, lic := range licenseIDs {
-				uniqueLicenses[lic] = true
-				result.Summary.LicenseCounts[lic]++
-
-				if deniedLicenses[lic] {
-					hasDenied = true
-					allAllowed = false
-				} else if !allowedLicenses[lic] {
-					allAllowed = false
-				}
-			}
-
-			if hasDenied {
-				status = "denied"
-				result.Summary.Denied++
-				result.Summary.PolicyViolations++
-			} else if allAllowed {
-				status = "allowed"
-				result.Summary.Allowed++
-			} else {
-				status = "review"
-				result.Summary.NeedsReview++
-			}
+func writeNotices(w io.Writer, bom *CycloneDXBOM, repoPath string) (int, error) {
+	var packages []licenses.NoticePackage
+	for _, c := range bom.Components {
+		if c.Scope == "optional" || c.Scope == "excluded" {
+			continue
 		}
-
-		result.Findings = append(result.Findings, LicenseFinding{
-			Package:   c.Name,
-			Version:   c.Version,
-			Ecosystem: extractEcosystem(c.Purl),
-			Licenses:  licenseIDs,
-			Status:    status,
+		packages = append(packages, licenses.NoticePackage{
+			Name:        c.Name,
+			Version:     c.Version,
+			Purl:        c.Purl,
+			Expression:  componentLicenseExpression(c.Licenses),
+			LicenseText: packageLicenseText(repoPath, Component{Name: c.Name, Ecosystem: extractEcosystem(c.Purl), Purl: c.Purl}),
 		})
 	}
+	return len(packages), licenses.WriteNotices(w, bom.Metadata.Component.Name, packages)
+}
 
-	result.Summary.UniqueLicenses = len(uniqueLicenses)
-	return result
+func writeNoticesFile(path string, bom *CycloneDXBOM, repoPath string) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("creating notices file: %w", err)
+	}
+	n, err := writeNotices(f, bom, repoPath)
+	if err != nil {
+		f.Close()
+		return 0, fmt.Errorf("writing notices file: %w", err)
+	}
+	return n, f.Close()
 }
 
 // ==================== Malcontent Feature ====================
@@ -2075,26 +2030,55 @@ type malcontentFeatureResult struct {
 	Findings []MalcontentFinding
 }
 
-func (s *SupplyChainScanner) runMalcontentFeature(ctx context.Context, opts *scanner.ScanOptions) *malcontentFeatureResult {
+// runMalcontentFeature looks for malicious packages with malcontent, the
+// builtin install-script and package-behavior heuristics, or both
+func (s *SupplyChainScanner) runMalcontentFeature(ctx context.Context, opts *scanner.ScanOptions, sbomPath string) *malcontentFeatureResult {
 	result := &malcontentFeatureResult{
 		Summary:  &MalcontentSummary{},
 		Findings: []MalcontentFinding{},
 	}
 
-	if !common.ToolExists("mal") {
-		result.Summary.Error = "malcontent not installed (brew install malcontent)"
-		return result
-	}
-
 	minRisk := s.config.Malcontent.MinRiskLevel
 	if minRisk == "" {
 		minRisk = "medium"
 	}
 
+	malInstalled := common.ToolExists("mal")
+	var useMal, useBuiltin bool
+	switch engine := s.config.Malcontent.Engine; engine {
+	case "", "auto":
+		useMal, useBuiltin = malInstalled, !malInstalled
+	case "mal":
+		useMal = true
+	case "builtin":
+		useBuiltin = true
+	case "both":
+		useMal, useBuiltin = true, true
+	default:
+		result.Summary.Error = fmt.Sprintf("unknown engine %q (auto, mal, builtin, both)", engine)
+		return result
+	}
+
+	graph := loadDependencyGraph(sbomPath)
+	if useMal {
+		if malInstalled {
+			runMal(ctx, opts, graph, minRisk, result)
+		} else {
+			result.Summary.Error = "malcontent not installed (brew install malcontent)"
+		}
+	}
+	if useBuiltin {
+		s.runBuiltinBehavior(ctx, opts, graph, minRisk, result)
+	}
+	return result
+}
+
+// runMal runs malcontent over the repository
+func runMal(ctx context.Context, opts *scanner.ScanOptions, graph *cyclonedx.Graph, minRisk string, result *malcontentFeatureResult) {
 	cmdResult, err := common.RunCommand(ctx, "mal", "analyze", "--format=json", "--min-file-risk="+minRisk, opts.RepoPath)
 	if err != nil || cmdResult == nil {
 		result.Summary.Error = "malcontent execution failed"
-		return result
+		return
 	}
 
 	var output struct {
@@ -2109,10 +2093,11 @@ func (s *SupplyChainScanner) runMalcontentFeature(ctx context.Context, opts *sca
 	}
 
 	if json.Unmarshal(cmdResult.Stdout, &output) != nil {
-		return result
+		return
 	}
 
-	result.Summary.TotalFiles = len(output.Files)
+	result.Summary.Engines = append(result.Summary.Engines, "mal")
+	result.Summary.TotalFiles += len(output.Files)
 
 	for path, file := range output.Files {
 		if file.RiskScore == 0 {
@@ -2123,28 +2108,127 @@ func (s *SupplyChainScanner) runMalcontentFeature(ctx context.Context, opts *sca
 		var behaviors []string
 		for _, b := range file.Behaviors {
 			behaviors = append(behaviors, b.Description)
-			result.Summary.TotalFindings++
-			switch strings.ToLower(b.RiskLevel) {
-			case "critical":
-				result.Summary.Critical++
-			case "high":
-				result.Summary.High++
-			case "medium":
-				result.Summary.Medium++
-			case "low":
-				result.Summary.Low++
+			result.Summary.countBehavior(b.RiskLevel)
+		}
+
+		finding := malcontentFinding(graph, opts.RepoPath, path, file.RiskLevel, file.RiskScore, behaviors)
+		finding.Engine = "mal"
+		result.Findings = append(result.Findings, finding)
+	}
+}
+
+// runBuiltinBehavior runs the builtin heuristics over the installed
+// dependency trees, package archives in the repository and the configured
+// package manager caches. It needs neither malcontent nor network access.
+func (s *SupplyChainScanner) runBuiltinBehavior(ctx context.Context, opts *scanner.ScanOptions, graph *cyclonedx.Graph, minRisk string, result *malcontentFeatureResult) {
+	var cacheDirs []string
+	for _, dir := range s.config.Malcontent.CacheDirs {
+		if dir == "~" || strings.HasPrefix(dir, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				continue
 			}
+			dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+		} else if !filepath.IsAbs(dir) {
+			dir = filepath.Join(opts.RepoPath, dir)
 		}
+		cacheDirs = append(cacheDirs, dir)
+	}
 
-		result.Findings = append(result.Findings, MalcontentFinding{
-			File:      path,
-			Risk:      file.RiskLevel,
-			RiskScore: file.RiskScore,
-			Behaviors: behaviors,
-		})
+	scan, err := behavior.Scan(ctx, opts.RepoPath, behavior.Options{CacheDirs: cacheDirs})
+	if err != nil {
+		result.Summary.Error = fmt.Sprintf("builtin analysis failed: %v", err)
+		return
 	}
 
-	return result
+	result.Summary.Engines = append(result.Summary.Engines, "builtin")
+	result.Summary.TotalFiles += scan.Files
+	result.Summary.PackagesInspected = scan.Packages
+
+	threshold := behavior.Score(minRisk)
+	for _, f := range scan.Findings {
+		if f.RiskScore < threshold {
+			continue
+		}
+		result.Summary.FilesWithRisk++
+		for _, e := range f.Evidence {
+			result.Summary.countBehavior(e.Risk)
+		}
+		result.Findings = append(result.Findings, behaviorFinding(graph, opts.RepoPath, f))
+	}
+}
+
+// countBehavior counts one behavior of the given risk level
+func (m *MalcontentSummary) countBehavior(risk string) {
+	m.TotalFindings++
+	switch strings.ToLower(risk) {
+	case "critical":
+		m.Critical++
+	case "high":
+		m.High++
+	case "medium":
+		m.Medium++
+	case "low":
+		m.Low++
+	}
+}
+
+// behaviorFinding converts a builtin finding. Files in archives and caches
+// cannot be attributed by path, so the package the heuristics identified is
+// looked up in the dependency graph instead.
+func behaviorFinding(graph *cyclonedx.Graph, repoPath string, f behavior.Finding) MalcontentFinding {
+	path := f.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoPath, filepath.FromSlash(path))
+	}
+	behaviors := make([]string, 0, len(f.Evidence))
+	for _, e := range f.Evidence {
+		behaviors = append(behaviors, e.Description)
+	}
+
+	finding := malcontentFinding(graph, repoPath, path, f.Risk, f.RiskScore, behaviors)
+	finding.Engine = "builtin"
+	finding.Hook = f.Hook
+	finding.Evidence = f.Evidence
+	if finding.Package == "" && f.Package != "" {
+		finding.Package = f.Package
+		finding.Version = f.Version
+		finding.Ecosystem = f.Ecosystem
+		if refs := graph.Lookup(f.Ecosystem, f.Package, f.Version); len(refs) > 0 {
+			finding.DependencyPath, finding.DependencyPaths = graph.Paths(refs...)
+		}
+	}
+	return finding
+}
+
+// malcontentFinding builds a malcontent finding, attributing files inside
+// installed packages to the package and how it is pulled in
+func malcontentFinding(graph *cyclonedx.Graph, repoPath, path, risk string, score int, behaviors []string) MalcontentFinding {
+	finding := MalcontentFinding{
+		File:      path,
+		Risk:      risk,
+		RiskScore: score,
+		Behaviors: behaviors,
+	}
+	if rel, err := filepath.Rel(repoPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = rel
+	}
+	refs := graph.LookupFile(path)
+	if len(refs) == 0 {
+		return finding
+	}
+	c, _ := graph.Component(refs[0])
+	finding.Package = cyclonedx.PackageName(c)
+	finding.Version = c.Version
+	finding.Ecosystem = extractEcosystem(c.Purl)
+	// Nested installs of different versions cannot be told apart by path
+	for _, ref := range refs[1:] {
+		if other, _ := graph.Component(ref); other.Version != c.Version {
+			finding.Version = ""
+		}
+	}
+	finding.DependencyPath, finding.DependencyPaths = graph.Paths(refs...)
+	return finding
 }
 
 // ==================== Confusion Feature ====================
@@ -2160,7 +2244,8 @@ func (s *SupplyChainScanner) runConfusionFeature(ctx context.Context, opts *scan
 		Findings: []ConfusionFinding{},
 	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
+	npmClient := liveapi.NewNPMClient()
+	pypiClient := liveapi.NewPyPIClient()
 
 	// Check npm
 	if s.config.Confusion.CheckNPM {
@@ -2185,7 +2270,7 @@ func (s *SupplyChainScanner) runConfusionFeature(ctx context.Context, opts *scan
 
 			for name, version := range allDeps {
 				if looksInternal(name) {
-					exists, pubVersion := checkNPMPackage(ctx, client, name)
+					exists, pubVersion := checkNPMPackage(ctx, npmClient, name)
 					if exists {
 						result.Findings = append(result.Findings, ConfusionFinding{
 							Package:       name,
@@ -2224,7 +2309,7 @@ func (s *SupplyChainScanner) runConfusionFeature(ctx context.Context, opts *scan
 				}
 				matches := pkgRegex.FindStringSubmatch(line)
 				if len(matches) >= 2 && looksInternal(matches[1]) {
-					exists, pubVersion := checkPyPIPackage(ctx, client, matches[1])
+					exists, pubVersion := checkPyPIPackage(ctx, pypiClient, matches[1])
 					if exists {
 						result.Findings = append(result.Findings, ConfusionFinding{
 							Package:       matches[1],
@@ -2260,51 +2345,25 @@ func looksInternal(name string) bool {
 	return false
 }
 
-func checkNPMPackage(ctx context.Context, client *http.Client, name string) (bool, string) {
-	req, _ := http.NewRequestWithContext(ctx, "GET", "https://registry.npmjs.org/"+name, nil)
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
+func checkNPMPackage(ctx context.Context, client *liveapi.Client, name string) (bool, string) {
 	var pkg struct {
 		DistTags struct {
 			Latest string `json:"latest"`
 		} `json:"dist-tags"`
 	}
-	if json.Unmarshal(body, &pkg) != nil {
+	if client.CachedGet(ctx, "/"+name, &pkg) != nil {
 		return false, ""
 	}
 	return true, pkg.DistTags.Latest
 }
 
-func checkPyPIPackage(ctx context.Context, client *http.Client, name string) (bool, string) {
-	req, _ := http.NewRequestWithContext(ctx, "GET", "https://pypi.org/pypi/"+name+"/json", nil)
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
+func checkPyPIPackage(ctx context.Context, client *liveapi.Client, name string) (bool, string) {
 	var pkg struct {
 		Info struct {
 			Version string `json:"version"`
 		} `json:"info"`
 	}
-	if json.Unmarshal(body, &pkg) != nil {
+	if client.CachedGet(ctx, "/"+name+"/json", &pkg) != nil {
 		return false, ""
 	}
 	return true, pkg.Info.Version
@@ -2317,16 +2376,32 @@ type reachabilityFeatureResult struct {
 	Findings []ReachabilityFinding
 }
 
-func (s *SupplyChainScanner) runReachabilityFeature(ctx context.Context, opts *scanner.ScanOptions) *reachabilityFeatureResult {
+func (s *SupplyChainScanner) runReachabilityFeature(ctx context.Context, opts *scanner.ScanOptions, vulns []VulnFinding) *reachabilityFeatureResult {
 	result := &reachabilityFeatureResult{
 		Summary:  &ReachabilitySummary{},
 		Findings: []ReachabilityFinding{},
 	}
 
-	if !common.ToolExists("osv-scanner") {
-		result.Summary.Error = "osv-scanner not installed"
-		return result
+	// JavaScript/TypeScript and Java are analyzed natively from the
+	// project's imports; osv-scanner's call analysis covers the rest
+	var jsVulns, javaVulns []VulnFinding
+	for _, v := range vulns {
+		switch versions.ParseEcosystem(v.Ecosystem) {
+		case versions.NPM:
+			jsVulns = append(jsVulns, v)
+		case versions.Maven:
+			javaVulns = append(javaVulns, v)
+		}
+	}
+	if len(jsVulns) > 0 {
+		result.Summary.Ecosystems = append(result.Summary.Ecosystems, "npm")
+		result.add(analyzeJSReachability(ctx, opts.RepoPath, jsVulns)...)
+	}
+	if len(javaVulns) > 0 {
+		result.Summary.Ecosystems = append(result.Summary.Ecosystems, "maven")
+		result.add(analyzeJavaReachability(ctx, opts.RepoPath, javaVulns)...)
 	}
+	result.Summary.Supported = len(result.Summary.Ecosystems) > 0
 
 	// Detect ecosystem
 	ecosystem := ""
@@ -2339,14 +2414,22 @@ func (s *SupplyChainScanner) runReachabilityFeature(ctx context.Context, opts *s
 	}
 
 	if ecosystem == "" {
-		result.Summary.Supported = false
+		result.finish()
+		return result
+	}
+
+	if !common.ToolExists("osv-scanner") {
+		result.Summary.Error = "osv-scanner not installed"
+		result.finish()
 		return result
 	}
 
 	result.Summary.Supported = true
+	result.Summary.Ecosystems = append(result.Summary.Ecosystems, strings.ToLower(ecosystem))
 
 	cmdResult, _ := common.RunCommand(ctx, "osv-scanner", "scan", "--format", "json", "--experimental-call-analysis", opts.RepoPath)
 	if cmdResult == nil || len(cmdResult.Stdout) == 0 {
+		result.finish()
 		return result
 	}
 
@@ -2354,8 +2437,9 @@ func (s *SupplyChainScanner) runReachabilityFeature(ctx context.Context, opts *s
 		Results []struct {
 			Packages []struct {
 				Package struct {
-					Name    string `json:"name"`
-					Version string `json:"version"`
+					Name      string `json:"name"`
+					Version   string `json:"version"`
+					Ecosystem string `json:"ecosystem"`
 				} `json:"package"`
 				Vulnerabilities []struct {
 					ID       string `json:"id"`
@@ -2369,50 +2453,63 @@ func (s *SupplyChainScanner) runReachabilityFeature(ctx context.Context, opts *s
 	}
 
 	if json.Unmarshal(cmdResult.Stdout, &output) != nil {
+		result.finish()
 		return result
 	}
 
 	for _, r := range output.Results {
 		for _, pkg := range r.Packages {
+			switch versions.ParseEcosystem(pkg.Package.Ecosystem) {
+			case versions.NPM, versions.Maven:
+				continue // Analyzed natively above
+			}
 			for _, vuln := range pkg.Vulnerabilities {
-				status := "unknown"
-				reachable := true
+				finding := ReachabilityFinding{
+					ID:        vuln.ID,
+					Package:   pkg.Package.Name,
+					Version:   pkg.Package.Version,
+					Ecosystem: pkg.Package.Ecosystem,
+					Summary:   vuln.Summary,
+				}
+				finding.setStatus("unknown")
 				if vuln.Analysis != nil {
+					finding.Method = "call-analysis"
 					if vuln.Analysis.Called {
-						status = "reachable"
+						finding.setStatus("reachable")
 					} else {
-						status = "unreachable"
-						reachable = false
+						finding.setStatus("unreachable")
 					}
 				}
-
-				result.Findings = append(result.Findings, ReachabilityFinding{
-					ID:                 vuln.ID,
-					Package:            pkg.Package.Name,
-					Version:            pkg.Package.Version,
-					Summary:            vuln.Summary,
-					ReachabilityStatus: status,
-					Reachable:          reachable,
-				})
-
-				result.Summary.TotalVulns++
-				switch status {
-				case "reachable":
-					result.Summary.ReachableVulns++
-				case "unreachable":
-					result.Summary.UnreachableVulns++
-				default:
-					result.Summary.UnknownReachability++
-				}
+				result.add(finding)
 			}
 		}
 	}
 
-	if result.Summary.TotalVulns > 0 {
-		result.Summary.ReductionPercent = float64(result.Summary.UnreachableVulns) / float64(result.Summary.TotalVulns) * 100
+	result.finish()
+	return result
+}
+
+// add records findings in the summary counts
+func (r *reachabilityFeatureResult) add(findings ...ReachabilityFinding) {
+	for _, f := range findings {
+		r.Findings = append(r.Findings, f)
+		r.Summary.TotalVulns++
+		switch f.ReachabilityStatus {
+		case "reachable":
+			r.Summary.ReachableVulns++
+		case "unreachable":
+			r.Summary.UnreachableVulns++
+		default:
+			r.Summary.UnknownReachability++
+		}
 	}
+}
 
-	return result
+// finish computes the share of vulnerabilities ruled out
+func (r *reachabilityFeatureResult) finish() {
+	if r.Summary.TotalVulns > 0 {
+		r.Summary.ReductionPercent = float64(r.Summary.UnreachableVulns) / float64(r.Summary.TotalVulns) * 100
+	}
 }
 
 // ==================== Provenance Feature ====================
@@ -2525,18 +2622,56 @@ type recommendationsFeatureResult struct {
 	Findings []RecommendationFinding
 }
 
-func (s *SupplyChainScanner) runRecommendationsFeature(scanResult *Result) *recommendationsFeatureResult {
+// recommendationsInput is what recommendations read from the scan result.
+// It is copied before the feature starts: a feature abandoned at its
+// deadline keeps running while the scan goes on to write the result.
+type recommendationsInput struct {
+	criticalVulns int
+	deprecated    int
+	vulns         []VulnFinding
+}
+
+func newRecommendationsInput(scanResult *Result) recommendationsInput {
+	var input recommendationsInput
+	if scanResult.Summary.Vulns != nil {
+		input.criticalVulns = scanResult.Summary.Vulns.Critical
+	}
+	if scanResult.Summary.Health != nil {
+		input.deprecated = scanResult.Summary.Health.DeprecatedCount
+	}
+	vulns, _ := scanResult.Findings.Vulns.([]VulnFinding)
+	input.vulns = slices.Clone(vulns)
+	return input
+}
+
+func (s *SupplyChainScanner) runRecommendationsFeature(ctx context.Context, sbomPath string, input recommendationsInput) *recommendationsFeatureResult {
 	result := &recommendationsFeatureResult{
 		Summary:  &RecommendationsSummary{},
 		Findings: []RecommendationFinding{},
 	}
 
 	// Generate recommendations based on vulns and health data
-	if scanResult.Summary.Vulns != nil && scanResult.Summary.Vulns.Critical > 0 {
-		result.Summary.SecurityRecommendations = scanResult.Summary.Vulns.Critical
+	if input.criticalVulns > 0 {
+		result.Summary.SecurityRecommendations = input.criticalVulns
 	}
-	if scanResult.Summary.Health != nil && scanResult.Summary.Health.DeprecatedCount > 0 {
-		result.Summary.HealthRecommendations = scanResult.Summary.Health.DeprecatedCount
+	if input.deprecated > 0 {
+		result.Summary.HealthRecommendations = input.deprecated
+	}
+
+	// Turn vulnerabilities into an upgrade plan when the dependency graph is available
+	vulns := input.vulns
+	if len(vulns) > 0 && sbomPath != "" {
+		bom, err := parseSBOM(sbomPath)
+		if err != nil {
+			result.Summary.Error = err.Error()
+		} else {
+			plan := planUpgrades(ctx, bom, vulns, s.config.Recommendations.ResolveTransitive)
+			result.Summary.UpgradePlan = &plan.Summary
+			result.Summary.SecurityRecommendations = len(plan.Upgrades)
+			for _, u := range plan.Upgrades {
+				result.Findings = append(result.Findings, upgradeRecommendation(u))
+			}
+		}
 	}
 
 	result.Summary.TotalRecommendations = result.Summary.SecurityRecommendations + result.Summary.HealthRecommendations
@@ -2544,6 +2679,134 @@ func (s *SupplyChainScanner) runRecommendationsFeature(scanResult *Result) *reco
 	return result
 }
 
+// PlanUpgrades computes the upgrade plan for a scanned repository from the
+// vulnerabilities in code-packages.json and the graph in sbom.cdx.json.
+// With resolve, deps.dev is asked which direct dependency versions pull in
+// fixed transitive dependencies.
+func PlanUpgrades(ctx context.Context, analysisDir string, resolve bool) (*upgrades.Plan, error) {
+	data, err := os.ReadFile(filepath.Join(analysisDir, Name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading scan results: %w", err)
+	}
+	var scanResult struct {
+		Findings struct {
+			Vulns []VulnFinding `json:"vulns"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &scanResult); err != nil {
+		return nil, fmt.Errorf("parsing scan results: %w", err)
+	}
+
+	bom, err := parseSBOM(GetSBOMPath(analysisDir))
+	if err != nil {
+		return nil, err
+	}
+	return planUpgrades(ctx, bom, scanResult.Findings.Vulns, resolve), nil
+}
+
+func planUpgrades(ctx context.Context, bom *CycloneDXBOM, vulns []VulnFinding, resolve bool) *upgrades.Plan {
+	planVulns := make([]upgrades.Vulnerability, 0, len(vulns))
+	for _, v := range vulns {
+		planVulns = append(planVulns, upgrades.Vulnerability{
+			ID:        v.ID,
+			Package:   v.Package,
+			Version:   v.Version,
+			Ecosystem: v.Ecosystem,
+			Severity:  v.Severity,
+			FixedIn:   v.FixedIn,
+		})
+	}
+
+	var resolver upgrades.Resolver
+	if resolve {
+		resolver = upgrades.NewDepsDevResolver(liveapi.NewDepsDevClient())
+	}
+	return upgrades.Build(ctx, upgradeDependencies(bom), planVulns, resolver)
+}
+
+// upgradeDependencies converts the SBOM's components for the upgrade
+// planner, marking direct dependencies and the direct dependencies each
+// transitive one is pulled in by. SBOMs without a dependency graph give no
+// dependencies, and the planner treats every vulnerable package as direct.
+func upgradeDependencies(bom *CycloneDXBOM) []upgrades.Dependency {
+	graph, direct := dependencyGraph(bom)
+	if len(direct) == 0 {
+		return nil
+	}
+
+	names := make(map[string]string, len(bom.Components))
+	for _, c := range bom.Components {
+		names[c.BomRef] = osvPackageName(Component{Name: c.Name, Ecosystem: extractEcosystem(c.Purl), Purl: c.Purl})
+	}
+
+	isDirect := make(map[string]bool, len(direct))
+	via := make(map[string][]string)
+	for _, root := range direct {
+		isDirect[root] = true
+		seen := map[string]bool{root: true}
+		queue := []string{root}
+		for len(queue) > 0 {
+			ref := queue[0]
+			queue = queue[1:]
+			for _, next := range graph[ref] {
+				if seen[next] {
+					continue
+				}
+				seen[next] = true
+				via[next] = append(via[next], names[root])
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	deps := make([]upgrades.Dependency, 0, len(bom.Components))
+	for _, c := range bom.Components {
+		d := upgrades.Dependency{
+			Name:      names[c.BomRef],
+			Version:   c.Version,
+			Ecosystem: extractEcosystem(c.Purl),
+			Direct:    isDirect[c.BomRef],
+		}
+		if !d.Direct {
+			d.Via = via[c.BomRef]
+		}
+		deps = append(deps, d)
+	}
+	return deps
+}
+
+// upgradeRecommendation converts a planned upgrade into a recommendation
+func upgradeRecommendation(u upgrades.Upgrade) RecommendationFinding {
+	reason := fmt.Sprintf("Upgrade to %s fixes %s", u.To, strings.Join(u.Fixes, ", "))
+	switch {
+	case u.Method != upgrades.MethodBump:
+		reason = fmt.Sprintf("Force %s (%s) to fix %s; pulled in by %s", u.To, u.Method, strings.Join(u.Fixes, ", "), strings.Join(u.Via, ", "))
+	case len(u.Resolves) > 0:
+		reason += fmt.Sprintf(" (moves %s to fixed versions)", strings.Join(u.Resolves, ", "))
+	}
+	if u.Breaking {
+		reason += "; crosses a major version"
+		if u.CompatibleTo != "" {
+			reason += fmt.Sprintf(", %s is compatible and fixes %s", u.CompatibleTo, strings.Join(u.CompatibleFixes, ", "))
+		}
+	}
+
+	return RecommendationFinding{
+		Package:        u.Package,
+		CurrentVersion: strings.Join(u.From, ", "),
+		Reason:         reason,
+		Priority:       u.Severity,
+		Type:           "upgrade",
+		Ecosystem:      u.Ecosystem,
+		TargetVersion:  u.To,
+		Method:         u.Method,
+		Breaking:       u.Breaking,
+		Fixes:          u.Fixes,
+		Resolves:       u.Resolves,
+		Via:            u.Via,
+	}
+}
+
 // ==================== Typosquats Feature ====================
 
 type typosquatsFeatureResult struct {
@@ -2551,46 +2814,67 @@ type typosquatsFeatureResult struct {
 	Findings []TyposquatFinding
 }
 
-func (s *SupplyChainScanner) runTyposquatsFeature(ctx context.Context, components []ComponentData) *typosquatsFeatureResult {
+func (s *SupplyChainScanner) runTyposquatsFeature(ctx context.Context, opts *scanner.ScanOptions, components []ComponentData) *typosquatsFeatureResult {
 	result := &typosquatsFeatureResult{
-		Summary:  &TyposquatsSummary{},
+		Summary:  &TyposquatsSummary{ByModel: make(map[string]int)},
 		Findings: []TyposquatFinding{},
 	}
 
 	result.Summary.TotalChecked = len(components)
 
-	// Check for packages similar to popular ones
-	popularPackages := map[string]bool{
-		"lodash": true, "express": true, "react": true, "axios": true,
-		"moment": true, "request": true, "async": true, "chalk": true,
-		"commander": true, "debug": true, "underscore": true, "bluebird": true,
+	// Name checks run offline against the popular-package corpus: an
+	// imported one when present, else the bundled one
+	corpusPath := s.config.Typosquats.Corpus
+	if corpusPath == "" {
+		corpusPath = typosquat.DefaultPath(zeroHome(opts))
+	}
+	corpus, err := typosquat.Load(corpusPath)
+	if err != nil {
+		result.Summary.Error = err.Error()
+		corpus = typosquat.Bundled()
 	}
+	result.Summary.CorpusVersion = corpus.Version
+	detector := typosquat.NewDetector(corpus)
 
-	client := &http.Client{Timeout: 5 * time.Second}
+	client := liveapi.NewNPMClient()
+	registryReachable := true
+	seen := make(map[string]bool)
 
 	for _, pkg := range components {
+		name := fullPackageName(pkg)
+		if seen[pkg.Ecosystem+"/"+name] {
+			continue
+		}
+		seen[pkg.Ecosystem+"/"+name] = true
+
 		// Check similar names
 		if s.config.Typosquats.CheckSimilarNames {
-			for popular := range popularPackages {
-				if pkg.Name != popular && isSimilar(pkg.Name, popular) {
-					result.Findings = append(result.Findings, TyposquatFinding{
-						Package:   pkg.Name,
-						Ecosystem: pkg.Ecosystem,
-						SimilarTo: popular,
-						Reason:    fmt.Sprintf("Name similar to popular package '%s'", popular),
-						RiskLevel: "medium",
-					})
-					result.Summary.SuspiciousCount++
-				}
+			if m := detector.Check(pkg.Ecosystem, name); m != nil && m.Confidence >= s.config.Typosquats.MinConfidence {
+				result.Findings = append(result.Findings, TyposquatFinding{
+					Package:    name,
+					Ecosystem:  pkg.Ecosystem,
+					SimilarTo:  m.Target,
+					Reason:     m.Reason,
+					RiskLevel:  m.RiskLevel(),
+					Model:      m.Model,
+					Confidence: m.Confidence,
+				})
+				result.Summary.SuspiciousCount++
+				result.Summary.ByModel[m.Model]++
 			}
 		}
 
-		// Check package age
-		if s.config.Typosquats.CheckNewPackages && pkg.Ecosystem == "npm" {
-			age := getPackageAge(ctx, client, pkg.Name)
+		// Check package age; stop asking once the registry is unreachable
+		// so air-gapped runs don't wait out a timeout per package
+		if s.config.Typosquats.CheckNewPackages && registryReachable && pkg.Ecosystem == "npm" {
+			age, err := getPackageAge(ctx, client, name)
+			if err != nil {
+				registryReachable = false
+				continue
+			}
 			if age >= 0 && age < 30 {
 				result.Findings = append(result.Findings, TyposquatFinding{
-					Package:   pkg.Name,
+					Package:   name,
 					Ecosystem: pkg.Ecosystem,
 					AgeInDays: age,
 					Reason:    fmt.Sprintf("Package is only %d days old", age),
@@ -2604,68 +2888,50 @@ func (s *SupplyChainScanner) runTyposquatsFeature(ctx context.Context, component
 	return result
 }
 
-func isSimilar(a, b string) bool {
-	if len(a) < 3 || len(b) < 3 {
-		return false
-	}
-	// Simple Levenshtein-like check
-	if abs(len(a)-len(b)) > 2 {
-		return false
-	}
-	differences := 0
-	minLen := len(a)
-	if len(b) < minLen {
-		minLen = len(b)
-	}
-	for i := 0; i < minLen; i++ {
-		if a[i] != b[i] {
-			differences++
-		}
+// fullPackageName returns a component's name as its registry knows it:
+// with the npm scope, Go module path or Maven group
+func fullPackageName(pkg ComponentData) string {
+	ns := purlNamespace(pkg.Purl)
+	if ns == "" || strings.HasPrefix(pkg.Name, ns+"/") || strings.HasPrefix(pkg.Name, ns+":") {
+		return pkg.Name
 	}
-	differences += abs(len(a) - len(b))
-	return differences > 0 && differences <= 2
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
+	switch pkg.Ecosystem {
+	case "maven":
+		return ns + ":" + pkg.Name
+	case "npm", "golang", "composer":
+		return ns + "/" + pkg.Name
 	}
-	return x
+	return pkg.Name
 }
 
-func getPackageAge(ctx context.Context, client *http.Client, name string) int {
-	req, _ := http.NewRequestWithContext(ctx, "GET", "https://registry.npmjs.org/"+name, nil)
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return -1
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return -1
-	}
+// getPackageAge returns the age in days of an npm package, or -1 if the
+// registry has no creation time for it. An error means the registry could
+// not be reached.
+func getPackageAge(ctx context.Context, client *liveapi.Client, name string) (int, error) {
 	var pkg struct {
 		Time map[string]string `json:"time"`
 	}
-	if json.Unmarshal(body, &pkg) != nil {
-		return -1
+	if err := client.CachedGet(ctx, "/"+name, &pkg); err != nil {
+		// Registry errors and packages missing from an offline cache
+		// only cost this package its age
+		var apiErr *liveapi.APIError
+		if errors.As(err, &apiErr) || errors.Is(err, liveapi.ErrOffline) {
+			return -1, nil
+		}
+		return -1, err
 	}
 
 	created, ok := pkg.Time["created"]
 	if !ok {
-		return -1
+		return -1, nil
 	}
 
 	t, err := time.Parse(time.RFC3339, created)
 	if err != nil {
-		return -1
+		return -1, nil
 	}
 
-	return int(time.Since(t).Hours() / 24)
+	return int(time.Since(t).Hours() / 24), nil
 }
 
 // ==================== Deprecations Feature ====================
@@ -2685,7 +2951,9 @@ func (s *SupplyChainScanner) runDeprecationsFeature(ctx context.Context, compone
 
 	// Use deps.dev client as primary source (cross-ecosystem)
 	depsClient := liveapi.NewDepsDevClient()
-	httpClient := &http.Client{Timeout: 5 * time.Second}
+	npmClient := liveapi.NewNPMClient()
+	pypiClient := liveapi.NewPyPIClient()
+	goClients := goDeprecationClients{proxy: liveapi.NewGoProxyClient(), site: liveapi.NewPkgGoDevClient()}
 
 	for _, pkg := range components {
 		var deprecated bool
@@ -2705,15 +2973,15 @@ func (s *SupplyChainScanner) runDeprecationsFeature(ctx context.Context, compone
 			switch pkg.Ecosystem {
 			case "npm":
 				if s.config.Deprecations.CheckNPM {
-					deprecated, message = checkNPMDeprecation(ctx, httpClient, pkg.Name, pkg.Version)
+					deprecated, message = checkNPMDeprecation(ctx, npmClient, pkg.Name, pkg.Version)
 				}
 			case "pypi":
 				if s.config.Deprecations.CheckPyPI {
-					deprecated, message = checkPyPIDeprecation(ctx, httpClient, pkg.Name)
+					deprecated, message = checkPyPIDeprecation(ctx, pypiClient, pkg.Name)
 				}
 			case "golang", "go":
 				if s.config.Deprecations.CheckGo {
-					deprecated, message = checkGoDeprecation(ctx, httpClient, pkg.Name, pkg.Version)
+					deprecated, message = checkGoDeprecation(ctx, goClients, pkg.Name, pkg.Version)
 				}
 			}
 		}
@@ -2734,52 +3002,24 @@ func (s *SupplyChainScanner) runDeprecationsFeature(ctx context.Context, compone
 	return result
 }
 
-func checkNPMDeprecation(ctx context.Context, client *http.Client, name, version string) (bool, string) {
-	req, _ := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://registry.npmjs.org/%s/%s", name, version), nil)
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
+func checkNPMDeprecation(ctx context.Context, client *liveapi.Client, name, version string) (bool, string) {
 	var pkg struct {
 		Deprecated string `json:"deprecated"`
 	}
-	if json.Unmarshal(body, &pkg) != nil {
+	if client.CachedGet(ctx, fmt.Sprintf("/%s/%s", name, version), &pkg) != nil {
 		return false, ""
 	}
 
 	return pkg.Deprecated != "", pkg.Deprecated
 }
 
-func checkPyPIDeprecation(ctx context.Context, client *http.Client, name string) (bool, string) {
-	req, _ := http.NewRequestWithContext(ctx, "GET", "https://pypi.org/pypi/"+name+"/json", nil)
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
+func checkPyPIDeprecation(ctx context.Context, client *liveapi.Client, name string) (bool, string) {
 	var pkg struct {
 		Info struct {
 			Classifiers []string `json:"classifiers"`
 		} `json:"info"`
 	}
-	if json.Unmarshal(body, &pkg) != nil {
+	if client.CachedGet(ctx, "/"+name+"/json", &pkg) != nil {
 		return false, ""
 	}
 
@@ -2797,40 +3037,23 @@ func checkPyPIDeprecation(ctx context.Context, client *http.Client, name string)
 	return false, ""
 }
 
+// goDeprecationClients query the Go module proxy and pkg.go.dev
+type goDeprecationClients struct {
+	proxy *liveapi.Client
+	site  *liveapi.Client
+}
+
 // checkGoDeprecation checks if a Go module version is deprecated/retracted
-func checkGoDeprecation(ctx context.Context, client *http.Client, modulePath, version string) (bool, string) {
+func checkGoDeprecation(ctx context.Context, clients goDeprecationClients, modulePath, version string) (bool, string) {
 	// Query the Go module proxy for version info
 	// The proxy returns retracted status in the .info endpoint
 	// Module paths must be escaped: uppercase letters become !lowercase
-	escapedPath := escapeModulePath(modulePath)
-	proxyURL := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info",
-		escapedPath,
-		version)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", proxyURL, nil)
-	if err != nil {
-		return false, ""
-	}
-
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
 	var info struct {
 		Version string `json:"Version"`
 		Time    string `json:"Time"`
 		Retract string `json:"Retract,omitempty"` // Retraction message if retracted
 	}
-	if json.Unmarshal(body, &info) != nil {
+	if clients.proxy.CachedGet(ctx, fmt.Sprintf("/%s/@v/%s.info", escapeModulePath(modulePath), version), &info) != nil {
 		return false, ""
 	}
 
@@ -2840,27 +3063,12 @@ func checkGoDeprecation(ctx context.Context, client *http.Client, modulePath, ve
 
 	// Also check for deprecated modules via pkg.go.dev API
 	// Some modules are marked deprecated at the module level
-	pkgURL := fmt.Sprintf("https://pkg.go.dev/%s?tab=versions", modulePath)
-	req, err = http.NewRequestWithContext(ctx, "GET", pkgURL, nil)
+	htmlBody, err := clients.site.CachedGetRaw(ctx, fmt.Sprintf("/%s?tab=versions", modulePath))
 	if err != nil {
 		return false, ""
 	}
-	req.Header.Set("Accept", "text/html")
-
-	resp, err = client.Do(req)
-	if err != nil || resp.StatusCode != 200 {
-		if resp != nil {
-			resp.Body.Close()
-		}
-		return false, ""
-	}
-	defer resp.Body.Close()
 
 	// Quick check for deprecation notice in HTML
-	htmlBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, ""
-	}
 	htmlStr := string(htmlBody)
 	if strings.Contains(htmlStr, "Deprecated") && strings.Contains(htmlStr, "deprecated") {
 		return true, "Module marked as deprecated on pkg.go.dev"
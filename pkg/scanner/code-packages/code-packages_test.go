@@ -1,8 +1,17 @@
 package codepackages
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/crashappsec/zero/pkg/core/licenses"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/osvdb"
+	"github.com/crashappsec/zero/pkg/scanner"
 )
 
 func TestCodePackagesScanner_Name(t *testing.T) {
@@ -250,22 +259,17 @@ func TestFullConfig(t *testing.T) {
 	}
 }
 
-func TestAllowedLicenses(t *testing.T) {
-	expected := []string{"MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC", "Unlicense", "CC0-1.0", "0BSD"}
+func TestLicensePolicy_Defaults(t *testing.T) {
+	policy := licensePolicy(DefaultConfig().Licenses)
 
-	for _, lic := range expected {
-		if !allowedLicenses[lic] {
-			t.Errorf("allowedLicenses should include %q", lic)
+	for _, lic := range []string{"MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC", "Unlicense", "CC0-1.0", "0BSD"} {
+		if eval := policy.Evaluate(lic); eval.Decision != licenses.Allow {
+			t.Errorf("%s decision = %s, want allow", lic, eval.Decision)
 		}
 	}
-}
-
-func TestDeniedLicenses(t *testing.T) {
-	expected := []string{"GPL-2.0", "GPL-2.0-only", "GPL-3.0", "GPL-3.0-only", "AGPL-3.0", "AGPL-3.0-only", "SSPL-1.0"}
-
-	for _, lic := range expected {
-		if !deniedLicenses[lic] {
-			t.Errorf("deniedLicenses should include %q", lic)
+	for _, lic := range []string{"GPL-2.0", "GPL-2.0-only", "GPL-3.0", "GPL-3.0-only", "AGPL-3.0", "AGPL-3.0-only", "SSPL-1.0"} {
+		if eval := policy.Evaluate(lic); eval.Decision != licenses.Deny {
+			t.Errorf("%s decision = %s, want deny", lic, eval.Decision)
 		}
 	}
 }
@@ -293,48 +297,114 @@ func TestLooksInternal(t *testing.T) {
 	}
 }
 
-func TestIsSimilar(t *testing.T) {
-	// isSimilar compares char-by-char up to minLen, then adds length difference
-	// Returns true if differences > 0 && differences <= 2
-	tests := []struct {
-		a        string
-		b        string
-		expected bool
-	}{
-		{"lodash", "1odash", true},   // 1 char difference at position 0
-		{"lodash", "lodaxh", true},   // 1 char difference at position 4
-		{"lodash", "lodash", false},  // identical - differences = 0
-		{"lodash", "express", false}, // completely different
-		{"ab", "cd", false},          // too short (< 3 chars)
-		{"express", "lodash", false}, // length diff = 1, but many char differences
-		{"lodashe", "lodash", true},  // 1 char added (length diff = 1)
-		{"lodas", "lodash", true},    // 1 char removed (length diff = 1)
+func TestRunMalcontentFeature_Builtin(t *testing.T) {
+	repo := t.TempDir()
+	files := map[string]string{
+		"node_modules/stealer/package.json": `{"name":"stealer","version":"1.0.0","scripts":{"postinstall":"node install.js"}}`,
+		"node_modules/stealer/install.js":   "require('https').request({}).end(process.env.NPM_TOKEN)",
+		"node_modules/addon/package.json":   `{"name":"addon","version":"2.0.0","scripts":{"install":"node-gyp rebuild"}}`,
+	}
+	for name, content := range files {
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	for _, tt := range tests {
-		got := isSimilar(tt.a, tt.b)
-		if got != tt.expected {
-			t.Errorf("isSimilar(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
-		}
+	s := &SupplyChainScanner{
+		config: FeatureConfig{
+			Malcontent: MalcontentConfig{Enabled: true, MinRiskLevel: "medium", Engine: "builtin"},
+		},
+	}
+	result := s.runMalcontentFeature(context.Background(), &scanner.ScanOptions{RepoPath: repo}, "")
+
+	if result.Summary.Error != "" || strings.Join(result.Summary.Engines, ",") != "builtin" {
+		t.Fatalf("Summary = %+v", result.Summary)
+	}
+	if result.Summary.PackagesInspected != 2 || result.Summary.Critical != 1 {
+		t.Errorf("Summary = %+v", result.Summary)
+	}
+	// The lifecycle scripts themselves are low risk and filtered out
+	if len(result.Findings) != 1 {
+		t.Fatalf("Findings = %+v, want install.js only", result.Findings)
+	}
+	f := result.Findings[0]
+	if f.Engine != "builtin" || f.Hook != "postinstall" || f.Risk != "critical" || f.RiskScore != 4 {
+		t.Errorf("Finding = %+v", f)
+	}
+	if f.Package != "stealer" || f.Version != "1.0.0" || f.Ecosystem != "npm" || len(f.Evidence) == 0 {
+		t.Errorf("Finding = %+v", f)
+	}
+
+	s.config.Malcontent.Engine = "bogus"
+	if result := s.runMalcontentFeature(context.Background(), &scanner.ScanOptions{RepoPath: repo}, ""); result.Summary.Error == "" {
+		t.Error("unknown engine gave no error")
 	}
 }
 
-func TestAbs(t *testing.T) {
+func TestRunTyposquatsFeature(t *testing.T) {
+	s := &SupplyChainScanner{
+		config: FeatureConfig{
+			Typosquats: TyposquatsConfig{
+				Enabled:           true,
+				CheckSimilarNames: true,
+				MinConfidence:     0.5,
+			},
+		},
+	}
+
+	components := []ComponentData{
+		{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+		{Name: "1odash", Version: "1.0.0", Ecosystem: "npm"},
+		{Name: "1odash", Version: "1.0.1", Ecosystem: "npm"},
+		{Name: "node", Version: "1.0.0", Ecosystem: "npm", Purl: "pkg:npm/%40typess/node@1.0.0"},
+		{Name: "reqeusts", Version: "2.31.0", Ecosystem: "pypi"},
+		{Name: "tslib2", Version: "1.0.0", Ecosystem: "npm"}, // below min_confidence
+	}
+
+	result := s.runTyposquatsFeature(context.Background(), &scanner.ScanOptions{ZeroHome: t.TempDir()}, components)
+
+	if result.Summary.SuspiciousCount != 3 {
+		t.Fatalf("SuspiciousCount = %d, want 3: %+v", result.Summary.SuspiciousCount, result.Findings)
+	}
+	if result.Summary.CorpusVersion == "" {
+		t.Error("CorpusVersion is empty")
+	}
+
+	byPackage := make(map[string]TyposquatFinding)
+	for _, f := range result.Findings {
+		byPackage[f.Package] = f
+	}
+	if f := byPackage["1odash"]; f.SimilarTo != "lodash" || f.Model != "homoglyph" || f.RiskLevel != "high" {
+		t.Errorf("1odash = %+v", f)
+	}
+	if f := byPackage["@typess/node"]; f.SimilarTo != "@types/node" || f.Model != "scope-confusion" {
+		t.Errorf("@typess/node = %+v", f)
+	}
+	if f := byPackage["reqeusts"]; f.SimilarTo != "requests" || f.Confidence == 0 {
+		t.Errorf("reqeusts = %+v", f)
+	}
+}
+
+func TestFullPackageName(t *testing.T) {
 	tests := []struct {
-		input    int
-		expected int
+		pkg  ComponentData
+		want string
 	}{
-		{5, 5},
-		{-5, 5},
-		{0, 0},
-		{-100, 100},
-		{100, 100},
+		{ComponentData{Name: "lodash", Ecosystem: "npm", Purl: "pkg:npm/lodash@4.17.21"}, "lodash"},
+		{ComponentData{Name: "core", Ecosystem: "npm", Purl: "pkg:npm/%40babel/core@7.22.0"}, "@babel/core"},
+		{ComponentData{Name: "@babel/core", Ecosystem: "npm", Purl: "pkg:npm/%40babel/core@7.22.0"}, "@babel/core"},
+		{ComponentData{Name: "guava", Ecosystem: "maven", Purl: "pkg:maven/com.google.guava/guava@32.0.0"}, "com.google.guava:guava"},
+		{ComponentData{Name: "cobra", Ecosystem: "golang", Purl: "pkg:golang/github.com/spf13/cobra@v1.8.0"}, "github.com/spf13/cobra"},
+		{ComponentData{Name: "requests", Ecosystem: "pypi"}, "requests"},
 	}
 
 	for _, tt := range tests {
-		got := abs(tt.input)
-		if got != tt.expected {
-			t.Errorf("abs(%d) = %d, want %d", tt.input, got, tt.expected)
+		if got := fullPackageName(tt.pkg); got != tt.want {
+			t.Errorf("fullPackageName(%s) = %q, want %q", tt.pkg.Purl, got, tt.want)
 		}
 	}
 }
@@ -465,7 +535,7 @@ func TestRunRecommendationsFeature(t *testing.T) {
 		},
 	}
 
-	result := s.runRecommendationsFeature(scanResult)
+	result := s.runRecommendationsFeature(context.Background(), "", newRecommendationsInput(scanResult))
 
 	// Should have recommendations based on critical vulns
 	if result.Summary.SecurityRecommendations != 3 {
@@ -491,10 +561,347 @@ func TestRunRecommendationsFeature_NoVulns(t *testing.T) {
 		Summary: Summary{},
 	}
 
-	result := s.runRecommendationsFeature(scanResult)
+	result := s.runRecommendationsFeature(context.Background(), "", newRecommendationsInput(scanResult))
 
 	// Should have no recommendations
 	if result.Summary.TotalRecommendations != 0 {
 		t.Errorf("TotalRecommendations = %d, want 0", result.Summary.TotalRecommendations)
 	}
 }
+
+func TestRunRecommendationsFeature_UpgradePlan(t *testing.T) {
+	sbomPath := filepath.Join(t.TempDir(), "sbom.cdx.json")
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.5",
+		"metadata": {"component": {"bom-ref": "app", "name": "app"}},
+		"components": [
+			{"type": "library", "name": "mkdirp", "version": "0.5.1", "purl": "pkg:npm/mkdirp@0.5.1", "bom-ref": "mkdirp"},
+			{"type": "library", "name": "minimist", "version": "0.0.8", "purl": "pkg:npm/minimist@0.0.8", "bom-ref": "minimist"},
+			{"type": "library", "name": "core", "version": "7.0.0", "purl": "pkg:npm/%40babel/core@7.0.0", "bom-ref": "babel"}
+		],
+		"dependencies": [
+			{"ref": "app", "dependsOn": ["mkdirp", "babel"]},
+			{"ref": "mkdirp", "dependsOn": ["minimist"]}
+		]}`
+	if err := os.WriteFile(sbomPath, []byte(bom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &SupplyChainScanner{}
+	scanResult := &Result{Findings: Findings{Vulns: []VulnFinding{
+		{ID: "GHSA-vh95-rmgr-6w4m", Package: "minimist", Version: "0.0.8", Ecosystem: "npm", Severity: "critical", FixedIn: "0.2.1"},
+		{ID: "GHSA-67hx-6x53-jw92", Package: "@babel/core", Version: "7.0.0", Ecosystem: "npm", Severity: "critical", FixedIn: "7.23.2"},
+	}}}
+
+	result := s.runRecommendationsFeature(context.Background(), sbomPath, newRecommendationsInput(scanResult))
+
+	if result.Summary.UpgradePlan == nil || result.Summary.UpgradePlan.DirectUpgrades != 1 || result.Summary.UpgradePlan.Overrides != 1 {
+		t.Fatalf("upgrade plan = %+v", result.Summary.UpgradePlan)
+	}
+	for _, f := range result.Findings {
+		switch f.Package {
+		case "@babel/core":
+			if f.Method != "bump" || f.TargetVersion != "7.23.2" || f.Breaking {
+				t.Errorf("@babel/core = %+v", f)
+			}
+		case "minimist":
+			if f.Method != "override" || strings.Join(f.Via, ",") != "mkdirp" {
+				t.Errorf("minimist = %+v", f)
+			}
+		default:
+			t.Errorf("unexpected recommendation %+v", f)
+		}
+	}
+}
+
+func TestRunVulnsFeature_LocalDB(t *testing.T) {
+	ctx := context.Background()
+	zeroHome := t.TempDir()
+	dump := t.TempDir()
+	writeTestFile(t, dump, "GHSA-35jh-r3h4-6jhm.json", `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "modified": "2024-01-01T00:00:00Z",
+  "summary": "Command Injection in lodash",
+  "aliases": ["CVE-2021-23337"],
+  "database_specific": {"severity": "HIGH"},
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+  }]
+}`)
+	writeTestFile(t, dump, "GHSA-xxxx-scoped.json", `{
+  "id": "GHSA-xxxx-scoped",
+  "modified": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "@babel/traverse"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "7.23.2"}]}]
+  }]
+}`)
+	db, err := osvdb.Open(osvdb.DefaultPath(zeroHome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Import(ctx, dump); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	sbomPath := writeTestFile(t, t.TempDir(), "sbom.cdx.json", `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.20", "purl": "pkg:npm/lodash@4.17.20"},
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21"},
+    {"type": "library", "group": "@babel", "name": "traverse", "version": "7.23.0", "purl": "pkg:npm/%40babel/traverse@7.23.0"},
+    {"type": "library", "name": "requests", "version": "2.0.0", "purl": "pkg:pypi/requests@2.0.0"}
+  ]
+}`)
+
+	s := &SupplyChainScanner{config: FeatureConfig{Vulns: VulnsConfig{Enabled: true}}}
+	result := s.runVulnsFeature(ctx, &scanner.ScanOptions{ZeroHome: zeroHome}, sbomPath)
+
+	// The fixed lodash is not reported, and without api_fallback PyPI
+	// (absent from the database) is not queried, which the summary says
+	if len(result.Summary.UncheckedEcosystems) != 1 || result.Summary.UncheckedEcosystems[0] != "PyPI" ||
+		!strings.Contains(result.Summary.Error, "1 package not checked") {
+		t.Errorf("unchecked = %v, error = %q", result.Summary.UncheckedEcosystems, result.Summary.Error)
+	}
+	if len(result.Findings) != 2 || result.Summary.High != 1 || result.Summary.Medium != 1 {
+		t.Fatalf("findings = %+v, summary = %+v", result.Findings, result.Summary)
+	}
+	lodash := result.Findings[0]
+	if lodash.Package != "lodash" || lodash.Version != "4.17.20" || lodash.FixedIn != "4.17.21" || lodash.Severity != "high" {
+		t.Errorf("lodash finding = %+v", lodash)
+	}
+	if scoped := result.Findings[1]; scoped.Package != "@babel/traverse" {
+		t.Errorf("scoped finding = %+v", scoped)
+	}
+	if len(result.Summary.Sources) != 1 || result.Summary.Sources[0] != "osv-db" {
+		t.Errorf("sources = %v", result.Summary.Sources)
+	}
+}
+
+func TestOSVPackageName(t *testing.T) {
+	tests := []struct {
+		component Component
+		want      string
+	}{
+		{Component{Name: "guava", Ecosystem: "maven", Purl: "pkg:maven/com.google.guava/guava@32.1.3-jre"}, "com.google.guava:guava"},
+		{Component{Name: "@babel/core", Ecosystem: "npm", Purl: "pkg:npm/%40babel/core@7.23.0"}, "@babel/core"},
+		{Component{Name: "cobra", Ecosystem: "golang", Purl: "pkg:golang/github.com/spf13/cobra@v1.8.0"}, "github.com/spf13/cobra"},
+		{Component{Name: "requests", Ecosystem: "pypi", Purl: "pkg:pypi/requests@2.31.0"}, "requests"},
+	}
+	for _, tt := range tests {
+		if got := osvPackageName(tt.component); got != tt.want {
+			t.Errorf("osvPackageName(%s) = %s, want %s", tt.component.Purl, got, tt.want)
+		}
+	}
+}
+
+func TestCompareSBOMs_VersionDirection(t *testing.T) {
+	previous := filepath.Join(t.TempDir(), "previous.cdx.json")
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [
+		{"type": "library", "name": "requests", "version": "2.31.0", "purl": "pkg:pypi/requests@2.31.0"},
+		{"type": "library", "name": "django", "version": "4.2.9", "purl": "pkg:pypi/django@4.2.9"}
+	]}`
+	if err := os.WriteFile(previous, []byte(bom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := compareSBOMs(previous, "", []Component{
+		{Name: "requests", Version: "2.31.0rc1", Ecosystem: "pypi"},
+		{Name: "django", Version: "4.2.10", Ecosystem: "pypi"},
+	})
+	if err != nil {
+		t.Fatalf("compareSBOMs() error = %v", err)
+	}
+	directions := make(map[string]string)
+	for _, change := range drift.VersionChanged {
+		directions[change.Name] = change.Direction
+	}
+	if directions["requests"] != "downgrade" || directions["django"] != "upgrade" {
+		t.Errorf("directions = %v", directions)
+	}
+}
+
+func TestRunLicensesFeature(t *testing.T) {
+	repo := t.TempDir()
+	out := t.TempDir()
+	sbomPath := filepath.Join(out, "sbom.cdx.json")
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.5",
+		"metadata": {"component": {"bom-ref": "app", "name": "acme-app"}},
+		"components": [
+			{"type": "library", "name": "express", "version": "4.18.2", "purl": "pkg:npm/express@4.18.2", "bom-ref": "express",
+			 "licenses": [{"license": {"id": "MIT"}}]},
+			{"type": "library", "name": "readline-gpl", "version": "1.0.0", "purl": "pkg:npm/readline-gpl@1.0.0", "bom-ref": "readline-gpl",
+			 "licenses": [{"expression": "GPL-3.0-or-later"}]},
+			{"type": "library", "name": "dual", "version": "2.0.0", "purl": "pkg:npm/dual@2.0.0", "bom-ref": "dual",
+			 "licenses": [{"expression": "GPL-2.0-only OR MIT"}]},
+			{"type": "library", "name": "jest", "version": "29.0.0", "purl": "pkg:npm/jest@29.0.0", "bom-ref": "jest", "scope": "optional",
+			 "licenses": [{"license": {"name": "MIT License"}}]}
+		],
+		"dependencies": [
+			{"ref": "app", "dependsOn": ["express", "jest"]},
+			{"ref": "express", "dependsOn": ["readline-gpl", "dual"]}
+		]}`
+	if err := os.WriteFile(sbomPath, []byte(bom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, repo, map[string]string{
+		"node_modules/express/LICENSE": "Copyright (c) 2009-2014 TJ Holowaychuk",
+	})
+
+	s := &SupplyChainScanner{config: DefaultConfig()}
+	result := s.runLicensesFeature(&scanner.ScanOptions{RepoPath: repo, OutputDir: out}, sbomPath)
+
+	if result.Summary.Allowed != 3 || result.Summary.Denied != 1 || result.Summary.PolicyViolations != 1 {
+		t.Errorf("summary = %+v", result.Summary)
+	}
+	for _, f := range result.Findings {
+		if f.Package != "readline-gpl" {
+			if len(f.DependencyPath) != 0 {
+				t.Errorf("%s has a dependency path but is allowed", f.Package)
+			}
+			continue
+		}
+		if f.Status != "denied" || f.Category != "strong-copyleft" {
+			t.Errorf("readline-gpl = %s/%s", f.Status, f.Category)
+		}
+		if got := strings.Join(f.DependencyPath, " -> "); got != "express@4.18.2 -> readline-gpl@1.0.0" {
+			t.Errorf("dependency path = %q", got)
+		}
+		if len(f.DependencyPaths) != 1 {
+			t.Errorf("dependency paths = %v", f.DependencyPaths)
+		}
+	}
+
+	notices, err := os.ReadFile(filepath.Join(out, licenses.NoticesFile))
+	if err != nil {
+		t.Fatalf("notices not written: %v", err)
+	}
+	for _, want := range []string{"acme-app incorporates", "dual 2.0.0 - GPL-2.0-only OR MIT", "TJ Holowaychuk"} {
+		if !strings.Contains(string(notices), want) {
+			t.Errorf("notices missing %q", want)
+		}
+	}
+	if strings.Contains(string(notices), "jest") {
+		t.Error("notices should not include dev dependencies")
+	}
+}
+
+func TestMalcontentFinding_Package(t *testing.T) {
+	sbomPath := filepath.Join(t.TempDir(), "sbom.cdx.json")
+	bom := `{"bomFormat": "CycloneDX", "specVersion": "1.5",
+		"metadata": {"component": {"bom-ref": "app", "name": "app"}},
+		"components": [
+			{"type": "library", "name": "request", "version": "2.88.2", "purl": "pkg:npm/request@2.88.2", "bom-ref": "request"},
+			{"type": "library", "name": "event-stream", "version": "3.3.6", "purl": "pkg:npm/event-stream@3.3.6", "bom-ref": "event-stream"},
+			{"type": "library", "name": "flatmap-stream", "version": "0.1.1", "purl": "pkg:npm/flatmap-stream@0.1.1", "bom-ref": "flatmap-stream"}
+		],
+		"dependencies": [
+			{"ref": "app", "dependsOn": ["request", "event-stream"]},
+			{"ref": "request", "dependsOn": ["event-stream"]},
+			{"ref": "event-stream", "dependsOn": ["flatmap-stream"]}
+		]}`
+	if err := os.WriteFile(sbomPath, []byte(bom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	graph := loadDependencyGraph(sbomPath)
+
+	f := malcontentFinding(graph, "/repo", "/repo/node_modules/flatmap-stream/index.min.js", "critical", 4, []string{"obfuscated"})
+	if f.Package != "flatmap-stream" || f.Version != "0.1.1" || f.Ecosystem != "npm" {
+		t.Errorf("finding = %+v", f)
+	}
+	if got := strings.Join(f.DependencyPath, " -> "); got != "event-stream@3.3.6 -> flatmap-stream@0.1.1" {
+		t.Errorf("dependency path = %q", got)
+	}
+	if len(f.DependencyPaths) != 2 {
+		t.Errorf("dependency paths = %v", f.DependencyPaths)
+	}
+
+	if f := malcontentFinding(graph, "/repo", "/repo/scripts/install.sh", "high", 3, nil); f.Package != "" || f.DependencyPath != nil {
+		t.Errorf("file outside packages attributed: %+v", f)
+	}
+}
+
+func TestRunGeneration_ImportSPDX(t *testing.T) {
+	repo := t.TempDir()
+	out := t.TempDir()
+	writeTestFile(t, repo, "sbom/vendor.spdx", `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: vendor-app
+DocumentNamespace: https://example.com/vendor-app
+
+PackageName: vendor-app
+SPDXID: SPDXRef-app
+PackageDownloadLocation: NOASSERTION
+
+PackageName: lodash
+SPDXID: SPDXRef-lodash
+PackageVersion: 4.17.20
+PackageDownloadLocation: NOASSERTION
+PackageLicenseDeclared: MIT
+ExternalRef: PACKAGE-MANAGER purl pkg:npm/lodash@4.17.20
+
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-app
+Relationship: SPDXRef-app DEPENDS_ON SPDXRef-lodash
+`)
+
+	cfg := DefaultConfig().Generation
+	cfg.SBOMPath = "sbom/vendor.spdx"
+	summary, findings, path, err := runGeneration(context.Background(), &scanner.ScanOptions{RepoPath: repo, OutputDir: out}, cfg)
+	if err != nil {
+		t.Fatalf("runGeneration() error = %v", err)
+	}
+	if path != GetSBOMPath(out) || summary.Tool != "import" || summary.SourceFormat != "spdx-tag-value" {
+		t.Errorf("summary = %+v, path = %s", summary, path)
+	}
+	if !summary.HasDependencies || len(findings.Components) != 1 {
+		t.Fatalf("components = %+v", findings.Components)
+	}
+	c := findings.Components[0]
+	if c.Purl != "pkg:npm/lodash@4.17.20" || c.Ecosystem != "npm" || len(c.Licenses) != 1 || c.Licenses[0] != "MIT" {
+		t.Errorf("component = %+v", c)
+	}
+
+	if _, err := ImportSBOM(filepath.Join(repo, "sbom/vendor.spdx"), filepath.Join(out, "copy.json")); err != nil {
+		t.Errorf("ImportSBOM() error = %v", err)
+	}
+	writeTestFile(t, repo, "bad.json", `{"name": "not an sbom"}`)
+	if _, err := ImportSBOM(filepath.Join(repo, "bad.json"), filepath.Join(out, "bad.json")); err == nil {
+		t.Error("ImportSBOM() accepted a non-SBOM")
+	}
+}
+
+func TestUseAPICache(t *testing.T) {
+	opts := &scanner.ScanOptions{
+		ZeroHome: t.TempDir(),
+		FeatureConfig: map[string]interface{}{
+			"api_cache": map[string]interface{}{
+				"enabled":   true,
+				"ttl_hours": map[string]interface{}{"kev": float64(2)},
+			},
+		},
+	}
+	defaultPolicy := liveapi.PolicyFor(liveapi.EndpointKEV)
+	defer liveapi.SetPolicy(liveapi.EndpointKEV, defaultPolicy)
+	defer liveapi.UseStore("")
+
+	s := &SupplyChainScanner{config: getFeatureConfig(opts)}
+	s.useAPICache(opts)
+
+	store := liveapi.SharedStore()
+	if store == nil || store.Path() != liveapi.DefaultStorePath(opts.ZeroHome) {
+		t.Fatalf("shared store = %v, want one under the zero home", store)
+	}
+	if p := liveapi.PolicyFor(liveapi.EndpointKEV); p.TTL != 2*time.Hour || p.StaleFor != defaultPolicy.StaleFor {
+		t.Errorf("kev policy = %+v, want a 2h TTL", p)
+	}
+
+	if summary := cacheSummary(liveapi.CacheStats{}); summary != nil {
+		t.Errorf("cacheSummary() with no requests = %+v, want nil", summary)
+	}
+	if summary := cacheSummary(liveapi.CacheStats{Hits: 3, Stale: 1}); summary == nil || !summary.PossiblyStale {
+		t.Errorf("cacheSummary() with stale responses = %+v, want possibly stale", summary)
+	}
+}
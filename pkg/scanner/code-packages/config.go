@@ -20,17 +20,21 @@ type FeatureConfig struct {
 	Typosquats      TyposquatsConfig      `json:"typosquats"`
 	Deprecations    DeprecationsConfig    `json:"deprecations"`
 	Duplicates      DuplicatesConfig      `json:"duplicates"`
+	// Shared by the features that query registries and APIs
+	APICache APICacheConfig `json:"api_cache"`
 }
 
 // GenerationConfig configures SBOM generation
 type GenerationConfig struct {
-	Enabled        bool   `json:"enabled"`
-	Tool           string `json:"tool"`             // cdxgen, syft, auto
-	SpecVersion    string `json:"spec_version"`     // CycloneDX version (1.4, 1.5, 1.6)
-	Format         string `json:"format"`           // json, xml
-	FallbackToSyft bool   `json:"fallback_to_syft"` // Use syft if cdxgen fails
-	IncludeDev     bool   `json:"include_dev"`      // Include dev dependencies
-	Deep           bool   `json:"deep"`             // Deep analysis mode
+	Enabled          bool   `json:"enabled"`
+	Tool             string `json:"tool"`               // cdxgen, syft, native, auto
+	SpecVersion      string `json:"spec_version"`       // CycloneDX version (1.4, 1.5, 1.6)
+	Format           string `json:"format"`             // json, xml
+	FallbackToSyft   bool   `json:"fallback_to_syft"`   // Use syft if cdxgen fails
+	FallbackToNative bool   `json:"fallback_to_native"` // Build the SBOM from lockfiles if no tool is available or it fails
+	IncludeDev       bool   `json:"include_dev"`        // Include dev dependencies
+	Deep             bool   `json:"deep"`               // Deep analysis mode
+	SBOMPath         string `json:"sbom_path"`          // Import this CycloneDX or SPDX SBOM instead of generating one (relative to the repo)
 }
 
 // IntegrityConfig configures SBOM integrity verification
@@ -48,6 +52,9 @@ type VulnsConfig struct {
 	SeverityThreshold string   `json:"severity_threshold"` // critical, high, medium, low
 	IncludeKEV        bool     `json:"include_kev"`        // Check CISA KEV catalog
 	IgnoreIDs         []string `json:"ignore_ids"`         // CVE IDs to ignore
+	LocalDB           string   `json:"local_db"`           // OSV database path (default: <zero home>/feeds/osv.db)
+	APIFallback       bool     `json:"api_fallback"`       // Query the OSV API for ecosystems the local database lacks
+	ExploitDB         string   `json:"exploit_db"`         // EPSS/KEV database path (default: <zero home>/feeds/exploits.db)
 }
 
 // HealthConfig configures package health checking
@@ -60,9 +67,11 @@ type HealthConfig struct {
 
 // MalcontentConfig configures supply chain compromise detection
 type MalcontentConfig struct {
-	Enabled         bool   `json:"enabled"`
-	MinRiskLevel    string `json:"min_risk_level"` // critical, high, medium, low
-	ScanNodeModules bool   `json:"scan_node_modules"`
+	Enabled         bool     `json:"enabled"`
+	MinRiskLevel    string   `json:"min_risk_level"` // critical, high, medium, low
+	ScanNodeModules bool     `json:"scan_node_modules"`
+	Engine          string   `json:"engine"`     // auto (mal if installed, else builtin), mal, builtin, both
+	CacheDirs       []string `json:"cache_dirs"` // Package manager caches whose archives the builtin engine inspects
 }
 
 // ProvenanceConfig configures provenance verification
@@ -86,6 +95,7 @@ type RecommendationsConfig struct {
 	IncludeSecurity    bool `json:"include_security"`
 	IncludeHealth      bool `json:"include_health"`
 	IncludePerformance bool `json:"include_performance"`
+	ResolveTransitive  bool `json:"resolve_transitive"` // Ask deps.dev which direct dependency bumps fix transitive vulns
 }
 
 // ConfusionConfig configures dependency confusion detection
@@ -104,17 +114,23 @@ type ReachabilityConfig struct {
 
 // LicensesConfig configures license compliance
 type LicensesConfig struct {
-	Enabled       bool     `json:"enabled"`
-	AllowedList   []string `json:"allowed"`   // Explicitly allowed licenses
-	BlockedList   []string `json:"blocked"`   // Explicitly blocked licenses
-	FailOnUnknown bool     `json:"fail_on_unknown"`
+	Enabled       bool              `json:"enabled"`
+	Distribution  string            `json:"distribution"` // distributed (default) or saas
+	AllowedList   []string          `json:"allowed"`      // Explicitly allowed licenses
+	BlockedList   []string          `json:"blocked"`      // Explicitly blocked licenses
+	ReviewList    []string          `json:"review"`       // Licenses that always need review
+	Categories    map[string]string `json:"categories"`   // Category -> allow/review/deny, overriding the distribution's defaults
+	FailOnUnknown bool              `json:"fail_on_unknown"`
+	Notices       bool              `json:"notices"` // Write THIRD_PARTY_NOTICES.txt
 }
 
 // TyposquatsConfig configures typosquatting detection
 type TyposquatsConfig struct {
-	Enabled           bool `json:"enabled"`
-	CheckSimilarNames bool `json:"check_similar_names"`
-	CheckNewPackages  bool `json:"check_new_packages"` // Flag packages < 30 days old
+	Enabled           bool    `json:"enabled"`
+	CheckSimilarNames bool    `json:"check_similar_names"`
+	CheckNewPackages  bool    `json:"check_new_packages"` // Flag packages < 30 days old (queries npm)
+	Corpus            string  `json:"corpus"`             // Popular-package corpus (default: <zero home>/feeds/popular-packages.json, else bundled)
+	MinConfidence     float64 `json:"min_confidence"`     // Report name matches at or above this confidence (0-1)
 }
 
 // DeprecationsConfig configures deprecated package detection
@@ -132,17 +148,27 @@ type DuplicatesConfig struct {
 	CheckFunctionality bool `json:"check_functionality"`  // Different packages with same purpose
 }
 
+// APICacheConfig configures the on-disk cache of registry and API responses
+// (OSV, deps.dev, npm, PyPI, the Go proxy and the CISA KEV feed)
+type APICacheConfig struct {
+	Enabled    bool           `json:"enabled"`
+	Path       string         `json:"path"`        // Cache database (default: <zero home>/cache/liveapi.db)
+	TTLHours   map[string]int `json:"ttl_hours"`   // Per endpoint: how long responses are fresh
+	StaleHours map[string]int `json:"stale_hours"` // Per endpoint: how long past the TTL responses are served while refreshing
+}
+
 // DefaultConfig returns default feature configuration
 func DefaultConfig() FeatureConfig {
 	return FeatureConfig{
 		Generation: GenerationConfig{
-			Enabled:        true,
-			Tool:           "auto",
-			SpecVersion:    "1.5",
-			Format:         "json",
-			FallbackToSyft: true,
-			IncludeDev:     false,
-			Deep:           false,
+			Enabled:          true,
+			Tool:             "auto",
+			SpecVersion:      "1.5",
+			Format:           "json",
+			FallbackToSyft:   true,
+			FallbackToNative: true,
+			IncludeDev:       false,
+			Deep:             false,
 		},
 		Integrity: IntegrityConfig{
 			Enabled:           true,
@@ -154,6 +180,7 @@ func DefaultConfig() FeatureConfig {
 			Enabled:           true,
 			SeverityThreshold: "low",
 			IncludeKEV:        true,
+			APIFallback:       true,
 		},
 		Health: HealthConfig{
 			Enabled:        true,
@@ -165,6 +192,7 @@ func DefaultConfig() FeatureConfig {
 			Enabled:         true,
 			MinRiskLevel:    "medium",
 			ScanNodeModules: false,
+			Engine:          "auto",
 		},
 		Provenance: ProvenanceConfig{
 			Enabled:           false, // Off by default - slow
@@ -182,6 +210,7 @@ func DefaultConfig() FeatureConfig {
 			IncludeSecurity:    true,
 			IncludeHealth:      true,
 			IncludePerformance: false,
+			ResolveTransitive:  true,
 		},
 		Confusion: ConfusionConfig{
 			Enabled:  true,
@@ -195,14 +224,17 @@ func DefaultConfig() FeatureConfig {
 		},
 		Licenses: LicensesConfig{
 			Enabled:       true,
+			Distribution:  "distributed",
 			AllowedList:   []string{},
-			BlockedList:   []string{"GPL-3.0", "AGPL-3.0"},
+			BlockedList:   []string{},
 			FailOnUnknown: false,
+			Notices:       true,
 		},
 		Typosquats: TyposquatsConfig{
 			Enabled:           true,
 			CheckSimilarNames: true,
 			CheckNewPackages:  true,
+			MinConfidence:     0.5,
 		},
 		Deprecations: DeprecationsConfig{
 			Enabled:  true,
@@ -215,6 +247,9 @@ func DefaultConfig() FeatureConfig {
 			CheckVersions:      true,
 			CheckFunctionality: false,
 		},
+		APICache: APICacheConfig{
+			Enabled: true,
+		},
 	}
 }
 
@@ -254,13 +289,14 @@ func SecurityConfig() FeatureConfig {
 func FullConfig() FeatureConfig {
 	return FeatureConfig{
 		Generation: GenerationConfig{
-			Enabled:        true,
-			Tool:           "auto",
-			SpecVersion:    "1.5",
-			Format:         "json",
-			FallbackToSyft: true,
-			IncludeDev:     true, // Full config includes dev deps
-			Deep:           true, // Full config uses deep analysis
+			Enabled:          true,
+			Tool:             "auto",
+			SpecVersion:      "1.5",
+			Format:           "json",
+			FallbackToSyft:   true,
+			FallbackToNative: true,
+			IncludeDev:       true, // Full config includes dev deps
+			Deep:             true, // Full config uses deep analysis
 		},
 		Integrity: IntegrityConfig{
 			Enabled:           true,
@@ -272,6 +308,7 @@ func FullConfig() FeatureConfig {
 			Enabled:           true,
 			SeverityThreshold: "low",
 			IncludeKEV:        true,
+			APIFallback:       true,
 		},
 		Health: HealthConfig{
 			Enabled:        true,
@@ -283,6 +320,7 @@ func FullConfig() FeatureConfig {
 			Enabled:         true,
 			MinRiskLevel:    "low",
 			ScanNodeModules: true,
+			Engine:          "both",
 		},
 		Provenance: ProvenanceConfig{
 			Enabled:           true,
@@ -300,6 +338,7 @@ func FullConfig() FeatureConfig {
 			IncludeSecurity:    true,
 			IncludeHealth:      true,
 			IncludePerformance: true,
+			ResolveTransitive:  true,
 		},
 		Confusion: ConfusionConfig{
 			Enabled:  true,
@@ -313,14 +352,17 @@ func FullConfig() FeatureConfig {
 		},
 		Licenses: LicensesConfig{
 			Enabled:       true,
+			Distribution:  "distributed",
 			AllowedList:   []string{},
-			BlockedList:   []string{"GPL-3.0", "AGPL-3.0", "SSPL-1.0"},
+			BlockedList:   []string{},
 			FailOnUnknown: false,
+			Notices:       true,
 		},
 		Typosquats: TyposquatsConfig{
 			Enabled:           true,
 			CheckSimilarNames: true,
 			CheckNewPackages:  true,
+			MinConfidence:     0.5,
 		},
 		Deprecations: DeprecationsConfig{
 			Enabled:  true,
@@ -333,5 +375,8 @@ func FullConfig() FeatureConfig {
 			CheckVersions:      true,
 			CheckFunctionality: true,
 		},
+		APICache: APICacheConfig{
+			Enabled: true,
+		},
 	}
 }
@@ -0,0 +1,397 @@
+package codepackages
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/licenses"
+	"github.com/crashappsec/zero/pkg/core/ospkg"
+)
+
+// Limits on what the image walk reads into memory
+const (
+	maxManifestSize  = 4 << 20  // package.json, METADATA, gemspecs, pom.properties
+	maxNestedJarSize = 64 << 20 // jars inside war/ear/Spring Boot archives
+)
+
+// ImageSBOMStats summarizes an image SBOM
+type ImageSBOMStats struct {
+	Distro       string                `json:"distro,omitempty"` // PRETTY_NAME, or ID and VERSION_ID
+	OSPackages   int                   `json:"os_packages"`
+	LangPackages int                   `json:"language_packages"`
+	Databases    []string              `json:"databases,omitempty"`
+	Unsupported  []string              `json:"unsupported,omitempty"` // Package databases Zero cannot read
+	Failed       []ospkg.DatabaseError `json:"failed,omitempty"`      // Package databases that are unreadable or corrupt
+}
+
+// GenerateImageSBOM writes a CycloneDX SBOM of an unpacked container image:
+// the OS packages from the distribution's package database, plus the
+// language packages installed in the image (node_modules, Python
+// site-packages, gem specifications, Maven jars and the modules compiled
+// into Go binaries). Unlike generateNativeSBOM it reads what is installed,
+// not lockfiles, and records where each package was found as a zero:file
+// property. The image itself is the metadata component.
+func GenerateImageSBOM(rootfs, sbomFile string, image *cyclonedx.Component) (*ImageSBOMStats, error) {
+	inv, err := ospkg.Scan(rootfs)
+	if err != nil {
+		return nil, err
+	}
+	stats := &ImageSBOMStats{
+		OSPackages:  len(inv.Packages),
+		Databases:   inv.Databases,
+		Unsupported: inv.Unsupported,
+		Failed:      inv.Failed,
+	}
+
+	components := make(map[string]*cyclonedx.Component)
+	add := func(c *cyclonedx.Component) {
+		if existing, ok := components[c.Purl]; ok {
+			// The same package installed in several places
+			if len(existing.Licenses) == 0 {
+				existing.Licenses = c.Licenses
+			}
+			return
+		}
+		components[c.Purl] = c
+	}
+
+	if d := inv.Distro; d != nil {
+		stats.Distro = d.Name
+		if stats.Distro == "" {
+			stats.Distro = strings.TrimSpace(d.ID + " " + d.VersionID)
+		}
+		c := &cyclonedx.Component{
+			Type:        cyclonedx.ComponentTypeOS,
+			BOMRef:      "os:" + d.ID + "@" + d.VersionID,
+			Name:        d.ID,
+			Version:     d.VersionID,
+			Description: d.Name,
+		}
+		components[c.BOMRef] = c
+	}
+	for _, p := range inv.Packages {
+		purl := p.PURL(inv.Distro)
+		c := &cyclonedx.Component{
+			Type:     cyclonedx.ComponentTypeLibrary,
+			BOMRef:   purl,
+			Name:     p.Name,
+			Version:  p.Version,
+			Purl:     purl,
+			Licenses: licenseChoices(p.License),
+		}
+		if p.Maintainer != "" {
+			c.Supplier = &cyclonedx.OrgEntity{Name: p.Maintainer}
+		}
+		c.AddProperty("zero:file", "/"+p.Database)
+		if p.Source != "" && p.Source != p.Name {
+			c.AddProperty("zero:source_package", p.Source)
+		}
+		add(c)
+	}
+
+	osCount := len(components)
+	if err := walkImagePackages(rootfs, add); err != nil {
+		return nil, err
+	}
+	stats.LangPackages = len(components) - osCount
+
+	bom := cyclonedx.NewSBOM()
+	bom.WithMetadataComponent(image)
+	refs := make([]string, 0, len(components))
+	for ref := range components {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		bom.WithComponent(*components[ref])
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(sbomFile), 0755); err != nil {
+		return nil, err
+	}
+	return stats, os.WriteFile(sbomFile, data, 0644)
+}
+
+// walkImagePackages finds the language packages installed in an image.
+// WalkDir does not follow symlinks and only regular files are read, so
+// links in the image cannot lead the walk outside it.
+func walkImagePackages(rootfs string, add func(*cyclonedx.Component)) error {
+	return filepath.WalkDir(rootfs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(rootfs, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			switch rel {
+			case "proc", "sys", "dev":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		name := d.Name()
+		dir := path.Dir(rel)
+		var found []*cyclonedx.Component
+		switch {
+		case name == "package.json" && isNodeModule(dir):
+			found = nodeModule(p)
+		case name == "METADATA" && strings.HasSuffix(dir, ".dist-info"),
+			name == "PKG-INFO" && strings.HasSuffix(dir, ".egg-info"):
+			found = pythonDist(p)
+		case strings.HasSuffix(name, ".gemspec") && path.Base(dir) == "specifications":
+			found = installedGem(p, name)
+		case strings.HasSuffix(name, ".jar") || strings.HasSuffix(name, ".war") || strings.HasSuffix(name, ".ear"):
+			found = javaArchive(p)
+		default:
+			if info, err := d.Info(); err == nil && info.Mode()&0111 != 0 && info.Size() > 0 {
+				found = goBinary(p)
+			}
+		}
+		for _, c := range found {
+			c.AddProperty("zero:file", "/"+rel)
+			add(c)
+		}
+		return nil
+	})
+}
+
+// isNodeModule reports whether dir is a package directly inside
+// node_modules, e.g. node_modules/lodash or node_modules/@babel/core
+func isNodeModule(dir string) bool {
+	parent := path.Dir(dir)
+	if strings.HasPrefix(path.Base(parent), "@") {
+		parent = path.Dir(parent)
+	}
+	return path.Base(parent) == "node_modules"
+}
+
+func readSmallFile(p string) ([]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, maxManifestSize))
+}
+
+func nodeModule(p string) []*cyclonedx.Component {
+	data, err := readSmallFile(p)
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Name    string          `json:"name"`
+		Version string          `json:"version"`
+		License json.RawMessage `json:"license"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || pkg.Name == "" || pkg.Version == "" {
+		return nil
+	}
+	// "license": "MIT" or the older "license": {"type": "MIT"}
+	var license string
+	if json.Unmarshal(pkg.License, &license) != nil {
+		var obj struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(pkg.License, &obj)
+		license = obj.Type
+	}
+	return []*cyclonedx.Component{imageComponent("npm", "", pkg.Name, pkg.Version, license)}
+}
+
+// pythonDist reads the core metadata of an installed Python distribution
+func pythonDist(p string) []*cyclonedx.Component {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(io.LimitReader(f, maxManifestSize))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // The description follows the headers
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if ok && fields[key] == "" {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	if fields["Name"] == "" || fields["Version"] == "" {
+		return nil
+	}
+	license := fields["License-Expression"]
+	if license == "" && len(fields["License"]) <= 64 {
+		// Some projects paste the whole license text here
+		license = fields["License"]
+	}
+	return []*cyclonedx.Component{imageComponent("pypi", "", fields["Name"], fields["Version"], license)}
+}
+
+var gemLicensesRegex = regexp.MustCompile(`\.licenses\s*=\s*\[([^\]]*)\]`)
+var quotedRegex = regexp.MustCompile(`"([^"]+)"`)
+
+// installedGem reads an installed gem's specification, named name-version.gemspec
+func installedGem(p, file string) []*cyclonedx.Component {
+	base := strings.TrimSuffix(file, ".gemspec")
+	// Names may contain dashes; the version is the first part starting with a digit
+	parts := strings.Split(base, "-")
+	i := 1
+	for i < len(parts) && (parts[i] == "" || parts[i][0] < '0' || parts[i][0] > '9') {
+		i++
+	}
+	if i >= len(parts) {
+		return nil
+	}
+	name, version := strings.Join(parts[:i], "-"), strings.Join(parts[i:], "-")
+
+	var license string
+	if data, err := readSmallFile(p); err == nil {
+		if m := gemLicensesRegex.FindSubmatch(data); m != nil {
+			var ids []string
+			for _, q := range quotedRegex.FindAllSubmatch(m[1], -1) {
+				ids = append(ids, string(q[1]))
+			}
+			license = strings.Join(ids, " OR ")
+		}
+	}
+	return []*cyclonedx.Component{imageComponent("gem", "", name, version, license)}
+}
+
+// javaArchive reads the Maven coordinates embedded in a jar, and in the
+// jars nested one level inside it (war, ear and Spring Boot fat jars)
+func javaArchive(p string) []*cyclonedx.Component {
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+	return mavenComponents(&zr.Reader, true)
+}
+
+func mavenComponents(zr *zip.Reader, nested bool) []*cyclonedx.Component {
+	var found []*cyclonedx.Component
+	for _, f := range zr.File {
+		switch {
+		case strings.HasPrefix(f.Name, "META-INF/maven/") && strings.HasSuffix(f.Name, "/pom.properties"):
+			props := readPomProperties(f)
+			if props["groupId"] != "" && props["artifactId"] != "" && props["version"] != "" {
+				found = append(found, imageComponent("maven", props["groupId"], props["artifactId"], props["version"], ""))
+			}
+		case nested && strings.HasSuffix(f.Name, ".jar") && f.UncompressedSize64 <= maxNestedJarSize:
+			rc, err := f.Open()
+			if err != nil {
+				continue
+			}
+			data, err := io.ReadAll(io.LimitReader(rc, maxNestedJarSize))
+			rc.Close()
+			if err != nil {
+				continue
+			}
+			if inner, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err == nil {
+				found = append(found, mavenComponents(inner, false)...)
+			}
+		}
+	}
+	return found
+}
+
+func readPomProperties(f *zip.File) map[string]string {
+	props := make(map[string]string)
+	rc, err := f.Open()
+	if err != nil {
+		return props
+	}
+	defer rc.Close()
+	scanner := bufio.NewScanner(io.LimitReader(rc, maxManifestSize))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			props[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return props
+}
+
+// goBinary reads the module information the Go linker embeds in binaries:
+// the main module, its dependencies and the Go standard library
+func goBinary(p string) []*cyclonedx.Component {
+	info, err := buildinfo.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var found []*cyclonedx.Component
+	if m := info.Main; m.Path != "" && m.Version != "" && m.Version != "(devel)" {
+		found = append(found, imageComponent("golang", "", m.Path, m.Version, ""))
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		// Replacements with local directories have no version
+		if dep.Path != "" && dep.Version != "" {
+			found = append(found, imageComponent("golang", "", dep.Path, dep.Version, ""))
+		}
+	}
+	if v := strings.TrimPrefix(info.GoVersion, "go"); v != "" {
+		// Drop experiment suffixes, e.g. go1.22.1 X:nocoverageredesign
+		v, _, _ = strings.Cut(v, " ")
+		found = append(found, imageComponent("golang", "", "stdlib", v, ""))
+	}
+	return found
+}
+
+func imageComponent(ecosystem, group, name, version, license string) *cyclonedx.Component {
+	purl := packageURL(ecosystem, group, name, version)
+	return &cyclonedx.Component{
+		Type:     cyclonedx.ComponentTypeLibrary,
+		BOMRef:   purl,
+		Group:    group,
+		Name:     name,
+		Version:  version,
+		Purl:     purl,
+		Licenses: licenseChoices(license),
+	}
+}
+
+// licenseChoices turns a declared license into CycloneDX form: an SPDX ID,
+// an expression, or a free-text name
+func licenseChoices(license string) []cyclonedx.LicenseChoice {
+	license = strings.TrimSpace(license)
+	switch {
+	case license == "" || strings.EqualFold(license, "UNKNOWN"):
+		return nil
+	case strings.Contains(license, " AND ") || strings.Contains(license, " OR "):
+		return []cyclonedx.LicenseChoice{{Expression: license}}
+	case licenses.Known(license):
+		return []cyclonedx.LicenseChoice{{License: &cyclonedx.License{ID: license}}}
+	default:
+		return []cyclonedx.LicenseChoice{{License: &cyclonedx.License{Name: license}}}
+	}
+}
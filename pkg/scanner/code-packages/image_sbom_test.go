@@ -0,0 +1,158 @@
+package codepackages
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+)
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerateImageSBOM(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, "etc/os-release", "ID=alpine\nVERSION_ID=3.20.1\nPRETTY_NAME=\"Alpine Linux v3.20\"\n")
+	writeTestFile(t, root, "lib/apk/db/installed", "P:musl\nV:1.2.5-r0\nA:x86_64\nL:MIT\no:musl\n\nP:libssl3\nV:3.3.1-r0\nA:x86_64\nL:Apache-2.0\no:openssl\n")
+
+	writeTestFile(t, root, "app/node_modules/lodash/package.json", `{"name": "lodash", "version": "4.17.20", "license": "MIT"}`)
+	writeTestFile(t, root, "app/node_modules/@babel/core/package.json", `{"name": "@babel/core", "version": "7.24.0", "license": {"type": "MIT"}}`)
+	// package.json files of a package's own sources are not packages
+	writeTestFile(t, root, "app/node_modules/lodash/fp/package.json", `{"name": "lodash-fp", "version": "0.0.0"}`)
+	writeTestFile(t, root, "app/package.json", `{"name": "my-app", "version": "1.0.0"}`)
+
+	writeTestFile(t, root, "usr/lib/python3.12/site-packages/Jinja2-3.1.2.dist-info/METADATA",
+		"Metadata-Version: 2.1\nName: Jinja2\nVersion: 3.1.2\nLicense: BSD-3-Clause\n\nLicense: not a header\n")
+	writeTestFile(t, root, "usr/lib/ruby/gems/3.3.0/specifications/aws-sdk-s3-1.151.0.gemspec",
+		"Gem::Specification.new do |s|\n  s.name = \"aws-sdk-s3\".freeze\n  s.licenses = [\"Apache-2.0\".freeze]\nend\n")
+
+	jar := zipBytes(t, map[string][]byte{
+		"META-INF/maven/org.slf4j/slf4j-api/pom.properties": []byte("#Generated by Maven\ngroupId=org.slf4j\nartifactId=slf4j-api\nversion=2.0.13\n"),
+	})
+	war := zipBytes(t, map[string][]byte{
+		"WEB-INF/lib/slf4j-api-2.0.13.jar":               []byte(jar),
+		"META-INF/maven/com.example/shop/pom.properties": []byte("groupId=com.example\nartifactId=shop\nversion=1.4.0\n"),
+	})
+	writeTestFile(t, root, "opt/shop.war", string(war))
+
+	// A Go binary: this test binary carries build info like any other
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	bin := filepath.Join(root, "usr/local/bin/app")
+	if err := os.MkdirAll(filepath.Dir(bin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := os.OpenFile(bin, os.O_CREATE|os.O_WRONLY, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	dst.Close()
+
+	// Links are not followed out of the image
+	if err := os.Symlink(self, filepath.Join(root, "usr/local/bin/host-binary")); err != nil {
+		t.Fatal(err)
+	}
+
+	sbomFile := filepath.Join(t.TempDir(), "image.cdx.json")
+	image := &cyclonedx.Component{Type: cyclonedx.ComponentTypeContainer, BOMRef: "app", Name: "app", Version: "1.0"}
+	stats, err := GenerateImageSBOM(root, sbomFile, image)
+	if err != nil {
+		t.Fatalf("GenerateImageSBOM() error = %v", err)
+	}
+	if stats.OSPackages != 2 || stats.Distro != "Alpine Linux v3.20" {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	_, findings, err := summarizeSBOM(sbomFile, "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPurl := make(map[string]Component)
+	var stdlib, zeroFile int
+	for _, c := range findings.Components {
+		byPurl[c.Purl] = c
+		if strings.HasPrefix(c.Purl, "pkg:golang/stdlib@") {
+			stdlib++
+		}
+	}
+	for _, purl := range []string{
+		"pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64&distro=alpine-3.20.1",
+		"pkg:npm/lodash@4.17.20",
+		"pkg:npm/%40babel/core@7.24.0",
+		"pkg:pypi/jinja2@3.1.2",
+		"pkg:gem/aws-sdk-s3@1.151.0",
+		"pkg:maven/org.slf4j/slf4j-api@2.0.13",
+		"pkg:maven/com.example/shop@1.4.0",
+	} {
+		if _, ok := byPurl[purl]; !ok {
+			t.Errorf("missing %s", purl)
+		}
+	}
+	for _, purl := range []string{"pkg:npm/lodash-fp@0.0.0", "pkg:npm/my-app@1.0.0"} {
+		if _, ok := byPurl[purl]; ok {
+			t.Errorf("%s is not an installed package", purl)
+		}
+	}
+	if stdlib != 1 {
+		t.Errorf("found %d Go stdlib components, want 1 from usr/local/bin/app", stdlib)
+	}
+
+	if lic := byPurl["pkg:npm/%40babel/core@7.24.0"].Licenses; len(lic) != 1 || lic[0] != "MIT" {
+		t.Errorf("@babel/core licenses = %v", lic)
+	}
+	if lic := byPurl["pkg:pypi/jinja2@3.1.2"].Licenses; len(lic) != 1 || lic[0] != "BSD-3-Clause" {
+		t.Errorf("Jinja2 licenses = %v", lic)
+	}
+	if lic := byPurl["pkg:gem/aws-sdk-s3@1.151.0"].Licenses; len(lic) != 1 || lic[0] != "Apache-2.0" {
+		t.Errorf("aws-sdk-s3 licenses = %v", lic)
+	}
+
+	bom, err := parseSBOM(sbomFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bom.Metadata.Component.Type != cyclonedx.ComponentTypeContainer {
+		t.Errorf("metadata component = %+v, want the image", bom.Metadata.Component)
+	}
+	for _, c := range bom.Components {
+		for _, p := range c.Properties {
+			if p.Name == "zero:file" && strings.HasPrefix(p.Value, "/") {
+				zeroFile++
+			}
+		}
+	}
+	if zeroFile != len(bom.Components)-1 {
+		t.Errorf("%d of %d packages record where they were found", zeroFile, len(bom.Components)-1)
+	}
+}
@@ -0,0 +1,1468 @@
+package codepackages
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// =============================================================================
+// Lockfile Parsing
+// =============================================================================
+
+// lockfileEcosystems maps the supported lockfile names to their ecosystem
+// (the purl type of their packages)
+var lockfileEcosystems = map[string]string{
+	"package-lock.json":  "npm",
+	"yarn.lock":          "npm",
+	"pnpm-lock.yaml":     "npm",
+	"go.sum":             "golang",
+	"requirements.txt":   "pypi",
+	"poetry.lock":        "pypi",
+	"uv.lock":            "pypi",
+	"Cargo.lock":         "cargo",
+	"Gemfile.lock":       "gem",
+	"composer.lock":      "composer",
+	"packages.lock.json": "nuget",
+	"gradle.lockfile":    "maven",
+}
+
+// lockPackage is a package version resolved in a lockfile
+type lockPackage struct {
+	Name      string
+	Group     string // Maven group ID
+	Version   string
+	Dev       bool // Only needed for development (tests, builds)
+	Hashes    []Hash
+	DependsOn []int // Indexes into lockfileData.Packages
+}
+
+// lockfileData holds the packages resolved by one lockfile. The dependency
+// graph and direct dependencies are only filled in when the lockfile (or
+// its manifest) records them.
+type lockfileData struct {
+	Path      string
+	Ecosystem string
+	Packages  []lockPackage
+	Direct    []int // Indexes of the project's direct dependencies
+
+	index map[string]int   // name@version -> index
+	names map[string][]int // name -> indexes
+}
+
+// parseLockfile parses a supported lockfile
+func parseLockfile(path string) (*lockfileData, error) {
+	base := filepath.Base(path)
+	ecosystem, ok := lockfileEcosystems[base]
+	if !ok {
+		return nil, fmt.Errorf("unsupported lockfile: %s", base)
+	}
+	lf := &lockfileData{
+		Path:      path,
+		Ecosystem: ecosystem,
+		index:     make(map[string]int),
+		names:     make(map[string][]int),
+	}
+
+	var err error
+	switch base {
+	case "package-lock.json":
+		err = parsePackageLock(path, lf)
+	case "yarn.lock":
+		err = parseYarnLock(path, lf)
+	case "pnpm-lock.yaml":
+		err = parsePnpmLock(path, lf)
+	case "go.sum":
+		err = parseGoSum(path, lf)
+	case "requirements.txt":
+		err = parseRequirements(path, lf)
+	case "poetry.lock":
+		err = parsePoetryLock(path, lf)
+	case "uv.lock":
+		err = parseUvLock(path, lf)
+	case "Cargo.lock":
+		err = parseCargoLock(path, lf)
+	case "Gemfile.lock":
+		err = parseGemfileLock(path, lf)
+	case "composer.lock":
+		err = parseComposerLock(path, lf)
+	case "packages.lock.json":
+		err = parseNugetLock(path, lf)
+	case "gradle.lockfile":
+		err = parseGradleLock(path, lf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", base, err)
+	}
+	return lf, nil
+}
+
+// normalizePackageName returns the name packages are matched by within an
+// ecosystem: PyPI names are compared per PEP 503 and NuGet ids are case
+// insensitive
+func normalizePackageName(ecosystem, name string) string {
+	switch ecosystem {
+	case "pypi":
+		name = strings.ToLower(name)
+		return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	case "nuget":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+func (lf *lockfileData) key(p lockPackage) string {
+	name := normalizePackageName(lf.Ecosystem, p.Name)
+	if p.Group != "" {
+		name = p.Group + ":" + name
+	}
+	return name + "@" + p.Version
+}
+
+// add adds a package and returns its index. A package already added (the
+// same version installed in several places) is merged: it stays a dev
+// dependency only if every occurrence is one.
+func (lf *lockfileData) add(p lockPackage) int {
+	key := lf.key(p)
+	if i, ok := lf.index[key]; ok {
+		existing := &lf.Packages[i]
+		existing.Dev = existing.Dev && p.Dev
+		if len(existing.Hashes) == 0 {
+			existing.Hashes = p.Hashes
+		}
+		return i
+	}
+	i := len(lf.Packages)
+	lf.Packages = append(lf.Packages, p)
+	lf.index[key] = i
+	name := normalizePackageName(lf.Ecosystem, p.Name)
+	lf.names[name] = append(lf.names[name], i)
+	return i
+}
+
+// lookup finds a package by name and, when version is not empty, version
+func (lf *lockfileData) lookup(name, version string) (int, bool) {
+	if version != "" {
+		i, ok := lf.index[normalizePackageName(lf.Ecosystem, name)+"@"+version]
+		return i, ok
+	}
+	if indexes := lf.names[normalizePackageName(lf.Ecosystem, name)]; len(indexes) > 0 {
+		return indexes[0], true
+	}
+	return 0, false
+}
+
+// addDependency records that package from depends on package to
+func (lf *lockfileData) addDependency(from, to int) {
+	if from == to {
+		return
+	}
+	for _, d := range lf.Packages[from].DependsOn {
+		if d == to {
+			return
+		}
+	}
+	lf.Packages[from].DependsOn = append(lf.Packages[from].DependsOn, to)
+}
+
+// addDirect records a direct dependency of the project
+func (lf *lockfileData) addDirect(i int) {
+	for _, d := range lf.Direct {
+		if d == i {
+			return
+		}
+	}
+	lf.Direct = append(lf.Direct, i)
+}
+
+// markDev marks the packages reachable only from the project's dev
+// dependencies as dev dependencies, for lockfiles that don't flag them
+func (lf *lockfileData) markDev(prod, dev []int) {
+	reachable := func(roots []int) []bool {
+		seen := make([]bool, len(lf.Packages))
+		stack := append([]int(nil), roots...)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			stack = append(stack, lf.Packages[i].DependsOn...)
+		}
+		return seen
+	}
+	inProd := reachable(prod)
+	inDev := reachable(dev)
+	for i := range lf.Packages {
+		lf.Packages[i].Dev = inDev[i] && !inProd[i]
+	}
+}
+
+// =============================================================================
+// Hash Helpers
+// =============================================================================
+
+// hashAlgorithms maps lockfile hash algorithm names to CycloneDX names
+var hashAlgorithms = map[string]string{
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+}
+
+// sriHashes converts a Subresource Integrity value ("sha512-<base64>", as
+// used by npm and yarn) to hashes
+func sriHashes(integrity string) []Hash {
+	var hashes []Hash
+	for _, field := range strings.Fields(integrity) {
+		alg, digest, ok := strings.Cut(field, "-")
+		if !ok {
+			continue
+		}
+		hashes = append(hashes, base64Hash(hashAlgorithms[alg], digest)...)
+	}
+	return hashes
+}
+
+// base64Hash converts a base64 digest to a hex hash
+func base64Hash(alg, digest string) []Hash {
+	raw, err := base64.StdEncoding.DecodeString(digest)
+	if alg == "" || err != nil || len(raw) == 0 {
+		return nil
+	}
+	return []Hash{{Algorithm: alg, Content: hex.EncodeToString(raw)}}
+}
+
+// prefixedHash converts an "alg:hex" digest (as used by pip, poetry and uv)
+// to a hash
+func prefixedHash(digest string) []Hash {
+	alg, content, ok := strings.Cut(digest, ":")
+	if !ok || hashAlgorithms[alg] == "" || content == "" {
+		return nil
+	}
+	return []Hash{{Algorithm: hashAlgorithms[alg], Content: strings.ToLower(content)}}
+}
+
+// =============================================================================
+// npm (package-lock.json, yarn.lock, pnpm-lock.yaml)
+// =============================================================================
+
+type npmLockPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Integrity            string            `json:"integrity"`
+	Dev                  bool              `json:"dev"`
+	DevOptional          bool              `json:"devOptional"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+}
+
+type npmLockV1Dependency struct {
+	Version      string                         `json:"version"`
+	Integrity    string                         `json:"integrity"`
+	Dev          bool                           `json:"dev"`
+	Requires     map[string]string              `json:"requires"`
+	Dependencies map[string]npmLockV1Dependency `json:"dependencies"`
+}
+
+func parsePackageLock(path string, lf *lockfileData) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var lock struct {
+		Packages     map[string]npmLockPackage      `json:"packages"`
+		Dependencies map[string]npmLockV1Dependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	packages := lock.Packages
+	if len(packages) == 0 {
+		// lockfileVersion 1 nests dependencies; flatten them into the
+		// install paths used by later versions
+		packages = make(map[string]npmLockPackage)
+		flattenNpmV1Dependencies("", lock.Dependencies, packages)
+		prod, dev := readPackageJSONDeps(filepath.Dir(path))
+		packages[""] = npmLockPackage{Dependencies: prod, DevDependencies: dev}
+	}
+
+	paths := make([]string, 0, len(packages))
+	for p := range packages {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	installed := make(map[string]int) // install path -> index
+	for _, p := range paths {
+		pkg := packages[p]
+		idx := strings.LastIndex(p, "node_modules/")
+		if idx < 0 || pkg.Link || pkg.Version == "" || strings.Contains(pkg.Version, ":") {
+			continue
+		}
+		name := pkg.Name
+		if name == "" {
+			name = p[idx+len("node_modules/"):]
+		}
+		installed[p] = lf.add(lockPackage{
+			Name:    name,
+			Version: pkg.Version,
+			Dev:     pkg.Dev || pkg.DevOptional,
+			Hashes:  sriHashes(pkg.Integrity),
+		})
+	}
+
+	for _, p := range paths {
+		pkg := packages[p]
+		from, isPackage := installed[p]
+		// The project and its workspaces are the entries outside node_modules
+		isProject := !strings.Contains(p, "node_modules/")
+		if !isPackage && !isProject {
+			continue
+		}
+
+		deps := sortedKeys(pkg.Dependencies, pkg.OptionalDependencies)
+		if isProject {
+			deps = append(deps, sortedKeys(pkg.DevDependencies)...)
+		}
+		for _, name := range deps {
+			to, ok := resolveNodeModule(installed, p, name)
+			if !ok {
+				continue
+			}
+			if isProject {
+				lf.addDirect(to)
+			} else {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// flattenNpmV1Dependencies converts the nested lockfileVersion 1 tree into
+// install paths ("node_modules/a/node_modules/b")
+func flattenNpmV1Dependencies(parent string, deps map[string]npmLockV1Dependency, out map[string]npmLockPackage) {
+	for name, dep := range deps {
+		p := "node_modules/" + name
+		if parent != "" {
+			p = parent + "/" + p
+		}
+		out[p] = npmLockPackage{
+			Version:      dep.Version,
+			Integrity:    dep.Integrity,
+			Dev:          dep.Dev,
+			Dependencies: dep.Requires,
+		}
+		flattenNpmV1Dependencies(p, dep.Dependencies, out)
+	}
+}
+
+// resolveNodeModule resolves a dependency the way Node does: from the
+// requiring package's own node_modules up through its ancestors
+func resolveNodeModule(installed map[string]int, from, name string) (int, bool) {
+	dir := from
+	for {
+		candidate := "node_modules/" + name
+		if dir != "" {
+			candidate = dir + "/" + candidate
+		}
+		if i, ok := installed[candidate]; ok {
+			return i, true
+		}
+		if dir == "" {
+			return 0, false
+		}
+		if idx := strings.LastIndex(dir, "/node_modules/"); idx >= 0 {
+			dir = dir[:idx]
+		} else {
+			dir = ""
+		}
+	}
+}
+
+// readPackageJSONDeps reads the dependencies and devDependencies declared
+// in the package.json in dir
+func readPackageJSONDeps(dir string) (prod, dev map[string]string) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, nil
+	}
+	var manifest struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil
+	}
+	prod = make(map[string]string)
+	for _, m := range []map[string]string{manifest.Dependencies, manifest.OptionalDependencies} {
+		for name, spec := range m {
+			prod[name] = spec
+		}
+	}
+	return prod, manifest.DevDependencies
+}
+
+// parseYarnLock parses classic (v1) and Berry yarn.lock files. Entries are
+// keyed by the specs that resolve to them ("name@^1.0.0" or
+// "name@npm:^1.0.0"), which is also how their dependencies refer to them.
+func parseYarnLock(path string, lf *lockfileData) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	specs := make(map[string]int)
+	pending := make(map[int][]string)
+	var workspaceDeps []string
+
+	var entrySpecs, entryDeps []string
+	var entry lockPackage
+	workspace, inDeps := false, false
+	flush := func() {
+		switch {
+		case workspace:
+			workspaceDeps = append(workspaceDeps, entryDeps...)
+		case len(entrySpecs) > 0 && entry.Version != "":
+			i := lf.add(entry)
+			for _, spec := range entrySpecs {
+				specs[spec] = i
+			}
+			pending[i] = append(pending[i], entryDeps...)
+		}
+		entrySpecs, entryDeps, entry, workspace = nil, nil, lockPackage{}, false
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Entry header: "a@^1.0.0", a@^1.1.0:
+		if !strings.HasPrefix(line, " ") {
+			flush()
+			for _, spec := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				spec = strings.Trim(strings.TrimSpace(spec), `"`)
+				idx := strings.LastIndex(spec, "@")
+				if idx <= 0 {
+					continue
+				}
+				entry.Name = spec[:idx]
+				entrySpecs = append(entrySpecs, spec)
+				if strings.Contains(spec, "@workspace:") {
+					workspace = true
+				}
+			}
+			continue
+		}
+
+		key, value := splitYarnField(trimmed)
+		switch indent := len(line) - len(strings.TrimLeft(line, " ")); {
+		case indent == 2:
+			inDeps = key == "dependencies" || key == "optionalDependencies"
+			switch key {
+			case "version":
+				entry.Version = value
+			case "integrity":
+				entry.Hashes = sriHashes(value)
+			}
+		case indent == 4 && inDeps:
+			entryDeps = append(entryDeps, key+"@"+value)
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for from, deps := range pending {
+		for _, spec := range deps {
+			if to, ok := specs[spec]; ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+
+	// Dev-only packages are those reachable only from devDependencies
+	resolve := func(name, spec string) (int, bool) {
+		if i, ok := specs[name+"@"+spec]; ok {
+			return i, true
+		}
+		i, ok := specs[name+"@npm:"+spec]
+		return i, ok
+	}
+	prodDeps, devDeps := readPackageJSONDeps(filepath.Dir(path))
+	var prod, dev []int
+	for _, name := range sortedKeys(prodDeps) {
+		if i, ok := resolve(name, prodDeps[name]); ok {
+			prod = append(prod, i)
+		}
+	}
+	for _, spec := range workspaceDeps {
+		if i, ok := specs[spec]; ok {
+			prod = append(prod, i)
+		}
+	}
+	for _, name := range sortedKeys(devDeps) {
+		if i, ok := resolve(name, devDeps[name]); ok {
+			dev = append(dev, i)
+		}
+	}
+	for _, i := range append(append([]int(nil), prod...), dev...) {
+		lf.addDirect(i)
+	}
+	if len(prod)+len(dev) > 0 {
+		lf.markDev(prod, dev)
+	}
+	return nil
+}
+
+// splitYarnField splits a yarn.lock field line in either format:
+// `version "1.0.0"` (v1) or `version: 1.0.0` (Berry)
+func splitYarnField(line string) (string, string) {
+	key, value, _ := strings.Cut(line, " ")
+	key = strings.Trim(strings.TrimSuffix(key, ":"), `"`)
+	return key, strings.Trim(strings.TrimSpace(value), `"`)
+}
+
+type pnpmLock struct {
+	Dependencies         map[string]interface{}  `yaml:"dependencies"`
+	DevDependencies      map[string]interface{}  `yaml:"devDependencies"`
+	OptionalDependencies map[string]interface{}  `yaml:"optionalDependencies"`
+	Importers            map[string]pnpmImporter `yaml:"importers"`
+	Packages             map[string]pnpmPackage  `yaml:"packages"`
+	Snapshots            map[string]pnpmPackage  `yaml:"snapshots"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]interface{} `yaml:"dependencies"`
+	DevDependencies      map[string]interface{} `yaml:"devDependencies"`
+	OptionalDependencies map[string]interface{} `yaml:"optionalDependencies"`
+}
+
+type pnpmPackage struct {
+	Resolution struct {
+		Integrity string `yaml:"integrity"`
+	} `yaml:"resolution"`
+	Dev                  *bool             `yaml:"dev"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// parsePnpmLock parses pnpm-lock.yaml v5 to v9. Packages are keyed
+// "/name/1.0.0" (v5), "/name@1.0.0" (v6) or "name@1.0.0" (v9, which moves
+// the dependency graph into "snapshots").
+func parsePnpmLock(path string, lf *lockfileData) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	devFlagged := false
+	for _, key := range sortedKeys(lock.Packages) {
+		name, version := pnpmPackageKey(key)
+		if name == "" {
+			continue
+		}
+		pkg := lock.Packages[key]
+		if pkg.Dev != nil {
+			devFlagged = true
+		}
+		lf.add(lockPackage{
+			Name:    name,
+			Version: version,
+			Dev:     pkg.Dev != nil && *pkg.Dev,
+			Hashes:  sriHashes(pkg.Resolution.Integrity),
+		})
+	}
+
+	for _, entries := range []map[string]pnpmPackage{lock.Packages, lock.Snapshots} {
+		for _, key := range sortedKeys(entries) {
+			from, ok := lf.lookup(pnpmPackageKey(key))
+			if !ok {
+				continue
+			}
+			pkg := entries[key]
+			for _, deps := range []map[string]string{pkg.Dependencies, pkg.OptionalDependencies} {
+				for _, name := range sortedKeys(deps) {
+					if to, ok := lf.lookup(pnpmDependency(name, deps[name])); ok {
+						lf.addDependency(from, to)
+					}
+				}
+			}
+		}
+	}
+
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]pnpmImporter{".": {
+			Dependencies:         lock.Dependencies,
+			DevDependencies:      lock.DevDependencies,
+			OptionalDependencies: lock.OptionalDependencies,
+		}}
+	}
+	var prod, dev []int
+	roots := func(deps map[string]interface{}) []int {
+		var out []int
+		for _, name := range sortedKeys(deps) {
+			version := ""
+			switch v := deps[name].(type) {
+			case string:
+				version = v
+			case map[string]interface{}:
+				version, _ = v["version"].(string)
+			}
+			if i, ok := lf.lookup(pnpmDependency(name, version)); ok {
+				out = append(out, i)
+			}
+		}
+		return out
+	}
+	for _, dir := range sortedKeys(importers) {
+		imp := importers[dir]
+		prod = append(prod, roots(imp.Dependencies)...)
+		prod = append(prod, roots(imp.OptionalDependencies)...)
+		dev = append(dev, roots(imp.DevDependencies)...)
+	}
+	for _, i := range append(append([]int(nil), prod...), dev...) {
+		lf.addDirect(i)
+	}
+	if !devFlagged {
+		lf.markDev(prod, dev)
+	}
+	return nil
+}
+
+// pnpmPackageKey splits a pnpm package key into name and version, dropping
+// peer dependency suffixes ("1.0.0(react@18.2.0)" or "1.0.0_react@18.2.0")
+func pnpmPackageKey(key string) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if idx := strings.Index(key, "("); idx > 0 {
+		key = key[:idx]
+	}
+	var name, version string
+	if idx := strings.LastIndex(key, "@"); idx > 0 {
+		name, version = key[:idx], key[idx+1:]
+	} else {
+		// v5: name/version or @scope/name/version
+		idx := strings.LastIndex(key, "/")
+		if idx <= 0 {
+			return "", ""
+		}
+		name, version = key[:idx], key[idx+1:]
+	}
+	if idx := strings.Index(version, "_"); idx > 0 {
+		version = version[:idx]
+	}
+	if strings.Contains(version, ":") {
+		return "", "" // link:, file: and other non-registry versions
+	}
+	return name, version
+}
+
+// pnpmDependency resolves a dependency entry ("name: version") to the
+// package it refers to; aliased entries carry the package key as version
+func pnpmDependency(name, version string) (string, string) {
+	if strings.HasPrefix(version, "/") || strings.Contains(strings.SplitN(version, "(", 2)[0], "@") {
+		return pnpmPackageKey(version)
+	}
+	return pnpmPackageKey(name + "@" + version)
+}
+
+// =============================================================================
+// Go (go.sum, go.mod)
+// =============================================================================
+
+// goRequire is a module requirement in go.mod
+type goRequire struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+// parseGoSum parses go.sum for module hashes. The modules themselves come
+// from the go.mod next to it when there is one, since go.sum also lists
+// modules that were only consulted during version selection.
+func parseGoSum(path string, lf *lockfileData) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hashes := make(map[string][]Hash) // module@version -> h1 hash
+	var modules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		key := fields[0] + "@" + fields[1]
+		if _, seen := hashes[key]; !seen {
+			modules = append(modules, key)
+		}
+		hashes[key] = base64Hash("SHA-256", strings.TrimPrefix(fields[2], "h1:"))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if requires, err := readGoModRequires(filepath.Join(filepath.Dir(path), "go.mod")); err == nil && len(requires) > 0 {
+		for _, r := range requires {
+			i := lf.add(lockPackage{Name: r.Path, Version: r.Version, Hashes: hashes[r.Path+"@"+r.Version]})
+			if !r.Indirect {
+				lf.addDirect(i)
+			}
+		}
+		return nil
+	}
+
+	for _, key := range modules {
+		idx := strings.LastIndex(key, "@")
+		lf.add(lockPackage{Name: key[:idx], Version: key[idx+1:], Hashes: hashes[key]})
+	}
+	return nil
+}
+
+// readGoModRequires reads the require directives of a go.mod file
+func readGoModRequires(path string) ([]goRequire, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var requires []goRequire
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inBlock:
+			continue
+		}
+
+		spec, comment, _ := strings.Cut(line, "//")
+		fields := strings.Fields(spec)
+		if len(fields) != 2 {
+			continue
+		}
+		requires = append(requires, goRequire{
+			Path:     fields[0],
+			Version:  fields[1],
+			Indirect: strings.TrimSpace(comment) == "indirect",
+		})
+	}
+	return requires, scanner.Err()
+}
+
+// =============================================================================
+// Python (requirements.txt, poetry.lock, uv.lock)
+// =============================================================================
+
+// parseRequirements parses requirements.txt. Only exact pins ("==") carry a
+// version; "--hash" options (pip's hash-checking mode) carry hashes.
+func parseRequirements(path string, lf *lockfileData) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Join continuation lines
+	text := strings.ReplaceAll(string(data), "\\\r\n", " ")
+	text = strings.ReplaceAll(text, "\\\n", " ")
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = line[:idx]
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+
+		spec, options := line, ""
+		if idx := strings.Index(line, " -"); idx >= 0 {
+			spec, options = line[:idx], line[idx:]
+		}
+		if idx := strings.Index(spec, ";"); idx >= 0 {
+			spec = spec[:idx] // environment marker
+		}
+		if idx := strings.Index(spec, " @ "); idx >= 0 {
+			spec = spec[:idx] // direct URL reference
+		}
+
+		var name, version string
+		for _, sep := range []string{"==", ">=", "<=", "~=", "!=", ">", "<"} {
+			if idx := strings.Index(spec, sep); idx > 0 {
+				name = spec[:idx]
+				if sep == "==" && !strings.Contains(spec, ",") {
+					version = strings.TrimSpace(strings.TrimLeft(spec[idx+len(sep):], "="))
+				}
+				break
+			}
+		}
+		if name == "" {
+			name = spec
+		}
+		if idx := strings.Index(name, "["); idx > 0 {
+			name = name[:idx] // extras
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/:") {
+			continue
+		}
+
+		var hashes []Hash
+		for _, opt := range strings.Fields(options) {
+			if digest, ok := strings.CutPrefix(opt, "--hash="); ok {
+				hashes = prefixedHash(digest)
+				break
+			}
+		}
+		lf.add(lockPackage{Name: name, Version: version, Hashes: hashes})
+	}
+	return nil
+}
+
+// tomlPackage is one [[package]] entry of a TOML lockfile (poetry.lock,
+// uv.lock, Cargo.lock). Values are kept as raw TOML: the lockfiles are
+// regular enough that a handful of keys can be read without a TOML parser.
+type tomlPackage struct {
+	values map[string]string            // key -> raw value
+	tables map[string]map[string]string // [package.<table>] -> key -> raw value
+}
+
+var (
+	tomlStringPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+	tomlNamePattern   = regexp.MustCompile(`\bname\s*=\s*"([^"]+)"`)
+	tomlHashPattern   = regexp.MustCompile(`\bhash\s*=\s*"([^"]+)"`)
+	tomlFilePattern   = regexp.MustCompile(`\bfile\s*=\s*"([^"]+)"`)
+)
+
+// readTOMLPackages reads the [[package]] entries of a TOML lockfile, along
+// with any other top-level tables (such as poetry's [metadata.files])
+func readTOMLPackages(path string) ([]*tomlPackage, map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var packages []*tomlPackage
+	tables := make(map[string]map[string]string)
+	var current *tomlPackage
+	table := ""
+
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && !strings.Contains(line, "=") {
+			header := strings.Trim(line, "[]")
+			switch {
+			case line == "[[package]]":
+				current = &tomlPackage{values: make(map[string]string), tables: make(map[string]map[string]string)}
+				packages = append(packages, current)
+				table = ""
+			case current != nil && strings.HasPrefix(header, "package."):
+				table = strings.TrimPrefix(header, "package.")
+			default:
+				current = nil
+				table = header
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value = strings.TrimSpace(value)
+		// Multi-line arrays and inline tables continue until balanced
+		for tomlDepth(value) > 0 && i+1 < len(lines) {
+			i++
+			value += "\n" + strings.TrimSpace(lines[i])
+		}
+
+		var target map[string]string
+		switch {
+		case current != nil && table == "":
+			target = current.values
+		case current != nil:
+			if current.tables[table] == nil {
+				current.tables[table] = make(map[string]string)
+			}
+			target = current.tables[table]
+		default:
+			if tables[table] == nil {
+				tables[table] = make(map[string]string)
+			}
+			target = tables[table]
+		}
+		target[key] = value
+	}
+	return packages, tables, nil
+}
+
+// tomlDepth returns how many arrays and inline tables are left open in a
+// raw TOML value
+func tomlDepth(value string) int {
+	depth := 0
+	inString, escaped := false, false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && r == '\\':
+			escaped = true
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// tomlString returns the value of a raw TOML string
+func tomlString(raw string) string {
+	if m := tomlStringPattern.FindStringSubmatch(raw); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// tomlStrings returns the strings in a raw TOML array
+func tomlStrings(raw string) []string {
+	var out []string
+	for _, m := range tomlStringPattern.FindAllStringSubmatch(raw, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+// tomlNames returns the names in a raw TOML array of inline tables
+// ([{ name = "a" }, { name = "b", marker = "..." }])
+func tomlNames(raw string) []string {
+	var out []string
+	for _, m := range tomlNamePattern.FindAllStringSubmatch(raw, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+// tomlFileHash returns the first hash in a raw TOML array of file entries,
+// preferring the source distribution
+func tomlFileHash(raw string) []Hash {
+	var first []Hash
+	for _, entry := range strings.Split(raw, "}") {
+		m := tomlHashPattern.FindStringSubmatch(entry)
+		if m == nil {
+			continue
+		}
+		if f := tomlFilePattern.FindStringSubmatch(entry); f != nil && strings.HasSuffix(f[1], ".tar.gz") {
+			return prefixedHash(m[1])
+		}
+		if first == nil {
+			first = prefixedHash(m[1])
+		}
+	}
+	return first
+}
+
+func parsePoetryLock(path string, lf *lockfileData) error {
+	packages, tables, err := readTOMLPackages(path)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[int][]string)
+	for _, p := range packages {
+		name := tomlString(p.values["name"])
+		if name == "" {
+			continue
+		}
+		// Poetry < 1.5 records "category", later versions "groups"
+		dev := tomlString(p.values["category"]) == "dev"
+		if groups, ok := p.values["groups"]; ok {
+			dev = true
+			for _, g := range tomlStrings(groups) {
+				if g == "main" {
+					dev = false
+				}
+			}
+		}
+		hashes := tomlFileHash(p.values["files"])
+		if hashes == nil {
+			// Poetry < 1.2 keeps hashes under [metadata.files]
+			hashes = tomlFileHash(tables["metadata.files"][name])
+		}
+		i := lf.add(lockPackage{
+			Name:    name,
+			Version: tomlString(p.values["version"]),
+			Dev:     dev,
+			Hashes:  hashes,
+		})
+		pending[i] = append(pending[i], sortedKeys(p.tables["dependencies"])...)
+	}
+
+	for from, deps := range pending {
+		for _, name := range deps {
+			if to, ok := lf.lookup(name, ""); ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// parseUvLock parses uv.lock. The project itself is the editable or
+// virtual package; its dependencies and dev-dependencies are the roots.
+func parseUvLock(path string, lf *lockfileData) error {
+	packages, _, err := readTOMLPackages(path)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[int][]string)
+	var prodRoots, devRoots []string
+	for _, p := range packages {
+		name := tomlString(p.values["name"])
+		if name == "" {
+			continue
+		}
+		deps := tomlNames(p.values["dependencies"])
+		for _, group := range sortedKeys(p.tables["optional-dependencies"]) {
+			deps = append(deps, tomlNames(p.tables["optional-dependencies"][group])...)
+		}
+
+		source := p.values["source"]
+		if strings.Contains(source, "editable") || strings.Contains(source, "virtual") {
+			prodRoots = append(prodRoots, deps...)
+			for _, group := range sortedKeys(p.tables["dev-dependencies"]) {
+				devRoots = append(devRoots, tomlNames(p.tables["dev-dependencies"][group])...)
+			}
+			continue
+		}
+
+		hashes := tomlFileHash(p.values["sdist"])
+		if hashes == nil {
+			hashes = tomlFileHash(p.values["wheels"])
+		}
+		i := lf.add(lockPackage{
+			Name:    name,
+			Version: tomlString(p.values["version"]),
+			Hashes:  hashes,
+		})
+		pending[i] = append(pending[i], deps...)
+	}
+
+	for from, deps := range pending {
+		for _, name := range deps {
+			if to, ok := lf.lookup(name, ""); ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+
+	resolve := func(names []string) []int {
+		var out []int
+		for _, name := range names {
+			if i, ok := lf.lookup(name, ""); ok {
+				out = append(out, i)
+				lf.addDirect(i)
+			}
+		}
+		return out
+	}
+	prod, dev := resolve(prodRoots), resolve(devRoots)
+	if len(prod)+len(dev) > 0 {
+		lf.markDev(prod, dev)
+	}
+	return nil
+}
+
+// =============================================================================
+// Rust (Cargo.lock)
+// =============================================================================
+
+// parseCargoLock parses Cargo.lock. Packages without a source are the
+// workspace's own crates; their dependencies are the direct ones.
+func parseCargoLock(path string, lf *lockfileData) error {
+	packages, _, err := readTOMLPackages(path)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[int][]string)
+	var direct []string
+	for _, p := range packages {
+		name := tomlString(p.values["name"])
+		if name == "" {
+			continue
+		}
+		deps := tomlStrings(p.values["dependencies"])
+		if p.values["source"] == "" {
+			direct = append(direct, deps...)
+			continue
+		}
+
+		var hashes []Hash
+		if checksum := tomlString(p.values["checksum"]); checksum != "" {
+			hashes = []Hash{{Algorithm: "SHA-256", Content: checksum}}
+		}
+		i := lf.add(lockPackage{
+			Name:    name,
+			Version: tomlString(p.values["version"]),
+			Hashes:  hashes,
+		})
+		pending[i] = append(pending[i], deps...)
+	}
+
+	// Dependencies are "name", or "name version [(source)]" when several
+	// versions of a crate are locked
+	resolve := func(dep string) (int, bool) {
+		fields := strings.Fields(dep)
+		if len(fields) == 0 {
+			return 0, false
+		}
+		if len(fields) > 1 {
+			if i, ok := lf.lookup(fields[0], fields[1]); ok {
+				return i, true
+			}
+		}
+		return lf.lookup(fields[0], "")
+	}
+	for from, deps := range pending {
+		for _, dep := range deps {
+			if to, ok := resolve(dep); ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+	for _, dep := range direct {
+		if i, ok := resolve(dep); ok {
+			lf.addDirect(i)
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// Ruby (Gemfile.lock)
+// =============================================================================
+
+func parseGemfileLock(path string, lf *lockfileData) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	pending := make(map[int][]string)
+	checksums := make(map[string][]Hash) // "name (version)" -> hash
+	var direct []string
+	section := ""
+	inSpecs := false
+	current := -1
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			section, inSpecs, current = trimmed, false, -1
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch section {
+		case "GEM", "GIT", "PATH", "PLUGIN SOURCE":
+			if trimmed == "specs:" {
+				inSpecs = true
+				continue
+			}
+			if !inSpecs {
+				continue
+			}
+			name, version := gemSpec(trimmed)
+			switch {
+			case indent == 4 && section == "PATH":
+				// The project's own gems: their dependencies are direct
+				current = -1
+			case indent == 4:
+				current = lf.add(lockPackage{Name: name, Version: version})
+			case indent == 6 && current >= 0:
+				pending[current] = append(pending[current], name)
+			case indent == 6 && section == "PATH":
+				direct = append(direct, name)
+			}
+		case "DEPENDENCIES":
+			name, _ := gemSpec(trimmed)
+			direct = append(direct, strings.TrimSuffix(name, "!"))
+		case "CHECKSUMS":
+			spec, digest, ok := strings.Cut(trimmed, " sha256=")
+			if ok {
+				checksums[spec] = []Hash{{Algorithm: "SHA-256", Content: digest}}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for i, p := range lf.Packages {
+		lf.Packages[i].Hashes = checksums[p.Name+" ("+p.Version+")"]
+	}
+	for from, deps := range pending {
+		for _, name := range deps {
+			if to, ok := lf.lookup(name, ""); ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+	for _, name := range direct {
+		if i, ok := lf.lookup(name, ""); ok {
+			lf.addDirect(i)
+		}
+	}
+	return nil
+}
+
+// gemSpec splits "name (version)" into name and version
+func gemSpec(s string) (string, string) {
+	if idx := strings.Index(s, " ("); idx > 0 {
+		return s[:idx], strings.TrimSuffix(s[idx+2:], ")")
+	}
+	return s, ""
+}
+
+// =============================================================================
+// PHP (composer.lock)
+// =============================================================================
+
+type composerLockPackage struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
+	Require map[string]string `json:"require"`
+	Dist    struct {
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+}
+
+func parseComposerLock(path string, lf *lockfileData) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var lock struct {
+		Packages    []composerLockPackage `json:"packages"`
+		PackagesDev []composerLockPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	pending := make(map[int]map[string]string)
+	for _, group := range []struct {
+		packages []composerLockPackage
+		dev      bool
+	}{{lock.Packages, false}, {lock.PackagesDev, true}} {
+		for _, pkg := range group.packages {
+			var hashes []Hash
+			if pkg.Dist.Shasum != "" {
+				hashes = []Hash{{Algorithm: "SHA-1", Content: pkg.Dist.Shasum}}
+			}
+			i := lf.add(lockPackage{
+				Name: pkg.Name,
+				// Composer versions often have "v" prefix
+				Version: strings.TrimPrefix(pkg.Version, "v"),
+				Dev:     group.dev,
+				Hashes:  hashes,
+			})
+			pending[i] = pkg.Require
+		}
+	}
+	for from, require := range pending {
+		for _, name := range sortedKeys(require) {
+			if to, ok := lf.lookup(name, ""); ok {
+				lf.addDependency(from, to)
+			}
+		}
+	}
+
+	// Direct dependencies come from composer.json
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(path), "composer.json")); err == nil {
+		var manifest struct {
+			Require    map[string]string `json:"require"`
+			RequireDev map[string]string `json:"require-dev"`
+		}
+		if json.Unmarshal(data, &manifest) == nil {
+			for _, name := range sortedKeys(manifest.Require, manifest.RequireDev) {
+				if i, ok := lf.lookup(name, ""); ok {
+					lf.addDirect(i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// .NET (packages.lock.json)
+// =============================================================================
+
+type nugetLockPackage struct {
+	Type         string            `json:"type"`
+	Resolved     string            `json:"resolved"`
+	ContentHash  string            `json:"contentHash"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+func parseNugetLock(path string, lf *lockfileData) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var lock struct {
+		Dependencies map[string]map[string]nugetLockPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	// Dependencies are keyed by target framework, then by package name;
+	// versions may differ between frameworks
+	for _, framework := range sortedKeys(lock.Dependencies) {
+		packages := lock.Dependencies[framework]
+		resolved := make(map[string]int)
+		for _, name := range sortedKeys(packages) {
+			pkg := packages[name]
+			if pkg.Type == "Project" || pkg.Resolved == "" {
+				continue
+			}
+			i := lf.add(lockPackage{
+				Name:    name,
+				Version: pkg.Resolved,
+				Hashes:  base64Hash("SHA-512", pkg.ContentHash),
+			})
+			resolved[strings.ToLower(name)] = i
+			if pkg.Type == "Direct" {
+				lf.addDirect(i)
+			}
+		}
+		for _, name := range sortedKeys(packages) {
+			from, ok := resolved[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			for _, dep := range sortedKeys(packages[name].Dependencies) {
+				if to, ok := resolved[strings.ToLower(dep)]; ok {
+					lf.addDependency(from, to)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// Java (gradle.lockfile)
+// =============================================================================
+
+// parseGradleLock parses gradle.lockfile. Dependencies locked only for
+// test configurations are dev dependencies.
+func parseGradleLock(path string, lf *lockfileData) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Skip comments, empty lines and the "empty=" line
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "empty=") {
+			continue
+		}
+
+		// Format: group:artifact:version=configuration1,configuration2
+		coords, configurations, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(coords, ":")
+		if len(parts) < 3 {
+			continue
+		}
+		dev := true
+		for _, c := range strings.Split(configurations, ",") {
+			if !strings.HasPrefix(strings.ToLower(c), "test") {
+				dev = false
+			}
+		}
+		lf.add(lockPackage{Group: parts[0], Name: parts[1], Version: parts[2], Dev: dev})
+	}
+	return scanner.Err()
+}
+
+// sortedKeys returns the sorted keys of one or more maps
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
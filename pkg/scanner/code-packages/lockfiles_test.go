@@ -0,0 +1,447 @@
+package codepackages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// describeLockfile renders parsed packages as sorted
+// "name@version[ dev] -> deps" lines, with "* name" lines for direct deps
+func describeLockfile(lf *lockfileData) string {
+	var lines []string
+	for _, p := range lf.Packages {
+		line := p.Name + "@" + p.Version
+		if p.Group != "" {
+			line = p.Group + ":" + line
+		}
+		if p.Dev {
+			line += " dev"
+		}
+		var deps []string
+		for _, d := range p.DependsOn {
+			deps = append(deps, lf.Packages[d].Name)
+		}
+		if len(deps) > 0 {
+			sort.Strings(deps)
+			line += " -> " + strings.Join(deps, ",")
+		}
+		lines = append(lines, line)
+	}
+	for _, d := range lf.Direct {
+		lines = append(lines, "* "+lf.Packages[d].Name)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func TestParseLockfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "package-lock.json",
+			files: map[string]string{"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"express": "^4.18.0"}, "devDependencies": {"jest": "^29.0.0"}},
+    "node_modules/express": {"version": "4.18.2", "integrity": "sha512-AAAA", "dependencies": {"debug": "2.6.9"}},
+    "node_modules/debug": {"version": "4.3.4", "dev": true},
+    "node_modules/express/node_modules/debug": {"version": "2.6.9"},
+    "node_modules/jest": {"version": "29.7.0", "dev": true, "dependencies": {"debug": "^4"}},
+    "node_modules/local": {"resolved": "packages/local", "link": true}
+  }
+}`},
+			want: []string{"* express", "* jest", "debug@2.6.9", "debug@4.3.4 dev", "express@4.18.2 -> debug", "jest@29.7.0 dev -> debug"},
+		},
+		{
+			name: "yarn.lock",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"lodash": "^4.17.0"}, "devDependencies": {"@types/node": "^20.0.0"}}`,
+				"yarn.lock": `# yarn lockfile v1
+
+"@types/node@^20.0.0":
+  version "20.1.0"
+  integrity sha512-AAAA
+  dependencies:
+    undici-types "~5.26.4"
+
+lodash@^4.17.0, lodash@^4.17.21:
+  version "4.17.21"
+
+undici-types@~5.26.4:
+  version "5.26.5"
+`},
+			want: []string{"* @types/node", "* lodash", "@types/node@20.1.0 dev -> undici-types", "lodash@4.17.21", "undici-types@5.26.5 dev"},
+		},
+		{
+			name: "pnpm-lock.yaml",
+			files: map[string]string{"pnpm-lock.yaml": `lockfileVersion: '9.0'
+importers:
+  .:
+    dependencies:
+      react-dom:
+        specifier: ^18.2.0
+        version: 18.2.0(react@18.2.0)
+    devDependencies:
+      typescript:
+        specifier: ^5.0.0
+        version: 5.3.3
+packages:
+  react-dom@18.2.0:
+    resolution: {integrity: sha512-AAAA}
+  react@18.2.0:
+    resolution: {integrity: sha512-AAAA}
+  typescript@5.3.3:
+    resolution: {integrity: sha512-AAAA}
+snapshots:
+  react-dom@18.2.0(react@18.2.0):
+    dependencies:
+      react: 18.2.0
+  react@18.2.0: {}
+  typescript@5.3.3: {}
+`},
+			want: []string{"* react-dom", "* typescript", "react-dom@18.2.0 -> react", "react@18.2.0", "typescript@5.3.3 dev"},
+		},
+		{
+			name: "go.sum",
+			files: map[string]string{
+				"go.mod": `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	github.com/spf13/pflag v1.0.5 // indirect
+)
+`,
+				"go.sum": `github.com/spf13/cobra v1.8.0 h1:AAAA
+github.com/spf13/cobra v1.8.0/go.mod h1:AAAA
+github.com/spf13/pflag v1.0.5 h1:AAAA
+github.com/spf13/pflag v1.0.3/go.mod h1:AAAA
+`},
+			want: []string{"* github.com/spf13/cobra", "github.com/spf13/cobra@v1.8.0", "github.com/spf13/pflag@v1.0.5"},
+		},
+		{
+			name: "requirements.txt",
+			files: map[string]string{"requirements.txt": `# pinned
+Flask==3.0.0 \
+    --hash=sha256:aaaa
+requests[socks]>=2.0 ; python_version > "3.8"
+-r other.txt
+./local-package
+`},
+			want: []string{"Flask@3.0.0", "requests@"},
+		},
+		{
+			name: "poetry.lock",
+			files: map[string]string{"poetry.lock": `[[package]]
+name = "Requests"
+version = "2.31.0"
+optional = false
+groups = ["main"]
+files = [
+    {file = "requests-2.31.0-py3-none-any.whl", hash = "sha256:bbbb"},
+    {file = "requests-2.31.0.tar.gz", hash = "sha256:aaaa"},
+]
+
+[package.dependencies]
+charset_normalizer = ">=2,<4"
+
+[[package]]
+name = "charset-normalizer"
+version = "3.3.2"
+groups = ["main"]
+
+[[package]]
+name = "pytest"
+version = "8.0.0"
+groups = ["dev"]
+`},
+			want: []string{"Requests@2.31.0 -> charset-normalizer", "charset-normalizer@3.3.2", "pytest@8.0.0 dev"},
+		},
+		{
+			name: "uv.lock",
+			files: map[string]string{"uv.lock": `version = 1
+
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+dependencies = [
+    { name = "httpx" },
+]
+
+[package.dev-dependencies]
+dev = [
+    { name = "pytest" },
+]
+
+[package.metadata]
+requires-dist = [{ name = "httpx", specifier = ">=0.27" }]
+
+[[package]]
+name = "httpx"
+version = "0.27.0"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [{ name = "idna" }]
+sdist = { url = "https://example.com/httpx-0.27.0.tar.gz", hash = "sha256:aaaa", size = 1 }
+
+[[package]]
+name = "idna"
+version = "3.6"
+source = { registry = "https://pypi.org/simple" }
+
+[[package]]
+name = "pytest"
+version = "8.0.0"
+source = { registry = "https://pypi.org/simple" }
+dependencies = [
+    { name = "idna", marker = "sys_platform == 'win32'" },
+]
+`},
+			want: []string{"* httpx", "* pytest", "httpx@0.27.0 -> idna", "idna@3.6", "pytest@8.0.0 dev -> idna"},
+		},
+		{
+			name: "Cargo.lock",
+			files: map[string]string{"Cargo.lock": `version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+dependencies = [
+ "syn 2.0.48",
+]
+
+[[package]]
+name = "syn"
+version = "1.0.109"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "aaaa"
+
+[[package]]
+name = "syn"
+version = "2.0.48"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "bbbb"
+dependencies = [
+ "unicode-ident",
+]
+
+[[package]]
+name = "unicode-ident"
+version = "1.0.12"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`},
+			want: []string{"* syn", "syn@1.0.109", "syn@2.0.48 -> unicode-ident", "unicode-ident@1.0.12"},
+		},
+		{
+			name: "Gemfile.lock",
+			files: map[string]string{"Gemfile.lock": `GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.1.0)
+      rack (>= 2.2.4)
+    rack (3.0.8)
+
+PLATFORMS
+  ruby
+
+DEPENDENCIES
+  actionpack (~> 7.1)
+
+CHECKSUMS
+  rack (3.0.8) sha256=aaaa
+`},
+			want: []string{"* actionpack", "actionpack@7.1.0 -> rack", "rack@3.0.8"},
+		},
+		{
+			name: "composer.lock",
+			files: map[string]string{"composer.lock": `{
+  "packages": [{"name": "monolog/monolog", "version": "v3.5.0", "require": {"php": ">=8.1", "psr/log": "^3"}}, {"name": "psr/log", "version": "3.0.0"}],
+  "packages-dev": [{"name": "phpunit/phpunit", "version": "10.5.0"}]
+}`},
+			want: []string{"monolog/monolog@3.5.0 -> psr/log", "phpunit/phpunit@10.5.0 dev", "psr/log@3.0.0"},
+		},
+		{
+			name: "packages.lock.json",
+			files: map[string]string{"packages.lock.json": `{
+  "version": 1,
+  "dependencies": {
+    "net8.0": {
+      "Serilog.Sinks.Console": {"type": "Direct", "resolved": "5.0.1", "contentHash": "AAAA", "dependencies": {"Serilog": "3.1.1"}},
+      "Serilog": {"type": "Transitive", "resolved": "3.1.1"},
+      "MyLib": {"type": "Project"}
+    }
+  }
+}`},
+			want: []string{"* Serilog.Sinks.Console", "Serilog.Sinks.Console@5.0.1 -> Serilog", "Serilog@3.1.1"},
+		},
+		{
+			name: "gradle.lockfile",
+			files: map[string]string{"gradle.lockfile": `# Gradle lockfile
+com.google.guava:guava:32.1.3-jre=compileClasspath,runtimeClasspath,testCompileClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`},
+			want: []string{"com.google.guava:guava@32.1.3-jre", "junit:junit@4.13.2 dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeTestFile(t, dir, name, content)
+			}
+			lf, err := parseLockfile(filepath.Join(dir, tt.name))
+			if err != nil {
+				t.Fatalf("parseLockfile() error = %v", err)
+			}
+			if got, want := describeLockfile(lf), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("parsed:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestLockfileHashes(t *testing.T) {
+	if got := sriHashes("sha512-3q2+7w== sha1-3q2+7w=="); len(got) != 2 || got[0].Algorithm != "SHA-512" || got[0].Content != "deadbeef" || got[1].Algorithm != "SHA-1" {
+		t.Errorf("sriHashes() = %+v", got)
+	}
+	if got := prefixedHash("sha256:ABCD"); len(got) != 1 || got[0].Algorithm != "SHA-256" || got[0].Content != "abcd" {
+		t.Errorf("prefixedHash() = %+v", got)
+	}
+	if got := prefixedHash("md5:abcd"); got != nil {
+		t.Errorf("prefixedHash() with unknown algorithm = %+v", got)
+	}
+}
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		ecosystem, group, name, version string
+		want                            string
+	}{
+		{"npm", "", "@babel/core", "7.23.0", "pkg:npm/%40babel/core@7.23.0"},
+		{"npm", "", "lodash", "4.17.21", "pkg:npm/lodash@4.17.21"},
+		{"pypi", "", "Charset_Normalizer", "3.3.2", "pkg:pypi/charset-normalizer@3.3.2"},
+		{"golang", "", "github.com/spf13/cobra", "v1.8.0", "pkg:golang/github.com/spf13/cobra@v1.8.0"},
+		{"maven", "com.google.guava", "guava", "32.1.3-jre", "pkg:maven/com.google.guava/guava@32.1.3-jre"},
+		{"composer", "", "monolog/monolog", "3.5.0", "pkg:composer/monolog/monolog@3.5.0"},
+		{"pypi", "", "requests", "", "pkg:pypi/requests"},
+	}
+	for _, tt := range tests {
+		if got := packageURL(tt.ecosystem, tt.group, tt.name, tt.version); got != tt.want {
+			t.Errorf("packageURL(%s, %s) = %s, want %s", tt.ecosystem, tt.name, got, tt.want)
+		}
+	}
+	if got := purlNamespace("pkg:maven/com.google.guava/guava@32.1.3-jre"); got != "com.google.guava" {
+		t.Errorf("purlNamespace() = %q", got)
+	}
+}
+
+func TestRunGeneration_Native(t *testing.T) {
+	repo := t.TempDir()
+	writeTestFile(t, repo, "package-lock.json", `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"express": "^4.18.0"}, "devDependencies": {"jest": "^29.0.0"}},
+    "node_modules/express": {"version": "4.18.2", "integrity": "sha512-3q2+7w==", "dependencies": {"debug": "^4"}},
+    "node_modules/debug": {"version": "4.3.4"},
+    "node_modules/jest": {"version": "29.7.0", "dev": true}
+  }
+}`)
+	writeTestFile(t, repo, "service/Cargo.lock", `[[package]]
+name = "service"
+version = "0.1.0"
+dependencies = ["serde"]
+
+[[package]]
+name = "serde"
+version = "1.0.195"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+checksum = "abcd"
+`)
+
+	outputDir := t.TempDir()
+	summary, findings, sbomPath, err := runGeneration(context.Background(), &scanner.ScanOptions{
+		RepoPath:  repo,
+		OutputDir: outputDir,
+	}, GenerationConfig{Tool: "native", SpecVersion: "1.5"})
+	if err != nil {
+		t.Fatalf("runGeneration() error = %v", err)
+	}
+	if summary.Tool != "native" || summary.SpecVersion != "1.5" || summary.TotalComponents != 4 || !summary.HasDependencies {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.ByEcosystem["npm"] != 3 || summary.ByEcosystem["cargo"] != 1 {
+		t.Errorf("by ecosystem = %v", summary.ByEcosystem)
+	}
+
+	byPurl := make(map[string]Component)
+	for _, c := range findings.Components {
+		byPurl[c.Purl] = c
+	}
+	express := byPurl["pkg:npm/express@4.18.2"]
+	if express.Scope != "required" || len(express.Hashes) != 1 || express.Hashes[0].Content != "deadbeef" {
+		t.Errorf("express = %+v", express)
+	}
+	if jest := byPurl["pkg:npm/jest@29.7.0"]; jest.Scope != "optional" {
+		t.Errorf("jest scope = %q, want optional", jest.Scope)
+	}
+	if serde := byPurl["pkg:cargo/serde@1.0.195"]; len(serde.Hashes) != 1 || serde.Hashes[0].Algorithm != "SHA-256" {
+		t.Errorf("serde = %+v", serde)
+	}
+
+	graph := make(map[string][]string)
+	for _, d := range findings.Dependencies {
+		graph[d.Ref] = d.DependsOn
+	}
+	root := filepath.Base(repo)
+	if got := strings.Join(graph[root], ","); got != "pkg:cargo/serde@1.0.195,pkg:npm/express@4.18.2,pkg:npm/jest@29.7.0" {
+		t.Errorf("root dependencies = %s", got)
+	}
+	if got := strings.Join(graph["pkg:npm/express@4.18.2"], ","); got != "pkg:npm/debug@4.3.4" {
+		t.Errorf("express dependencies = %s", got)
+	}
+
+	// The SBOM matches the lockfiles it was built from
+	for _, comp := range verifyAgainstLockfiles(repo, findings.Components) {
+		if comp.Matched != comp.InLockfile || comp.Missing != 0 || comp.Extra != 0 {
+			t.Errorf("lockfile comparison = %+v", comp)
+		}
+	}
+	if _, err := LoadSBOM(sbomPath); err != nil {
+		t.Errorf("LoadSBOM() error = %v", err)
+	}
+}
+
+func TestRunGeneration_NoLockfiles(t *testing.T) {
+	_, _, _, err := runGeneration(context.Background(), &scanner.ScanOptions{
+		RepoPath:  t.TempDir(),
+		OutputDir: t.TempDir(),
+	}, GenerationConfig{Tool: "native"})
+	if err == nil || !strings.Contains(err.Error(), "no supported lockfiles") {
+		t.Errorf("runGeneration() error = %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package codepackages
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+)
+
+// generateNativeSBOM writes a CycloneDX SBOM built from the repository's
+// lockfiles, for environments where cdxgen and syft are not available.
+// Components carry purls, the hashes lockfiles record and a scope
+// ("optional" for dev-only packages); the dependency graph covers what the
+// lockfiles record, with the project's direct dependencies under the
+// metadata component.
+func generateNativeSBOM(repoPath, sbomFile, specVersion string) error {
+	lockfiles := findLockfilesRecursive(repoPath)
+	if len(lockfiles) == 0 {
+		return fmt.Errorf("no supported lockfiles found")
+	}
+
+	rootRef := filepath.Base(repoPath)
+	components := make(map[string]*cyclonedx.Component)
+	graph := make(map[string]map[string]bool)
+	addEdge := func(from, to string) {
+		if graph[from] == nil {
+			graph[from] = make(map[string]bool)
+		}
+		graph[from][to] = true
+	}
+
+	var parsed int
+	var lastErr error
+	for _, path := range lockfiles {
+		lf, err := parseLockfile(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		parsed++
+
+		refs := make([]string, len(lf.Packages))
+		for i, p := range lf.Packages {
+			purl := packageURL(lf.Ecosystem, p.Group, p.Name, p.Version)
+			refs[i] = purl
+
+			c, ok := components[purl]
+			if !ok {
+				c = &cyclonedx.Component{
+					Type:    cyclonedx.ComponentTypeLibrary,
+					BOMRef:  purl,
+					Group:   p.Group,
+					Name:    p.Name,
+					Version: p.Version,
+					Purl:    purl,
+					Scope:   "optional",
+				}
+				components[purl] = c
+			}
+			// A package is required if any lockfile needs it outside development
+			if !p.Dev {
+				c.Scope = "required"
+			}
+			for _, h := range p.Hashes {
+				if !hasHashAlgorithm(c.Hashes, h.Algorithm) {
+					c.Hashes = append(c.Hashes, cyclonedx.Hash{Algorithm: h.Algorithm, Content: h.Content})
+				}
+			}
+		}
+		for i, p := range lf.Packages {
+			for _, d := range p.DependsOn {
+				addEdge(refs[i], refs[d])
+			}
+		}
+		for _, d := range lf.Direct {
+			addEdge(rootRef, refs[d])
+		}
+	}
+	if parsed == 0 {
+		return lastErr
+	}
+
+	bom := cyclonedx.NewSBOM()
+	if specVersion != "" {
+		bom.SpecVersion = specVersion
+	}
+	bom.WithMetadataComponent(&cyclonedx.Component{
+		Type:   cyclonedx.ComponentTypeApplication,
+		BOMRef: rootRef,
+		Name:   rootRef,
+	})
+
+	purls := make([]string, 0, len(components))
+	for purl := range components {
+		purls = append(purls, purl)
+	}
+	sort.Strings(purls)
+	for _, purl := range purls {
+		bom.WithComponent(*components[purl])
+	}
+
+	refs := make([]string, 0, len(graph))
+	for ref := range graph {
+		if ref != rootRef {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	if graph[rootRef] != nil {
+		refs = append([]string{rootRef}, refs...)
+	}
+	for _, ref := range refs {
+		dependsOn := make([]string, 0, len(graph[ref]))
+		for to := range graph[ref] {
+			dependsOn = append(dependsOn, to)
+		}
+		sort.Strings(dependsOn)
+		bom.WithDependency(cyclonedx.Dependency{Ref: ref, DependsOn: dependsOn})
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sbomFile, data, 0644)
+}
+
+func hasHashAlgorithm(hashes []cyclonedx.Hash, alg string) bool {
+	for _, h := range hashes {
+		if h.Algorithm == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// packageURL builds the purl of a package, e.g. pkg:npm/%40babel/core@7.0.0
+func packageURL(ecosystem, group, name, version string) string {
+	var path string
+	switch ecosystem {
+	case "maven":
+		path = url.PathEscape(group) + "/" + url.PathEscape(name)
+	case "pypi":
+		path = url.PathEscape(normalizePackageName(ecosystem, name))
+	case "npm", "composer", "golang":
+		// Namespaced names keep their "/" separators
+		segments := strings.Split(name, "/")
+		for i, s := range segments {
+			segments[i] = strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+		}
+		path = strings.Join(segments, "/")
+	default:
+		path = url.PathEscape(name)
+	}
+	purl := "pkg:" + ecosystem + "/" + path
+	if version != "" {
+		purl += "@" + url.PathEscape(version)
+	}
+	return purl
+}
@@ -0,0 +1,207 @@
+package codepackages
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/exploitdb"
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+// Vulnerability prioritization combines how bad a vulnerability is (its
+// CVSS score), how likely it is to be exploited (KEV, EPSS), whether the
+// project can reach it, and whether it ships (dependency scope) into one
+// 0-100 score:
+//
+//	priority = 100 × severity × threat × reachability × scope
+//
+// so teams can work through a ranked list rather than every "high".
+
+// fixFirstCount is the number of top-ranked vulnerabilities listed in the
+// summary
+const fixFirstCount = 10
+
+// severityWeights stand in for the CVSS score of advisories without a vector
+var severityWeights = map[string]float64{
+	"critical": 0.95,
+	"high":     0.8,
+	"medium":   0.55,
+	"low":      0.25,
+}
+
+// Threat weights. Without KEV or EPSS data a vulnerability counts as an
+// average one; EPSS is spread with a cube root since most scores are tiny.
+const (
+	threatKEV     = 1.0
+	threatUnknown = 0.5
+	threatEPSSMin = 0.3
+)
+
+// Reachability weights. Unreachable vulnerabilities are demoted, not
+// dropped: import analysis can miss dynamic uses.
+var reachabilityWeights = map[string]float64{
+	"reachable":   1.0,
+	"unknown":     0.8,
+	"":            0.8, // Not analyzed
+	"unreachable": 0.4,
+}
+
+// devScopeWeight demotes vulnerabilities in packages that do not ship
+const devScopeWeight = 0.5
+
+// enrichExploits adds EPSS scores and KEV status to the findings from the
+// local exploit database (zero feeds epss / zero feeds kev), falling back
+// to the live KEV catalog when the database has no KEV data
+func (s *SupplyChainScanner) enrichExploits(ctx context.Context, opts *scanner.ScanOptions, result *vulnsFeatureResult) {
+	dbPath := s.config.Vulns.ExploitDB
+	if dbPath == "" {
+		dbPath = exploitdb.DefaultPath(zeroHome(opts))
+	}
+
+	var db *exploitdb.DB
+	hasKEV := false
+	if exploitdb.Exists(dbPath) {
+		if d, err := exploitdb.Open(dbPath); err == nil {
+			defer d.Close()
+			if stats, err := d.Stats(ctx); err == nil {
+				db = d
+				hasKEV = stats.KEVEntries > 0
+				if stats.EPSSScores > 0 {
+					result.Summary.Sources = append(result.Summary.Sources, "epss")
+				}
+			}
+		}
+	}
+
+	var liveKEV map[string]bool
+	if s.config.Vulns.IncludeKEV {
+		if hasKEV {
+			result.Summary.Sources = append(result.Summary.Sources, "kev")
+		} else {
+			liveKEV = fetchKEV(ctx)
+		}
+	}
+
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		for _, id := range append([]string{f.ID}, f.Aliases...) {
+			if liveKEV[id] {
+				f.InKEV = true
+			}
+			if db == nil || !strings.HasPrefix(id, "CVE-") {
+				continue
+			}
+			intel, err := db.Lookup(ctx, id)
+			if err != nil || intel == nil {
+				continue
+			}
+			if intel.HasEPSS && intel.EPSS >= f.EPSS {
+				f.EPSS, f.EPSSPercentile = intel.EPSS, intel.EPSSPercentile
+			}
+			if intel.KEV != nil && s.config.Vulns.IncludeKEV {
+				f.InKEV = true
+			}
+		}
+		if f.InKEV {
+			result.Summary.KEVCount++
+		}
+		if f.EPSS > 0 {
+			result.Summary.EPSSCount++
+		}
+	}
+}
+
+// prioritizeVulns scores the findings, records their reachability, and
+// sorts them by rank, first to fix first
+func prioritizeVulns(vulns []VulnFinding, reachability []ReachabilityFinding) {
+	status := make(map[string]string, len(reachability))
+	for _, r := range reachability {
+		status[r.ID+"|"+r.Package+"|"+r.Version] = r.ReachabilityStatus
+	}
+
+	for i := range vulns {
+		f := &vulns[i]
+		if st, ok := status[f.ID+"|"+f.Package+"|"+f.Version]; ok {
+			f.Reachability = st
+		}
+		f.PriorityScore, f.PriorityFactors = priorityScore(f)
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		a, b := &vulns[i], &vulns[j]
+		switch {
+		case a.PriorityScore != b.PriorityScore:
+			return a.PriorityScore > b.PriorityScore
+		case a.InKEV != b.InKEV:
+			return a.InKEV
+		case a.EPSS != b.EPSS:
+			return a.EPSS > b.EPSS
+		case a.CVSSScore != b.CVSSScore:
+			return a.CVSSScore > b.CVSSScore
+		case a.ID != b.ID:
+			return a.ID < b.ID
+		}
+		return a.Package < b.Package
+	})
+	for i := range vulns {
+		vulns[i].PriorityRank = i + 1
+	}
+}
+
+// priorityScore returns a finding's 0-100 priority and the factors behind it
+func priorityScore(f *VulnFinding) (float64, []string) {
+	var factors []string
+
+	severity := severityWeights[f.Severity]
+	if severity == 0 {
+		severity = severityWeights["medium"]
+	}
+	if f.CVSSScore > 0 {
+		severity = f.CVSSScore / 10
+		factors = append(factors, fmt.Sprintf("CVSS %.1f", f.CVSSScore))
+	} else {
+		factors = append(factors, f.Severity+" severity")
+	}
+
+	threat := threatUnknown
+	switch {
+	case f.InKEV:
+		threat = threatKEV
+		factors = append(factors, "known exploited (CISA KEV)")
+	case f.EPSS > 0:
+		threat = threatEPSSMin + (1-threatEPSSMin)*math.Cbrt(f.EPSS)
+		factors = append(factors, fmt.Sprintf("EPSS %.1f%% (percentile %.0f)", f.EPSS*100, f.EPSSPercentile*100))
+	}
+
+	reach, ok := reachabilityWeights[f.Reachability]
+	if !ok {
+		reach = reachabilityWeights["unknown"]
+	}
+	switch f.Reachability {
+	case "reachable":
+		factors = append(factors, "reachable")
+	case "unreachable":
+		factors = append(factors, "not reachable")
+	}
+
+	scope := 1.0
+	if f.Scope == "optional" || f.Scope == "excluded" {
+		scope = devScopeWeight
+		factors = append(factors, "dev dependency")
+	}
+
+	score := 100 * severity * threat * reach * scope
+	return math.Round(score*10) / 10, factors
+}
+
+// fixFirst lists the top-ranked findings as "ID (package@version)"
+func fixFirst(vulns []VulnFinding) []string {
+	var ids []string
+	for i := 0; i < len(vulns) && i < fixFirstCount; i++ {
+		ids = append(ids, fmt.Sprintf("%s (%s@%s)", vulns[i].ID, vulns[i].Package, vulns[i].Version))
+	}
+	return ids
+}
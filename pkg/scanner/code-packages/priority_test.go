@@ -0,0 +1,121 @@
+package codepackages
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/exploitdb"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+func TestPrioritizeVulns(t *testing.T) {
+	vulns := []VulnFinding{
+		// Critical, but only in a dev dependency and not reachable
+		{ID: "GHSA-dev", Package: "jest", Version: "1.0.0", Severity: "critical", CVSSScore: 9.8, Scope: "optional"},
+		// High and known to be exploited
+		{ID: "GHSA-kev", Package: "log4j-core", Version: "2.14.1", Severity: "high", CVSSScore: 8.1, InKEV: true},
+		// Critical with a low EPSS score
+		{ID: "GHSA-epss", Package: "lodash", Version: "4.17.20", Severity: "critical", CVSSScore: 9.1, EPSS: 0.001, EPSSPercentile: 0.4},
+		// No vector, no exploit data
+		{ID: "GHSA-label", Package: "minimist", Version: "1.2.0", Severity: "medium"},
+	}
+	reachability := []ReachabilityFinding{
+		{ID: "GHSA-dev", Package: "jest", Version: "1.0.0", ReachabilityStatus: "unreachable"},
+		{ID: "GHSA-kev", Package: "log4j-core", Version: "2.14.1", ReachabilityStatus: "reachable"},
+	}
+
+	prioritizeVulns(vulns, reachability)
+
+	want := []string{"GHSA-kev", "GHSA-epss", "GHSA-label", "GHSA-dev"}
+	for i, id := range want {
+		if vulns[i].ID != id || vulns[i].PriorityRank != i+1 {
+			t.Errorf("rank %d = %s (rank %d, score %.1f), want %s", i+1, vulns[i].ID, vulns[i].PriorityRank, vulns[i].PriorityScore, id)
+		}
+	}
+
+	kev := vulns[0]
+	if kev.PriorityScore != 81 || kev.Reachability != "reachable" {
+		t.Errorf("KEV finding = %+v, want score 81", kev)
+	}
+	if len(kev.PriorityFactors) != 3 || kev.PriorityFactors[1] != "known exploited (CISA KEV)" {
+		t.Errorf("KEV factors = %v", kev.PriorityFactors)
+	}
+	// 100 × 0.98 × 0.5 × 0.4 × 0.5
+	if dev := vulns[3]; dev.PriorityScore != 9.8 {
+		t.Errorf("dev finding score = %.1f, want 9.8", dev.PriorityScore)
+	}
+
+	if got := fixFirst(vulns); len(got) != 4 || got[0] != "GHSA-kev (log4j-core@2.14.1)" {
+		t.Errorf("fixFirst() = %v", got)
+	}
+}
+
+func TestEnrichExploits(t *testing.T) {
+	ctx := context.Background()
+	zeroHome := t.TempDir()
+
+	db, err := exploitdb.Open(exploitdb.DefaultPath(zeroHome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	epss := writeTestFile(t, t.TempDir(), "epss.csv", "cve,epss,percentile\nCVE-2021-23337,0.0123,0.85\nCVE-2021-44228,0.944,0.999\n")
+	if _, err := db.ImportEPSS(ctx, epss); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.ImportKEV(ctx, []byte(`{"catalogVersion": "2026.10.14", "vulnerabilities": [
+		{"cveID": "CVE-2021-44228", "vulnerabilityName": "Log4Shell", "dateAdded": "2021-12-10", "dueDate": "2021-12-24"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// KEV comes from the database, so nothing is fetched
+	liveapi.SetOffline(true)
+	defer liveapi.SetOffline(false)
+
+	result := &vulnsFeatureResult{
+		Summary: &VulnsSummary{},
+		Findings: []VulnFinding{
+			{ID: "GHSA-35jh-r3h4-6jhm", Aliases: []string{"CVE-2021-23337"}, Package: "lodash"},
+			{ID: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}, Package: "log4j-core"},
+			{ID: "GHSA-none", Package: "left-pad"},
+		},
+	}
+	s := &SupplyChainScanner{config: FeatureConfig{Vulns: VulnsConfig{Enabled: true, IncludeKEV: true}}}
+	s.enrichExploits(ctx, &scanner.ScanOptions{ZeroHome: zeroHome}, result)
+
+	lodash, log4j, leftPad := result.Findings[0], result.Findings[1], result.Findings[2]
+	if lodash.EPSS != 0.0123 || lodash.EPSSPercentile != 0.85 || lodash.InKEV {
+		t.Errorf("lodash = %+v", lodash)
+	}
+	if log4j.EPSS != 0.944 || !log4j.InKEV {
+		t.Errorf("log4j = %+v", log4j)
+	}
+	if leftPad.EPSS != 0 || leftPad.InKEV {
+		t.Errorf("left-pad = %+v", leftPad)
+	}
+	if result.Summary.KEVCount != 1 || result.Summary.EPSSCount != 2 {
+		t.Errorf("summary = %+v", result.Summary)
+	}
+}
+
+func TestOSVSeverity_CVSS(t *testing.T) {
+	vuln := &liveapi.Vulnerability{
+		Severity: []liveapi.OSVSeverity{
+			{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"},
+		},
+		DatabaseSpecific: map[string]interface{}{"severity": "MODERATE"},
+	}
+	if got := osvSeverity(vuln); got != "high" {
+		t.Errorf("osvSeverity() = %q, want high (CVSS 7.5)", got)
+	}
+	if f := osvFinding(vuln, "npm", "pkg", "1.0.0"); f.CVSSScore != 7.5 || f.CVSSVector != vuln.Severity[0].Score {
+		t.Errorf("osvFinding() = %+v", f)
+	}
+
+	vuln.Severity = nil
+	if got := osvSeverity(vuln); got != "medium" {
+		t.Errorf("osvSeverity() without a vector = %q, want the database label", got)
+	}
+}
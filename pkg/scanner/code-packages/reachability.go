@@ -0,0 +1,804 @@
+package codepackages
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Native reachability for the ecosystems osv-scanner's call analysis does
+// not cover. Source files are scanned for imports of vulnerable packages
+// and, when an advisory names the vulnerable functions or classes, for uses
+// of those symbols. Test code is ignored: a vulnerability only reachable
+// from tests does not ship.
+
+const (
+	// maxReachabilityFileSize skips generated and bundled sources
+	maxReachabilityFileSize = 1 << 20
+	// maxModuleFiles bounds the files read from each installed npm package
+	maxModuleFiles = 200
+	// maxReachabilityEvidence bounds the evidence and call paths per finding
+	maxReachabilityEvidence = 10
+)
+
+// moduleSkipDirs are skipped inside installed packages: tests, fixtures and
+// examples do not ship, and nested node_modules are packages of their own
+var moduleSkipDirs = map[string]bool{
+	"test":         true,
+	"tests":        true,
+	"__tests__":    true,
+	"__mocks__":    true,
+	"__fixtures__": true,
+	"fixtures":     true,
+	"examples":     true,
+	"node_modules": true,
+}
+
+// sourceSkipDirs are never first-party source
+var sourceSkipDirs = map[string]bool{
+	"test":             true,
+	"tests":            true,
+	"__tests__":        true,
+	"__mocks__":        true,
+	"__fixtures__":     true,
+	"fixtures":         true,
+	"examples":         true,
+	"node_modules":     true,
+	"bower_components": true,
+	"vendor":           true,
+	"dist":             true,
+	"build":            true,
+	"out":              true,
+	"target":           true,
+	"coverage":         true,
+}
+
+// reachabilityWalk calls fn for up to limit (0 for no limit) files under dir
+// with one of the given extensions, skipping hidden directories, skipDirs
+// and test files. complete is false if a matching file was left out, for
+// being over the size limit or past the file limit, or the walk was
+// cancelled: what was not read cannot show that a package is unused.
+func reachabilityWalk(ctx context.Context, dir string, exts map[string]bool, skipDirs map[string]bool, limit int, fn func(path string) error) (complete bool, err error) {
+	complete = true
+	count := 0
+	err = filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if p != dir && (skipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name := entry.Name()
+		if !exts[filepath.Ext(name)] || isTestSource(name) {
+			return nil
+		}
+		if info, err := entry.Info(); err != nil || info.Size() > maxReachabilityFileSize {
+			complete = false
+			return nil
+		}
+		if limit > 0 && count >= limit {
+			complete = false
+			return filepath.SkipAll
+		}
+		count++
+		return fn(p)
+	})
+	if err != nil {
+		complete = false
+	}
+	return complete, err
+}
+
+// isTestSource returns true for test and type declaration files
+func isTestSource(name string) bool {
+	lower := strings.ToLower(name)
+	for _, marker := range []string{".test.", ".spec.", ".d.ts", ".d.mts", ".d.cts", ".min.js"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.HasSuffix(base, "Test") || strings.HasSuffix(base, "Tests") || strings.HasSuffix(base, "IT")
+}
+
+// lineAt returns the 1-based line of a byte offset
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+// symbolName returns the final identifier of an advisory symbol, e.g.
+// "template" for "lodash.template", "_.template" or "lodash/template"
+func symbolName(symbol string) string {
+	symbol = strings.TrimSuffix(strings.TrimSpace(symbol), "()")
+	if i := strings.LastIndexAny(symbol, "./#:"); i >= 0 {
+		symbol = symbol[i+1:]
+	}
+	return symbol
+}
+
+// setStatus sets a finding's status; like osv-scanner's results, only
+// vulnerabilities shown to be unreachable are not treated as reachable
+func (f *ReachabilityFinding) setStatus(status string) {
+	f.ReachabilityStatus = status
+	f.Reachable = status != "unreachable"
+}
+
+// ==================== JavaScript / TypeScript ====================
+
+var jsExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".mjs": true, ".cjs": true,
+	".ts": true, ".tsx": true, ".mts": true, ".cts": true,
+	".vue": true, ".svelte": true,
+}
+
+// jsModuleExtensions are the files read from installed packages, which
+// ship compiled JavaScript
+var jsModuleExtensions = map[string]bool{".js": true, ".mjs": true, ".cjs": true}
+
+var (
+	jsImportFrom    = regexp.MustCompile(`\bimport\s+(type\s+)?([\w$*{}\s,]+?)\s*from\s*['"]([^'"\n]+)['"]`)
+	jsExportFrom    = regexp.MustCompile(`\bexport\s+(type\s+)?(\*(?:\s+as\s+[\w$]+)?|\{[^}]*\})\s*from\s*['"]([^'"\n]+)['"]`)
+	jsImportBare    = regexp.MustCompile(`\bimport\s*\(?\s*['"]([^'"\n]+)['"]`)
+	jsRequire       = regexp.MustCompile(`(?:\b(?:const|let|var)\s+([\w$]+|\{[^}]*\})\s*=\s*)?\brequire\(\s*['"]([^'"\n]+)['"]\s*\)(?:\s*\.\s*([\w$]+))?`)
+	jsIdentifier    = regexp.MustCompile(`^[\w$]+$`)
+	jsBindingSplits = regexp.MustCompile(`\s+as\s+|\s*:\s*`)
+)
+
+// jsImport is an import or require of an npm package
+type jsImport struct {
+	Package string   // Package name, e.g. "lodash" or "@babel/core"
+	Subpath string   // Path inside the package, e.g. "template" for "lodash/template"
+	File    string   // Relative to the repository root
+	Line    int      // Line of the import
+	Names   []string // Named imports ({ template } or require(...).template)
+	Locals  []string // Default and namespace bindings
+	Uses    []jsUse  // Member accesses and calls through Locals
+
+	importer *jsModule
+	resolved *jsModule
+}
+
+// jsUse is a use of an imported binding: local.member (or local(...) for
+// "default")
+type jsUse struct {
+	Name string
+	Line int
+}
+
+// jsModule is the project or a package installed in node_modules
+type jsModule struct {
+	dir     string // Relative to the repository root; "" for the project
+	name    string
+	version string
+	parent  *jsModule
+	via     *jsImport // The import in parent that first reached this module
+}
+
+// path returns the import chain from first-party code to the module
+func (m *jsModule) path() []string {
+	if m.parent == nil {
+		return nil
+	}
+	hops := m.parent.path()
+	if len(hops) == 0 {
+		hops = []string{fmt.Sprintf("%s:%d", m.via.File, m.via.Line)}
+	}
+	return append(hops, m.name)
+}
+
+// jsPackageName splits an import specifier into the package name and
+// subpath, returning "" for relative paths, URLs and Node builtins
+func jsPackageName(spec string) (string, string) {
+	if spec == "" || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") ||
+		strings.HasPrefix(spec, "node:") || strings.Contains(spec, "://") {
+		return "", ""
+	}
+	parts := strings.SplitN(spec, "/", 3)
+	if strings.HasPrefix(spec, "@") {
+		if len(parts) < 2 {
+			return "", ""
+		}
+		name := parts[0] + "/" + parts[1]
+		if len(parts) == 3 {
+			return name, parts[2]
+		}
+		return name, ""
+	}
+	return parts[0], strings.Join(parts[1:], "/")
+}
+
+// parseJSImports returns the package imports in a JavaScript/TypeScript file
+func parseJSImports(content, file string) []*jsImport {
+	var imports []*jsImport
+	seen := make(map[int]bool) // Offsets already matched by a more specific pattern
+	add := func(offset int, spec string) *jsImport {
+		name, subpath := jsPackageName(spec)
+		if name == "" || seen[offset] {
+			return nil
+		}
+		seen[offset] = true
+		imp := &jsImport{Package: name, Subpath: subpath, File: file, Line: lineAt(content, offset)}
+		imports = append(imports, imp)
+		return imp
+	}
+
+	for _, m := range jsImportFrom.FindAllStringSubmatchIndex(content, -1) {
+		if m[2] >= 0 { // import type ... is erased at compile time
+			seen[m[0]] = true
+			continue
+		}
+		imp := add(m[0], content[m[6]:m[7]])
+		if imp == nil {
+			continue
+		}
+		clause := content[m[4]:m[5]]
+		if open := strings.Index(clause, "{"); open >= 0 {
+			if end := strings.Index(clause[open:], "}"); end >= 0 {
+				imp.Names = jsBindingNames(clause[open+1 : open+end])
+				clause = clause[:open] + clause[open+end+1:]
+			}
+		}
+		for _, part := range strings.Split(clause, ",") {
+			part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "*"))
+			part = strings.TrimSpace(strings.TrimPrefix(part, "as "))
+			if jsIdentifier.MatchString(part) {
+				imp.Locals = append(imp.Locals, part)
+			}
+		}
+	}
+	for _, m := range jsExportFrom.FindAllStringSubmatchIndex(content, -1) {
+		if m[2] >= 0 {
+			seen[m[0]] = true
+			continue
+		}
+		if imp := add(m[0], content[m[6]:m[7]]); imp != nil {
+			if clause := content[m[4]:m[5]]; strings.HasPrefix(clause, "{") {
+				imp.Names = jsBindingNames(strings.Trim(clause, "{}"))
+			} else {
+				imp.Names = []string{"*"} // Re-exports everything
+			}
+		}
+	}
+	for _, m := range jsImportBare.FindAllStringSubmatchIndex(content, -1) {
+		if imp := add(m[0], content[m[2]:m[3]]); imp != nil {
+			imp.Names = []string{"*"} // Side effects or dynamic use
+		}
+	}
+	for _, m := range jsRequire.FindAllStringSubmatchIndex(content, -1) {
+		imp := add(m[0], content[m[4]:m[5]])
+		if imp == nil {
+			continue
+		}
+		switch {
+		case m[6] >= 0:
+			imp.Names = []string{content[m[6]:m[7]]}
+		case m[2] >= 0:
+			binding := content[m[2]:m[3]]
+			if strings.HasPrefix(binding, "{") {
+				imp.Names = jsBindingNames(strings.Trim(binding, "{}"))
+			} else {
+				imp.Locals = []string{binding}
+			}
+		default:
+			imp.Names = []string{"*"} // Passed around or used inline
+		}
+	}
+
+	for _, imp := range imports {
+		for _, local := range imp.Locals {
+			imp.Uses = append(imp.Uses, jsLocalUses(content, local)...)
+		}
+	}
+	return imports
+}
+
+// jsBindingNames returns the imported names of "{ a, b as c }" or the
+// destructuring "{ a, b: c }"
+func jsBindingNames(list string) []string {
+	var names []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(item), "type "))
+		name := jsBindingSplits.Split(item, 2)[0]
+		if jsIdentifier.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// jsLocalUses finds member accesses (local.member) and calls (local(...))
+// through an imported binding
+func jsLocalUses(content, local string) []jsUse {
+	var uses []jsUse
+	pattern := regexp.MustCompile(`(?:^|[^\w$.])` + regexp.QuoteMeta(local) + `\s*(?:\?\.|\.)\s*([\w$]+)|(?:^|[^\w$.])` + regexp.QuoteMeta(local) + `\s*\(`)
+	for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+		name := "default"
+		if m[2] >= 0 {
+			name = content[m[2]:m[3]]
+		}
+		uses = append(uses, jsUse{Name: name, Line: lineAt(content, m[1]-1)})
+	}
+	return uses
+}
+
+// matchSymbol returns the line where an import uses a vulnerable symbol
+func (imp *jsImport) matchSymbol(symbol string) (int, bool) {
+	name := symbolName(symbol)
+	if name == "" {
+		return 0, false
+	}
+	// The advisory names the package itself (a module exporting one function)
+	if name == symbolName(imp.Package) && imp.Subpath == "" {
+		return imp.Line, true
+	}
+	if imp.Subpath != "" {
+		sub := path.Base(imp.Subpath)
+		if strings.TrimSuffix(sub, path.Ext(sub)) == name {
+			return imp.Line, true
+		}
+	}
+	for _, n := range imp.Names {
+		if n == name || n == "*" {
+			return imp.Line, true
+		}
+	}
+	for _, use := range imp.Uses {
+		if use.Name == name {
+			return use.Line, true
+		}
+	}
+	return 0, false
+}
+
+// analyzeJSReachability checks npm vulnerabilities against the project's
+// imports. With node_modules installed, the packages the project imports
+// are followed transitively, so vulnerable dependencies of dependencies are
+// reached through the chain of imports leading to them.
+func analyzeJSReachability(ctx context.Context, root string, vulns []VulnFinding) []ReachabilityFinding {
+	project := &jsModule{}
+	modules := map[string]*jsModule{"": project}
+	var all []*jsImport
+	_, hasNodeModules := os.Stat(filepath.Join(root, "node_modules"))
+	installed := hasNodeModules == nil
+	complete := true
+
+	queue := []*jsModule{project}
+	for len(queue) > 0 && ctx.Err() == nil {
+		m := queue[0]
+		queue = queue[1:]
+
+		dir, exts, skip, limit := root, jsExtensions, sourceSkipDirs, 0
+		if m != project {
+			dir, exts, skip, limit = filepath.Join(root, filepath.FromSlash(m.dir)), jsModuleExtensions, moduleSkipDirs, maxModuleFiles
+		}
+		var imports []*jsImport
+		walked, _ := reachabilityWalk(ctx, dir, exts, skip, limit, func(p string) error {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, p)
+			for _, imp := range parseJSImports(string(data), filepath.ToSlash(rel)) {
+				if imp.Package == m.name {
+					continue // Self-reference
+				}
+				imp.importer = m
+				imports = append(imports, imp)
+			}
+			return nil
+		})
+		complete = complete && walked
+		all = append(all, imports...)
+
+		if !installed {
+			break // Only first-party imports can be seen
+		}
+		for _, imp := range imports {
+			depDir := resolveJSPackage(root, m.dir, imp.Package)
+			if depDir == "" {
+				continue
+			}
+			dep, ok := modules[depDir]
+			if !ok {
+				dep = &jsModule{dir: depDir, name: imp.Package, version: jsPackageVersion(filepath.Join(root, depDir)), parent: m, via: imp}
+				modules[depDir] = dep
+				queue = append(queue, dep)
+			}
+			imp.resolved = dep
+		}
+	}
+	if ctx.Err() != nil {
+		complete = false
+	}
+
+	prod, dev := readPackageJSONDeps(root)
+
+	var findings []ReachabilityFinding
+	for _, v := range vulns {
+		finding := ReachabilityFinding{
+			ID:                v.ID,
+			Package:           v.Package,
+			Version:           v.Version,
+			Ecosystem:         v.Ecosystem,
+			Summary:           v.Title,
+			Method:            "import-graph",
+			VulnerableSymbols: v.Symbols,
+		}
+
+		// Imports of the vulnerable package from the project or from a
+		// package the project reaches, resolving to the vulnerable version
+		var sites []*jsImport
+		for _, imp := range all {
+			if imp.Package != v.Package {
+				continue
+			}
+			if imp.resolved != nil && imp.resolved.version != "" && imp.resolved.version != v.Version {
+				continue
+			}
+			sites = append(sites, imp)
+		}
+
+		switch {
+		case len(sites) == 0:
+			_, isProd := prod[v.Package]
+			_, isDev := dev[v.Package]
+			if complete && (installed || isProd || isDev) {
+				finding.setStatus("unreachable")
+			} else {
+				// A transitive dependency whose importers are not installed,
+				// or sources were left out of the walk
+				finding.setStatus("unknown")
+			}
+		case len(v.Symbols) == 0:
+			finding.setStatus("reachable")
+			for _, imp := range sites {
+				finding.addEvidence(ReachabilityEvidence{File: imp.File, Line: imp.Line}, imp.callPath(imp.Line))
+			}
+		default:
+			for _, imp := range sites {
+				for _, symbol := range v.Symbols {
+					if line, ok := imp.matchSymbol(symbol); ok {
+						finding.addEvidence(ReachabilityEvidence{File: imp.File, Line: line, Symbol: symbol}, imp.callPath(line))
+					}
+				}
+			}
+			if len(finding.Evidence) > 0 {
+				finding.setStatus("reachable")
+			} else {
+				// Imported, but the vulnerable symbols are not named. They may
+				// still be reached through the package's public API.
+				finding.setStatus("unknown")
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// callPath returns the import chain from first-party code to an import
+// site, ending at the imported package
+func (imp *jsImport) callPath(line int) string {
+	hops := imp.importer.path()
+	if len(hops) == 0 {
+		hops = []string{fmt.Sprintf("%s:%d", imp.File, line)}
+	}
+	return strings.Join(append(hops, imp.Package), " -> ")
+}
+
+// addEvidence records a location and call path, up to the evidence limit
+func (f *ReachabilityFinding) addEvidence(evidence ReachabilityEvidence, callPath string) {
+	if len(f.Evidence) < maxReachabilityEvidence {
+		f.Evidence = append(f.Evidence, evidence)
+	}
+	if callPath == "" || len(f.CallPaths) >= maxReachabilityEvidence {
+		return
+	}
+	for _, p := range f.CallPaths {
+		if p == callPath {
+			return
+		}
+	}
+	f.CallPaths = append(f.CallPaths, callPath)
+}
+
+// resolveJSPackage finds the directory of an installed package the way
+// Node resolves it: in the importer's node_modules, then its ancestors'
+func resolveJSPackage(root, fromDir, name string) string {
+	dir := fromDir
+	for {
+		candidate := path.Join(dir, "node_modules", name)
+		if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(candidate))); err == nil && info.IsDir() {
+			return candidate
+		}
+		if dir == "" || dir == "." {
+			return ""
+		}
+		// Step out of the current package: a/node_modules/b -> a
+		idx := strings.LastIndex(dir, "node_modules/")
+		if idx < 0 {
+			dir = ""
+			continue
+		}
+		dir = strings.TrimSuffix(dir[:idx], "/")
+	}
+}
+
+// jsPackageVersion reads the version from an installed package's package.json
+func jsPackageVersion(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var manifest struct {
+		Version string `json:"version"`
+	}
+	_ = json.Unmarshal(data, &manifest)
+	return manifest.Version
+}
+
+// ==================== Java ====================
+
+var javaExtensions = map[string]bool{".java": true, ".kt": true}
+
+var (
+	javaImportLine    = regexp.MustCompile(`^\s*import\s+(static\s+)?([\w.]+?)(\.\*)?(?:\s+as\s+\w+)?\s*;?\s*$`)
+	javaQualifiedName = regexp.MustCompile(`\b((?:[a-z_]\w*\.)+[A-Z]\w*)`)
+	pomDependency     = regexp.MustCompile(`(?s)<dependency>(.*?)</dependency>`)
+	pomGroupID        = regexp.MustCompile(`<groupId>\s*([^<\s]+)\s*</groupId>`)
+	pomArtifactID     = regexp.MustCompile(`<artifactId>\s*([^<\s]+)\s*</artifactId>`)
+	gradleCoordinate  = regexp.MustCompile(`['"]([\w.\-]+):([\w.\-]+)(?::[^'"\s]*)?['"]`)
+)
+
+// javaReference is an import or fully qualified class name in a source file
+type javaReference struct {
+	Path     string // e.g. "org.apache.commons.text.StringSubstitutor"
+	Wildcard bool   // import pkg.*;
+	File     string
+	Line     int
+}
+
+// javaSource is a parsed Java or Kotlin file
+type javaSource struct {
+	content    string
+	references []javaReference
+}
+
+// parseJavaSource returns the imports and qualified class names in a file
+func parseJavaSource(content, file string) *javaSource {
+	src := &javaSource{content: content}
+	for i, line := range strings.Split(content, "\n") {
+		if m := javaImportLine.FindStringSubmatch(line); m != nil {
+			src.references = append(src.references, javaReference{Path: m[2], Wildcard: m[3] != "", File: file, Line: i + 1})
+			continue
+		}
+		for _, name := range javaQualifiedName.FindAllString(line, -1) {
+			src.references = append(src.references, javaReference{Path: name, File: file, Line: i + 1})
+		}
+	}
+	return src
+}
+
+// javaPackages returns the Java packages of a Maven artifact. When the jar
+// is in the local Maven or Gradle cache its packages are read from it and
+// exact is true; otherwise they are guessed from the coordinates
+// (com.fasterxml.jackson.core:jackson-databind -> com.fasterxml.jackson.core.databind,
+// com.fasterxml.jackson.databind). The bare group is not a guess: artifacts
+// share it (log4j-api and log4j-core are both org.apache.logging.log4j).
+func javaPackages(group, artifact, version string) (packages map[string]bool, exact bool) {
+	if pkgs := jarPackages(group, artifact, version); len(pkgs) > 0 {
+		return pkgs, true
+	}
+	packages = make(map[string]bool)
+	segments := strings.Split(artifact, "-")
+	suffix := segments[len(segments)-1]
+	packages[group+"."+suffix] = true
+	if i := strings.LastIndex(group, "."); i > 0 {
+		packages[group[:i]+"."+suffix] = true
+	}
+	return packages, false
+}
+
+// jarPackages lists the packages of a jar in the local Maven or Gradle cache
+func jarPackages(group, artifact, version string) map[string]bool {
+	home, err := os.UserHomeDir()
+	if err != nil || version == "" {
+		return nil
+	}
+	jarName := artifact + "-" + version + ".jar"
+	candidates := []string{filepath.Join(home, ".m2", "repository", filepath.FromSlash(strings.ReplaceAll(group, ".", "/")), artifact, version, jarName)}
+	gradle, _ := filepath.Glob(filepath.Join(home, ".gradle", "caches", "modules-2", "files-2.1", group, artifact, version, "*", jarName))
+	candidates = append(candidates, gradle...)
+
+	for _, jar := range candidates {
+		zr, err := zip.OpenReader(jar)
+		if err != nil {
+			continue
+		}
+		packages := make(map[string]bool)
+		for _, f := range zr.File {
+			name := f.Name
+			if strings.HasPrefix(name, "META-INF/") || !strings.HasSuffix(name, ".class") {
+				continue
+			}
+			if dir := path.Dir(name); dir != "." {
+				packages[strings.ReplaceAll(dir, "/", ".")] = true
+			}
+		}
+		zr.Close()
+		if len(packages) > 0 {
+			return packages
+		}
+	}
+	return nil
+}
+
+// inPackages returns true if a reference names something in or under one
+// of the packages
+func (r javaReference) inPackages(packages map[string]bool) bool {
+	name := r.Path
+	for {
+		if packages[name] {
+			return true
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			return false
+		}
+		name = name[:i]
+	}
+}
+
+// matchSymbol returns true if a reference uses a vulnerable class, given
+// as a qualified or simple class name, optionally followed by a method
+// ("org.yaml.snakeyaml.Yaml.load", "Yaml#load", "JndiLookup")
+func (r javaReference) matchSymbol(symbol string, src *javaSource) bool {
+	symbol = strings.NewReplacer("#", ".", "::", ".", "()", "").Replace(strings.TrimSpace(symbol))
+	segments := strings.Split(symbol, ".")
+	class := -1
+	for i, s := range segments {
+		if s != "" && s[0] >= 'A' && s[0] <= 'Z' {
+			class = i
+			break
+		}
+	}
+	if class < 0 {
+		// A package: anything in it is a use
+		return r.Path == symbol || strings.HasPrefix(r.Path, symbol+".")
+	}
+	pkg := strings.Join(segments[:class], ".")
+	simple := segments[class]
+	usesSimple := regexp.MustCompile(`\b` + regexp.QuoteMeta(simple) + `\b`).MatchString
+
+	if pkg == "" {
+		if r.Wildcard {
+			return usesSimple(src.content)
+		}
+		last := r.Path[strings.LastIndex(r.Path, ".")+1:]
+		return last == simple || strings.Contains(r.Path, "."+simple+".")
+	}
+	fqcn := pkg + "." + simple
+	if r.Path == fqcn || strings.HasPrefix(r.Path, fqcn+".") {
+		return true
+	}
+	return r.Wildcard && r.Path == pkg && usesSimple(src.content)
+}
+
+// readJavaDirectDependencies returns the group:artifact coordinates declared
+// in the repository's pom.xml and Gradle build files
+func readJavaDirectDependencies(ctx context.Context, root string) map[string]bool {
+	direct := make(map[string]bool)
+	buildFiles := map[string]bool{".xml": true, ".gradle": true, ".kts": true}
+	_, _ = reachabilityWalk(ctx, root, buildFiles, sourceSkipDirs, 0, func(p string) error {
+		name := filepath.Base(p)
+		if name != "pom.xml" && !strings.HasPrefix(name, "build.gradle") {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		content := string(data)
+		if name == "pom.xml" {
+			for _, m := range pomDependency.FindAllStringSubmatch(content, -1) {
+				g, a := pomGroupID.FindStringSubmatch(m[1]), pomArtifactID.FindStringSubmatch(m[1])
+				if g != nil && a != nil {
+					direct[g[1]+":"+a[1]] = true
+				}
+			}
+			return nil
+		}
+		for _, m := range gradleCoordinate.FindAllStringSubmatch(content, -1) {
+			direct[m[1]+":"+m[2]] = true
+		}
+		return nil
+	})
+	return direct
+}
+
+// analyzeJavaReachability checks Maven vulnerabilities against the imports
+// and qualified class names in the project's Java and Kotlin sources
+func analyzeJavaReachability(ctx context.Context, root string, vulns []VulnFinding) []ReachabilityFinding {
+	var sources []*javaSource
+	complete, _ := reachabilityWalk(ctx, root, javaExtensions, sourceSkipDirs, 0, func(p string) error {
+		rel, _ := filepath.Rel(root, p)
+		if strings.Contains(filepath.ToSlash(rel), "src/test/") {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		sources = append(sources, parseJavaSource(string(data), filepath.ToSlash(rel)))
+		return nil
+	})
+	direct := readJavaDirectDependencies(ctx, root)
+
+	var findings []ReachabilityFinding
+	for _, v := range vulns {
+		finding := ReachabilityFinding{
+			ID:                v.ID,
+			Package:           v.Package,
+			Version:           v.Version,
+			Ecosystem:         v.Ecosystem,
+			Summary:           v.Title,
+			Method:            "import-graph",
+			VulnerableSymbols: v.Symbols,
+		}
+		group, artifact, ok := strings.Cut(v.Package, ":")
+		if !ok {
+			finding.setStatus("unknown")
+			findings = append(findings, finding)
+			continue
+		}
+		packages, exact := javaPackages(group, artifact, v.Version)
+
+		imported := false
+		for _, src := range sources {
+			for _, ref := range src.references {
+				if !ref.inPackages(packages) {
+					continue
+				}
+				imported = true
+				location := fmt.Sprintf("%s:%d", ref.File, ref.Line)
+				if len(v.Symbols) == 0 {
+					finding.addEvidence(ReachabilityEvidence{File: ref.File, Line: ref.Line}, location+" -> "+v.Package)
+					continue
+				}
+				for _, symbol := range v.Symbols {
+					if ref.matchSymbol(symbol, src) {
+						finding.addEvidence(ReachabilityEvidence{File: ref.File, Line: ref.Line, Symbol: symbol}, location+" -> "+v.Package)
+					}
+				}
+			}
+		}
+
+		switch {
+		case len(finding.Evidence) > 0:
+			finding.setStatus("reachable")
+		case !imported && complete && exact && direct[v.Package]:
+			finding.setStatus("unreachable")
+		default:
+			// Imported without naming the vulnerable classes (which the
+			// public API may still reach), a transitive dependency another
+			// library may call, the artifact's packages could only be
+			// guessed, or sources were left out of the walk
+			finding.setStatus("unknown")
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
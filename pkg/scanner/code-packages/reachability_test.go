@@ -0,0 +1,176 @@
+package codepackages
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseJSImports(t *testing.T) {
+	content := `import _, { merge as deepMerge } from 'lodash';
+import type { Options } from "yargs";
+import * as babel from '@babel/core';
+import './polyfills';
+const { parse } = require("qs");
+const minimist = require('minimist/index.js');
+export { default as chalk } from 'chalk';
+
+babel.transformSync(code);
+_.template(src);
+`
+	imports := parseJSImports(content, "src/app.ts")
+	byPackage := make(map[string]*jsImport)
+	for _, imp := range imports {
+		byPackage[imp.Package] = imp
+	}
+
+	if len(imports) != 5 {
+		t.Fatalf("parseJSImports() returned %d imports", len(imports))
+	}
+	if _, ok := byPackage["yargs"]; ok {
+		t.Error("type-only import was recorded")
+	}
+	lodash := byPackage["lodash"]
+	if lodash.Line != 1 || len(lodash.Names) != 1 || lodash.Names[0] != "merge" || lodash.Locals[0] != "_" {
+		t.Errorf("lodash import = %+v", lodash)
+	}
+	if line, ok := lodash.matchSymbol("lodash.template"); !ok || line != 10 {
+		t.Errorf("matchSymbol(lodash.template) = %d, %v", line, ok)
+	}
+	if _, ok := lodash.matchSymbol("zipObjectDeep"); ok {
+		t.Error("matchSymbol(zipObjectDeep) matched an unused function")
+	}
+	if babel := byPackage["@babel/core"]; babel.Locals[0] != "babel" || len(babel.Uses) != 1 || babel.Uses[0].Name != "transformSync" {
+		t.Errorf("@babel/core import = %+v", babel)
+	}
+	if qs := byPackage["qs"]; qs.Line != 5 || qs.Names[0] != "parse" {
+		t.Errorf("qs require = %+v", qs)
+	}
+	if minimist := byPackage["minimist"]; minimist.Subpath != "index.js" || minimist.Line != 6 {
+		t.Errorf("minimist require = %+v", minimist)
+	}
+}
+
+func TestAnalyzeJSReachability(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"package.json":                       `{"dependencies": {"lodash": "^4.17.0", "mkdirp": "^0.5.0", "express": "^4.0.0"}}`,
+		"src/index.js":                       "const mkdirp = require('mkdirp');\nconst _ = require('lodash');\n\nmodule.exports = _.merge({}, {});\n",
+		"src/index.test.js":                  "const express = require('express');",
+		"node_modules/lodash/package.json":   `{"version": "4.17.20"}`,
+		"node_modules/mkdirp/package.json":   `{"version": "0.5.1"}`,
+		"node_modules/mkdirp/index.js":       "var minimist = require('minimist');",
+		"node_modules/minimist/package.json": `{"version": "0.0.8"}`,
+		"node_modules/express/package.json":  `{"version": "4.17.0"}`,
+	})
+
+	findings := analyzeJSReachability(context.Background(), root, []VulnFinding{
+		{ID: "GHSA-template", Package: "lodash", Version: "4.17.20", Ecosystem: "npm", Symbols: []string{"lodash.template"}},
+		{ID: "GHSA-merge", Package: "lodash", Version: "4.17.20", Ecosystem: "npm", Symbols: []string{"merge"}},
+		{ID: "GHSA-minimist", Package: "minimist", Version: "0.0.8", Ecosystem: "npm"},
+		{ID: "GHSA-express", Package: "express", Version: "4.17.0", Ecosystem: "npm"},
+	})
+
+	status := make(map[string]ReachabilityFinding)
+	for _, f := range findings {
+		status[f.ID] = f
+	}
+	if f := status["GHSA-template"]; f.ReachabilityStatus != "unknown" || !f.Reachable {
+		t.Errorf("imported package, vulnerable function not named: %+v", f)
+	}
+	if f := status["GHSA-merge"]; f.ReachabilityStatus != "reachable" || f.Evidence[0].File != "src/index.js" || f.Evidence[0].Line != 4 {
+		t.Errorf("used vulnerable function: %+v", f)
+	}
+	f := status["GHSA-minimist"]
+	if f.ReachabilityStatus != "reachable" || len(f.CallPaths) != 1 || f.CallPaths[0] != "src/index.js:1 -> mkdirp -> minimist" {
+		t.Errorf("transitive dependency: %+v", f)
+	}
+	if f.Evidence[0].File != "node_modules/mkdirp/index.js" {
+		t.Errorf("transitive evidence = %+v", f.Evidence)
+	}
+	if f := status["GHSA-express"]; f.ReachabilityStatus != "unreachable" {
+		t.Errorf("package only used by tests: %+v", f)
+	}
+
+	// A source left out of the walk may import the package
+	writeTree(t, root, map[string]string{"src/bundle.js": strings.Repeat("x", maxReachabilityFileSize+1)})
+	findings = analyzeJSReachability(context.Background(), root, []VulnFinding{{ID: "GHSA-express", Package: "express", Version: "4.17.0", Ecosystem: "npm"}})
+	if f := findings[0]; f.ReachabilityStatus != "unknown" {
+		t.Errorf("package not imported by a truncated walk: %+v", f)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	findings = analyzeJSReachability(ctx, root, []VulnFinding{{ID: "GHSA-express", Package: "express", Version: "4.17.0", Ecosystem: "npm"}})
+	if f := findings[0]; f.ReachabilityStatus != "unknown" {
+		t.Errorf("cancelled walk: %+v", f)
+	}
+}
+
+func TestAnalyzeJavaReachability(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // No local jar cache
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"pom.xml": `<project><dependencies>
+			<dependency><groupId>org.apache.commons</groupId><artifactId>commons-text</artifactId></dependency>
+		</dependencies></project>`,
+		"src/main/java/com/example/App.java": `package com.example;
+
+import org.apache.commons.text.StringSubstitutor;
+import com.fasterxml.jackson.databind.*;
+import org.apache.logging.log4j.LogManager;
+
+public class App {
+    ObjectMapper mapper = new ObjectMapper();
+    String s = StringSubstitutor.replace("x", null);
+}
+`,
+		"src/test/java/com/example/AppTest.java": "import org.yaml.snakeyaml.Yaml;",
+	})
+
+	findings := analyzeJavaReachability(context.Background(), root, []VulnFinding{
+		{ID: "text4shell", Package: "org.apache.commons:commons-text", Version: "1.9", Ecosystem: "maven",
+			Symbols: []string{"org.apache.commons.text.StringSubstitutor.createInterpolator"}},
+		{ID: "jackson-typing", Package: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.10", Ecosystem: "maven",
+			Symbols: []string{"com.fasterxml.jackson.databind.ObjectMapper#enableDefaultTyping"}},
+		{ID: "jackson-node", Package: "com.fasterxml.jackson.core:jackson-databind", Version: "2.9.10", Ecosystem: "maven",
+			Symbols: []string{"com.fasterxml.jackson.databind.node.TreeTraversingParser"}},
+		{ID: "snakeyaml", Package: "org.yaml:snakeyaml", Version: "1.33", Ecosystem: "maven"},
+		{ID: "log4shell", Package: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", Ecosystem: "maven"},
+	})
+
+	status := make(map[string]ReachabilityFinding)
+	for _, f := range findings {
+		status[f.ID] = f
+	}
+	if f := status["text4shell"]; f.ReachabilityStatus != "reachable" || f.Evidence[0].Line != 3 {
+		t.Errorf("imported vulnerable class: %+v", f)
+	}
+	if f := status["jackson-typing"]; f.ReachabilityStatus != "reachable" || f.Evidence[0].File != "src/main/java/com/example/App.java" {
+		t.Errorf("class used through a wildcard import: %+v", f)
+	}
+	if f := status["jackson-node"]; f.ReachabilityStatus != "unknown" {
+		t.Errorf("imported package, vulnerable class not named: %+v", f)
+	}
+	if f := status["snakeyaml"]; f.ReachabilityStatus != "unknown" {
+		t.Errorf("transitive artifact only used by tests: %+v", f)
+	}
+	if f := status["log4shell"]; f.ReachabilityStatus != "unknown" || len(f.Evidence) != 0 {
+		t.Errorf("log4j-api import matched log4j-core: %+v", f)
+	}
+}
@@ -2,7 +2,13 @@
 // This scanner generates SBOMs and performs comprehensive package analysis.
 package codepackages
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/crashappsec/zero/pkg/core/behavior"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/core/upgrades"
+)
 
 // Result holds all feature results
 type Result struct {
@@ -29,9 +35,17 @@ type Summary struct {
 	Typosquats      *TyposquatsSummary      `json:"typosquats,omitempty"`
 	Deprecations    *DeprecationsSummary    `json:"deprecations,omitempty"`
 	Duplicates      *DuplicatesSummary      `json:"duplicates,omitempty"`
+	Cache           *CacheSummary           `json:"cache,omitempty"`
 	Errors          []string                `json:"errors,omitempty"`
 }
 
+// CacheSummary describes how registry and API data was served
+type CacheSummary struct {
+	liveapi.CacheStats
+	Offline       bool `json:"offline,omitempty"`        // Run with --offline: only cached data was used
+	PossiblyStale bool `json:"possibly_stale,omitempty"` // Some results rely on data past its TTL or missing from the cache
+}
+
 // Findings holds findings from all features
 type Findings struct {
 	// SBOM features
@@ -67,13 +81,17 @@ type ComponentData struct {
 
 // VulnsSummary contains vulnerability scanning summary
 type VulnsSummary struct {
-	TotalVulnerabilities int    `json:"total_vulnerabilities"`
-	Critical             int    `json:"critical"`
-	High                 int    `json:"high"`
-	Medium               int    `json:"medium"`
-	Low                  int    `json:"low"`
-	KEVCount             int    `json:"kev_count"`
-	Error                string `json:"error,omitempty"`
+	TotalVulnerabilities int      `json:"total_vulnerabilities"`
+	Critical             int      `json:"critical"`
+	High                 int      `json:"high"`
+	Medium               int      `json:"medium"`
+	Low                  int      `json:"low"`
+	KEVCount             int      `json:"kev_count"`
+	EPSSCount            int      `json:"epss_count"`                     // Vulnerabilities with an EPSS score
+	FixFirst             []string `json:"fix_first,omitempty"`            // Top-ranked vulnerabilities, as "ID (package@version)"
+	Sources              []string `json:"sources,omitempty"`              // osv-db, osv-api, osv-scanner, epss, kev
+	UncheckedEcosystems  []string `json:"unchecked_ecosystems,omitempty"` // Ecosystems no source had data for; their packages were not checked
+	Error                string   `json:"error,omitempty"`
 }
 
 // HealthSummary contains package health summary
@@ -98,20 +116,25 @@ type LicensesSummary struct {
 	NeedsReview      int            `json:"needs_review"`
 	Unknown          int            `json:"unknown"`
 	PolicyViolations int            `json:"policy_violations"`
+	Distribution     string         `json:"distribution,omitempty"`
 	LicenseCounts    map[string]int `json:"license_counts,omitempty"`
+	ByCategory       map[string]int `json:"by_category,omitempty"`
+	NoticesFile      string         `json:"notices_file,omitempty"`
 	Error            string         `json:"error,omitempty"`
 }
 
 // MalcontentSummary contains malware detection summary
 type MalcontentSummary struct {
-	TotalFiles    int    `json:"total_files"`
-	TotalFindings int    `json:"total_findings"`
-	Critical      int    `json:"critical"`
-	High          int    `json:"high"`
-	Medium        int    `json:"medium"`
-	Low           int    `json:"low"`
-	FilesWithRisk int    `json:"files_with_risk"`
-	Error         string `json:"error,omitempty"`
+	TotalFiles        int      `json:"total_files"`
+	TotalFindings     int      `json:"total_findings"`
+	Critical          int      `json:"critical"`
+	High              int      `json:"high"`
+	Medium            int      `json:"medium"`
+	Low               int      `json:"low"`
+	FilesWithRisk     int      `json:"files_with_risk"`
+	PackagesInspected int      `json:"packages_inspected,omitempty"` // builtin engine
+	Engines           []string `json:"engines,omitempty"`            // mal, builtin
+	Error             string   `json:"error,omitempty"`
 }
 
 // ConfusionSummary contains dependency confusion summary
@@ -127,13 +150,14 @@ type ConfusionSummary struct {
 
 // ReachabilitySummary contains vulnerability reachability summary
 type ReachabilitySummary struct {
-	Supported           bool    `json:"supported"`
-	TotalVulns          int     `json:"total_vulns"`
-	ReachableVulns      int     `json:"reachable_vulns"`
-	UnreachableVulns    int     `json:"unreachable_vulns"`
-	UnknownReachability int     `json:"unknown_reachability"`
-	ReductionPercent    float64 `json:"reduction_percent"`
-	Error               string  `json:"error,omitempty"`
+	Supported           bool     `json:"supported"`
+	TotalVulns          int      `json:"total_vulns"`
+	ReachableVulns      int      `json:"reachable_vulns"`
+	UnreachableVulns    int      `json:"unreachable_vulns"`
+	UnknownReachability int      `json:"unknown_reachability"`
+	ReductionPercent    float64  `json:"reduction_percent"`
+	Ecosystems          []string `json:"ecosystems,omitempty"` // Ecosystems analyzed
+	Error               string   `json:"error,omitempty"`
 }
 
 // ProvenanceSummary contains provenance verification summary
@@ -158,18 +182,21 @@ type BundleSummary struct {
 
 // RecommendationsSummary contains package recommendations summary
 type RecommendationsSummary struct {
-	TotalRecommendations    int    `json:"total_recommendations"`
-	SecurityRecommendations int    `json:"security_recommendations"`
-	HealthRecommendations   int    `json:"health_recommendations"`
-	Error                   string `json:"error,omitempty"`
+	TotalRecommendations    int               `json:"total_recommendations"`
+	SecurityRecommendations int               `json:"security_recommendations"`
+	HealthRecommendations   int               `json:"health_recommendations"`
+	UpgradePlan             *upgrades.Summary `json:"upgrade_plan,omitempty"`
+	Error                   string            `json:"error,omitempty"`
 }
 
 // TyposquatsSummary contains typosquatting detection summary
 type TyposquatsSummary struct {
-	TotalChecked     int    `json:"total_checked"`
-	SuspiciousCount  int    `json:"suspicious_count"`
-	NewPackagesCount int    `json:"new_packages_count"` // Packages < 30 days old
-	Error            string `json:"error,omitempty"`
+	TotalChecked     int            `json:"total_checked"`
+	SuspiciousCount  int            `json:"suspicious_count"`
+	NewPackagesCount int            `json:"new_packages_count"` // Packages < 30 days old
+	ByModel          map[string]int `json:"by_model,omitempty"` // Suspicious names per similarity model
+	CorpusVersion    string         `json:"corpus_version,omitempty"`
+	Error            string         `json:"error,omitempty"`
 }
 
 // DeprecationsSummary contains deprecated package summary
@@ -201,6 +228,21 @@ type VulnFinding struct {
 	Title     string   `json:"title,omitempty"`
 	FixedIn   string   `json:"fixed_in,omitempty"`
 	InKEV     bool     `json:"in_kev"`
+	Symbols   []string `json:"symbols,omitempty"` // Vulnerable functions/classes named by the advisory
+
+	CVSSScore      float64 `json:"cvss_score,omitempty"`  // Highest CVSS v3/v4 score of the advisory
+	CVSSVector     string  `json:"cvss_vector,omitempty"` // Vector the score was computed from
+	EPSS           float64 `json:"epss,omitempty"`        // Probability of exploitation in the next 30 days
+	EPSSPercentile float64 `json:"epss_percentile,omitempty"`
+	Reachability   string  `json:"reachability,omitempty"` // reachable, unreachable, unknown (reachability feature)
+	Scope          string  `json:"scope,omitempty"`        // SBOM scope: required, optional, excluded
+
+	PriorityScore   float64  `json:"priority_score"` // 0-100, see priority.go
+	PriorityRank    int      `json:"priority_rank"`  // 1 is the first to fix
+	PriorityFactors []string `json:"priority_factors,omitempty"`
+
+	DependencyPath  []string   `json:"dependency_path,omitempty"`  // Shortest path from a direct dependency
+	DependencyPaths [][]string `json:"dependency_paths,omitempty"` // Every path, up to cyclonedx.MaxPaths
 }
 
 // HealthFinding represents a package health finding
@@ -218,11 +260,19 @@ type HealthFinding struct {
 
 // LicenseFinding represents a license finding
 type LicenseFinding struct {
-	Package   string   `json:"package"`
-	Version   string   `json:"version"`
-	Ecosystem string   `json:"ecosystem"`
-	Licenses  []string `json:"licenses"`
-	Status    string   `json:"status"`
+	Package    string   `json:"package"`
+	Version    string   `json:"version"`
+	Ecosystem  string   `json:"ecosystem"`
+	Scope      string   `json:"scope,omitempty"`
+	Expression string   `json:"expression,omitempty"` // Normalized SPDX expression
+	Licenses   []string `json:"licenses"`             // Licenses the policy decision rests on
+	Category   string   `json:"category"`             // permissive, weak-copyleft, strong-copyleft, proprietary, unknown
+	Status     string   `json:"status"`               // allowed, review, denied, unknown
+	Reason     string   `json:"reason,omitempty"`
+
+	// How the package is pulled in, for violations
+	DependencyPath  []string   `json:"dependency_path,omitempty"`
+	DependencyPaths [][]string `json:"dependency_paths,omitempty"`
 }
 
 // MalcontentFinding represents a malware detection finding
@@ -231,6 +281,20 @@ type MalcontentFinding struct {
 	Risk      string   `json:"risk"`
 	RiskScore int      `json:"risk_score"`
 	Behaviors []string `json:"behaviors"`
+	Engine    string   `json:"engine,omitempty"` // mal or builtin
+
+	// What runs the file at install and the heuristics it tripped, for
+	// builtin findings
+	Hook     string              `json:"hook,omitempty"`
+	Evidence []behavior.Evidence `json:"evidence,omitempty"`
+
+	// The installed package the file belongs to, for files under
+	// node_modules, vendor or site-packages
+	Package         string     `json:"package,omitempty"`
+	Version         string     `json:"version,omitempty"`
+	Ecosystem       string     `json:"ecosystem,omitempty"`
+	DependencyPath  []string   `json:"dependency_path,omitempty"`
+	DependencyPaths [][]string `json:"dependency_paths,omitempty"`
 }
 
 // ConfusionFinding represents a dependency confusion finding
@@ -248,12 +312,24 @@ type ConfusionFinding struct {
 
 // ReachabilityFinding represents a reachability analysis finding
 type ReachabilityFinding struct {
-	ID                 string `json:"id"`
-	Package            string `json:"package"`
-	Version            string `json:"version"`
-	Summary            string `json:"summary"`
-	ReachabilityStatus string `json:"reachability_status"`
-	Reachable          bool   `json:"reachable"`
+	ID                 string                 `json:"id"`
+	Package            string                 `json:"package"`
+	Version            string                 `json:"version"`
+	Ecosystem          string                 `json:"ecosystem,omitempty"`
+	Summary            string                 `json:"summary"`
+	ReachabilityStatus string                 `json:"reachability_status"`
+	Reachable          bool                   `json:"reachable"`
+	Method             string                 `json:"method,omitempty"`             // call-analysis (osv-scanner), import-graph (native)
+	Evidence           []ReachabilityEvidence `json:"evidence,omitempty"`           // Where the package or its vulnerable symbols are used
+	CallPaths          []string               `json:"call_paths,omitempty"`         // e.g. "src/app.js:3 -> mkdirp -> minimist"
+	VulnerableSymbols  []string               `json:"vulnerable_symbols,omitempty"` // Symbols the advisory names, if any
+}
+
+// ReachabilityEvidence is a source location that reaches a vulnerable package
+type ReachabilityEvidence struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Symbol string `json:"symbol,omitempty"` // Vulnerable symbol used at this location
 }
 
 // ProvenanceFinding represents a provenance verification finding
@@ -276,21 +352,31 @@ type BundleFinding struct {
 
 // RecommendationFinding represents a package recommendation
 type RecommendationFinding struct {
-	Package        string `json:"package"`
-	CurrentVersion string `json:"current_version"`
-	Alternative    string `json:"alternative,omitempty"`
-	Reason         string `json:"reason"`
-	Priority       string `json:"priority"`
+	Package        string   `json:"package"`
+	CurrentVersion string   `json:"current_version"`
+	Alternative    string   `json:"alternative,omitempty"`
+	Reason         string   `json:"reason"`
+	Priority       string   `json:"priority"`
+	Type           string   `json:"type,omitempty"` // upgrade
+	Ecosystem      string   `json:"ecosystem,omitempty"`
+	TargetVersion  string   `json:"target_version,omitempty"`
+	Method         string   `json:"method,omitempty"` // bump, override, require, pin
+	Breaking       bool     `json:"breaking,omitempty"`
+	Fixes          []string `json:"fixes,omitempty"`
+	Resolves       []string `json:"resolves,omitempty"`
+	Via            []string `json:"via,omitempty"`
 }
 
 // TyposquatFinding represents a typosquatting finding
 type TyposquatFinding struct {
-	Package        string `json:"package"`
-	Ecosystem      string `json:"ecosystem"`
-	SimilarTo      string `json:"similar_to,omitempty"`
-	Reason         string `json:"reason"`
-	AgeInDays      int    `json:"age_in_days,omitempty"`
-	RiskLevel      string `json:"risk_level"`
+	Package        string  `json:"package"`
+	Ecosystem      string  `json:"ecosystem"`
+	SimilarTo      string  `json:"similar_to,omitempty"`
+	Reason         string  `json:"reason"`
+	AgeInDays      int     `json:"age_in_days,omitempty"`
+	RiskLevel      string  `json:"risk_level"`
+	Model          string  `json:"model,omitempty"`      // Similarity model: homoglyph, keyboard-adjacency, ...
+	Confidence     float64 `json:"confidence,omitempty"` // 0-1
 }
 
 // DeprecationFinding represents a deprecated package finding
@@ -323,6 +409,7 @@ type GenerationSummary struct {
 	ByEcosystem     map[string]int `json:"by_ecosystem"`
 	HasDependencies bool           `json:"has_dependencies"`
 	SBOMPath        string         `json:"sbom_path"`
+	SourceFormat    string         `json:"source_format,omitempty"` // Format of an imported SBOM: cyclonedx, spdx-json, spdx-tag-value, spdx-jsonld
 	Error           string         `json:"error,omitempty"`
 }
 
@@ -442,6 +529,7 @@ type VersionChange struct {
 	Ecosystem  string `json:"ecosystem,omitempty"`
 	OldVersion string `json:"old_version"`
 	NewVersion string `json:"new_version"`
+	Direction  string `json:"direction,omitempty"` // upgrade, downgrade (empty if equivalent)
 }
 
 // =============================================================================
@@ -500,20 +588,14 @@ type CycloneDXBOM struct {
 		} `json:"component,omitempty"`
 	} `json:"metadata,omitempty"`
 	Components []struct {
-		Type    string `json:"type"`
-		Name    string `json:"name"`
-		Version string `json:"version"`
-		Purl    string `json:"purl,omitempty"`
-		BomRef  string `json:"bom-ref,omitempty"`
-		Scope   string `json:"scope,omitempty"`
-		Licenses []struct {
-			License struct {
-				ID   string `json:"id,omitempty"`
-				Name string `json:"name,omitempty"`
-			} `json:"license,omitempty"`
-			Expression string `json:"expression,omitempty"`
-		} `json:"licenses,omitempty"`
-		Hashes []struct {
+		Type     string       `json:"type"`
+		Name     string       `json:"name"`
+		Version  string       `json:"version"`
+		Purl     string       `json:"purl,omitempty"`
+		BomRef   string       `json:"bom-ref,omitempty"`
+		Scope    string       `json:"scope,omitempty"`
+		Licenses []cdxLicense `json:"licenses,omitempty"`
+		Hashes   []struct {
 			Alg     string `json:"alg"`
 			Content string `json:"content"`
 		} `json:"hashes,omitempty"`
@@ -527,3 +609,12 @@ type CycloneDXBOM struct {
 		DependsOn []string `json:"dependsOn,omitempty"`
 	} `json:"dependencies,omitempty"`
 }
+
+// cdxLicense is a CycloneDX license choice: a license or an SPDX expression
+type cdxLicense struct {
+	License struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"license,omitempty"`
+	Expression string `json:"expression,omitempty"`
+}
@@ -43,6 +43,14 @@ func (s *QualityScanner) Dependencies() []string {
 	return nil
 }
 
+func (s *QualityScanner) Version() string {
+	return Version
+}
+
+func (s *QualityScanner) Tools() []string {
+	return []string{"semgrep"}
+}
+
 func (s *QualityScanner) EstimateDuration(fileCount int) time.Duration {
 	est := 10 + fileCount/500
 	return time.Duration(est) * time.Second
@@ -59,6 +67,8 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 		Findings:    Findings{},
 	}
 
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -70,7 +80,12 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, techDebtResult := s.runTechDebt(ctx, opts, cfg.TechDebt)
+			summary, techDebtResult, ok := scanner.RunFeatureFindings(tracker, "tech_debt", func(ctx context.Context) (*TechDebtSummary, *TechDebtResult) {
+				return s.runTechDebt(ctx, opts, cfg.TechDebt)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "tech_debt")
 			result.Summary.TechDebt = summary
@@ -83,7 +98,12 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, complexityResult := s.runComplexity(ctx, opts, cfg.Complexity)
+			summary, complexityResult, ok := scanner.RunFeatureFindings(tracker, "complexity", func(ctx context.Context) (*ComplexitySummary, *ComplexityResult) {
+				return s.runComplexity(ctx, opts, cfg.Complexity)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "complexity")
 			result.Summary.Complexity = summary
@@ -100,7 +120,12 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary := s.runTestCoverage(ctx, opts, cfg.TestCoverage)
+			summary, ok := scanner.RunFeature(tracker, "test_coverage", func(ctx context.Context) *TestCoverageSummary {
+				return s.runTestCoverage(ctx, opts, cfg.TestCoverage)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "test_coverage")
 			result.Summary.TestCoverage = summary
@@ -112,7 +137,12 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary := s.runCodeDocs(ctx, opts, cfg.CodeDocs)
+			summary, ok := scanner.RunFeature(tracker, "code_docs", func(ctx context.Context) *CodeDocsSummary {
+				return s.runCodeDocs(ctx, opts, cfg.CodeDocs)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "code_docs")
 			result.Summary.CodeDocs = summary
@@ -129,6 +159,7 @@ func (s *QualityScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*s
 	_ = scanResult.SetMetadata(map[string]interface{}{
 		"features_run": result.FeaturesRun,
 	})
+	tracker.Apply(scanResult)
 
 	if opts.OutputDir != "" {
 		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
@@ -35,6 +35,19 @@ type SecretsConfig struct {
 	AIAnalysis          AIAnalysisConfig         `json:"ai_analysis"`           // Claude-powered FP reduction
 	RotationGuidance    bool                     `json:"rotation_guidance"`     // Add rotation recommendations
 	IaCSecrets          IaCSecretsConfig         `json:"iac_secrets"`           // IaC-specific secrets detection
+	Verification        VerificationConfig       `json:"verification"`          // Liveness checks against providers
+}
+
+// VerificationConfig configures secret liveness verification. Each check is
+// a read-only call to the secret's provider, so it is off by default.
+type VerificationConfig struct {
+	Enabled           bool              `json:"enabled"`
+	Providers         []string          `json:"providers"`           // Providers to verify against (default: all)
+	MaxVerifications  int               `json:"max_verifications"`   // Distinct secrets checked per scan (default: 50)
+	RequestsPerSecond int               `json:"requests_per_second"` // Per provider (default: 2)
+	TimeoutSeconds    int               `json:"timeout_seconds"`     // Per request (default: 10)
+	Endpoints         map[string]string `json:"endpoints"`           // Base URL overrides by provider, e.g. for local stand-ins
+	AuditLog          string            `json:"audit_log"`           // JSONL audit path (default: secrets-verification.jsonl in the analysis directory)
 }
 
 // IaCSecretsConfig configures IaC-specific secrets detection
@@ -175,6 +188,12 @@ func DefaultConfig() FeatureConfig {
 			IaCSecrets: IaCSecretsConfig{
 				Enabled: true, // Enabled by default - catches secrets in Terraform, K8s, etc.
 			},
+			Verification: VerificationConfig{
+				Enabled:           false, // Disabled by default - sends secrets to their providers
+				MaxVerifications:  50,
+				RequestsPerSecond: 2,
+				TimeoutSeconds:    10,
+			},
 		},
 		API: APIConfig{
 			Enabled:        true,
@@ -286,6 +286,12 @@ func (s *GitHistoryScanner) parseSinceDate() time.Time {
 
 // isFalsePositive checks if a match is a known false positive
 func (s *GitHistoryScanner) isFalsePositive(line, filename string) bool {
+	return isSecretFalsePositive(line, filename)
+}
+
+// isSecretFalsePositive checks if a pattern match is in a test or
+// documentation file, or is a placeholder rather than a secret
+func isSecretFalsePositive(line, filename string) bool {
 	lineLower := strings.ToLower(line)
 	filenameLower := strings.ToLower(filename)
 
@@ -12,7 +12,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
 	coreFindings "github.com/crashappsec/zero/pkg/core/findings"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
 	"github.com/crashappsec/zero/pkg/scanner"
 	"github.com/crashappsec/zero/pkg/scanner/common"
 )
@@ -41,6 +43,18 @@ func (s *CodeSecurityScanner) Dependencies() []string {
 	return nil
 }
 
+func (s *CodeSecurityScanner) Version() string {
+	return Version
+}
+
+func (s *CodeSecurityScanner) Tools() []string {
+	return []string{"semgrep"}
+}
+
+func (s *CodeSecurityScanner) Artifacts() []string {
+	return []string{CBOMFile}
+}
+
 func (s *CodeSecurityScanner) EstimateDuration(fileCount int) time.Duration {
 	est := 15 + fileCount/300
 	return time.Duration(est) * time.Second
@@ -57,10 +71,14 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		Findings:    Findings{},
 	}
 
+	// Each feature runs under its own deadline so one slow feature cannot
+	// cost the results of the others
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	// Check if semgrep is available (needed for all features)
+	// Check if semgrep is available (needed for vulns and api)
 	hasSemgrep := common.ToolExists("semgrep")
 
 	// Run features in parallel where possible
@@ -68,7 +86,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runVulns(ctx, opts, cfg.Vulns)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "vulns", func(ctx context.Context) (*VulnsSummary, []VulnFinding) {
+				return s.runVulns(ctx, opts, cfg.Vulns)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "vulns")
 			result.Summary.Vulns = summary
@@ -81,28 +104,35 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		mu.Unlock()
 	}
 
-	if cfg.Secrets.Enabled && hasSemgrep {
+	// Secrets fall back to native pattern matching without semgrep
+	if cfg.Secrets.Enabled {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runSecrets(ctx, opts, cfg.Secrets)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "secrets", func(ctx context.Context) (*SecretsSummary, []SecretFinding) {
+				return s.runSecrets(ctx, opts, cfg.Secrets)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "secrets")
 			result.Summary.Secrets = summary
 			result.Findings.Secrets = findings
 			mu.Unlock()
 		}()
-	} else if cfg.Secrets.Enabled {
-		mu.Lock()
-		result.Summary.Errors = append(result.Summary.Errors, "secrets: semgrep not installed")
-		mu.Unlock()
 	}
 
 	if cfg.API.Enabled && hasSemgrep {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runAPI(ctx, opts, cfg.API)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "api", func(ctx context.Context) (*APISummary, []APIFinding) {
+				return s.runAPI(ctx, opts, cfg.API)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "api")
 			result.Summary.API = summary
@@ -120,8 +150,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			securityScanner := NewGitHistorySecurityScanner(cfg.Secrets.GitHistorySecurity)
-			securityResult, err := securityScanner.ScanRepository(opts.RepoPath)
+			securityResult, err, ok := scanner.RunFeatureFindings(tracker, "git_history_security", func(context.Context) (*GitHistorySecurityResult, error) {
+				return NewGitHistorySecurityScanner(cfg.Secrets.GitHistorySecurity).ScanRepository(opts.RepoPath)
+			})
+			if !ok {
+				return
+			}
 			// Include results even with errors (may have partial results)
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "git_history_security")
@@ -141,7 +175,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runCiphers(ctx, opts, cfg.Ciphers)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "ciphers", func(ctx context.Context) (*CiphersSummary, []CipherFinding) {
+				return s.runCiphers(ctx, opts, cfg.Ciphers)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "ciphers")
 			result.Summary.Ciphers = summary
@@ -154,7 +193,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runKeys(ctx, opts, cfg.Keys)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "keys", func(ctx context.Context) (*KeysSummary, []KeyFinding) {
+				return s.runKeys(ctx, opts, cfg.Keys)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "keys")
 			result.Summary.Keys = summary
@@ -167,7 +211,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runRandom(ctx, opts, cfg.Random)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "random", func(ctx context.Context) (*RandomSummary, []RandomFinding) {
+				return s.runRandom(ctx, opts, cfg.Random)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "random")
 			result.Summary.Random = summary
@@ -180,7 +229,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runTLS(ctx, opts, cfg.TLS)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "tls", func(ctx context.Context) (*TLSSummary, []TLSFinding) {
+				return s.runTLS(ctx, opts, cfg.TLS)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "tls")
 			result.Summary.TLS = summary
@@ -193,7 +247,12 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, certResults := s.runCertificates(ctx, opts, cfg.Certificates)
+			summary, certResults, ok := scanner.RunFeatureFindings(tracker, "certificates", func(ctx context.Context) (*CertificatesSummary, *CertificatesResult) {
+				return s.runCertificates(ctx, opts, cfg.Certificates)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "certificates")
 			result.Summary.Certificates = summary
@@ -217,6 +276,7 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 		metadata["git_history_security"] = result.GitHistorySecurity
 	}
 	_ = scanResult.SetMetadata(metadata)
+	tracker.Apply(scanResult)
 
 	if opts.OutputDir != "" {
 		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
@@ -227,11 +287,51 @@ func (s *CodeSecurityScanner) Run(ctx context.Context, opts *scanner.ScanOptions
 			return nil, fmt.Errorf("writing result: %w", err)
 		}
 
+		// Export CBOM (CycloneDX format) when any crypto feature ran
+		if hasCryptoFeatures(result.FeaturesRun) {
+			if err := s.exportCBOM(opts.OutputDir, result); err != nil {
+				// Log warning but don't fail the scan
+				if opts.OnStatus != nil {
+					opts.OnStatus(fmt.Sprintf("Warning: failed to export CBOM: %v", err))
+				}
+			}
+		}
 	}
 
 	return scanResult, nil
 }
 
+// CBOMFile is the filename of the CycloneDX CBOM written next to sbom.cdx.json
+const CBOMFile = "cbom.cdx.json"
+
+// cbomFeatures are the features whose findings feed the CBOM
+var cbomFeatures = map[string]bool{
+	"ciphers":      true,
+	"keys":         true,
+	"tls":          true,
+	"certificates": true,
+}
+
+// hasCryptoFeatures reports whether any CBOM-relevant feature ran
+func hasCryptoFeatures(featuresRun []string) bool {
+	for _, f := range featuresRun {
+		if cbomFeatures[f] {
+			return true
+		}
+	}
+	return false
+}
+
+// exportCBOM exports crypto findings as a CycloneDX CBOM
+func (s *CodeSecurityScanner) exportCBOM(outputDir string, result *Result) error {
+	exporter := cyclonedx.NewExporter(outputDir)
+	bom, err := exporter.ExportCBOM(result)
+	if err != nil {
+		return err
+	}
+	return exporter.WriteCBOM(bom, CBOMFile)
+}
+
 func getConfig(opts *scanner.ScanOptions) FeatureConfig {
 	if opts.FeatureConfig == nil {
 		return DefaultConfig()
@@ -419,6 +519,7 @@ func parseVulnsOutput(data []byte, opts *scanner.ScanOptions, cfg VulnsConfig) (
 func (s *CodeSecurityScanner) runSecrets(ctx context.Context, opts *scanner.ScanOptions, cfg SecretsConfig) (*SecretsSummary, []SecretFinding) {
 	var allFindings []SecretFinding
 	var semgrepFindings []SecretFinding
+	var patternFindings []SecretFinding
 	var entropyFindings []SecretFinding
 	var historyFindings []SecretFinding
 	var iacSecretsFindings []SecretFinding
@@ -434,10 +535,20 @@ func (s *CodeSecurityScanner) runSecrets(ctx context.Context, opts *scanner.Scan
 	var mu sync.Mutex
 	var semgrepSummary *SecretsSummary
 
-	// Run semgrep secrets detection
+	// Run semgrep secrets detection. Its p/secrets rules come from the
+	// semgrep registry, so without semgrep, or offline, the builtin
+	// patterns are matched natively instead.
+	useSemgrep := common.ToolExists("semgrep") && !liveapi.Offline()
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
+		if !useSemgrep {
+			found, _ := NewPatternScanner(cfg.RedactSecrets).ScanDirectory(ctx, opts.RepoPath, opts)
+			mu.Lock()
+			patternFindings = found
+			mu.Unlock()
+			return
+		}
 		result, err := common.RunCommand(ctx, "semgrep",
 			"--config", "p/secrets",
 			"--json",
@@ -550,6 +661,10 @@ func (s *CodeSecurityScanner) runSecrets(ctx context.Context, opts *scanner.Scan
 	// Merge all findings and deduplicate
 	allFindings = append(allFindings, semgrepFindings...)
 	semgrepSummary.BySource["semgrep"] = len(semgrepFindings)
+	allFindings = append(allFindings, patternFindings...)
+	if !useSemgrep {
+		semgrepSummary.BySource["patterns"] = len(patternFindings)
+	}
 
 	// Add entropy findings (dedupe by file:line)
 	seen := make(map[string]bool)
@@ -600,6 +715,12 @@ func (s *CodeSecurityScanner) runSecrets(ctx context.Context, opts *scanner.Scan
 		allFindings = EnrichWithRotation(allFindings, rotationDB)
 	}
 
+	// Check whether secrets are live if enabled. Verified secrets are
+	// critical whatever the rule said.
+	if cfg.Verification.Enabled {
+		allFindings, semgrepSummary.Verification = NewSecretsVerifier(cfg.Verification, opts).VerifyFindings(ctx, allFindings)
+	}
+
 	// Run AI analysis for false positive detection if enabled
 	if cfg.AIAnalysis.Enabled {
 		aiAnalyzer := NewAIAnalyzer(cfg.AIAnalysis, opts.RepoPath)
@@ -1,6 +1,9 @@
 package codesecurity
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/crashappsec/zero/pkg/scanner"
@@ -504,3 +507,67 @@ func TestParseAPIOutput(t *testing.T) {
 		t.Errorf("summary.TotalFindings = %d, want 1", summary.TotalFindings)
 	}
 }
+
+func TestHasCryptoFeatures(t *testing.T) {
+	tests := []struct {
+		features []string
+		want     bool
+	}{
+		{nil, false},
+		{[]string{"vulns", "secrets", "api"}, false},
+		{[]string{"random"}, false},
+		{[]string{"vulns", "ciphers"}, true},
+		{[]string{"certificates"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := hasCryptoFeatures(tt.features); got != tt.want {
+			t.Errorf("hasCryptoFeatures(%v) = %v, want %v", tt.features, got, tt.want)
+		}
+	}
+}
+
+func TestExportCBOM(t *testing.T) {
+	dir := t.TempDir()
+	s := &CodeSecurityScanner{}
+
+	result := &Result{
+		FeaturesRun: []string{"ciphers", "tls"},
+		Findings: Findings{
+			Ciphers: []CipherFinding{
+				{Algorithm: "DES", Severity: "high", File: "crypto.go", Line: 10, Description: "DES is broken"},
+			},
+			TLS: []TLSFinding{
+				{Type: "insecure-skip-verify", Severity: "high", File: "client.go", Line: 22, Description: "TLS verification disabled"},
+			},
+		},
+	}
+
+	if err := s.exportCBOM(dir, result); err != nil {
+		t.Fatalf("exportCBOM() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, CBOMFile))
+	if err != nil {
+		t.Fatalf("reading CBOM: %v", err)
+	}
+
+	var bom struct {
+		BOMFormat       string            `json:"bomFormat"`
+		Components      []json.RawMessage `json:"components"`
+		Vulnerabilities []json.RawMessage `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("parsing CBOM: %v", err)
+	}
+
+	if bom.BOMFormat != "CycloneDX" {
+		t.Errorf("bomFormat = %q, want CycloneDX", bom.BOMFormat)
+	}
+	if len(bom.Components) != 2 {
+		t.Errorf("components = %d, want 2", len(bom.Components))
+	}
+	if len(bom.Vulnerabilities) != 2 {
+		t.Errorf("vulnerabilities = %d, want 2", len(bom.Vulnerabilities))
+	}
+}
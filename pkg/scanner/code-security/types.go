@@ -77,9 +77,22 @@ type SecretsSummary struct {
 	FalsePositives   int `json:"false_positives,omitempty"`   // AI-identified false positives
 	ConfirmedSecrets int `json:"confirmed_secrets,omitempty"` // AI-confirmed real secrets
 
+	// Liveness verification results
+	Verification *VerificationSummary `json:"verification,omitempty"`
+
 	Error string `json:"error,omitempty"`
 }
 
+// VerificationSummary counts secret liveness checks
+type VerificationSummary struct {
+	Checked  int    `json:"checked"`  // Distinct secrets sent to a provider
+	Verified int    `json:"verified"` // Findings whose secret is live
+	Invalid  int    `json:"invalid"`  // Findings whose secret was rejected
+	Unknown  int    `json:"unknown"`  // Findings the provider gave no answer for
+	Skipped  int    `json:"skipped"`  // Findings with no verifier, no extractable secret or over the limit
+	Error    string `json:"error,omitempty"`
+}
+
 // APISummary contains API security summary
 type APISummary struct {
 	TotalFindings  int            `json:"total_findings"`
@@ -128,7 +141,7 @@ type SecretFinding struct {
 	// Detection source tracking
 	Entropy         float64 `json:"entropy,omitempty"`          // Shannon entropy score (0-8)
 	EntropyLevel    string  `json:"entropy_level,omitempty"`    // "low", "medium", "high"
-	DetectionSource string  `json:"detection_source,omitempty"` // "semgrep", "entropy", "git_history", "iac-scanner"
+	DetectionSource string  `json:"detection_source,omitempty"` // "semgrep", "patterns", "entropy", "git_history", "iac-scanner"
 	IaCType         string  `json:"iac_type,omitempty"`         // terraform, kubernetes, cloudformation, github-actions, helm
 
 	// Git history context
@@ -143,6 +156,10 @@ type SecretFinding struct {
 	Rotation        *RotationGuide `json:"rotation,omitempty"`         // Rotation steps, URLs, commands
 	ServiceProvider string         `json:"service_provider,omitempty"` // "aws", "github", "stripe", etc.
 
+	// Liveness verification (opt-in)
+	Verified     bool                `json:"verified,omitempty"`     // A provider accepted the secret
+	Verification *SecretVerification `json:"verification,omitempty"` // Verifier result
+
 	// Evidence for analyst review and rule improvement
 	Evidence *findings.Evidence `json:"evidence,omitempty"`
 }
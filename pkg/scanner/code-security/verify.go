@@ -0,0 +1,567 @@
+package codesecurity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+// Verification statuses
+const (
+	VerificationVerified = "verified" // The provider accepted the secret
+	VerificationInvalid  = "invalid"  // The provider rejected the secret
+	VerificationUnknown  = "unknown"  // No answer either way (network error, rate limit, missing context)
+)
+
+// SecretVerification is the result of checking whether a secret is live
+type SecretVerification struct {
+	Status    string `json:"status"`             // verified, invalid, unknown
+	Provider  string `json:"provider"`           // github, aws, slack, ...
+	Identity  string `json:"identity,omitempty"` // Who the secret authenticates as, when the provider says
+	Reason    string `json:"reason,omitempty"`
+	CheckedAt string `json:"checked_at"`
+}
+
+// SecretCandidate is a secret handed to a verifier
+type SecretCandidate struct {
+	Type    string // Secret type, as in the rotation database
+	Secret  string // The credential, as extracted by the verifier
+	Context string // Content of the file the secret was found in, for credentials in two parts
+}
+
+// SecretVerifier checks whether a secret is live with a minimal read-only
+// call to its provider. Verifiers never change anything at the provider.
+type SecretVerifier interface {
+	// Provider names the provider, as returned by GetServiceProvider
+	Provider() string
+	// DefaultEndpoint is the base URL called unless the config overrides it
+	DefaultEndpoint() string
+	// Extract returns the credential in text, or "" if there is none
+	Extract(text string) string
+	// Verify calls endpoint and returns the HTTP status along with the
+	// result. Identity and Reason may be empty.
+	Verify(ctx context.Context, client *http.Client, endpoint string, c SecretCandidate) (int, SecretVerification)
+}
+
+// secretVerifiers maps secret types to their verifier
+var secretVerifiers = make(map[string]SecretVerifier)
+
+// RegisterSecretVerifier registers a verifier for one or more secret types,
+// replacing any verifier registered for them before
+func RegisterSecretVerifier(v SecretVerifier, secretTypes ...string) {
+	for _, t := range secretTypes {
+		secretVerifiers[t] = v
+	}
+}
+
+// GetSecretVerifier returns the verifier for a secret type, or nil
+func GetSecretVerifier(secretType string) SecretVerifier {
+	return secretVerifiers[secretType]
+}
+
+func init() {
+	// Types include the names semgrep findings are given (getSecretType)
+	RegisterSecretVerifier(&awsVerifier{}, "aws_access_key", "aws_credential")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "github",
+		endpoint: "https://api.github.com",
+		pattern:  regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`),
+		path:     "/user",
+		header:   func(s string) (string, string) { return "Authorization", "token " + s },
+		identity: jsonField("login"),
+	}, "github_token")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "gitlab",
+		endpoint: "https://gitlab.com",
+		pattern:  regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`),
+		path:     "/api/v4/personal_access_tokens/self",
+		header:   func(s string) (string, string) { return "PRIVATE-TOKEN", s },
+		identity: jsonField("name"),
+	}, "gitlab_token")
+	RegisterSecretVerifier(&slackVerifier{}, "slack_token")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "stripe",
+		endpoint: "https://api.stripe.com",
+		pattern:  regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{24,}\b`),
+		path:     "/v1/balance",
+		header:   func(s string) (string, string) { return "Authorization", "Bearer " + s },
+		// Restricted keys without balance access are still live
+		forbiddenIsLive: true,
+	}, "stripe_secret_key", "stripe_key")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "openai",
+		endpoint: "https://api.openai.com",
+		pattern:  regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9_-]{20,}T3BlbkFJ[A-Za-z0-9_-]{20,}`),
+		path:     "/v1/models",
+		header:   func(s string) (string, string) { return "Authorization", "Bearer " + s },
+	}, "openai_api_key")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "anthropic",
+		endpoint: "https://api.anthropic.com",
+		pattern:  regexp.MustCompile(`\bsk-ant-(api|admin)[0-9]{2}-[A-Za-z0-9_-]{80,}`),
+		path:     "/v1/models",
+		header:   func(s string) (string, string) { return "x-api-key", s },
+		extra:    map[string]string{"anthropic-version": "2023-06-01"},
+	}, "anthropic_api_key")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "sendgrid",
+		endpoint: "https://api.sendgrid.com",
+		pattern:  regexp.MustCompile(`\bSG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43}\b`),
+		path:     "/v3/scopes",
+		header:   func(s string) (string, string) { return "Authorization", "Bearer " + s },
+	}, "sendgrid_api_key")
+	RegisterSecretVerifier(&httpVerifier{
+		provider: "npm",
+		endpoint: "https://registry.npmjs.org",
+		pattern:  regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`),
+		path:     "/-/whoami",
+		header:   func(s string) (string, string) { return "Authorization", "Bearer " + s },
+		identity: jsonField("username"),
+	}, "npm_token")
+}
+
+// httpVerifier verifies bearer-style tokens with one authenticated GET:
+// 2xx means live and 401 means rejected
+type httpVerifier struct {
+	provider        string
+	endpoint        string
+	pattern         *regexp.Regexp
+	path            string
+	header          func(secret string) (name, value string)
+	extra           map[string]string
+	identity        func(body []byte) string
+	forbiddenIsLive bool // 403 means authenticated but not allowed
+}
+
+func (v *httpVerifier) Provider() string        { return v.provider }
+func (v *httpVerifier) DefaultEndpoint() string { return v.endpoint }
+func (v *httpVerifier) Extract(text string) string {
+	return v.pattern.FindString(text)
+}
+
+func (v *httpVerifier) Verify(ctx context.Context, client *http.Client, endpoint string, c SecretCandidate) (int, SecretVerification) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+v.path, nil)
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+	req.Header.Set(v.header(c.Secret))
+	for k, val := range v.extra {
+		req.Header.Set(k, val)
+	}
+	code, body, err := doVerifyRequest(client, req)
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+
+	switch {
+	case code >= 200 && code < 300:
+		result := SecretVerification{Status: VerificationVerified}
+		if v.identity != nil {
+			result.Identity = v.identity(body)
+		}
+		return code, result
+	case code == http.StatusForbidden && v.forbiddenIsLive:
+		return code, SecretVerification{Status: VerificationVerified, Reason: "authenticated without permission for " + v.path}
+	case code == http.StatusUnauthorized:
+		return code, SecretVerification{Status: VerificationInvalid}
+	}
+	return code, SecretVerification{Status: VerificationUnknown, Reason: fmt.Sprintf("HTTP %d", code)}
+}
+
+// slackVerifier calls auth.test, which answers 200 with ok set either way
+type slackVerifier struct{}
+
+var slackTokenPattern = regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}`)
+
+func (slackVerifier) Provider() string        { return "slack" }
+func (slackVerifier) DefaultEndpoint() string { return "https://slack.com" }
+func (slackVerifier) Extract(text string) string {
+	return slackTokenPattern.FindString(text)
+}
+
+func (slackVerifier) Verify(ctx context.Context, client *http.Client, endpoint string, c SecretCandidate) (int, SecretVerification) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/api/auth.test", nil)
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Secret)
+	code, body, err := doVerifyRequest(client, req)
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		Team  string `json:"team"`
+		User  string `json:"user"`
+	}
+	if code != http.StatusOK || json.Unmarshal(body, &resp) != nil {
+		return code, SecretVerification{Status: VerificationUnknown, Reason: fmt.Sprintf("HTTP %d", code)}
+	}
+	switch {
+	case resp.OK:
+		return code, SecretVerification{Status: VerificationVerified, Identity: strings.Trim(resp.Team+"/"+resp.User, "/")}
+	case resp.Error == "invalid_auth" || resp.Error == "token_revoked" || resp.Error == "account_inactive" || resp.Error == "not_authed":
+		return code, SecretVerification{Status: VerificationInvalid, Reason: resp.Error}
+	}
+	return code, SecretVerification{Status: VerificationUnknown, Reason: resp.Error}
+}
+
+// awsVerifier calls STS GetCallerIdentity, which needs no permissions. The
+// secret access key is looked for next to the key ID in the same file.
+type awsVerifier struct{}
+
+var (
+	awsKeyIDPattern  = regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)
+	awsSecretPattern = regexp.MustCompile(`(?i)(secret_access_key|secretaccesskey|aws_secret|secret_key)["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`)
+	awsArnPattern    = regexp.MustCompile(`<Arn>([^<]+)</Arn>`)
+	awsErrorPattern  = regexp.MustCompile(`<Code>([^<]+)</Code>`)
+)
+
+func (awsVerifier) Provider() string        { return "aws" }
+func (awsVerifier) DefaultEndpoint() string { return "https://sts.amazonaws.com" }
+
+// Extract finds long-term key IDs only: temporary (ASIA) keys need a
+// session token as well
+func (awsVerifier) Extract(text string) string {
+	return awsKeyIDPattern.FindString(text)
+}
+
+func (awsVerifier) Verify(ctx context.Context, client *http.Client, endpoint string, c SecretCandidate) (int, SecretVerification) {
+	m := awsSecretPattern.FindStringSubmatch(c.Context)
+	if m == nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: "no secret access key found with the key ID"}
+	}
+
+	body := "Action=GetCallerIdentity&Version=2011-06-15"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", strings.NewReader(body))
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, body, c.Secret, m[2], "us-east-1", "sts", time.Now().UTC())
+
+	code, resp, err := doVerifyRequest(client, req)
+	if err != nil {
+		return 0, SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	}
+	if code == http.StatusOK {
+		result := SecretVerification{Status: VerificationVerified}
+		if arn := awsArnPattern.FindSubmatch(resp); arn != nil {
+			result.Identity = string(arn[1])
+		}
+		return code, result
+	}
+	if e := awsErrorPattern.FindSubmatch(resp); e != nil {
+		switch reason := string(e[1]); reason {
+		case "InvalidClientTokenId", "SignatureDoesNotMatch":
+			return code, SecretVerification{Status: VerificationInvalid, Reason: reason}
+		default:
+			return code, SecretVerification{Status: VerificationUnknown, Reason: reason}
+		}
+	}
+	return code, SecretVerification{Status: VerificationUnknown, Reason: fmt.Sprintf("HTTP %d", code)}
+}
+
+// signAWSRequest adds a Signature Version 4 Authorization header
+func signAWSRequest(req *http.Request, body, keyID, secret, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	bodyHash := sha256.Sum256([]byte(body))
+	canonical := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		"content-type:" + req.Header.Get("Content-Type"),
+		"host:" + req.URL.Host,
+		"x-amz-date:" + amzDate,
+		"",
+		"content-type;host;x-amz-date",
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := []byte("AWS4" + secret)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=content-type;host;x-amz-date, Signature=%s",
+		keyID, scope, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// doVerifyRequest sends a verification request and reads up to 64KB of the
+// response
+func doVerifyRequest(client *http.Client, req *http.Request) (int, []byte, error) {
+	req.Header.Set("User-Agent", "zero-secret-verifier/"+Version)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, body, err
+}
+
+// jsonField returns a function reading a top-level string field
+func jsonField(name string) func([]byte) string {
+	return func(body []byte) string {
+		var m map[string]interface{}
+		if json.Unmarshal(body, &m) != nil {
+			return ""
+		}
+		s, _ := m[name].(string)
+		return s
+	}
+}
+
+// verificationAudit is one line of the verification audit log. It records
+// what was sent where, never the secret itself.
+type verificationAudit struct {
+	Time       string `json:"time"`
+	Repo       string `json:"repo,omitempty"`
+	Provider   string `json:"provider"`
+	Type       string `json:"type"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Secret     string `json:"secret"`      // Redacted
+	SecretHash string `json:"secret_hash"` // sha256 prefix, to correlate repeated checks
+	Endpoint   string `json:"endpoint"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SecretsVerifier checks secret findings against their providers
+type SecretsVerifier struct {
+	config   VerificationConfig
+	repoPath string
+	repo     string
+	client   *http.Client
+	auditLog string
+
+	mu       sync.Mutex
+	limiters map[string]*liveapi.RateLimiter
+}
+
+// NewSecretsVerifier creates a verifier for the findings of one scan. The
+// audit log defaults to secrets-verification.jsonl in outputDir.
+func NewSecretsVerifier(config VerificationConfig, opts *scanner.ScanOptions) *SecretsVerifier {
+	if config.MaxVerifications <= 0 {
+		config.MaxVerifications = 50
+	}
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = 2
+	}
+	if config.TimeoutSeconds <= 0 {
+		config.TimeoutSeconds = 10
+	}
+	v := &SecretsVerifier{
+		config:   config,
+		repoPath: opts.RepoPath,
+		client:   &http.Client{Timeout: time.Duration(config.TimeoutSeconds) * time.Second},
+		auditLog: config.AuditLog,
+		limiters: make(map[string]*liveapi.RateLimiter),
+	}
+	if v.auditLog == "" && opts.OutputDir != "" {
+		v.auditLog = filepath.Join(opts.OutputDir, "secrets-verification.jsonl")
+	}
+	if opts.RepoMetadata != nil && opts.RepoMetadata.GitHubOrg != "" {
+		v.repo = opts.RepoMetadata.GitHubOrg + "/" + opts.RepoMetadata.GitHubRepo
+	}
+	return v
+}
+
+// VerifyFindings checks each finding with a verifier for its type. A secret
+// found several times is checked once. Verified secrets become critical.
+func (v *SecretsVerifier) VerifyFindings(ctx context.Context, findings []SecretFinding) ([]SecretFinding, *VerificationSummary) {
+	summary := &VerificationSummary{}
+	if liveapi.Offline() {
+		summary.Skipped = len(findings)
+		summary.Error = "offline: verification needs network access"
+		return findings, summary
+	}
+
+	checked := make(map[string]SecretVerification)
+	fileCache := make(map[string]string)
+	for i := range findings {
+		f := &findings[i]
+		verifier := GetSecretVerifier(f.Type)
+		if verifier == nil || !v.providerEnabled(verifier.Provider()) {
+			summary.Skipped++
+			continue
+		}
+
+		content := v.readFile(f.File, fileCache)
+		secret := ""
+		if f.Evidence != nil {
+			secret = verifier.Extract(f.Evidence.MatchedText)
+		}
+		if secret == "" {
+			secret = verifier.Extract(lineAt(content, f.Line))
+		}
+		if secret == "" {
+			summary.Skipped++
+			continue
+		}
+
+		key := verifier.Provider() + ":" + secret
+		result, ok := checked[key]
+		if !ok {
+			if len(checked) >= v.config.MaxVerifications {
+				summary.Skipped++
+				continue
+			}
+			result = v.verify(ctx, verifier, f, SecretCandidate{Type: f.Type, Secret: secret, Context: content})
+			checked[key] = result
+			summary.Checked++
+		}
+
+		f.Verification = &result
+		switch result.Status {
+		case VerificationVerified:
+			f.Verified = true
+			f.Severity = "critical"
+			summary.Verified++
+		case VerificationInvalid:
+			summary.Invalid++
+		default:
+			summary.Unknown++
+		}
+	}
+	return findings, summary
+}
+
+// verify makes one rate-limited, audited verification call
+func (v *SecretsVerifier) verify(ctx context.Context, verifier SecretVerifier, f *SecretFinding, c SecretCandidate) SecretVerification {
+	provider := verifier.Provider()
+	endpoint := verifier.DefaultEndpoint()
+	if e := v.config.Endpoints[provider]; e != "" {
+		endpoint = e
+	}
+
+	var code int
+	var result SecretVerification
+	if err := v.limiter(provider).Wait(ctx); err != nil {
+		result = SecretVerification{Status: VerificationUnknown, Reason: err.Error()}
+	} else {
+		code, result = verifier.Verify(ctx, v.client, endpoint, c)
+	}
+	result.Provider = provider
+	result.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+
+	sum := sha256.Sum256([]byte(c.Secret))
+	v.audit(verificationAudit{
+		Time:       result.CheckedAt,
+		Repo:       v.repo,
+		Provider:   provider,
+		Type:       f.Type,
+		File:       f.File,
+		Line:       f.Line,
+		Secret:     redactHistorySecret(c.Secret),
+		SecretHash: hex.EncodeToString(sum[:])[:16],
+		Endpoint:   endpoint,
+		HTTPStatus: code,
+		Status:     result.Status,
+		Reason:     result.Reason,
+	})
+	return result
+}
+
+func (v *SecretsVerifier) providerEnabled(provider string) bool {
+	if len(v.config.Providers) == 0 {
+		return true
+	}
+	for _, p := range v.config.Providers {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// limiter returns the rate limiter for a provider
+func (v *SecretsVerifier) limiter(provider string) *liveapi.RateLimiter {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	l, ok := v.limiters[provider]
+	if !ok {
+		l = liveapi.NewRateLimiter(v.config.RequestsPerSecond, time.Second)
+		v.limiters[provider] = l
+	}
+	return l
+}
+
+// audit appends an entry to the audit log. Failing to write it is not
+// fatal to the scan.
+func (v *SecretsVerifier) audit(entry verificationAudit) {
+	if v.auditLog == "" {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(v.auditLog), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(v.auditLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// readFile returns the content of a finding's file, which is empty for
+// files no longer in the tree (git history findings)
+func (v *SecretsVerifier) readFile(file string, cache map[string]string) string {
+	if content, ok := cache[file]; ok {
+		return content
+	}
+	content := ""
+	if !filepath.IsAbs(file) && !strings.Contains(file, "..") {
+		if info, err := os.Stat(filepath.Join(v.repoPath, file)); err == nil && info.Size() <= maxPatternFileSize {
+			data, _ := os.ReadFile(filepath.Join(v.repoPath, file))
+			content = string(data)
+		}
+	}
+	cache[file] = content
+	return content
+}
+
+// lineAt returns the 1-based line n of content
+func lineAt(content string, n int) string {
+	if n < 1 {
+		return ""
+	}
+	lines := strings.SplitN(content, "\n", n+1)
+	if len(lines) < n {
+		return ""
+	}
+	return lines[n-1]
+}
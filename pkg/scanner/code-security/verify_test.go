@@ -0,0 +1,152 @@
+package codesecurity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/crashappsec/zero/pkg/core/findings"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+func TestSecretsVerifier_VerifyFindings(t *testing.T) {
+	liveToken := "ghp_" + strings.Repeat("L1ve", 9)
+	revokedToken := "ghp_" + strings.Repeat("Dead", 9)
+	stripeKey := "rk_live_" + strings.Repeat("x", 24)
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/user" && r.Header.Get("Authorization") == "token "+liveToken:
+			w.Write([]byte(`{"login": "deploy-bot"}`))
+		case r.URL.Path == "/v1/balance":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	repo := t.TempDir()
+	writeSecretFile(t, repo, "deploy.sh", "export GH_TOKEN="+revokedToken+"\n")
+	out := t.TempDir()
+	cfg := DefaultConfig().Secrets.Verification
+	cfg.Enabled = true
+	cfg.RequestsPerSecond = 100
+	cfg.Endpoints = map[string]string{"github": srv.URL, "stripe": srv.URL}
+
+	input := []SecretFinding{
+		{Type: "github_token", Severity: "high", File: "config.yaml", Line: 3, Evidence: &findings.Evidence{MatchedText: "token: " + liveToken}},
+		{Type: "github_token", Severity: "high", File: "ci.yaml", Line: 9, Evidence: &findings.Evidence{MatchedText: liveToken}},
+		// No evidence: the secret is read from the file
+		{Type: "github_token", Severity: "high", File: "deploy.sh", Line: 1, Snippet: "export GH_TOKEN=ghp_Dead****"},
+		{Type: "stripe_key", Severity: "high", File: "billing.py", Line: 1, Evidence: &findings.Evidence{MatchedText: stripeKey}},
+		{Type: "high_entropy_string", Severity: "medium", File: "a.txt", Line: 1},
+	}
+	verifier := NewSecretsVerifier(cfg, &scanner.ScanOptions{RepoPath: repo, OutputDir: out})
+	got, summary := verifier.VerifyFindings(context.Background(), input)
+
+	if !got[0].Verified || got[0].Severity != "critical" || got[0].Verification.Identity != "deploy-bot" || got[0].Verification.Provider != "github" {
+		t.Errorf("live token = %+v, %+v", got[0], got[0].Verification)
+	}
+	if !got[1].Verified {
+		t.Errorf("repeated live token = %+v", got[1])
+	}
+	if got[2].Verified || got[2].Severity != "high" || got[2].Verification == nil || got[2].Verification.Status != VerificationInvalid {
+		t.Errorf("revoked token = %+v", got[2])
+	}
+	if !got[3].Verified {
+		t.Errorf("restricted stripe key = %+v, want verified", got[3].Verification)
+	}
+	if got[4].Verification != nil {
+		t.Errorf("entropy finding was verified: %+v", got[4].Verification)
+	}
+	if summary.Checked != 3 || summary.Verified != 3 || summary.Invalid != 1 || summary.Skipped != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("made %d requests, want 3 (one per distinct secret)", n)
+	}
+
+	audit, err := os.ReadFile(filepath.Join(out, "secrets-verification.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(audit), "\n"); lines != 3 {
+		t.Errorf("audit log has %d entries, want 3", lines)
+	}
+	for _, secret := range []string{liveToken, revokedToken, stripeKey} {
+		if strings.Contains(string(audit), secret) {
+			t.Errorf("audit log contains a secret:\n%s", audit)
+		}
+	}
+}
+
+func TestSecretsVerifier_MaxVerifications(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cfg := VerificationConfig{Enabled: true, MaxVerifications: 1, RequestsPerSecond: 100, Endpoints: map[string]string{"npm": srv.URL}}
+	input := []SecretFinding{
+		{Type: "npm_token", Evidence: &findings.Evidence{MatchedText: "npm_" + strings.Repeat("a", 36)}},
+		{Type: "npm_token", Evidence: &findings.Evidence{MatchedText: "npm_" + strings.Repeat("b", 36)}},
+	}
+	_, summary := NewSecretsVerifier(cfg, &scanner.ScanOptions{RepoPath: t.TempDir()}).VerifyFindings(context.Background(), input)
+	if summary.Checked != 1 || summary.Invalid != 1 || summary.Skipped != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestSecretsVerifier_Offline(t *testing.T) {
+	liveapi.SetOffline(true)
+	defer liveapi.SetOffline(false)
+
+	cfg := VerificationConfig{Enabled: true, Endpoints: map[string]string{"github": "http://127.0.0.1:1"}}
+	input := []SecretFinding{{Type: "github_token", Evidence: &findings.Evidence{MatchedText: "ghp_" + strings.Repeat("a", 36)}}}
+	got, summary := NewSecretsVerifier(cfg, &scanner.ScanOptions{RepoPath: t.TempDir()}).VerifyFindings(context.Background(), input)
+	if got[0].Verification != nil || summary.Checked != 0 || summary.Error == "" {
+		t.Errorf("offline verification = %+v, %+v", got[0].Verification, summary)
+	}
+}
+
+func TestAWSVerifier(t *testing.T) {
+	keyID := "AKIA" + "Q3EGRZ7NVHBPLW4K"
+	secret := strings.Repeat("wJalrXUtnFEMI/K7MDENG+bPxRfiCY", 2)[:40]
+
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn:aws:iam::123456789012:user/ci</Arn></GetCallerIdentityResult></GetCallerIdentityResponse>`))
+	}))
+	defer srv.Close()
+
+	v := GetSecretVerifier("aws_access_key")
+	if got := v.Extract("key = " + keyID); got != keyID {
+		t.Fatalf("Extract() = %q", got)
+	}
+
+	c := SecretCandidate{Type: "aws_access_key", Secret: keyID, Context: "aws_access_key_id = " + keyID + "\naws_secret_access_key = " + secret + "\n"}
+	_, result := v.Verify(context.Background(), http.DefaultClient, srv.URL, c)
+	if result.Status != VerificationVerified || result.Identity != "arn:aws:iam::123456789012:user/ci" {
+		t.Errorf("Verify() = %+v", result)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+keyID+"/") || !strings.Contains(auth, "/us-east-1/sts/aws4_request") {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	// Without the secret access key nothing is sent
+	auth = ""
+	_, result = v.Verify(context.Background(), http.DefaultClient, srv.URL, SecretCandidate{Secret: keyID, Context: "id = " + keyID})
+	if result.Status != VerificationUnknown || auth != "" {
+		t.Errorf("Verify() without secret = %+v", result)
+	}
+}
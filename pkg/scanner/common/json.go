@@ -93,6 +93,18 @@ func GetArray(m map[string]interface{}, key string) []interface{} {
 	return nil
 }
 
+// GetStringArray safely extracts an array of strings from a map, skipping
+// non-string elements
+func GetStringArray(m map[string]interface{}, key string) []string {
+	var result []string
+	for _, v := range GetArray(m, key) {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
 // GetMap safely extracts a map from a map
 func GetMap(m map[string]interface{}, key string) map[string]interface{} {
 	if v, ok := m[key].(map[string]interface{}); ok {
@@ -40,6 +40,10 @@ func (s *DevXScanner) Dependencies() []string {
 	return []string{"technology-identification"}
 }
 
+func (s *DevXScanner) Version() string {
+	return Version
+}
+
 func (s *DevXScanner) EstimateDuration(fileCount int) time.Duration {
 	est := 5 + fileCount/1000
 	return time.Duration(est) * time.Second
@@ -56,6 +60,8 @@ func (s *DevXScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*scan
 		Findings:    Findings{},
 	}
 
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -64,7 +70,12 @@ func (s *DevXScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*scan
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runOnboarding(ctx, opts, cfg.Onboarding)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "onboarding", func(ctx context.Context) (*OnboardingSummary, *OnboardingFindings) {
+				return s.runOnboarding(ctx, opts, cfg.Onboarding)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "onboarding")
 			result.Summary.Onboarding = summary
@@ -77,7 +88,12 @@ func (s *DevXScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*scan
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runSprawl(ctx, opts, cfg.Sprawl)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "sprawl", func(ctx context.Context) (*SprawlSummary, *SprawlFindings) {
+				return s.runSprawl(ctx, opts, cfg.Sprawl)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "sprawl")
 			result.Summary.Sprawl = summary
@@ -90,7 +106,12 @@ func (s *DevXScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*scan
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runWorkflow(ctx, opts, cfg.Workflow)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "workflow", func(ctx context.Context) (*WorkflowSummary, *WorkflowFindings) {
+				return s.runWorkflow(ctx, opts, cfg.Workflow)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "workflow")
 			result.Summary.Workflow = summary
@@ -108,6 +129,7 @@ func (s *DevXScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*scan
 	_ = scanResult.SetMetadata(map[string]interface{}{
 		"features_run": result.FeaturesRun,
 	})
+	tracker.Apply(scanResult)
 
 	if opts.OutputDir != "" {
 		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
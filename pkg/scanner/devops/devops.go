@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -21,6 +20,8 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 
+	"github.com/crashappsec/zero/pkg/core/forge"
+	"github.com/crashappsec/zero/pkg/core/liveapi"
 	"github.com/crashappsec/zero/pkg/scanner"
 	"github.com/crashappsec/zero/pkg/scanner/common"
 )
@@ -49,6 +50,14 @@ func (s *DevOpsScanner) Dependencies() []string {
 	return nil
 }
 
+func (s *DevOpsScanner) Version() string {
+	return Version
+}
+
+func (s *DevOpsScanner) Tools() []string {
+	return []string{"trivy", "checkov"}
+}
+
 func (s *DevOpsScanner) EstimateDuration(fileCount int) time.Duration {
 	return 30 * time.Second
 }
@@ -64,6 +73,8 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		Findings:    Findings{},
 	}
 
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -72,7 +83,12 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runIaC(ctx, opts, cfg.IaC)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "iac", func(ctx context.Context) (*IaCSummary, []IaCFinding) {
+				return s.runIaC(ctx, opts, cfg.IaC)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "iac")
 			result.Summary.IaC = summary
@@ -85,7 +101,12 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runContainers(ctx, opts, cfg.Containers)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "containers", func(ctx context.Context) (*ContainersSummary, []ContainerFinding) {
+				return s.runContainers(ctx, opts, cfg.Containers)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "containers")
 			result.Summary.Containers = summary
@@ -98,7 +119,12 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runGitHubActions(ctx, opts, cfg.GitHubActions)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "github_actions", func(ctx context.Context) (*GitHubActionsSummary, []GitHubActionsFinding) {
+				return s.runGitHubActions(ctx, opts, cfg.GitHubActions)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "github_actions")
 			result.Summary.GitHubActions = summary
@@ -111,7 +137,12 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, metrics := s.runDORA(ctx, opts, cfg.DORA)
+			summary, metrics, ok := scanner.RunFeatureFindings(tracker, "dora", func(ctx context.Context) (*DORASummary, *DORAMetrics) {
+				return s.runDORA(ctx, opts, cfg.DORA)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "dora")
 			result.Summary.DORA = summary
@@ -124,7 +155,12 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			summary, findings := s.runGit(ctx, opts, cfg.Git)
+			summary, findings, ok := scanner.RunFeatureFindings(tracker, "git", func(ctx context.Context) (*GitSummary, *GitFindings) {
+				return s.runGit(ctx, opts, cfg.Git)
+			})
+			if !ok {
+				return
+			}
 			mu.Lock()
 			result.FeaturesRun = append(result.FeaturesRun, "git")
 			result.Summary.Git = summary
@@ -142,6 +178,7 @@ func (s *DevOpsScanner) Run(ctx context.Context, opts *scanner.ScanOptions) (*sc
 	_ = scanResult.SetMetadata(map[string]interface{}{
 		"features_run": result.FeaturesRun,
 	})
+	tracker.Apply(scanResult)
 
 	if opts.OutputDir != "" {
 		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
@@ -206,7 +243,7 @@ func (s *DevOpsScanner) runIaC(ctx context.Context, opts *scanner.ScanOptions, c
 		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
-		result, err = common.RunCommand(ctx, "checkov",
+		args := []string{
 			"--directory", opts.RepoPath,
 			"--output", "json",
 			"--quiet",
@@ -214,7 +251,11 @@ func (s *DevOpsScanner) runIaC(ctx context.Context, opts *scanner.ScanOptions, c
 			"--skip-path", "node_modules",
 			"--skip-path", "vendor",
 			"--skip-path", ".git",
-		)
+		}
+		if liveapi.Offline() {
+			args = append(args, "--skip-download")
+		}
+		result, err = common.RunCommand(ctx, "checkov", args...)
 
 		if err != nil && cfg.FallbackTool && common.ToolExists("trivy") {
 			useTrivy = true
@@ -227,15 +268,19 @@ func (s *DevOpsScanner) runIaC(ctx context.Context, opts *scanner.ScanOptions, c
 		ctx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
-		result, err = common.RunCommand(ctx, "trivy",
+		args := []string{
 			"config",
 			"--format", "json",
 			"--severity", "CRITICAL,HIGH,MEDIUM,LOW",
 			"--skip-dirs", "node_modules",
 			"--skip-dirs", "vendor",
 			"--skip-dirs", ".git",
-			opts.RepoPath,
-		)
+		}
+		if liveapi.Offline() {
+			// Use the checks bundled with (or cached by) trivy
+			args = append(args, "--skip-check-update")
+		}
+		result, err = common.RunCommand(ctx, "trivy", append(args, opts.RepoPath)...)
 	}
 
 	if err != nil || result == nil {
@@ -1170,7 +1215,7 @@ func (s *DevOpsScanner) runDORA(ctx context.Context, opts *scanner.ScanOptions,
 
 	// Fetch PR-level metrics if enabled (LinearB alignment)
 	if cfg.IncludePRMetrics {
-		prMetrics, err := fetchPRMetrics(opts.RepoPath, cfg, since)
+		prMetrics, err := fetchPRMetrics(ctx, opts.RepoPath, cfg, since)
 		if err == nil && prMetrics != nil {
 			metrics.PRMetrics = prMetrics
 			// Copy to summary for easy access
@@ -1406,12 +1451,17 @@ func calculateOverallClass(m *DORAMetrics) string {
 // PR-LEVEL METRICS (LinearB alignment)
 // ============================================================================
 
-// fetchPRMetrics fetches PR cycle time data from GitHub API
-func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetrics, error) {
-	// Extract owner/repo from remote URL
-	owner, repo := extractOwnerRepo(repoPath)
-	if owner == "" || repo == "" {
-		return nil, fmt.Errorf("could not determine repository owner/name")
+// fetchPRMetrics fetches PR cycle time data from the forge hosting the origin
+// remote (GitHub, GitLab or Gitea)
+func fetchPRMetrics(ctx context.Context, repoPath string, cfg DORAConfig, since time.Time) (*PRMetrics, error) {
+	remote := remoteURL(repoPath)
+	if remote == "" {
+		return nil, fmt.Errorf("could not determine repository remote")
+	}
+
+	f, fullName, err := forge.ForRemote(remote)
+	if err != nil {
+		return nil, err
 	}
 
 	maxPRs := cfg.MaxPRs
@@ -1419,59 +1469,27 @@ func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetric
 		maxPRs = 100
 	}
 
-	// Fetch merged PRs with timing data using gh CLI
-	args := []string{
-		"pr", "list",
-		"--repo", fmt.Sprintf("%s/%s", owner, repo),
-		"--state", "merged",
-		"--json", "number,title,author,createdAt,mergedAt,additions,deletions,reviews",
-		"--limit", fmt.Sprintf("%d", maxPRs),
-	}
-
-	cmd := exec.Command("gh", args...)
-	out, err := cmd.Output()
+	// Fetch merged PRs with timing data
+	prs, err := f.ListMergedPullRequests(ctx, fullName, forge.PullRequestOptions{
+		Limit:     maxPRs,
+		Since:     since,
+		WithStats: true,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetching PRs: %w", err)
 	}
 
-	var ghPRs []struct {
-		Number    int    `json:"number"`
-		Title     string `json:"title"`
-		Author    struct {
-			Login string `json:"login"`
-		} `json:"author"`
-		CreatedAt string `json:"createdAt"`
-		MergedAt  string `json:"mergedAt"`
-		Additions int    `json:"additions"`
-		Deletions int    `json:"deletions"`
-		Reviews   []struct {
-			Author struct {
-				Login string `json:"login"`
-			} `json:"author"`
-			State       string `json:"state"`
-			SubmittedAt string `json:"submittedAt"`
-		} `json:"reviews"`
-	}
-
-	if err := json.Unmarshal(out, &ghPRs); err != nil {
-		return nil, fmt.Errorf("parsing PRs: %w", err)
-	}
-
 	metrics := &PRMetrics{
-		PRs: make([]PRCycleTime, 0, len(ghPRs)),
+		PRs: make([]PRCycleTime, 0, len(prs)),
 	}
 
 	var totalPickup, totalReview, totalMerge, totalCycle float64
 	var totalSize int
 	validPRs := 0
 
-	for _, pr := range ghPRs {
-		createdAt, err := time.Parse(time.RFC3339, pr.CreatedAt)
-		if err != nil {
-			continue
-		}
-		mergedAt, err := time.Parse(time.RFC3339, pr.MergedAt)
-		if err != nil {
+	for _, pr := range prs {
+		createdAt, mergedAt := pr.CreatedAt, pr.MergedAt
+		if createdAt.IsZero() || mergedAt.IsZero() {
 			continue
 		}
 
@@ -1483,7 +1501,7 @@ func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetric
 		prCycle := PRCycleTime{
 			Number:    pr.Number,
 			Title:     pr.Title,
-			Author:    pr.Author.Login,
+			Author:    pr.Author,
 			CreatedAt: createdAt,
 			MergedAt:  mergedAt,
 			Additions: pr.Additions,
@@ -1495,8 +1513,8 @@ func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetric
 		var firstReviewTime, approvalTime time.Time
 
 		for _, review := range pr.Reviews {
-			submittedAt, err := time.Parse(time.RFC3339, review.SubmittedAt)
-			if err != nil {
+			submittedAt := review.SubmittedAt
+			if submittedAt.IsZero() {
 				continue
 			}
 
@@ -1506,7 +1524,7 @@ func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetric
 			}
 
 			// Last approval time
-			if review.State == "APPROVED" {
+			if review.State == forge.ReviewApproved {
 				if approvalTime.IsZero() || submittedAt.After(approvalTime) {
 					approvalTime = submittedAt
 				}
@@ -1574,16 +1592,16 @@ func fetchPRMetrics(repoPath string, cfg DORAConfig, since time.Time) (*PRMetric
 	return metrics, nil
 }
 
-// extractOwnerRepo extracts owner and repo name from git remote
-func extractOwnerRepo(repoPath string) (string, string) {
+// remoteURL returns the origin remote URL, falling back to the first remote
+func remoteURL(repoPath string) string {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
-		return "", ""
+		return ""
 	}
 
 	remotes, err := repo.Remotes()
 	if err != nil || len(remotes) == 0 {
-		return "", ""
+		return ""
 	}
 
 	// Get origin remote URL
@@ -1591,7 +1609,7 @@ func extractOwnerRepo(repoPath string) (string, string) {
 		if remote.Config().Name == "origin" {
 			urls := remote.Config().URLs
 			if len(urls) > 0 {
-				return parseGitHubURL(urls[0])
+				return urls[0]
 			}
 		}
 	}
@@ -1599,10 +1617,10 @@ func extractOwnerRepo(repoPath string) (string, string) {
 	// Fallback to first remote
 	urls := remotes[0].Config().URLs
 	if len(urls) > 0 {
-		return parseGitHubURL(urls[0])
+		return urls[0]
 	}
 
-	return "", ""
+	return ""
 }
 
 // parseGitHubURL extracts owner/repo from GitHub URL
@@ -0,0 +1,282 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Feature run statuses recorded in ScanResult.FeatureStatus
+const (
+	FeatureComplete  = "complete"
+	FeatureTimeout   = "timeout"
+	FeatureCancelled = "cancelled"
+	FeatureFailed    = "failed"
+	FeatureSkipped   = "skipped" // Not run because a feature it depends on did not complete
+)
+
+// maxFeatureReserve caps the time kept back from the scanner deadline so a
+// super scanner can still write what its features produced
+const maxFeatureReserve = 5 * time.Second
+
+// FeatureStatus records how one feature of a super scanner finished
+type FeatureStatus struct {
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// FeatureTracker runs the features of a super scanner under per-feature
+// deadlines and records their status. A feature that overruns its deadline
+// is abandoned and marked timed out; the others keep their results, so a
+// single slow feature no longer loses the whole scanner.
+//
+// Each feature's deadline is the smaller of its "timeout_seconds" feature
+// config and the scanner deadline minus a short reserve for writing output.
+type FeatureTracker struct {
+	ctx      context.Context
+	timeouts map[string]time.Duration
+
+	mu       sync.Mutex
+	statuses map[string]FeatureStatus
+}
+
+// NewFeatureTracker creates a tracker for a scanner run
+func NewFeatureTracker(ctx context.Context, opts *ScanOptions) *FeatureTracker {
+	t := &FeatureTracker{
+		ctx:      ctx,
+		timeouts: make(map[string]time.Duration),
+		statuses: make(map[string]FeatureStatus),
+	}
+	if opts != nil {
+		for name, raw := range opts.FeatureConfig {
+			cfg, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var seconds float64
+			switch v := cfg["timeout_seconds"].(type) {
+			case float64:
+				seconds = v
+			case int:
+				seconds = float64(v)
+			}
+			if seconds > 0 {
+				t.timeouts[name] = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	return t
+}
+
+// RunFeature runs fn as the named feature. It returns fn's result and true
+// when fn returned, even if its deadline passed while it was finishing (the
+// feature is then still marked timed out, since its result may be
+// incomplete). It returns false when the feature was abandoned at its
+// deadline. fn must honour the context it is given.
+func RunFeature[T any](t *FeatureTracker, name string, fn func(ctx context.Context) T) (T, bool) {
+	var zero T
+	if t.notStarted(name) {
+		return zero, false
+	}
+	ctx, cancel := t.featureContext(name)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan T, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case v := <-done:
+		if ctx.Err() != nil {
+			t.record(name, t.interrupted(), time.Since(start), ctx.Err())
+		} else {
+			t.record(name, FeatureComplete, time.Since(start), nil)
+		}
+		return v, true
+	case <-ctx.Done():
+		t.record(name, t.interrupted(), time.Since(start), ctx.Err())
+		return zero, false
+	}
+}
+
+// RunFeatureFindings is RunFeature for the common feature shape that
+// returns a summary and findings
+func RunFeatureFindings[S, F any](t *FeatureTracker, name string, fn func(ctx context.Context) (S, F)) (S, F, bool) {
+	type output struct {
+		summary  S
+		findings F
+	}
+	out, ok := RunFeature(t, name, func(ctx context.Context) output {
+		summary, findings := fn(ctx)
+		return output{summary, findings}
+	})
+	return out.summary, out.findings, ok
+}
+
+// Run runs fn as the named feature and waits for it to return, however long
+// that takes. It is for features that write into shared scanner state and so
+// cannot be abandoned; fn should stop early once its context is done.
+// A feature is not started at all once the scanner's context is done.
+func (t *FeatureTracker) Run(name string, fn func(ctx context.Context)) {
+	if t.notStarted(name) {
+		return
+	}
+	ctx, cancel := t.featureContext(name)
+	defer cancel()
+
+	start := time.Now()
+	fn(ctx)
+	if ctx.Err() != nil {
+		t.record(name, t.interrupted(), time.Since(start), ctx.Err())
+	} else {
+		t.record(name, FeatureComplete, time.Since(start), nil)
+	}
+}
+
+// notStarted records a feature as interrupted without running it when the
+// scanner's context is already done
+func (t *FeatureTracker) notStarted(name string) bool {
+	if err := t.ctx.Err(); err != nil {
+		t.record(name, t.interrupted(), 0, err)
+		return true
+	}
+	return false
+}
+
+// Fail marks a feature as failed
+func (t *FeatureTracker) Fail(name string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st := t.statuses[name]
+	st.Status = FeatureFailed
+	if err != nil {
+		st.Error = err.Error()
+	}
+	t.statuses[name] = st
+}
+
+// Skip marks a feature as not run because a feature it depends on did not
+// complete
+func (t *FeatureTracker) Skip(name, dependency string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.statuses[name] = FeatureStatus{
+		Status: FeatureSkipped,
+		Error:  fmt.Sprintf("%s did not complete", dependency),
+	}
+}
+
+// Completed reports whether the named feature ran to completion
+func (t *FeatureTracker) Completed(name string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statuses[name].Status == FeatureComplete
+}
+
+// Partial reports whether any tracked feature did not complete
+func (t *FeatureTracker) Partial() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, st := range t.statuses {
+		if st.Status != FeatureComplete {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses returns a copy of the recorded feature statuses
+func (t *FeatureTracker) Statuses() map[string]FeatureStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make(map[string]FeatureStatus, len(t.statuses))
+	for name, st := range t.statuses {
+		out[name] = st
+	}
+	return out
+}
+
+// Apply records the feature statuses and partial marker on a scan result
+func (t *FeatureTracker) Apply(result *ScanResult) {
+	result.FeatureStatus = t.Statuses()
+	result.Partial = t.Partial()
+}
+
+func (t *FeatureTracker) featureContext(name string) (context.Context, context.CancelFunc) {
+	timeout := t.timeouts[name]
+	if deadline, ok := t.ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		reserve := remaining / 10
+		if reserve > maxFeatureReserve {
+			reserve = maxFeatureReserve
+		}
+		if limit := remaining - reserve; timeout == 0 || limit < timeout {
+			timeout = limit
+		}
+	}
+	if timeout == 0 {
+		return context.WithCancel(t.ctx)
+	}
+	return context.WithTimeout(t.ctx, timeout)
+}
+
+// interrupted returns the status for a feature whose context ended: a
+// cancelled scan is "cancelled", anything else ran out of time
+func (t *FeatureTracker) interrupted() string {
+	if errors.Is(t.ctx.Err(), context.Canceled) {
+		return FeatureCancelled
+	}
+	return FeatureTimeout
+}
+
+func (t *FeatureTracker) record(name, status string, d time.Duration, err error) {
+	st := FeatureStatus{Status: status, DurationSeconds: d.Round(time.Millisecond).Seconds()}
+	if err != nil && status != FeatureComplete {
+		st.Error = err.Error()
+	}
+	t.mu.Lock()
+	t.statuses[name] = st
+	t.mu.Unlock()
+}
+
+// IncompleteFeatures returns the sorted names of features in statuses that
+// did not complete
+func IncompleteFeatures(statuses map[string]FeatureStatus) []string {
+	var names []string
+	for name, st := range statuses {
+		if st.Status != FeatureComplete {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// partialSummary describes the incomplete features of a partial result for
+// progress output, e.g. "partial: reachability timed out"
+func partialSummary(statuses map[string]FeatureStatus) string {
+	var parts []string
+	for _, name := range IncompleteFeatures(statuses) {
+		switch statuses[name].Status {
+		case FeatureTimeout:
+			parts = append(parts, name+" timed out")
+		case FeatureCancelled:
+			parts = append(parts, name+" cancelled")
+		case FeatureSkipped:
+			parts = append(parts, name+" skipped")
+		default:
+			parts = append(parts, name+" failed")
+		}
+	}
+	if len(parts) == 0 {
+		return "partial"
+	}
+	return "partial: " + strings.Join(parts, ", ")
+}
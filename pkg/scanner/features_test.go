@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFeatureTracker_PerFeatureTimeout(t *testing.T) {
+	tracker := NewFeatureTracker(context.Background(), &ScanOptions{
+		FeatureConfig: map[string]interface{}{
+			"slow": map[string]interface{}{"timeout_seconds": 0.05},
+		},
+	})
+
+	fast, ok := RunFeature(tracker, "fast", func(ctx context.Context) string { return "done" })
+	if !ok || fast != "done" {
+		t.Errorf("fast feature = %q, %v", fast, ok)
+	}
+
+	// A feature that ignores its context is abandoned at the deadline
+	release := make(chan struct{})
+	defer close(release)
+	start := time.Now()
+	if _, ok := RunFeature(tracker, "slow", func(ctx context.Context) string {
+		<-release
+		return "late"
+	}); ok {
+		t.Error("slow feature should be abandoned")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("abandoning took %v", elapsed)
+	}
+
+	statuses := tracker.Statuses()
+	if statuses["fast"].Status != FeatureComplete || statuses["slow"].Status != FeatureTimeout {
+		t.Errorf("statuses = %+v", statuses)
+	}
+	if !tracker.Partial() {
+		t.Error("tracker with a timed-out feature should be partial")
+	}
+	if got := IncompleteFeatures(statuses); len(got) != 1 || got[0] != "slow" {
+		t.Errorf("IncompleteFeatures() = %v", got)
+	}
+}
+
+func TestFeatureTracker_ReservesTimeBeforeScannerDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tracker := NewFeatureTracker(ctx, nil)
+
+	var featureDeadline time.Time
+	tracker.Run("feature", func(ctx context.Context) {
+		featureDeadline, _ = ctx.Deadline()
+	})
+	scannerDeadline, _ := ctx.Deadline()
+	if !featureDeadline.Before(scannerDeadline) {
+		t.Errorf("feature deadline %v should leave time before scanner deadline %v", featureDeadline, scannerDeadline)
+	}
+}
+
+func TestFeatureTracker_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tracker := NewFeatureTracker(ctx, nil)
+
+	tracker.Run("first", func(ctx context.Context) {
+		cancel()
+		<-ctx.Done()
+	})
+	ran := false
+	tracker.Run("second", func(ctx context.Context) { ran = true })
+
+	if ran {
+		t.Error("features should not start after the scan is cancelled")
+	}
+	statuses := tracker.Statuses()
+	if statuses["first"].Status != FeatureCancelled || statuses["second"].Status != FeatureCancelled {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestFeatureTracker_FailAndSkip(t *testing.T) {
+	tracker := NewFeatureTracker(context.Background(), nil)
+	RunFeature(tracker, "generation", func(ctx context.Context) error { return nil })
+	tracker.Fail("generation", os.ErrNotExist)
+	tracker.Skip("vulns", "generation")
+
+	result := &ScanResult{}
+	tracker.Apply(result)
+	if !result.Partial {
+		t.Error("result should be partial")
+	}
+	if st := result.FeatureStatus["generation"]; st.Status != FeatureFailed || st.Error == "" {
+		t.Errorf("generation = %+v", st)
+	}
+	if st := result.FeatureStatus["vulns"]; st.Status != FeatureSkipped {
+		t.Errorf("vulns = %+v", st)
+	}
+	if got := partialSummary(result.FeatureStatus); got != "partial: generation failed, vulns skipped" {
+		t.Errorf("partialSummary() = %q", got)
+	}
+}
+
+// partialScanner runs two features, one of which outlives the scanner timeout
+type partialScanner struct{ countingScanner }
+
+func (s *partialScanner) Run(ctx context.Context, opts *ScanOptions) (*ScanResult, error) {
+	tracker := NewFeatureTracker(ctx, opts)
+	findings := map[string]interface{}{}
+	if v, ok := RunFeature(tracker, "quick", func(ctx context.Context) int { return 3 }); ok {
+		findings["quick"] = v
+	}
+	if v, ok := RunFeature(tracker, "stuck", func(ctx context.Context) int {
+		<-ctx.Done()
+		time.Sleep(time.Hour) // ignores cancellation
+		return 0
+	}); ok {
+		findings["stuck"] = v
+	}
+
+	result := NewScanResult(s.name, s.version, time.Now())
+	_ = result.SetSummary(map[string]int{"total_findings": 3})
+	_ = result.SetFindings(findings)
+	tracker.Apply(result)
+	return result, result.WriteJSON(filepath.Join(opts.OutputDir, s.name+".json"))
+}
+
+func TestRunScanners_PartialResult(t *testing.T) {
+	repo := initGitRepo(t)
+	outputDir := t.TempDir()
+	runner := NewNativeRunner(t.TempDir())
+	s := &partialScanner{countingScanner{name: "super", version: "1.0.0"}}
+
+	result, err := runner.RunScanners(context.Background(), RunOptions{
+		RepoPath:  repo,
+		OutputDir: outputDir,
+		Scanners:  []Scanner{s},
+		Timeout:   500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := result.Results["super"]
+	if r.Status != StatusComplete || !r.Partial {
+		t.Fatalf("result = %+v, want complete and partial", r)
+	}
+	if r.Summary != "complete (partial: stuck timed out)" {
+		t.Errorf("summary = %q", r.Summary)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "super.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written ScanResult
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if !written.Partial || written.FeatureStatus["quick"].Status != FeatureComplete {
+		t.Errorf("written result = partial %v, features %+v", written.Partial, written.FeatureStatus)
+	}
+	var findings map[string]int
+	if err := json.Unmarshal(written.Findings, &findings); err != nil || len(findings) != 1 || findings["quick"] != 3 {
+		t.Errorf("findings = %s, want only the completed feature's findings", written.Findings)
+	}
+
+	// Partial results are not cached
+	if key := runner.Cache.Key(s, cacheableCommit(repo), nil, nil); key != "" {
+		if _, ok := runner.Cache.Restore(key, t.TempDir()); ok {
+			t.Error("partial result should not be cached")
+		}
+	}
+}
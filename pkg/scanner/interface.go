@@ -37,6 +37,10 @@ type ScanOptions struct {
 	// SBOMPath is path to pre-generated SBOM (optional, for scanners that need it)
 	SBOMPath string
 
+	// ZeroHome is the Zero home directory holding shared data such as
+	// imported feeds (optional)
+	ZeroHome string
+
 	// Timeout is the maximum duration for this scanner
 	Timeout time.Duration
 
@@ -116,6 +120,13 @@ type ScanResult struct {
 
 	// Error contains error message if scan failed
 	Error string `json:"error,omitempty"`
+
+	// Partial is set when some features did not complete (timed out,
+	// cancelled, failed or skipped); the findings of the others are kept
+	Partial bool `json:"partial,omitempty"`
+
+	// FeatureStatus records how each feature of a super scanner finished
+	FeatureStatus map[string]FeatureStatus `json:"feature_status,omitempty"`
 }
 
 // ScanSummary is a common summary structure used by many scanners
@@ -0,0 +1,346 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+)
+
+// PluginProtocolVersion is the version of the plugin protocol sent to
+// plugins in every request
+const PluginProtocolVersion = 1
+
+// Plugin protocol
+//
+// A plugin is an external executable declared under "plugins" in the config.
+// Zero starts it in the repository directory, writes one PluginRequest as JSON
+// to its stdin and closes stdin. The plugin writes JSON lines to stdout:
+//
+//	{"type": "progress", "message": "checking manifests"}
+//	{"type": "result", "result": {"summary": {...}, "findings": {...}}}
+//	{"type": "error", "message": "config file missing"}
+//
+// Progress messages are forwarded to OnStatus while the plugin runs. The last
+// result message becomes the scanner's ScanResult and is written to
+// <output_dir>/<name>.json; zero fills in analyzer, version, timestamp and
+// duration when the plugin leaves them empty. Lines that are not JSON are
+// treated as progress. Stderr is kept for error messages. A plugin that exits
+// non-zero, reports an error or exits without a result fails the scan.
+
+// PluginRequest is written to a plugin's stdin
+type PluginRequest struct {
+	Protocol       int                    `json:"protocol"`
+	Scanner        string                 `json:"scanner"`
+	RepoPath       string                 `json:"repo_path"`
+	OutputDir      string                 `json:"output_dir"`
+	SBOMPath       string                 `json:"sbom_path,omitempty"`
+	TimeoutSeconds int                    `json:"timeout_seconds,omitempty"`
+	Verbose        bool                   `json:"verbose,omitempty"`
+	ExtraArgs      map[string]string      `json:"extra_args,omitempty"`
+	FeatureConfig  map[string]interface{} `json:"feature_config,omitempty"`
+	RepoMetadata   *RepoMetadata          `json:"repo_metadata,omitempty"`
+}
+
+// PluginMessage is one line of plugin output
+type PluginMessage struct {
+	Type    string      `json:"type"` // progress, result or error
+	Message string      `json:"message,omitempty"`
+	Result  *ScanResult `json:"result,omitempty"`
+}
+
+// pluginWaitDelay bounds how long a cancelled plugin's pipes are drained
+// before it is abandoned
+const pluginWaitDelay = 5 * time.Second
+
+// maxPluginStderr is how much trailing stderr is kept for error messages
+const maxPluginStderr = 4096
+
+// PluginScanner runs an external plugin executable as a scanner
+type PluginScanner struct {
+	name        string
+	description string
+	command     []string
+	deps        []string
+	version     string
+	timeout     time.Duration
+	env         map[string]string
+}
+
+// versionedPlugin is a plugin with a declared version. Only versioned
+// plugins implement Versioned, so unversioned plugins are never cached.
+type versionedPlugin struct {
+	*PluginScanner
+}
+
+// Version returns the declared plugin version
+func (p *versionedPlugin) Version() string { return p.version }
+
+// TimeoutOverrider is implemented by scanners that need a different timeout
+// than the runner's default
+type TimeoutOverrider interface {
+	ScanTimeout() time.Duration
+}
+
+// NewPlugin creates a scanner for a configured plugin
+func NewPlugin(cfg config.Plugin) (Scanner, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("plugin without name")
+	}
+	if len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("plugin %s has no command", cfg.Name)
+	}
+
+	p := &PluginScanner{
+		name:        cfg.Name,
+		description: cfg.Description,
+		command:     cfg.Command,
+		deps:        cfg.Dependencies,
+		version:     cfg.Version,
+		timeout:     time.Duration(cfg.TimeoutSeconds) * time.Second,
+		env:         cfg.Env,
+	}
+	if p.description == "" {
+		p.description = fmt.Sprintf("External plugin (%s)", filepath.Base(cfg.Command[0]))
+	}
+	if cfg.Version != "" {
+		return &versionedPlugin{PluginScanner: p}, nil
+	}
+	return p, nil
+}
+
+// RegisterPlugins registers the configured plugins. Plugins may not replace
+// built-in scanners. Invalid or conflicting plugins are skipped and their
+// errors joined; the valid ones are still registered.
+func RegisterPlugins(plugins []config.Plugin) error {
+	var errs []error
+	for _, cfg := range plugins {
+		if existing, ok := Get(cfg.Name); ok {
+			if _, isPlugin := asPlugin(existing); !isPlugin {
+				errs = append(errs, fmt.Errorf("plugin %s conflicts with a built-in scanner", cfg.Name))
+				continue
+			}
+		}
+		s, err := NewPlugin(cfg)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		Register(s)
+	}
+	return errors.Join(errs...)
+}
+
+func asPlugin(s Scanner) (*PluginScanner, bool) {
+	switch p := s.(type) {
+	case *PluginScanner:
+		return p, true
+	case *versionedPlugin:
+		return p.PluginScanner, true
+	}
+	return nil, false
+}
+
+// Name returns the plugin name
+func (p *PluginScanner) Name() string { return p.name }
+
+// Description returns the plugin description
+func (p *PluginScanner) Description() string { return p.description }
+
+// Dependencies returns scanners that must run before the plugin
+func (p *PluginScanner) Dependencies() []string { return p.deps }
+
+// CacheConfig returns the command and environment the plugin runs with, so
+// editing either invalidates cached results
+func (p *PluginScanner) CacheConfig() map[string]interface{} {
+	return map[string]interface{}{"command": p.command, "env": p.env}
+}
+
+// ScanTimeout returns the configured plugin timeout (0 = runner default)
+func (p *PluginScanner) ScanTimeout() time.Duration { return p.timeout }
+
+// EstimateDuration returns a fixed estimate; plugin run times are unknown
+func (p *PluginScanner) EstimateDuration(fileCount int) time.Duration {
+	return 30 * time.Second
+}
+
+// Run executes the plugin and writes its result to <OutputDir>/<name>.json.
+// The plugin is killed when ctx is done.
+func (p *PluginScanner) Run(ctx context.Context, opts *ScanOptions) (*ScanResult, error) {
+	start := time.Now()
+
+	request, err := json.Marshal(PluginRequest{
+		Protocol:       PluginProtocolVersion,
+		Scanner:        p.name,
+		RepoPath:       opts.RepoPath,
+		OutputDir:      opts.OutputDir,
+		SBOMPath:       opts.SBOMPath,
+		TimeoutSeconds: int(opts.Timeout.Seconds()),
+		Verbose:        opts.Verbose,
+		ExtraArgs:      opts.ExtraArgs,
+		FeatureConfig:  opts.FeatureConfig,
+		RepoMetadata:   opts.RepoMetadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding plugin request: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Dir = opts.RepoPath
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.WaitDelay = pluginWaitDelay
+	cmd.Env = os.Environ()
+	for k, v := range p.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	stderr := &tailBuffer{max: maxPluginStderr}
+	cmd.Stderr = stderr
+
+	// Stdout goes through an io.Pipe rather than cmd.StdoutPipe so that Wait
+	// (bounded by WaitDelay) returns even if a killed plugin's children keep
+	// the pipe open
+	stdout, stdoutWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting plugin %s: %w", p.name, err)
+	}
+
+	var result *ScanResult
+	var pluginErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		result, pluginErr = p.readMessages(stdout, opts.OnStatus)
+	}()
+
+	waitErr := cmd.Wait()
+	stdoutWriter.Close()
+	<-done
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &ScanResult{Analyzer: p.name, Error: "timeout"}, nil
+	}
+	if waitErr != nil {
+		return nil, fmt.Errorf("plugin %s: %w%s", p.name, waitErr, stderr.suffix())
+	}
+	if pluginErr != nil {
+		return nil, fmt.Errorf("plugin %s: %w%s", p.name, pluginErr, stderr.suffix())
+	}
+	if result == nil {
+		return nil, fmt.Errorf("plugin %s exited without a result%s", p.name, stderr.suffix())
+	}
+
+	if result.Analyzer == "" {
+		result.Analyzer = p.name
+	}
+	if result.Version == "" {
+		result.Version = p.version
+	}
+	if result.Timestamp == "" {
+		result.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if result.DurationSeconds == 0 {
+		result.DurationSeconds = int(time.Since(start).Seconds())
+	}
+	if len(result.Summary) == 0 {
+		result.Summary = json.RawMessage("{}")
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := result.WriteJSON(filepath.Join(opts.OutputDir, p.name+".json")); err != nil {
+		return nil, fmt.Errorf("writing plugin result: %w", err)
+	}
+	return result, nil
+}
+
+// readMessages consumes plugin output until EOF, forwarding progress and
+// returning the last result. An error message from the plugin is returned
+// as an error once output ends.
+func (p *PluginScanner) readMessages(r io.Reader, onStatus func(string)) (*ScanResult, error) {
+	var result *ScanResult
+	var pluginErr error
+
+	status := func(msg string) {
+		if onStatus != nil && msg != "" {
+			onStatus(msg)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg PluginMessage
+		if line[0] != '{' || json.Unmarshal(line, &msg) != nil || msg.Type == "" {
+			status(string(line))
+			continue
+		}
+
+		switch msg.Type {
+		case "progress":
+			status(msg.Message)
+		case "result":
+			if msg.Result == nil {
+				pluginErr = fmt.Errorf("result message without result")
+				continue
+			}
+			result = msg.Result
+		case "error":
+			pluginErr = fmt.Errorf("%s", msg.Message)
+		default:
+			status(msg.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil && pluginErr == nil {
+		pluginErr = fmt.Errorf("reading plugin output: %w", err)
+	}
+
+	// Drain anything left so the plugin is not blocked on a full pipe
+	_, _ = io.Copy(io.Discard, r)
+	return result, pluginErr
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+	max int
+}
+
+func (t *tailBuffer) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, b...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(b), nil
+}
+
+// suffix formats the captured stderr for appending to an error message
+func (t *tailBuffer) suffix() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := strings.TrimSpace(string(t.buf))
+	if s == "" {
+		return ""
+	}
+	return ": " + s
+}
@@ -0,0 +1,336 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/config"
+)
+
+// TestHelperPlugin is not a real test: it is run as the plugin executable
+// by the tests below, selected by ZERO_TEST_PLUGIN
+func TestHelperPlugin(t *testing.T) {
+	mode := os.Getenv("ZERO_TEST_PLUGIN")
+	if mode == "" {
+		return
+	}
+
+	var req PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "bad request:", err)
+		os.Exit(2)
+	}
+
+	emit := func(msg PluginMessage) {
+		data, _ := json.Marshal(msg)
+		fmt.Println(string(data))
+	}
+
+	switch mode {
+	case "ok":
+		emit(PluginMessage{Type: "progress", Message: "checking " + req.Scanner})
+		fmt.Println("plain text progress")
+		result := &ScanResult{}
+		_ = result.SetSummary(map[string]int{"total_findings": 1})
+		_ = result.SetFindings(map[string]interface{}{
+			"request": req,
+			"cwd":     mustGetwd(),
+			"env":     os.Getenv("PLUGIN_SETTING"),
+		})
+		emit(PluginMessage{Type: "result", Result: result})
+	case "error":
+		fmt.Fprintln(os.Stderr, "policy file missing")
+		emit(PluginMessage{Type: "error", Message: "cannot run check"})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "panic: boom")
+		os.Exit(3)
+	case "hang":
+		emit(PluginMessage{Type: "progress", Message: "working"})
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func mustGetwd() string {
+	wd, _ := os.Getwd()
+	return wd
+}
+
+func testPlugin(t *testing.T, name, mode string) config.Plugin {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config.Plugin{
+		Name:    name,
+		Command: []string{exe, "-test.run=^TestHelperPlugin$"},
+		Env:     map[string]string{"ZERO_TEST_PLUGIN": mode, "PLUGIN_SETTING": "from-config"},
+	}
+}
+
+func TestPluginRun(t *testing.T) {
+	repo := t.TempDir()
+	outputDir := t.TempDir()
+
+	cfg := testPlugin(t, "acme-checks", "ok")
+	cfg.Version = "1.2.0"
+	s, err := NewPlugin(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := s.(Versioned); !ok || v.Version() != "1.2.0" {
+		t.Error("plugin with a version should implement Versioned")
+	}
+
+	var mu sync.Mutex
+	var statuses []string
+	result, err := s.Run(context.Background(), &ScanOptions{
+		RepoPath:      repo,
+		OutputDir:     outputDir,
+		Timeout:       time.Minute,
+		FeatureConfig: map[string]interface{}{"strict": true},
+		RepoMetadata:  &RepoMetadata{CommitSHA: "abc123"},
+		OnStatus: func(msg string) {
+			mu.Lock()
+			statuses = append(statuses, msg)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := strings.Join(statuses, "|"); got != "checking acme-checks|plain text progress" {
+		t.Errorf("statuses = %q", got)
+	}
+	if result.Analyzer != "acme-checks" || result.Version != "1.2.0" || result.Timestamp == "" {
+		t.Errorf("result header = %s %s %s, want defaults filled in", result.Analyzer, result.Version, result.Timestamp)
+	}
+
+	var written ScanResult
+	data, err := os.ReadFile(filepath.Join(outputDir, "acme-checks.json"))
+	if err != nil {
+		t.Fatalf("result not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+
+	var findings struct {
+		Request PluginRequest `json:"request"`
+		Cwd     string        `json:"cwd"`
+		Env     string        `json:"env"`
+	}
+	if err := json.Unmarshal(written.Findings, &findings); err != nil {
+		t.Fatal(err)
+	}
+	req := findings.Request
+	if req.Protocol != PluginProtocolVersion || req.RepoPath != repo || req.OutputDir != outputDir || req.TimeoutSeconds != 60 {
+		t.Errorf("request = %+v", req)
+	}
+	if req.FeatureConfig["strict"] != true || req.RepoMetadata == nil || req.RepoMetadata.CommitSHA != "abc123" {
+		t.Errorf("request config/metadata = %v %+v", req.FeatureConfig, req.RepoMetadata)
+	}
+	if wantDir, _ := filepath.EvalSymlinks(repo); findings.Cwd != wantDir && findings.Cwd != repo {
+		t.Errorf("plugin cwd = %s, want %s", findings.Cwd, repo)
+	}
+	if findings.Env != "from-config" {
+		t.Errorf("plugin env = %q, want from-config", findings.Env)
+	}
+}
+
+func TestPluginRunErrors(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{"error", []string{"cannot run check", "policy file missing"}},
+		{"crash", []string{"exit status 3", "panic: boom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			s, err := NewPlugin(testPlugin(t, "acme-checks", tt.mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = s.Run(context.Background(), &ScanOptions{RepoPath: t.TempDir(), OutputDir: t.TempDir()})
+			if err == nil {
+				t.Fatal("Run() should fail")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q should contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunScannersWithPlugin(t *testing.T) {
+	repo := t.TempDir()
+	runner := NewNativeRunner(t.TempDir())
+
+	var mu sync.Mutex
+	var events []string
+	runner.OnProgress = func(name string, status Status, summary string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, fmt.Sprintf("%s:%s:%s", name, status, summary))
+	}
+
+	base := &countingScanner{name: "base", version: "1.0.0"}
+	cfg := testPlugin(t, "acme-checks", "ok")
+	cfg.Dependencies = []string{"base"}
+	plugin, err := NewPlugin(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hang, err := NewPlugin(config.Plugin{
+		Name:           "slow-plugin",
+		Command:        testPlugin(t, "", "").Command,
+		Env:            map[string]string{"ZERO_TEST_PLUGIN": "hang"},
+		TimeoutSeconds: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels, err := GroupByDependencies([]Scanner{plugin, hang, base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 2 || levels[1][0].Name() != "acme-checks" {
+		t.Errorf("plugin should run after its dependency, levels = %v", levelNames(levels))
+	}
+
+	result, err := runner.RunScanners(context.Background(), RunOptions{
+		RepoPath:  repo,
+		OutputDir: t.TempDir(),
+		Scanners:  []Scanner{plugin, hang, base},
+		Timeout:   time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Results["acme-checks"].Status; got != StatusComplete {
+		t.Errorf("plugin status = %s, want complete (%v)", got, result.Results["acme-checks"].Error)
+	}
+	if got := result.Results["slow-plugin"].Status; got != StatusTimeout {
+		t.Errorf("hanging plugin status = %s, want timeout", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	found := false
+	for _, e := range events {
+		if e == "acme-checks:running:checking acme-checks" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("plugin progress not forwarded to OnProgress: %v", events)
+	}
+}
+
+func TestRegisterPluginsRejectsBuiltin(t *testing.T) {
+	Register(&countingScanner{name: "builtin-test"})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "builtin-test")
+		delete(registry, "plugin-test")
+		registryMu.Unlock()
+	}()
+
+	if err := RegisterPlugins([]config.Plugin{{Name: "builtin-test", Command: []string{"true"}}}); err == nil {
+		t.Error("plugin should not replace a built-in scanner")
+	}
+	if err := RegisterPlugins([]config.Plugin{{Name: "plugin-test", Command: []string{"true"}}}); err != nil {
+		t.Fatal(err)
+	}
+	// Re-registering a plugin (e.g., after a config reload) is allowed
+	if err := RegisterPlugins([]config.Plugin{{Name: "plugin-test", Command: []string{"true"}, Version: "2"}}); err != nil {
+		t.Errorf("re-registering plugin: %v", err)
+	}
+	if s, _ := Get("plugin-test"); s.(Versioned).Version() != "2" {
+		t.Error("re-registered plugin should replace the previous one")
+	}
+}
+
+func TestRegisterPluginsKeepsValid(t *testing.T) {
+	Register(&countingScanner{name: "builtin-test"})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "builtin-test")
+		delete(registry, "plugin-a")
+		delete(registry, "plugin-b")
+		registryMu.Unlock()
+	}()
+
+	err := RegisterPlugins([]config.Plugin{
+		{Name: "plugin-a", Command: []string{"true"}},
+		{Name: "builtin-test", Command: []string{"true"}},
+		{Name: "plugin-nocmd"},
+		{Name: "plugin-b", Command: []string{"true"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "builtin-test") || !strings.Contains(err.Error(), "plugin-nocmd") {
+		t.Errorf("RegisterPlugins() error = %v, want both invalid plugins reported", err)
+	}
+	for _, name := range []string{"plugin-a", "plugin-b"} {
+		if _, ok := Get(name); !ok {
+			t.Errorf("valid plugin %s was not registered", name)
+		}
+	}
+}
+
+func TestPluginCacheKey(t *testing.T) {
+	cache := NewResultCache(t.TempDir())
+	key := func(cfg config.Plugin) string {
+		t.Helper()
+		s, err := NewPlugin(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cache.Key(s, "abc", nil, nil)
+	}
+
+	cfg := config.Plugin{Name: "acme-checks", Command: []string{"acme", "--fast"}, Version: "1", Env: map[string]string{"LEVEL": "1"}}
+	base := key(cfg)
+	if base == "" {
+		t.Fatal("versioned plugin should be cacheable")
+	}
+	if key(cfg) != base {
+		t.Error("key should be stable")
+	}
+
+	changed := cfg
+	changed.Command = []string{"acme", "--thorough"}
+	if key(changed) == base {
+		t.Error("key should change with the plugin command")
+	}
+	changed = cfg
+	changed.Env = map[string]string{"LEVEL": "2"}
+	if key(changed) == base {
+		t.Error("key should change with the plugin environment")
+	}
+}
+
+func levelNames(levels [][]Scanner) [][]string {
+	var names [][]string
+	for _, level := range levels {
+		var l []string
+		for _, s := range level {
+			l = append(l, s.Name())
+		}
+		names = append(names, l)
+	}
+	return names
+}
@@ -16,14 +16,18 @@ type NativeRunner struct {
 	Timeout     time.Duration
 	Parallel    int
 	OnProgress  func(scanner string, status Status, summary string)
+	Cache       *ResultCache // Reuses results for unchanged commits (nil disables)
 }
 
 // NewNativeRunner creates a new native scanner runner
 func NewNativeRunner(zeroHome string) *NativeRunner {
+	cache := NewResultCache(filepath.Join(zeroHome, "cache", "results"))
+	cache.ZeroHome = zeroHome
 	return &NativeRunner{
 		ZeroHome: zeroHome,
 		Timeout:  5 * time.Minute,
 		Parallel: 4,
+		Cache:    cache,
 	}
 }
 
@@ -37,6 +41,7 @@ type RunOptions struct {
 	Parallel       int
 	FeatureConfigs map[string]map[string]interface{} // Scanner name -> feature config
 	RepoMetadata   *RepoMetadata                     // Repository metadata for evidence collection
+	NoCache        bool                              // Re-run scanners even if cached results exist
 }
 
 // RunScanners executes all configured scanners for a repository
@@ -83,6 +88,21 @@ func (r *NativeRunner) RunScanners(ctx context.Context, opts RunOptions) (*RunRe
 		outputDir = filepath.Join(r.ZeroHome, "repos", opts.RepoPath, "analysis")
 	}
 
+	// Compute result cache keys in dependency order so a dependency's key
+	// feeds into its dependents'
+	cacheKeys := make(map[string]string)
+	var commit string
+	if r.Cache != nil && !opts.NoCache {
+		commit = cacheableCommit(opts.RepoPath)
+	}
+	if commit != "" {
+		for _, s := range sorted {
+			if key := r.Cache.Key(s, commit, opts.FeatureConfigs[s.Name()], cacheKeys); key != "" {
+				cacheKeys[s.Name()] = key
+			}
+		}
+	}
+
 	// Track results
 	results := make(map[string]*Result)
 	var resultsMu sync.Mutex
@@ -134,13 +154,19 @@ func (r *NativeRunner) RunScanners(ctx context.Context, opts RunOptions) (*RunRe
 					r.OnProgress(scanner.Name(), StatusRunning, "")
 				}
 
+				scannerTimeout := timeout
+				if t, ok := scanner.(TimeoutOverrider); ok && t.ScanTimeout() > 0 {
+					scannerTimeout = t.ScanTimeout()
+				}
+
 				// Build scan options
 				sbomMu.RLock()
 				scanOpts := &ScanOptions{
 					RepoPath:     opts.RepoPath,
 					OutputDir:    outputDir,
 					SBOMPath:     sbomPath,
-					Timeout:      timeout,
+					ZeroHome:     r.ZeroHome,
+					Timeout:      scannerTimeout,
 					RepoMetadata: opts.RepoMetadata,
 					// Forward status messages to progress callback
 					OnStatus: func(msg string) {
@@ -157,8 +183,32 @@ func (r *NativeRunner) RunScanners(ctx context.Context, opts RunOptions) (*RunRe
 				}
 				sbomMu.RUnlock()
 
+				// Reuse cached output for an unchanged commit and config
+				cacheKey := cacheKeys[scanner.Name()]
+				if cacheKey != "" {
+					if summary, ok := r.Cache.Restore(cacheKey, outputDir); ok {
+						resultsMu.Lock()
+						results[scanner.Name()] = &Result{
+							Scanner: scanner.Name(),
+							Status:  StatusComplete,
+							Summary: summary,
+							Cached:  true,
+						}
+						resultsMu.Unlock()
+						if scanner.Name() == "package-sbom" {
+							sbomMu.Lock()
+							sbomPath = filepath.Join(outputDir, "sbom.cdx.json")
+							sbomMu.Unlock()
+						}
+						if r.OnProgress != nil {
+							r.OnProgress(scanner.Name(), StatusComplete, cachedSummary(summary))
+						}
+						return
+					}
+				}
+
 				// Create context with per-scanner timeout
-				scanCtx, cancel := context.WithTimeout(ctx, timeout)
+				scanCtx, cancel := context.WithTimeout(ctx, scannerTimeout)
 				defer cancel()
 
 				// Run scanner
@@ -191,6 +241,14 @@ func (r *NativeRunner) RunScanners(ctx context.Context, opts RunOptions) (*RunRe
 				} else {
 					result.Status = StatusComplete
 					result.Summary = extractSummaryString(scanner.Name(), scanResult)
+					if scanResult.Partial {
+						// Keep what the completed features found, but never
+						// cache it: the next run should retry the rest
+						result.Partial = true
+						result.Summary += " (" + partialSummary(scanResult.FeatureStatus) + ")"
+					} else if cacheKey != "" {
+						r.Cache.Store(cacheKey, scanner, commit, outputDir, result.Summary)
+					}
 					if r.OnProgress != nil {
 						r.OnProgress(scanner.Name(), StatusComplete, result.Summary)
 					}
@@ -30,6 +30,8 @@ type Result struct {
 	Duration  time.Duration
 	Error     error
 	Output    json.RawMessage
+	Cached    bool // Restored from the result cache instead of re-running
+	Partial   bool // Some features did not complete; results cover the rest
 }
 
 // Progress tracks scanner progress for a repo
@@ -120,6 +122,9 @@ type RunResult struct {
 // Runner executes scanners (wraps NativeRunner for backward compatibility)
 type Runner struct {
 	native *NativeRunner
+
+	// NoCache re-runs scanners even if cached results exist
+	NoCache bool
 }
 
 // NewRunner creates a new scanner runner
@@ -129,6 +134,13 @@ func NewRunner(zeroHome string) *Runner {
 	}
 }
 
+// SetCacheTTL sets how long cached scanner results are reused
+func (r *Runner) SetCacheTTL(ttl time.Duration) {
+	if r.native.Cache != nil {
+		r.native.Cache.MaxAge = ttl
+	}
+}
+
 // Run executes all scanners for a repository using native Go scanners
 func (r *Runner) Run(ctx context.Context, repo, profile string, progress *Progress, skipScanners []string) (*RunResult, error) {
 	// Get scanners for the profile from registry
@@ -166,6 +178,7 @@ func (r *Runner) Run(ctx context.Context, repo, profile string, progress *Progre
 		OutputDir:    outputDir,
 		Scanners:     scannersToRun,
 		SkipScanners: skipScanners,
+		NoCache:      r.NoCache,
 	})
 }
 
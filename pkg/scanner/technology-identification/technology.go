@@ -27,6 +27,7 @@ var ruleLoadMessageOnce sync.Once
 const (
 	Name        = "technology-identification"
 	Description = "Technology identification, AI/ML security analysis and ML-BOM generation"
+	Version     = "1.0.0"
 )
 
 // TechnologyScanner implements the technology identification super scanner
@@ -56,6 +57,21 @@ func (s *TechnologyScanner) Dependencies() []string {
 	return []string{}
 }
 
+// Version returns the scanner version
+func (s *TechnologyScanner) Version() string {
+	return Version
+}
+
+// Tools returns the external tools whose versions affect results
+func (s *TechnologyScanner) Tools() []string {
+	return []string{"semgrep"}
+}
+
+// Artifacts returns files written next to the scanner result
+func (s *TechnologyScanner) Artifacts() []string {
+	return []string{"mlbom.cdx.json"}
+}
+
 // EstimateDuration returns estimated scan duration based on file count
 func (s *TechnologyScanner) EstimateDuration(fileCount int) time.Duration {
 	// Base time + time per file for pattern scanning
@@ -146,44 +162,63 @@ func (s *TechnologyScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	s.mergeSemgrepFindings(semgrepResult, result)
 	result.FeaturesRun = append(result.FeaturesRun, "semgrep_scan")
 
+	// Run each enabled feature (Go-native detection). Features share the
+	// result, so they are run to completion in turn; once the deadline has
+	// passed the remaining ones are recorded as timed out instead of run.
+	tracker := scanner.NewFeatureTracker(ctx, opts)
+
 	// Run each enabled feature (Go-native detection)
 	if s.config.Technology.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "technology")
-		s.runTechnologyFeature(ctx, repoPath, opts.SBOMPath, result)
+		tracker.Run("technology", func(ctx context.Context) {
+			s.runTechnologyFeature(ctx, repoPath, opts.SBOMPath, result)
+		})
 	}
 
 	if s.config.Models.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "models")
-		s.runModelsFeature(ctx, repoPath, result)
+		tracker.Run("models", func(ctx context.Context) {
+			s.runModelsFeature(ctx, repoPath, result)
+		})
 	}
 
 	if s.config.Frameworks.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "frameworks")
-		s.runFrameworksFeature(ctx, repoPath, result)
+		tracker.Run("frameworks", func(ctx context.Context) {
+			s.runFrameworksFeature(ctx, repoPath, result)
+		})
 	}
 
 	if s.config.Datasets.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "datasets")
-		s.runDatasetsFeature(ctx, repoPath, result)
+		tracker.Run("datasets", func(ctx context.Context) {
+			s.runDatasetsFeature(ctx, repoPath, result)
+		})
 	}
 
 	if s.config.Security.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "security")
-		s.runSecurityFeature(ctx, repoPath, result)
+		tracker.Run("security", func(ctx context.Context) {
+			s.runSecurityFeature(ctx, repoPath, result)
+		})
 	}
 
 	if s.config.Governance.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "governance")
-		s.runGovernanceFeature(ctx, repoPath, result)
+		tracker.Run("governance", func(ctx context.Context) {
+			s.runGovernanceFeature(ctx, repoPath, result)
+		})
 	}
 
 	if s.config.Infrastructure.Enabled {
 		result.FeaturesRun = append(result.FeaturesRun, "infrastructure")
-		s.runInfrastructureFeature(ctx, repoPath, onStatus, result)
+		tracker.Run("infrastructure", func(ctx context.Context) {
+			s.runInfrastructureFeature(ctx, repoPath, onStatus, result)
+		})
 	}
 
 	// Create scan result using the proper interface
-	scanResult := scanner.NewScanResult(Name, "1.0.0", startTime)
+	scanResult := scanner.NewScanResult(Name, Version, startTime)
 
 	if err := scanResult.SetSummary(result.Summary); err != nil {
 		return nil, fmt.Errorf("failed to set summary: %w", err)
@@ -200,6 +235,7 @@ func (s *TechnologyScanner) Run(ctx context.Context, opts *scanner.ScanOptions)
 	if err := scanResult.SetMetadata(metadata); err != nil {
 		return nil, fmt.Errorf("failed to set metadata: %w", err)
 	}
+	tracker.Apply(scanResult)
 
 	// Write output to disk
 	if opts.OutputDir != "" {
@@ -117,6 +117,10 @@ type FindingsSummary struct {
 	PackagesTotal     int       `json:"packages_total"`
 	TechnologiesTotal int       `json:"technologies_total"`
 	UpdatedAt         time.Time `json:"updated_at"`
+
+	// IncompleteFeatures lists, per scanner with partial results, the
+	// features that did not complete; counts above exclude their findings
+	IncompleteFeatures map[string][]string `json:"incomplete_features,omitempty"`
 }
 
 // AggregateStats contains global statistics across all projects.
@@ -144,6 +148,14 @@ type Vulnerability struct {
 	FixVersion  string `json:"fix_version,omitempty"`
 	Source      string `json:"source"` // package, code
 	Scanner     string `json:"scanner"`
+
+	// Prioritization (package vulnerabilities only)
+	CVSSScore     float64 `json:"cvss_score,omitempty"`
+	CVSSVector    string  `json:"cvss_vector,omitempty"`
+	EPSS          float64 `json:"epss,omitempty"`
+	InKEV         bool    `json:"in_kev,omitempty"`
+	PriorityScore float64 `json:"priority_score,omitempty"`
+	PriorityRank  int     `json:"priority_rank,omitempty"` // 1 = fix first, within the project
 }
 
 // Secret represents a detected secret.
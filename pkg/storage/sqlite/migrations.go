@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 // Migrate runs all database migrations.
@@ -26,11 +27,18 @@ func (s *Store) Migrate(ctx context.Context) error {
 		return fmt.Errorf("getting current migration version: %w", err)
 	}
 
-	// Run pending migrations
-	for version, migration := range migrations {
+	// Run pending migrations in version order (map iteration is random)
+	versions := make([]int, 0, len(migrations))
+	for version := range migrations {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	for _, version := range versions {
 		if version <= currentVersion {
 			continue
 		}
+		migration := migrations[version]
 
 		if _, err := s.db.ExecContext(ctx, migration); err != nil {
 			return fmt.Errorf("running migration %d: %w", version, err)
@@ -48,6 +56,8 @@ func (s *Store) Migrate(ctx context.Context) error {
 var migrations = map[int]string{
 	1: migration001,
 	2: migration002,
+	3: migration003,
+	4: migration004,
 }
 
 const migration001 = `
@@ -159,3 +169,18 @@ CREATE TABLE IF NOT EXISTS secrets (
 CREATE INDEX IF NOT EXISTS idx_secrets_project ON secrets(project_id);
 CREATE INDEX IF NOT EXISTS idx_secrets_severity ON secrets(severity);
 `
+
+const migration003 = `
+-- Scanners with partial results (JSON: scanner -> incomplete features)
+ALTER TABLE findings_summary ADD COLUMN incomplete_features TEXT;
+`
+
+const migration004 = `
+-- Exploit-aware prioritization of package vulnerabilities
+ALTER TABLE vulnerabilities ADD COLUMN cvss_score REAL DEFAULT 0;
+ALTER TABLE vulnerabilities ADD COLUMN cvss_vector TEXT DEFAULT '';
+ALTER TABLE vulnerabilities ADD COLUMN epss REAL DEFAULT 0;
+ALTER TABLE vulnerabilities ADD COLUMN in_kev INTEGER DEFAULT 0;
+ALTER TABLE vulnerabilities ADD COLUMN priority_score REAL DEFAULT 0;
+ALTER TABLE vulnerabilities ADD COLUMN priority_rank INTEGER DEFAULT 0;
+`
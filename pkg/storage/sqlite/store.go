@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -327,8 +328,8 @@ func (s *Store) GetLatestScan(ctx context.Context, projectID string) (*storage.S
 // UpsertFindingsSummary creates or updates a findings summary.
 func (s *Store) UpsertFindingsSummary(ctx context.Context, summary *storage.FindingsSummary) error {
 	query := `INSERT INTO findings_summary (project_id, vulns_critical, vulns_high, vulns_medium, vulns_low,
-		vulns_total, secrets_total, packages_total, technologies_total, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		vulns_total, secrets_total, packages_total, technologies_total, incomplete_features, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(project_id) DO UPDATE SET
 			vulns_critical = excluded.vulns_critical,
 			vulns_high = excluded.vulns_high,
@@ -338,15 +339,25 @@ func (s *Store) UpsertFindingsSummary(ctx context.Context, summary *storage.Find
 			secrets_total = excluded.secrets_total,
 			packages_total = excluded.packages_total,
 			technologies_total = excluded.technologies_total,
+			incomplete_features = excluded.incomplete_features,
 			updated_at = excluded.updated_at`
 
 	if summary.UpdatedAt.IsZero() {
 		summary.UpdatedAt = time.Now()
 	}
 
+	var incomplete sql.NullString
+	if len(summary.IncompleteFeatures) > 0 {
+		data, err := json.Marshal(summary.IncompleteFeatures)
+		if err != nil {
+			return fmt.Errorf("encoding incomplete features: %w", err)
+		}
+		incomplete = nullString(string(data))
+	}
+
 	_, err := s.db.ExecContext(ctx, query,
 		summary.ProjectID, summary.VulnsCritical, summary.VulnsHigh, summary.VulnsMedium, summary.VulnsLow,
-		summary.VulnsTotal, summary.SecretsTotal, summary.PackagesTotal, summary.TechnologiesTotal, summary.UpdatedAt)
+		summary.VulnsTotal, summary.SecretsTotal, summary.PackagesTotal, summary.TechnologiesTotal, incomplete, summary.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("upserting findings summary: %w", err)
 	}
@@ -356,19 +367,25 @@ func (s *Store) UpsertFindingsSummary(ctx context.Context, summary *storage.Find
 // GetFindingsSummary returns the findings summary for a project.
 func (s *Store) GetFindingsSummary(ctx context.Context, projectID string) (*storage.FindingsSummary, error) {
 	query := `SELECT project_id, vulns_critical, vulns_high, vulns_medium, vulns_low,
-		vulns_total, secrets_total, packages_total, technologies_total, updated_at
+		vulns_total, secrets_total, packages_total, technologies_total, incomplete_features, updated_at
 		FROM findings_summary WHERE project_id = ?`
 
 	summary := &storage.FindingsSummary{}
+	var incomplete sql.NullString
 	err := s.db.QueryRowContext(ctx, query, projectID).Scan(
 		&summary.ProjectID, &summary.VulnsCritical, &summary.VulnsHigh, &summary.VulnsMedium, &summary.VulnsLow,
-		&summary.VulnsTotal, &summary.SecretsTotal, &summary.PackagesTotal, &summary.TechnologiesTotal, &summary.UpdatedAt)
+		&summary.VulnsTotal, &summary.SecretsTotal, &summary.PackagesTotal, &summary.TechnologiesTotal, &incomplete, &summary.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("querying findings summary: %w", err)
 	}
+	if incomplete.Valid && incomplete.String != "" {
+		if err := json.Unmarshal([]byte(incomplete.String), &summary.IncompleteFeatures); err != nil {
+			return nil, fmt.Errorf("decoding incomplete features: %w", err)
+		}
+	}
 	return summary, nil
 }
 
@@ -442,8 +459,9 @@ func (s *Store) UpsertVulnerabilities(ctx context.Context, projectID string, vul
 
 	// Insert new vulnerabilities
 	stmt, err := tx.PrepareContext(ctx, `INSERT INTO vulnerabilities
-		(project_id, vuln_id, package, version, severity, title, description, fix_version, source, scanner)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		(project_id, vuln_id, package, version, severity, title, description, fix_version, source, scanner,
+		 cvss_score, cvss_vector, epss, in_kev, priority_score, priority_rank)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return fmt.Errorf("preparing statement: %w", err)
 	}
@@ -451,7 +469,8 @@ func (s *Store) UpsertVulnerabilities(ctx context.Context, projectID string, vul
 
 	for _, v := range vulns {
 		_, err := stmt.ExecContext(ctx, projectID, v.VulnID, v.Package, v.Version,
-			v.Severity, v.Title, v.Description, v.FixVersion, v.Source, v.Scanner)
+			v.Severity, v.Title, v.Description, v.FixVersion, v.Source, v.Scanner,
+			v.CVSSScore, v.CVSSVector, v.EPSS, v.InKEV, v.PriorityScore, v.PriorityRank)
 		if err != nil {
 			return fmt.Errorf("inserting vulnerability: %w", err)
 		}
@@ -492,8 +511,10 @@ func (s *Store) GetVulnerabilities(ctx context.Context, opts storage.VulnOptions
 
 	// Get results
 	// #nosec G202 -- SQL concatenation is safe here; all values are parameterized via args
-	selectQuery := `SELECT id, project_id, vuln_id, package, version, severity, title, description, fix_version, source, scanner ` + baseQuery
-	selectQuery += " ORDER BY CASE severity WHEN 'critical' THEN 1 WHEN 'high' THEN 2 WHEN 'medium' THEN 3 WHEN 'low' THEN 4 ELSE 5 END"
+	selectQuery := `SELECT id, project_id, vuln_id, package, version, severity, title, description, fix_version, source, scanner,
+		cvss_score, cvss_vector, epss, in_kev, priority_score, priority_rank ` + baseQuery
+	// Highest priority first; unprioritized (code) vulnerabilities by severity
+	selectQuery += " ORDER BY priority_score DESC, CASE severity WHEN 'critical' THEN 1 WHEN 'high' THEN 2 WHEN 'medium' THEN 3 WHEN 'low' THEN 4 ELSE 5 END"
 
 	if opts.Limit > 0 {
 		selectQuery += " LIMIT ?"
@@ -514,7 +535,8 @@ func (s *Store) GetVulnerabilities(ctx context.Context, opts storage.VulnOptions
 	for rows.Next() {
 		v := &storage.Vulnerability{}
 		err := rows.Scan(&v.ID, &v.ProjectID, &v.VulnID, &v.Package, &v.Version,
-			&v.Severity, &v.Title, &v.Description, &v.FixVersion, &v.Source, &v.Scanner)
+			&v.Severity, &v.Title, &v.Description, &v.FixVersion, &v.Source, &v.Scanner,
+			&v.CVSSScore, &v.CVSSVector, &v.EPSS, &v.InKEV, &v.PriorityScore, &v.PriorityRank)
 		if err != nil {
 			return nil, 0, fmt.Errorf("scanning vulnerability row: %w", err)
 		}
@@ -691,6 +713,10 @@ func (s *Store) SyncProjectFromJSON(ctx context.Context, projectID string, analy
 		}
 	}
 
+	// Record scanners whose results are partial so counts can be read as
+	// lower bounds
+	summary.IncompleteFeatures = incompleteFeatures(analysisDir)
+
 	// Update database
 	if err := s.UpsertFindingsSummary(ctx, summary); err != nil {
 		return fmt.Errorf("upserting findings summary: %w", err)
@@ -705,6 +731,45 @@ func (s *Store) SyncProjectFromJSON(ctx context.Context, projectID string, analy
 	return nil
 }
 
+// incompleteFeatures reads the partial-result markers of the scanner
+// outputs in analysisDir and returns scanner -> features that did not complete
+func incompleteFeatures(analysisDir string) map[string][]string {
+	paths, _ := filepath.Glob(filepath.Join(analysisDir, "*.json"))
+	var incomplete map[string][]string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var result struct {
+			Analyzer      string `json:"analyzer"`
+			Partial       bool   `json:"partial"`
+			FeatureStatus map[string]struct {
+				Status string `json:"status"`
+			} `json:"feature_status"`
+		}
+		if json.Unmarshal(data, &result) != nil || !result.Partial {
+			continue
+		}
+		name := result.Analyzer
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		var features []string
+		for feature, st := range result.FeatureStatus {
+			if st.Status != "complete" {
+				features = append(features, feature)
+			}
+		}
+		sort.Strings(features)
+		if incomplete == nil {
+			incomplete = make(map[string][]string)
+		}
+		incomplete[name] = features
+	}
+	return incomplete
+}
+
 // Helper functions
 
 func nullTime(t time.Time) sql.NullTime {
@@ -778,6 +843,24 @@ func extractVulnsFromPackages(projectID string, data map[string]interface{}) ([]
 		if fix, ok := v["fix_version"].(string); ok {
 			vuln.FixVersion = fix
 		}
+		if score, ok := v["cvss_score"].(float64); ok {
+			vuln.CVSSScore = score
+		}
+		if vector, ok := v["cvss_vector"].(string); ok {
+			vuln.CVSSVector = vector
+		}
+		if epss, ok := v["epss"].(float64); ok {
+			vuln.EPSS = epss
+		}
+		if kev, ok := v["in_kev"].(bool); ok {
+			vuln.InKEV = kev
+		}
+		if score, ok := v["priority_score"].(float64); ok {
+			vuln.PriorityScore = score
+		}
+		if rank, ok := v["priority_rank"].(float64); ok {
+			vuln.PriorityRank = int(rank)
+		}
 
 		vulns = append(vulns, vuln)
 	}
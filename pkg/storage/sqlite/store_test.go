@@ -568,6 +568,34 @@ func TestStore_Vulnerabilities(t *testing.T) {
 		}
 	})
 
+	t.Run("GetVulnerabilities by priority", func(t *testing.T) {
+		vulns := []*storage.Vulnerability{
+			{VulnID: "CVE-2023-001", Package: "lodash", Version: "4.17.0", Severity: "critical", Source: "package", Scanner: "code-packages",
+				CVSSScore: 9.8, PriorityScore: 24.5, PriorityRank: 2},
+			{VulnID: "CVE-2021-44228", Package: "log4j-core", Version: "2.14.1", Severity: "high", Source: "package", Scanner: "code-packages",
+				CVSSScore: 8.1, CVSSVector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:H/A:H", EPSS: 0.944, InKEV: true, PriorityScore: 81, PriorityRank: 1},
+			{VulnID: "rule-001", Package: "src/auth.js", Severity: "critical", Source: "code", Scanner: "code-security"},
+		}
+		if err := store.UpsertVulnerabilities(ctx, "test/repo", vulns); err != nil {
+			t.Fatalf("UpsertVulnerabilities failed: %v", err)
+		}
+
+		got, _, err := store.GetVulnerabilities(ctx, storage.VulnOptions{ProjectID: "test/repo"})
+		if err != nil {
+			t.Fatalf("GetVulnerabilities failed: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("got %d vulns, want 3", len(got))
+		}
+		top := got[0]
+		if top.VulnID != "CVE-2021-44228" || !top.InKEV || top.EPSS != 0.944 || top.PriorityRank != 1 || top.CVSSVector == "" {
+			t.Errorf("first vuln = %+v, want the KEV-listed log4j finding", top)
+		}
+		if got[2].Source != "code" {
+			t.Errorf("last vuln = %+v, want the unprioritized code finding", got[2])
+		}
+	})
+
 	t.Run("DeleteVulnerabilities", func(t *testing.T) {
 		err := store.DeleteVulnerabilities(ctx, "test/repo")
 		if err != nil {
@@ -726,6 +754,12 @@ func TestStore_SyncProjectFromJSON(t *testing.T) {
 
 	// Write code-packages.json
 	packagesJSON := `{
+		"analyzer": "code-packages",
+		"partial": true,
+		"feature_status": {
+			"vulns": {"status": "complete"},
+			"reachability": {"status": "timeout"}
+		},
 		"summary": {
 			"sbom": {
 				"total_components": 150
@@ -793,6 +827,12 @@ func TestStore_SyncProjectFromJSON(t *testing.T) {
 	if summary.TechnologiesTotal != 25 {
 		t.Errorf("TechnologiesTotal = %d, want 25", summary.TechnologiesTotal)
 	}
+	if got := summary.IncompleteFeatures["code-packages"]; len(got) != 1 || got[0] != "reachability" {
+		t.Errorf("IncompleteFeatures = %v, want code-packages: [reachability]", summary.IncompleteFeatures)
+	}
+	if _, ok := summary.IncompleteFeatures["code-security"]; ok {
+		t.Error("complete scanner should not be listed as incomplete")
+	}
 
 	// Verify vulnerabilities
 	vulns, total, _ := store.GetVulnerabilities(ctx, storage.VulnOptions{ProjectID: "test/repo"})
@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Crash Override Inc. - https://crashoverride.com
+// SPDX-License-Identifier: GPL-3.0
+
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LineRange is an inclusive range of line numbers
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ChangedLines maps repo-relative file paths to the line ranges added or
+// modified on the head side of a diff. Deleted files are not included.
+type ChangedLines map[string][]LineRange
+
+// hunkHeader matches the new-file side of a unified diff hunk header
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff extracts changed head-side lines from `git diff -U0` output
+func ParseUnifiedDiff(data []byte) ChangedLines {
+	changed := make(ChangedLines)
+	var file string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = ""
+			if path := strings.TrimPrefix(line, "+++ "); path != "/dev/null" {
+				file = normalizePath(strings.TrimPrefix(path, "b/"))
+				if _, ok := changed[file]; !ok {
+					changed[file] = nil
+				}
+			}
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil || file == "" {
+				continue
+			}
+			start, _ := strconv.Atoi(m[1])
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			// A count of 0 is a pure deletion; nothing on the head side changed
+			if count > 0 {
+				changed[file] = append(changed[file], LineRange{Start: start, End: start + count - 1})
+			}
+		}
+	}
+	return changed
+}
+
+// GitChangedLines returns the lines changed between base and head in the
+// repository at repoPath. It diffs against the merge base (like a pull
+// request) and falls back to a direct diff when no merge base is available,
+// as in shallow clones.
+func GitChangedLines(ctx context.Context, repoPath, base, head string) (ChangedLines, error) {
+	out, err := gitDiff(ctx, repoPath, base+"..."+head)
+	if err != nil {
+		out, err = gitDiff(ctx, repoPath, base, head)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ParseUnifiedDiff(out), nil
+}
+
+func gitDiff(ctx context.Context, repoPath string, refs ...string) ([]byte, error) {
+	args := append([]string{"-C", repoPath, "diff", "--no-color", "--no-ext-diff", "-U0"}, refs...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git diff %s: %s", strings.Join(refs, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+	return out, nil
+}
+
+// HasFile reports whether file was added or modified
+func (c ChangedLines) HasFile(file string) bool {
+	_, ok := c[normalizePath(file)]
+	return ok
+}
+
+// Contains reports whether line of file was added or modified
+func (c ChangedLines) Contains(file string, line int) bool {
+	for _, r := range c[normalizePath(file)] {
+		if line >= r.Start && line <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// Includes reports whether a finding falls inside the diff. Findings with a
+// file and line must be on a changed line; findings with only a file (e.g.,
+// a vulnerable dependency in a manifest) must be in a changed file; findings
+// with no location (e.g., a new transitive dependency) are always included.
+func (c ChangedLines) Includes(f DeltaFinding) bool {
+	if f.File == "" {
+		return true
+	}
+	if f.Line <= 0 {
+		return c.HasFile(f.File)
+	}
+	return c.Contains(f.File, f.Line)
+}
+
+// ComputeDeltaFromResults computes the delta between two sets of scanner
+// results (scanner name -> <scanner>.json contents) that are not in history,
+// such as the base and head of a pull request
+func (c *DeltaComputer) ComputeDeltaFromResults(baseline, compare map[string]json.RawMessage, baselineRef, compareRef string) *ScanDelta {
+	delta := &ScanDelta{
+		BaselineScanID: baselineRef,
+		CompareScanID:  compareRef,
+		BaselineCommit: baselineRef,
+		CompareCommit:  compareRef,
+		GeneratedAt:    time.Now(),
+		ScannerDeltas:  make(map[string]ScannerDelta),
+	}
+
+	for _, scanner := range c.getScannersToCompare(baseline, compare) {
+		scannerDelta, err := c.computeScannerDelta(scanner, baseline[scanner], compare[scanner])
+		if err != nil {
+			continue
+		}
+		delta.ScannerDeltas[scanner] = scannerDelta
+	}
+
+	delta.Summary = c.computeSummary(delta.ScannerDeltas)
+	return delta
+}
+
+// RestrictToChanges keeps only new findings inside the changed lines, the
+// view a pull request check needs. Fixed and moved findings are dropped and
+// new findings outside the diff are counted in OutsideDiff.
+func (c *DeltaComputer) RestrictToChanges(delta *ScanDelta, changed ChangedLines) {
+	for name, sd := range delta.ScannerDeltas {
+		var kept []DeltaFinding
+		for _, f := range sd.New {
+			if changed.Includes(f) {
+				kept = append(kept, f)
+			} else {
+				sd.OutsideDiff++
+			}
+		}
+		sd.New = kept
+		sd.Fixed = nil
+		sd.Moved = nil
+		delta.ScannerDeltas[name] = sd
+	}
+	delta.Summary = c.computeSummary(delta.ScannerDeltas)
+}
+
+// CountAtOrAbove returns the number of new findings with severity at or
+// above threshold (critical, high, medium, low)
+func CountAtOrAbove(delta *ScanDelta, threshold string) int {
+	limit := severityRank(threshold)
+	count := 0
+	for _, sd := range delta.ScannerDeltas {
+		for _, f := range sd.New {
+			if severityRank(f.Severity) <= limit {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ValidSeverity reports whether s is a severity threshold name
+func ValidSeverity(s string) bool {
+	switch strings.ToLower(s) {
+	case "critical", "high", "medium", "low":
+		return true
+	}
+	return false
+}
@@ -6,6 +6,7 @@ package diff
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -140,6 +141,11 @@ func (c *DeltaComputer) computeScannerDelta(scanner string, baselineData, compar
 	results := matcher.MatchFindings(baselineFindings, compareFindings)
 	results = matcher.FilterMatches(results)
 
+	// A finding missing from a feature that did not complete in the compare
+	// scan was not looked for, so it is not fixed
+	compareIncomplete := incompleteFeatures(compareData)
+	delta.IncompleteFeatures = mergeFeatures(incompleteFeatures(baselineData), compareIncomplete)
+
 	// Categorize results
 	for _, r := range results {
 		switch r.Status {
@@ -149,6 +155,10 @@ func (c *DeltaComputer) computeScannerDelta(scanner string, baselineData, compar
 			}
 		case MatchFixed:
 			if r.OldFinding != nil {
+				if compareIncomplete[findingFeature(*r.OldFinding)] {
+					delta.NotRescanned++
+					continue
+				}
 				delta.Fixed = append(delta.Fixed, *r.OldFinding)
 			}
 		case MatchMoved:
@@ -189,7 +199,13 @@ func (c *DeltaComputer) computeSummary(scannerDeltas map[string]ScannerDelta) De
 		// Count moved and unchanged
 		summary.TotalMoved += len(sd.Moved)
 		summary.TotalUnchanged += sd.Unchanged
+		summary.TotalOutsideDiff += sd.OutsideDiff
+		summary.TotalNotRescanned += sd.NotRescanned
+		if len(sd.IncompleteFeatures) > 0 {
+			summary.PartialScanners = append(summary.PartialScanners, sd.Scanner)
+		}
 	}
+	sort.Strings(summary.PartialScanners)
 
 	// Calculate net change
 	summary.NetChange = summary.TotalNew - summary.TotalFixed
@@ -200,6 +216,55 @@ func (c *DeltaComputer) computeSummary(scannerDeltas map[string]ScannerDelta) De
 	return summary
 }
 
+// incompleteFeatures returns the features that did not complete in a
+// partial scanner result
+func incompleteFeatures(data json.RawMessage) map[string]bool {
+	if len(data) == 0 {
+		return nil
+	}
+	var result struct {
+		Partial       bool `json:"partial"`
+		FeatureStatus map[string]struct {
+			Status string `json:"status"`
+		} `json:"feature_status"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || !result.Partial {
+		return nil
+	}
+	features := make(map[string]bool)
+	for name, st := range result.FeatureStatus {
+		if st.Status != "complete" {
+			features[name] = true
+		}
+	}
+	return features
+}
+
+// mergeFeatures returns the sorted union of feature sets
+func mergeFeatures(sets ...map[string]bool) []string {
+	seen := make(map[string]bool)
+	var features []string
+	for _, set := range sets {
+		for name := range set {
+			if !seen[name] {
+				seen[name] = true
+				features = append(features, name)
+			}
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
+// findingFeature returns the feature a finding came from, taken from its
+// "<scanner>/<feature>" fingerprint
+func findingFeature(f DeltaFinding) string {
+	if i := strings.LastIndex(f.Fingerprint.Scanner, "/"); i >= 0 {
+		return f.Fingerprint.Scanner[i+1:]
+	}
+	return ""
+}
+
 // countBySeverity increments the appropriate severity counter
 func (c *DeltaComputer) countBySeverity(severity string, critical, high, medium, low *int) {
 	switch strings.ToLower(severity) {
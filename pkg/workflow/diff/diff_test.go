@@ -75,6 +75,32 @@ func TestFingerprintGenerationCodeSecurity(t *testing.T) {
 	}
 }
 
+func TestFingerprintFinding(t *testing.T) {
+	gen := NewFingerprintGenerator()
+
+	raw := json.RawMessage(`{"rule_id": "G401", "title": "Weak crypto", "severity": "high", "file": "./crypto/cipher.go", "line": 42}`)
+	fp, ok := gen.FingerprintFinding("code-security", "vulns", raw)
+	if !ok {
+		t.Fatal("FingerprintFinding returned no fingerprint")
+	}
+	if fp.Scanner != "code-security/vulns" {
+		t.Errorf("Scanner = %q, want code-security/vulns", fp.Scanner)
+	}
+	if fp.PrimaryKey != "G401:crypto/cipher.go" {
+		t.Errorf("PrimaryKey = %q, want G401:crypto/cipher.go", fp.PrimaryKey)
+	}
+
+	// Crypto features live in code-security since the scanners were merged
+	cipher := json.RawMessage(`{"algorithm": "DES", "severity": "high", "file": "enc.go", "line": 3}`)
+	if fp, ok := gen.FingerprintFinding("code-security", "ciphers", cipher); !ok || fp.Scanner != "crypto/ciphers" {
+		t.Errorf("expected crypto/ciphers fingerprint from code-security, got %+v (ok=%v)", fp, ok)
+	}
+
+	if _, ok := gen.FingerprintFinding("unknown-scanner", "vulns", raw); ok {
+		t.Error("expected no fingerprint for unknown scanner")
+	}
+}
+
 func TestFingerprintGenerationPackageAnalysis(t *testing.T) {
 	gen := NewFingerprintGenerator()
 
@@ -807,3 +833,171 @@ func TestParseLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseUnifiedDiff(t *testing.T) {
+	data := []byte(`diff --git a/app/handler.go b/app/handler.go
+index 1111111..2222222 100644
+--- a/app/handler.go
++++ b/app/handler.go
+@@ -10,0 +11,3 @@ func handle() {
++	a := 1
++	b := 2
++	c := 3
+@@ -40 +43 @@ func other() {
+-	old()
++	new()
+@@ -60,2 +62,0 @@ func gone() {
+-	x()
+-	y()
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package main
++
+diff --git a/removed.go b/removed.go
+deleted file mode 100644
+--- a/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-
+`)
+
+	changed := ParseUnifiedDiff(data)
+
+	tests := []struct {
+		file string
+		line int
+		want bool
+	}{
+		{"app/handler.go", 10, false},
+		{"app/handler.go", 11, true},
+		{"app/handler.go", 13, true},
+		{"app/handler.go", 14, false},
+		{"app/handler.go", 43, true},
+		{"app/handler.go", 62, false},
+		{"new.go", 1, true},
+		{"new.go", 2, true},
+		{"removed.go", 1, false},
+	}
+	for _, tt := range tests {
+		if got := changed.Contains(tt.file, tt.line); got != tt.want {
+			t.Errorf("Contains(%s, %d) = %v, want %v", tt.file, tt.line, got, tt.want)
+		}
+	}
+
+	if changed.HasFile("removed.go") {
+		t.Error("deleted file should not be in changed lines")
+	}
+	if !changed.HasFile("./app/handler.go") {
+		t.Error("HasFile should normalize paths")
+	}
+}
+
+func TestChangedLinesIncludes(t *testing.T) {
+	changed := ChangedLines{"go.mod": nil, "main.go": {{Start: 5, End: 8}}}
+
+	tests := []struct {
+		name    string
+		finding DeltaFinding
+		want    bool
+	}{
+		{"on changed line", DeltaFinding{File: "main.go", Line: 6}, true},
+		{"outside hunk", DeltaFinding{File: "main.go", Line: 20}, false},
+		{"unchanged file", DeltaFinding{File: "util.go", Line: 6}, false},
+		{"file only, changed", DeltaFinding{File: "go.mod"}, true},
+		{"file only, unchanged", DeltaFinding{File: "package.json"}, false},
+		{"no location", DeltaFinding{Message: "new transitive dependency"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changed.Includes(tt.finding); got != tt.want {
+				t.Errorf("Includes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeDeltaFromResultsRestrictToChanges(t *testing.T) {
+	baseline := map[string]json.RawMessage{
+		"code-security": json.RawMessage(`{"findings": {"vulns": [
+			{"rule_id": "G101", "title": "Hardcoded credentials", "severity": "high", "file": "auth.go", "line": 10},
+			{"rule_id": "G201", "title": "SQL string formatting", "severity": "medium", "file": "db.go", "line": 30}
+		]}}`),
+	}
+	compare := map[string]json.RawMessage{
+		"code-security": json.RawMessage(`{"findings": {"vulns": [
+			{"rule_id": "G101", "title": "Hardcoded credentials", "severity": "high", "file": "auth.go", "line": 10},
+			{"rule_id": "G401", "title": "Use of weak cryptographic primitive", "severity": "critical", "file": "crypto.go", "line": 7},
+			{"rule_id": "G304", "title": "File path from variable", "severity": "low", "file": "io.go", "line": 3},
+			{"rule_id": "G402", "title": "TLS InsecureSkipVerify", "severity": "high", "file": "legacy.go", "line": 90}
+		]}}`),
+	}
+
+	computer := NewDeltaComputer(nil, DefaultDiffOptions())
+	delta := computer.ComputeDeltaFromResults(baseline, compare, "aaaaaaa", "bbbbbbb")
+
+	if delta.Summary.TotalNew != 3 || delta.Summary.TotalFixed != 1 {
+		t.Fatalf("before restrict: new = %d, fixed = %d, want 3, 1", delta.Summary.TotalNew, delta.Summary.TotalFixed)
+	}
+
+	// legacy.go was not touched, so its finding predates the change
+	changed := ChangedLines{"crypto.go": {{Start: 5, End: 9}}, "io.go": {{Start: 1, End: 4}}}
+	computer.RestrictToChanges(delta, changed)
+
+	if delta.Summary.TotalNew != 2 {
+		t.Errorf("TotalNew = %d, want 2", delta.Summary.TotalNew)
+	}
+	if delta.Summary.TotalFixed != 0 {
+		t.Errorf("TotalFixed = %d, want 0", delta.Summary.TotalFixed)
+	}
+	if delta.Summary.TotalOutsideDiff != 1 {
+		t.Errorf("TotalOutsideDiff = %d, want 1", delta.Summary.TotalOutsideDiff)
+	}
+
+	if got := CountAtOrAbove(delta, "high"); got != 1 {
+		t.Errorf("CountAtOrAbove(high) = %d, want 1", got)
+	}
+	if got := CountAtOrAbove(delta, "low"); got != 2 {
+		t.Errorf("CountAtOrAbove(low) = %d, want 2", got)
+	}
+	if got := CountAtOrAbove(delta, "critical"); got != 1 {
+		t.Errorf("CountAtOrAbove(critical) = %d, want 1", got)
+	}
+}
+
+func TestComputeDeltaFromResultsPartialCompare(t *testing.T) {
+	baseline := map[string]json.RawMessage{
+		"code-security": json.RawMessage(`{"findings": {
+			"vulns": [{"rule_id": "G201", "title": "SQL string formatting", "severity": "medium", "file": "db.go", "line": 30}],
+			"secrets": [{"rule_id": "aws-key", "type": "aws_access_key", "severity": "critical", "file": "config.go", "line": 4}]
+		}}`),
+	}
+	// The secrets feature timed out in the compare scan, so its finding is
+	// missing without having been fixed
+	compare := map[string]json.RawMessage{
+		"code-security": json.RawMessage(`{
+			"partial": true,
+			"feature_status": {"vulns": {"status": "complete"}, "secrets": {"status": "timeout"}},
+			"findings": {"vulns": []}
+		}`),
+	}
+
+	computer := NewDeltaComputer(nil, DefaultDiffOptions())
+	delta := computer.ComputeDeltaFromResults(baseline, compare, "aaaaaaa", "bbbbbbb")
+
+	if delta.Summary.TotalFixed != 1 {
+		t.Errorf("TotalFixed = %d, want 1 (only the vulns finding)", delta.Summary.TotalFixed)
+	}
+	if delta.Summary.TotalNotRescanned != 1 {
+		t.Errorf("TotalNotRescanned = %d, want 1", delta.Summary.TotalNotRescanned)
+	}
+	if got := delta.Summary.PartialScanners; len(got) != 1 || got[0] != "code-security" {
+		t.Errorf("PartialScanners = %v", got)
+	}
+	if got := delta.ScannerDeltas["code-security"].IncompleteFeatures; len(got) != 1 || got[0] != "secrets" {
+		t.Errorf("IncompleteFeatures = %v", got)
+	}
+}
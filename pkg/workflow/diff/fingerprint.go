@@ -32,7 +32,7 @@ func (g *FingerprintGenerator) FingerprintFindings(scanner string, data json.Raw
 	switch scanner {
 	case "code-security":
 		return g.fingerprintCodeSecurity(result.Findings)
-	case "package-analysis":
+	case "package-analysis", "code-packages":
 		return g.fingerprintPackageAnalysis(result.Findings)
 	case "crypto":
 		return g.fingerprintCrypto(result.Findings)
@@ -46,6 +46,24 @@ func (g *FingerprintGenerator) FingerprintFindings(scanner string, data json.Raw
 	}
 }
 
+// FingerprintFinding generates the fingerprint for a single finding of a scanner feature
+// (e.g. scanner "code-security", feature "secrets"). The finding must be the raw JSON
+// object as written by the scanner so the fingerprint matches the one used by diff.
+func (g *FingerprintGenerator) FingerprintFinding(scanner, feature string, finding json.RawMessage) (FindingFingerprint, bool) {
+	wrapped, err := json.Marshal(map[string]map[string][]json.RawMessage{
+		"findings": {feature: {finding}},
+	})
+	if err != nil {
+		return FindingFingerprint{}, false
+	}
+
+	fps, err := g.FingerprintFindings(scanner, wrapped)
+	if err != nil || len(fps) == 0 {
+		return FindingFingerprint{}, false
+	}
+	return fps[0].Fingerprint, true
+}
+
 // FingerprintedFinding combines a fingerprint with the original finding data
 type FingerprintedFinding struct {
 	Fingerprint FindingFingerprint `json:"fingerprint"`
@@ -179,6 +197,16 @@ func (g *FingerprintGenerator) fingerprintCodeSecurity(data json.RawMessage) ([]
 		})
 	}
 
+	// Crypto features were merged into code-security (ciphers, keys, random, tls)
+	cryptoFindings, err := g.fingerprintCrypto(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range cryptoFindings {
+		f.Scanner = "code-security"
+		result = append(result, f)
+	}
+
 	return result, nil
 }
 
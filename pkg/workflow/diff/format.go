@@ -64,6 +64,9 @@ func (f *Formatter) formatSummary(delta *ScanDelta) error {
 		f.formatNetChange(delta.Summary.NetChange),
 		trend,
 	)
+	if len(delta.Summary.PartialScanners) > 0 {
+		fmt.Fprintf(f.writer, "  partial results: %s\n", strings.Join(delta.Summary.PartialScanners, ", "))
+	}
 	return nil
 }
 
@@ -89,11 +92,27 @@ func (f *Formatter) formatTable(delta *ScanDelta) error {
 	if delta.Summary.TotalMoved > 0 {
 		fmt.Fprintf(f.writer, "  ├─ Moved:            %d\n", delta.Summary.TotalMoved)
 	}
+	if delta.Summary.TotalOutsideDiff > 0 {
+		fmt.Fprintf(f.writer, "  ├─ Outside diff:     %d\n", delta.Summary.TotalOutsideDiff)
+	}
+	if delta.Summary.TotalNotRescanned > 0 {
+		fmt.Fprintf(f.writer, "  ├─ Not rescanned:    %d\n", delta.Summary.TotalNotRescanned)
+	}
 	fmt.Fprintf(f.writer, "  └─ Net change:       %s %s\n",
 		f.formatNetChange(delta.Summary.NetChange),
 		f.formatRiskTrend(delta.Summary.RiskTrend),
 	)
 
+	// Partial results make the counts above incomplete
+	if len(delta.Summary.PartialScanners) > 0 {
+		fmt.Fprintln(f.writer)
+		fmt.Fprintf(f.writer, "  %s\n", f.colorize("PARTIAL RESULTS", colorYellow))
+		for _, scanner := range delta.Summary.PartialScanners {
+			fmt.Fprintf(f.writer, "  • %s: %s did not complete\n",
+				scanner, strings.Join(delta.ScannerDeltas[scanner].IncompleteFeatures, ", "))
+		}
+	}
+
 	// By scanner breakdown
 	if len(delta.ScannerDeltas) > 0 {
 		fmt.Fprintln(f.writer)
@@ -66,10 +66,15 @@ type ScanDelta struct {
 
 // DeltaSummary provides an overview of changes between scans
 type DeltaSummary struct {
-	TotalNew       int    `json:"total_new"`
-	TotalFixed     int    `json:"total_fixed"`
-	TotalUnchanged int    `json:"total_unchanged"`
-	TotalMoved     int    `json:"total_moved"`
+	TotalNew          int `json:"total_new"`
+	TotalFixed        int `json:"total_fixed"`
+	TotalUnchanged    int `json:"total_unchanged"`
+	TotalMoved        int `json:"total_moved"`
+	TotalOutsideDiff  int `json:"total_outside_diff,omitempty"`  // Diff-mode scans only
+	TotalNotRescanned int `json:"total_not_rescanned,omitempty"` // Fixed findings of incomplete features
+
+	// PartialScanners lists scanners with partial results in either scan
+	PartialScanners []string `json:"partial_scanners,omitempty"`
 
 	// By severity
 	NewCritical   int `json:"new_critical"`
@@ -94,6 +99,16 @@ type ScannerDelta struct {
 	Fixed     []DeltaFinding `json:"fixed,omitempty"`
 	Moved     []MovedFinding `json:"moved,omitempty"`
 	Unchanged int            `json:"unchanged"`
+
+	// OutsideDiff counts new findings dropped because they are not on
+	// lines changed between the refs (diff-mode scans only)
+	OutsideDiff int `json:"outside_diff,omitempty"`
+
+	// IncompleteFeatures lists features with partial results in either scan.
+	// Findings of a feature that did not complete in the compare scan are not
+	// reported as fixed; NotRescanned counts them.
+	IncompleteFeatures []string `json:"incomplete_features,omitempty"`
+	NotRescanned       int      `json:"not_rescanned,omitempty"`
 }
 
 // DeltaFinding represents a finding that changed between scans
@@ -0,0 +1,211 @@
+package hydrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+	"github.com/crashappsec/zero/pkg/scanner"
+	"github.com/crashappsec/zero/pkg/workflow/diff"
+)
+
+// DiffScanOptions configures a diff-mode scan of two refs
+type DiffScanOptions struct {
+	RepoPath string           // Git work tree containing both refs
+	Base     string           // Base ref (e.g., main, origin/main, a commit)
+	Head     string           // Head ref (default: HEAD)
+	OnStatus func(msg string) // Progress messages (optional)
+}
+
+// DiffScanResult holds the findings introduced between two refs
+type DiffScanResult struct {
+	BaseCommit string
+	HeadCommit string
+	Changed    diff.ChangedLines
+	Delta      *diff.ScanDelta
+	Failed     []string // "<side>/<scanner>" for scanners that did not complete
+}
+
+// ScanDiff scans the base and head refs in temporary worktrees, compares the
+// results with diff fingerprints and fuzzy matching, and keeps only new
+// findings on lines changed between the refs. Unchanged commits are served
+// from the scanner result cache, so re-running a check is cheap.
+func (h *Hydrate) ScanDiff(ctx context.Context, opts DiffScanOptions) (*DiffScanResult, error) {
+	status := func(format string, args ...interface{}) {
+		if opts.OnStatus != nil {
+			opts.OnStatus(fmt.Sprintf(format, args...))
+		}
+	}
+	if opts.Head == "" {
+		opts.Head = "HEAD"
+	}
+
+	baseCommit, err := resolveCommit(ctx, opts.RepoPath, opts.Base)
+	if err != nil {
+		return nil, fmt.Errorf("resolving base %q: %w", opts.Base, err)
+	}
+	headCommit, err := resolveCommit(ctx, opts.RepoPath, opts.Head)
+	if err != nil {
+		return nil, fmt.Errorf("resolving head %q: %w", opts.Head, err)
+	}
+
+	changed, err := diff.GitChangedLines(ctx, opts.RepoPath, baseCommit, headCommit)
+	if err != nil {
+		return nil, err
+	}
+	result := &DiffScanResult{BaseCommit: baseCommit, HeadCommit: headCommit, Changed: changed}
+
+	scanners, err := h.cfg.GetProfileScanners(h.opts.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("getting scanners: %w", err)
+	}
+	scanners = filterOutSkipped(scanners, h.opts.SkipScanners)
+	scannerList, err := scanner.GetByNames(scanners)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "zero-diff-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sides := []struct {
+		name   string
+		commit string
+	}{
+		{"base", baseCommit},
+		{"head", headCommit},
+	}
+	results := make(map[string]map[string]json.RawMessage)
+	for _, side := range sides {
+		status("Scanning %s (%s)...", side.name, shortCommit(side.commit))
+
+		worktree := filepath.Join(tmpDir, side.name)
+		if err := addWorktree(ctx, opts.RepoPath, worktree, side.commit); err != nil {
+			return nil, err
+		}
+		defer removeWorktree(opts.RepoPath, worktree)
+
+		outputDir := filepath.Join(tmpDir, side.name+"-analysis")
+		run, err := h.runner.RunScanners(ctx, scanner.RunOptions{
+			RepoPath:       worktree,
+			OutputDir:      outputDir,
+			Scanners:       scannerList,
+			Parallel:       h.opts.ParallelScanners,
+			Timeout:        time.Duration(h.cfg.Settings.ScannerTimeoutSeconds) * time.Second,
+			FeatureConfigs: h.loadFeatureConfigs(scanners),
+			RepoMetadata: &scanner.RepoMetadata{
+				CommitSHA:      side.commit,
+				ScanProfile:    h.opts.Profile,
+				ScannerVersion: h.cfg.Version,
+			},
+			NoCache: h.opts.Force,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", side.name, err)
+		}
+		for name, r := range run.Results {
+			if r.Status != scanner.StatusComplete {
+				result.Failed = append(result.Failed, side.name+"/"+name)
+			}
+		}
+		results[side.name] = loadResults(outputDir, scanners)
+	}
+
+	computer := diff.NewDeltaComputer(nil, diff.DefaultDiffOptions())
+	result.Delta = computer.ComputeDeltaFromResults(results["base"], results["head"], shortCommit(baseCommit), shortCommit(headCommit))
+	computer.RestrictToChanges(result.Delta, changed)
+	return result, nil
+}
+
+// DiffScanRepoPath returns the git work tree for a diff-mode scan target:
+// a local checkout is used in place and owner/repo resolves to its hydrated
+// clone. Remote git URLs must be hydrated first.
+func DiffScanRepoPath(zeroHome, target string) (string, error) {
+	if IsSourceTarget(target) {
+		src, err := ParseSource(target)
+		if err != nil {
+			return "", err
+		}
+		if src.Path == "" {
+			return "", fmt.Errorf("diff mode needs a local checkout: hydrate %s first and scan %s", target, src.ProjectID())
+		}
+		if !isGitWorkTree(src.Path) {
+			return "", fmt.Errorf("diff mode needs a git checkout: %s is not a git work tree", target)
+		}
+		return src.Path, nil
+	}
+
+	repoPath := filepath.Join(zeroHome, "repos", github.ProjectID(target), "repo")
+	if !isGitWorkTree(repoPath) {
+		return "", fmt.Errorf("repo not found: %s (run hydrate first)", target)
+	}
+	return repoPath, nil
+}
+
+// resolveCommit resolves ref to a commit SHA. Branch names that only exist
+// on origin (as in fresh clones) are tried as origin/<ref>, and a missing
+// ref is fetched from origin as a last resort.
+func resolveCommit(ctx context.Context, repoPath, ref string) (string, error) {
+	for _, candidate := range []string{ref, "origin/" + ref} {
+		if sha, err := revParse(ctx, repoPath, candidate); err == nil {
+			return sha, nil
+		}
+	}
+
+	fetch := exec.CommandContext(ctx, "git", "-C", repoPath, "fetch", "--quiet", "origin", ref)
+	if out, err := fetch.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unknown ref and fetch failed: %s", strings.TrimSpace(string(out)))
+	}
+	return revParse(ctx, repoPath, "FETCH_HEAD")
+}
+
+func revParse(ctx context.Context, repoPath, ref string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown ref %s", ref)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// addWorktree checks out commit into a detached worktree at path
+func addWorktree(ctx context.Context, repoPath, path, commit string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "worktree", "add", "--detach", "--force", path, commit)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("checking out %s: %s", shortCommit(commit), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// removeWorktree removes a worktree and its administrative files
+func removeWorktree(repoPath, path string) {
+	_ = exec.Command("git", "-C", repoPath, "worktree", "remove", "--force", path).Run()
+	_ = exec.Command("git", "-C", repoPath, "worktree", "prune").Run()
+}
+
+// loadResults reads <scanner>.json for each scanner from outputDir
+func loadResults(outputDir string, scanners []string) map[string]json.RawMessage {
+	results := make(map[string]json.RawMessage)
+	for _, name := range scanners {
+		data, err := os.ReadFile(filepath.Join(outputDir, name+".json"))
+		if err == nil {
+			results[name] = data
+		}
+	}
+	return results
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
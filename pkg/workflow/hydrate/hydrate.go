@@ -14,7 +14,9 @@ import (
 	"time"
 
 	"github.com/crashappsec/zero/pkg/core/config"
+	"github.com/crashappsec/zero/pkg/core/forge"
 	"github.com/crashappsec/zero/pkg/workflow/diff"
+	"github.com/crashappsec/zero/pkg/workflow/freshness"
 	"github.com/crashappsec/zero/pkg/core/github"
 	"github.com/crashappsec/zero/pkg/core/languages"
 	"github.com/crashappsec/zero/pkg/scanner"
@@ -24,8 +26,11 @@ import (
 // Options configures the hydrate command
 type Options struct {
 	// Target
-	Org   string // GitHub organization
-	Repo  string // Single repo (owner/repo)
+	Org    string // GitHub organization
+	Repo   string // Single repo (owner/repo)
+	Source string // Local directory or git URL (any remote, including file://)
+	Forge    string // Forge type for org/repo targets: github (default), gitlab, gitea
+	ForgeURL string // Forge base URL for self-hosted instances
 	Limit int    // Max repos in org mode
 	Demo  bool   // Demo mode: skip repos > 50MB, fetch replacements
 
@@ -36,7 +41,7 @@ type Options struct {
 
 	// Scan options
 	Profile          string   // Scan profile
-	Force            bool     // Re-scan even if exists
+	Force            bool     // Re-scan even if cached results exist
 	SkipSlow         bool     // Skip slow scanners
 	Yes              bool     // Auto-accept prompts
 	ParallelRepos    int      // Parallel repo processing (default: 1)
@@ -47,12 +52,14 @@ type Options struct {
 // RepoStatus tracks the status of a repo being processed
 type RepoStatus struct {
 	Repo      github.Repository
+	Source    *Source // Set for local directories and non-GitHub git URLs
 	RepoPath  string
 	FileCount int
 	CloneOK   bool
 	ScanOK    bool
 	Progress  *scanner.Progress
 	Duration  time.Duration
+	Digest    string // Content digest recorded in place of a commit (standalone SBOMs)
 
 	// SBOM stats (populated after scan)
 	SBOMPackages int    // Number of packages in SBOM
@@ -85,9 +92,10 @@ type RepoStatus struct {
 type Hydrate struct {
 	cfg      *config.Config
 	term     *terminal.Terminal
-	gh       *github.Client
+	forge    forge.Forge
 	runner   *scanner.NativeRunner
 	opts     *Options
+	source   *Source
 	zeroHome string
 }
 
@@ -102,6 +110,11 @@ func New(opts *Options) (*Hydrate, error) {
 	if zeroHome == "" {
 		zeroHome = ".zero"
 	}
+	// Diff scans run scanners in temporary worktrees, so pin the storage
+	// path rather than leave it relative to wherever a scanner runs
+	if abs, err := filepath.Abs(zeroHome); err == nil {
+		zeroHome = abs
+	}
 
 	if opts.ParallelRepos == 0 {
 		opts.ParallelRepos = cfg.Settings.ParallelRepos
@@ -110,16 +123,30 @@ func New(opts *Options) (*Hydrate, error) {
 		opts.ParallelScanners = cfg.Settings.ParallelScanners
 	}
 
+	forge.SetHosts(cfg.Settings.Forges)
+	f, err := forge.Open(forge.Kind(opts.Forge), opts.ForgeURL)
+	if err != nil {
+		return nil, err
+	}
+
+	runner := scanner.NewNativeRunner(zeroHome)
+	runner.Cache.MaxAge = time.Duration(cfg.Settings.CacheTTLHours) * time.Hour
+
 	return &Hydrate{
 		cfg:      cfg,
 		term:     terminal.New(),
-		gh:       github.NewClient(),
-		runner:   scanner.NewNativeRunner(zeroHome),
+		forge:    f,
+		runner:   runner,
 		opts:     opts,
 		zeroHome: zeroHome,
 	}, nil
 }
 
+// ZeroHome returns the storage path hydrate reads and writes
+func (h *Hydrate) ZeroHome() string {
+	return h.zeroHome
+}
+
 // ProgressCallback is called when scanner progress changes
 type ProgressCallback func(scanner string, status scanner.Status, summary string)
 
@@ -136,25 +163,40 @@ func (h *Hydrate) Run(ctx context.Context) ([]string, error) {
 	var repos []github.Repository
 	var targetName string
 
-	// Single repo or org mode
-	if h.opts.Repo != "" {
+	// Source, single repo or org mode
+	if h.opts.Source != "" {
+		// Source mode: local directory or arbitrary git URL
+		src, err := ParseSource(h.opts.Source)
+		if err != nil {
+			return nil, err
+		}
+		h.source = src
+		targetName = src.ProjectID()
+		h.term.Info("Hydrating %s...", h.term.Color(terminal.Cyan, src.Location()))
+
+		repos = []github.Repository{src.Repository()}
+	} else if h.opts.Repo != "" {
 		// Single repo mode
 		targetName = h.opts.Repo
 		h.term.Info("Hydrating %s...", h.term.Color(terminal.Cyan, h.opts.Repo))
 
-		// Parse owner/repo
+		// Parse owner/repo. GitLab projects can sit in nested groups
+		// (group/subgroup/project); like group listings, they are stored
+		// under their immediate namespace.
 		parts := strings.Split(h.opts.Repo, "/")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid repo format: use owner/repo")
+		if len(parts) < 2 || (len(parts) > 2 && h.forge.Kind() != forge.KindGitLab) {
+			return nil, fmt.Errorf("invalid repo format: use owner/repo (or group/subgroup/project on GitLab)")
 		}
+		owner, name := parts[len(parts)-2], parts[len(parts)-1]
 
 		repos = []github.Repository{{
-			Name:          parts[1],
-			NameWithOwner: h.opts.Repo,
-			Owner:         parts[0],
+			Name:          name,
+			NameWithOwner: owner + "/" + name,
+			Owner:         owner,
 			DefaultBranch: h.opts.Branch,
-			SSHURL:        fmt.Sprintf("git@github.com:%s.git", h.opts.Repo),
-			CloneURL:      fmt.Sprintf("https://github.com/%s.git", h.opts.Repo),
+			FullName:      h.opts.Repo,
+			CloneURL:      h.forge.CloneURL(h.opts.Repo),
+			SSHURL:        forge.SSHURL(h.forge, h.opts.Repo),
 		}}
 	} else {
 		// Org mode
@@ -168,7 +210,7 @@ func (h *Hydrate) Run(ctx context.Context) ([]string, error) {
 		}
 
 		var err error
-		allRepos, err := h.gh.ListOrgRepos(h.opts.Org, fetchLimit)
+		allRepos, err := h.forge.ListOrgRepos(ctx, h.opts.Org, fetchLimit)
 		if err != nil {
 			return nil, fmt.Errorf("listing repos: %w", err)
 		}
@@ -215,6 +257,9 @@ func (h *Hydrate) Run(ctx context.Context) ([]string, error) {
 	h.term.Divider()
 	if h.opts.Org != "" {
 		h.term.Info("%s %s", h.term.Color(terminal.Bold, "Hydrate Organization:"), h.term.Color(terminal.Cyan, h.opts.Org))
+	} else if h.source != nil {
+		h.term.Info("%s %s", h.term.Color(terminal.Bold, "Hydrate Source:"), h.term.Color(terminal.Cyan, h.source.Location()))
+		h.term.Info("Project:      %s", h.term.Color(terminal.Cyan, targetName))
 	} else {
 		h.term.Info("%s %s", h.term.Color(terminal.Bold, "Hydrate Repository:"), h.term.Color(terminal.Cyan, h.opts.Repo))
 	}
@@ -243,6 +288,11 @@ func (h *Hydrate) Run(ctx context.Context) ([]string, error) {
 		}
 	}
 
+	// A single local or git URL target has nothing to scan if its import failed
+	if h.source != nil && len(projectIDs) == 0 {
+		return nil, fmt.Errorf("importing %s failed", h.source.Location())
+	}
+
 	// Stop here if clone-only
 	if h.opts.CloneOnly {
 		h.term.Divider()
@@ -273,6 +323,9 @@ func (h *Hydrate) Run(ctx context.Context) ([]string, error) {
 	// Preserve scan history for diff/delta tracking
 	h.preserveHistory(repoStatuses, runningScanners, scanID, start)
 
+	// Record freshness metadata
+	h.recordFreshness(repoStatuses)
+
 	// Print summary
 	duration := int(time.Since(start).Seconds())
 	diskUsage := h.getDiskUsage()
@@ -291,7 +344,7 @@ func (h *Hydrate) cloneRepos(ctx context.Context, repos []github.Repository) ([]
 	sem := make(chan struct{}, h.opts.ParallelRepos)
 
 	for i, repo := range repos {
-		statuses[i] = &RepoStatus{Repo: repo}
+		statuses[i] = &RepoStatus{Repo: repo, Source: h.source}
 	}
 
 	for i := range repos {
@@ -317,6 +370,11 @@ func (h *Hydrate) cloneRepo(ctx context.Context, status *RepoStatus) {
 	repoPath := filepath.Join(h.zeroHome, "repos", projectID, "repo")
 	status.RepoPath = repoPath
 
+	if status.Source != nil {
+		h.cloneSource(ctx, status)
+		return
+	}
+
 	// Check if already cloned (must have .git directory to be valid)
 	gitDir := filepath.Join(repoPath, ".git")
 	if _, err := os.Stat(gitDir); err == nil {
@@ -357,30 +415,35 @@ func (h *Hydrate) cloneRepo(ctx context.Context, status *RepoStatus) {
 	cloneURL := repo.CloneURL
 	if cloneURL == "" && repo.NameWithOwner != "" {
 		// Build HTTPS URL from nameWithOwner
-		cloneURL = fmt.Sprintf("https://github.com/%s.git", repo.NameWithOwner)
+		cloneURL = h.forge.CloneURL(repo.NameWithOwner)
 	}
 	if cloneURL == "" {
 		// Fallback to SSH URL if HTTPS is unavailable
 		cloneURL = repo.SSHURL
 	}
 
-	// Determine clone depth - use configured depth, profile default, or 1
-	cloneDepth := 1
-	if h.opts.Depth > 0 {
-		cloneDepth = h.opts.Depth
-	} else if h.needsDeepHistory() {
-		// Profiles that need git history for ownership analysis
-		cloneDepth = 100 // Enough commits for 90-day analysis
-	}
-
-	cloneArgs := []string{"clone", "--depth", fmt.Sprintf("%d", cloneDepth), cloneURL, repoPath}
+	cloneArgs := []string{"clone", "--depth", fmt.Sprintf("%d", h.cloneDepth()), cloneURL, repoPath}
 	cmd := exec.CommandContext(ctx, "git", cloneArgs...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
+	sshFallback := repo.SSHURL != "" && repo.SSHURL != cloneURL
+	if sshFallback {
+		// Fail instead of prompting for credentials, so SSH keys get a turn
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	}
 
 	if err := cmd.Run(); err != nil {
-		h.term.Error("%s clone failed: %v", repo.Name, err)
-		return
+		if !sshFallback || ctx.Err() != nil {
+			h.term.Error("%s clone failed: %v", repo.Name, err)
+			return
+		}
+		// HTTPS needs a token for private repos; SSH uses the user's keys
+		os.RemoveAll(repoPath)
+		cloneArgs[len(cloneArgs)-2] = repo.SSHURL
+		if err := exec.CommandContext(ctx, "git", cloneArgs...).Run(); err != nil {
+			h.term.Error("%s clone failed: %v", repo.Name, err)
+			return
+		}
 	}
 
 	status.CloneOK = true
@@ -401,6 +464,66 @@ func (h *Hydrate) cloneRepo(ctx context.Context, status *RepoStatus) {
 	)
 }
 
+// cloneDepth returns the clone depth - configured depth, profile default, or 1
+func (h *Hydrate) cloneDepth() int {
+	if h.opts.Depth > 0 {
+		return h.opts.Depth
+	}
+	if h.needsDeepHistory() {
+		// Profiles that need git history for ownership analysis
+		return 100 // Enough commits for 90-day analysis
+	}
+	return 1
+}
+
+// cloneSource imports a local directory or non-GitHub git URL
+func (h *Hydrate) cloneSource(ctx context.Context, status *RepoStatus) {
+	repo := status.Repo
+
+	if err := os.MkdirAll(filepath.Dir(status.RepoPath), 0755); err != nil {
+		h.term.Error("%s import failed: %v", repo.Name, err)
+		return
+	}
+
+	state, err := h.importSource(ctx, status.Source, status.RepoPath, h.cloneDepth())
+	if err != nil {
+		h.term.Error("%s import failed: %v", repo.Name, err)
+		return
+	}
+
+	// Local checkouts are cloned, so only committed changes are scanned
+	if src := status.Source; src.Kind == SourceGitURL && src.Path != "" && hasUncommittedChanges(ctx, src.Path) {
+		h.term.Warning("%s has uncommitted changes that will not be scanned; commit them to include them", src.Path)
+	}
+
+	status.CloneOK = true
+	status.FileCount = h.countFiles(status.RepoPath)
+
+	// Detect and cache languages (available to all scanners)
+	h.detectAndCacheLanguages(repo, status.RepoPath)
+
+	h.term.RepoCloned(
+		repo.Name,
+		h.getRepoSize(status.RepoPath),
+		formatNumber(status.FileCount),
+		h.getCommitHash(status.RepoPath),
+		state,
+	)
+}
+
+// repoURL returns the URL recorded in scanner evidence for a repo
+func (h *Hydrate) repoURL(status *RepoStatus) string {
+	if status.Source != nil {
+		return status.Source.Location()
+	}
+	// FullName keeps nested GitLab groups that NameWithOwner flattens
+	fullName := status.Repo.FullName
+	if fullName == "" {
+		fullName = status.Repo.NameWithOwner
+	}
+	return fmt.Sprintf("%s/%s", h.forge.BaseURL(), fullName)
+}
+
 // scanRepos scans all repositories sequentially with live progress
 func (h *Hydrate) scanRepos(ctx context.Context, statuses []*RepoStatus, scanners, skipScanners []string) (success, failed int) {
 	// Use parallel scanning if configured and multiple repos
@@ -544,7 +667,7 @@ func (h *Hydrate) scanRepoSimple(ctx context.Context, status *RepoStatus, scanne
 	repoMetadata := &scanner.RepoMetadata{
 		GitHubOrg:      status.Repo.Owner,
 		GitHubRepo:     status.Repo.Name,
-		RepoURL:        fmt.Sprintf("https://github.com/%s", status.Repo.NameWithOwner),
+		RepoURL:        h.repoURL(status),
 		CommitSHA:      h.getFullCommitHash(status.RepoPath),
 		Branch:         h.getCurrentBranch(status.RepoPath),
 		ScanProfile:    h.opts.Profile,
@@ -561,6 +684,7 @@ func (h *Hydrate) scanRepoSimple(ctx context.Context, status *RepoStatus, scanne
 		Timeout:        time.Duration(h.cfg.Settings.ScannerTimeoutSeconds) * time.Second,
 		FeatureConfigs: featureConfigs,
 		RepoMetadata:   repoMetadata,
+		NoCache:        h.opts.Force,
 	}
 
 	result, err := h.runner.RunScanners(ctx, opts)
@@ -581,6 +705,7 @@ func (h *Hydrate) scanRepoSimple(ctx context.Context, status *RepoStatus, scanne
 			status.Progress.Results[name].Status = r.Status
 			status.Progress.Results[name].Summary = r.Summary
 			status.Progress.Results[name].Duration = r.Duration
+			status.Progress.Results[name].Partial = r.Partial
 		}
 	}
 
@@ -790,7 +915,7 @@ func (h *Hydrate) scanRepoWithProgress(ctx context.Context, status *RepoStatus,
 	repoMetadata := &scanner.RepoMetadata{
 		GitHubOrg:      status.Repo.Owner,
 		GitHubRepo:     status.Repo.Name,
-		RepoURL:        fmt.Sprintf("https://github.com/%s", status.Repo.NameWithOwner),
+		RepoURL:        h.repoURL(status),
 		CommitSHA:      h.getFullCommitHash(status.RepoPath),
 		Branch:         h.getCurrentBranch(status.RepoPath),
 		ScanProfile:    h.opts.Profile,
@@ -806,6 +931,7 @@ func (h *Hydrate) scanRepoWithProgress(ctx context.Context, status *RepoStatus,
 		Parallel:       h.opts.ParallelScanners,
 		FeatureConfigs: featureConfigs,
 		RepoMetadata:   repoMetadata,
+		NoCache:        h.opts.Force,
 	})
 	status.Duration = time.Since(start)
 
@@ -824,6 +950,7 @@ func (h *Hydrate) scanRepoWithProgress(ctx context.Context, status *RepoStatus,
 			r.Summary = res.Summary
 			r.Duration = res.Duration
 			r.Error = res.Error
+			r.Partial = res.Partial
 		}
 	}
 
@@ -1601,14 +1728,28 @@ func (h *Hydrate) preserveHistory(statuses []*RepoStatus, scannersRun []string,
 
 		projectID := github.ProjectID(status.Repo.NameWithOwner)
 
-		// Get commit info
-		commitHash := h.getFullCommitHash(status.RepoPath)
-		commitShort := h.getCommitHash(status.RepoPath)
-		branch := h.getCurrentBranch(status.RepoPath)
+		// Get commit info; projects without a repository are identified
+		// by the digest of what was analyzed
+		var commitHash, commitShort, branch string
+		if status.Digest != "" {
+			commitHash = status.Digest
+			commitShort = shortCommit(status.Digest)
+		} else {
+			commitHash = h.getFullCommitHash(status.RepoPath)
+			commitShort = h.getCommitHash(status.RepoPath)
+			branch = h.getCurrentBranch(status.RepoPath)
+		}
 
 		// Build findings summary from aggregated data
 		findingsSummary := h.buildFindingsSummary(projectID)
 
+		// Scans where some scanner features did not complete are recorded
+		// as partial so later diffs can be read accordingly
+		recordStatus := "complete"
+		if hasPartialResults(status) {
+			recordStatus = "partial"
+		}
+
 		// Create scan record
 		record := diff.ScanRecord{
 			ScanID:          scanID,
@@ -1620,7 +1761,7 @@ func (h *Hydrate) preserveHistory(statuses []*RepoStatus, scannersRun []string,
 			DurationSeconds: int(time.Since(startTime).Seconds()),
 			Profile:         h.opts.Profile,
 			ScannersRun:     scannersRun,
-			Status:          "complete",
+			Status:          recordStatus,
 			FindingsSummary: findingsSummary,
 		}
 
@@ -1632,6 +1773,55 @@ func (h *Hydrate) preserveHistory(statuses []*RepoStatus, scannersRun []string,
 	}
 }
 
+// hasPartialResults reports whether any scanner of a repo produced partial
+// results
+func hasPartialResults(status *RepoStatus) bool {
+	if status.Progress == nil {
+		return false
+	}
+	for _, r := range status.Progress.Results {
+		if r.Partial {
+			return true
+		}
+	}
+	return false
+}
+
+// recordFreshness records per-scanner results in freshness metadata
+func (h *Hydrate) recordFreshness(statuses []*RepoStatus) {
+	freshnessMgr := freshness.NewManager(h.zeroHome)
+
+	for _, status := range statuses {
+		if !status.CloneOK || status.Progress == nil {
+			continue
+		}
+
+		projectID := github.ProjectID(status.Repo.NameWithOwner)
+
+		var results []freshness.ScanResult
+		for name, r := range status.Progress.Results {
+			if r.Status == scanner.StatusSkipped || r.Status == scanner.StatusQueued {
+				continue
+			}
+			result := freshness.ScanResult{
+				Name:       name,
+				Success:    r.Status == scanner.StatusComplete,
+				Duration:   r.Duration,
+				OutputFile: filepath.Join(h.zeroHome, "repos", projectID, "analysis", name+".json"),
+			}
+			if r.Error != nil {
+				result.Error = r.Error.Error()
+			}
+			results = append(results, result)
+		}
+
+		if err := freshnessMgr.RecordScan(projectID, results); err != nil {
+			// Log error but don't fail the scan
+			fmt.Fprintf(os.Stderr, "Warning: failed to record freshness for %s: %v\n", projectID, err)
+		}
+	}
+}
+
 // getFullCommitHash returns the full commit hash of the repo
 func (h *Hydrate) getFullCommitHash(path string) string {
 	cmd := exec.Command("git", "-C", path, "rev-parse", "HEAD")
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/crashappsec/zero/pkg/core/config"
 	"github.com/crashappsec/zero/pkg/core/github"
 )
 
@@ -548,3 +549,82 @@ func TestCountFiles(t *testing.T) {
 		t.Errorf("countFiles() = %d, want 3", count)
 	}
 }
+
+func TestSBOMProjectID(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+		want string
+	}{
+		{"vendor.cdx.json", "", "sbom/vendor"},
+		{"/tmp/Acme Gateway.spdx.json", "", "sbom/acme-gateway"},
+		{"gateway.spdx", "", "sbom/gateway"},
+		{"gateway.spdx.jsonld", "", "sbom/gateway"},
+		{"vendor.cdx.json", "Acme/Gateway", "sbom/acme-gateway"},
+		{"", "../..", "sbom/unnamed"},
+	}
+
+	for _, tt := range tests {
+		if got := SBOMProjectID(tt.path, tt.name); got != tt.want {
+			t.Errorf("SBOMProjectID(%q, %q) = %q, want %q", tt.path, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestImageProjectID(t *testing.T) {
+	tests := []struct {
+		path     string
+		name     string
+		repoTags []string
+		want     string
+	}{
+		{"app.tar", "", []string{"registry.example.com/team/app:1.2.3"}, "image/app"},
+		{"app.tar", "", []string{"localhost:5000/api"}, "image/api"},
+		{"/tmp/Build Output.tar.gz", "", nil, "image/build-output"},
+		{"web.tgz", "", nil, "image/web"},
+		{"app.tar", "Acme/Gateway", []string{"app:1.0"}, "image/acme-gateway"},
+		{"", "../..", nil, "image/unnamed"},
+	}
+
+	for _, tt := range tests {
+		if got := ImageProjectID(tt.path, tt.name, tt.repoTags); got != tt.want {
+			t.Errorf("ImageProjectID(%q, %q, %v) = %q, want %q", tt.path, tt.name, tt.repoTags, got, tt.want)
+		}
+	}
+}
+
+func TestSBOMFeatureConfigs(t *testing.T) {
+	h := &Hydrate{
+		cfg: &config.Config{Scanners: map[string]config.Scanner{
+			"code-packages": {Features: map[string]interface{}{
+				"generation": map[string]interface{}{"enabled": true, "tool": "cdxgen"},
+				"vulns":      map[string]interface{}{"enabled": false, "include_kev": true},
+				"malcontent": map[string]interface{}{"enabled": true},
+			}},
+		}},
+		opts: &Options{},
+	}
+
+	features := h.sbomFeatureConfigs("vendor.cdx.json")["code-packages"]
+	feature := func(name string) map[string]interface{} {
+		m, _ := features[name].(map[string]interface{})
+		return m
+	}
+
+	if gen := feature("generation"); gen["sbom_path"] != "vendor.cdx.json" || gen["tool"] != "cdxgen" {
+		t.Errorf("generation = %v", gen)
+	}
+	if vulns := feature("vulns"); vulns["enabled"] != true || vulns["include_kev"] != true {
+		t.Errorf("vulns = %v", vulns)
+	}
+	for _, name := range []string{"malcontent", "reachability", "integrity"} {
+		if feature(name)["enabled"] != false {
+			t.Errorf("%s should be disabled", name)
+		}
+	}
+
+	// The configured features must not be modified
+	if h.cfg.Scanners["code-packages"].Features["vulns"].(map[string]interface{})["enabled"] != false {
+		t.Error("sbomFeatureConfigs modified the scanner config")
+	}
+}
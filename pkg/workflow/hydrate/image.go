@@ -0,0 +1,236 @@
+package hydrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/cyclonedx"
+	"github.com/crashappsec/zero/pkg/core/github"
+	"github.com/crashappsec/zero/pkg/core/oci"
+	"github.com/crashappsec/zero/pkg/scanner"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+)
+
+// ImageOwner is the project owner for analyzed container images
+const ImageOwner = "image"
+
+// imageScanners are the scanners run against an unpacked image
+var imageScanners = []string{"code-packages", "code-security", "devops"}
+
+// ImageResult describes a container image analysis
+type ImageResult struct {
+	ProjectID string
+	Image     *oci.Image
+	Digest    string // Image ID, recorded in place of a commit
+	Extract   *oci.ExtractStats
+	SBOM      *codepackages.ImageSBOMStats
+	Status    *RepoStatus
+}
+
+// ImageProjectID returns the pseudo-project ID for an image archive. The
+// name defaults to the repository of the image's first tag
+// (registry.example.com/team/app:1.2.3 is "app"), then to the file name.
+func ImageProjectID(path, name string, repoTags []string) string {
+	if name == "" && len(repoTags) > 0 {
+		name, _ = splitImageTag(repoTags[0])
+		name = name[strings.LastIndex(name, "/")+1:]
+	}
+	if name == "" {
+		name = filepath.Base(path)
+		for _, ext := range []string{".gz", ".tgz", ".tar"} {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+	if seg := sanitizeSegment(name); seg != "" {
+		name = seg
+	} else {
+		name = "unnamed"
+	}
+	return ImageOwner + "/" + name
+}
+
+// splitImageTag splits a tag into its repository and tag. A colon in the
+// registry host (localhost:5000/app) is not a tag.
+func splitImageTag(ref string) (repo, tag string) {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// AnalyzeImage unpacks a `docker save` or OCI layout archive and analyzes
+// the image's filesystem. The layers are applied natively into the
+// project's repo directory, an SBOM of the OS and language packages is
+// built from what is installed, and code-packages, code-security (secrets)
+// and devops (IaC) run over the result. Nothing is pulled or executed, so
+// locally built images can be analyzed offline.
+func (h *Hydrate) AnalyzeImage(ctx context.Context, path, name string) (*ImageResult, error) {
+	start := time.Now()
+	scanID := fmt.Sprintf("scan-%s", start.Format("20060102-150405"))
+
+	archive, err := oci.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening image: %w", err)
+	}
+	defer archive.Close()
+	img := archive.Image
+	digest := strings.TrimPrefix(img.ID, "sha256:")
+
+	projectID := ImageProjectID(path, name, img.RepoTags)
+	repoName := strings.TrimPrefix(projectID, ImageOwner+"/")
+	projectDir := filepath.Join(h.zeroHome, "repos", projectID)
+	repoPath := filepath.Join(projectDir, "repo")
+	outputDir := filepath.Join(projectDir, "analysis")
+
+	// The repo directory holds only the image's root filesystem
+	if err := os.RemoveAll(repoPath); err != nil {
+		return nil, err
+	}
+	extract, err := archive.Extract(ctx, repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("unpacking image: %w", err)
+	}
+
+	// The SBOM is kept beside the root filesystem so the secrets scan does
+	// not read it
+	sbomPath, err := filepath.Abs(filepath.Join(projectDir, "image.cdx.json"))
+	if err != nil {
+		return nil, err
+	}
+	sbomStats, err := codepackages.GenerateImageSBOM(repoPath, sbomPath, imageMetadataComponent(img, repoName))
+	if err != nil {
+		return nil, fmt.Errorf("building image SBOM: %w", err)
+	}
+
+	scannerList, err := scanner.GetByNames(imageScanners)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &RepoStatus{
+		Repo: github.Repository{
+			Name:          repoName,
+			NameWithOwner: projectID,
+			Owner:         ImageOwner,
+		},
+		RepoPath:    repoPath,
+		FileCount:   extract.Files,
+		CloneOK:     true,
+		Digest:      digest,
+		Progress:    scanner.NewProgress(imageScanners),
+		ScannersRun: imageScanners,
+	}
+
+	run, err := h.runner.RunScanners(ctx, scanner.RunOptions{
+		RepoPath:       repoPath,
+		OutputDir:      outputDir,
+		Scanners:       scannerList,
+		Parallel:       1,
+		Timeout:        time.Duration(h.cfg.Settings.ScannerTimeoutSeconds) * time.Second,
+		FeatureConfigs: h.imageFeatureConfigs(sbomPath),
+		RepoMetadata: &scanner.RepoMetadata{
+			GitHubOrg:      ImageOwner,
+			GitHubRepo:     repoName,
+			CommitSHA:      digest,
+			ScanProfile:    h.opts.Profile,
+			ScannerVersion: h.cfg.Version,
+		},
+		NoCache: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyzing image: %w", err)
+	}
+
+	recordRunResults(status, run)
+	status.Duration = time.Since(start)
+	h.extractSBOMStats(status, outputDir)
+
+	statuses := []*RepoStatus{status}
+	h.recordFreshness(statuses)
+	h.preserveHistory(statuses, imageScanners, scanID, start)
+
+	result := &ImageResult{
+		ProjectID: projectID,
+		Image:     img,
+		Digest:    digest,
+		Extract:   extract,
+		SBOM:      sbomStats,
+		Status:    status,
+	}
+	if !status.ScanOK {
+		for _, name := range imageScanners {
+			if r := run.Results[name]; r != nil && r.Error != nil {
+				return result, fmt.Errorf("analyzing image: %s: %w", name, r.Error)
+			}
+		}
+		return result, fmt.Errorf("analyzing image: scanners did not complete")
+	}
+	return result, nil
+}
+
+// imageMetadataComponent describes the image as the subject of its SBOM
+func imageMetadataComponent(img *oci.Image, repoName string) *cyclonedx.Component {
+	name, version := repoName, ""
+	if len(img.RepoTags) > 0 {
+		name, version = splitImageTag(img.RepoTags[0])
+	}
+	if version == "" {
+		version = strings.TrimPrefix(img.ID, "sha256:")
+		version = version[:min(len(version), 12)]
+	}
+
+	comp := &cyclonedx.Component{
+		Type:    cyclonedx.ComponentTypeContainer,
+		BOMRef:  img.ID,
+		Name:    name,
+		Version: version,
+	}
+	for _, p := range []cyclonedx.Property{
+		{Name: "zero:image_id", Value: img.ID},
+		{Name: "zero:os", Value: img.Config.OS},
+		{Name: "zero:architecture", Value: img.Config.Architecture},
+	} {
+		if p.Value != "" {
+			comp.Properties = append(comp.Properties, p)
+		}
+	}
+	return comp
+}
+
+// imageFeatureConfigs returns the feature configs for an image analysis:
+// code-packages reads the image SBOM like a standalone SBOM, code-security
+// looks only for secrets (an image has no git history) and devops only
+// runs the IaC checks
+func (h *Hydrate) imageFeatureConfigs(sbomPath string) map[string]map[string]interface{} {
+	configs := h.sbomFeatureConfigs(sbomPath)
+
+	redact := true
+	if secrets, ok := h.loadFeatureConfigs([]string{"code-security"})["code-security"]["secrets"].(map[string]interface{}); ok {
+		if v, ok := secrets["redact_secrets"].(bool); ok {
+			redact = v
+		}
+	}
+	configs["code-security"] = map[string]interface{}{
+		"secrets": map[string]interface{}{
+			"enabled":        true,
+			"redact_secrets": redact,
+			// Compiled files and hashes make entropy findings noise here
+			"entropy_analysis":     map[string]interface{}{"enabled": false},
+			"git_history_scan":     map[string]interface{}{"enabled": false},
+			"git_history_security": map[string]interface{}{"enabled": false},
+			"rotation_guidance":    true,
+		},
+	}
+	configs["devops"] = map[string]interface{}{
+		"iac": map[string]interface{}{
+			"enabled":       true,
+			"tool":          "auto",
+			"fallback_tool": true,
+		},
+	}
+	return configs
+}
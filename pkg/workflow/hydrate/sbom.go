@@ -0,0 +1,208 @@
+package hydrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+	"github.com/crashappsec/zero/pkg/scanner"
+	codepackages "github.com/crashappsec/zero/pkg/scanner/code-packages"
+)
+
+// SBOMOwner is the project owner for SBOMs analyzed without a repository
+const SBOMOwner = "sbom"
+
+// sbomFeatures are the code-packages features that work from an SBOM alone.
+// Everything else needs source (lockfiles, node_modules, call graphs).
+var sbomFeatures = []string{"vulns", "licenses", "health", "deprecations", "typosquats", "provenance"}
+
+// sourceFeatures are the code-packages features turned off for standalone SBOMs
+var sourceFeatures = []string{"integrity", "malcontent", "confusion", "reachability", "bundle", "duplicates", "recommendations"}
+
+// SBOMResult describes a standalone SBOM analysis
+type SBOMResult struct {
+	ProjectID string
+	Format    string // cyclonedx, spdx-json, spdx-tag-value or spdx-jsonld
+	Digest    string // sha256 of the SBOM, recorded in place of a commit
+	Packages  int
+	Status    *RepoStatus
+}
+
+// SBOMProjectID returns the pseudo-project ID for an SBOM. The name defaults
+// to the file name without its SBOM extensions.
+func SBOMProjectID(path, name string) string {
+	if name == "" {
+		name = filepath.Base(path)
+		for _, ext := range []string{".json", ".jsonld", ".spdx", ".cdx", ".bom", ".sbom"} {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+	if seg := sanitizeSegment(name); seg != "" {
+		name = seg
+	} else {
+		name = "unnamed"
+	}
+	return SBOMOwner + "/" + name
+}
+
+// AnalyzeSBOM runs the SBOM-based code-packages features against a supplied
+// CycloneDX or SPDX document. There is no clone: the SBOM is stored as the
+// project's only source file and results land in the usual analysis
+// directory, so reports, history diffs and VEX work on the pseudo-project.
+// Successive analyses are told apart by the SBOM's digest.
+func (h *Hydrate) AnalyzeSBOM(ctx context.Context, path, name string) (*SBOMResult, error) {
+	start := time.Now()
+	scanID := fmt.Sprintf("scan-%s", start.Format("20060102-150405"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading SBOM: %w", err)
+	}
+	format := codepackages.DetectSBOMFormat(data)
+	if format == "" {
+		return nil, fmt.Errorf("%s is not a CycloneDX JSON or SPDX SBOM", filepath.Base(path))
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	projectID := SBOMProjectID(path, name)
+	repoName := strings.TrimPrefix(projectID, SBOMOwner+"/")
+	projectDir := filepath.Join(h.zeroHome, "repos", projectID)
+	repoPath := filepath.Join(projectDir, "repo")
+	outputDir := filepath.Join(projectDir, "analysis")
+
+	// The repo directory holds only the SBOM being analyzed
+	if err := os.RemoveAll(repoPath); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		return nil, fmt.Errorf("creating project directory: %w", err)
+	}
+	sbomName := filepath.Base(path)
+	if err := os.WriteFile(filepath.Join(repoPath, sbomName), data, 0644); err != nil {
+		return nil, fmt.Errorf("storing SBOM: %w", err)
+	}
+
+	scanners := []string{"code-packages"}
+	scannerList, err := scanner.GetByNames(scanners)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &RepoStatus{
+		Repo: github.Repository{
+			Name:          repoName,
+			NameWithOwner: projectID,
+			Owner:         SBOMOwner,
+		},
+		RepoPath:    repoPath,
+		FileCount:   1,
+		CloneOK:     true,
+		Digest:      digest,
+		Progress:    scanner.NewProgress(scanners),
+		ScannersRun: scanners,
+	}
+
+	run, err := h.runner.RunScanners(ctx, scanner.RunOptions{
+		RepoPath:       repoPath,
+		OutputDir:      outputDir,
+		Scanners:       scannerList,
+		Parallel:       1,
+		Timeout:        time.Duration(h.cfg.Settings.ScannerTimeoutSeconds) * time.Second,
+		FeatureConfigs: h.sbomFeatureConfigs(sbomName),
+		RepoMetadata: &scanner.RepoMetadata{
+			GitHubOrg:      SBOMOwner,
+			GitHubRepo:     repoName,
+			CommitSHA:      digest,
+			ScanProfile:    h.opts.Profile,
+			ScannerVersion: h.cfg.Version,
+		},
+		NoCache: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyzing SBOM: %w", err)
+	}
+
+	recordRunResults(status, run)
+	status.Duration = time.Since(start)
+	h.extractSBOMStats(status, outputDir)
+
+	statuses := []*RepoStatus{status}
+	h.recordFreshness(statuses)
+	h.preserveHistory(statuses, scanners, scanID, start)
+
+	result := &SBOMResult{
+		ProjectID: projectID,
+		Format:    format,
+		Digest:    digest,
+		Packages:  status.SBOMPackages,
+		Status:    status,
+	}
+	if !status.ScanOK {
+		if r := run.Results["code-packages"]; r != nil && r.Error != nil {
+			return result, fmt.Errorf("analyzing SBOM: %w", r.Error)
+		}
+		return result, fmt.Errorf("analyzing SBOM: code-packages did not complete")
+	}
+	return result, nil
+}
+
+// recordRunResults copies a scanner run's outcome into a pseudo-project's
+// status
+func recordRunResults(status *RepoStatus, run *scanner.RunResult) {
+	status.ScanOK = run.Success
+	for name, r := range run.Results {
+		if r.Status == scanner.StatusFailed {
+			status.ScanOK = false
+		}
+		if p := status.Progress.Results[name]; p != nil {
+			p.Status = r.Status
+			p.Summary = r.Summary
+			p.Duration = r.Duration
+			p.Partial = r.Partial
+			p.Error = r.Error
+		}
+	}
+}
+
+// sbomFeatureConfigs returns the configured code-packages features with
+// generation pointed at the supplied SBOM and the source-dependent features
+// turned off
+func (h *Hydrate) sbomFeatureConfigs(sbomName string) map[string]map[string]interface{} {
+	features := make(map[string]interface{})
+	for name, v := range h.loadFeatureConfigs([]string{"code-packages"})["code-packages"] {
+		if m, ok := v.(map[string]interface{}); ok {
+			copied := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				copied[k] = v
+			}
+			v = copied
+		}
+		features[name] = v
+	}
+
+	setFeature := func(name, key string, value interface{}) {
+		m, ok := features[name].(map[string]interface{})
+		if !ok {
+			m = make(map[string]interface{})
+			features[name] = m
+		}
+		m[key] = value
+	}
+	setFeature("generation", "enabled", true)
+	setFeature("generation", "sbom_path", sbomName)
+	for _, name := range sbomFeatures {
+		setFeature(name, "enabled", true)
+	}
+	for _, name := range sourceFeatures {
+		setFeature(name, "enabled", false)
+	}
+
+	return map[string]map[string]interface{}{"code-packages": features}
+}
@@ -0,0 +1,322 @@
+package hydrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/crashappsec/zero/pkg/core/github"
+)
+
+// LocalOwner is the owner segment used for projects imported from local directories
+const LocalOwner = "local"
+
+// SourceKind identifies where a non-GitHub target comes from
+type SourceKind string
+
+const (
+	SourceGitURL   SourceKind = "git"   // Any git remote (https, ssh, git, file)
+	SourceLocalDir SourceKind = "local" // Local directory that is not a git work tree
+)
+
+// Source describes a target that is not resolved through GitHub.
+// It is imported into the same .zero/repos/<owner>/<repo> layout as GitHub repos.
+type Source struct {
+	Kind  SourceKind
+	URL   string // Clone URL (file:// for local git checkouts)
+	Path  string // Absolute path for local directories
+	Owner string // Owner segment of the project ID
+	Name  string // Repo segment of the project ID
+}
+
+// ProjectID returns the owner/repo identifier for the source
+func (s *Source) ProjectID() string {
+	return github.ProjectID(s.Owner + "/" + s.Name)
+}
+
+// Repository returns a repository record for the source so it flows through
+// the same clone/scan pipeline as GitHub repos
+func (s *Source) Repository() github.Repository {
+	return github.Repository{
+		Name:          s.Name,
+		NameWithOwner: s.Owner + "/" + s.Name,
+		Owner:         s.Owner,
+		CloneURL:      s.URL,
+	}
+}
+
+// Location returns a human-readable location for the source
+func (s *Source) Location() string {
+	if s.Kind == SourceLocalDir {
+		return s.Path
+	}
+	return s.URL
+}
+
+// scpLikePattern matches scp-style git remotes (git@host:path/repo.git)
+var scpLikePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:.+$`)
+
+// unsafeSegmentChars matches characters not allowed in project ID segments
+var unsafeSegmentChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// IsSourceTarget reports whether target is a local path or git URL rather than
+// a GitHub owner/repo or org name. Bare "owner/repo" is always treated as GitHub;
+// local paths must be explicit (".", "./x", "../x", "/x" or "~/x").
+func IsSourceTarget(target string) bool {
+	if strings.Contains(target, "://") || scpLikePattern.MatchString(target) {
+		return true
+	}
+	return target == "." || target == ".." || target == "~" ||
+		strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") ||
+		strings.HasPrefix(target, "/") || strings.HasPrefix(target, "~/")
+}
+
+// ParseSource resolves a local path or git URL into a Source
+func ParseSource(target string) (*Source, error) {
+	if strings.Contains(target, "://") || scpLikePattern.MatchString(target) {
+		owner, name, err := projectFromURL(target)
+		if err != nil {
+			return nil, err
+		}
+		return &Source{Kind: SourceGitURL, URL: target, Owner: owner, Name: name}, nil
+	}
+
+	path := target
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("resolving home directory: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %s: %w", target, err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("local path not found: %s", target)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("local path is not a directory: %s", target)
+	}
+
+	name := sanitizeSegment(filepath.Base(abs))
+	if name == "" {
+		return nil, fmt.Errorf("cannot derive project name from path: %s", target)
+	}
+	// Directories that share a basename must not share a workspace
+	sum := sha256.Sum256([]byte(abs))
+	name += "-" + hex.EncodeToString(sum[:4])
+
+	src := &Source{Kind: SourceLocalDir, Path: abs, Owner: LocalOwner, Name: name}
+
+	// Git checkouts are cloned like any other remote so history-based scanners work
+	if isGitWorkTree(abs) {
+		src.Kind = SourceGitURL
+		src.URL = (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+	}
+
+	return src, nil
+}
+
+// projectFromURL derives owner/repo from the last two path segments of a git URL.
+// Single-segment paths use the host as owner.
+func projectFromURL(raw string) (owner, name string, err error) {
+	var host, path string
+
+	if scpLikePattern.MatchString(raw) && !strings.Contains(raw, "://") {
+		// git@host:team/repo.git
+		at := strings.Index(raw, "@")
+		colon := strings.Index(raw[at:], ":") + at
+		host = raw[at+1 : colon]
+		path = raw[colon+1:]
+	} else {
+		u, perr := url.Parse(raw)
+		if perr != nil {
+			return "", "", fmt.Errorf("invalid git URL %s: %w", raw, perr)
+		}
+		host = u.Hostname()
+		path = u.Path
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	segments := strings.Split(path, "/")
+
+	var parts []string
+	for _, s := range segments {
+		if s = sanitizeSegment(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	switch {
+	case len(parts) >= 2:
+		return parts[len(parts)-2], parts[len(parts)-1], nil
+	case len(parts) == 1:
+		if host = sanitizeSegment(host); host == "" {
+			host = LocalOwner
+		}
+		return host, parts[0], nil
+	default:
+		return "", "", fmt.Errorf("cannot derive project name from URL: %s", raw)
+	}
+}
+
+// sanitizeSegment lowercases a path segment and strips characters that are not
+// safe in a project ID
+func sanitizeSegment(s string) string {
+	s = unsafeSegmentChars.ReplaceAllString(strings.ToLower(s), "-")
+	s = strings.Trim(s, "-.")
+	return s
+}
+
+// isGitWorkTree reports whether path is the root of a git work tree.
+// Subdirectories of a checkout are copied rather than cloned.
+func isGitWorkTree(path string) bool {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil
+}
+
+// hasUncommittedChanges reports whether a git work tree has staged, unstaged
+// or untracked changes. Errors are treated as a clean tree.
+func hasUncommittedChanges(ctx context.Context, path string) bool {
+	out, err := exec.CommandContext(ctx, "git", "-C", path, "status", "--porcelain").Output()
+	return err == nil && len(strings.TrimSpace(string(out))) > 0
+}
+
+// importSource brings a non-GitHub source into repoPath. Existing git clones are
+// updated to the remote HEAD (or --branch); local directories are re-copied so
+// every scan sees the current contents.
+func (h *Hydrate) importSource(ctx context.Context, src *Source, repoPath string, depth int) (string, error) {
+	if src.Kind == SourceLocalDir {
+		if err := os.RemoveAll(repoPath); err != nil {
+			return "", fmt.Errorf("removing previous copy: %w", err)
+		}
+		// The storage path defaults to .zero in the working directory, so
+		// "zero scan ." would otherwise copy the copy into itself
+		skip := []string{repoPath}
+		if h.zeroHome != "" {
+			skip = append(skip, h.zeroHome)
+		}
+		if err := copyDir(src.Path, repoPath, skip...); err != nil {
+			return "", fmt.Errorf("copying %s: %w", src.Path, err)
+		}
+		return "copied", nil
+	}
+
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err == nil {
+		ref := h.opts.Branch
+		if ref == "" {
+			ref = "HEAD"
+		}
+		fetch := exec.CommandContext(ctx, "git", "-C", repoPath, "fetch", "--depth", fmt.Sprintf("%d", depth), "origin", ref)
+		if out, err := fetch.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git fetch: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		reset := exec.CommandContext(ctx, "git", "-C", repoPath, "reset", "--hard", "FETCH_HEAD")
+		if out, err := reset.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git reset: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return "updated", nil
+	}
+
+	if err := os.RemoveAll(repoPath); err != nil {
+		return "", fmt.Errorf("removing invalid repo directory: %w", err)
+	}
+
+	args := []string{"clone", "--depth", fmt.Sprintf("%d", depth)}
+	if h.opts.Branch != "" {
+		args = append(args, "--branch", h.opts.Branch)
+	}
+	args = append(args, src.URL, repoPath)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git clone: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return "cloned", nil
+}
+
+// copyDir copies a directory tree, preserving file modes and symlinks.
+// Directories in skip and .zero storage directories are left out.
+func copyDir(src, dst string, skip ...string) error {
+	skipped := make(map[string]bool, len(skip))
+	for _, p := range skip {
+		if abs, err := filepath.Abs(p); err == nil {
+			skipped[abs] = true
+		}
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != src {
+			if d.Name() == ".zero" {
+				return filepath.SkipDir
+			}
+			if abs, err := filepath.Abs(path); err == nil && skipped[abs] {
+				return filepath.SkipDir
+			}
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// Skip sockets, devices and other special files
+			return nil
+		}
+	})
+}
+
+// copyFile copies a single regular file
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
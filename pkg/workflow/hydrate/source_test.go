@@ -0,0 +1,298 @@
+package hydrate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crashappsec/zero/pkg/scanner"
+)
+
+func TestIsSourceTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"strapi/strapi", false},
+		{"zero-test-org", false},
+		{".", true},
+		{"./path/to/checkout", true},
+		{"../sibling", true},
+		{"/abs/path", true},
+		{"~/src/repo", true},
+		{"https://git.example.com/team/repo.git", true},
+		{"ssh://git@git.example.com/team/repo.git", true},
+		{"file:///srv/git/repo.git", true},
+		{"git@git.example.com:team/repo.git", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsSourceTarget(tt.target); got != tt.want {
+			t.Errorf("IsSourceTarget(%q) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestParseSource_URLs(t *testing.T) {
+	tests := []struct {
+		target    string
+		projectID string
+	}{
+		{"https://git.example.com/team/repo.git", "team/repo"},
+		{"https://git.example.com/group/subgroup/Repo", "subgroup/repo"},
+		{"ssh://git@git.example.com:2222/team/repo.git", "team/repo"},
+		{"git@git.example.com:team/repo.git", "team/repo"},
+		{"file:///srv/git/repo.git", "git/repo"},
+		{"https://git.example.com/repo.git", "git.example.com/repo"},
+	}
+
+	for _, tt := range tests {
+		src, err := ParseSource(tt.target)
+		if err != nil {
+			t.Errorf("ParseSource(%q) error = %v", tt.target, err)
+			continue
+		}
+		if src.Kind != SourceGitURL {
+			t.Errorf("ParseSource(%q).Kind = %q, want %q", tt.target, src.Kind, SourceGitURL)
+		}
+		if src.URL != tt.target {
+			t.Errorf("ParseSource(%q).URL = %q", tt.target, src.URL)
+		}
+		if got := src.ProjectID(); got != tt.projectID {
+			t.Errorf("ParseSource(%q).ProjectID() = %q, want %q", tt.target, got, tt.projectID)
+		}
+	}
+
+	if _, err := ParseSource("https://git.example.com/"); err == nil {
+		t.Error("ParseSource() should fail for URL without a repo path")
+	}
+}
+
+func TestParseSource_LocalDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	dir := filepath.Join(tmpDir, "My Service")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := ParseSource(dir)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if src.Kind != SourceLocalDir {
+		t.Errorf("Kind = %q, want %q", src.Kind, SourceLocalDir)
+	}
+	if !strings.HasPrefix(src.ProjectID(), "local/my-service-") {
+		t.Errorf("ProjectID() = %q, want local/my-service-<hash>", src.ProjectID())
+	}
+
+	// Another directory with the same basename gets its own workspace
+	other := filepath.Join(tmpDir, "other", "My Service")
+	if err := os.MkdirAll(other, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if o, err := ParseSource(other); err != nil || o.ProjectID() == src.ProjectID() {
+		t.Errorf("ParseSource(%q).ProjectID() = %q, shared with %q", other, o.ProjectID(), dir)
+	}
+	if src.Location() != dir {
+		t.Errorf("Location() = %q, want %q", src.Location(), dir)
+	}
+
+	// A git checkout is cloned through a file:// URL
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	src, err = ParseSource(dir)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if src.Kind != SourceGitURL || !strings.HasPrefix(src.URL, "file://") {
+		t.Errorf("git checkout: Kind = %q, URL = %q", src.Kind, src.URL)
+	}
+
+	if _, err := ParseSource(filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("ParseSource() should fail for missing path")
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("main.go", filepath.Join(src, "link.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "repo")
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("copyDir() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "main.go"))
+	if err != nil || string(data) != "package main" {
+		t.Errorf("main.go not copied: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "sub", "run.sh"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("run.sh should be copied with exec bit: %v", err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "link.go")); err != nil || link != "main.go" {
+		t.Errorf("link.go should remain a symlink to main.go, got %q (%v)", link, err)
+	}
+}
+
+func TestImportSource_LocalDirContainingStorage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Earlier results under the default storage path and a custom one
+	if err := os.MkdirAll(filepath.Join(dir, ".zero", "cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	zeroHome := filepath.Join(dir, "store")
+
+	src, err := ParseSource(dir)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	h := &Hydrate{zeroHome: zeroHome, opts: &Options{}}
+	repoPath := filepath.Join(zeroHome, "repos", src.ProjectID(), "repo")
+
+	for i := 0; i < 2; i++ {
+		if _, err := h.importSource(context.Background(), src, repoPath, 1); err != nil {
+			t.Fatalf("importSource() error = %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, "main.go")); err != nil {
+		t.Errorf("main.go not copied: %v", err)
+	}
+	for _, name := range []string{"store", ".zero"} {
+		if _, err := os.Stat(filepath.Join(repoPath, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied into the project", name)
+		}
+	}
+}
+
+func TestImportSource_GitURL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// Build a source repository with one commit
+	origin := t.TempDir()
+	runGit(t, origin, "init", "-q")
+	if err := os.WriteFile(filepath.Join(origin, "README.md"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, origin, "add", ".")
+	runGit(t, origin, "commit", "-q", "-m", "initial")
+
+	src, err := ParseSource(origin)
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	h := &Hydrate{opts: &Options{}}
+	repoPath := filepath.Join(t.TempDir(), "repos", src.ProjectID(), "repo")
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := h.importSource(context.Background(), src, repoPath, 1)
+	if err != nil {
+		t.Fatalf("importSource() error = %v", err)
+	}
+	if state != "cloned" {
+		t.Errorf("state = %q, want cloned", state)
+	}
+
+	// New commits are picked up on the next import
+	if err := os.WriteFile(filepath.Join(origin, "README.md"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, origin, "commit", "-q", "-am", "update")
+
+	state, err = h.importSource(context.Background(), src, repoPath, 1)
+	if err != nil {
+		t.Fatalf("importSource() update error = %v", err)
+	}
+	if state != "updated" {
+		t.Errorf("state = %q, want updated", state)
+	}
+	data, _ := os.ReadFile(filepath.Join(repoPath, "README.md"))
+	if string(data) != "v2" {
+		t.Errorf("README.md = %q, want v2", data)
+	}
+
+	if hasUncommittedChanges(context.Background(), origin) {
+		t.Error("clean checkout reported uncommitted changes")
+	}
+	if err := os.WriteFile(filepath.Join(origin, "README.md"), []byte("v3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !hasUncommittedChanges(context.Background(), origin) {
+		t.Error("modified checkout reported no uncommitted changes")
+	}
+}
+
+func TestRecordFreshness(t *testing.T) {
+	zeroHome := t.TempDir()
+	h := &Hydrate{zeroHome: zeroHome, opts: &Options{}}
+
+	src := &Source{Kind: SourceLocalDir, Path: "/tmp/svc", Owner: LocalOwner, Name: "svc"}
+	progress := scanner.NewProgress([]string{"code-security", "code-packages", "devops"})
+	progress.SetComplete("code-security", "", time.Second)
+	progress.SetFailed("code-packages", os.ErrNotExist, time.Second)
+	progress.SetSkipped("devops")
+
+	h.recordFreshness([]*RepoStatus{{
+		Repo:     src.Repository(),
+		Source:   src,
+		CloneOK:  true,
+		Progress: progress,
+	}})
+
+	data, err := os.ReadFile(filepath.Join(zeroHome, "repos", "local", "svc", "freshness.json"))
+	if err != nil {
+		t.Fatalf("freshness.json not written: %v", err)
+	}
+
+	var meta struct {
+		LastScan      time.Time                  `json:"last_scan"`
+		ScannerStatus map[string]json.RawMessage `json:"scanner_status"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("parsing freshness.json: %v", err)
+	}
+	if meta.LastScan.IsZero() {
+		t.Error("last_scan should be set")
+	}
+	if len(meta.ScannerStatus) != 2 {
+		t.Errorf("scanner_status has %d entries, want 2 (skipped excluded)", len(meta.ScannerStatus))
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+}
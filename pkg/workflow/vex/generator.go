@@ -178,7 +178,7 @@ func (g *Generator) analyzeVulnerability(v VulnInput) *Analysis {
 		analysis.State = StateExploitable
 		if len(v.ReachablePaths) > 0 {
 			analysis.Detail = fmt.Sprintf("Vulnerable code is reachable via: %s",
-				strings.Join(v.ReachablePaths[:min(3, len(v.ReachablePaths))], " -> "))
+				strings.Join(v.ReachablePaths[:min(3, len(v.ReachablePaths))], "; "))
 		}
 	}
 
@@ -239,10 +239,10 @@ func (g *Generator) getSourceURL(id string) string {
 // GenerateFromScanResults generates VEX from Zero scan results
 func (g *Generator) GenerateFromScanResults(analysisDir string) (*Document, error) {
 	// Load packages.json for vulnerability data
-	packagesPath := filepath.Join(analysisDir, "packages.json")
+	packagesPath := PackagesPath(analysisDir)
 	packagesData, err := os.ReadFile(packagesPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read packages.json: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(packagesPath), err)
 	}
 
 	var packagesResult struct {
@@ -262,7 +262,7 @@ func (g *Generator) GenerateFromScanResults(analysisDir string) (*Document, erro
 				CVSSVector  string   `json:"cvss_vector"`
 				Description string   `json:"description"`
 				Published   string   `json:"published"`
-				Fixed       string   `json:"fixed_version"`
+				Fixed       string   `json:"fixed_in"`
 				References  []string `json:"references"`
 				Aliases     []string `json:"aliases"`
 				CWEs        []int    `json:"cwes"`
@@ -271,7 +271,7 @@ func (g *Generator) GenerateFromScanResults(analysisDir string) (*Document, erro
 	}
 
 	if err := json.Unmarshal(packagesData, &packagesResult); err != nil {
-		return nil, fmt.Errorf("failed to parse packages.json: %w", err)
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(packagesPath), err)
 	}
 
 	// Convert to VulnInputs
@@ -311,22 +311,28 @@ func (g *Generator) enrichWithReachability(analysisDir string, vulns []VulnInput
 		return vulns
 	}
 
-	// Try to load reachability data from packages.json
-	packagesPath := filepath.Join(analysisDir, "packages.json")
-	packagesData, err := os.ReadFile(packagesPath)
+	// Try to load reachability data from the code-packages results
+	packagesData, err := os.ReadFile(PackagesPath(analysisDir))
 	if err != nil {
 		return vulns
 	}
 
+	type reachability struct {
+		ID                 string   `json:"id"`
+		Package            string   `json:"package"`
+		Version            string   `json:"version"`
+		IsReachable        bool     `json:"reachable"`
+		ReachabilityStatus string   `json:"reachability_status"`
+		CallPaths          []string `json:"call_paths"`
+		Evidence           []struct {
+			File string `json:"file"`
+			Line int    `json:"line"`
+		} `json:"evidence"`
+		VulnerableSymbols []string `json:"vulnerable_symbols"`
+	}
 	var reachData struct {
 		Findings struct {
-			Reachability []struct {
-				Package       string   `json:"package"`
-				Version       string   `json:"version"`
-				IsReachable   bool     `json:"is_reachable"`
-				CallPaths     []string `json:"call_paths"`
-				UsedFunctions []string `json:"used_functions"`
-			} `json:"reachability"`
+			Reachability []reachability `json:"reachability"`
 		} `json:"findings"`
 	}
 
@@ -334,39 +340,54 @@ func (g *Generator) enrichWithReachability(analysisDir string, vulns []VulnInput
 		return vulns
 	}
 
-	// Build reachability map
-	reachMap := make(map[string]struct {
-		IsReachable   bool
-		CallPaths     []string
-		UsedFunctions []string
-	})
-
+	// Build reachability map, by vulnerability and by package version
+	reachMap := make(map[string]reachability)
 	for _, r := range reachData.Findings.Reachability {
-		key := fmt.Sprintf("%s@%s", r.Package, r.Version)
-		reachMap[key] = struct {
-			IsReachable   bool
-			CallPaths     []string
-			UsedFunctions []string
-		}{
-			IsReachable:   r.IsReachable,
-			CallPaths:     r.CallPaths,
-			UsedFunctions: r.UsedFunctions,
+		if r.ReachabilityStatus == "unknown" {
+			continue
+		}
+		reachMap[r.ID+"|"+r.Package] = r
+		if _, ok := reachMap[r.Package+"@"+r.Version]; !ok || r.IsReachable {
+			reachMap[r.Package+"@"+r.Version] = r
 		}
 	}
 
 	// Enrich vulnerabilities
 	for i := range vulns {
-		key := fmt.Sprintf("%s@%s", vulns[i].Package, vulns[i].Version)
-		if reach, ok := reachMap[key]; ok {
-			vulns[i].IsReachable = &reach.IsReachable
-			vulns[i].ReachablePaths = reach.CallPaths
-			vulns[i].UsedFunctions = reach.UsedFunctions
+		reach, ok := reachMap[vulns[i].ID+"|"+vulns[i].Package]
+		if !ok {
+			reach, ok = reachMap[fmt.Sprintf("%s@%s", vulns[i].Package, vulns[i].Version)]
+		}
+		if !ok {
+			continue
 		}
+		isReachable := reach.IsReachable
+		vulns[i].IsReachable = &isReachable
+		vulns[i].ReachablePaths = reach.CallPaths
+		if len(vulns[i].ReachablePaths) == 0 {
+			for _, e := range reach.Evidence {
+				vulns[i].ReachablePaths = append(vulns[i].ReachablePaths, fmt.Sprintf("%s:%d", e.File, e.Line))
+			}
+		}
+		vulns[i].UsedFunctions = reach.VulnerableSymbols
 	}
 
 	return vulns
 }
 
+// PackagesPath returns the code-packages results in an analysis directory,
+// falling back to the legacy packages.json name
+func PackagesPath(analysisDir string) string {
+	path := filepath.Join(analysisDir, "code-packages.json")
+	if _, err := os.Stat(path); err != nil {
+		legacy := filepath.Join(analysisDir, "packages.json")
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return path
+}
+
 // getProductInfo extracts product info from SBOM
 func (g *Generator) getProductInfo(analysisDir string) (string, string) {
 	if g.config.ProductName != "" {